@@ -3,6 +3,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 
@@ -11,11 +12,25 @@ import (
 	appiCli "github.com/urfave/cli/v3"
 )
 
+// errReadOnly is returned by subcommands that would change worktrees while
+// read-only mode is enabled.
+var errReadOnly = errors.New("refusing to change worktrees in read-only mode")
+
 // wtCreateCommand returns the wt-create subcommand definition.
 func wtCreateCommand() *appiCli.Command {
 	return &appiCli.Command{
 		Name:  "wt-create",
 		Usage: "Create a new worktree",
+		Description: `Creates a worktree from the current branch, another branch or a PR/MR
+without opening the TUI, running init_commands from the configuration
+and .wt. The new worktree path is printed on stdout.
+
+Examples:
+  lazyworktree wt-create
+  lazyworktree wt-create --from-branch main --name my-feature
+  lazyworktree wt-create --with-change --name wip
+  lazyworktree wt-create --from-pr 123 --silent`,
+		ShellComplete: completeWtCreate,
 		Action: func(ctx context.Context, cmd *appiCli.Command) error {
 			if err := validateWtCreateFlags(ctx, cmd); err != nil {
 				return err
@@ -47,12 +62,50 @@ func wtCreateCommand() *appiCli.Command {
 	}
 }
 
+func createCommand() *appiCli.Command {
+	return &appiCli.Command{
+		Name:  "create",
+		Usage: "Create a worktree for a new branch, as the TUI's create dialogue does",
+		Description: `Starts a new branch from --base, or the main branch, in a worktree named
+after it, without opening the TUI. The name is sanitised as in the
+create dialogue, the worktree policy applies, and init_commands from the
+configuration and a trusted .wt run. The new worktree path is printed on
+stdout.
+
+Examples:
+  lazyworktree create login-page
+  lazyworktree create hotfix --base origin/release-1.2
+  cd "$(lazyworktree create --silent spike)"`,
+		ArgsUsage:     "<branch>",
+		Action:        handleCreateAction,
+		ShellComplete: completeCreate,
+		Flags: []appiCli.Flag{
+			&appiCli.StringFlag{
+				Name:  "base",
+				Usage: "Branch, tag or commit the new branch starts from (defaults to the main branch)",
+			},
+			&appiCli.BoolFlag{
+				Name:  "silent",
+				Usage: "Suppress progress messages",
+			},
+		},
+	}
+}
+
 func wtDeleteCommand() *appiCli.Command {
 	return &appiCli.Command{
-		Name:      "wt-delete",
-		Usage:     "Delete a worktree",
-		ArgsUsage: "[worktree-path]",
-		Action:    handleWtDeleteAction,
+		Name:  "wt-delete",
+		Usage: "Delete a worktree",
+		Description: `Deletes a worktree by path or name, running terminate_commands first.
+Without an argument the current worktree is used, or a list is offered.
+Its branch is deleted too when it matches the worktree name.
+
+Examples:
+  lazyworktree wt-delete my-feature
+  lazyworktree wt-delete --no-branch ~/.local/share/worktrees/repo/wip`,
+		ArgsUsage:     "[worktree-path]",
+		Action:        handleWtDeleteAction,
+		ShellComplete: completeWtDelete,
 		Flags: []appiCli.Flag{
 			&appiCli.BoolFlag{
 				Name:  "no-branch",
@@ -103,6 +156,9 @@ func handleWtCreateAction(ctx context.Context, cmd *appiCli.Command) error {
 		fmt.Fprintf(os.Stderr, "%v\n", err)
 		return err
 	}
+	if cfg.ReadOnly || cmd.Bool("read-only") || cmd.Bool("safe") || cmd.Bool("observe") {
+		return errReadOnly
+	}
 
 	gitSvc := newCLIGitService(cfg)
 
@@ -163,6 +219,9 @@ func handleWtDeleteAction(ctx context.Context, cmd *appiCli.Command) error {
 		fmt.Fprintf(os.Stderr, "%v\n", err)
 		return err
 	}
+	if cfg.ReadOnly || cmd.Bool("read-only") || cmd.Bool("safe") || cmd.Bool("observe") {
+		return errReadOnly
+	}
 
 	gitSvc := newCLIGitService(cfg)
 
@@ -187,3 +246,32 @@ func handleWtDeleteAction(ctx context.Context, cmd *appiCli.Command) error {
 	_ = log.Close()
 	return nil
 }
+
+// handleCreateAction handles the create subcommand action.
+func handleCreateAction(ctx context.Context, cmd *appiCli.Command) error {
+	if cmd.NArg() != 1 {
+		return fmt.Errorf("create takes exactly one branch name")
+	}
+	cfg, err := loadCLIConfig(
+		cmd.String("config-file"),
+		cmd.String("worktree-dir"),
+		cmd.StringSlice("config"),
+	)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return err
+	}
+	if cfg.ReadOnly || cmd.Bool("read-only") || cmd.Bool("safe") || cmd.Bool("observe") {
+		return errReadOnly
+	}
+
+	gitSvc := newCLIGitService(cfg)
+	if err := cli.CreateFromBase(ctx, gitSvc, cfg, cmd.Args().First(), cmd.String("base"), cmd.Bool("silent")); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		_ = log.Close()
+		return err
+	}
+
+	_ = log.Close()
+	return nil
+}
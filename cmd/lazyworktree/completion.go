@@ -0,0 +1,128 @@
+// Package main provides shell completion for lazyworktree.
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/chmouel/lazyworktree/internal/cli"
+	"github.com/chmouel/lazyworktree/internal/git"
+	appiCli "github.com/urfave/cli/v3"
+)
+
+// completionFlag is appended by the completion scripts when they ask the
+// binary for candidates.
+const completionFlag = "--generate-shell-completion"
+
+// fishDynamicCompletion completes worktree names and branches in fish, whose
+// generated script is otherwise static.
+const fishDynamicCompletion = `
+complete -c lazyworktree -n '__fish_seen_subcommand_from wt-delete' -f -a '(lazyworktree wt-delete ` + completionFlag + ` 2>/dev/null)'
+complete -c lazyworktree -n '__fish_seen_subcommand_from wt-create' -l from-branch -x -a '(lazyworktree wt-create --from-branch ` + completionFlag + ` 2>/dev/null)'
+`
+
+// configureCompletionCommand shows the completion subcommand in --help and
+// adds the dynamic candidates to the fish script.
+func configureCompletionCommand(cmd *appiCli.Command) {
+	cmd.Hidden = false
+	cmd.Usage = "Print the shell completion script for bash, zsh, fish or pwsh"
+	cmd.ArgsUsage = "SHELL"
+	cmd.Description = `Prints a script completing flags, subcommands, worktree names for
+wt-delete and local branches after wt-create --from-branch.
+
+Examples:
+  source <(lazyworktree completion bash)
+  source <(lazyworktree completion zsh)
+  lazyworktree completion fish > ~/.config/fish/completions/lazyworktree.fish`
+	printScript := cmd.Action
+	cmd.Action = func(ctx context.Context, cmd *appiCli.Command) error {
+		switch cmd.Args().First() {
+		case "-h", "--help":
+			// The library passes flags through as the shell name.
+			return appiCli.ShowCommandHelp(ctx, cmd.Root(), cmd.Name)
+		case "fish":
+			script, err := cmd.Root().ToFishCompletion()
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprint(cmd.Root().Writer, script+fishDynamicCompletion)
+			return err
+		}
+		cmd.Writer = cmd.Root().Writer
+		return printScript(ctx, cmd)
+	}
+}
+
+// completeWtCreate offers local branches after --from-branch.
+func completeWtCreate(ctx context.Context, cmd *appiCli.Command) {
+	switch lastArg := completionLastArg(); {
+	case lastArg == "--from-branch":
+		printCompletions(cmd, cli.BranchNames(ctx, completionGitService()))
+	case strings.HasPrefix(lastArg, "-"):
+		printCompletions(cmd, flagCompletions(cmd, lastArg))
+	default:
+		appiCli.DefaultCompleteWithFlags(ctx, cmd)
+	}
+}
+
+// completeCreate offers the refs --base accepts.
+func completeCreate(ctx context.Context, cmd *appiCli.Command) {
+	switch lastArg := completionLastArg(); {
+	case lastArg == "--base":
+		printCompletions(cmd, cli.BranchNames(ctx, completionGitService()))
+	case strings.HasPrefix(lastArg, "-"):
+		printCompletions(cmd, flagCompletions(cmd, lastArg))
+	default:
+		appiCli.DefaultCompleteWithFlags(ctx, cmd)
+	}
+}
+
+// completeWtDelete offers the worktree names accepted by wt-delete.
+func completeWtDelete(ctx context.Context, cmd *appiCli.Command) {
+	switch lastArg := completionLastArg(); {
+	case strings.HasPrefix(lastArg, "-"):
+		printCompletions(cmd, flagCompletions(cmd, lastArg))
+	case cmd.NArg() == 0:
+		printCompletions(cmd, cli.WorktreeNames(ctx, completionGitService()))
+	}
+}
+
+// flagCompletions returns the long flags of cmd starting with prefix. Unlike
+// the library default it also works for a partly typed flag of a subcommand,
+// which the parser would otherwise reject as unknown.
+func flagCompletions(cmd *appiCli.Command, prefix string) []string {
+	var flags []string
+	for _, flag := range cmd.Flags {
+		name := "--" + flag.Names()[0]
+		if strings.HasPrefix(name, prefix) && name != prefix {
+			flags = append(flags, name)
+		}
+	}
+	return flags
+}
+
+// completionLastArg returns the word typed before the completion request.
+func completionLastArg() string {
+	args := os.Args
+	if len(args) > 0 && args[len(args)-1] == completionFlag {
+		args = args[:len(args)-1]
+	}
+	if len(args) < 2 {
+		return ""
+	}
+	return args[len(args)-1]
+}
+
+// completionGitService returns a git service that keeps completion output
+// free of notifications.
+func completionGitService() *git.Service {
+	return git.NewService(func(string, string) {}, func(string, string, string) {})
+}
+
+func printCompletions(cmd *appiCli.Command, candidates []string) {
+	for _, candidate := range candidates {
+		_, _ = fmt.Fprintln(cmd.Root().Writer, candidate)
+	}
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	urfavecli "github.com/urfave/cli/v3"
+)
+
+func TestCompletionLastArg(t *testing.T) {
+	oldArgs := os.Args
+	t.Cleanup(func() { os.Args = oldArgs })
+
+	os.Args = []string{"lazyworktree", "wt-create", "--from-branch", completionFlag}
+	if got := completionLastArg(); got != "--from-branch" {
+		t.Fatalf("expected --from-branch, got %q", got)
+	}
+	os.Args = []string{"lazyworktree", completionFlag}
+	if got := completionLastArg(); got != "" {
+		t.Fatalf("expected no argument, got %q", got)
+	}
+}
+
+func TestFlagCompletions(t *testing.T) {
+	got := flagCompletions(wtCreateCommand(), "--fr")
+	if want := []string{"--from-branch", "--from-pr"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	if got := flagCompletions(wtDeleteCommand(), "--silent"); len(got) != 0 {
+		t.Fatalf("expected a complete flag to offer nothing, got %v", got)
+	}
+}
+
+func runCompletionCommand(t *testing.T, shell string) string {
+	t.Helper()
+	var out bytes.Buffer
+	app := &urfavecli.Command{
+		Name:                            "lazyworktree",
+		EnableShellCompletion:           true,
+		ConfigureShellCompletionCommand: configureCompletionCommand,
+		Commands:                        []*urfavecli.Command{wtCreateCommand(), wtDeleteCommand()},
+		Writer:                          &out,
+	}
+	if err := app.Run(context.Background(), []string{"lazyworktree", "completion", shell}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return out.String()
+}
+
+func TestCompletionCommand(t *testing.T) {
+	script := runCompletionCommand(t, "fish")
+	for _, want := range []string{"wt-delete " + completionFlag, "-l from-branch -x"} {
+		if !strings.Contains(script, want) {
+			t.Fatalf("expected fish script to contain %q", want)
+		}
+	}
+
+	if !strings.Contains(runCompletionCommand(t, "bash"), completionFlag) {
+		t.Fatal("expected bash script to request completions dynamically")
+	}
+}
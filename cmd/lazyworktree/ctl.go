@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"time"
+
+	"github.com/chmouel/lazyworktree/internal/control"
+	"github.com/chmouel/lazyworktree/internal/models"
+	"github.com/chmouel/lazyworktree/internal/utils"
+	appiCli "github.com/urfave/cli/v3"
+)
+
+// ctlTimeout bounds a ctl request, including the wait for the instance.
+const ctlTimeout = 15 * time.Second
+
+// ctlCommand returns the ctl subcommand definition.
+func ctlCommand() *appiCli.Command {
+	return &appiCli.Command{
+		Name:      "ctl",
+		Usage:     "Send a command to the lazyworktree running on this repository",
+		ArgsUsage: "select <worktree> | refresh | create <branch> | list | counterpart <file> <worktree>",
+		Description: `Drives an instance started with control_socket: true through its Unix
+socket, found from the current repository unless --socket is given.
+
+  select       moves the cursor to the worktree with that branch,
+               directory name or path
+  refresh      reloads the worktree list
+  create       creates a worktree for a new branch from --base, or from
+               the main branch, with the same checks as the create dialogue
+  list         lists the worktrees as shown, with their status and PR
+  counterpart  maps an absolute file path to the same file in another
+               worktree
+
+With --json the whole response is printed, its "data" field holding the
+worktrees or the counterpart, for editor plugins.
+
+Examples:
+  lazyworktree ctl select feature-x
+  lazyworktree ctl create --base origin/main fix-login
+  lazyworktree ctl --json list
+  lazyworktree ctl counterpart "$PWD/main.go" feature-x`,
+		Action: handleCtlAction,
+		Flags: []appiCli.Flag{
+			&appiCli.StringFlag{
+				Name:  "socket",
+				Usage: "Control socket to use instead of the current repository's",
+			},
+			&appiCli.StringFlag{
+				Name:  "base",
+				Usage: "With create, the ref to base the new branch on",
+			},
+			&appiCli.BoolFlag{
+				Name:  "json",
+				Usage: "Print the response as JSON",
+			},
+		},
+	}
+}
+
+// handleCtlAction handles the ctl subcommand action.
+func handleCtlAction(ctx context.Context, cmd *appiCli.Command) error {
+	if cmd.NArg() == 0 {
+		return errors.New("ctl expects a command: select, refresh or create")
+	}
+	args := cmd.Args().Slice()
+	req := control.Request{Command: args[0], Args: args[1:]}
+	if base := cmd.String("base"); base != "" {
+		if req.Command != "create" {
+			return errors.New("--base only applies to create")
+		}
+		req.Args = append(req.Args, "--base", base)
+	}
+	socket, err := ctlSocketPath(ctx, cmd)
+	if err != nil {
+		return err
+	}
+	return sendCtl(ctx, socket, req, cmd.Bool("json"), cmd.Root().Writer)
+}
+
+// ctlSocketPath returns the --socket flag, or the socket of the current
+// repository's instance.
+func ctlSocketPath(ctx context.Context, cmd *appiCli.Command) (string, error) {
+	if socket := cmd.String("socket"); socket != "" {
+		return utils.ExpandPath(socket)
+	}
+	cfg, err := loadCLIConfig(
+		cmd.String("config-file"),
+		cmd.String("worktree-dir"),
+		cmd.StringSlice("config"),
+	)
+	if err != nil {
+		return "", err
+	}
+	gitSvc := newCLIGitService(cfg)
+	if gitSvc.GetMainWorktreePath(ctx) == "" {
+		return "", errors.New("ctl must be run inside a git repository, or given --socket")
+	}
+	return filepath.Join(utils.CacheDir(), gitSvc.ResolveRepoName(ctx), models.ControlSocketFilename), nil
+}
+
+// sendCtl sends req to the instance on socket and prints its answer, whole
+// when asJSON is set.
+func sendCtl(ctx context.Context, socket string, req control.Request, asJSON bool, out io.Writer) error {
+	ctx, cancel := context.WithTimeout(ctx, ctlTimeout)
+	defer cancel()
+	resp, err := control.Send(ctx, socket, req)
+	if errors.Is(err, control.ErrNotRunning) {
+		return fmt.Errorf("%w; start it with control_socket: true", err)
+	}
+	if err != nil {
+		return err
+	}
+	if asJSON {
+		if err := json.NewEncoder(out).Encode(resp); err != nil {
+			return err
+		}
+		if !resp.OK {
+			return errors.New(resp.Error)
+		}
+		return nil
+	}
+	if !resp.OK {
+		return errors.New(resp.Error)
+	}
+	if resp.Message != "" {
+		_, _ = fmt.Fprintln(out, resp.Message)
+	}
+	return nil
+}
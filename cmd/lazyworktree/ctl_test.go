@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/chmouel/lazyworktree/internal/control"
+)
+
+func TestSendCtl(t *testing.T) {
+	dir, err := os.MkdirTemp("", "lwctl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+	socket := filepath.Join(dir, "control.sock")
+
+	var out bytes.Buffer
+	err = sendCtl(context.Background(), socket, control.Request{Command: "refresh"}, false, &out)
+	if err == nil || !strings.Contains(err.Error(), "control_socket: true") {
+		t.Fatalf("expected a hint to enable control_socket, got %v", err)
+	}
+
+	var got control.Request
+	server, err := control.Listen(socket, func(req control.Request) control.Response {
+		got = req
+		if req.Command == "select" {
+			return control.Response{Error: "no worktree matches \"nope\""}
+		}
+		return control.Response{OK: true, Message: "Creating worktree fix from main."}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = server.Close() }()
+
+	root := newRootCommand()
+	root.Writer = &out
+	if err := root.Run(context.Background(), []string{"lazyworktree", "ctl", "--socket", socket, "create", "fix", "--base", "main"}); err != nil {
+		t.Fatalf("ctl create: %v", err)
+	}
+	if got.Command != "create" || strings.Join(got.Args, " ") != "fix --base main" {
+		t.Fatalf("unexpected request %+v", got)
+	}
+	if !strings.Contains(out.String(), "Creating worktree fix from main.") {
+		t.Fatalf("expected the response to be printed, got %q", out.String())
+	}
+
+	out.Reset()
+	if err := sendCtl(context.Background(), socket, control.Request{Command: "list"}, true, &out); err != nil {
+		t.Fatalf("ctl --json list: %v", err)
+	}
+	var resp control.Response
+	if err := json.Unmarshal(out.Bytes(), &resp); err != nil || !resp.OK {
+		t.Fatalf("expected the JSON response, got %q (%v)", out.String(), err)
+	}
+
+	err = sendCtl(context.Background(), socket, control.Request{Command: "select", Args: []string{"nope"}}, false, &out)
+	if err == nil || !strings.Contains(err.Error(), "no worktree matches") {
+		t.Fatalf("expected the instance's error, got %v", err)
+	}
+}
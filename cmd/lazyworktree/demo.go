@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/chmouel/lazyworktree/internal/config"
+	"github.com/chmouel/lazyworktree/internal/demo"
+)
+
+// openDemo creates the demo repository and points cfg and the working
+// directory at it. The cache moves into the demo too, so nothing it
+// records outlives it. The returned function restores the working
+// directory and removes the demo.
+func openDemo(ctx context.Context, cfg *config.AppConfig) (*demo.Repo, func(), error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, nil, err
+	}
+	repo, err := demo.Setup(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error creating the demo repository: %w", err)
+	}
+	if err := os.Chdir(repo.Main); err != nil {
+		_ = repo.Close()
+		return nil, nil, fmt.Errorf("error changing to the demo repository: %w", err)
+	}
+	cacheHome, hadCacheHome := os.LookupEnv("XDG_CACHE_HOME")
+	_ = os.Setenv("XDG_CACHE_HOME", filepath.Join(repo.Root, "cache"))
+
+	cfg.WorktreeDir = repo.WorktreeDir
+	cfg.SSHHost = ""
+	cfg.SuggestBranches = false
+
+	return repo, func() {
+		_ = os.Chdir(cwd)
+		if hadCacheHome {
+			_ = os.Setenv("XDG_CACHE_HOME", cacheHome)
+		} else {
+			_ = os.Unsetenv("XDG_CACHE_HOME")
+		}
+		_ = repo.Close()
+	}, nil
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/chmouel/lazyworktree/internal/config"
+)
+
+func TestOpenDemo(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	start := t.TempDir()
+	t.Chdir(start)
+	t.Setenv("XDG_CACHE_HOME", "/original/cache")
+
+	cfg := &config.AppConfig{WorktreeDir: "/home/user/worktrees", SSHHost: "devbox", SuggestBranches: true}
+	repo, closeDemo, err := openDemo(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("open demo: %v", err)
+	}
+	cwd, _ := os.Getwd()
+	if cwd != repo.Main || cfg.WorktreeDir != repo.WorktreeDir || cfg.SSHHost != "" || cfg.SuggestBranches {
+		t.Fatalf("expected the demo opened locally, got cwd %s and %+v", cwd, cfg)
+	}
+	if got := os.Getenv("XDG_CACHE_HOME"); got != filepath.Join(repo.Root, "cache") {
+		t.Fatalf("expected the cache inside the demo, got %s", got)
+	}
+
+	closeDemo()
+	cwd, _ = os.Getwd()
+	if cwd != start || os.Getenv("XDG_CACHE_HOME") != "/original/cache" {
+		t.Fatalf("expected the directory and cache restored, got %s and %s", cwd, os.Getenv("XDG_CACHE_HOME"))
+	}
+	if _, err := os.Stat(repo.Root); !os.IsNotExist(err) {
+		t.Fatalf("expected the demo removed, got %v", err)
+	}
+}
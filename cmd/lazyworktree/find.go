@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/chmouel/lazyworktree/internal/search"
+	"github.com/chmouel/lazyworktree/internal/utils"
+	appiCli "github.com/urfave/cli/v3"
+)
+
+// findCommand returns the find subcommand definition.
+func findCommand() *appiCli.Command {
+	return &appiCli.Command{
+		Name:      "find",
+		Usage:     "Find a worktree by branch or PR across every repository",
+		ArgsUsage: "<query>",
+		Description: `Searches the worktrees of every repository with worktrees under the
+worktree root for a branch, directory name or pull request title
+containing the query, case-insensitively. "#123" or "123" finds the
+worktree of PR 123, as last seen by the TUI.
+
+Each match is printed as its path, branch and repository, separated by
+tabs, followed by the PR when known. With --cd only the path of the one
+match is printed, for the shell to change into; an exact branch name
+wins over partial matches.
+
+Examples:
+  lazyworktree find JIRA-123
+  lazyworktree find '#42'
+  cd "$(lazyworktree find --cd login-redirect)"`,
+		Action: handleFindAction,
+		Flags: []appiCli.Flag{
+			&appiCli.BoolFlag{
+				Name:  "cd",
+				Usage: "Print only the path of the single match",
+			},
+		},
+	}
+}
+
+// handleFindAction handles the find subcommand action.
+func handleFindAction(ctx context.Context, cmd *appiCli.Command) error {
+	query := strings.TrimSpace(strings.Join(cmd.Args().Slice(), " "))
+	if query == "" {
+		return fmt.Errorf("a query is required, e.g. lazyworktree find JIRA-123")
+	}
+	cfg, err := loadCLIConfig(cmd.String("config-file"), cmd.String("worktree-dir"), cmd.StringSlice("config"))
+	if err != nil {
+		return err
+	}
+	cacheRoot := utils.CacheDir()
+	matches := search.Find(ctx, search.Repositories(cfg.WorktreeDir, cacheRoot), cacheRoot, query)
+	return printFindMatches(cmd.Root().Writer, os.Stderr, matches, query, cmd.Bool("cd"))
+}
+
+// printFindMatches prints the matches, or with cd the path of the only one.
+func printFindMatches(w, errW io.Writer, matches []search.Match, query string, cd bool) error {
+	if len(matches) == 0 {
+		return fmt.Errorf("no worktree matches %q", query)
+	}
+	if !cd {
+		for _, match := range matches {
+			_, _ = fmt.Fprintln(w, findMatchLine(match))
+		}
+		return nil
+	}
+
+	if len(matches) > 1 {
+		var exact []search.Match
+		for _, match := range matches {
+			if strings.EqualFold(match.Branch, query) {
+				exact = append(exact, match)
+			}
+		}
+		if len(exact) != 1 {
+			for _, match := range matches {
+				_, _ = fmt.Fprintln(errW, findMatchLine(match))
+			}
+			return fmt.Errorf("%d worktrees match %q; narrow the query", len(matches), query)
+		}
+		matches = exact
+	}
+	_, _ = fmt.Fprintln(w, matches[0].Path)
+	return nil
+}
+
+// findMatchLine renders a match as tab-separated fields.
+func findMatchLine(match search.Match) string {
+	fields := []string{match.Path, match.Branch, match.RepoName()}
+	if match.PR != nil {
+		fields = append(fields, fmt.Sprintf("#%d %s", match.PR.Number, match.PR.Title))
+	}
+	return strings.Join(fields, "\t")
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/chmouel/lazyworktree/internal/models"
+	"github.com/chmouel/lazyworktree/internal/search"
+)
+
+func TestPrintFindMatches(t *testing.T) {
+	matches := []search.Match{
+		{Repo: "/src/api", Path: "/wt/api/login", Branch: "login", PR: &models.PRInfo{Number: 42, Title: "Fix login"}},
+		{Repo: "/src/web", Path: "/wt/web/login-page", Branch: "login-page"},
+	}
+
+	var out, errOut bytes.Buffer
+	if err := printFindMatches(&out, &errOut, matches, "login", false); err != nil {
+		t.Fatal(err)
+	}
+	if want := "/wt/api/login\tlogin\tapi\t#42 Fix login\n/wt/web/login-page\tlogin-page\tweb\n"; out.String() != want {
+		t.Fatalf("unexpected listing %q", out.String())
+	}
+
+	out.Reset()
+	if err := printFindMatches(&out, &errOut, matches, "LOGIN", true); err != nil || out.String() != "/wt/api/login\n" {
+		t.Fatalf("expected the exact branch to win, got %q (%v)", out.String(), err)
+	}
+
+	out.Reset()
+	err := printFindMatches(&out, &errOut, matches, "log", true)
+	if err == nil || !strings.Contains(err.Error(), "2 worktrees match") || out.Len() != 0 {
+		t.Fatalf("expected an ambiguous query to fail, got %q (%v)", out.String(), err)
+	}
+	if !strings.Contains(errOut.String(), "/wt/web/login-page") {
+		t.Fatalf("expected the candidates on stderr, got %q", errOut.String())
+	}
+
+	if err := printFindMatches(&out, &errOut, nil, "nothing", false); err == nil {
+		t.Fatal("expected no match to be an error")
+	}
+}
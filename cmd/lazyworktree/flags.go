@@ -31,6 +31,42 @@ func globalFlags() []urfavecli.Flag {
 			Name:  "search-auto-select",
 			Usage: "Start with filter focused",
 		},
+		&urfavecli.BoolFlag{
+			Name:  "no-animations",
+			Usage: "Disable the loading spinner and other animations",
+		},
+		&urfavecli.BoolFlag{
+			Name:  "print-json",
+			Usage: "Print the repository's worktrees as JSON and exit, without the TUI",
+		},
+		&urfavecli.BoolFlag{
+			Name:  "demo",
+			Usage: "Open a throwaway demo repository with made-up PRs and CI, and a guided tour",
+		},
+		&urfavecli.BoolFlag{
+			Name:  "read-only",
+			Usage: "Browse without changing anything: creating, deleting, pushing, staging and hooks are disabled",
+		},
+		&urfavecli.BoolFlag{
+			Name:  "safe",
+			Usage: "Read-only, and run nothing besides git: no hooks, custom commands, scripts, gh, glab or delta",
+		},
+		&urfavecli.BoolFlag{
+			Name:  "observe",
+			Usage: "Read-only activity monitor: follow checkouts and commits in every worktree in a feed in place of the log pane",
+		},
+		&urfavecli.StringFlag{
+			Name:  "ssh",
+			Usage: "Experimental: manage the worktrees of a repository on another host, given as HOST:PATH",
+		},
+		&urfavecli.StringFlag{
+			Name:  "events",
+			Usage: "Write NDJSON lifecycle events to FILE, or to an inherited descriptor with fd:N",
+		},
+		&urfavecli.BoolFlag{
+			Name:  "verbose",
+			Usage: "With --version, also report tool versions, the git host and paths for bug reports",
+		},
 		&urfavecli.BoolFlag{
 			Name:  "show-syntax-themes",
 			Usage: "List available delta syntax themes",
@@ -5,19 +5,40 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
-	"runtime/debug"
 	"sort"
+	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/chmouel/lazyworktree/internal/app"
 	"github.com/chmouel/lazyworktree/internal/config"
+	"github.com/chmouel/lazyworktree/internal/control"
+	"github.com/chmouel/lazyworktree/internal/crash"
+	"github.com/chmouel/lazyworktree/internal/demo"
+	"github.com/chmouel/lazyworktree/internal/diagnostics"
+	"github.com/chmouel/lazyworktree/internal/events"
+	"github.com/chmouel/lazyworktree/internal/git"
 	"github.com/chmouel/lazyworktree/internal/log"
 	"github.com/chmouel/lazyworktree/internal/theme"
 	"github.com/chmouel/lazyworktree/internal/utils"
 	"github.com/urfave/cli/v3"
 )
 
+// rootDescription is shown by --help and in the generated man page.
+const rootDescription = `Without a command, lazyworktree opens the TUI for the git repository in
+the current directory. The selected worktree path is printed on exit.
+Started outside a repository, it offers the repositories owning worktrees
+under the worktree directory instead.
+
+Examples:
+  lazyworktree
+  lazyworktree --theme nord --search-auto-select
+  lazyworktree --config=lw.auto_fetch_prs=true
+  lazyworktree --output-selection=/tmp/selected-worktree
+  lazyworktree --print-json | jq -r '.[] | select(.dirty) | .path'
+  lazyworktree --demo`
+
 var (
 	version = "dev"
 	commit  = "none"
@@ -26,16 +47,38 @@ var (
 )
 
 func main() {
-	cliApp := &cli.Command{
-		Name:                  "lazyworktree",
-		Usage:                 "A TUI tool to manage git worktrees",
-		Version:               version,
-		EnableShellCompletion: true,
-		Flags:                 globalFlags(),
+	if err := newRootCommand().Run(context.Background(), os.Args); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+}
+
+// newRootCommand returns the command tree: the TUI as the root action, its
+// global flags and the subcommands. --help, completion and the man page are
+// all generated from it.
+func newRootCommand() *cli.Command {
+	cli.VersionPrinter = printVersion
+	return &cli.Command{
+		Name:                            "lazyworktree",
+		Usage:                           "A TUI tool to manage git worktrees",
+		Description:                     rootDescription,
+		Version:                         version,
+		EnableShellCompletion:           true,
+		ConfigureShellCompletionCommand: configureCompletionCommand,
+		Flags:                           globalFlags(),
 
 		Commands: []*cli.Command{
 			wtCreateCommand(),
+			createCommand(),
 			wtDeleteCommand(),
+			updateCommand(),
+			exportStateCommand(),
+			importStateCommand(),
+			configCommand(),
+			ctlCommand(),
+			promptCommand(),
+			findCommand(),
+			manCommand(),
 		},
 
 		Action: func(ctx context.Context, cmd *cli.Command) error {
@@ -50,14 +93,12 @@ func main() {
 		},
 		Suggest: true,
 	}
-
-	if err := cliApp.Run(context.Background(), os.Args); err != nil {
-		fmt.Fprintf(os.Stderr, "%v\n", err)
-		os.Exit(1)
-	}
 }
 
-func runTUI(_ context.Context, cmd *cli.Command) error {
+func runTUI(ctx context.Context, cmd *cli.Command) error {
+	if cmd.Bool("print-json") {
+		return printWorktreesJSON(ctx, cmd)
+	}
 	if debugLog := cmd.String("debug-log"); debugLog != "" {
 		expanded, err := utils.ExpandPath(debugLog)
 		if err == nil {
@@ -103,6 +144,24 @@ func runTUI(_ context.Context, cmd *cli.Command) error {
 		cfg.SearchAutoSelect = true
 	}
 
+	if cmd.Bool("no-animations") {
+		cfg.NoAnimations = true
+	}
+	if cmd.Bool("read-only") {
+		cfg.ReadOnly = true
+	}
+	if cmd.Bool("observe") {
+		cfg.Observe = true
+		cfg.ReadOnly = true
+		cfg.AutoRefresh = true
+	}
+
+	if err := applySSHConfig(cfg, cmd.String("ssh")); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		_ = log.Close()
+		return err
+	}
+
 	if err := applyWorktreeDirConfig(cfg, cmd.String("worktree-dir")); err != nil {
 		fmt.Fprintf(os.Stderr, "%v\n", err)
 		_ = log.Close()
@@ -125,11 +184,76 @@ func runTUI(_ context.Context, cmd *cli.Command) error {
 			return err
 		}
 	}
+	// Applied after the overrides so that none of them brings a hook back.
+	if cmd.Bool("safe") {
+		cfg.ApplySafeMode()
+	}
+	if err := cfg.ResolveSecrets(ctx); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	if _, err := cfg.Policy(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error in worktree policy: %v\n", err)
+		_ = log.Close()
+		return err
+	}
+	if _, err := cfg.MaintenanceSchedule(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error in maintenance schedule: %v\n", err)
+		_ = log.Close()
+		return err
+	}
+
+	// Opened before ensureRepository may change directory, so a relative
+	// path names a file where the user started lazyworktree.
+	stream, err := openEvents(cmd.String("events"))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error opening event stream: %v\n", err)
+		_ = log.Close()
+		return err
+	}
+	defer func() { _ = stream.Close() }()
+
+	var demoRepo *demo.Repo
+	if cmd.Bool("demo") {
+		repo, closeDemo, err := openDemo(ctx, cfg)
+		if err != nil {
+			_ = log.Close()
+			return err
+		}
+		defer closeDemo()
+		demoRepo = repo
+	} else {
+		inRepo, err := ensureRepository(ctx, cfg)
+		if err != nil || !inRepo {
+			_ = log.Close()
+			return err
+		}
+	}
 
 	model := app.NewModel(cfg, "")
-	p := tea.NewProgram(model, tea.WithAltScreen(), tea.WithMouseCellMotion())
+	if demoRepo != nil {
+		model.StartDemo(demoRepo)
+	}
+	model.SetBuildInfo(buildInfo())
+	model.SetEvents(stream)
+	recorder := crash.NewRecorder(version, model.CrashState)
+	p := tea.NewProgram(crash.Guard(model, recorder), tea.WithAltScreen(), tea.WithMouseCellMotion())
+	recorder.Install(p.Kill)
+	stopControl := startControl(ctx, cfg, model, p)
 
 	_, err = p.Run()
+	stopControl()
+	recorder.Uninstall()
+	if report := recorder.Report(); report != nil {
+		reportCrash(report)
+		// Still release the instance lock and cancel background work, but a
+		// model that already panicked must not hide the report behind another.
+		func() {
+			defer func() { _ = recover() }()
+			model.Close()
+		}()
+		_ = log.Close()
+		return fmt.Errorf("lazyworktree stopped unexpectedly: %s", report.Panic)
+	}
 	model.Close()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error running app: %v\n", err)
@@ -137,6 +261,12 @@ func runTUI(_ context.Context, cmd *cli.Command) error {
 		return err
 	}
 
+	// The demo's worktrees are removed on exit, so none is handed back
+	if demoRepo != nil {
+		_ = log.Close()
+		return nil
+	}
+
 	// Handle output-selection flag
 	selectedPath := model.GetSelectedPath()
 	if outputSelection := cmd.String("output-selection"); outputSelection != "" {
@@ -181,6 +311,17 @@ func runTUI(_ context.Context, cmd *cli.Command) error {
 // applyWorktreeDirConfig applies the worktree directory configuration.
 // This ensures the same path expansion logic is used in both TUI and CLI modes.
 func applyWorktreeDirConfig(cfg *config.AppConfig, worktreeDirFlag string) error {
+	// Over SSH the directory is a path on the host, left as given; the
+	// default is chosen once the repository there is found.
+	if cfg.SSHHost != "" {
+		if worktreeDirFlag != "" {
+			cfg.WorktreeDir = worktreeDirFlag
+		}
+		if cfg.WorktreeDir != "" && !path.IsAbs(cfg.WorktreeDir) {
+			return fmt.Errorf("worktree-dir must be an absolute path on %s in SSH mode", cfg.SSHHost)
+		}
+		return nil
+	}
 	switch {
 	case worktreeDirFlag != "":
 		expanded, err := utils.ExpandPath(worktreeDirFlag)
@@ -200,6 +341,21 @@ func applyWorktreeDirConfig(cfg *config.AppConfig, worktreeDirFlag string) error
 	return nil
 }
 
+// applySSHConfig applies the --ssh HOST:PATH flag over ssh_host and
+// ssh_path, splitting it at the last colon.
+func applySSHConfig(cfg *config.AppConfig, sshFlag string) error {
+	if sshFlag == "" {
+		return nil
+	}
+	i := strings.LastIndex(sshFlag, ":")
+	if i <= 0 {
+		return fmt.Errorf("invalid --ssh value %q: expected HOST:PATH", sshFlag)
+	}
+	cfg.SSHHost = sshFlag[:i]
+	cfg.SSHPath = sshFlag[i+1:]
+	return nil
+}
+
 // printSyntaxThemes prints available syntax themes for delta.
 func printSyntaxThemes() {
 	names := theme.AvailableThemes()
@@ -210,29 +366,39 @@ func printSyntaxThemes() {
 	}
 }
 
-// printVersion prints version information.
-func printVersion() {
-	v := version
-	c := commit
-	d := date
-	b := builtBy
-
-	if c == "none" || b == "unknown" {
-		if info, ok := debug.ReadBuildInfo(); ok {
-			if c == "none" {
-				for _, setting := range info.Settings {
-					if setting.Key == "vcs.revision" {
-						c = setting.Value
-					}
-				}
-			}
-			if b == "unknown" {
-				b = info.GoVersion
-			}
-		}
+// buildInfo returns the version details set by the release ldflags.
+func buildInfo() diagnostics.Build {
+	return diagnostics.Build{Version: version, Commit: commit, Date: date, BuiltBy: builtBy}.Resolve()
+}
+
+// printVersion prints version information, followed by tool versions and
+// paths when --verbose is set. It replaces urfave/cli's version printer.
+func printVersion(cmd *cli.Command) {
+	b := buildInfo()
+	w := cmd.Root().Writer
+	if w == nil {
+		w = os.Stdout
+	}
+	if !cmd.Bool("verbose") {
+		_, _ = fmt.Fprintf(w, "lazyworktree version %s\ncommit: %s\nbuilt at: %s\nbuilt by: %s\n", b.Version, b.Commit, b.Date, b.BuiltBy)
+		return
 	}
+	_, _ = fmt.Fprint(w, verboseVersion(context.Background(), cmd, b))
+}
 
-	fmt.Printf("lazyworktree version %s\ncommit: %s\nbuilt at: %s\nbuilt by: %s\n", v, c, d, b)
+// verboseVersion gathers the diagnostics report for --version --verbose.
+func verboseVersion(ctx context.Context, cmd *cli.Command, b diagnostics.Build) string {
+	report := &diagnostics.Report{Build: b, Tools: diagnostics.ToolVersions(ctx)}
+	cfg, err := config.LoadConfig(cmd.String("config-file"))
+	if err != nil {
+		cfg = config.DefaultConfig()
+	}
+	if err := applyWorktreeDirConfig(cfg, cmd.String("worktree-dir")); err == nil {
+		report.WorktreeDir = cfg.WorktreeDir
+	}
+	report.ConfigPath = cfg.ConfigPath
+	report.Host = newCLIGitService(cfg).DetectHost(ctx)
+	return report.String()
 }
 
 // applyThemeConfig applies theme configuration from command line flag.
@@ -253,3 +419,103 @@ func applyThemeConfig(cfg *config.AppConfig, themeName string) error {
 
 	return nil
 }
+
+// startControl listens for "lazyworktree ctl" commands when control_socket
+// is enabled, returning the function stopping it. A socket already in use
+// by another instance is left to that instance.
+func startControl(ctx context.Context, cfg *config.AppConfig, model *app.Model, p *tea.Program) func() {
+	if !cfg.ControlSocket {
+		return func() {}
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	server, err := control.Listen(model.ControlSocketPath(), app.ControlHandler(ctx, p.Send))
+	if err != nil {
+		log.Printf("control socket disabled: %v", err)
+		cancel()
+		return func() {}
+	}
+	return func() {
+		cancel()
+		if err := server.Close(); err != nil {
+			log.Printf("failed to close the control socket: %v", err)
+		}
+	}
+}
+
+// openEvents opens the --events target, returning a nil stream when it is
+// not set.
+func openEvents(target string) (*events.Stream, error) {
+	if target == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(target, "fd:") {
+		expanded, err := utils.ExpandPath(target)
+		if err != nil {
+			return nil, err
+		}
+		target = expanded
+	}
+	return events.Open(target)
+}
+
+// ensureRepository offers the repositories owning worktrees under the
+// worktree root when lazyworktree is started outside a git repository, and
+// changes into the chosen one. It reports false when the user cancels.
+func ensureRepository(ctx context.Context, cfg *config.AppConfig) (bool, error) {
+	gitSvc := git.NewService(func(string, string) {}, func(string, string, string) {})
+	if cfg.SSHHost != "" {
+		return ensureRemoteRepository(ctx, gitSvc, cfg)
+	}
+	if gitSvc.IsInsideRepository(ctx) {
+		return true, nil
+	}
+
+	root := cfg.WorktreeDir
+	if root == "" {
+		home, _ := os.UserHomeDir()
+		root = filepath.Join(home, ".local", "share", "worktrees")
+	}
+	repos := git.KnownRepositories(root)
+	if len(repos) == 0 {
+		return false, fmt.Errorf("not inside a git repository and no repositories found under %s; please run lazyworktree from within a repository", root)
+	}
+
+	picker := app.NewRepoPicker(cfg, repos)
+	if _, err := tea.NewProgram(picker, tea.WithAltScreen()).Run(); err != nil {
+		return false, fmt.Errorf("error running repository picker: %w", err)
+	}
+	if picker.Selected() == "" {
+		return false, nil
+	}
+	if err := os.Chdir(picker.Selected()); err != nil {
+		return false, fmt.Errorf("error changing to %s: %w", picker.Selected(), err)
+	}
+	return true, nil
+}
+
+// ensureRemoteRepository checks that ssh_path is a repository on ssh_host
+// and resolves it to its top level, below which worktrees are created
+// unless worktree_dir says otherwise.
+func ensureRemoteRepository(ctx context.Context, gitSvc *git.Service, cfg *config.AppConfig) (bool, error) {
+	gitSvc.SetRemote(cfg.SSHHost, cfg.SSHPath)
+	top := gitSvc.RunGit(ctx, []string{"git", "rev-parse", "--show-toplevel"}, "", []int{0}, true, true)
+	if top == "" {
+		return false, fmt.Errorf("no git repository at %s:%s, or the host cannot be reached with ssh", cfg.SSHHost, cfg.SSHPath)
+	}
+	cfg.SSHPath = top
+	if cfg.WorktreeDir == "" {
+		cfg.WorktreeDir = path.Join(path.Dir(top), "worktrees")
+	}
+	return true, nil
+}
+
+// reportCrash writes the crash report and tells the user where to find it.
+// The terminal has been restored by then, so plain stderr output is safe.
+func reportCrash(report *crash.Report) {
+	path, err := crash.Write(crash.Dir(), report)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "lazyworktree stopped unexpectedly and could not save a crash report: %v\n\n%s", err, report)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "lazyworktree stopped unexpectedly. A crash report has been written to:\n  %s\nPlease attach it when reporting the issue.\n", path)
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"io"
 	"os"
 	"path/filepath"
@@ -143,7 +144,7 @@ func TestPrintSyntaxThemesContainsThemes(t *testing.T) {
 
 func TestPrintVersion(t *testing.T) {
 	out := captureStdout(t, func() {
-		printVersion()
+		printVersion(newRootCommand())
 	})
 
 	if !strings.Contains(out, "lazyworktree version") {
@@ -154,6 +155,26 @@ func TestPrintVersion(t *testing.T) {
 	}
 }
 
+func TestVersionVerbose(t *testing.T) {
+	var out strings.Builder
+	cmd := newRootCommand()
+	cmd.Writer = &out
+	err := cmd.Run(context.Background(), []string{
+		"lazyworktree",
+		"--config-file", filepath.Join(t.TempDir(), "missing.yaml"),
+		"--worktree-dir", "/tmp/worktrees",
+		"--version", "--verbose",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{"version:", "git:", "lazygit:", "git host:", "worktree dir:  /tmp/worktrees"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("expected %q in output, got %q", want, out.String())
+		}
+	}
+}
+
 func TestApplyWorktreeDirConfig(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -211,6 +232,28 @@ func TestApplyWorktreeDirConfig(t *testing.T) {
 	}
 }
 
+func TestApplySSHConfig(t *testing.T) {
+	cfg := &config.AppConfig{SSHHost: "old", SSHPath: "/old"}
+	if err := applySSHConfig(cfg, "me@devbox:~/src/app"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.SSHHost != "me@devbox" || cfg.SSHPath != "~/src/app" {
+		t.Fatalf("unexpected remote %q:%q", cfg.SSHHost, cfg.SSHPath)
+	}
+	if err := applySSHConfig(cfg, "devbox"); err == nil {
+		t.Fatal("expected an error without a path")
+	}
+
+	// The worktree directory is a host path, neither expanded nor defaulted
+	// locally.
+	if err := applyWorktreeDirConfig(cfg, ""); err != nil || cfg.WorktreeDir != "" {
+		t.Fatalf("expected no local default, got %q (%v)", cfg.WorktreeDir, err)
+	}
+	if err := applyWorktreeDirConfig(cfg, "~/worktrees"); err == nil {
+		t.Fatal("expected a relative host path to be refused")
+	}
+}
+
 func TestApplyThemeConfig(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -0,0 +1,129 @@
+// Package main renders the CLI reference man page for lazyworktree.
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	appiCli "github.com/urfave/cli/v3"
+)
+
+// manCommand returns the hidden man subcommand, which prints a man page of
+// the command tree: global options, subcommands and their examples.
+func manCommand() *appiCli.Command {
+	return &appiCli.Command{
+		Name:   "man",
+		Usage:  "Print the command-line reference as a man page",
+		Hidden: true,
+		Action: func(_ context.Context, cmd *appiCli.Command) error {
+			_, err := fmt.Fprint(cmd.Root().Writer, renderManPage(cmd.Root()))
+			return err
+		},
+	}
+}
+
+// renderManPage renders root and its visible subcommands as roff.
+func renderManPage(root *appiCli.Command) string {
+	var b strings.Builder
+	name := strings.ToUpper(root.Name)
+	fmt.Fprintf(&b, ".TH %s 1 \"\" \"%s %s\" \"User Commands\"\n", name, root.Name, root.Version)
+	fmt.Fprintf(&b, ".SH NAME\n%s \\- %s\n", roffEscape(root.Name), roffEscape(root.Usage))
+	fmt.Fprintf(&b, ".SH SYNOPSIS\n.B %s\n[global options] [command [command options]]\n", roffEscape(root.Name))
+	if root.Description != "" {
+		b.WriteString(".SH DESCRIPTION\n")
+		writeManDescription(&b, root.Description)
+	}
+
+	b.WriteString(".SH OPTIONS\n")
+	writeManFlags(&b, root.VisibleFlags())
+
+	b.WriteString(".SH COMMANDS\n")
+	writeManCommands(&b, "", root.VisibleCommands())
+	return b.String()
+}
+
+// writeManCommands renders each command as a subsection, followed by its own
+// subcommands prefixed with its name.
+func writeManCommands(b *strings.Builder, prefix string, commands []*appiCli.Command) {
+	for _, sub := range commands {
+		if sub.Name == "help" {
+			continue
+		}
+		usage := prefix + sub.Name
+		if sub.ArgsUsage != "" {
+			usage += " " + sub.ArgsUsage
+		}
+		fmt.Fprintf(b, ".SS %s\n%s\n", roffEscape(usage), roffEscape(sub.Usage))
+		if sub.Description != "" {
+			b.WriteString(".PP\n")
+			writeManDescription(b, sub.Description)
+		}
+		if flags := sub.VisibleFlags(); len(flags) > 0 {
+			b.WriteString(".PP\n.B Options:\n")
+			writeManFlags(b, flags)
+		}
+		writeManCommands(b, prefix+sub.Name+" ", sub.VisibleCommands())
+	}
+}
+
+// writeManFlags renders each flag as a tagged paragraph.
+func writeManFlags(b *strings.Builder, flags []appiCli.Flag) {
+	for _, flag := range flags {
+		var names []string
+		for _, n := range flag.Names() {
+			if len(n) == 1 {
+				names = append(names, "-"+n)
+			} else {
+				names = append(names, "--"+n)
+			}
+		}
+		tag := `\fB` + roffEscape(strings.Join(names, ", ")) + `\fR`
+		usage := ""
+		if doc, ok := flag.(appiCli.DocGenerationFlag); ok {
+			if doc.TakesValue() {
+				tag += ` \fI` + strings.ToUpper(doc.TypeName()) + `\fR`
+			}
+			usage = doc.GetUsage()
+		}
+		fmt.Fprintf(b, ".TP\n%s\n%s\n", tag, roffEscape(usage))
+	}
+}
+
+// writeManDescription renders a description: blank lines separate
+// paragraphs and indented lines, such as examples, are kept verbatim.
+func writeManDescription(b *strings.Builder, text string) {
+	inExample := false
+	for line := range strings.SplitSeq(strings.TrimSpace(text), "\n") {
+		indented := strings.HasPrefix(line, "  ")
+		switch {
+		case indented && !inExample:
+			b.WriteString(".RS\n.nf\n")
+			inExample = true
+		case !indented && inExample:
+			b.WriteString(".fi\n.RE\n")
+			inExample = false
+		}
+		switch {
+		case indented:
+			b.WriteString(roffEscape(strings.TrimPrefix(line, "  ")) + "\n")
+		case strings.TrimSpace(line) == "":
+			b.WriteString(".PP\n")
+		default:
+			b.WriteString(roffEscape(line) + "\n")
+		}
+	}
+	if inExample {
+		b.WriteString(".fi\n.RE\n")
+	}
+}
+
+// roffEscape escapes text for roff: backslashes and hyphens are escaped and
+// a leading dot or quote cannot start a request.
+func roffEscape(s string) string {
+	s = strings.NewReplacer(`\`, `\e`, "-", `\-`).Replace(s)
+	if strings.HasPrefix(s, ".") || strings.HasPrefix(s, "'") {
+		s = `\&` + s
+	}
+	return s
+}
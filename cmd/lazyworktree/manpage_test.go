@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestManCommand(t *testing.T) {
+	var out bytes.Buffer
+	root := newRootCommand()
+	root.Writer = &out
+	if err := root.Run(context.Background(), []string{"lazyworktree", "man"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	page := out.String()
+	for _, want := range []string{
+		".TH LAZYWORKTREE 1",
+		".SH OPTIONS",
+		`\fB\-\-worktree\-dir, \-w\fR \fISTRING\fR`,
+		`.SS wt\-delete [worktree\-path]`,
+		".RS\n.nf\nlazyworktree wt\\-create\n",
+		".SS completion SHELL",
+		`.SS config set\-secret name`,
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("expected man page to contain %q", want)
+		}
+	}
+	for _, unwanted := range []string{".SS man", ".SS help"} {
+		if strings.Contains(page, unwanted) {
+			t.Errorf("expected man page to leave out %q", unwanted)
+		}
+	}
+}
+
+func TestRootHelpHasExamples(t *testing.T) {
+	var out bytes.Buffer
+	root := newRootCommand()
+	root.Writer = &out
+	if err := root.Run(context.Background(), []string{"lazyworktree", "wt-create", "--help"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	help := out.String()
+	if !strings.Contains(help, "Examples:") || !strings.Contains(help, "lazyworktree wt-create --from-pr 123 --silent") {
+		t.Fatalf("expected examples in help, got %q", help)
+	}
+}
+
+func TestRoffEscape(t *testing.T) {
+	tests := map[string]string{
+		"--config-file": `\-\-config\-file`,
+		`C:\path`:       `C:\epath`,
+		".wt file":      `\&.wt file`,
+		"'quoted'":      `\&'quoted'`,
+	}
+	for in, want := range tests {
+		if got := roffEscape(in); got != want {
+			t.Errorf("roffEscape(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
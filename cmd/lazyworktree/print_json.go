@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/chmouel/lazyworktree/internal/control"
+	"github.com/chmouel/lazyworktree/internal/models"
+	"github.com/chmouel/lazyworktree/internal/utils"
+	"github.com/urfave/cli/v3"
+)
+
+// printWorktreesJSON prints the current repository's worktrees as a JSON
+// array, shaped like the answer to ctl list, without starting the TUI.
+// PRs are those the TUI last saw, as nothing is fetched.
+func printWorktreesJSON(ctx context.Context, cmd *cli.Command) error {
+	cfg, err := loadCLIConfig(cmd.String("config-file"), cmd.String("worktree-dir"), cmd.StringSlice("config"))
+	if err != nil {
+		return err
+	}
+	gitSvc := newCLIGitService(cfg)
+	if !gitSvc.IsInsideRepository(ctx) {
+		return errors.New("not inside a git repository")
+	}
+	worktrees, err := gitSvc.GetWorktrees(ctx)
+	if err != nil {
+		return err
+	}
+	cached := cachedPRs(filepath.Join(utils.CacheDir(), gitSvc.ResolveRepoName(ctx), models.CacheFilename))
+	return writeWorktreesJSON(cmd.Root().Writer, worktrees, cached)
+}
+
+// writeWorktreesJSON writes worktrees to w, with their cached PRs.
+func writeWorktreesJSON(w io.Writer, worktrees []*models.WorktreeInfo, prs map[string]*models.PRInfo) error {
+	list := make([]control.Worktree, 0, len(worktrees))
+	for _, wt := range worktrees {
+		if wt.PR == nil {
+			wt.PR = prs[wt.Path]
+		}
+		list = append(list, control.NewWorktree(wt, false))
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(list)
+}
+
+// cachedPRs reads the PRs the TUI recorded in its worktree cache, by
+// worktree path.
+func cachedPRs(cachePath string) map[string]*models.PRInfo {
+	// #nosec G304 -- the cache file lazyworktree itself writes
+	data, err := os.ReadFile(cachePath)
+	if err != nil {
+		return nil
+	}
+	var cache struct {
+		Worktrees []*models.WorktreeInfo `json:"worktrees"`
+	}
+	if json.Unmarshal(data, &cache) != nil {
+		return nil
+	}
+	prs := make(map[string]*models.PRInfo)
+	for _, wt := range cache.Worktrees {
+		if wt.PR != nil {
+			prs[wt.Path] = wt.PR
+		}
+	}
+	return prs
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/chmouel/lazyworktree/internal/control"
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+func TestWriteWorktreesJSON(t *testing.T) {
+	cachePath := filepath.Join(t.TempDir(), models.CacheFilename)
+	cache := `{"version":1,"repo":"/repo","worktrees":[{"Path":"/wt/feature","PR":{"Number":12,"State":"OPEN","URL":"https://example.com/pull/12"}}]}`
+	if err := os.WriteFile(cachePath, []byte(cache), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	prs := cachedPRs(cachePath)
+	if cachedPRs(filepath.Join(t.TempDir(), "missing")) != nil || prs["/wt/feature"] == nil {
+		t.Fatalf("expected the cached PR of feature, got %+v", prs)
+	}
+
+	var buf bytes.Buffer
+	worktrees := []*models.WorktreeInfo{
+		{Path: "/repo", Branch: "main", IsMain: true},
+		{Path: "/wt/feature", Branch: "feature", Dirty: true, Ahead: 2, Behind: 1},
+	}
+	if err := writeWorktreesJSON(&buf, worktrees, prs); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	var got []control.Worktree
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("expected a JSON array, got %q: %v", buf.String(), err)
+	}
+	if len(got) != 2 || !got[0].Main || got[0].PR != nil {
+		t.Fatalf("expected the main worktree first without a PR, got %+v", got)
+	}
+	feature := got[1]
+	if feature.Name != "feature" || !feature.Dirty || feature.Ahead != 2 || feature.Behind != 1 || feature.PR == nil || feature.PR.Number != 12 {
+		t.Fatalf("expected feature with its state and cached PR, got %+v", feature)
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/chmouel/lazyworktree/internal/git"
+	"github.com/chmouel/lazyworktree/internal/models"
+	"github.com/chmouel/lazyworktree/internal/prompt"
+	"github.com/chmouel/lazyworktree/internal/utils"
+	appiCli "github.com/urfave/cli/v3"
+)
+
+// promptCommand returns the prompt subcommand definition.
+func promptCommand() *appiCli.Command {
+	return &appiCli.Command{
+		Name:  "prompt",
+		Usage: "Print a one-line worktree summary for shell prompts and status bars",
+		Description: `Prints the number of worktrees besides the main one, and of those dirty,
+failing CI or with an open pull request, e.g. "3wt 1* 1✗ 2pr". It reads
+the cache lazyworktree keeps, so it takes a few milliseconds and reflects
+the last refresh made in the TUI. Nothing is printed outside a repository
+or before lazyworktree has been opened on it.
+
+--format takes a Go template over .Repo, .Worktrees, .Dirty, .FailingCI
+and .OpenPRs.
+
+Examples:
+  lazyworktree prompt
+  lazyworktree prompt --format '{{.Repo}}: {{.OpenPRs}} PRs'
+  set -g status-right '#(cd "#{pane_current_path}" && lazyworktree prompt)'`,
+		Action: handlePromptAction,
+		Flags: []appiCli.Flag{
+			&appiCli.StringFlag{
+				Name:  "format",
+				Usage: "Go template for the summary",
+				Value: prompt.DefaultFormat,
+			},
+		},
+	}
+}
+
+// handlePromptAction handles the prompt subcommand action.
+func handlePromptAction(ctx context.Context, cmd *appiCli.Command) error {
+	dir, err := os.Getwd()
+	if err != nil {
+		return nil
+	}
+	line, err := promptLine(dir, utils.CacheDir(), cmd.String("format"), func() string {
+		return git.NewService(cliNotify, cliNotifyOnce).ResolveRepoName(ctx)
+	})
+	if err != nil || line == "" {
+		return err
+	}
+	_, _ = fmt.Fprintln(cmd.Root().Writer, line)
+	return nil
+}
+
+// promptLine renders the summary for the repository containing dir, from
+// the caches under cacheDir. resolveRepo, which asks git, is only called
+// the first time a worktree is seen.
+func promptLine(dir, cacheDir, format string, resolveRepo func() string) (string, error) {
+	root := prompt.WorktreeRoot(dir)
+	if root == "" {
+		return "", nil
+	}
+	index := prompt.LoadRepoIndex(filepath.Join(cacheDir, prompt.RepoIndexFilename))
+	repo, ok := index.Lookup(root)
+	if !ok {
+		repo = resolveRepo()
+		if repo == "" {
+			return "", nil
+		}
+		// A failure only costs the next prompt another lookup.
+		_ = index.Store(root, repo)
+	}
+	worktrees, err := prompt.ReadWorktrees(filepath.Join(cacheDir, repo, models.CacheFilename))
+	if err != nil {
+		return "", nil
+	}
+	return prompt.Render(format, prompt.Summarise(repo, worktrees))
+}
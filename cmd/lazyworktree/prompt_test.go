@@ -0,0 +1,55 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+func TestPromptLine(t *testing.T) {
+	cacheDir := t.TempDir()
+	repoDir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(repoDir, ".git"), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	subdir := filepath.Join(repoDir, "internal")
+	if err := os.Mkdir(subdir, 0o750); err != nil {
+		t.Fatal(err)
+	}
+
+	resolved := 0
+	resolve := func() string {
+		resolved++
+		return "owner/repo"
+	}
+
+	line, err := promptLine(subdir, cacheDir, "{{.Worktrees}}", resolve)
+	if err != nil || line != "" {
+		t.Fatalf("expected nothing before the TUI has written its cache, got %q (%v)", line, err)
+	}
+
+	cache := `{"version":1,"worktrees":[{"Path":"/repo","IsMain":true},{"Path":"/wt/a","Dirty":true}]}`
+	if err := os.MkdirAll(filepath.Join(cacheDir, "owner/repo"), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(cacheDir, "owner/repo", models.CacheFilename), []byte(cache), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	line, err = promptLine(subdir, cacheDir, "{{.Repo}} {{.Worktrees}} {{.Dirty}}", resolve)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if line != "owner/repo 1 1" {
+		t.Fatalf("unexpected line %q", line)
+	}
+	if resolved != 1 {
+		t.Fatalf("expected the repository to be resolved once, got %d", resolved)
+	}
+
+	line, err = promptLine(t.TempDir(), cacheDir, "{{.Worktrees}}", resolve)
+	if err != nil || line != "" {
+		t.Fatalf("expected nothing outside a repository, got %q (%v)", line, err)
+	}
+}
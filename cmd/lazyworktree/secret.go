@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/chmouel/lazyworktree/internal/secrets"
+	appiCli "github.com/urfave/cli/v3"
+	"golang.org/x/term"
+)
+
+// configCommand returns the config subcommand, grouping configuration
+// helpers.
+func configCommand() *appiCli.Command {
+	return &appiCli.Command{
+		Name:  "config",
+		Usage: "Configuration helpers",
+		Commands: []*appiCli.Command{
+			setSecretCommand(),
+			syncTeamCommand(),
+		},
+	}
+}
+
+// setSecretCommand returns the config set-secret subcommand definition.
+func setSecretCommand() *appiCli.Command {
+	return &appiCli.Command{
+		Name:      "set-secret",
+		Usage:     "Store a token in the operating system's credential store",
+		ArgsUsage: "name",
+		Description: `Stores a token under name in the macOS Keychain, the Secret Service
+(secret-tool), the Linux kernel keyring (keyctl) or, on Windows, encrypted
+with DPAPI. The value is read from the terminal without echo, or from
+stdin when piped. Reference it in the configuration instead of writing the
+token itself, e.g. github_token: secret:github.
+
+Examples:
+  lazyworktree config set-secret github
+  gh auth token | lazyworktree config set-secret github`,
+		Action: handleSetSecretAction,
+	}
+}
+
+// handleSetSecretAction handles the config set-secret subcommand action.
+func handleSetSecretAction(ctx context.Context, cmd *appiCli.Command) error {
+	if cmd.NArg() != 1 {
+		return errors.New("set-secret expects the name to store the token under, e.g. lazyworktree config set-secret github")
+	}
+	name := cmd.Args().First()
+	value, err := readSecret(os.Stdin, name, cmd.Root().ErrWriter)
+	if err != nil {
+		return err
+	}
+	return setSecret(ctx, secrets.Default(), name, value, cmd.Root().Writer)
+}
+
+// readSecret prompts for the secret on a terminal, or reads it from in.
+func readSecret(in *os.File, name string, prompt io.Writer) (string, error) {
+	if prompt == nil {
+		prompt = os.Stderr
+	}
+	fd := int(in.Fd()) // #nosec G115 -- file descriptors fit in an int
+	if term.IsTerminal(fd) {
+		_, _ = fmt.Fprintf(prompt, "Token for %s: ", name)
+		data, err := term.ReadPassword(fd)
+		_, _ = fmt.Fprintln(prompt)
+		if err != nil {
+			return "", fmt.Errorf("failed to read the token: %w", err)
+		}
+		return string(data), nil
+	}
+	data, err := io.ReadAll(in)
+	if err != nil {
+		return "", fmt.Errorf("failed to read the token: %w", err)
+	}
+	return string(data), nil
+}
+
+// setSecret stores value under name in backend.
+func setSecret(ctx context.Context, backend secrets.Backend, name, value string, out io.Writer) error {
+	if err := secrets.ValidateName(name); err != nil {
+		return err
+	}
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return errors.New("refusing to store an empty token")
+	}
+	if backend == nil {
+		return fmt.Errorf("%w; install secret-tool or keyctl, or keep the token in an environment variable", secrets.ErrUnavailable)
+	}
+	if err := backend.Set(ctx, name, value); err != nil {
+		return fmt.Errorf("failed to store %s in %s: %w", name, backend.Name(), err)
+	}
+	_, _ = fmt.Fprintf(out, "Stored %s in %s. Reference it in the configuration as:\n  github_token: %s%s\n", name, backend.Name(), secrets.Prefix, name)
+	return nil
+}
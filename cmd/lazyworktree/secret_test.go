@@ -0,0 +1,49 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type memorySecrets map[string]string
+
+func (m memorySecrets) Name() string { return "test store" }
+
+func (m memorySecrets) Get(_ context.Context, name string) (string, error) {
+	if v, ok := m[name]; ok {
+		return v, nil
+	}
+	return "", errors.New("not found")
+}
+
+func (m memorySecrets) Set(_ context.Context, name, value string) error {
+	m[name] = value
+	return nil
+}
+
+func TestSetSecret(t *testing.T) {
+	store := memorySecrets{}
+	var out bytes.Buffer
+	if err := setSecret(context.Background(), store, "github", "ghp_secret\n", &out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if store["github"] != "ghp_secret" {
+		t.Fatalf("expected the trimmed token to be stored, got %q", store["github"])
+	}
+	if !strings.Contains(out.String(), "github_token: secret:github") {
+		t.Fatalf("expected the reference to be printed, got %q", out.String())
+	}
+
+	if err := setSecret(context.Background(), store, "github", "  ", &out); err == nil {
+		t.Fatal("expected an empty token to be refused")
+	}
+	if err := setSecret(context.Background(), store, "../escape", "x", &out); err == nil {
+		t.Fatal("expected an invalid name to be refused")
+	}
+	if err := setSecret(context.Background(), nil, "github", "x", &out); err == nil {
+		t.Fatal("expected an error without a credential store")
+	}
+}
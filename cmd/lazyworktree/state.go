@@ -0,0 +1,161 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/chmouel/lazyworktree/internal/config"
+	"github.com/chmouel/lazyworktree/internal/security"
+	"github.com/chmouel/lazyworktree/internal/statebundle"
+	"github.com/chmouel/lazyworktree/internal/utils"
+	appiCli "github.com/urfave/cli/v3"
+)
+
+// exportStateCommand returns the export-state subcommand definition.
+func exportStateCommand() *appiCli.Command {
+	return &appiCli.Command{
+		Name:      "export-state",
+		Usage:     "Bundle the configuration and worktree metadata into a tarball",
+		ArgsUsage: "[file]",
+		Description: `Writes the configuration file, the trust database and the per-repository
+state kept under the worktree directory (last selection and command,
+access and navigation history) to a .tar.gz, to be restored with
+import-state on another machine. Worktree checkouts and caches are not
+included.
+
+Examples:
+  lazyworktree export-state
+  lazyworktree export-state ~/lazyworktree-state.tar.gz`,
+		Action: handleExportStateAction,
+	}
+}
+
+// importStateCommand returns the import-state subcommand definition.
+func importStateCommand() *appiCli.Command {
+	return &appiCli.Command{
+		Name:      "import-state",
+		Usage:     "Restore a tarball written by export-state",
+		ArgsUsage: "file",
+		Description: `Restores the configuration, trust database and per-repository state from
+a bundle written by export-state. Existing files are kept unless --force is
+given.
+
+Examples:
+  lazyworktree import-state lazyworktree-state.tar.gz
+  lazyworktree --worktree-dir ~/src/worktrees import-state state.tar.gz --force`,
+		Action: handleImportStateAction,
+		Flags: []appiCli.Flag{
+			&appiCli.BoolFlag{
+				Name:  "force",
+				Usage: "Overwrite files that already exist",
+			},
+		},
+	}
+}
+
+// statePaths resolves where the state lives for the current configuration.
+func statePaths(cmd *appiCli.Command) (statebundle.Paths, error) {
+	cfg, err := loadCLIConfig(
+		cmd.String("config-file"),
+		cmd.String("worktree-dir"),
+		cmd.StringSlice("config"),
+	)
+	if err != nil {
+		return statebundle.Paths{}, err
+	}
+	configFile := cfg.ConfigPath
+	if flag := cmd.String("config-file"); flag != "" {
+		expanded, err := utils.ExpandPath(flag)
+		if err != nil {
+			return statebundle.Paths{}, err
+		}
+		configFile = expanded
+	}
+	if configFile == "" {
+		configFile = config.DefaultConfigPath()
+	}
+	return statebundle.Paths{
+		ConfigFile:  configFile,
+		TrustDB:     security.TrustDBPath(),
+		WorktreeDir: cfg.WorktreeDir,
+	}, nil
+}
+
+// handleExportStateAction handles the export-state subcommand action.
+func handleExportStateAction(_ context.Context, cmd *appiCli.Command) error {
+	paths, err := statePaths(cmd)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	target := cmd.Args().First()
+	if target == "" {
+		target = fmt.Sprintf("lazyworktree-state-%s.tar.gz", now.Format("20060102"))
+	}
+	return exportState(paths, target, now, cmd.Root().Writer)
+}
+
+// exportState writes the bundle to target, replacing it only once complete.
+func exportState(paths statebundle.Paths, target string, now time.Time, out io.Writer) error {
+	dir := filepath.Dir(target)
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(target)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", target, err)
+	}
+	tmpPath := tmp.Name()
+	defer func() { _ = os.Remove(tmpPath) }()
+
+	entries, err := statebundle.Export(tmp, paths, now)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, target); err != nil {
+		return fmt.Errorf("failed to write %s: %w", target, err)
+	}
+	_, _ = fmt.Fprintf(out, "Exported %d files to %s.\n", len(entries), target)
+	return nil
+}
+
+// handleImportStateAction handles the import-state subcommand action.
+func handleImportStateAction(_ context.Context, cmd *appiCli.Command) error {
+	if cmd.NArg() != 1 {
+		return fmt.Errorf("import-state expects the bundle to restore, e.g. lazyworktree import-state state.tar.gz")
+	}
+	paths, err := statePaths(cmd)
+	if err != nil {
+		return err
+	}
+	return importState(paths, cmd.Args().First(), cmd.Bool("force"), cmd.Root().Writer)
+}
+
+// importState restores the bundle at source and reports what changed.
+func importState(paths statebundle.Paths, source string, force bool, out io.Writer) error {
+	// #nosec G304 -- the bundle path is supplied by the user
+	f, err := os.Open(source)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", source, err)
+	}
+	defer func() { _ = f.Close() }()
+
+	result, err := statebundle.Import(f, paths, force)
+	if err != nil {
+		return err
+	}
+	for _, path := range result.Written {
+		_, _ = fmt.Fprintf(out, "Restored %s\n", path)
+	}
+	for _, path := range result.Skipped {
+		_, _ = fmt.Fprintf(out, "Kept existing %s\n", path)
+	}
+	if len(result.Skipped) > 0 {
+		_, _ = fmt.Fprintln(out, "Use --force to overwrite the files kept.")
+	}
+	return nil
+}
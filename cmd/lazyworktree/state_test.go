@@ -0,0 +1,71 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExportImportState(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	src := t.TempDir()
+	configFile := filepath.Join(src, "config.yaml")
+	worktreeDir := filepath.Join(src, "worktrees")
+	stateFile := filepath.Join(worktreeDir, "owner", "repo", ".last-selected")
+	if err := os.WriteFile(configFile, []byte("sort_mode: active\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(stateFile), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(stateFile, []byte("/wt/feature"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	bundlePath := filepath.Join(t.TempDir(), "state.tar.gz")
+	var out bytes.Buffer
+	root := newRootCommand()
+	root.Writer = &out
+	if err := root.Run(context.Background(), []string{
+		"lazyworktree", "--config-file", configFile, "--worktree-dir", worktreeDir,
+		"export-state", bundlePath,
+	}); err != nil {
+		t.Fatalf("export-state failed: %v", err)
+	}
+	if !strings.Contains(out.String(), "Exported 2 files") {
+		t.Fatalf("unexpected export output %q", out.String())
+	}
+
+	dst := t.TempDir()
+	out.Reset()
+	root = newRootCommand()
+	root.Writer = &out
+	if err := root.Run(context.Background(), []string{
+		"lazyworktree", "--config-file", filepath.Join(dst, "config.yaml"), "--worktree-dir", filepath.Join(dst, "worktrees"),
+		"import-state", bundlePath,
+	}); err != nil {
+		t.Fatalf("import-state failed: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dst, "worktrees", "owner", "repo", ".last-selected"))
+	if err != nil {
+		t.Fatalf("expected state to be restored: %v", err)
+	}
+	if string(data) != "/wt/feature" {
+		t.Fatalf("unexpected restored state %q", data)
+	}
+	if _, err := os.Stat(filepath.Join(dst, "config.yaml")); err != nil {
+		t.Fatalf("expected configuration to be restored: %v", err)
+	}
+}
+
+func TestImportStateRequiresFile(t *testing.T) {
+	root := newRootCommand()
+	root.Writer = &bytes.Buffer{}
+	err := root.Run(context.Background(), []string{"lazyworktree", "import-state"})
+	if err == nil || !strings.Contains(err.Error(), "expects the bundle") {
+		t.Fatalf("expected a missing bundle error, got %v", err)
+	}
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
 
@@ -15,6 +16,8 @@ func loadCLIConfig(configFileFlag, worktreeDirFlag string, configOverrides []str
 		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
 		cfg = config.DefaultConfig()
 	}
+	// Subcommands work on the local repository; SSH mode is the TUI's.
+	cfg.SSHHost, cfg.SSHPath = "", ""
 
 	if err := applyWorktreeDirConfig(cfg, worktreeDirFlag); err != nil {
 		return nil, err
@@ -25,6 +28,9 @@ func loadCLIConfig(configFileFlag, worktreeDirFlag string, configOverrides []str
 			return nil, fmt.Errorf("error applying config overrides: %w", err)
 		}
 	}
+	if err := cfg.ResolveSecrets(context.Background()); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
 
 	return cfg, nil
 }
@@ -34,6 +40,7 @@ func newCLIGitService(cfg *config.AppConfig) *git.Service {
 	gitSvc := git.NewService(cliNotify, cliNotifyOnce)
 	gitSvc.SetGitPager(cfg.GitPager)
 	gitSvc.SetGitPagerArgs(cfg.GitPagerArgs)
+	gitSvc.SetForgeTokens(cfg.GitHubToken, cfg.GitLabToken)
 	return gitSvc
 }
 
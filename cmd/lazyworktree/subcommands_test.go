@@ -2,6 +2,10 @@ package main
 
 import (
 	"context"
+	"errors"
+	"os/exec"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	urfavecli "github.com/urfave/cli/v3"
@@ -195,3 +199,64 @@ func TestHandleWtDeleteFlags(t *testing.T) {
 		})
 	}
 }
+
+func TestWorktreeCommandsRefuseReadOnly(t *testing.T) {
+	for _, args := range [][]string{
+		{"--read-only", "wt-create", "--name", "feature"},
+		{"--safe", "wt-create", "--name", "feature"},
+		{"--read-only", "create", "feature"},
+		{"--config", "lw.read_only=true", "wt-delete", "feature"},
+	} {
+		root := newRootCommand()
+		err := root.Run(context.Background(), append([]string{
+			"lazyworktree", "--config-file", filepath.Join(t.TempDir(), "missing.yaml"),
+		}, args...))
+		if !errors.Is(err, errReadOnly) {
+			t.Fatalf("%v: expected errReadOnly, got %v", args, err)
+		}
+	}
+}
+
+func TestCreateCommand(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	repo := filepath.Join(t.TempDir(), "shop")
+	for _, args := range [][]string{
+		{"init", "-q", "-b", "main", repo},
+		{"-C", repo, "-c", "user.name=Test", "-c", "user.email=test@example.com", "commit", "-q", "--allow-empty", "-m", "initial"},
+	} {
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	t.Chdir(repo)
+	worktreeDir := t.TempDir()
+
+	var err error
+	out := captureStdout(t, func() {
+		err = newRootCommand().Run(context.Background(), []string{
+			"lazyworktree", "--config-file", filepath.Join(t.TempDir(), "missing.yaml"),
+			"--worktree-dir", worktreeDir, "create", "--silent", "Login Page",
+		})
+	})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	path := strings.TrimSpace(out)
+	if filepath.Base(path) != "login-page" || !strings.HasPrefix(path, worktreeDir) {
+		t.Fatalf("expected the login-page worktree under %s, got %q", worktreeDir, out)
+	}
+	branch, _ := exec.Command("git", "-C", path, "branch", "--show-current").Output()
+	if got := strings.TrimSpace(string(branch)); got != "login-page" {
+		t.Fatalf("expected the new worktree on login-page, got %q", got)
+	}
+
+	err = newRootCommand().Run(context.Background(), []string{
+		"lazyworktree", "--config-file", filepath.Join(t.TempDir(), "missing.yaml"),
+		"--worktree-dir", worktreeDir, "create", "--silent", "login-page",
+	})
+	if err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Fatalf("expected an existing branch to be refused, got %v", err)
+	}
+}
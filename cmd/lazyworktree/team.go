@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/chmouel/lazyworktree/internal/config"
+	"github.com/chmouel/lazyworktree/internal/security"
+	"github.com/chmouel/lazyworktree/internal/teamconfig"
+	appiCli "github.com/urfave/cli/v3"
+)
+
+// syncTeamCommand returns the config sync-team subcommand definition.
+func syncTeamCommand() *appiCli.Command {
+	return &appiCli.Command{
+		Name:  "sync-team",
+		Usage: "Fetch the team's shared .wt configuration",
+		Description: `Fetches the .wt file named by team_config, either an https:// URL or a
+ref such as refs/meta/lazyworktree fetched from origin, and keeps it in the
+repository's git directory. Its keys apply wherever the repository's own .wt
+does not set them.
+
+Set team_config_sha256 to pin the file's SHA-256, or, for a ref,
+team_config_verify_signature to require a commit signed by a key git trusts.
+A verified file is trusted at once; otherwise its commands are shown for
+approval before they first run.
+
+Examples:
+  lazyworktree config sync-team
+  lazyworktree --config=lw.team_config=refs/meta/lazyworktree config sync-team`,
+		Action: handleSyncTeamAction,
+	}
+}
+
+// handleSyncTeamAction handles the config sync-team subcommand action.
+func handleSyncTeamAction(ctx context.Context, cmd *appiCli.Command) error {
+	cfg, err := loadCLIConfig(
+		cmd.String("config-file"),
+		cmd.String("worktree-dir"),
+		cmd.StringSlice("config"),
+	)
+	if err != nil {
+		return err
+	}
+	src, err := teamconfig.ParseSource(cfg.TeamConfig)
+	if err != nil {
+		return err
+	}
+	mainPath := newCLIGitService(cfg).GetMainWorktreePath(ctx)
+	if mainPath == "" {
+		return errors.New("sync-team must be run inside a git repository")
+	}
+	opts := teamconfig.Options{SHA256: cfg.TeamConfigSHA256, VerifySignature: cfg.TeamConfigVerify}
+	return syncTeam(ctx, teamconfig.NewFetcher(mainPath), security.NewTrustManager(), src, opts, config.TeamConfigPath(mainPath), cmd.Root().Writer)
+}
+
+// syncTeam fetches the team file into target, trusting it when verified.
+func syncTeam(ctx context.Context, fetcher *teamconfig.Fetcher, trust *security.TrustManager, src teamconfig.Source, opts teamconfig.Options, target string, out io.Writer) error {
+	result, err := fetcher.Fetch(ctx, src, opts)
+	if err != nil {
+		return err
+	}
+	if err := teamconfig.Save(target, result.Data); err != nil {
+		return fmt.Errorf("failed to save the team configuration: %w", err)
+	}
+	if len(result.Verified) == 0 {
+		_, _ = fmt.Fprintf(out, "Saved the team configuration from %s to %s.\nIt is not pinned by team_config_sha256 or team_config_verify_signature, so its commands will be shown for approval before they first run.\n", src, target)
+		return nil
+	}
+	if err := trust.TrustFile(target); err != nil {
+		return fmt.Errorf("failed to trust %s: %w", target, err)
+	}
+	_, _ = fmt.Fprintf(out, "Saved the team configuration from %s to %s, verified by %s.\n", src, target, strings.Join(result.Verified, " and "))
+	return nil
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/chmouel/lazyworktree/internal/security"
+	"github.com/chmouel/lazyworktree/internal/teamconfig"
+)
+
+func TestSyncTeam(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	fetcher := &teamconfig.Fetcher{Git: func(_ context.Context, _ string, args ...string) (string, error) {
+		if args[0] == "show" {
+			return "init_commands:\n  - npm ci\n", nil
+		}
+		return "", nil
+	}}
+	src := teamconfig.Source{Ref: "refs/meta/lazyworktree", File: teamconfig.DefaultFile}
+	target := filepath.Join(t.TempDir(), "lazyworktree", "team.wt")
+	trust := security.NewTrustManager()
+
+	var out bytes.Buffer
+	if err := syncTeam(context.Background(), fetcher, trust, src, teamconfig.Options{}, target, &out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out.String(), "shown for approval") {
+		t.Fatalf("expected an unverified file to await approval, got %q", out.String())
+	}
+	if status := trust.CheckTrust(target); status != security.TrustStatusUntrusted {
+		t.Fatalf("expected an unverified file to stay untrusted, got %v", status)
+	}
+
+	out.Reset()
+	if err := syncTeam(context.Background(), fetcher, trust, src, teamconfig.Options{VerifySignature: true}, target, &out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out.String(), "verified by signed commit") {
+		t.Fatalf("expected the verification to be reported, got %q", out.String())
+	}
+	if status := trust.CheckTrust(target); status != security.TrustStatusTrusted {
+		t.Fatalf("expected a verified file to be trusted, got %v", status)
+	}
+}
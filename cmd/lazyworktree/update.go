@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/chmouel/lazyworktree/internal/update"
+	appiCli "github.com/urfave/cli/v3"
+)
+
+// errUpdatesDisabled is returned when self_update is false.
+var errUpdatesDisabled = errors.New("self-update is disabled by self_update: false in the configuration")
+
+// updateCommand returns the update subcommand definition.
+func updateCommand() *appiCli.Command {
+	return &appiCli.Command{
+		Name:  "update",
+		Usage: "Update lazyworktree to the latest release",
+		Description: `Checks the latest GitHub release and, when it is newer, downloads the
+archive for this platform, verifies it against the release checksums and
+replaces the running executable atomically. Set self_update: false to
+disable it, for instance when a package manager owns the installation.
+
+Examples:
+  lazyworktree update
+  lazyworktree update --check`,
+		Action: handleUpdateAction,
+		Flags: []appiCli.Flag{
+			&appiCli.BoolFlag{
+				Name:  "check",
+				Usage: "Only report whether a newer release is available",
+			},
+			&appiCli.BoolFlag{
+				Name:  "force",
+				Usage: "Install the latest release even when it is not newer, such as on development builds",
+			},
+		},
+	}
+}
+
+// handleUpdateAction handles the update subcommand action.
+func handleUpdateAction(ctx context.Context, cmd *appiCli.Command) error {
+	cfg, err := loadCLIConfig(
+		cmd.String("config-file"),
+		cmd.String("worktree-dir"),
+		cmd.StringSlice("config"),
+	)
+	if err != nil {
+		return err
+	}
+	if !cfg.SelfUpdate {
+		return errUpdatesDisabled
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate the running executable: %w", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+		exe = resolved
+	}
+	updater := update.New()
+	updater.Token = cfg.GitHubToken
+	return runUpdate(ctx, updater, exe, version, cmd.Bool("check"), cmd.Bool("force"), cmd.Root().Writer)
+}
+
+// runUpdate installs the latest release over exe when it is newer than
+// current, or when force is set.
+func runUpdate(ctx context.Context, updater *update.Updater, exe, current string, check, force bool, out io.Writer) error {
+	release, err := updater.Latest(ctx)
+	if err != nil {
+		return err
+	}
+	newer := update.IsNewer(current, release.Tag)
+	if check {
+		if newer {
+			_, _ = fmt.Fprintf(out, "lazyworktree %s is available (installed: %s).\n", release.Tag, current)
+		} else {
+			_, _ = fmt.Fprintf(out, "lazyworktree %s is up to date (latest: %s).\n", current, release.Tag)
+		}
+		return nil
+	}
+	if !newer && !force {
+		_, _ = fmt.Fprintf(out, "lazyworktree %s is up to date (latest: %s); use --force to reinstall.\n", current, release.Tag)
+		return nil
+	}
+
+	_, _ = fmt.Fprintf(out, "Downloading lazyworktree %s...\n", release.Tag)
+	binary, err := updater.Download(ctx, release)
+	if err != nil {
+		return err
+	}
+	if err := update.Replace(exe, binary); err != nil {
+		return fmt.Errorf("%w; if a package manager installed lazyworktree, please update it there", err)
+	}
+	_, _ = fmt.Fprintf(out, "Updated %s to %s.\n", exe, release.Tag)
+	return nil
+}
@@ -0,0 +1,21 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestUpdateDisabledByConfig(t *testing.T) {
+	cmd := newRootCommand()
+	err := cmd.Run(context.Background(), []string{
+		"lazyworktree",
+		"--config-file", filepath.Join(t.TempDir(), "missing.yaml"),
+		"--config", "lw.self_update=false",
+		"update", "--check",
+	})
+	if !errors.Is(err, errUpdatesDisabled) {
+		t.Fatalf("expected errUpdatesDisabled, got %v", err)
+	}
+}
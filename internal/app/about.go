@@ -0,0 +1,48 @@
+package app
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/chmouel/lazyworktree/internal/diagnostics"
+)
+
+// aboutReadyMsg carries the diagnostics gathered for the about screen.
+type aboutReadyMsg struct {
+	report string
+}
+
+// SetBuildInfo records the binary's version details for the about screen.
+func (m *Model) SetBuildInfo(build diagnostics.Build) {
+	m.build = build
+}
+
+// showAbout gathers versions and paths in the background, since probing the
+// external tools can take a moment.
+func (m *Model) showAbout() tea.Cmd {
+	m.statusContent = "Gathering diagnostics..."
+	build := m.build
+	if build.Version == "" {
+		build.Version = "dev"
+	}
+	worktreeDir := m.getWorktreeDir()
+	configPath := m.config.ConfigPath
+	return func() tea.Msg {
+		report := &diagnostics.Report{
+			Build:       build,
+			Tools:       diagnostics.ToolVersions(m.ctx),
+			Host:        m.git.DetectHost(m.ctx),
+			ConfigPath:  configPath,
+			WorktreeDir: worktreeDir,
+		}
+		return aboutReadyMsg{report: report.String()}
+	}
+}
+
+// handleAboutReady shows the diagnostics, which y copies for a bug report.
+func (m *Model) handleAboutReady(msg aboutReadyMsg) {
+	m.statusContent = ""
+	body := fmt.Sprintf("Please include this when reporting an issue; press y to copy it.\n\n```\n%s```\n", msg.report)
+	m.showMarkdown("About lazyworktree", "", body, screenNone)
+	m.markdownScreen.copyText = msg.report
+}
@@ -0,0 +1,69 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+// activityDays is how far back the info pane's activity sparkline reaches.
+const activityDays = 30
+
+// sparkLevels draw a day's activity relative to the busiest day.
+var sparkLevels = []rune("▁▂▃▄▅▆▇█")
+
+// sparkline draws one bar per count, scaled to the largest, with a dot for
+// days without any.
+func sparkline(counts []int) string {
+	peak := 0
+	for _, c := range counts {
+		peak = max(peak, c)
+	}
+	var b strings.Builder
+	for _, c := range counts {
+		if c == 0 {
+			b.WriteRune('·')
+			continue
+		}
+		level := (c*len(sparkLevels) + peak - 1) / peak // Rounded up, so any activity shows
+		b.WriteRune(sparkLevels[level-1])
+	}
+	return b.String()
+}
+
+// setActivity records a worktree's activity per day.
+func (m *Model) setActivity(path string, counts []int) {
+	if counts == nil {
+		return
+	}
+	if m.activity == nil {
+		m.activity = make(map[string][]int)
+	}
+	m.activity[path] = counts
+}
+
+// activityLines shows, in the info pane, how busy the worktree has been
+// each day of the last month, from its reflog.
+func (m *Model) activityLines(wt *models.WorktreeInfo, labelStyle, valueStyle lipgloss.Style) []string {
+	counts, ok := m.activity[wt.Path]
+	if !ok {
+		return nil
+	}
+	total, active := 0, 0
+	for _, c := range counts {
+		total += c
+		if c > 0 {
+			active++
+		}
+	}
+	label := labelStyle.Render("Activity:")
+	if total == 0 {
+		mutedStyle := lipgloss.NewStyle().Foreground(m.theme.MutedFg)
+		return []string{fmt.Sprintf("%s %s", label, mutedStyle.Render(fmt.Sprintf("none in %d days", len(counts))))}
+	}
+	sparkStyle := lipgloss.NewStyle().Foreground(m.theme.SuccessFg)
+	summary := fmt.Sprintf("%d on %d of %d days", total, active, len(counts))
+	return []string{fmt.Sprintf("%s %s %s", label, sparkStyle.Render(sparkline(counts)), valueStyle.Render(summary))}
+}
@@ -0,0 +1,51 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/chmouel/lazyworktree/internal/config"
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+func TestSparkline(t *testing.T) {
+	tests := []struct {
+		counts []int
+		want   string
+	}{
+		{[]int{0, 0, 0}, "···"},
+		{[]int{1, 0, 8, 4}, "▁·█▄"},
+		{[]int{1, 100}, "▁█"},
+		{[]int{3, 3}, "██"},
+	}
+	for _, tt := range tests {
+		if got := sparkline(tt.counts); got != tt.want {
+			t.Errorf("sparkline(%v) = %q, want %q", tt.counts, got, tt.want)
+		}
+	}
+}
+
+func TestActivityInInfoPane(t *testing.T) {
+	cfg := &config.AppConfig{WorktreeDir: t.TempDir()}
+	m := NewModel(cfg, "")
+	wt := &models.WorktreeInfo{Path: "/tmp/feature", Branch: "feature"}
+	m.worktrees = []*models.WorktreeInfo{wt}
+	m.filteredWts = m.worktrees
+	m.selectedIndex = 0
+
+	if strings.Contains(m.buildInfoContent(wt), "Activity:") {
+		t.Fatal("expected no activity line before it is loaded")
+	}
+
+	counts := make([]int, activityDays)
+	counts[0], counts[activityDays-1] = 2, 1
+	m.Update(statusUpdatedMsg{path: wt.Path, activity: counts})
+	if !strings.Contains(m.infoContent, "Activity:") || !strings.Contains(m.infoContent, "3 on 2 of 30 days") {
+		t.Fatalf("expected the activity in the info pane, got %q", m.infoContent)
+	}
+
+	m.Update(statusUpdatedMsg{path: wt.Path, activity: make([]int, activityDays)})
+	if !strings.Contains(m.infoContent, "none in 30 days") {
+		t.Fatalf("expected an idle worktree to say so, got %q", m.infoContent)
+	}
+}
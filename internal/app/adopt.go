@@ -0,0 +1,137 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+const (
+	adoptMoveID = "move"
+	adoptKeepID = "keep"
+
+	// externalWorktreeMarker flags worktrees living outside the managed directory.
+	externalWorktreeMarker = "↗"
+)
+
+// isExternalWorktree reports whether wt was created outside the managed
+// worktree directory and has not been adopted in place.
+func (m *Model) isExternalWorktree(wt *models.WorktreeInfo) bool {
+	if wt == nil || wt.IsMain || m.adoptedWorktrees[wt.Path] {
+		return false
+	}
+	rel, err := filepath.Rel(m.getRepoWorktreeDir(), wt.Path)
+	return err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// showAdoptWorktree offers to move the selected external worktree under the
+// managed directory or to keep it where it is.
+func (m *Model) showAdoptWorktree() tea.Cmd {
+	if m.readOnlyDenied("Adopting worktrees") {
+		return nil
+	}
+	if m.selectedIndex < 0 || m.selectedIndex >= len(m.filteredWts) {
+		return nil
+	}
+	wt := m.filteredWts[m.selectedIndex]
+	if wt.IsMain {
+		m.showInfo("The main worktree needs no adopting.", nil)
+		return nil
+	}
+	if !m.isExternalWorktree(wt) {
+		m.showInfo(fmt.Sprintf("%s is already managed by lazyworktree.", filepath.Base(wt.Path)), nil)
+		return nil
+	}
+
+	targetPath := filepath.Join(m.getRepoWorktreeDir(), filepath.Base(wt.Path))
+	items := []selectionItem{
+		{id: adoptMoveID, label: "Move under the worktree directory", description: targetPath},
+		{id: adoptKeepID, label: "Keep in place", description: wt.Path},
+	}
+	title := fmt.Sprintf("Adopt %s", filepath.Base(wt.Path))
+	m.listScreen = NewListSelectionScreen(items, title, "Filter...", "No options.", m.windowWidth, m.windowHeight, "", m.theme)
+	m.listSubmit = func(item selectionItem) tea.Cmd {
+		m.listScreen = nil
+		m.listSubmit = nil
+		m.currentScreen = screenNone
+		if item.id == adoptKeepID {
+			m.adoptWorktreeInPlace(wt.Path)
+			return nil
+		}
+		return m.moveWorktreeUnderRoot(wt.Path, targetPath)
+	}
+	m.currentScreen = screenListSelect
+	return textinput.Blink
+}
+
+// adoptWorktreeInPlace remembers path as a managed worktree.
+func (m *Model) adoptWorktreeInPlace(path string) {
+	m.adoptedWorktrees[path] = true
+	m.saveAdoptedWorktrees()
+	m.updateTable()
+	m.showInfo(fmt.Sprintf("%s has been adopted where it stands.", path), nil)
+}
+
+// moveWorktreeUnderRoot relocates a worktree with git worktree move.
+func (m *Model) moveWorktreeUnderRoot(oldPath, newPath string) tea.Cmd {
+	if _, err := os.Stat(newPath); err == nil {
+		m.showInfo(fmt.Sprintf("Destination already exists: %s", newPath), nil)
+		return nil
+	}
+	if err := m.ensureWorktreeDir(filepath.Dir(newPath)); err != nil {
+		return func() tea.Msg { return errMsg{err: err} }
+	}
+	return func() tea.Msg {
+		if !m.git.RunCommandChecked(m.ctx, []string{"git", "worktree", "move", oldPath, newPath}, "", fmt.Sprintf("Failed to move worktree from %s to %s", oldPath, newPath)) {
+			return errMsg{err: fmt.Errorf("failed to move %s to %s", oldPath, newPath)}
+		}
+		worktrees, err := m.git.GetWorktrees(m.ctx)
+		return worktreesLoadedMsg{
+			worktrees: worktrees,
+			err:       err,
+		}
+	}
+}
+
+func (m *Model) loadAdoptedWorktrees() {
+	repoKey := m.getRepoKey()
+	adoptedPath := filepath.Join(m.getWorktreeDir(), repoKey, models.AdoptedWorktreesFilename)
+	// #nosec G304 -- path is constructed from known safe components
+	data, err := os.ReadFile(adoptedPath)
+	if err != nil {
+		return
+	}
+	var paths []string
+	if err := json.Unmarshal(data, &paths); err != nil {
+		m.debugf("failed to parse adopted worktrees: %v", err)
+		return
+	}
+	for _, path := range paths {
+		m.adoptedWorktrees[path] = true
+	}
+}
+
+func (m *Model) saveAdoptedWorktrees() {
+	repoKey := m.getRepoKey()
+	adoptedPath := filepath.Join(m.getWorktreeDir(), repoKey, models.AdoptedWorktreesFilename)
+	if err := os.MkdirAll(filepath.Dir(adoptedPath), defaultDirPerms); err != nil {
+		m.debugf("failed to create adopted worktrees dir: %v", err)
+		return
+	}
+	paths := make([]string, 0, len(m.adoptedWorktrees))
+	for path := range m.adoptedWorktrees {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	data, _ := json.Marshal(paths)
+	if err := os.WriteFile(adoptedPath, data, defaultFilePerms); err != nil {
+		m.debugf("failed to write adopted worktrees: %v", err)
+	}
+}
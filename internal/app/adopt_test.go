@@ -0,0 +1,92 @@
+package app
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/chmouel/lazyworktree/internal/config"
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+func newAdoptTestModel(t *testing.T) *Model {
+	t.Helper()
+	cfg := &config.AppConfig{
+		WorktreeDir: t.TempDir(),
+	}
+	m := NewModel(cfg, "")
+	m.repoKey = testRepoKey
+	return m
+}
+
+func TestIsExternalWorktree(t *testing.T) {
+	m := newAdoptTestModel(t)
+	managed := &models.WorktreeInfo{Path: filepath.Join(m.getRepoWorktreeDir(), "feature"), Branch: "feature"}
+	external := &models.WorktreeInfo{Path: "/elsewhere/feature", Branch: "feature"}
+	sibling := &models.WorktreeInfo{Path: m.getRepoWorktreeDir() + "-other/feature", Branch: "feature"}
+	mainWt := &models.WorktreeInfo{Path: "/src/repo", Branch: "main", IsMain: true}
+
+	if m.isExternalWorktree(managed) {
+		t.Fatal("expected worktree under the managed directory not to be external")
+	}
+	if !m.isExternalWorktree(external) || !m.isExternalWorktree(sibling) {
+		t.Fatal("expected worktrees outside the managed directory to be external")
+	}
+	if m.isExternalWorktree(mainWt) {
+		t.Fatal("expected the main worktree never to be external")
+	}
+}
+
+func TestAdoptWorktreeInPlacePersists(t *testing.T) {
+	m := newAdoptTestModel(t)
+	external := &models.WorktreeInfo{Path: "/elsewhere/feature", Branch: "feature"}
+	m.worktrees = []*models.WorktreeInfo{external}
+	m.filteredWts = m.worktrees
+	m.selectedIndex = 0
+
+	m.showAdoptWorktree()
+	if m.currentScreen != screenListSelect || m.listSubmit == nil {
+		t.Fatalf("expected adopt options to be shown, got screen %v", m.currentScreen)
+	}
+	m.listSubmit(selectionItem{id: adoptKeepID})
+	if m.isExternalWorktree(external) {
+		t.Fatal("expected adopted worktree to be managed")
+	}
+
+	m2 := newAdoptTestModel(t)
+	m2.config.WorktreeDir = m.config.WorktreeDir
+	m2.loadAdoptedWorktrees()
+	if m2.isExternalWorktree(external) {
+		t.Fatal("expected adoption to survive a reload")
+	}
+}
+
+func TestShowAdoptWorktreeManaged(t *testing.T) {
+	m := newAdoptTestModel(t)
+	m.worktrees = []*models.WorktreeInfo{{Path: filepath.Join(m.getRepoWorktreeDir(), "feature"), Branch: "feature"}}
+	m.filteredWts = m.worktrees
+	m.selectedIndex = 0
+
+	m.showAdoptWorktree()
+	if m.currentScreen != screenInfo || !strings.Contains(m.infoScreen.message, "already managed") {
+		t.Fatalf("expected an info message for a managed worktree, got screen %v", m.currentScreen)
+	}
+}
+
+func TestPruneMergedLeavesExternalUnchecked(t *testing.T) {
+	m := newAdoptTestModel(t)
+	m.worktrees = []*models.WorktreeInfo{
+		{Path: "/src/repo", Branch: "main", IsMain: true},
+		{Path: "/elsewhere/feature", Branch: "feature", PR: &models.PRInfo{Number: 1, State: "MERGED"}},
+	}
+	m.filteredWts = m.worktrees
+
+	m.performMergedWorktreeCheck()
+	if m.checklistScreen == nil || len(m.checklistScreen.items) != 1 {
+		t.Fatal("expected the external merged worktree to be listed")
+	}
+	item := m.checklistScreen.items[0]
+	if item.Checked || !strings.Contains(item.Description, "outside the worktree directory") {
+		t.Fatalf("expected external worktree to be unchecked and flagged, got %+v", item)
+	}
+}
@@ -11,8 +11,10 @@ import (
 	"path/filepath"
 	"runtime"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
+	"text/template"
 	"time"
 
 	"github.com/charmbracelet/bubbles/spinner"
@@ -21,13 +23,19 @@ import (
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/chmouel/lazyworktree/internal/codeowners"
 	"github.com/chmouel/lazyworktree/internal/config"
+	"github.com/chmouel/lazyworktree/internal/diagnostics"
+	"github.com/chmouel/lazyworktree/internal/events"
 	"github.com/chmouel/lazyworktree/internal/git"
 	log "github.com/chmouel/lazyworktree/internal/log"
 	"github.com/chmouel/lazyworktree/internal/models"
+	"github.com/chmouel/lazyworktree/internal/policy"
 	"github.com/chmouel/lazyworktree/internal/security"
+	"github.com/chmouel/lazyworktree/internal/services"
 	"github.com/chmouel/lazyworktree/internal/theme"
 	"github.com/chmouel/lazyworktree/internal/utils"
+	"github.com/chmouel/lazyworktree/internal/workspace"
 	"github.com/fsnotify/fsnotify"
 )
 
@@ -50,6 +58,7 @@ const (
 	detailsCacheTTL  = 2 * time.Second
 	debounceDelay    = 200 * time.Millisecond
 	ciCacheTTL       = 30 * time.Second
+	closeWaitTimeout = 2 * time.Second
 	defaultDirPerms  = utils.DefaultDirPerms
 	defaultFilePerms = 0o600
 
@@ -58,6 +67,7 @@ const (
 
 	// Visual symbols for enhanced UI
 	symbolFilledCircle = "●"
+	symbolDraftCircle  = "◌"
 
 	searchFiles = "Search files..."
 
@@ -69,15 +79,25 @@ const (
 )
 
 type (
-	errMsg             struct{ err error }
+	errMsg struct {
+		err   error
+		retry tea.Cmd // Set by withRetry, for the error screen
+	}
 	worktreesLoadedMsg struct {
 		worktrees []*models.WorktreeInfo
 		err       error
+		// refreshGen identifies the refresh that produced the message; zero
+		// for reloads following an operation, which always apply.
+		refreshGen uint64
+		// background marks a timed rescan, which neither dismisses a
+		// loading screen nor reports its errors.
+		background bool
 	}
 	prDataLoadedMsg struct {
 		prMap          map[string]*models.PRInfo
 		worktreePRs    map[string]*models.PRInfo // keyed by worktree path
 		worktreeErrors map[string]string         // keyed by worktree path, stores error messages
+		lookups        map[string]*prLookupEntry // per-worktree fallback results to cache, keyed by path
 		err            error
 	}
 	statusUpdatedMsg struct {
@@ -85,6 +105,11 @@ type (
 		statusFiles []StatusFile
 		log         []commitLogEntry
 		path        string
+		extras      *infoExtras
+		changed     []string // files changed since the main branch, when needed
+		activity    []int    // reflog entries per day, oldest first
+		remote      string   // the upstream remote, when its URL was looked up
+		remoteURL   string
 	}
 	refreshCompleteMsg      struct{}
 	fetchRemotesCompleteMsg struct{}
@@ -141,11 +166,53 @@ type (
 	pushResultMsg struct {
 		output string
 		err    error
+		retry  *credentialRetry
 	}
 	syncResultMsg struct {
 		stage  string
 		output string
 		err    error
+		retry  *credentialRetry
+	}
+	prUpdatedMsg struct {
+		path      string
+		number    int
+		draft     *bool
+		reviewers []string
+		err       error
+	}
+	changelogReadyMsg struct {
+		path   string
+		branch string
+		base   string
+		text   string
+	}
+	previewLoadedMsg struct {
+		path     string
+		content  string
+		markdown bool
+		err      error
+	}
+	deploymentsLoadedMsg struct {
+		deployments map[string]*models.DeploymentInfo
+	}
+	prSyncLoadedMsg struct {
+		prs       []*models.PRInfo
+		branchPRs map[string]*models.PRInfo
+		err       error
+	}
+	prSyncResultMsg struct {
+		created     []prSyncWorktree
+		failures    []string
+		pruned      int
+		pruneFailed int
+	}
+	prSyncInitMsg struct {
+		pending []prSyncWorktree
+	}
+	reviewerCandidatesMsg struct {
+		path       string
+		candidates []string
 	}
 	createFromPRResultMsg struct {
 		prNumber   int
@@ -209,6 +276,7 @@ type (
 
 type commitLogEntry struct {
 	sha            string
+	date           time.Time
 	authorInitials string
 	message        string
 	isUnpushed     bool
@@ -258,6 +326,7 @@ const (
 	sortModePath         = 0 // Sort by path (alphabetical)
 	sortModeLastActive   = 1 // Sort by last commit date
 	sortModeLastSwitched = 2 // Sort by last UI access time
+	sortModeManual       = 3 // Sort in the order arranged with J and K
 )
 
 type searchTarget int
@@ -280,7 +349,7 @@ const (
 type Model struct {
 	// Configuration
 	config *config.AppConfig
-	git    *git.Service
+	git    GitService
 	theme  *theme.Theme
 
 	// UI Components
@@ -289,6 +358,13 @@ type Model struct {
 	logTable       table.Model
 	filterInput    textinput.Model
 
+	// build describes the running binary for the about screen.
+	build diagnostics.Build
+
+	// Rendering
+	renders           renderCaches
+	statusViewportSet string
+
 	// State
 	worktrees                 []*models.WorktreeInfo
 	filteredWts               []*models.WorktreeInfo
@@ -299,18 +375,50 @@ type Model struct {
 	worktreeSearchQuery       string
 	statusSearchQuery         string
 	logSearchQuery            string
-	sortMode                  int // sortModePath, sortModeLastActive, or sortModeLastSwitched
+	sortMode                  int  // sortModePath, sortModeLastActive, sortModeLastSwitched, or sortModeManual
+	sortReversed              bool // Names descending, or dates oldest first
+	worktreeOrder             worktreeOrder
+	motionCount               int  // Count prefix being typed with vim_motions
+	motionPendingG            bool // g pressed with vim_motions, waiting for the second g
+	absoluteDates             bool // Dates shown with the date_format layout rather than relative to now
 	prDataLoaded              bool
 	checkMergedAfterPRRefresh bool             // Flag to trigger merged check after PR data refresh
 	accessHistory             map[string]int64 // worktree path -> last access timestamp
+	adoptedWorktrees          map[string]bool  // external worktree paths adopted in place
+	instanceFile              string           // this process's entry under the instances dir
+	navHistory                []string         // visited worktree paths for back/forward
+	navHistoryPos             int              // index of the current entry in navHistory
+	navHistoryDirty           bool             // navHistory changed since it was last saved
+	navDwellSeq               int              // latest selection waiting to join navHistory
+	prLookupCache             map[string]*prLookupEntry
+	deployments               map[string]*models.DeploymentInfo
+	infoExtras                map[string]*infoExtras // worktree path -> details gathered for info_template
+	infoTemplate              *template.Template     // parsed info_template, nil when unset
+	infoTemplateErr           error                  // parse error of the configured info_template
+	previewMode               bool                   // status area shows the README/overview preview
+	previewPath               string                 // worktree the loaded preview belongs to
+	previewRaw                string
+	previewMarkdown           bool
+	previewRendered           string // previewRaw rendered for previewWidth
+	previewWidth              int
 	repoKey                   string
 	repoKeyOnce               sync.Once
+	refreshGen                uint64             // generation of the latest worktree refresh
+	refreshCancel             context.CancelFunc // cancels the in-flight worktree refresh
+	refreshWG                 sync.WaitGroup     // in-flight refreshes Close waits for
+	refreshScheduled          bool               // a coalesced refresh is waiting to start
+	refreshRunning            bool               // a coordinated refresh is scanning worktrees
+	refreshQueued             bool               // another refresh was asked for mid-scan
 	currentScreen             screenType
 	currentDetailsPath        string
 	helpScreen                *HelpScreen
 	trustScreen               *TrustScreen
 	inputScreen               *InputScreen
 	inputSubmit               func(string, bool) (tea.Cmd, bool)
+	branchNameStream          *branchNameStream // suggestion streaming into the branch name input
+	branchNameStreamID        int
+	prPipeline                *prPipeline // p fetching PR and CI data in the background
+	prPipelineID              int
 	commitScreen              *CommitScreen
 	welcomeScreen             *WelcomeScreen
 	paletteScreen             *CommandPaletteScreen
@@ -349,11 +457,96 @@ type Model struct {
 	// Cache
 	cache           map[string]any
 	divergenceCache map[string]string
+	remoteURLs      map[string]string // remote name -> fetch URL, for the info pane
 	notifiedErrors  map[string]bool
 	ciCache         map[string]*ciCacheEntry // branch -> CI checks cache
 	detailsCache    map[string]*detailsCacheEntry
+	ciAnnotations   map[string]*ciAnnotationsEntry // branch -> CI findings of failing checks
 	worktreesLoaded bool
 
+	// Worktree quotas and naming rules
+	policy             *policy.Policy
+	diskUsage          int64     // Size of the repository's worktree directory
+	diskUsageAt        time.Time // When diskUsage was measured; zero if never
+	diskUsageWorktrees int       // Worktree count when diskUsage was measured
+	measuringDiskUsage bool
+
+	// Scheduled maintenance
+	maintenanceStarted bool
+	maintenanceRunning bool
+
+	// Partial clone (blob filters)
+	partialClone        git.PartialClone
+	partialCloneChecked bool
+
+	// Jujutsu or git-branchless managing the repository alongside git
+	colocated git.Colocated
+	changeIDs map[string]string // commit -> Jujutsu change ID
+
+	// Init command output streamed into the status pane, by worktree path
+	initOutputs       map[string]*initOutput
+	initOutputsMu     sync.Mutex
+	initOutputEvents  chan struct{}
+	initOutputWaiting bool
+
+	// Services of the .wt running per worktree
+	runningServices  map[string]services.List // worktree path -> services
+	servicePorts     map[int][]int            // service PID -> listening ports
+	serviceListeners []services.Listener      // processes on the .wt service_ports
+
+	// Docker Compose project status, by project name
+	composeStatuses map[string]string
+
+	// Whether direnv and mise trust each worktree: path -> tool -> state
+	envToolStates map[string]map[string]string
+
+	// Branch stacks: branch -> the branch it is stacked on
+	stackLinks map[string]git.StackLink
+
+	// Branch suggestions for a repository with only its main worktree
+	suggestionsChecked bool
+
+	// Monorepo workspace projects
+	workspaceProjects []workspace.Project
+	workspaceChecked  bool
+	projectTouches    map[string][]string // worktree path -> touched project dirs
+	activity          map[string][]int    // worktree path -> reflog entries per day
+	projectFilter     string
+
+	// Focus mode: only the family of this branch is listed
+	focusBranch string
+
+	// Only the current user's worktrees are listed
+	ownerFilter bool
+
+	// Macro being recorded, and the keys left of the one running
+	macroRecording bool
+	macroTyping    bool // The last step is text still being typed
+	macroKeys      []string
+	macroPaletteAt int // Steps recorded before the palette was last opened
+	macroQueue     []string
+	macroRunning   string
+
+	// Size of each worktree's changes, by path, for the ±Lines column
+	diffStats map[string]worktreeDiffStat
+
+	// Step of the demo's guided tour, while it is running
+	demoRunning bool
+	demoStep    int
+
+	// CODEOWNERS of the main worktree
+	codeOwners        *codeowners.File
+	codeOwnersChecked bool
+	codeOwnership     map[string]codeowners.Summary // worktree path -> owners of its changes
+
+	// Health matrix
+	healthResults healthResults
+	healthRunning map[string]bool // healthKey -> check in progress
+
+	// Event stream (--events)
+	events             *events.Stream
+	lastEventSelection string
+
 	// Create from current state
 	createFromCurrentDiff       string // Cached diff for AI script
 	createFromCurrentRandomName string // Random branch name
@@ -384,6 +577,20 @@ type Model struct {
 	gitWatcher         *fsnotify.Watcher
 	gitLastRefresh     time.Time
 
+	// Worktree watches, guarded by gitWatchMu
+	gitWatchWorktrees map[string][]string // worktree → its watched directories
+	gitWatchOwners    map[string]string   // watched directory → its worktree
+	gitWatchGitDirs   map[string]string   // git directory → its worktree
+	gitWatchFull      bool                // an event calls for a full rescan
+	gitWatchChanged   map[string]struct{} // worktrees touched by events
+
+	worktreeWatchPending  map[string]struct{}
+	worktreeWatchFlushing bool
+
+	// Observe mode
+	observeFeed   []observeEvent // newest first
+	observeLatest string         // path of the worktree that changed last
+
 	// Post-refresh selection (e.g. after creating worktree)
 	pendingSelectWorktreePath string
 
@@ -403,6 +610,7 @@ type Model struct {
 	pendingCmdCwd           string
 	pendingAfter            func() tea.Msg
 	pendingTrust            string
+	pendingTrustAction      func() tea.Cmd           // run once trusted instead of pendingCommands
 	pendingCustomBranchName string                   // Branch name from custom create command
 	pendingCustomBaseRef    string                   // Base ref for custom create (selected before running command)
 	pendingCustomMenu       *config.CustomCreateMenu // Menu item for custom create
@@ -414,6 +622,16 @@ type Model struct {
 	// Commit files screen for browsing files in a commit
 	commitFilesScreen *CommitFilesScreen
 
+	// Markdown viewer for PR/issue descriptions
+	markdownScreen *MarkdownScreen
+	healthScreen   *HealthScreen
+	whichKeyScreen *WhichKeyScreen
+	errorScreen    *ErrorScreen
+
+	// git.Service error notifications on their way to the error screen
+	notifyErrors  chan notifyErrorMsg
+	errorsPending bool // An action failed while another screen was open
+
 	// Command history for ! command
 	commandHistory []string
 
@@ -436,6 +654,12 @@ type Model struct {
 // NewModel creates a new application model with the given configuration.
 // initialFilter is an optional filter string to apply on startup.
 func NewModel(cfg *config.AppConfig, initialFilter string) *Model {
+	return NewModelWithGit(cfg, initialFilter, nil)
+}
+
+// NewModelWithGit creates the model around gitSvc, or around a git service
+// set up from cfg when gitSvc is nil.
+func NewModelWithGit(cfg *config.AppConfig, initialFilter string, gitSvc GitService) *Model {
 	ctx, cancel := context.WithCancel(context.Background())
 
 	// Load theme
@@ -446,8 +670,10 @@ func NewModel(cfg *config.AppConfig, initialFilter string) *Model {
 
 	log.Printf("debug logging enabled")
 
+	notifyErrors := make(chan notifyErrorMsg, notifyErrorBuffer)
 	notify := func(message string, severity string) {
 		log.Printf("[%s] %s", severity, message)
+		routeNotifyError(notifyErrors, message, severity, false)
 	}
 	notifyOnce := func(key string, message string, severity string) {
 		debugMu.Lock()
@@ -457,11 +683,14 @@ func NewModel(cfg *config.AppConfig, initialFilter string) *Model {
 		}
 		debugNotified[key] = true
 		log.Printf("[%s] %s", severity, message)
+		// Deduplicated notifications come from background commands, which
+		// wait for E rather than interrupt.
+		routeNotifyError(notifyErrors, message, severity, true)
 	}
 
-	gitService := git.NewService(notify, notifyOnce)
-	gitService.SetGitPager(cfg.GitPager)
-	gitService.SetGitPagerArgs(cfg.GitPagerArgs)
+	if gitSvc == nil {
+		gitSvc = newGitService(cfg, notify, notifyOnce)
+	}
 	trustManager := security.NewTrustManager()
 
 	columns := []table.Column{
@@ -523,39 +752,49 @@ func NewModel(cfg *config.AppConfig, initialFilter string) *Model {
 		sortMode = sortModeLastActive
 	case "switched":
 		sortMode = sortModeLastSwitched
+	case "manual":
+		sortMode = sortModeManual
 	}
 
 	m := &Model{
-		config:          cfg,
-		git:             gitService,
-		theme:           thm,
-		worktreeTable:   t,
-		statusViewport:  statusVp,
-		logTable:        logT,
-		filterInput:     filterInput,
-		worktrees:       []*models.WorktreeInfo{},
-		filteredWts:     []*models.WorktreeInfo{},
-		sortMode:        sortMode,
-		filterQuery:     initialFilter,
-		filterTarget:    filterTargetWorktrees,
-		searchTarget:    searchTargetWorktrees,
-		cache:           make(map[string]any),
-		divergenceCache: make(map[string]string),
-		notifiedErrors:  make(map[string]bool),
-		ciCache:         make(map[string]*ciCacheEntry),
-		detailsCache:    make(map[string]*detailsCacheEntry),
-		accessHistory:   make(map[string]int64),
-		trustManager:    trustManager,
-		ctx:             ctx,
-		cancel:          cancel,
-		focusedPane:     0,
-		zoomedPane:      -1,
-		infoContent:     errNoWorktreeSelected,
-		statusContent:   "Loading...",
-		spinner:         sp,
-		loading:         true,
-		commandRunner:   exec.Command,
-		execProcess:     tea.ExecProcess,
+		config:           cfg,
+		git:              gitSvc,
+		theme:            thm,
+		worktreeTable:    t,
+		statusViewport:   statusVp,
+		logTable:         logT,
+		filterInput:      filterInput,
+		worktrees:        []*models.WorktreeInfo{},
+		filteredWts:      []*models.WorktreeInfo{},
+		sortMode:         sortMode,
+		absoluteDates:    usesAbsoluteDates(cfg.DateFormat),
+		filterQuery:      initialFilter,
+		filterTarget:     filterTargetWorktrees,
+		searchTarget:     searchTargetWorktrees,
+		cache:            make(map[string]any),
+		divergenceCache:  make(map[string]string),
+		remoteURLs:       make(map[string]string),
+		notifiedErrors:   make(map[string]bool),
+		ciCache:          make(map[string]*ciCacheEntry),
+		prLookupCache:    make(map[string]*prLookupEntry),
+		detailsCache:     make(map[string]*detailsCacheEntry),
+		accessHistory:    make(map[string]int64),
+		initOutputs:      make(map[string]*initOutput),
+		initOutputEvents: make(chan struct{}, 1),
+		notifyErrors:     notifyErrors,
+		adoptedWorktrees: make(map[string]bool),
+		navHistoryPos:    -1,
+		trustManager:     trustManager,
+		ctx:              ctx,
+		cancel:           cancel,
+		focusedPane:      0,
+		zoomedPane:       -1,
+		infoContent:      errNoWorktreeSelected,
+		statusContent:    "Loading...",
+		spinner:          sp,
+		loading:          true,
+		commandRunner:    exec.Command,
+		execProcess:      tea.ExecProcess,
 		startCommand: func(cmd *exec.Cmd) error {
 			return cmd.Start()
 		},
@@ -571,19 +810,60 @@ func NewModel(cfg *config.AppConfig, initialFilter string) *Model {
 		m.setFilterTarget(filterTargetWorktrees)
 		m.filterInput.Focus()
 	}
+	m.loadInfoTemplate()
 
 	return m
 }
 
+// newGitService sets the git service up from the configuration.
+func newGitService(cfg *config.AppConfig, notify func(string, string), notifyOnce func(string, string, string)) *git.Service {
+	gitService := git.NewService(notify, notifyOnce)
+	gitPager := cfg.GitPager
+	if cfg.NoColor {
+		// Diff formatters such as delta colour their output.
+		gitPager = ""
+	}
+	gitService.SetGitPager(gitPager)
+	gitService.SetGitPagerArgs(cfg.GitPagerArgs)
+	gitService.SetForgeTokens(cfg.GitHubToken, cfg.GitLabToken)
+	gitService.SetDivergenceRef(cfg.DivergenceRef)
+	gitService.SetFetchOptions(git.FetchOptions{
+		ProtocolV2:         cfg.FetchProtocolV2,
+		NegotiateWorktrees: cfg.FetchNegotiateWorktrees,
+		WorktreesOnly:      cfg.FetchWorktreesOnly,
+		Prune:              cfg.FetchPrune,
+		Depth:              cfg.FetchDepth,
+		Filter:             cfg.FetchFilter,
+	})
+	gitService.SetRemote(cfg.SSHHost, cfg.SSHPath)
+	if cfg.SafeMode {
+		gitService.DisableForge()
+	}
+	gitService.SetPoolLimits(git.PoolLimits{
+		Local:    cfg.GitConcurrency,
+		Network:  cfg.NetworkConcurrency,
+		Commands: cfg.CommandConcurrency,
+	})
+	return gitService
+}
+
 // Init satisfies the tea.Model interface and starts with no command.
 func (m *Model) Init() tea.Cmd {
 	m.loadCommandHistory()
 	m.loadAccessHistory()
+	m.loadAdoptedWorktrees()
+	m.registerInstance()
+	m.loadNavHistory()
+	m.loadWorktreeOrder()
 	m.loadPaletteHistory()
 	cmds := []tea.Cmd{
 		m.loadCache(),
-		m.refreshWorktrees(),
-		m.spinner.Tick,
+		m.startRefresh(),
+		m.waitForInitOutput(),
+		m.waitForNotifyError(),
+	}
+	if m.animationsEnabled() {
+		cmds = append(cmds, m.spinner.Tick)
 	}
 	if m.showingFilter {
 		cmds = append(cmds, textinput.Blink)
@@ -596,6 +876,7 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 	var cmds []tea.Cmd
 
+	m.showPendingErrors()
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		m.debugf("window: %dx%d", msg.Width, msg.Height)
@@ -614,10 +895,21 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case tea.KeyMsg:
 		m.debugf("key: %s screen=%s focus=%d filter=%t", msg.String(), screenName(m.currentScreen), m.focusedPane, m.showingFilter)
-		if m.currentScreen != screenNone {
-			return m.handleScreenKey(msg)
+		if len(m.macroQueue) > 0 {
+			m.stopMacro()
+			return m, nil
 		}
-		return m.handleKeyMsg(msg)
+		m.recordMacroKey(msg)
+		m.advanceDemoTour(msg)
+		model, cmd := m.dispatchKey(msg)
+		return model, withRetry(cmd)
+
+	case macroStepMsg:
+		return m, m.handleMacroStep()
+
+	case navDwellMsg:
+		m.handleNavDwell(msg)
+		return m, nil
 
 	case worktreesLoadedMsg, cachedWorktreesMsg, pruneResultMsg, absorbMergeResultMsg:
 		return m.handleWorktreeMessages(msg)
@@ -656,12 +948,11 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 		env := m.buildCommandEnv(msg.branch, msg.targetPath)
-		initCmds := m.collectInitCommands()
 		after := func() tea.Msg {
 			worktrees, err := m.git.GetWorktrees(m.ctx)
 			return worktreesLoadedMsg{worktrees: worktrees, err: err}
 		}
-		return m, m.runCommandsWithTrust(initCmds, msg.targetPath, env, after)
+		return m, m.runInitCommands(msg.targetPath, env, after)
 
 	case createFromIssueResultMsg:
 		m.loading = false
@@ -675,12 +966,11 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 		env := m.buildCommandEnv(msg.branch, msg.targetPath)
-		initCmds := m.collectInitCommands()
 		after := func() tea.Msg {
 			worktrees, err := m.git.GetWorktrees(m.ctx)
 			return worktreesLoadedMsg{worktrees: worktrees, err: err}
 		}
-		return m, m.runCommandsWithTrust(initCmds, msg.targetPath, env, after)
+		return m, m.runInitCommands(msg.targetPath, env, after)
 
 	case customCreateResultMsg:
 		m.loading = false
@@ -788,8 +1078,27 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			m.setLogEntries(msg.log, reset)
 		}
+		if msg.extras != nil {
+			if m.infoExtras == nil {
+				m.infoExtras = make(map[string]*infoExtras)
+			}
+			m.infoExtras[msg.path] = msg.extras
+		}
+		if msg.changed != nil {
+			m.setProjectTouches(msg.path, m.projectDirs(msg.changed))
+			m.setCodeOwnership(msg.path, msg.changed)
+		}
+		m.setActivity(msg.path, msg.activity)
+		if msg.remote != "" {
+			m.remoteURLs[msg.remote] = msg.remoteURL
+		}
+		if m.config.CommitLint || msg.extras != nil || msg.changed != nil || msg.activity != nil || msg.remote != "" {
+			if wt := m.selectedWorktree(); wt != nil && wt.Path == msg.path {
+				m.infoContent = m.buildInfoContent(wt)
+			}
+		}
 		// Trigger CI fetch if worktree has a PR and cache is stale
-		return m, m.maybeFetchCIStatus()
+		return m, tea.Batch(m.maybeFetchCIStatus(), m.maybeFetchCIAnnotations())
 
 	case debouncedDetailsMsg:
 		// Only update if the index matches and is still valid
@@ -801,10 +1110,18 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case errMsg:
 		if msg.err != nil {
-			m.showInfo(fmt.Sprintf("Error: %v", msg.err), nil)
+			m.showError(errorFromErr(msg.err, msg.retry))
 		}
 		return m, nil
 
+	case notifyErrorMsg:
+		index := m.queueError(msg.err)
+		if !msg.background {
+			m.errorScreen.index = index
+			m.errorsPending = true
+		}
+		return m, m.waitForNotifyError()
+
 	case tmuxSessionReadyMsg:
 		if msg.attach {
 			return m, m.attachTmuxSessionCmd(msg.sessionName, msg.insideTmux)
@@ -823,7 +1140,142 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case refreshCompleteMsg:
-		return m, m.updateDetailsView()
+		return m, m.refreshSelectedWorktree()
+
+	case worktreeRefreshedMsg:
+		m.handleWorktreeRefreshed(msg)
+		return m, nil
+
+	case branchFetchedMsg:
+		return m, m.handleBranchFetched(msg)
+
+	case controlRequestMsg:
+		return m, m.handleControlRequest(msg)
+
+	case snapshotDoneMsg:
+		return m, m.handleSnapshotDone(msg)
+
+	case healthCheckDoneMsg:
+		m.handleHealthCheckDone(msg)
+		return m, nil
+
+	case deletedRecreatedMsg:
+		return m, m.handleDeletedRecreated(msg)
+
+	case branchSuggestionsMsg:
+		return m, m.handleBranchSuggestions(msg)
+
+	case initOutputMsg:
+		return m, m.handleInitOutput()
+
+	case servicesLoadedMsg:
+		m.handleServicesLoaded(msg)
+		return m, nil
+
+	case diffStatsMsg:
+		return m, m.handleDiffStats(msg)
+
+	case serviceDoneMsg:
+		return m, m.handleServiceDone(msg)
+
+	case servicePortsMsg:
+		return m, m.handleServicePorts(msg)
+
+	case composeStatusMsg:
+		m.handleComposeStatus(msg)
+		return m, nil
+
+	case composeDoneMsg:
+		return m, m.handleComposeDone(msg)
+
+	case envToolsMsg:
+		m.handleEnvTools(msg)
+		return m, nil
+
+	case envToolStatesMsg:
+		m.handleEnvToolStates(msg)
+		return m, nil
+
+	case stackLinksMsg:
+		m.handleStackLinks(msg)
+		return m, nil
+
+	case restackDoneMsg:
+		return m, m.handleRestackDone(msg)
+
+	case bootstrapOfferMsg:
+		return m, m.handleBootstrapOffer(msg)
+
+	case artifactsSyncedMsg:
+		return m, m.handleArtifactsSynced(msg)
+
+	case ciAnnotationsLoadedMsg:
+		return m, m.handleCIAnnotationsLoaded(msg)
+
+	case branchNameStreamMsg:
+		return m, m.handleBranchNameStream(msg)
+
+	case prPipelineMsg:
+		return m, m.handlePRPipeline(msg)
+
+	case remoteURLSetMsg:
+		return m, m.handleRemoteURLSet(msg)
+
+	case clipboardCreateMsg:
+		return m, m.handleClipboardCreate(msg)
+
+	case autoStashDoneMsg:
+		return m, m.handleAutoStashDone(msg)
+
+	case autoStashFoundMsg:
+		return m, m.handleAutoStashFound(msg)
+
+	case autoStashPoppedMsg:
+		return m, m.handleAutoStashPopped(msg)
+
+	case codeOwnersMsg:
+		return m, m.handleCodeOwners(msg)
+
+	case codeOwnersSummaryMsg:
+		m.handleCodeOwnersSummary(msg)
+		return m, nil
+
+	case workspaceProjectsMsg:
+		return m, m.handleWorkspaceProjects(msg)
+
+	case projectTouchesMsg:
+		return m, m.handleProjectTouches(msg)
+
+	case partialCloneMsg:
+		m.partialClone = msg.info
+		return m, nil
+
+	case colocatedMsg:
+		m.handleColocated(msg)
+		return m, nil
+
+	case pushScanMsg:
+		return m, m.handlePushScan(msg)
+
+	case whitespaceFixedMsg:
+		return m, m.handleWhitespaceFixed(msg)
+
+	case prefetchDoneMsg:
+		m.handlePrefetchDone(msg)
+		return m, nil
+
+	case sparseCheckoutMsg:
+		return m, m.handleSparseCheckout(msg)
+
+	case maintenanceTickMsg:
+		return m, tea.Batch(m.runDueMaintenance(), m.maintenanceTick())
+
+	case maintenanceDoneMsg:
+		return m, m.handleMaintenanceDone(msg)
+
+	case diskUsageMsg:
+		m.handleDiskUsage(msg)
+		return m, nil
 
 	case fetchRemotesCompleteMsg:
 		m.statusContent = "Remotes fetched"
@@ -831,7 +1283,37 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if m.loadingScreen != nil {
 			m.loadingScreen.message = loadingRefreshWorktrees
 		}
-		return m, m.refreshWorktrees()
+		return m, m.requestRefresh()
+
+	case prUpdatedMsg:
+		return m, m.handlePRUpdated(msg)
+
+	case reviewerCandidatesMsg:
+		return m, m.handleReviewerCandidates(msg)
+
+	case changelogReadyMsg:
+		return m, m.handleChangelogReady(msg)
+
+	case aboutReadyMsg:
+		m.handleAboutReady(msg)
+		return m, nil
+
+	case previewLoadedMsg:
+		return m, m.handlePreviewLoaded(msg)
+
+	case deploymentsLoadedMsg:
+		m.deployments = msg.deployments
+		m.updateTable()
+		return m, m.updateDetailsView()
+
+	case prSyncLoadedMsg:
+		return m, m.handlePRSyncLoaded(msg)
+
+	case prSyncResultMsg:
+		return m, m.handlePRSyncResult(msg)
+
+	case prSyncInitMsg:
+		return m, m.runPRSyncInit(msg.pending)
 
 	case pushResultMsg:
 		m.loading = false
@@ -841,6 +1323,9 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.loadingScreen = nil
 		}
 		output := strings.TrimSpace(msg.output)
+		if msg.err != nil && msg.retry != nil {
+			return m, m.showCredentialRetry("Push failed.", output, msg.retry)
+		}
 		if msg.err != nil {
 			message := fmt.Sprintf("Push failed: %v", msg.err)
 			if output != "" {
@@ -851,11 +1336,14 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		if output != "" {
 			message := fmt.Sprintf("Push completed.\n\n%s", truncateToHeight(output, 3))
-			m.showInfo(message, m.updateDetailsView())
+			m.showInfo(message, m.refreshSelectedWorktree())
 			return m, nil
 		}
 		m.statusContent = "Push completed"
-		return m, m.updateDetailsView()
+		return m, m.refreshSelectedWorktree()
+
+	case gitStreamResultMsg:
+		return m, m.handleGitStreamResult(msg)
 
 	case syncResultMsg:
 		m.loading = false
@@ -873,6 +1361,9 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			case "push":
 				heading = "Push failed."
 			}
+			if msg.retry != nil {
+				return m, m.showCredentialRetry(heading, output, msg.retry)
+			}
 			message := fmt.Sprintf("%s: %v", heading, msg.err)
 			if output != "" {
 				message = fmt.Sprintf("%s\n\n%s", heading, truncateToHeightFromEnd(output, 5))
@@ -882,11 +1373,11 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		if output != "" {
 			message := fmt.Sprintf("Synchronised.\n\n%s", truncateToHeight(output, 3))
-			m.showInfo(message, m.updateDetailsView())
+			m.showInfo(message, m.refreshSelectedWorktree())
 			return m, nil
 		}
 		m.statusContent = "Synchronised"
-		return m, m.updateDetailsView()
+		return m, m.refreshSelectedWorktree()
 
 	case autoRefreshTickMsg:
 		if cmd := m.autoRefreshTick(); cmd != nil {
@@ -895,16 +1386,34 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if cmd := m.refreshDetails(); cmd != nil {
 			cmds = append(cmds, cmd)
 		}
+		if cmd := m.backgroundRefresh(); cmd != nil {
+			cmds = append(cmds, cmd)
+		}
 		return m, tea.Batch(cmds...)
 
+	case refreshDueMsg:
+		m.refreshScheduled = false
+		if m.refreshRunning {
+			m.refreshQueued = true
+			return m, nil
+		}
+		return m, m.startRefresh()
+
 	case gitDirChangedMsg:
 		m.gitWatchWaiting = false
 		cmds = append(cmds, m.waitForGitWatchEvent())
-		if m.shouldRefreshGitEvent(time.Now()) {
-			cmds = append(cmds, m.refreshWorktrees())
+		full, changed := m.takeGitWatchChanges()
+		if (full || len(changed) == 0) && m.shouldRefreshGitEvent(time.Now()) {
+			cmds = append(cmds, m.requestRefresh())
+		}
+		if cmd := m.queueWorktreeWatchRefresh(changed); cmd != nil {
+			cmds = append(cmds, cmd)
 		}
 		return m, tea.Batch(cmds...)
 
+	case worktreeWatchFlushMsg:
+		return m, m.flushWorktreeWatch()
+
 	case cherryPickResultMsg:
 		return m, m.handleCherryPickResult(msg)
 
@@ -965,6 +1474,14 @@ func screenName(screen screenType) string {
 		return "commit-files"
 	case screenChecklist:
 		return "checklist"
+	case screenMarkdown:
+		return "markdown"
+	case screenHealth:
+		return "health"
+	case screenWhichKey:
+		return "which-key"
+	case screenError:
+		return "error"
 	default:
 		return "unknown"
 	}
@@ -1037,7 +1554,7 @@ func (m *Model) setFilterQuery(target filterTarget, query string) {
 func (m *Model) hasActiveFilterForPane(paneIndex int) bool {
 	switch paneIndex {
 	case 0:
-		return strings.TrimSpace(m.filterQuery) != ""
+		return strings.TrimSpace(m.filterQuery) != "" || m.projectFilter != "" || m.focusBranch != "" || m.ownerFilter
 	case 1:
 		return strings.TrimSpace(m.statusFilterQuery) != ""
 	case 2:
@@ -1114,11 +1631,22 @@ func (m *Model) updateTable() {
 	query := strings.ToLower(strings.TrimSpace(m.filterQuery))
 	m.filteredWts = []*models.WorktreeInfo{}
 
-	if query == "" {
+	if query == "" && m.projectFilter == "" && m.focusBranch == "" && !m.ownerFilter {
 		m.filteredWts = m.worktrees
 	} else {
 		hasPathSep := strings.Contains(query, "/")
+		family := m.focusFamily()
 		for _, wt := range m.worktrees {
+			if !m.worktreeTouchesProject(wt) || (family != nil && !family[wt.Branch]) {
+				continue
+			}
+			if m.ownerFilter && !wt.IsMain && m.ownedByOther(wt) {
+				continue
+			}
+			if query == "" {
+				m.filteredWts = append(m.filteredWts, wt)
+				continue
+			}
 			name := filepath.Base(wt.Path)
 			if wt.IsMain {
 				name = mainWorktreeName
@@ -1137,29 +1665,26 @@ func (m *Model) updateTable() {
 	}
 
 	// Sort based on current sort mode
-	switch m.sortMode {
-	case sortModeLastActive:
-		sort.Slice(m.filteredWts, func(i, j int) bool {
-			return m.filteredWts[i].LastActiveTS > m.filteredWts[j].LastActiveTS
-		})
-	case sortModeLastSwitched:
-		sort.Slice(m.filteredWts, func(i, j int) bool {
-			return m.filteredWts[i].LastSwitchedTS > m.filteredWts[j].LastSwitchedTS
-		})
-	default: // sortModePath
-		sort.Slice(m.filteredWts, func(i, j int) bool {
-			return m.filteredWts[i].Path < m.filteredWts[j].Path
-		})
-	}
+	m.sortWorktrees(m.filteredWts)
+
+	// Stacked branches follow their parent
+	var stackDepths map[string]int
+	m.filteredWts, stackDepths = m.orderByStack(m.filteredWts)
 
 	// Update table rows
 	rows := make([]table.Row, 0, len(m.filteredWts))
 	for _, wt := range m.filteredWts {
 		name := filepath.Base(wt.Path)
-		if wt.IsMain {
+		switch {
+		case wt.IsMain:
 			name = " " + mainWorktreeName
-		} else {
-			name = " " + name
+		case m.isExternalWorktree(wt):
+			name = " " + stackPrefix(stackDepths[wt.Path]) + name + " " + externalWorktreeMarker
+		default:
+			name = " " + stackPrefix(stackDepths[wt.Path]) + name
+		}
+		if tags := worktreeStateTags(wt); tags != "" {
+			name += " " + tags
 		}
 
 		// Truncate to configured max length with ellipsis if needed
@@ -1169,6 +1694,12 @@ func (m *Model) updateTable() {
 				name = string(nameRunes[:m.config.MaxNameLength]) + "..."
 			}
 		}
+		if m.isPinned(wt.Path) {
+			name += " " + pinnedMarker
+		}
+		if m.observing() && wt.Path == m.observeLatest {
+			name += " " + observeMarker
+		}
 
 		status := "✓ "
 		if wt.Dirty {
@@ -1197,7 +1728,7 @@ func (m *Model) updateTable() {
 			name,
 			status,
 			abStr,
-			wt.LastActive,
+			m.lastActiveLabel(wt.LastActive, wt.LastActiveTS),
 		}
 
 		// Only include PR column if PR data has been loaded
@@ -1213,6 +1744,9 @@ func (m *Model) updateTable() {
 				switch wt.PR.State {
 				case "OPEN":
 					stateSymbol = symbolFilledCircle
+					if wt.PR.IsDraft {
+						stateSymbol = symbolDraftCircle
+					}
 				case "MERGED":
 					stateSymbol = "◆"
 				case "CLOSED":
@@ -1225,10 +1759,29 @@ func (m *Model) updateTable() {
 			}
 			row = append(row, prStr)
 		}
+		if m.showDeploymentColumn() {
+			row = append(row, m.deploymentCell(wt))
+		}
+		if m.showServicesColumn() {
+			row = append(row, m.servicesCell(wt))
+		}
+		if m.showOwnerColumn() {
+			row = append(row, m.ownerCell(wt))
+		}
+		if m.showDiffStatsColumn() {
+			row = append(row, m.diffStatsCell(wt))
+		}
 
 		rows = append(rows, row)
 	}
 
+	// Columns come and go with PR data, deployments, services, owners and
+	// diff statistics;
+	// the table needs as many as each row has cells.
+	if len(rows) > 0 && len(rows[0]) != len(m.worktreeTable.Columns()) {
+		m.worktreeTable.SetRows(nil)
+		m.updateTableColumns(m.worktreeTable.Width())
+	}
 	m.worktreeTable.SetRows(rows)
 	if len(m.filteredWts) > 0 && m.selectedIndex >= len(m.filteredWts) {
 		m.selectedIndex = len(m.filteredWts) - 1
@@ -1279,37 +1832,74 @@ func (m *Model) updateDetailsView() tea.Cmd {
 		}
 		return nil
 	}
-	return func() tea.Msg {
+	dwellCmd := m.scheduleNavigation(wt.Path)
+	m.emitSelection(wt)
+	m.dropInitOutputs(wt.Path)
+	var previewCmd tea.Cmd
+	if m.previewMode && wt.Path != m.previewPath {
+		previewCmd = m.loadPreview()
+	}
+	// Remote URLs are looked up once per remote, as they seldom change.
+	remote := upstreamRemote(wt)
+	if _, known := m.remoteURLs[remote]; known {
+		remote = ""
+	}
+	// The template and cached disk size are read here, on the Update side,
+	// as the command below runs concurrently with Update.
+	source := m.infoTemplateSource()
+	var cachedExtras infoExtras
+	if cached := m.infoExtras[wt.Path]; cached != nil {
+		cachedExtras = *cached
+	}
+	detailsCmd := func() tea.Msg {
 		statusRaw, logRaw, unpushed, unmerged := m.getCachedDetails(wt)
 
 		// Parse log
 		logEntries := []commitLogEntry{}
 		for line := range strings.SplitSeq(logRaw, "\n") {
-			parts := strings.SplitN(line, "\t", 3)
-			if len(parts) < 2 {
+			parts := strings.SplitN(line, "\t", 4)
+			if len(parts) < 4 {
 				continue
 			}
-			sha := parts[0]
-			message := parts[len(parts)-1]
-			author := ""
-			if len(parts) == 3 {
-				author = parts[1]
+			sha, author, message := parts[0], parts[2], parts[3]
+			var date time.Time
+			if ts, err := strconv.ParseInt(parts[1], 10, 64); err == nil {
+				date = time.Unix(ts, 0)
 			}
 			logEntries = append(logEntries, commitLogEntry{
 				sha:            sha,
+				date:           date,
 				authorInitials: authorInitials(author),
 				message:        message,
 				isUnpushed:     unpushed[sha],
 				isUnmerged:     unmerged[sha],
 			})
 		}
+		// A templated info pane is rebuilt by Update once extras arrive.
+		info := ""
+		var extras *infoExtras
+		if source != "" {
+			extras = m.loadInfoExtras(wt, source, cachedExtras)
+		} else {
+			info = m.buildInfoContent(wt)
+		}
+		remoteURL := ""
+		if remote != "" {
+			remoteURL = m.git.RemoteURL(m.ctx, remote, wt.Path)
+		}
 		return statusUpdatedMsg{
-			info:        m.buildInfoContent(wt),
+			info:        info,
 			statusFiles: parseStatusFiles(statusRaw),
 			log:         logEntries,
 			path:        wt.Path,
+			extras:      extras,
+			changed:     m.branchChangedFiles(wt),
+			activity:    m.git.ActivityDays(m.ctx, wt.Path, activityDays, time.Now()),
+			remote:      remote,
+			remoteURL:   remoteURL,
 		}
 	}
+	return tea.Batch(detailsCmd, previewCmd, dwellCmd)
 }
 
 func (m *Model) debouncedUpdateDetailsView() tea.Cmd {
@@ -1341,56 +1931,103 @@ func (m *Model) debouncedUpdateDetailsView() tea.Cmd {
 	}
 }
 
+// refreshWorktrees reloads the worktree list, cancelling any refresh still
+// in flight so its stale result never replaces this one.
 func (m *Model) refreshWorktrees() tea.Cmd {
+	if m.refreshCancel != nil {
+		m.refreshCancel()
+	}
+	parent := m.ctx
+	if parent == nil {
+		parent = context.Background()
+	}
+	ctx, cancel := context.WithCancel(parent)
+	m.refreshCancel = cancel
+	m.refreshGen++
+	gen := m.refreshGen
+	m.refreshWG.Add(1)
 	return func() tea.Msg {
-		worktrees, err := m.git.GetWorktrees(m.ctx)
+		defer m.refreshWG.Done()
+		defer cancel()
+		worktrees, err := m.git.GetWorktrees(ctx)
+		if ctx.Err() != nil {
+			m.debugf("worktree refresh %d cancelled", gen)
+			return nil
+		}
 		return worktreesLoadedMsg{
-			worktrees: worktrees,
-			err:       err,
+			worktrees:  worktrees,
+			err:        err,
+			refreshGen: gen,
 		}
 	}
 }
 
 func (m *Model) fetchPRData() tea.Cmd {
+	worktrees := append([]*models.WorktreeInfo(nil), m.worktrees...)
+	cached := m.freshPRLookups()
 	return func() tea.Msg {
-		// First try the traditional approach (matches by headRefName)
-		prMap, err := m.git.FetchPRMap(m.ctx)
+		// Resolve the remote branch each worktree tracks so fork or renamed
+		// branches can match their PR by headRefName as well
+		heads := make(map[string]string)
+		branches := make([]string, 0, len(worktrees)*2)
+		for _, wt := range worktrees {
+			branches = append(branches, wt.Branch)
+			if head := m.git.UpstreamHeadBranch(m.ctx, wt.Branch, wt.Path); head != "" {
+				heads[wt.Path] = head
+				branches = append(branches, head)
+			}
+		}
+
+		// Match by headRefName, looking up local branches the recent
+		// listing does not cover so busy repositories still resolve every PR
+		prMap, err := m.git.FetchPRMapForBranches(m.ctx, branches)
 		if err != nil {
 			return prDataLoadedMsg{prMap: nil, err: err}
 		}
-		log.Printf("FetchPRMap returned %d PRs", len(prMap))
+		log.Printf("FetchPRMapForBranches returned %d PRs", len(prMap))
 		for branch, pr := range prMap {
 			log.Printf("  prMap[%q] = PR#%d", branch, pr.Number)
 		}
 
-		// Also fetch PRs per worktree for cases where local branch differs from remote
-		// This handles fork PRs where local branch name doesn't match headRefName
 		worktreePRs := make(map[string]*models.PRInfo)
 		worktreeErrors := make(map[string]string)
-		for _, wt := range m.worktrees {
+		pending := make([]*models.WorktreeInfo, 0)
+		for _, wt := range worktrees {
 			log.Printf("Checking worktree: Branch=%q Path=%q", wt.Branch, wt.Path)
+			// Skip if already matched by headRefName
 			if pr, ok := prMap[wt.Branch]; ok {
 				log.Printf("  Found in prMap: PR#%d", pr.Number)
-			} else {
-				log.Printf("  Not in prMap, will fetch per-worktree")
-			}
-
-			// Skip if already matched by headRefName
-			if _, ok := prMap[wt.Branch]; ok {
 				continue
 			}
-			// Try to fetch PR for this worktree directly
-			pr, fetchErr := m.git.FetchPRForWorktreeWithError(m.ctx, wt.Path)
-			if pr != nil {
-				worktreePRs[wt.Path] = pr
-				log.Printf("  FetchPRForWorktree returned PR#%d", pr.Number)
+			if head := heads[wt.Path]; head != "" {
+				if pr, ok := prMap[head]; ok {
+					worktreePRs[wt.Path] = pr
+					log.Printf("  Matched upstream %q: PR#%d", head, pr.Number)
+					continue
+				}
 			}
-			if fetchErr != nil {
-				worktreeErrors[wt.Path] = fetchErr.Error()
-				log.Printf("  FetchPRForWorktree error: %v", fetchErr)
+			if entry, ok := cached[wt.Path]; ok && entry.branch == wt.Branch {
+				if entry.pr != nil {
+					worktreePRs[wt.Path] = entry.pr
+				}
+				if entry.err != "" {
+					worktreeErrors[wt.Path] = entry.err
+				}
+				log.Printf("  Using cached per-worktree lookup")
+				continue
 			}
-			if pr == nil && fetchErr == nil {
-				log.Printf("  FetchPRForWorktree returned nil (no PR)")
+			log.Printf("  Not in prMap, will fetch per-worktree")
+			pending = append(pending, wt)
+		}
+
+		// Fall back to gh pr view/glab mr view per worktree, a few at a time
+		lookups := m.lookupWorktreePRs(pending)
+		for path, entry := range lookups {
+			if entry.pr != nil {
+				worktreePRs[path] = entry.pr
+			}
+			if entry.err != "" {
+				worktreeErrors[path] = entry.err
 			}
 		}
 
@@ -1398,6 +2035,7 @@ func (m *Model) fetchPRData() tea.Cmd {
 			prMap:          prMap,
 			worktreePRs:    worktreePRs,
 			worktreeErrors: worktreeErrors,
+			lookups:        lookups,
 			err:            nil,
 		}
 	}
@@ -1436,12 +2074,15 @@ func (m *Model) maybeFetchCIStatus() tea.Cmd {
 
 func (m *Model) fetchRemotes() tea.Cmd {
 	return func() tea.Msg {
-		m.git.RunGit(m.ctx, []string{"git", "fetch", "--all", "--quiet"}, "", []int{0}, false, false)
+		m.git.FetchAll(m.ctx)
 		return fetchRemotesCompleteMsg{}
 	}
 }
 
 func (m *Model) showDeleteFile() tea.Cmd {
+	if m.readOnlyDenied("Deleting files") {
+		return nil
+	}
 	if m.selectedIndex < 0 || m.selectedIndex >= len(m.filteredWts) {
 		return nil
 	}
@@ -1604,6 +2245,9 @@ func (m *Model) showDiffNonInteractive() tea.Cmd {
 	for k, v := range env {
 		envVars = append(envVars, fmt.Sprintf("%s=%s", k, v))
 	}
+	if report := m.diffReport(wt); report != "" {
+		envVars = append(envVars, diffReportEnv+"="+report)
+	}
 
 	// Get pager configuration
 	pager := m.pagerCommand()
@@ -1616,7 +2260,7 @@ func (m *Model) showDiffNonInteractive() tea.Cmd {
 	// Build a script that replicates BuildThreePartDiff behavior
 	// This shows: 1) Staged changes, 2) Unstaged changes, 3) Untracked files (limited)
 	maxUntracked := m.config.MaxUntrackedDiffs
-	script := fmt.Sprintf(`
+	script := diffReportScript + fmt.Sprintf(`
 	set -e
 	# Part 1: Staged changes
 	staged=$(git diff --cached --patch --no-color 2>/dev/null || true)
@@ -1716,8 +2360,11 @@ echo "=== Untracked:" %s "==="
 git diff --no-index /dev/null %s 2>/dev/null || true
 `, escapedFilename, escapedFilename)
 	} else {
+		if report := m.diffReport(wt, sf.Filename); report != "" {
+			envVars = append(envVars, diffReportEnv+"="+report)
+		}
 		// For tracked files, show both staged and unstaged changes
-		script = fmt.Sprintf(`
+		script = diffReportScript + fmt.Sprintf(`
 set -e
 # Staged changes for this file
 staged=$(git diff --cached --patch --no-color -- %s 2>/dev/null || true)
@@ -1765,6 +2412,15 @@ fi
 }
 
 func (m *Model) openStatusFileInEditor(sf StatusFile) tea.Cmd {
+	return m.openFileInEditor(sf.Filename, 0)
+}
+
+// openFileInEditor opens the selected worktree's file rel in the editor,
+// at line when it is set.
+func (m *Model) openFileInEditor(rel string, line int) tea.Cmd {
+	if m.readOnlyDenied("Editing files") {
+		return nil
+	}
 	if m.selectedIndex < 0 || m.selectedIndex >= len(m.filteredWts) {
 		return nil
 	}
@@ -1776,9 +2432,9 @@ func (m *Model) openStatusFileInEditor(sf StatusFile) tea.Cmd {
 		return nil
 	}
 
-	filePath := filepath.Join(wt.Path, sf.Filename)
+	filePath := filepath.Join(wt.Path, rel)
 	if _, err := os.Stat(filePath); err != nil {
-		m.showInfo(fmt.Sprintf("Cannot open %s: %v", sf.Filename, err), nil)
+		m.showInfo(fmt.Sprintf("Cannot open %s: %v", rel, err), nil)
 		return nil
 	}
 
@@ -1788,7 +2444,7 @@ func (m *Model) openStatusFileInEditor(sf StatusFile) tea.Cmd {
 		envVars = append(envVars, fmt.Sprintf("%s=%s", k, v))
 	}
 
-	cmdStr := fmt.Sprintf("%s %s", editor, shellQuote(sf.Filename))
+	cmdStr := fmt.Sprintf("%s %s", editor, editorFileArgs(editor, rel, line))
 	// #nosec G204 -- command is constructed from user config and controlled inputs
 	c := m.commandRunner("bash", "-c", cmdStr)
 	c.Dir = wt.Path
@@ -1803,10 +2459,16 @@ func (m *Model) openStatusFileInEditor(sf StatusFile) tea.Cmd {
 }
 
 func (m *Model) commitAllChanges() tea.Cmd {
+	if m.readOnlyDenied("Committing") {
+		return nil
+	}
 	if m.selectedIndex < 0 || m.selectedIndex >= len(m.filteredWts) {
 		return nil
 	}
 	wt := m.filteredWts[m.selectedIndex]
+	if m.bareDenied(wt, "Committing") {
+		return nil
+	}
 
 	env := m.buildCommandEnv(wt.Branch, wt.Path)
 	envVars := os.Environ()
@@ -1831,6 +2493,9 @@ func (m *Model) commitAllChanges() tea.Cmd {
 }
 
 func (m *Model) commitStagedChanges() tea.Cmd {
+	if m.readOnlyDenied("Committing") {
+		return nil
+	}
 	if m.selectedIndex < 0 || m.selectedIndex >= len(m.filteredWts) {
 		return nil
 	}
@@ -1876,6 +2541,9 @@ func (m *Model) commitStagedChanges() tea.Cmd {
 }
 
 func (m *Model) stageCurrentFile(sf StatusFile) tea.Cmd {
+	if m.readOnlyDenied("Staging") {
+		return nil
+	}
 	if m.selectedIndex < 0 || m.selectedIndex >= len(m.filteredWts) {
 		return nil
 	}
@@ -1931,6 +2599,9 @@ func (m *Model) stageCurrentFile(sf StatusFile) tea.Cmd {
 }
 
 func (m *Model) stageDirectory(node *StatusTreeNode) tea.Cmd {
+	if m.readOnlyDenied("Staging") {
+		return nil
+	}
 	if m.selectedIndex < 0 || m.selectedIndex >= len(m.filteredWts) {
 		return nil
 	}
@@ -1994,6 +2665,9 @@ func (m *Model) stageDirectory(node *StatusTreeNode) tea.Cmd {
 }
 
 func (m *Model) showRunCommand() tea.Cmd {
+	if m.readOnlyDenied("Running commands") {
+		return nil
+	}
 	if m.selectedIndex < 0 || m.selectedIndex >= len(m.filteredWts) {
 		return nil
 	}
@@ -2029,7 +2703,7 @@ func (m *Model) buildMRUPaletteItems() []paletteItem {
 
 	// Build a lookup map of all available palette items
 	itemMap := make(map[string]paletteItem)
-	customItems := m.customPaletteItems()
+	customItems := append(m.customPaletteItems(), m.macroPaletteItems()...)
 
 	// Add all standard palette items
 	standardItems := []paletteItem{
@@ -2038,7 +2712,20 @@ func (m *Model) buildMRUPaletteItems() []paletteItem {
 		{id: "delete", label: "Delete worktree (D)", description: "Remove worktree and branch"},
 		{id: "rename", label: "Rename worktree (m)", description: "Rename worktree and branch"},
 		{id: "absorb", label: "Absorb worktree (A)", description: "Merge branch into main and remove worktree"},
+		{id: "sync-my-prs", label: "Sync my PRs (M)", description: "Create worktrees for your open PRs, prune merged ones"},
 		{id: "prune", label: "Prune merged (X)", description: "Remove merged PR worktrees"},
+		{id: "adopt", label: "Adopt worktree", description: "Move an external worktree under the worktree directory or keep it in place"},
+		{id: "snapshot", label: "Snapshot worktree", description: "Record HEAD, staged, unstaged and untracked files"},
+		{id: "restore-snapshot", label: "Restore snapshot", description: "Bring the worktree back to a snapshot"},
+		{id: "delete-snapshot", label: "Delete snapshot", description: "Remove a snapshot of the worktree"},
+		{id: "sparse-checkout", label: "Edit sparse checkout", description: "Choose which directories the worktree checks out"},
+		{id: "health-matrix", label: "Health matrix", description: "Latest lint, test, build and CI results of every worktree"},
+		{id: "code-owners", label: "Show code owners", description: "List who owns the worktree's changed files (CODEOWNERS)"},
+		{id: "filter-project", label: "Filter by project", description: "Show worktrees whose changes touch a monorepo project"},
+		{id: "focus-mode", label: "Focus mode", description: "Show only the selected branch's family and widen the details"},
+		{id: "filter-mine", label: "Show only my worktrees", description: "Hide worktrees other users created on this machine"},
+		{id: "prefetch-blobs", label: "Prefetch blobs", description: "Download the file versions recent commits need in a partial clone"},
+		{id: "maintenance", label: "Maintenance", description: "Run git gc, worktree prune, fetch and cache cleanup"},
 
 		// Create Shortcuts
 		{id: "create-from-current", label: "Create worktree from current branch", description: "Create from current branch with or without changes"},
@@ -2046,18 +2733,44 @@ func (m *Model) buildMRUPaletteItems() []paletteItem {
 		{id: "create-from-commit", label: "Create worktree from commit", description: "Choose a branch, then select a specific commit"},
 		{id: "create-from-pr", label: "Create worktree from PR/MR", description: "Create from a pull/merge request"},
 		{id: "create-from-issue", label: "Create worktree from issue", description: "Create from a GitHub/GitLab issue"},
+		{id: "create-from-description", label: "Create worktree from description", description: "Describe the work and get a suggested branch name"},
+		{id: "create-from-clipboard", label: "Create worktree from clipboard", description: "Use a copied branch name or PR/issue URL"},
+		{id: "create-from-stash", label: "Create worktree from stash", description: "Move stashed work into a worktree of its own"},
+		{id: "create-from-patch", label: "Create worktree from patch file", description: "Apply a diff or format-patch file to a new branch"},
 		{id: "create-freeform", label: "Create worktree from ref", description: "Enter a branch, tag, or commit manually"},
+		{id: "create-default-branch", label: "Create worktree for default branch", description: "Check out the default branch, the first worktree a bare repository needs"},
+		{id: "recently-deleted", label: "Recently deleted worktrees", description: "Recreate a deleted worktree from its last commit"},
+		{id: "suggested-branches", label: "Suggested branches", description: "Create a worktree from a recent remote branch or your open PR"},
+		{id: "services", label: "Services", description: "Start, stop or restart the selected worktree's services"},
+		{id: "service-ports", label: "Service ports", description: "Show which worktree holds each service port"},
+		{id: "compose", label: "Docker Compose", description: "Start or stop the selected worktree's Compose project"},
+		{id: "allow-env-tools", label: "Allow direnv/mise", description: "Run direnv allow and mise trust in the selected worktree"},
+		{id: "bootstrap", label: "Bootstrap toolchains", description: "Run setup commands such as npm ci or go mod download"},
+		{id: "sync-artifacts", label: "Sync build artifacts", description: "Copy or link the configured build outputs from the main worktree"},
 
 		// Git Operations
 		{id: "diff", label: "Show diff (d)", description: "Show diff for current worktree or commit"},
+		{id: "fix-whitespace", label: "Fix staged whitespace (W)", description: "Strip trailing whitespace and fix line endings in staged changes"},
 		{id: "refresh", label: "Refresh (r)", description: "Reload worktrees"},
 		{id: "fetch", label: "Fetch remotes (R)", description: "git fetch --all"},
+		{id: "fetch-branch", label: "Fetch this branch (F)", description: "Fetch only the selected worktree's upstream"},
+		{id: "switch-remote-protocol", label: "Switch remote between ssh and https", description: "Point the upstream remote at its other URL form"},
 		{id: "push", label: "Push to upstream (P)", description: "git push (clean worktree only)"},
 		{id: "sync", label: "Synchronise with upstream (S)", description: "git pull, then git push (clean worktree only)"},
+		{id: "pull", label: "Pull from upstream (u)", description: "git pull, honouring merge_method (clean worktree only)"},
+		{id: "force-push", label: "Force push to upstream (U)", description: "git push --force-with-lease, after confirming"},
+		{id: "restack", label: "Restack descendants", description: "Rebase the branches stacked on this one onto its tip"},
 		{id: "fetch-pr-data", label: "Fetch PR data (p)", description: "Fetch PR/MR status from GitHub/GitLab"},
 		{id: "pr", label: "Open PR (o)", description: "Open PR in browser"},
+		{id: "pr-description", label: "Read PR description (i)", description: "Show the rendered PR/MR description"},
+		{id: "open-deployment", label: "Open deployment (O)", description: "Open the preview environment URL"},
+		{id: "pr-toggle-draft", label: "Toggle PR draft", description: "Mark the PR/MR as draft or ready for review"},
+		{id: "ci-findings", label: "CI findings", description: "List the failing CI checks' annotations and open them in the editor"},
+		{id: "pr-request-reviewers", label: "Request PR reviewers", description: "Ask reviewers to look at the PR/MR"},
 		{id: "lazygit", label: "Open LazyGit (g)", description: "Open LazyGit in selected worktree"},
 		{id: "run-command", label: "Run command (!)", description: "Run arbitrary command in worktree"},
+		{id: "record-macro", label: "Record macro (Q)", description: "Record keys as a macro bound to a key of its own"},
+		{id: "changelog", label: "Generate changelog", description: "Summarise branch commits as a changelog snippet"},
 
 		// Status Pane
 		{id: "stage-file", label: "Stage/unstage file (s)", description: "Stage or unstage selected file"},
@@ -2072,16 +2785,27 @@ func (m *Model) buildMRUPaletteItems() []paletteItem {
 
 		// Navigation
 		{id: "zoom-toggle", label: "Toggle zoom (=)", description: "Toggle zoom on focused pane"},
+		{id: "toggle-preview", label: "Toggle preview (v)", description: "Show the README or overview instead of changed files"},
 		{id: "filter", label: "Filter (f)", description: "Filter items in focused pane"},
 		{id: "search", label: "Search (/)", description: "Search items in focused pane"},
+		{id: "find-all-repos", label: "Find in all repositories", description: "Find a branch or PR's worktree in any repository and jump to it"},
 		{id: "focus-worktrees", label: "Focus worktrees (1)", description: "Focus worktree pane"},
 		{id: "focus-status", label: "Focus status (2)", description: "Focus status pane"},
 		{id: "focus-log", label: "Focus log (3)", description: "Focus log pane"},
-		{id: "sort-cycle", label: "Cycle sort (s)", description: "Cycle sort mode (path/active/switched)"},
+		{id: "sort-cycle", label: "Cycle sort (s)", description: "Cycle sort mode (path/active/switched/manual)"},
+		{id: "sort-reverse", label: "Reverse sort order", description: "Flip the direction of the current sort"},
+		{id: "toggle-dates", label: "Toggle date format (T)", description: "Show dates relative to now or as absolute timestamps"},
+		{id: "pin-worktree", label: "Pin / unpin worktree (b)", description: "Keep the worktree at the top whatever the sort"},
+		{id: "move-worktree-up", label: "Move worktree up (K)", description: "Move the worktree up in a manual order"},
+		{id: "move-worktree-down", label: "Move worktree down (J)", description: "Move the worktree down in a manual order"},
+		{id: "history-back", label: "Previous worktree (<)", description: "Go back to the previously visited worktree"},
+		{id: "history-forward", label: "Next worktree (>)", description: "Go forward in the worktree history"},
 
 		// Settings
 		{id: "theme", label: "Select theme", description: "Change the application theme with live preview"},
 		{id: "help", label: "Help (?)", description: "Show help"},
+		{id: "about", label: "About lazyworktree", description: "Versions, tools and paths for bug reports"},
+		{id: "show-errors", label: "Show errors (E)", description: "Failed commands, their output and suggested fixes"},
 	}
 
 	for _, item := range standardItems {
@@ -2117,7 +2841,7 @@ func (m *Model) buildMRUPaletteItems() []paletteItem {
 
 func (m *Model) showCommandPalette() tea.Cmd {
 	m.debugf("open palette")
-	customItems := m.customPaletteItems()
+	customItems := append(m.customPaletteItems(), m.macroPaletteItems()...)
 	items := make([]paletteItem, 0, 40+len(customItems))
 
 	// Build MRU section and track which items are in it
@@ -2151,7 +2875,20 @@ func (m *Model) showCommandPalette() tea.Cmd {
 	addItem(paletteItem{id: "delete", label: "Delete worktree (D)", description: "Remove worktree and branch"})
 	addItem(paletteItem{id: "rename", label: "Rename worktree (m)", description: "Rename worktree and branch"})
 	addItem(paletteItem{id: "absorb", label: "Absorb worktree (A)", description: "Merge branch into main and remove worktree"})
+	addItem(paletteItem{id: "sync-my-prs", label: "Sync my PRs (M)", description: "Create worktrees for your open PRs, prune merged ones"})
 	addItem(paletteItem{id: "prune", label: "Prune merged (X)", description: "Remove merged PR worktrees"})
+	addItem(paletteItem{id: "adopt", label: "Adopt worktree", description: "Move an external worktree under the worktree directory or keep it in place"})
+	addItem(paletteItem{id: "snapshot", label: "Snapshot worktree", description: "Record HEAD, staged, unstaged and untracked files"})
+	addItem(paletteItem{id: "restore-snapshot", label: "Restore snapshot", description: "Bring the worktree back to a snapshot"})
+	addItem(paletteItem{id: "delete-snapshot", label: "Delete snapshot", description: "Remove a snapshot of the worktree"})
+	addItem(paletteItem{id: "sparse-checkout", label: "Edit sparse checkout", description: "Choose which directories the worktree checks out"})
+	addItem(paletteItem{id: "health-matrix", label: "Health matrix", description: "Latest lint, test, build and CI results of every worktree"})
+	addItem(paletteItem{id: "code-owners", label: "Show code owners", description: "List who owns the worktree's changed files (CODEOWNERS)"})
+	addItem(paletteItem{id: "filter-project", label: "Filter by project", description: "Show worktrees whose changes touch a monorepo project"})
+	addItem(paletteItem{id: "focus-mode", label: "Focus mode", description: "Show only the selected branch's family and widen the details"})
+	addItem(paletteItem{id: "filter-mine", label: "Show only my worktrees", description: "Hide worktrees other users created on this machine"})
+	addItem(paletteItem{id: "prefetch-blobs", label: "Prefetch blobs", description: "Download the file versions recent commits need in a partial clone"})
+	addItem(paletteItem{id: "maintenance", label: "Maintenance", description: "Run git gc, worktree prune, fetch and cache cleanup"})
 
 	// Section: Create Shortcuts
 	items = append(items, paletteItem{label: "Create Shortcuts", isSection: true})
@@ -2160,19 +2897,49 @@ func (m *Model) showCommandPalette() tea.Cmd {
 	addItem(paletteItem{id: "create-from-commit", label: "Create worktree from commit", description: "Choose a branch, then select a specific commit"})
 	addItem(paletteItem{id: "create-from-pr", label: "Create worktree from PR/MR", description: "Create from a pull/merge request"})
 	addItem(paletteItem{id: "create-from-issue", label: "Create worktree from issue", description: "Create from a GitHub/GitLab issue"})
+	addItem(paletteItem{id: "create-from-description", label: "Create worktree from description", description: "Describe the work and get a suggested branch name"})
+	addItem(paletteItem{id: "create-from-clipboard", label: "Create worktree from clipboard", description: "Use a copied branch name or PR/issue URL"})
+	addItem(paletteItem{id: "create-from-stash", label: "Create worktree from stash", description: "Move stashed work into a worktree of its own"})
+	addItem(paletteItem{id: "create-from-patch", label: "Create worktree from patch file", description: "Apply a diff or format-patch file to a new branch"})
 	addItem(paletteItem{id: "create-freeform", label: "Create worktree from ref", description: "Enter a branch, tag, or commit manually"})
+	addItem(paletteItem{id: "create-default-branch", label: "Create worktree for default branch", description: "Check out the default branch, the first worktree a bare repository needs"})
+	addItem(paletteItem{id: "recently-deleted", label: "Recently deleted worktrees", description: "Recreate a deleted worktree from its last commit"})
+	addItem(paletteItem{id: "suggested-branches", label: "Suggested branches", description: "Create a worktree from a recent remote branch or your open PR"})
+	addItem(paletteItem{id: "services", label: "Services", description: "Start, stop or restart the selected worktree's services"})
+	addItem(paletteItem{id: "service-ports", label: "Service ports", description: "Show which worktree holds each service port"})
+	addItem(paletteItem{id: "compose", label: "Docker Compose", description: "Start or stop the selected worktree's Compose project"})
+	addItem(paletteItem{id: "allow-env-tools", label: "Allow direnv/mise", description: "Run direnv allow and mise trust in the selected worktree"})
+	addItem(paletteItem{id: "bootstrap", label: "Bootstrap toolchains", description: "Run setup commands such as npm ci or go mod download"})
+	addItem(paletteItem{id: "sync-artifacts", label: "Sync build artifacts", description: "Copy or link the configured build outputs from the main worktree"})
 
 	// Section: Git Operations
 	items = append(items, paletteItem{label: "Git Operations", isSection: true})
 	addItem(paletteItem{id: "diff", label: "Show diff (d)", description: "Show diff for current worktree or commit"})
+	addItem(paletteItem{id: "fix-whitespace", label: "Fix staged whitespace (W)", description: "Strip trailing whitespace and fix line endings in staged changes"})
 	addItem(paletteItem{id: "refresh", label: "Refresh (r)", description: "Reload worktrees"})
 	addItem(paletteItem{id: "fetch", label: "Fetch remotes (R)", description: "git fetch --all"})
+	addItem(paletteItem{id: "fetch-branch", label: "Fetch this branch (F)", description: "Fetch only the selected worktree's upstream"})
+	addItem(paletteItem{id: "switch-remote-protocol", label: "Switch remote between ssh and https", description: "Point the upstream remote at its other URL form"})
 	addItem(paletteItem{id: "push", label: "Push to upstream (P)", description: "git push (clean worktree only)"})
 	addItem(paletteItem{id: "sync", label: "Synchronise with upstream (S)", description: "git pull, then git push (clean worktree only)"})
+	addItem(paletteItem{id: "pull", label: "Pull from upstream (u)", description: "git pull, honouring merge_method (clean worktree only)"})
+	addItem(paletteItem{id: "force-push", label: "Force push to upstream (U)", description: "git push --force-with-lease, after confirming"})
+	addItem(paletteItem{id: "restack", label: "Restack descendants", description: "Rebase the branches stacked on this one onto its tip"})
 	addItem(paletteItem{id: "fetch-pr-data", label: "Fetch PR data (p)", description: "Fetch PR/MR status from GitHub/GitLab"})
 	addItem(paletteItem{id: "pr", label: "Open PR (o)", description: "Open PR in browser"})
+	addItem(paletteItem{id: "pr-description", label: "Read PR description (i)", description: "Show the rendered PR/MR description"})
+	addItem(paletteItem{id: "open-deployment", label: "Open deployment (O)", description: "Open the preview environment URL"})
+	addItem(paletteItem{id: "pr-toggle-draft", label: "Toggle PR draft", description: "Mark the PR/MR as draft or ready for review"})
+	addItem(paletteItem{id: "ci-findings", label: "CI findings", description: "List the failing CI checks' annotations and open them in the editor"})
+	addItem(paletteItem{id: "pr-request-reviewers", label: "Request PR reviewers", description: "Ask reviewers to look at the PR/MR"})
 	addItem(paletteItem{id: "lazygit", label: "Open LazyGit (g)", description: "Open LazyGit in selected worktree"})
 	addItem(paletteItem{id: "run-command", label: "Run command (!)", description: "Run arbitrary command in worktree"})
+	if m.macroRecording {
+		addItem(paletteItem{id: "record-macro", label: "Stop recording macro (Q)", description: "Save the keys recorded so far as a macro"})
+	} else {
+		addItem(paletteItem{id: "record-macro", label: "Record macro (Q)", description: "Record keys as a macro bound to a key of its own"})
+	}
+	addItem(paletteItem{id: "changelog", label: "Generate changelog", description: "Summarise branch commits as a changelog snippet"})
 
 	// Section: Status Pane
 	items = append(items, paletteItem{label: "Status Pane", isSection: true})
@@ -2190,17 +2957,28 @@ func (m *Model) showCommandPalette() tea.Cmd {
 	// Section: Navigation
 	items = append(items, paletteItem{label: "Navigation", isSection: true})
 	addItem(paletteItem{id: "zoom-toggle", label: "Toggle zoom (=)", description: "Toggle zoom on focused pane"})
+	addItem(paletteItem{id: "toggle-preview", label: "Toggle preview (v)", description: "Show the README or overview instead of changed files"})
 	addItem(paletteItem{id: "filter", label: "Filter (f)", description: "Filter items in focused pane"})
 	addItem(paletteItem{id: "search", label: "Search (/)", description: "Search items in focused pane"})
+	addItem(paletteItem{id: "find-all-repos", label: "Find in all repositories", description: "Find a branch or PR's worktree in any repository and jump to it"})
 	addItem(paletteItem{id: "focus-worktrees", label: "Focus worktrees (1)", description: "Focus worktree pane"})
 	addItem(paletteItem{id: "focus-status", label: "Focus status (2)", description: "Focus status pane"})
 	addItem(paletteItem{id: "focus-log", label: "Focus log (3)", description: "Focus log pane"})
-	addItem(paletteItem{id: "sort-cycle", label: "Cycle sort (s)", description: "Cycle sort mode (path/active/switched)"})
+	addItem(paletteItem{id: "sort-cycle", label: "Cycle sort (s)", description: "Cycle sort mode (path/active/switched/manual)"})
+	addItem(paletteItem{id: "sort-reverse", label: "Reverse sort order", description: "Flip the direction of the current sort"})
+	addItem(paletteItem{id: "toggle-dates", label: "Toggle date format (T)", description: "Show dates relative to now or as absolute timestamps"})
+	addItem(paletteItem{id: "pin-worktree", label: "Pin / unpin worktree (b)", description: "Keep the worktree at the top whatever the sort"})
+	addItem(paletteItem{id: "move-worktree-up", label: "Move worktree up (K)", description: "Move the worktree up in a manual order"})
+	addItem(paletteItem{id: "move-worktree-down", label: "Move worktree down (J)", description: "Move the worktree down in a manual order"})
+	addItem(paletteItem{id: "history-back", label: "Previous worktree (<)", description: "Go back to the previously visited worktree"})
+	addItem(paletteItem{id: "history-forward", label: "Next worktree (>)", description: "Go forward in the worktree history"})
 
 	// Section: Settings
 	items = append(items, paletteItem{label: "Settings", isSection: true})
 	addItem(paletteItem{id: "theme", label: "Select theme", description: "Change the application theme with live preview"})
 	addItem(paletteItem{id: "help", label: "Help (?)", description: "Show help"})
+	addItem(paletteItem{id: "about", label: "About lazyworktree", description: "Versions, tools and paths for bug reports"})
+	addItem(paletteItem{id: "show-errors", label: "Show errors (E)", description: "Failed commands, their output and suggested fixes"})
 
 	// Add custom items (filter out MRU duplicates)
 	for _, item := range customItems {
@@ -2233,6 +3011,10 @@ func (m *Model) showCommandPalette() tea.Cmd {
 			return m.attachZellijSessionCmd(fullSessionName)
 		}
 
+		if key, ok := strings.CutPrefix(action, "macro:"); ok {
+			return m.runMacro(key)
+		}
+
 		if _, ok := m.config.CustomCommands[action]; ok {
 			return m.executeCustomCommand(action)
 		}
@@ -2248,11 +3030,38 @@ func (m *Model) showCommandPalette() tea.Cmd {
 			return m.showAbsorbWorktree()
 		case "prune":
 			return m.showPruneMerged()
+		case "adopt":
+			return m.showAdoptWorktree()
+		case "snapshot":
+			return m.showSnapshotWorktree()
+		case "restore-snapshot":
+			return m.showRestoreSnapshot()
+		case "delete-snapshot":
+			return m.showDeleteSnapshot()
+		case "sparse-checkout":
+			return m.showEditSparseCheckout()
+		case "health-matrix":
+			return m.showHealthMatrix()
+		case "code-owners":
+			return m.showCodeOwners()
+		case "filter-project":
+			return m.showProjectFilter()
+		case "focus-mode":
+			return m.toggleFocusMode()
+		case "filter-mine":
+			return m.toggleOwnerFilter()
+		case "prefetch-blobs":
+			return m.prefetchBlobs()
+		case "maintenance":
+			return m.showMaintenance()
 
 		// Create Menu Shortcuts
 		case "create-from-current":
 			return m.showCreateFromCurrent()
 		case "create-from-branch":
+			if m.readOnlyDenied("Creating worktrees") {
+				return nil
+			}
 			defaultBase := m.git.GetMainBranch(m.ctx)
 			return m.showBranchSelection(
 				"Select base branch",
@@ -2271,23 +3080,62 @@ func (m *Model) showCommandPalette() tea.Cmd {
 			return m.showCreateFromPR()
 		case "create-from-issue":
 			return m.showCreateFromIssue()
+		case "create-from-description":
+			return m.showCreateFromDescription()
+		case "create-from-clipboard":
+			return m.showCreateFromClipboard()
+		case "create-from-stash":
+			return m.showCreateFromStash()
+		case "create-from-patch":
+			return m.showCreateFromPatch(m.git.GetMainBranch(m.ctx))
 		case "create-freeform":
 			defaultBase := m.git.GetMainBranch(m.ctx)
 			return m.showFreeformBaseInput(defaultBase)
+		case "create-default-branch":
+			return m.createDefaultBranchWorktree()
+		case "recently-deleted":
+			return m.showRecentlyDeleted()
+		case "suggested-branches":
+			return m.showSuggestedBranches()
+		case "services":
+			return m.showServices()
+		case "service-ports":
+			return m.showServicePorts()
+		case "compose":
+			return m.showCompose()
+		case "allow-env-tools":
+			return m.showAllowEnvTools()
+		case "bootstrap":
+			return m.showBootstrap()
+		case "sync-artifacts":
+			return m.showSyncArtifacts()
 
 		// Git Operations
 		case "diff":
 			return m.showDiff()
+		case "fix-whitespace":
+			return m.fixStagedWhitespace()
 		case "refresh":
-			return m.refreshWorktrees()
+			return m.requestRefresh()
 		case "fetch":
 			return m.fetchRemotes()
+		case "switch-remote-protocol":
+			return m.showSwitchRemoteProtocol()
+		case "fetch-branch":
+			return m.fetchSelectedBranch()
 		case "push":
 			return m.pushToUpstream()
 		case "sync":
 			return m.syncWithUpstream()
+		case "pull":
+			return m.pullFromUpstream()
+		case "force-push":
+			return m.forcePushToUpstream()
+		case "restack":
+			return m.showRestack()
 		case "fetch-pr-data":
 			m.ciCache = make(map[string]*ciCacheEntry)
+			m.prLookupCache = make(map[string]*prLookupEntry)
 			m.prDataLoaded = false
 			m.updateTable()
 			m.updateTableColumns(m.worktreeTable.Width())
@@ -2298,10 +3146,26 @@ func (m *Model) showCommandPalette() tea.Cmd {
 			return m.fetchPRData()
 		case "pr":
 			return m.openPR()
+		case "pr-description":
+			return m.showPRDescription()
+		case "sync-my-prs":
+			return m.showSyncMyPRs()
+		case "open-deployment":
+			return m.openDeployment()
+		case "pr-toggle-draft":
+			return m.togglePRDraft()
+		case "ci-findings":
+			return m.showCIFindings()
+		case "pr-request-reviewers":
+			return m.showRequestReviewers()
 		case "lazygit":
 			return m.openLazyGit()
 		case "run-command":
 			return m.showRunCommand()
+		case "record-macro":
+			return m.toggleMacroRecording(true)
+		case "changelog":
+			return m.showChangelog()
 
 		// Status Pane Actions
 		case "stage-file":
@@ -2335,6 +3199,8 @@ func (m *Model) showCommandPalette() tea.Cmd {
 			return m.openCommitView()
 
 		// Navigation & View
+		case "toggle-preview":
+			return m.togglePreview()
 		case "zoom-toggle":
 			if m.zoomedPane >= 0 {
 				m.zoomedPane = -1
@@ -2360,6 +3226,8 @@ func (m *Model) showCommandPalette() tea.Cmd {
 				target = searchTargetLog
 			}
 			return m.startSearch(target)
+		case "find-all-repos":
+			return m.showFindAllRepos()
 		case "focus-worktrees":
 			m.zoomedPane = -1
 			m.focusedPane = 0
@@ -2376,9 +3244,21 @@ func (m *Model) showCommandPalette() tea.Cmd {
 			m.logTable.Focus()
 			return nil
 		case "sort-cycle":
-			m.sortMode = (m.sortMode + 1) % 3
-			m.updateTable()
-			return nil
+			return m.cycleSortMode(1)
+		case "sort-reverse":
+			return m.sortBy(m.sortMode)
+		case "toggle-dates":
+			return m.toggleDateFormat()
+		case "pin-worktree":
+			return m.togglePin()
+		case "move-worktree-up":
+			return m.moveWorktree(-1)
+		case "move-worktree-down":
+			return m.moveWorktree(1)
+		case "history-back":
+			return m.navigateHistory(-1)
+		case "history-forward":
+			return m.navigateHistory(1)
 
 		// Settings & Help
 		case "theme":
@@ -2386,6 +3266,11 @@ func (m *Model) showCommandPalette() tea.Cmd {
 		case "help":
 			m.currentScreen = screenHelp
 			return nil
+		case "about":
+			return m.showAbout()
+		case "show-errors":
+			m.showErrors()
+			return nil
 		}
 		return nil
 	}
@@ -2570,10 +3455,16 @@ func (m *Model) customCommandLabel(cmd *config.CustomCommand, key string) string
 }
 
 func (m *Model) openLazyGit() tea.Cmd {
+	if m.readOnlyDenied("Opening lazygit") {
+		return nil
+	}
 	if m.selectedIndex < 0 || m.selectedIndex >= len(m.filteredWts) {
 		return nil
 	}
 	wt := m.filteredWts[m.selectedIndex]
+	if m.bareDenied(wt, "Opening lazygit") {
+		return nil
+	}
 
 	c := m.commandRunner("lazygit")
 	c.Dir = wt.Path
@@ -2587,6 +3478,9 @@ func (m *Model) openLazyGit() tea.Cmd {
 }
 
 func (m *Model) executeCustomCommand(key string) tea.Cmd {
+	if m.readOnlyDenied("Running custom commands") {
+		return nil
+	}
 	customCmd, ok := m.config.CustomCommands[key]
 	if !ok || customCmd == nil {
 		return nil
@@ -2598,6 +3492,8 @@ func (m *Model) executeCustomCommand(key string) tea.Cmd {
 
 	wt := m.filteredWts[m.selectedIndex]
 
+	// tmux and zellij sessions outlive this call, so they get no WT_CONTEXT
+	// file: nothing could remove it once the session ends.
 	if customCmd.Zellij != nil {
 		return m.openZellijSession(customCmd.Zellij, wt)
 	}
@@ -2612,6 +3508,7 @@ func (m *Model) executeCustomCommand(key string) tea.Cmd {
 
 	// Set environment variables
 	env := m.buildCommandEnv(wt.Branch, wt.Path)
+	removeContext := m.writeCommandContext(env)
 	envVars := filterWorktreeEnvVars(os.Environ())
 	for k, v := range env {
 		envVars = append(envVars, fmt.Sprintf("%s=%s", k, v))
@@ -2633,6 +3530,7 @@ func (m *Model) executeCustomCommand(key string) tea.Cmd {
 	c.Env = envVars
 
 	return m.execProcess(c, func(err error) tea.Msg {
+		removeContext()
 		if err != nil {
 			return errMsg{err: err}
 		}
@@ -2642,6 +3540,7 @@ func (m *Model) executeCustomCommand(key string) tea.Cmd {
 
 func (m *Model) executeCustomCommandWithPager(customCmd *config.CustomCommand, wt *models.WorktreeInfo) tea.Cmd {
 	env := m.buildCommandEnv(wt.Branch, wt.Path)
+	removeContext := m.writeCommandContext(env)
 	envVars := filterWorktreeEnvVars(os.Environ())
 	for k, v := range env {
 		envVars = append(envVars, fmt.Sprintf("%s=%s", k, v))
@@ -2661,6 +3560,7 @@ func (m *Model) executeCustomCommandWithPager(customCmd *config.CustomCommand, w
 	c.Env = envVars
 
 	return m.execProcess(c, func(err error) tea.Msg {
+		removeContext()
 		if err != nil {
 			// Ignore exit status 141 (SIGPIPE) which happens when the pager is closed early
 			if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 141 {
@@ -2872,8 +3772,35 @@ func (m *Model) openPR() tea.Cmd {
 	if wt.PR == nil {
 		return nil
 	}
+	return m.openURLInBrowser(wt.PR.URL)
+}
+
+// showPRDescription shows the rendered description of the selected
+// worktree's PR/MR.
+func (m *Model) showPRDescription() tea.Cmd {
+	wt := m.selectedWorktree()
+	if wt == nil {
+		return nil
+	}
+	if wt.PR == nil {
+		m.showInfo("No PR/MR found for this worktree. Press p to fetch PR data.", nil)
+		return nil
+	}
+	m.showMarkdown(fmt.Sprintf("#%d %s", wt.PR.Number, wt.PR.Title), wt.PR.URL, wt.PR.Body, screenNone)
+	return nil
+}
+
+// showMarkdown opens the Markdown viewer, returning to returnTo on close.
+func (m *Model) showMarkdown(title, url, body string, returnTo screenType) {
+	m.markdownScreen = NewMarkdownScreen(title, url, body, m.windowWidth, m.windowHeight, m.theme)
+	m.markdownScreen.returnTo = returnTo
+	m.currentScreen = screenMarkdown
+}
+
+// openURLInBrowser opens an http(s) URL with the platform's default handler.
+func (m *Model) openURLInBrowser(rawURL string) tea.Cmd {
 	return func() tea.Msg {
-		prURL, err := sanitizePRURL(wt.PR.URL)
+		prURL, err := sanitizePRURL(rawURL)
 		if err != nil {
 			return errMsg{err: err}
 		}
@@ -2898,6 +3825,9 @@ func (m *Model) openPR() tea.Cmd {
 }
 
 func (m *Model) showCherryPick() tea.Cmd {
+	if m.readOnlyDenied("Cherry-picking") {
+		return nil
+	}
 	// Validate: log pane must be focused
 	if m.focusedPane != 2 {
 		return nil
@@ -2923,8 +3853,8 @@ func (m *Model) showCherryPick() tea.Cmd {
 	// Build worktree selection items (exclude source worktree)
 	items := make([]selectionItem, 0, len(m.worktrees)-1)
 	for _, wt := range m.worktrees {
-		if wt.Path == sourceWorktree.Path {
-			continue // Skip source worktree
+		if wt.Path == sourceWorktree.Path || wt.Bare {
+			continue // Skip source worktree and a bare repository
 		}
 
 		name := filepath.Base(wt.Path)
@@ -3022,7 +3952,7 @@ func (m *Model) showCommitFilesScreen(commitSHA, worktreePath string) tea.Cmd {
 			m.ctx,
 			[]string{
 				"git", "log", "-1",
-				"--pretty=format:%H%x1f%an%x1f%ae%x1f%ad%x1f%s%x1f%b",
+				"--pretty=format:%H%x1f%an%x1f%ae%x1f%at%x1f%s%x1f%b",
 				commitSHA,
 			},
 			worktreePath,
@@ -3031,6 +3961,7 @@ func (m *Model) showCommitFilesScreen(commitSHA, worktreePath string) tea.Cmd {
 			false,
 		)
 		meta := parseCommitMeta(metaRaw)
+		meta.date = m.formatCommitDate(meta.date, commitDateLayout)
 		// Ensure SHA is set even if parsing fails
 		if meta.sha == "" {
 			meta.sha = commitSHA
@@ -3044,7 +3975,8 @@ func (m *Model) showCommitFilesScreen(commitSHA, worktreePath string) tea.Cmd {
 	}
 }
 
-func (m *Model) showCommitDiff(commitSHA string, wt *models.WorktreeInfo) tea.Cmd {
+// openCommitDiff shows a commit's diff in the configured pager or tool.
+func (m *Model) openCommitDiff(commitSHA string, wt *models.WorktreeInfo) tea.Cmd {
 	if strings.Contains(m.config.GitPager, "code") {
 		return m.showCommitDiffVSCode(commitSHA, wt)
 	}
@@ -3067,8 +3999,8 @@ func (m *Model) showCommitDiff(commitSHA string, wt *models.WorktreeInfo) tea.Cm
 	}
 
 	// Build git show command with colorization
-	// --color=always: ensure color codes are passed to delta/pager
-	gitCmd := fmt.Sprintf("git show --color=always %s", commitSHA)
+	// --color=always (never with NO_COLOR): ensure color codes are passed to delta/pager
+	gitCmd := fmt.Sprintf("git show --color=%s %s", m.gitColorMode(), commitSHA)
 
 	// Pipe through git_pager if configured, then through pager
 	// Note: delta only processes the diff part, so our colorized commit message will pass through
@@ -3114,7 +4046,7 @@ func (m *Model) showCommitFileDiff(commitSHA, filename, worktreePath string) tea
 	}
 
 	// Build git show command for specific file with colorization
-	gitCmd := fmt.Sprintf("git show --color=always %s -- %q", commitSHA, filename)
+	gitCmd := fmt.Sprintf("git show --color=%s %s -- %q", m.gitColorMode(), commitSHA, filename)
 
 	// Pipe through git_pager if configured, then through pager
 	var cmdStr string
@@ -3344,6 +4276,12 @@ func (m *Model) handleScreenKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.prSelectionSubmit = nil
 			return m, nil
 		}
+		if keyStr == keyTab {
+			if pr, ok := m.prSelectionScreen.Selected(); ok {
+				m.showMarkdown(fmt.Sprintf("#%d %s", pr.Number, pr.Title), pr.URL, pr.Body, screenPRSelect)
+			}
+			return m, nil
+		}
 		if keyStr == keyEnter {
 			if m.prSelectionSubmit != nil {
 				if pr, ok := m.prSelectionScreen.Selected(); ok {
@@ -3372,6 +4310,12 @@ func (m *Model) handleScreenKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.issueSelectionSubmit = nil
 			return m, nil
 		}
+		if keyStr == keyTab {
+			if issue, ok := m.issueSelectionScreen.Selected(); ok {
+				m.showMarkdown(fmt.Sprintf("#%d %s", issue.Number, issue.Title), issue.URL, issue.Body, screenIssueSelect)
+			}
+			return m, nil
+		}
 		if keyStr == keyEnter {
 			if m.issueSelectionSubmit != nil {
 				if issue, ok := m.issueSelectionScreen.Selected(); ok {
@@ -3432,10 +4376,14 @@ func (m *Model) handleScreenKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		if keyStr == keyEnter {
 			if m.checklistSubmit != nil {
 				selected := m.checklistScreen.SelectedItems()
-				cmd := m.checklistSubmit(selected)
+				submit := m.checklistSubmit
 				m.checklistScreen = nil
 				m.checklistSubmit = nil
-				m.currentScreen = screenNone
+				cmd := submit(selected)
+				// The submit may open another screen, such as a loading one.
+				if m.currentScreen == screenChecklist && m.checklistScreen == nil {
+					m.currentScreen = screenNone
+				}
 				return m, cmd
 			}
 		}
@@ -3444,6 +4392,55 @@ func (m *Model) handleScreenKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.checklistScreen = updated
 		}
 		return m, cmd
+	case screenMarkdown:
+		if m.markdownScreen == nil {
+			m.currentScreen = screenNone
+			return m, nil
+		}
+		keyStr := msg.String()
+		if keyStr == keyQ || isEscKey(keyStr) {
+			m.currentScreen = m.markdownScreen.returnTo
+			m.markdownScreen = nil
+			return m, nil
+		}
+		if link, ok := m.markdownScreen.linkForKey(keyStr); ok {
+			return m, m.openURLInBrowser(link)
+		}
+		if keyStr == "o" && m.markdownScreen.url != "" {
+			return m, m.openURLInBrowser(m.markdownScreen.url)
+		}
+		if keyStr == "y" && m.markdownScreen.copyText != "" {
+			if err := m.copyToClipboard(m.markdownScreen.copyText); err != nil {
+				m.statusContent = fmt.Sprintf("Failed to copy: %v", err)
+			} else {
+				m.statusContent = "Copied to clipboard"
+			}
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.markdownScreen, cmd = m.markdownScreen.Update(msg)
+		return m, cmd
+	case screenHealth:
+		if m.healthScreen == nil {
+			m.currentScreen = screenNone
+			return m, nil
+		}
+		return m, m.handleHealthKey(msg)
+	case screenError:
+		if m.errorScreen == nil {
+			m.currentScreen = screenNone
+			return m, nil
+		}
+		return m, m.handleErrorKey(msg)
+	case screenWhichKey:
+		// The overlay is transient: any key closes it, and all but Esc and
+		// the leader then run as usual.
+		m.currentScreen = screenNone
+		m.whichKeyScreen = nil
+		if keyStr := msg.String(); isEscKey(keyStr) || keyStr == whichKeyLeader {
+			return m, nil
+		}
+		return m.handleKeyMsg(msg)
 	case screenCommitFiles:
 		if m.commitFilesScreen == nil {
 			m.currentScreen = screenNone
@@ -3588,7 +4585,7 @@ func (m *Model) handleScreenKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		case keyStr == "r" || keyStr == "R":
 			m.currentScreen = screenNone
 			m.welcomeScreen = nil
-			return m, m.refreshWorktrees()
+			return m, m.requestRefresh()
 		case keyStr == keyQ || keyStr == "Q" || keyStr == "enter" || isEscKey(keyStr):
 			m.quitting = true
 			m.stopGitWatcher()
@@ -3605,10 +4602,16 @@ func (m *Model) handleScreenKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			if m.pendingTrust != "" {
 				_ = m.trustManager.TrustFile(m.pendingTrust)
 			}
-			cmd := m.runCommands(m.pendingCommands, m.pendingCmdCwd, m.pendingCmdEnv, m.pendingAfter)
+			if action := m.pendingTrustAction; action != nil {
+				m.clearPendingTrust()
+				m.currentScreen = screenNone
+				return m, action()
+			}
+			cmds, cwd, env, after := m.pendingCommands, m.pendingCmdCwd, m.pendingCmdEnv, m.pendingAfter
 			m.clearPendingTrust()
 			m.currentScreen = screenNone
-			return m, cmd
+			// Another file, such as the team configuration, may still need trust.
+			return m, m.runCommandsWithTrust(cmds, cwd, env, after)
 		case keyStr == "b" || keyStr == "B":
 			after := m.pendingAfter
 			m.clearPendingTrust()
@@ -3735,21 +4738,74 @@ func (m *Model) handleScreenKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// cacheSchemaVersion is bumped whenever the cache layout changes; caches
+// written with another version are discarded.
+const cacheSchemaVersion = 1
+
+// worktreeCache is the on-disk layout of the worktree cache.
+type worktreeCache struct {
+	Version int `json:"version"`
+	// Repo is the main worktree path, so clones sharing a repo key do not
+	// read each other's cache.
+	Repo      string                 `json:"repo"`
+	Worktrees []*models.WorktreeInfo `json:"worktrees"`
+}
+
+// cachePath is where the repository's worktree cache is kept.
+func (m *Model) cachePath() string {
+	return filepath.Join(m.getRepoCacheDir(), models.CacheFilename)
+}
+
+// migrateLegacyCache moves a cache written by older versions inside the
+// worktree directory to the cache directory. A cache already present there
+// wins and the legacy copy is dropped.
+func (m *Model) migrateLegacyCache(cachePath string) {
+	legacy := filepath.Join(m.getRepoWorktreeDir(), models.CacheFilename)
+	if _, err := os.Stat(legacy); err != nil {
+		return
+	}
+	if _, err := os.Stat(cachePath); err == nil {
+		_ = os.Remove(legacy)
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(cachePath), defaultDirPerms); err != nil {
+		m.debugf("failed to create cache dir: %v", err)
+		return
+	}
+	if err := os.Rename(legacy, cachePath); err != nil {
+		// Renaming fails across filesystems; the cache is rebuilt on the
+		// next refresh, so dropping it is enough.
+		m.debugf("failed to move legacy cache %s: %v", legacy, err)
+		_ = os.Remove(legacy)
+		return
+	}
+	m.debugf("moved legacy cache %s to %s", legacy, cachePath)
+}
+
 func (m *Model) loadCache() tea.Cmd {
 	return func() tea.Msg {
-		repoKey := m.getRepoKey()
-		cachePath := filepath.Join(m.getWorktreeDir(), repoKey, models.CacheFilename)
+		cachePath := m.cachePath()
+		m.migrateLegacyCache(cachePath)
 		// #nosec G304 -- cachePath is constructed from vetted worktree directory and constant filename
 		data, err := os.ReadFile(cachePath)
 		if err != nil {
 			return nil
 		}
 
-		var payload struct {
-			Worktrees []*models.WorktreeInfo `json:"worktrees"`
+		var payload worktreeCache
+		reason := ""
+		switch err := json.Unmarshal(data, &payload); {
+		case err != nil:
+			reason = fmt.Sprintf("unreadable: %v", err)
+		case payload.Version != cacheSchemaVersion:
+			reason = fmt.Sprintf("schema version %d, expected %d", payload.Version, cacheSchemaVersion)
+		case payload.Repo != m.git.GetMainWorktreePath(m.ctx):
+			reason = fmt.Sprintf("written for %s", payload.Repo)
 		}
-		if err := json.Unmarshal(data, &payload); err != nil {
-			return errMsg{err: err}
+		if reason != "" {
+			m.debugf("discarding worktree cache %s (%s)", cachePath, reason)
+			_ = os.Remove(cachePath)
+			return nil
 		}
 		if len(payload.Worktrees) == 0 {
 			return nil
@@ -3759,20 +4815,35 @@ func (m *Model) loadCache() tea.Cmd {
 }
 
 func (m *Model) saveCache() {
-	repoKey := m.getRepoKey()
-	cachePath := filepath.Join(m.getWorktreeDir(), repoKey, models.CacheFilename)
+	cachePath := m.cachePath()
 	if err := os.MkdirAll(filepath.Dir(cachePath), defaultDirPerms); err != nil {
 		m.showInfo(fmt.Sprintf("Failed to create cache dir: %v", err), nil)
 		return
 	}
 
-	cacheData := struct {
-		Worktrees []*models.WorktreeInfo `json:"worktrees"`
-	}{
+	cacheData := worktreeCache{
+		Version:   cacheSchemaVersion,
 		Worktrees: m.worktrees,
 	}
-	data, _ := json.Marshal(cacheData)
-	if err := os.WriteFile(cachePath, data, defaultFilePerms); err != nil {
+	for _, wt := range m.worktrees {
+		if wt.IsMain {
+			cacheData.Repo = wt.Path
+			break
+		}
+	}
+	data, err := json.Marshal(cacheData)
+	if err != nil {
+		m.debugf("failed to encode worktree cache: %v", err)
+		return
+	}
+	// Write then rename so another instance never reads a partial cache.
+	tmpPath := fmt.Sprintf("%s.%d.tmp", cachePath, os.Getpid())
+	if err := os.WriteFile(tmpPath, data, defaultFilePerms); err != nil {
+		m.showInfo(fmt.Sprintf("Failed to write cache: %v", err), nil)
+		return
+	}
+	if err := os.Rename(tmpPath, cachePath); err != nil {
+		_ = os.Remove(tmpPath)
 		m.showInfo(fmt.Sprintf("Failed to write cache: %v", err), nil)
 	}
 }
@@ -3999,10 +5070,14 @@ func (m *Model) getCachedDetails(wt *models.WorktreeInfo) (string, string, map[s
 		}
 	}
 
-	// Get status (using porcelain format for reliable machine parsing)
-	statusRaw := m.git.RunGit(m.ctx, []string{"git", "status", "--porcelain=v2"}, wt.Path, []int{0}, true, false)
+	// Get status (using porcelain format for reliable machine parsing); a
+	// bare repository has no working tree to have one.
+	statusRaw := ""
+	if !wt.Bare {
+		statusRaw = m.git.RunGit(m.ctx, []string{"git", "status", "--porcelain=v2"}, wt.Path, []int{0}, true, false)
+	}
 	// Use %H for full SHA to ensure reliable matching
-	logRaw := m.git.RunGit(m.ctx, []string{"git", "log", "-50", "--pretty=format:%H%x09%an%x09%s"}, wt.Path, []int{0}, true, false)
+	logRaw := m.git.RunGit(m.ctx, []string{"git", "log", "-50", "--pretty=format:%H%x09%ct%x09%an%x09%s"}, wt.Path, []int{0}, true, false)
 
 	// Get unpushed SHAs (commits not on any remote)
 	unpushedRaw := m.git.RunGit(m.ctx, []string{"git", "rev-list", "-100", "HEAD", "--not", "--remotes"}, wt.Path, []int{0}, true, false)
@@ -4060,6 +5135,12 @@ func (m *Model) getRepoWorktreeDir() string {
 	return filepath.Join(m.getWorktreeDir(), m.getRepoKey())
 }
 
+// getRepoCacheDir holds the repository's runtime files, which are safe to
+// delete, apart from the durable state under the worktree directory.
+func (m *Model) getRepoCacheDir() string {
+	return filepath.Join(utils.CacheDir(), m.getRepoKey())
+}
+
 func (m *Model) pagerCommand() string {
 	if m.config != nil {
 		if pager := strings.TrimSpace(m.config.Pager); pager != "" {
@@ -4078,6 +5159,35 @@ func (m *Model) pagerCommand() string {
 	return "cat"
 }
 
+// animationsEnabled reports whether spinners and other animations may run.
+func (m *Model) animationsEnabled() bool {
+	return m.config == nil || !m.config.NoAnimations
+}
+
+// gitColorMode returns the --color value for git output shown in a pager.
+func (m *Model) gitColorMode() string {
+	if m.config != nil && m.config.NoColor {
+		return "never"
+	}
+	return "always"
+}
+
+// editorFileArgs returns the quoted arguments opening file in editor, at
+// line when it is set: VS Code and its forks take --goto file:line, most
+// other editors +line.
+func editorFileArgs(editor, file string, line int) string {
+	if line <= 0 {
+		return shellQuote(file)
+	}
+	fields := strings.Fields(editor)
+	switch filepath.Base(fields[0]) {
+	case "code", "codium", "cursor", "code-insiders":
+		return "--goto " + shellQuote(fmt.Sprintf("%s:%d", file, line))
+	default:
+		return fmt.Sprintf("+%d %s", line, shellQuote(file))
+	}
+}
+
 func (m *Model) editorCommand() string {
 	if m.config != nil {
 		if editor := strings.TrimSpace(m.config.Editor); editor != "" {
@@ -4120,6 +5230,25 @@ func (m *Model) GetSelectedPath() string {
 	return m.selectedPath
 }
 
+// CrashState summarises the model for crash reports. It avoids paths and
+// content beyond the selected worktree so reports are safe to share.
+func (m *Model) CrashState() string {
+	selected := ""
+	if m.selectedIndex >= 0 && m.selectedIndex < len(m.filteredWts) {
+		selected = m.filteredWts[m.selectedIndex].Path
+	}
+	lines := []string{
+		fmt.Sprintf("repo: %s", m.repoKey),
+		fmt.Sprintf("screen: %s", m.currentScreen),
+		fmt.Sprintf("worktrees: %d (%d shown)", len(m.worktrees), len(m.filteredWts)),
+		fmt.Sprintf("selected: %d %s", m.selectedIndex, selected),
+		fmt.Sprintf("focused pane: %d, zoomed pane: %d", m.focusedPane, m.zoomedPane),
+		fmt.Sprintf("window: %dx%d", m.windowWidth, m.windowHeight),
+		fmt.Sprintf("loading: %t, filtering: %t", m.loading, m.filterQuery != ""),
+	}
+	return strings.Join(lines, "\n")
+}
+
 func (m *Model) showInfo(message string, action tea.Cmd) {
 	m.infoScreen = NewInfoScreen(message, m.theme)
 	m.infoAction = action
@@ -4166,6 +5295,8 @@ func (m *Model) persistLastSelected(path string) {
 // It also persists the current selection for the next session.
 func (m *Model) Close() {
 	m.persistCurrentSelection()
+	m.saveNavHistory()
+	m.unregisterInstance()
 	m.debugf("close")
 	if m.detailUpdateCancel != nil {
 		m.detailUpdateCancel()
@@ -4173,16 +5304,33 @@ func (m *Model) Close() {
 	if m.cancel != nil {
 		m.cancel()
 	}
+
+	// Give cancelled refreshes a moment to stop their git processes.
+	done := make(chan struct{})
+	go func() {
+		m.refreshWG.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(closeWaitTimeout):
+		m.debugf("close: gave up waiting for in-flight refreshes")
+	}
 }
 
 func (m *Model) buildCommandEnv(branch, wtPath string) map[string]string {
-	return map[string]string{
+	env := map[string]string{
 		"WORKTREE_BRANCH":    branch,
 		"MAIN_WORKTREE_PATH": m.git.GetMainWorktreePath(m.ctx),
 		"WORKTREE_PATH":      wtPath,
 		"WORKTREE_NAME":      filepath.Base(wtPath),
 		"REPO_NAME":          m.repoKey,
 	}
+	if m.composeEnabled() {
+		// docker compose run from any command lands in the worktree's project.
+		env["COMPOSE_PROJECT_NAME"] = m.composeProject(branch, wtPath)
+	}
+	return env
 }
 
 type resolvedTmuxWindow struct {
@@ -4223,6 +5371,7 @@ func filterWorktreeEnvVars(environ []string) []string {
 		"WORKTREE_BRANCH":    true,
 		"WORKTREE_NAME":      true,
 		"REPO_NAME":          true,
+		commandContextEnv:    true,
 	}
 
 	filtered := make([]string, 0, len(environ))
@@ -4625,33 +5774,49 @@ func (m *Model) runCommandsWithTrust(cmds []string, cwd string, env map[string]s
 		return after
 	}
 
-	// Determine trust status if repo config exists
-	trustPath := m.repoConfigPath
-	status := security.TrustStatusTrusted
-	if m.repoConfig != nil && trustPath != "" {
-		status = m.trustManager.CheckTrust(trustPath)
-	}
-
-	if trustMode == "always" || status == security.TrustStatusTrusted {
+	// Find a repo config file, the .wt or the team's, awaiting trust
+	trustPath := m.untrustedRepoConfigPath()
+	if trustMode == "always" || trustPath == "" {
 		return m.runCommands(cmds, cwd, env, after)
 	}
 
 	// TOFU: prompt user
-	if trustPath != "" {
-		m.pendingCommands = cmds
-		m.pendingCmdEnv = env
-		m.pendingCmdCwd = cwd
-		m.pendingAfter = after
-		m.pendingTrust = trustPath
-		m.trustScreen = NewTrustScreen(trustPath, cmds, m.theme)
-		m.currentScreen = screenTrust
-	}
+	m.pendingCommands = cmds
+	m.pendingCmdEnv = env
+	m.pendingCmdCwd = cwd
+	m.pendingAfter = after
+	m.pendingTrust = trustPath
+	m.trustScreen = NewTrustScreen(trustPath, cmds, m.theme)
+	m.currentScreen = screenTrust
 	return nil
 }
 
+// untrustedRepoConfigPath returns the first repo config file whose commands
+// have not been trusted, or "" when all have.
+func (m *Model) untrustedRepoConfigPath() string {
+	if m.repoConfig == nil {
+		return ""
+	}
+	for _, path := range []string{m.repoConfigPath, m.repoConfig.TeamPath} {
+		if path != "" && m.trustManager.CheckTrust(path) != security.TrustStatusTrusted {
+			return path
+		}
+	}
+	return ""
+}
+
 func (m *Model) runCommands(cmds []string, cwd string, env map[string]string, after func() tea.Msg) tea.Cmd {
+	removeContext := m.writeCommandContext(env)
 	return func() tea.Msg {
-		if err := m.git.ExecuteCommands(m.ctx, cmds, cwd, env); err != nil {
+		defer removeContext()
+		var err error
+		if out := m.startInitOutput(cwd); out != nil {
+			err = m.git.ExecuteCommandsTo(m.ctx, cmds, cwd, env, out)
+			out.finish(err)
+		} else {
+			err = m.git.ExecuteCommands(m.ctx, cmds, cwd, env)
+		}
+		if err != nil {
 			// Still refresh UI even if commands failed, so user sees current state
 			if after != nil {
 				return after()
@@ -4671,6 +5836,7 @@ func (m *Model) clearPendingTrust() {
 	m.pendingCmdCwd = ""
 	m.pendingAfter = nil
 	m.pendingTrust = ""
+	m.pendingTrustAction = nil
 	m.trustScreen = nil
 }
 
@@ -4688,7 +5854,12 @@ func (m *Model) ensureRepoConfig() {
 		return
 	}
 	m.repoConfigPath = cfgPath
+	if repoCfg != nil && repoCfg.Path == "" {
+		// Only the team configuration exists.
+		m.repoConfigPath = ""
+	}
 	m.repoConfig = repoCfg
+	m.loadInfoTemplate()
 }
 
 // UpdateTheme updates the application theme and refreshes component styles.
@@ -5154,12 +6325,30 @@ func (m *Model) applyLogFilter(reset bool) {
 		if len(sha) > 7 {
 			sha = sha[:7]
 		}
-		msg := formatCommitMessage(entry.message)
+		badge, msg := m.formatLogSubject(entry.message)
 		if entry.isUnpushed {
-			msg = lipgloss.NewStyle().Foreground(m.theme.WarnFg).Render("⬆ " + msg)
+			msg = lipgloss.NewStyle().Foreground(m.theme.WarnFg).Render(msg)
 		} else if entry.isUnmerged {
 			msg = lipgloss.NewStyle().Foreground(m.theme.Accent).Render(msg)
 		}
+		if badge != "" {
+			msg = badge + " " + msg
+		}
+		switch {
+		case entry.isUnpushed:
+			msg = lipgloss.NewStyle().Foreground(m.theme.WarnFg).Render("⬆ ") + msg
+		case entry.isUnmerged:
+			// A shape as well as the colour, for colour-blind users.
+			msg = lipgloss.NewStyle().Foreground(m.theme.Accent).Render("◇ ") + msg
+		}
+		if m.absoluteDates {
+			date := ""
+			if !entry.date.IsZero() {
+				date = entry.date.Format(m.dateLayout())
+			}
+			rows = append(rows, table.Row{sha, date, entry.authorInitials, msg})
+			continue
+		}
 		rows = append(rows, table.Row{sha, entry.authorInitials, msg})
 	}
 	m.logTable.SetRows(rows)
@@ -5317,7 +6506,10 @@ func (m *Model) advanceSearchMatch(forward bool) tea.Cmd {
 // rebuildStatusContentWithHighlight re-renders the status content with current selection highlighted.
 func (m *Model) rebuildStatusContentWithHighlight() {
 	m.statusContent = m.renderStatusFiles()
-	m.statusViewport.SetContent(m.statusContent)
+	if m.previewMode || m.showingInitOutput() {
+		return
+	}
+	m.setStatusViewportContent(m.statusContent)
 
 	if len(m.statusTreeFlat) == 0 {
 		return
@@ -1,6 +1,7 @@
 package app
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
@@ -17,6 +18,7 @@ import (
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/chmouel/lazyworktree/internal/config"
+	"github.com/chmouel/lazyworktree/internal/git"
 	"github.com/chmouel/lazyworktree/internal/models"
 	"github.com/chmouel/lazyworktree/internal/theme"
 	"github.com/chmouel/lazyworktree/internal/utils"
@@ -498,14 +500,15 @@ func TestShowCommandPaletteHasAllActions(t *testing.T) {
 	m.showCommandPalette()
 
 	expectedIDs := []string{
-		"create", "delete", "rename", "absorb", "prune",
+		"create", "delete", "rename", "absorb", "sync-my-prs", "prune", "adopt",
 		"create-from-current", "create-from-branch", "create-from-commit",
-		"create-from-pr", "create-from-issue", "create-freeform",
-		"diff", "refresh", "fetch", "push", "sync", "fetch-pr-data", "pr", "lazygit", "run-command",
+		"create-from-pr", "create-from-issue", "create-from-description", "create-from-clipboard", "create-from-stash", "create-from-patch", "create-freeform", "create-default-branch",
+		"recently-deleted", "suggested-branches", "services", "service-ports", "compose", "allow-env-tools", "bootstrap", "sync-artifacts",
+		"diff", "fix-whitespace", "refresh", "fetch", "fetch-branch", "switch-remote-protocol", "push", "sync", "pull", "force-push", "restack", "fetch-pr-data", "pr", "pr-description", "open-deployment", "pr-toggle-draft", "ci-findings", "pr-request-reviewers", "lazygit", "run-command", "record-macro", "changelog",
 		"stage-file", "commit-staged", "commit-all", "edit-file", "delete-file",
 		"cherry-pick", "commit-view",
-		"zoom-toggle", "filter", "search", "focus-worktrees", "focus-status", "focus-log", "sort-cycle",
-		"theme", "help",
+		"zoom-toggle", "toggle-preview", "filter", "focus-mode", "filter-mine", "search", "find-all-repos", "focus-worktrees", "focus-status", "focus-log", "sort-cycle", "sort-reverse", "toggle-dates", "pin-worktree", "move-worktree-up", "move-worktree-down",
+		"theme", "help", "about", "show-errors",
 	}
 
 	itemIDs := make(map[string]bool)
@@ -544,6 +547,33 @@ func TestRenderFooterIncludesCustomHelpHints(t *testing.T) {
 	}
 }
 
+func TestRenderFooterShowsQueueDepth(t *testing.T) {
+	cfg := &config.AppConfig{WorktreeDir: t.TempDir(), NetworkConcurrency: 1}
+	m := NewModel(cfg, "")
+	m.windowWidth = 200
+	m.windowHeight = 50
+	layout := m.computeLayout()
+
+	if footer := m.renderFooter(layout); strings.Contains(footer, "queued") {
+		t.Fatalf("expected no queue depth while idle, got %q", footer)
+	}
+
+	release := m.git.Acquire(context.Background(), git.PoolNetwork)
+	acquired := make(chan func())
+	go func() { acquired <- m.git.Acquire(context.Background(), git.PoolNetwork) }()
+	deadline := time.Now().Add(time.Second)
+	for m.git.QueueDepth().Network == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	footer := m.renderFooter(layout)
+	release()
+	(<-acquired)()
+	if !strings.Contains(footer, "queued: net 1") {
+		t.Fatalf("expected footer to show the queued network call, got %q", footer)
+	}
+}
+
 func TestPagerCommandFallbacksToLess(t *testing.T) {
 	if runtime.GOOS == osWindows {
 		t.Skip("pager fallback test relies on unix-like PATH lookup")
@@ -1639,6 +1669,42 @@ func TestRunCommandsWithTrustTofu(t *testing.T) {
 	}
 }
 
+func TestRunCommandsWithTrustTeamConfig(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	cfg := &config.AppConfig{
+		WorktreeDir: t.TempDir(),
+	}
+	m := NewModel(cfg, "")
+
+	dir := t.TempDir()
+	localPath := filepath.Join(dir, ".wt")
+	teamPath := filepath.Join(dir, "team.wt")
+	for _, path := range []string{localPath, teamPath} {
+		if err := os.WriteFile(path, []byte("init_commands: []"), 0o600); err != nil {
+			t.Fatalf("write trust file: %v", err)
+		}
+	}
+	m.repoConfigPath = localPath
+	m.repoConfig = &config.RepoConfig{Path: localPath, TeamPath: teamPath}
+
+	if cmd := m.runCommandsWithTrust([]string{"echo hi"}, "", nil, nil); cmd != nil {
+		t.Fatal("expected no command for trust prompt")
+	}
+	if m.pendingTrust != localPath {
+		t.Fatalf("expected the .wt to be reviewed first, got %q", m.pendingTrust)
+	}
+
+	_, cmd := m.handleScreenKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'t'}})
+	if cmd != nil || m.currentScreen != screenTrust || m.pendingTrust != teamPath {
+		t.Fatalf("expected the team configuration to be reviewed next, got %q", m.pendingTrust)
+	}
+
+	_, cmd = m.handleScreenKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'t'}})
+	if cmd == nil || m.currentScreen != screenNone {
+		t.Fatal("expected the commands to run once both files are trusted")
+	}
+}
+
 func TestClearPendingTrust(t *testing.T) {
 	cfg := &config.AppConfig{
 		WorktreeDir: t.TempDir(),
@@ -2071,17 +2137,17 @@ func TestRenderScreenVariants(t *testing.T) {
 	}
 }
 
-func TestErrMsgShowsInfo(t *testing.T) {
+func TestErrMsgShowsErrorScreen(t *testing.T) {
 	cfg := &config.AppConfig{WorktreeDir: t.TempDir()}
 	m := NewModel(cfg, "")
 
 	_, _ = m.Update(errMsg{err: errors.New("boom")})
 
-	if m.currentScreen != screenInfo {
-		t.Fatalf("expected info screen, got %v", m.currentScreen)
+	if m.currentScreen != screenError {
+		t.Fatalf("expected error screen, got %v", m.currentScreen)
 	}
-	if m.infoScreen == nil || !strings.Contains(m.infoScreen.message, "boom") {
-		t.Fatalf("expected info modal to include error, got %#v", m.infoScreen)
+	if m.errorScreen == nil || !strings.Contains(m.errorScreen.View(), "boom") {
+		t.Fatalf("expected error screen to include error, got %#v", m.errorScreen)
 	}
 }
 
@@ -2959,6 +3025,29 @@ func TestFilterWorktreeEnvVars(t *testing.T) {
 	}
 }
 
+func TestTruncateToHeight(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		maxLines int
+		expected string
+	}{
+		{name: "fewer lines than max", input: "line1\nline2", maxLines: 5, expected: "line1\nline2"},
+		{name: "exactly max lines", input: "line1\nline2\nline3", maxLines: 3, expected: "line1\nline2\nline3"},
+		{name: "more lines than max", input: "line1\nline2\nline3\nline4", maxLines: 2, expected: "line1\nline2"},
+		{name: "trailing newline", input: "line1\nline2\n", maxLines: 2, expected: "line1\nline2"},
+		{name: "empty lines kept", input: "\n\nline3", maxLines: 2, expected: "\n"},
+		{name: "maxLines zero", input: "line1", maxLines: 0, expected: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := truncateToHeight(tt.input, tt.maxLines); got != tt.expected {
+				t.Errorf("truncateToHeight() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
 func TestTruncateToHeightFromEnd(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -3101,3 +3190,56 @@ func TestRenderZoomedRightBottomPane(t *testing.T) {
 		t.Error("expected render to contain 'Log' title")
 	}
 }
+
+func TestNoColorAndNoAnimations(t *testing.T) {
+	cfg := &config.AppConfig{WorktreeDir: t.TempDir(), GitPager: "sh"}
+	m := NewModel(cfg, "")
+	if !m.animationsEnabled() || m.gitColorMode() != "always" || !m.git.UseGitPager() {
+		t.Fatal("expected animations, colour and the git pager by default")
+	}
+
+	cfg = &config.AppConfig{WorktreeDir: t.TempDir(), GitPager: "sh", NoColor: true, NoAnimations: true}
+	m = NewModel(cfg, "")
+	if m.animationsEnabled() {
+		t.Error("expected animations to be disabled")
+	}
+	if m.gitColorMode() != "never" {
+		t.Errorf("expected --color=never, got %q", m.gitColorMode())
+	}
+	if m.git.UseGitPager() {
+		t.Error("expected the git pager to be skipped with NO_COLOR")
+	}
+}
+
+func TestCrashState(t *testing.T) {
+	m := NewModel(&config.AppConfig{WorktreeDir: t.TempDir()}, "")
+	m.repoKey = testRepoKey
+	m.worktrees = []*models.WorktreeInfo{{Path: "/tmp/main", Branch: "main", IsMain: true}, {Path: "/tmp/feature", Branch: "feature"}}
+	m.updateTable()
+	m.currentScreen = screenHelp
+
+	state := m.CrashState()
+	for _, want := range []string{"repo: " + testRepoKey, "screen: help", "worktrees: 2 (2 shown)", "selected: 0 /tmp/"} {
+		if !strings.Contains(state, want) {
+			t.Fatalf("expected %q in crash state:\n%s", want, state)
+		}
+	}
+}
+
+func TestAboutScreen(t *testing.T) {
+	m := NewModel(&config.AppConfig{WorktreeDir: t.TempDir()}, "")
+	if cmd := m.showAbout(); cmd == nil {
+		t.Fatal("expected a command gathering diagnostics")
+	}
+
+	m.handleAboutReady(aboutReadyMsg{report: "version:  v1.0.0\n"})
+	if m.currentScreen != screenMarkdown || m.markdownScreen == nil {
+		t.Fatalf("expected the markdown viewer, got screen %v", m.currentScreen)
+	}
+	if m.markdownScreen.copyText != "version:  v1.0.0\n" {
+		t.Fatalf("expected the report to be copyable, got %q", m.markdownScreen.copyText)
+	}
+	if !strings.Contains(m.markdownScreen.View(), "y: copy") {
+		t.Fatal("expected a copy hint in the footer")
+	}
+}
@@ -0,0 +1,101 @@
+package app
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/chmouel/lazyworktree/internal/artifacts"
+)
+
+// artifactsSyncedMsg reports build artifacts brought into a worktree.
+type artifactsSyncedMsg struct {
+	path   string
+	result artifacts.Result
+	err    error
+}
+
+func (m *Model) artifactsPath() string {
+	return artifacts.Path(m.getRepoKey())
+}
+
+// artifactSpecs returns the configured build outputs to sync.
+func (m *Model) artifactSpecs() []artifacts.Spec {
+	specs := make([]artifacts.Spec, 0, len(m.config.ArtifactSync))
+	for _, spec := range m.config.ArtifactSync {
+		specs = append(specs, artifacts.Spec{Path: spec.Path, Link: spec.Link})
+	}
+	return specs
+}
+
+// syncArtifacts copies or links the configured build outputs from the main
+// worktree into the worktree at path, replacing those an earlier sync
+// brought, and records them so deleting the worktree removes them.
+func (m *Model) syncArtifacts(path string) tea.Cmd {
+	if len(m.config.ArtifactSync) == 0 || m.config.ReadOnly {
+		return nil
+	}
+	mainPath := m.git.GetMainWorktreePath(m.ctx)
+	if mainPath == "" || filepath.Clean(mainPath) == filepath.Clean(path) {
+		return nil
+	}
+	specs := m.artifactSpecs()
+	manifest := m.artifactsPath()
+	return func() tea.Msg {
+		previous := artifacts.Load(manifest)[path]
+		result, err := artifacts.Sync(mainPath, path, specs, previous)
+		if recErr := artifacts.Record(manifest, path, result.Synced); err == nil && recErr != nil {
+			err = fmt.Errorf("failed to record synced artifacts: %w", recErr)
+		}
+		return artifactsSyncedMsg{path: path, result: result, err: err}
+	}
+}
+
+// handleArtifactsSynced reports what a sync brought and what was missing.
+func (m *Model) handleArtifactsSynced(msg artifactsSyncedMsg) tea.Cmd {
+	name := filepath.Base(msg.path)
+	if msg.err != nil {
+		m.statusContent = fmt.Sprintf("Artifact sync into %s failed: %v", name, msg.err)
+		return nil
+	}
+	parts := []string{fmt.Sprintf("Synced %d build artifact(s) into %s", len(msg.result.Synced), name)}
+	if len(msg.result.Missing) > 0 {
+		parts = append(parts, "not built in the main worktree: "+strings.Join(msg.result.Missing, ", "))
+	}
+	if len(msg.result.Skipped) > 0 {
+		parts = append(parts, "already present: "+strings.Join(msg.result.Skipped, ", "))
+	}
+	m.statusContent = strings.Join(parts, "; ")
+	return nil
+}
+
+// showSyncArtifacts refreshes the selected worktree's build artifacts from
+// the main worktree.
+func (m *Model) showSyncArtifacts() tea.Cmd {
+	if m.readOnlyDenied("Syncing build artifacts") {
+		return nil
+	}
+	wt := m.selectedWorktree()
+	if wt == nil {
+		return nil
+	}
+	if len(m.config.ArtifactSync) == 0 {
+		m.showInfo("No build artifacts are configured.\n\nList the build outputs to bring from the main worktree under artifact_sync, such as gen/proto or web/dist.", nil)
+		return nil
+	}
+	if wt.IsMain || wt.Bare || wt.Prunable {
+		m.showInfo(fmt.Sprintf("%s has no artifacts to sync: they come from the main worktree.", filepath.Base(wt.Path)), nil)
+		return nil
+	}
+	m.statusContent = fmt.Sprintf("Syncing build artifacts into %s...", filepath.Base(wt.Path))
+	return m.syncArtifacts(wt.Path)
+}
+
+// cleanArtifacts removes the build artifacts synced into a worktree being
+// deleted.
+func (m *Model) cleanArtifacts(path string) {
+	if err := artifacts.Forget(m.artifactsPath(), path); err != nil {
+		m.debugf("artifacts: %v", err)
+	}
+}
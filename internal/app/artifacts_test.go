@@ -0,0 +1,59 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/chmouel/lazyworktree/internal/config"
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+func TestSyncArtifacts(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	repo := t.TempDir()
+	runGit(t, repo, "init", "-b", "main")
+	runGit(t, repo, "config", "user.email", "test@example.com")
+	runGit(t, repo, "config", "user.name", "Test User")
+	runGit(t, repo, "config", "commit.gpgsign", "false")
+	writeRepoFile(t, repo, "README.md", "readme\n")
+	runGit(t, repo, "add", ".")
+	runGit(t, repo, "commit", "-m", "Initial commit")
+	writeRepoFile(t, repo, "gen/api.pb.go", "package api\n")
+	wtPath := filepath.Join(t.TempDir(), "feature")
+	runGit(t, repo, "worktree", "add", "-q", "-b", "feature", wtPath)
+	t.Chdir(repo)
+
+	cfg := &config.AppConfig{WorktreeDir: t.TempDir()}
+	m := NewModel(cfg, "")
+	wt := &models.WorktreeInfo{Path: wtPath, Branch: "feature"}
+	m.worktrees = []*models.WorktreeInfo{wt, {Path: repo, Branch: "main", IsMain: true}}
+	m.filteredWts = m.worktrees
+
+	if m.showSyncArtifacts() != nil || m.currentScreen != screenInfo {
+		t.Fatal("expected to be told no artifacts are configured")
+	}
+	m.currentScreen = screenNone
+
+	cfg.ArtifactSync = []*config.ArtifactSync{{Path: "gen"}, {Path: "web/dist"}}
+	msg, ok := m.showSyncArtifacts()().(artifactsSyncedMsg)
+	if !ok || msg.err != nil {
+		t.Fatalf("expected the artifacts to sync, got %+v", msg)
+	}
+	m.handleArtifactsSynced(msg)
+	if !strings.Contains(m.statusContent, "Synced 1 build artifact(s) into feature") || !strings.Contains(m.statusContent, "not built in the main worktree: web/dist") {
+		t.Fatalf("unexpected status %q", m.statusContent)
+	}
+	if _, err := os.Stat(filepath.Join(wtPath, "gen", "api.pb.go")); err != nil {
+		t.Fatalf("expected the artifact to be copied: %v", err)
+	}
+
+	m.cleanArtifacts(wtPath)
+	if _, err := os.Stat(filepath.Join(wtPath, "gen")); !os.IsNotExist(err) {
+		t.Fatal("expected deleting the worktree to remove its artifacts")
+	}
+	if _, err := os.Stat(filepath.Join(repo, "gen", "api.pb.go")); err != nil {
+		t.Fatal("expected the main worktree's artifacts to stay")
+	}
+}
@@ -50,6 +50,27 @@ func (m *Model) autoRefreshTick() tea.Cmd {
 	})
 }
 
+// backgroundRefresh rescans the worktrees on an auto refresh tick, so
+// commits and edits made from other terminals show without pressing r.
+// It waits while a dialogue is open or an operation runs, and leaves a
+// scan already under way to finish.
+func (m *Model) backgroundRefresh() tea.Cmd {
+	if m.currentScreen != screenNone || m.loading || m.refreshRunning || m.refreshScheduled {
+		return nil
+	}
+	m.refreshRunning = true
+	m.git.InvalidateRemoteCache()
+	scan := m.refreshWorktrees()
+	return func() tea.Msg {
+		msg := scan()
+		if loaded, ok := msg.(worktreesLoadedMsg); ok {
+			loaded.background = true
+			return loaded
+		}
+		return msg
+	}
+}
+
 func (m *Model) refreshDetails() tea.Cmd {
 	if len(m.filteredWts) == 0 {
 		return nil
@@ -84,6 +105,9 @@ func (m *Model) startGitWatcher() tea.Cmd {
 	m.gitWatchEvents = make(chan struct{}, 1)
 	m.gitWatchDone = make(chan struct{})
 	m.gitWatchPaths = make(map[string]struct{})
+	m.gitWatchWorktrees = make(map[string][]string)
+	m.gitWatchOwners = make(map[string]string)
+	m.gitWatchGitDirs = make(map[string]string)
 	m.gitWatchRoots = []string{
 		filepath.Join(commonDir, "refs"),
 		filepath.Join(commonDir, "logs"),
@@ -139,6 +163,7 @@ func (m *Model) runGitWatcher() {
 			if event.Op&fsnotify.Create != 0 {
 				m.maybeWatchNewDir(event.Name)
 			}
+			m.noteGitWatchEvent(event.Name)
 			m.signalGitWatch()
 		case err, ok := <-m.gitWatcher.Errors:
 			if !ok {
@@ -221,3 +221,74 @@ func TestShouldRefreshGitEventDebounce(t *testing.T) {
 		t.Fatal("expected refresh after debounce window")
 	}
 }
+
+func TestRefreshWorktreesSupersedesInFlight(t *testing.T) {
+	cfg := &config.AppConfig{WorktreeDir: t.TempDir()}
+	m := NewModel(cfg, "")
+
+	first := m.refreshWorktrees()
+	second := m.refreshWorktrees()
+	if msg := first(); msg != nil {
+		t.Fatalf("expected the superseded refresh to yield nothing, got %T", msg)
+	}
+	msg, ok := second().(worktreesLoadedMsg)
+	if !ok || msg.refreshGen != m.refreshGen {
+		t.Fatalf("expected the latest refresh to report generation %d, got %#v", m.refreshGen, msg)
+	}
+
+	current := []*models.WorktreeInfo{{Path: "/tmp/current", Branch: "current"}}
+	m.worktrees = current
+	_, _ = m.Update(worktreesLoadedMsg{
+		worktrees:  []*models.WorktreeInfo{{Path: "/tmp/stale", Branch: "stale"}},
+		refreshGen: m.refreshGen - 1,
+	})
+	if len(m.worktrees) != 1 || m.worktrees[0].Path != "/tmp/current" {
+		t.Fatalf("expected a stale refresh not to replace worktrees, got %+v", m.worktrees)
+	}
+}
+
+func TestCloseWaitsForCancelledRefresh(t *testing.T) {
+	cfg := &config.AppConfig{WorktreeDir: t.TempDir()}
+	m := NewModel(cfg, "")
+	cmd := m.refreshWorktrees()
+
+	// Quitting cancels the model context before the refresh gets to run.
+	m.cancel()
+	if msg := cmd(); msg != nil {
+		t.Fatalf("expected a cancelled refresh to yield nothing, got %T", msg)
+	}
+
+	start := time.Now()
+	m.Close()
+	if elapsed := time.Since(start); elapsed >= closeWaitTimeout {
+		t.Fatalf("expected Close to return once the refresh finished, took %s", elapsed)
+	}
+}
+
+func TestBackgroundRefresh(t *testing.T) {
+	cfg := &config.AppConfig{WorktreeDir: t.TempDir()}
+	m := NewModel(cfg, "")
+	m.worktrees = []*models.WorktreeInfo{{Path: "/tmp/current", Branch: "current"}}
+
+	m.currentScreen = screenInput
+	if m.backgroundRefresh() != nil {
+		t.Fatal("expected no rescan while a dialogue is open")
+	}
+	m.currentScreen = screenNone
+	m.loading = false
+	scan := m.backgroundRefresh()
+	if scan == nil || !m.refreshRunning || m.backgroundRefresh() != nil {
+		t.Fatal("expected a single rescan when idle")
+	}
+	msg, ok := scan().(worktreesLoadedMsg)
+	if !ok || !msg.background {
+		t.Fatalf("expected a background scan result, got %#v", msg)
+	}
+
+	m.loading = true
+	m.currentScreen = screenLoading
+	_, _ = m.Update(worktreesLoadedMsg{err: os.ErrNotExist, refreshGen: m.refreshGen, background: true})
+	if m.currentScreen != screenLoading || m.refreshRunning {
+		t.Fatalf("expected a failed background scan to leave the screen alone, got %v", m.currentScreen)
+	}
+}
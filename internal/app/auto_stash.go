@@ -0,0 +1,165 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+// autoStashMarker starts the message of every stash made when switching
+// away from a dirty worktree; the worktree's path ends it.
+const autoStashMarker = "lazyworktree auto-stash"
+
+// autoStashDoneMsg reports the stash of the worktree being left.
+type autoStashDoneMsg struct {
+	target string
+	err    error
+}
+
+// autoStashFoundMsg carries the newest auto-stash of the worktree being
+// entered, empty when it has none.
+type autoStashFoundMsg struct {
+	target string
+	ref    string
+}
+
+// autoStashPoppedMsg reports the restore of an auto-stash.
+type autoStashPoppedMsg struct {
+	target string
+	ref    string
+	ok     bool
+}
+
+// autoStashMessage describes the stash of wt made when switching to target.
+func autoStashMessage(wt *models.WorktreeInfo, target string) string {
+	return fmt.Sprintf("%s of %s when switching to %s: %s", autoStashMarker, filepath.Base(wt.Path), filepath.Base(target), wt.Path)
+}
+
+// isAutoStashOf reports whether a stash subject, as shown by git stash
+// list, is an auto-stash of the worktree at path.
+func isAutoStashOf(subject, path string) bool {
+	return strings.Contains(subject, autoStashMarker+" ") && strings.HasSuffix(subject, ": "+path)
+}
+
+// cwdWorktree returns the worktree lazyworktree was started from, if any.
+func (m *Model) cwdWorktree() *models.WorktreeInfo {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil
+	}
+	var found *models.WorktreeInfo
+	for _, wt := range m.worktrees {
+		if cwd != wt.Path && !strings.HasPrefix(cwd, wt.Path+string(filepath.Separator)) {
+			continue
+		}
+		if found == nil || len(wt.Path) > len(found.Path) {
+			found = wt
+		}
+	}
+	return found
+}
+
+// switchToWorktree jumps to the worktree at target. With auto_stash, it
+// first offers to stash the dirty worktree being left, then to restore
+// the changes stashed when target was last left.
+func (m *Model) switchToWorktree(target string) tea.Cmd {
+	if m.config == nil || !m.config.AutoStash || m.config.ReadOnly {
+		return m.jumpToWorktree(target)
+	}
+	current := m.cwdWorktree()
+	if current == nil || current.Path == target || !current.Dirty {
+		return m.findAutoStash(target)
+	}
+
+	m.confirmScreen = NewConfirmScreen(
+		fmt.Sprintf("%s has uncommitted changes.\n\nStash them before switching to %s?\n(They will be offered back when you return.)", filepath.Base(current.Path), filepath.Base(target)),
+		m.theme,
+	)
+	m.confirmAction = func() tea.Cmd {
+		path := current.Path
+		message := autoStashMessage(current, target)
+		return func() tea.Msg {
+			if !m.git.RunCommandChecked(m.ctx, []string{"git", "stash", "push", "-u", "-m", message}, path, "Failed to stash changes") {
+				return autoStashDoneMsg{target: target, err: fmt.Errorf("failed to stash the changes in %s", filepath.Base(path))}
+			}
+			return autoStashDoneMsg{target: target}
+		}
+	}
+	m.confirmCancel = func() tea.Cmd {
+		return m.findAutoStash(target)
+	}
+	m.currentScreen = screenConfirm
+	return nil
+}
+
+// handleAutoStashDone carries on switching once the worktree left behind
+// is stashed.
+func (m *Model) handleAutoStashDone(msg autoStashDoneMsg) tea.Cmd {
+	if msg.err != nil {
+		m.showInfo(fmt.Sprintf("Error: %v\n\nThe switch was cancelled.", msg.err), nil)
+		return nil
+	}
+	return m.findAutoStash(msg.target)
+}
+
+// findAutoStash looks for changes stashed when target was last left.
+func (m *Model) findAutoStash(target string) tea.Cmd {
+	return func() tea.Msg {
+		out := m.git.RunGit(m.ctx, []string{"git", "stash", "list", "--format=%gd%x09%gs"}, target, []int{0}, true, true)
+		for line := range strings.SplitSeq(out, "\n") {
+			ref, subject, ok := strings.Cut(line, "\t")
+			if ok && isAutoStashOf(subject, target) {
+				return autoStashFoundMsg{target: target, ref: ref}
+			}
+		}
+		return autoStashFoundMsg{target: target}
+	}
+}
+
+// handleAutoStashFound offers to restore an auto-stash before jumping to
+// its worktree.
+func (m *Model) handleAutoStashFound(msg autoStashFoundMsg) tea.Cmd {
+	if msg.ref == "" {
+		return m.jumpToWorktree(msg.target)
+	}
+	name := filepath.Base(msg.target)
+	m.confirmScreen = NewConfirmScreen(
+		fmt.Sprintf("Changes were stashed when you last left %s.\n\nRestore them now?\n(Stash: %s)", name, msg.ref),
+		m.theme,
+	)
+	m.confirmAction = func() tea.Cmd {
+		target, ref := msg.target, msg.ref
+		return func() tea.Msg {
+			ok := m.git.RunCommandChecked(m.ctx, []string{"git", "stash", "pop", ref}, target, "Failed to restore stash")
+			return autoStashPoppedMsg{target: target, ref: ref, ok: ok}
+		}
+	}
+	m.confirmCancel = func() tea.Cmd {
+		return m.jumpToWorktree(msg.target)
+	}
+	m.currentScreen = screenConfirm
+	return nil
+}
+
+// handleAutoStashPopped jumps to the worktree once its changes are back,
+// or stays to explain why they are not.
+func (m *Model) handleAutoStashPopped(msg autoStashPoppedMsg) tea.Cmd {
+	if !msg.ok {
+		m.showInfo(fmt.Sprintf("The changes stashed in %s could not be restored cleanly.\n\nResolve any conflicts there; the stash is kept as %s until it applies.", filepath.Base(msg.target), msg.ref), nil)
+		return nil
+	}
+	return m.jumpToWorktree(msg.target)
+}
+
+// jumpToWorktree records the worktree at path as the one to switch to and
+// exits.
+func (m *Model) jumpToWorktree(path string) tea.Cmd {
+	m.persistLastSelected(path)
+	m.selectedPath = path
+	m.stopGitWatcher()
+	return tea.Quit
+}
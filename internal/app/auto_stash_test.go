@@ -0,0 +1,141 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/chmouel/lazyworktree/internal/config"
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+// confirmAutoStash answers the pending confirmation and feeds the resulting
+// messages back until the model stops or jumps.
+func confirmAutoStash(t *testing.T, m *Model, yes bool) tea.Cmd {
+	t.Helper()
+	if m.currentScreen != screenConfirm {
+		t.Fatalf("expected a confirmation, got %s", screenName(m.currentScreen))
+	}
+	action := m.confirmCancel
+	if yes {
+		action = m.confirmAction
+	}
+	m.confirmScreen, m.confirmAction, m.confirmCancel = nil, nil, nil
+	m.currentScreen = screenNone
+	return runAutoStash(t, m, action())
+}
+
+func runAutoStash(t *testing.T, m *Model, cmd tea.Cmd) tea.Cmd {
+	t.Helper()
+	for cmd != nil {
+		switch msg := cmd().(type) {
+		case autoStashDoneMsg:
+			cmd = m.handleAutoStashDone(msg)
+		case autoStashFoundMsg:
+			cmd = m.handleAutoStashFound(msg)
+		case autoStashPoppedMsg:
+			cmd = m.handleAutoStashPopped(msg)
+		case tea.QuitMsg:
+			return cmd
+		default:
+			t.Fatalf("unexpected message %T", msg)
+		}
+	}
+	return nil
+}
+
+func TestAutoStashOnSwitchAndRestoreOnReturn(t *testing.T) {
+	repo := initTestRepo(t)
+	mainPath, err := filepath.EvalSymlinks(repo.dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherPath := filepath.Join(filepath.Dir(mainPath), "other")
+	runGit(t, mainPath, "worktree", "add", otherPath, featureBranch)
+	writeRepoFile(t, mainPath, "file.txt", "work in progress\n")
+	writeRepoFile(t, mainPath, "notes.txt", "untracked\n")
+
+	newModel := func(dirtyMain bool) *Model {
+		m := NewModel(&config.AppConfig{WorktreeDir: t.TempDir(), AutoStash: true}, "")
+		m.worktrees = []*models.WorktreeInfo{
+			{Path: mainPath, Branch: repo.branch, IsMain: true, Dirty: dirtyMain},
+			{Path: otherPath, Branch: featureBranch},
+		}
+		return m
+	}
+
+	withCwd(t, mainPath)
+	m := newModel(true)
+	if cmd := m.switchToWorktree(otherPath); cmd != nil {
+		t.Fatal("expected to be asked about the uncommitted changes first")
+	}
+	if cmd := confirmAutoStash(t, m, true); cmd == nil || m.selectedPath != otherPath {
+		t.Fatalf("expected to switch to %s, got %q", otherPath, m.selectedPath)
+	}
+	if status := runGit(t, mainPath, "status", "--porcelain"); status != "" {
+		t.Fatalf("expected the main worktree to be clean, got %q", status)
+	}
+	if subject := runGit(t, mainPath, "stash", "list", "--format=%gs"); !isAutoStashOf(subject, mainPath) {
+		t.Fatalf("unexpected stash %q", subject)
+	}
+
+	withCwd(t, otherPath)
+	m = newModel(false)
+	if cmd := runAutoStash(t, m, m.switchToWorktree(mainPath)); cmd != nil {
+		t.Fatal("expected to be offered the stash back")
+	}
+	if !strings.Contains(m.confirmScreen.message, "Restore them now?") {
+		t.Fatalf("unexpected prompt %q", m.confirmScreen.message)
+	}
+	if cmd := confirmAutoStash(t, m, true); cmd == nil || m.selectedPath != mainPath {
+		t.Fatalf("expected to switch to %s, got %q", mainPath, m.selectedPath)
+	}
+	content, err := os.ReadFile(filepath.Join(mainPath, "file.txt"))
+	if err != nil || string(content) != "work in progress\n" {
+		t.Fatalf("expected the changes back, got %q (%v)", content, err)
+	}
+	if _, err := os.Stat(filepath.Join(mainPath, "notes.txt")); err != nil {
+		t.Fatalf("expected the untracked file back: %v", err)
+	}
+	if list := runGit(t, mainPath, "stash", "list"); list != "" {
+		t.Fatalf("expected the stash to be dropped, got %q", list)
+	}
+}
+
+func TestAutoStashDeclinedStillSwitches(t *testing.T) {
+	repo := initTestRepo(t)
+	mainPath, err := filepath.EvalSymlinks(repo.dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	withCwd(t, mainPath)
+	other := filepath.Join(t.TempDir(), "other")
+	m := NewModel(&config.AppConfig{WorktreeDir: t.TempDir(), AutoStash: true}, "")
+	m.worktrees = []*models.WorktreeInfo{{Path: mainPath, Dirty: true}, {Path: other}}
+
+	_ = m.switchToWorktree(other)
+	if cmd := confirmAutoStash(t, m, false); cmd == nil || m.selectedPath != other {
+		t.Fatalf("expected to switch without stashing, got %q", m.selectedPath)
+	}
+}
+
+func TestAutoStashDisabled(t *testing.T) {
+	m := NewModel(&config.AppConfig{WorktreeDir: t.TempDir()}, "")
+	m.worktrees = []*models.WorktreeInfo{{Path: "/repo", Dirty: true}}
+	if cmd := m.switchToWorktree("/repo-other"); cmd == nil || m.currentScreen == screenConfirm {
+		t.Fatal("expected to switch straight away")
+	}
+}
+
+func TestIsAutoStashOf(t *testing.T) {
+	wt := &models.WorktreeInfo{Path: "/work/repo/feature"}
+	subject := "On feature: " + autoStashMessage(wt, "/work/repo/main")
+	if !isAutoStashOf(subject, "/work/repo/feature") {
+		t.Fatalf("expected %q to match", subject)
+	}
+	if isAutoStashOf(subject, "/work/repo/main") || isAutoStashOf("On feature: wip: /work/repo/feature", "/work/repo/feature") {
+		t.Fatal("expected other stashes not to match")
+	}
+}
@@ -0,0 +1,67 @@
+package app
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+// bareDenied reports whether wt is a bare repository, which has no working
+// tree for action to act on, telling the user so.
+func (m *Model) bareDenied(wt *models.WorktreeInfo, action string) bool {
+	if wt == nil || !wt.Bare {
+		return false
+	}
+	m.showInfo(fmt.Sprintf("%s needs a working tree, which the bare repository has none of.\n\nPress Enter on it to check out the default branch in a worktree.", action), nil)
+	return true
+}
+
+// defaultBranchWorktree returns the worktree the default branch is checked
+// out in, if any.
+func (m *Model) defaultBranchWorktree(branch string) *models.WorktreeInfo {
+	for _, wt := range m.worktrees {
+		if !wt.Bare && wt.Branch == branch {
+			return wt
+		}
+	}
+	return nil
+}
+
+// enterBareRepository jumps to the worktree of the default branch, as the
+// bare repository has no files to jump into, creating it first when needed.
+func (m *Model) enterBareRepository() tea.Cmd {
+	if wt := m.defaultBranchWorktree(m.git.GetMainBranch(m.ctx)); wt != nil {
+		return m.switchToWorktree(wt.Path)
+	}
+	return m.createDefaultBranchWorktree()
+}
+
+// createDefaultBranchWorktree checks the default branch out in a worktree
+// of its own, the first step in a bare repository. The local branch is used
+// when there is one, otherwise a new one tracking the remote's.
+func (m *Model) createDefaultBranchWorktree() tea.Cmd {
+	if m.readOnlyDenied("Creating worktrees") {
+		return nil
+	}
+	branch := m.git.GetMainBranch(m.ctx)
+	if wt := m.defaultBranchWorktree(branch); wt != nil {
+		m.showInfo(fmt.Sprintf("%s is already checked out in %s.", branch, wt.Path), nil)
+		return nil
+	}
+	base := branch
+	if !m.baseRefExists("refs/heads/" + branch) {
+		base = "origin/" + branch
+		if !m.baseRefExists(base) {
+			m.showInfo(fmt.Sprintf("Cannot find the default branch %s, locally or on origin.", branch), nil)
+			return nil
+		}
+	}
+	targetPath, _, reason := m.newWorktreeTarget(branch, branch)
+	if reason != "" {
+		m.showInfo(reason, nil)
+		return nil
+	}
+	m.pendingSelectWorktreePath = targetPath
+	return m.createWorktreeFromBase(branch, targetPath, base)
+}
@@ -0,0 +1,51 @@
+package app
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/chmouel/lazyworktree/internal/config"
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+func TestBareRepositoryCreatesDefaultBranchWorktree(t *testing.T) {
+	repo := initTestRepo(t)
+	root := t.TempDir()
+	bare := filepath.Join(root, "repo.git")
+	runGit(t, root, "clone", "--bare", repo.dir, bare)
+	runGit(t, bare, "remote", "remove", "origin")
+	withCwd(t, bare)
+
+	m := NewModel(&config.AppConfig{WorktreeDir: t.TempDir()}, "")
+	m.repoKey = "bare-test"
+	m.worktrees = []*models.WorktreeInfo{{Path: bare, Branch: "(bare)", IsMain: true, Bare: true}}
+	m.filteredWts = m.worktrees
+	m.updateTable()
+
+	if cmd := m.pushToUpstream(); cmd != nil || m.currentScreen != screenInfo || !strings.Contains(m.infoScreen.message, "bare repository") {
+		t.Fatal("expected pushing the bare repository to be refused")
+	}
+	m.currentScreen = screenNone
+
+	_, cmd := m.handleEnterKey()
+	if cmd == nil || m.currentScreen != screenLoading {
+		t.Fatal("expected Enter to create a worktree for the default branch")
+	}
+	wtPath := filepath.Join(m.getRepoWorktreeDir(), repo.branch)
+	if m.pendingSelectWorktreePath != wtPath {
+		t.Fatalf("expected %s to be selected once created, got %q", wtPath, m.pendingSelectWorktreePath)
+	}
+	if msg, ok := cmd().(worktreesLoadedMsg); !ok || msg.err != nil || len(msg.worktrees) != 2 {
+		t.Fatalf("expected the worktrees to reload, got %#v", msg)
+	}
+	if got := runGit(t, wtPath, "branch", "--show-current"); got != repo.branch {
+		t.Fatalf("expected %s checked out, got %q", repo.branch, got)
+	}
+
+	m.worktrees = append(m.worktrees, &models.WorktreeInfo{Path: wtPath, Branch: repo.branch})
+	m.currentScreen = screenNone
+	if cmd := m.createDefaultBranchWorktree(); cmd != nil || !strings.Contains(m.infoScreen.message, "already checked out") {
+		t.Fatal("expected a second worktree for the default branch to be refused")
+	}
+}
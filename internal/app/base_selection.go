@@ -6,7 +6,7 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
-	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -14,6 +14,7 @@ import (
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/chmouel/lazyworktree/internal/config"
+	"github.com/chmouel/lazyworktree/internal/git"
 	"github.com/chmouel/lazyworktree/internal/utils"
 )
 
@@ -21,6 +22,11 @@ const (
 	commitListLimit = 25
 	originMain      = "origin/main"
 	originMaster    = "origin/master"
+
+	// remoteRefEagerLimit is how many remote branches the branch list loads
+	// up front; beyond it they are listed on request.
+	remoteRefEagerLimit = 200
+	loadRemotesID       = "\x00load-remotes"
 )
 
 type branchOption struct {
@@ -64,6 +70,9 @@ func (m *Model) showBaseSelection(defaultBase string) tea.Cmd {
 		{id: "commit-list", label: "Pick a base commit", description: "Choose a branch, then a commit"},
 		{id: "from-pr", label: "Create from PR/MR", description: "Create from a pull/merge request"},
 		{id: "from-issue", label: "Create from Issue", description: "Create from a GitHub/GitLab issue"},
+		{id: "from-clipboard", label: "Create from clipboard", description: "Use a copied branch name or PR/issue URL"},
+		{id: "from-stash", label: "Create from stash", description: "Move stashed work into a worktree of its own"},
+		{id: "from-patch", label: "Create from patch file", description: "Apply a diff or format-patch file to a new branch"},
 		{id: "freeform", label: "Enter base ref manually", description: "Type a branch or commit"},
 	}
 
@@ -102,6 +111,12 @@ func (m *Model) showBaseSelection(defaultBase string) tea.Cmd {
 			return m.showCreateFromPR()
 		case item.id == "from-issue":
 			return m.showCreateFromIssue()
+		case item.id == "from-clipboard":
+			return m.showCreateFromClipboard()
+		case item.id == "from-stash":
+			return m.showCreateFromStash()
+		case item.id == "from-patch":
+			return m.showCreateFromPatch(defaultBase)
 		case strings.HasPrefix(item.id, "custom-"):
 			idxStr := strings.TrimPrefix(item.id, "custom-")
 			var idx int
@@ -121,6 +136,9 @@ func (m *Model) showBaseSelection(defaultBase string) tea.Cmd {
 }
 
 func (m *Model) showFreeformBaseInput(defaultBase string) tea.Cmd {
+	if m.readOnlyDenied("Creating worktrees") {
+		return nil
+	}
 	m.clearListSelection()
 	m.inputScreen = NewInputScreen("Base ref", defaultBase, defaultBase, m.theme)
 	m.inputSubmit = func(baseVal string, checked bool) (tea.Cmd, bool) {
@@ -141,9 +159,13 @@ func (m *Model) showFreeformBaseInput(defaultBase string) tea.Cmd {
 }
 
 func (m *Model) showBranchSelection(title, placeholder, noResults, preferred string, onSelect func(string) tea.Cmd) tea.Cmd {
-	items := m.branchSelectionItems(preferred)
+	items := m.branchSelectionItems(false)
 	m.listScreen = NewListSelectionScreen(items, title, placeholder, noResults, m.windowWidth, m.windowHeight, preferred, m.theme)
 	m.listSubmit = func(item selectionItem) tea.Cmd {
+		if item.id == loadRemotesID {
+			m.listScreen.setItems(m.branchSelectionItems(true))
+			return nil
+		}
 		return onSelect(item.id)
 	}
 	m.currentScreen = screenListSelect
@@ -158,13 +180,15 @@ func stripRemotePrefix(branch string) string {
 }
 
 func (m *Model) showCommitSelection(baseBranch string) tea.Cmd {
+	if m.readOnlyDenied("Creating worktrees") {
+		return nil
+	}
 	raw := m.git.RunGit(
 		m.ctx,
 		[]string{
 			"git", "log",
 			fmt.Sprintf("--max-count=%d", commitListLimit),
-			"--date=short",
-			"--pretty=format:%H%x1f%h%x1f%ad%x1f%s",
+			"--pretty=format:%H%x1f%h%x1f%at%x1f%s",
 			baseBranch,
 		},
 		"",
@@ -173,6 +197,9 @@ func (m *Model) showCommitSelection(baseBranch string) tea.Cmd {
 		false,
 	)
 	commits := parseCommitOptions(raw)
+	for i := range commits {
+		commits[i].date = m.formatCommitDate(commits[i].date, commitListDateLayout)
+	}
 	items := buildCommitItems(commits)
 	commitLookup := make(map[string]commitOption, len(commits))
 	for _, commit := range commits {
@@ -197,7 +224,7 @@ func (m *Model) showCommitSelection(baseBranch string) tea.Cmd {
 		defaultName := ""
 		scriptErr := ""
 		if strings.TrimSpace(m.config.BranchNameScript) != "" && commitMessage != "" {
-			if generatedName, err := runBranchNameScript(m.ctx, m.config.BranchNameScript, commitMessage, "diff", "", "", ""); err != nil {
+			if generatedName, err := m.runBranchNameScript(commitMessage, "diff", "", "", ""); err != nil {
 				scriptErr = fmt.Sprintf("Branch name script error: %v", err)
 			} else if generatedName != "" {
 				defaultName = generatedName
@@ -229,12 +256,58 @@ func (m *Model) showCommitSelection(baseBranch string) tea.Cmd {
 }
 
 func (m *Model) showBranchNameInput(baseRef, defaultName string) tea.Cmd {
+	sparse := m.sparseCheckoutOffered()
+	return m.showNewBranchInput(baseRef, defaultName, sparse, func(newBranch, targetPath string, checked bool) tea.Cmd {
+		if checked && sparse {
+			return m.showSparseChoice(fmt.Sprintf("Sparse checkout for %s", newBranch), baseRef, nil, false, func(dirs []string) tea.Cmd {
+				return m.startCreateFromBase(newBranch, targetPath, baseRef, dirs)
+			})
+		}
+		return m.startCreateFromBase(newBranch, targetPath, baseRef, nil)
+	})
+}
+
+// showNewBranchInput asks for the name of a branch to start from baseRef,
+// checking it, its worktree path and the policy before handing them to
+// create. A name already taken is numbered, feature-2, feature-3 and so
+// on, unless ctrl+n turns that off; the final branch and path are shown
+// as the name is typed. With sparse set, the prompt offers a sparse
+// checkout checkbox.
+func (m *Model) showNewBranchInput(baseRef, defaultName string, sparse bool, create func(newBranch, targetPath string, checked bool) tea.Cmd) tea.Cmd {
 	m.clearListSelection()
+	branches := m.existingBranchNames()
+	if !m.branchExistsInWorktrees(baseRef) {
+		// Naming the branch after an existing base checks it out.
+		delete(branches, baseRef)
+	}
 	suggested := strings.TrimSpace(defaultName)
 	if suggested != "" {
 		suggested = m.suggestBranchName(suggested)
 	}
 	m.inputScreen = NewInputScreen("Create worktree: branch name", "feature/my-branch", suggested, m.theme)
+	if sparse {
+		m.inputScreen.SetCheckbox("Sparse checkout (choose directories)", false)
+	}
+	m.inputScreen.SetToggle("ctrl+n", "Number the name when taken", true)
+	screen := m.inputScreen
+	lastValue, lastNumbered, lastPreview := "", false, ""
+	screen.SetPreview(func(value string) string {
+		if strings.TrimSpace(value) == "" {
+			return ""
+		}
+		if value == lastValue && screen.toggleOn == lastNumbered && lastPreview != "" {
+			return lastPreview
+		}
+		lastValue, lastNumbered = value, screen.toggleOn
+		newBranch, targetPath, _, reason := m.freeWorktreeTarget(sanitizeBranchNameFromTitle(strings.TrimSpace(value), ""), branches, screen.toggleOn)
+		if reason != "" {
+			lastPreview = reason
+		} else {
+			lastPreview = fmt.Sprintf("→ %s in %s", newBranch, targetPath)
+		}
+		return lastPreview
+	})
+	offered := ""
 	m.inputSubmit = func(value string, checked bool) (tea.Cmd, bool) {
 		newBranch := strings.TrimSpace(value)
 		newBranch = sanitizeBranchNameFromTitle(newBranch, "")
@@ -243,28 +316,45 @@ func (m *Model) showBranchNameInput(baseRef, defaultName string) tea.Cmd {
 			return nil, false
 		}
 
-		targetPath := filepath.Join(m.getRepoWorktreeDir(), newBranch)
-		if errMsg := m.validateNewWorktreeTarget(newBranch, targetPath); errMsg != "" {
+		newBranch, targetPath, note, reason := m.freeWorktreeTarget(newBranch, branches, screen.toggleOn)
+		if reason != "" {
+			m.inputScreen.errorMsg = reason
+			return nil, false
+		}
+		if errMsg := m.policyViolation(newBranch, baseRef); errMsg != "" {
 			m.inputScreen.errorMsg = errMsg
 			return nil, false
 		}
-
-		// Show loading screen immediately (before returning from inputSubmit)
-		if err := m.ensureWorktreeDir(m.getRepoWorktreeDir()); err != nil {
-			return func() tea.Msg { return errMsg{err: err} }, true
+		if m.offerAdjustedPath(targetPath, note, &offered) {
+			return nil, false
 		}
-		m.loading = true
-		m.statusContent = fmt.Sprintf("Creating worktree from %s...", baseRef)
-		m.loadingScreen = NewLoadingScreen(m.statusContent, m.theme)
-		m.currentScreen = screenLoading
-
-		return m.createWorktreeFromBaseAsync(newBranch, targetPath, baseRef), true
+		return create(newBranch, targetPath, checked), true
 	}
 	m.currentScreen = screenInput
 	return textinput.Blink
 }
 
+// startCreateFromBase shows the loading screen and creates the worktree,
+// checking out only sparse when it is set.
+func (m *Model) startCreateFromBase(newBranch, targetPath, baseRef string, sparse []string) tea.Cmd {
+	if err := m.ensureWorktreeDir(m.getRepoWorktreeDir()); err != nil {
+		return func() tea.Msg { return errMsg{err: err} }
+	}
+	m.loading = true
+	m.statusContent = fmt.Sprintf("Creating worktree from %s...", baseRef)
+	m.loadingScreen = NewLoadingScreen(m.statusContent, m.theme)
+	m.currentScreen = screenLoading
+
+	return m.createWorktreeFromBaseAsync(newBranch, targetPath, baseRef, sparse)
+}
+
 func (m *Model) suggestBranchName(baseName string) string {
+	return suggestBranchNameWithExisting(baseName, m.existingBranchNames())
+}
+
+// existingBranchNames lists the local branches and those checked out in
+// worktrees.
+func (m *Model) existingBranchNames() map[string]struct{} {
 	existing := make(map[string]struct{})
 	for _, wt := range m.worktrees {
 		if wt.Branch == "" || wt.Branch == "(detached)" {
@@ -292,8 +382,7 @@ func (m *Model) suggestBranchName(baseName string) string {
 		}
 		existing[branch] = struct{}{}
 	}
-
-	return suggestBranchNameWithExisting(baseName, existing)
+	return existing
 }
 
 func suggestBranchNameWithExisting(baseName string, existing map[string]struct{}) string {
@@ -352,10 +441,18 @@ func sanitizeBranchNameFromTitle(title, fallback string) string {
 }
 
 // createWorktreeFromBaseAsync performs the actual async worktree creation.
-// The LoadingScreen should be set up before calling this.
-func (m *Model) createWorktreeFromBaseAsync(newBranch, targetPath, baseRef string) tea.Cmd {
+// With sparse set, only those directories are checked out, and a baseRef
+// equal to newBranch checks that existing branch out. The LoadingScreen
+// should be set up before calling this.
+func (m *Model) createWorktreeFromBaseAsync(newBranch, targetPath, baseRef string, sparse []string) tea.Cmd {
 	return func() tea.Msg {
-		args := []string{"git", "worktree", "add", "-b", newBranch}
+		args := []string{"git", "worktree", "add"}
+		if newBranch != baseRef {
+			args = append(args, "-b", newBranch)
+		}
+		if len(sparse) > 0 {
+			args = append(args, "--no-checkout")
+		}
 		if strings.Contains(baseRef, "/") {
 			args = append(args, "--track")
 		}
@@ -370,9 +467,11 @@ func (m *Model) createWorktreeFromBaseAsync(newBranch, targetPath, baseRef strin
 		if !ok {
 			return errMsg{err: fmt.Errorf("failed to create worktree %s", newBranch)}
 		}
+		if len(sparse) > 0 && !m.git.CheckoutSparse(m.ctx, targetPath, sparse) {
+			return errMsg{err: fmt.Errorf("worktree %s was created but its sparse checkout failed; use \"Edit sparse checkout\" to retry", newBranch)}
+		}
 
 		env := m.buildCommandEnv(newBranch, targetPath)
-		initCmds := m.collectInitCommands()
 
 		// Run init commands with trust checks, passing after callback
 		after := func() tea.Msg {
@@ -393,7 +492,7 @@ func (m *Model) createWorktreeFromBaseAsync(newBranch, targetPath, baseRef strin
 		}
 
 		// Return the init commands execution, which will handle the 'after' callback
-		cmd := m.runCommandsWithTrust(initCmds, targetPath, env, after)
+		cmd := m.runInitCommands(targetPath, env, after)
 		if cmd != nil {
 			return cmd()
 		}
@@ -403,6 +502,9 @@ func (m *Model) createWorktreeFromBaseAsync(newBranch, targetPath, baseRef strin
 
 // createWorktreeFromBase is kept for backward compatibility (e.g., custom create menus)
 func (m *Model) createWorktreeFromBase(newBranch, targetPath, baseRef string) tea.Cmd {
+	if m.readOnlyDenied("Creating worktrees") {
+		return nil
+	}
 	if err := m.ensureWorktreeDir(m.getRepoWorktreeDir()); err != nil {
 		return func() tea.Msg { return errMsg{err: err} }
 	}
@@ -413,7 +515,7 @@ func (m *Model) createWorktreeFromBase(newBranch, targetPath, baseRef string) te
 	m.loadingScreen = NewLoadingScreen(m.statusContent, m.theme)
 	m.currentScreen = screenLoading
 
-	return m.createWorktreeFromBaseAsync(newBranch, targetPath, baseRef)
+	return m.createWorktreeFromBaseAsync(newBranch, targetPath, baseRef, nil)
 }
 
 func (m *Model) clearListSelection() {
@@ -424,24 +526,22 @@ func (m *Model) clearListSelection() {
 	}
 }
 
-func (m *Model) branchSelectionItems(preferred string) []selectionItem {
-	raw := m.git.RunGit(
-		m.ctx,
-		[]string{
-			"git", "for-each-ref",
-			"--sort=-committerdate",
-			"--format=%(refname:short)\t%(refname)\t%(committerdate:unix)",
-			"refs/heads",
-			"refs/remotes",
-			"refs/tags",
-		},
-		"",
-		[]int{0},
-		true,
-		false,
-	)
-
-	options := parseBranchOptionsWithDate(raw)
+// branchSelectionItems lists local branches and tags, then remote branches.
+// Unless allRemotes is set, a repository with many remote branches only gets
+// the default ones and an entry to load the rest, as dating thousands of
+// refs stalls the create flow.
+func (m *Model) branchSelectionItems(allRemotes bool) []selectionItem {
+	options := m.branchRefOptions("refs/heads", "refs/tags")
+	remoteCount := 0
+	if !allRemotes {
+		remoteCount = m.remoteBranchCount()
+	}
+	lazy := remoteCount > remoteRefEagerLimit
+	if lazy {
+		options = append(options, m.branchRefOptions("refs/remotes/"+originMain, "refs/remotes/"+originMaster)...)
+	} else {
+		options = append(options, m.branchRefOptions("refs/remotes")...)
+	}
 	options = sortBranchOptions(options)
 	items := make([]selectionItem, 0, len(options))
 	for _, opt := range options {
@@ -457,9 +557,45 @@ func (m *Model) branchSelectionItems(preferred string) []selectionItem {
 			description: desc,
 		})
 	}
+	if lazy {
+		items = append(items, selectionItem{
+			id:          loadRemotesID,
+			label:       fmt.Sprintf("Load %d remote branches…", remoteCount),
+			description: "listed on request in large repositories",
+		})
+	}
 	return items
 }
 
+// remoteBranchCount counts remote branches without reading their commits.
+func (m *Model) remoteBranchCount() int {
+	raw := m.git.RunGit(m.ctx, []string{"git", "for-each-ref", "--format=%(refname)", "refs/remotes"}, "", []int{0}, true, false)
+	count := 0
+	for line := range strings.SplitSeq(raw, "\n") {
+		if line != "" && !strings.HasSuffix(line, "/HEAD") {
+			count++
+		}
+	}
+	return count
+}
+
+// branchRefOptions reads the refs matching args, newest commit first.
+func (m *Model) branchRefOptions(args ...string) []branchOption {
+	raw := m.git.RunGit(
+		m.ctx,
+		append([]string{
+			"git", "for-each-ref",
+			"--sort=-committerdate",
+			"--format=%(refname:short)\t%(refname)\t%(committerdate:unix)",
+		}, args...),
+		"",
+		[]int{0},
+		true,
+		false,
+	)
+	return parseBranchOptionsWithDate(raw)
+}
+
 func parseBranchOptionsWithDate(raw string) []branchOption {
 	lines := strings.Split(strings.TrimSpace(raw), "\n")
 	if len(lines) == 1 && strings.TrimSpace(lines[0]) == "" {
@@ -525,19 +661,12 @@ func sortBranchOptions(options []branchOption) []branchOption {
 	}
 
 	// Sort others by commit date (descending), then alphabetically
-	for i := 0; i < len(others); i++ {
-		for j := i + 1; j < len(others); j++ {
-			// Sort by date descending (newer first)
-			if others[i].committerDate.Before(others[j].committerDate) {
-				others[i], others[j] = others[j], others[i]
-			} else if others[i].committerDate.Equal(others[j].committerDate) {
-				// If dates are equal, sort alphabetically
-				if others[i].name > others[j].name {
-					others[i], others[j] = others[j], others[i]
-				}
-			}
+	sort.SliceStable(others, func(i, j int) bool {
+		if !others[i].committerDate.Equal(others[j].committerDate) {
+			return others[i].committerDate.After(others[j].committerDate)
 		}
-	}
+		return others[i].name < others[j].name
+	})
 
 	// Build result in priority order
 	result := make([]branchOption, 0, len(options))
@@ -605,6 +734,9 @@ func buildCommitItems(options []commitOption) []selectionItem {
 
 // executeCustomCreateCommand runs a custom create menu command and returns the result.
 func (m *Model) executeCustomCreateCommand(menu *config.CustomCreateMenu) tea.Cmd {
+	if m.readOnlyDenied("Creating worktrees") {
+		return nil
+	}
 	m.clearListSelection()
 
 	// Get main worktree path for command execution
@@ -639,7 +771,10 @@ func (m *Model) executeCustomCreateCommand(menu *config.CustomCreateMenu) tea.Cm
 		cmd.Stdout = &stdout
 		cmd.Stderr = &stderr
 
-		if err := cmd.Run(); err != nil {
+		release := m.git.Acquire(ctx, git.PoolCommands)
+		err := cmd.Run()
+		release()
+		if err != nil {
 			errMsg := strings.TrimSpace(stderr.String())
 			if errMsg == "" {
 				errMsg = err.Error()
@@ -757,7 +892,10 @@ func (m *Model) executeCustomPostCommand(script, targetPath string, env map[stri
 		var stderr bytes.Buffer
 		cmd.Stderr = &stderr
 
-		if err := cmd.Run(); err != nil {
+		release := m.git.Acquire(ctx, git.PoolCommands)
+		err := cmd.Run()
+		release()
+		if err != nil {
 			errMsg := strings.TrimSpace(stderr.String())
 			if errMsg == "" {
 				errMsg = err.Error()
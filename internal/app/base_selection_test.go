@@ -637,6 +637,60 @@ func TestShowBranchSelection(t *testing.T) {
 	}
 }
 
+func TestShowBranchSelectionLoadsManyRemotesOnRequest(t *testing.T) {
+	repo := initTestRepo(t)
+	withCwd(t, repo.dir)
+
+	var refs strings.Builder
+	for i := range remoteRefEagerLimit + 1 {
+		fmt.Fprintf(&refs, "create refs/remotes/origin/topic-%03d HEAD\n", i)
+	}
+	cmd := exec.Command("git", "update-ref", "--stdin")
+	cmd.Dir = repo.dir
+	cmd.Stdin = strings.NewReader(refs.String())
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("update-ref: %v: %s", err, out)
+	}
+
+	cfg := &config.AppConfig{WorktreeDir: t.TempDir()}
+	m := NewModel(cfg, "")
+	m.windowWidth = 120
+	m.windowHeight = 40
+
+	selected := ""
+	m.showBranchSelection("Pick", "Filter...", "None", repo.branch, func(branch string) tea.Cmd {
+		selected = branch
+		return nil
+	})
+
+	items := m.listScreen.items
+	last := items[len(items)-1]
+	if last.id != loadRemotesID {
+		t.Fatalf("expected a load remotes entry last, got %q", last.id)
+	}
+	if !strings.Contains(last.label, fmt.Sprintf("%d", remoteRefEagerLimit+2)) {
+		t.Fatalf("expected the remote branch count in %q", last.label)
+	}
+	for _, item := range items {
+		if strings.HasPrefix(item.id, "origin/topic-") {
+			t.Fatalf("expected remote topics to wait until requested, found %q", item.id)
+		}
+	}
+
+	m.listSubmit(last)
+	if selected != "" {
+		t.Fatalf("expected loading remotes not to select a branch, got %q", selected)
+	}
+	if got := len(m.listScreen.items); got < remoteRefEagerLimit+2 {
+		t.Fatalf("expected all remote branches after loading, got %d items", got)
+	}
+	for _, item := range m.listScreen.items {
+		if item.id == loadRemotesID {
+			t.Fatal("expected the load remotes entry to go once loaded")
+		}
+	}
+}
+
 func TestShowCommitSelection(t *testing.T) {
 	repo := initTestRepo(t)
 	withCwd(t, repo.dir)
@@ -727,6 +781,7 @@ func TestShowBranchNameInputValidation(t *testing.T) {
 		t.Fatalf("expected suggested branch name, got %q", got)
 	}
 
+	m.inputScreen.toggleOn = false
 	if _, ok := m.inputSubmit("demo", false); ok {
 		t.Fatal("expected duplicate branch to be rejected")
 	}
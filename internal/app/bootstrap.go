@@ -0,0 +1,116 @@
+package app
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/chmouel/lazyworktree/internal/bootstrap"
+)
+
+// bootstrapOfferMsg offers the toolchain setup a new worktree calls for.
+type bootstrapOfferMsg struct {
+	path  string
+	steps []bootstrap.Step
+}
+
+// bootstrapSteps returns the setup commands the worktree at path calls for,
+// leaving out those the init commands already run.
+func (m *Model) bootstrapSteps(path string) []bootstrap.Step {
+	return bootstrap.Without(bootstrap.Detect(path), m.collectInitCommands())
+}
+
+// offerBootstrap offers, once a new worktree is set up, the toolchain
+// commands it calls for.
+func (m *Model) offerBootstrap(path string) tea.Cmd {
+	if m.config == nil || !m.config.SuggestBootstrap || m.config.ReadOnly {
+		return nil
+	}
+	steps := m.bootstrapSteps(path)
+	if len(steps) == 0 {
+		return nil
+	}
+	return func() tea.Msg { return bootstrapOfferMsg{path: path, steps: steps} }
+}
+
+// handleBootstrapOffer shows the checklist, or, when another screen is
+// open, points at the palette instead of interrupting it.
+func (m *Model) handleBootstrapOffer(msg bootstrapOfferMsg) tea.Cmd {
+	if m.currentScreen != screenNone {
+		m.statusContent = fmt.Sprintf("%s can be bootstrapped: run \"Bootstrap toolchains\" from the palette.", filepath.Base(msg.path))
+		return nil
+	}
+	return m.showBootstrapChecklist(msg.path, msg.steps)
+}
+
+// showBootstrap offers the toolchain commands the selected worktree calls
+// for.
+func (m *Model) showBootstrap() tea.Cmd {
+	wt := m.selectedWorktree()
+	if wt == nil {
+		return nil
+	}
+	if m.readOnlyDenied("Bootstrapping toolchains") {
+		return nil
+	}
+	steps := m.bootstrapSteps(wt.Path)
+	if len(steps) == 0 {
+		m.showInfo(fmt.Sprintf("%s needs no bootstrapping.\n\nCommands are suggested for package.json, go.mod, Cargo.toml, pyproject.toml, requirements.txt, Gemfile and composer.json, unless already set up or run by init_commands.", filepath.Base(wt.Path)), nil)
+		return nil
+	}
+	return m.showBootstrapChecklist(wt.Path, steps)
+}
+
+// showBootstrapChecklist lets the user pick the commands to run; those
+// whose program is missing start unticked.
+func (m *Model) showBootstrapChecklist(path string, steps []bootstrap.Step) tea.Cmd {
+	items := make([]ChecklistItem, 0, len(steps))
+	for i, step := range steps {
+		description := "for " + step.Reason
+		if !step.Installed {
+			description += fmt.Sprintf(" (%s is not installed)", step.Tool)
+		}
+		items = append(items, ChecklistItem{ID: strconv.Itoa(i), Label: step.Command, Description: description, Checked: step.Installed})
+	}
+	m.checklistScreen = NewChecklistScreen(
+		items,
+		fmt.Sprintf("Bootstrap %s", filepath.Base(path)),
+		"Filter...",
+		"No matching commands.",
+		m.windowWidth,
+		m.windowHeight,
+		m.theme,
+	)
+	m.checklistSubmit = func(selected []ChecklistItem) tea.Cmd {
+		cmds := make([]string, 0, len(selected))
+		for _, item := range selected {
+			if i, err := strconv.Atoi(item.ID); err == nil && i < len(steps) {
+				cmds = append(cmds, steps[i].Command)
+			}
+		}
+		return m.runBootstrap(path, cmds)
+	}
+	m.currentScreen = screenChecklist
+	return textinput.Blink
+}
+
+// runBootstrap runs the chosen commands in the worktree, streaming their
+// output into its status pane as for init commands.
+func (m *Model) runBootstrap(path string, cmds []string) tea.Cmd {
+	if len(cmds) == 0 {
+		return nil
+	}
+	m.initOutputsMu.Lock()
+	m.initOutputs[path] = &initOutput{title: "Bootstrap commands", notify: m.signalInitOutput}
+	m.initOutputsMu.Unlock()
+	branch := ""
+	for _, wt := range m.worktrees {
+		if wt.Path == path {
+			branch = wt.Branch
+		}
+	}
+	// Failures show in the status pane; nothing else needs refreshing.
+	return m.runCommands(cmds, path, m.buildCommandEnv(branch, path), func() tea.Msg { return nil })
+}
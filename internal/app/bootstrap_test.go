@@ -0,0 +1,62 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/chmouel/lazyworktree/internal/config"
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+func TestBootstrapOfferedForNewWorktree(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as npm")
+	}
+	bin := t.TempDir()
+	if err := os.WriteFile(filepath.Join(bin, "npm"), []byte("#!/bin/sh\necho \"npm $* done\"\n"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	cfg := &config.AppConfig{WorktreeDir: t.TempDir(), TrustMode: "always", SuggestBootstrap: true, InitCommands: []string{"go mod download"}}
+	m := NewModel(cfg, "")
+	m.setWindowSize(120, 40)
+	path := t.TempDir()
+	for _, name := range []string{"package.json", "package-lock.json", "go.mod"} {
+		if err := os.WriteFile(filepath.Join(path, name), []byte("{}\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	m.worktrees = []*models.WorktreeInfo{{Path: path, Branch: "feature"}}
+	m.filteredWts = m.worktrees
+
+	offer, ok := m.offerBootstrap(path)().(bootstrapOfferMsg)
+	if !ok || len(offer.steps) != 1 || offer.steps[0].Command != "npm ci" {
+		t.Fatalf("expected npm ci alone, go mod download being an init command, got %+v", offer)
+	}
+
+	m.currentScreen = screenConfirm
+	_ = m.handleBootstrapOffer(offer)
+	if m.currentScreen != screenConfirm || !strings.Contains(m.statusContent, "Bootstrap toolchains") {
+		t.Fatalf("expected a hint rather than an interruption, got %s and %q", screenName(m.currentScreen), m.statusContent)
+	}
+
+	// Commands run through a login shell, which may reset PATH; stand in
+	// for npm.
+	offer.steps[0].Command = "echo npm ci done"
+	m.currentScreen = screenNone
+	_ = m.handleBootstrapOffer(offer)
+	if m.currentScreen != screenChecklist || !m.checklistScreen.items[0].Checked {
+		t.Fatalf("expected the checklist with npm ci ticked, got %s", screenName(m.currentScreen))
+	}
+	_ = m.checklistSubmit(m.checklistScreen.items)()
+	if got := m.statusPaneTitle(); got != "Bootstrap commands" {
+		t.Fatalf("expected the bootstrap output in the status pane, got %q", got)
+	}
+	if content := m.statusPaneContent(80); !strings.Contains(content, "Bootstrap commands finished.") || !strings.Contains(content, "npm ci done") {
+		t.Fatalf("expected the npm output, got %q", content)
+	}
+}
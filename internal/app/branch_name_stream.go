@@ -0,0 +1,191 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// branchNameStreamNote is shown under the branch name while
+// branch_name_script runs.
+const branchNameStreamNote = "Generating branch name..."
+
+// branchNameStream follows a branch_name_script run feeding the open
+// branch name input.
+type branchNameStream struct {
+	id       int
+	input    *InputScreen
+	format   func(generated string) string // Turns the script's line into a branch name
+	fallback string                        // Name kept when the script fails or prints nothing
+	shown    string                        // Value last put in the input, to notice the user's edits
+	cancel   context.CancelFunc
+	updates  chan branchNameStreamMsg
+}
+
+// branchNameStreamMsg carries what branch_name_script printed so far.
+type branchNameStreamMsg struct {
+	id     int
+	output string
+	done   bool
+	err    error
+}
+
+// branchNameScriptTimeout is how long branch_name_script may run.
+func (m *Model) branchNameScriptTimeout() time.Duration {
+	if m.config.BranchNameScriptTimeout > 0 {
+		return time.Duration(m.config.BranchNameScriptTimeout) * time.Second
+	}
+	return defaultBranchNameScriptTimeout
+}
+
+// runBranchNameScript runs branch_name_script with the configured timeout.
+func (m *Model) runBranchNameScript(content, scriptType, number, template, suggestedName string) (string, error) {
+	return branchNameScriptRun{
+		script:        m.config.BranchNameScript,
+		content:       content,
+		scriptType:    scriptType,
+		number:        number,
+		template:      template,
+		suggestedName: suggestedName,
+		timeout:       m.branchNameScriptTimeout(),
+	}.run(m.ctx)
+}
+
+// startBranchNameStream runs branch_name_script in the background and
+// streams its name into the open input, replacing the fallback it shows,
+// until the user edits the name.
+func (m *Model) startBranchNameStream(run branchNameScriptRun, format func(string) string) tea.Cmd {
+	m.stopBranchNameStream()
+	if m.config.BranchNameScript == "" || m.inputScreen == nil {
+		return nil
+	}
+	m.branchNameStreamID++
+	ctx, cancel := context.WithCancel(m.ctx)
+	stream := &branchNameStream{
+		id:       m.branchNameStreamID,
+		input:    m.inputScreen,
+		format:   format,
+		fallback: m.inputScreen.input.Value(),
+		shown:    m.inputScreen.input.Value(),
+		cancel:   cancel,
+		updates:  make(chan branchNameStreamMsg, 1),
+	}
+	m.branchNameStream = stream
+	m.inputScreen.note = branchNameStreamNote
+
+	// Only the latest output matters, so an update nobody took yet is
+	// replaced rather than waited on.
+	send := func(msg branchNameStreamMsg) {
+		select {
+		case <-stream.updates:
+		default:
+		}
+		stream.updates <- msg
+	}
+	run.script = m.config.BranchNameScript
+	run.timeout = m.branchNameScriptTimeout()
+	run.onOutput = func(output string) {
+		send(branchNameStreamMsg{id: stream.id, output: output})
+	}
+	go func() {
+		output, err := run.run(ctx)
+		send(branchNameStreamMsg{id: stream.id, output: output, done: true, err: err})
+	}()
+	return tea.Batch(textinput.Blink, waitForBranchNameStream(stream.updates))
+}
+
+func waitForBranchNameStream(updates chan branchNameStreamMsg) tea.Cmd {
+	return func() tea.Msg {
+		return <-updates
+	}
+}
+
+// stopBranchNameStream stops the running suggestion, if any.
+func (m *Model) stopBranchNameStream() {
+	if m.branchNameStream == nil {
+		return
+	}
+	m.branchNameStream.cancel()
+	m.branchNameStream.input.note = ""
+	m.branchNameStream = nil
+}
+
+// handleBranchNameStream puts the streamed name in the input, and falls
+// back to the name shown at first when the script fails.
+func (m *Model) handleBranchNameStream(msg branchNameStreamMsg) tea.Cmd {
+	stream := m.branchNameStream
+	if stream == nil || msg.id != stream.id {
+		return nil
+	}
+	// The input was closed, or the user took over the name.
+	if m.currentScreen != screenInput || m.inputScreen != stream.input || stream.input.input.Value() != stream.shown {
+		m.stopBranchNameStream()
+		return nil
+	}
+
+	name := stream.fallback
+	if line := firstOutputLine(msg.output); line != "" && msg.err == nil {
+		name = stream.format(line)
+		if msg.done {
+			name = m.suggestBranchName(name)
+		}
+	}
+	if name != stream.shown {
+		stream.input.input.SetValue(name)
+		stream.input.input.CursorEnd()
+		stream.shown = stream.input.input.Value()
+	}
+	if !msg.done {
+		return waitForBranchNameStream(stream.updates)
+	}
+
+	m.stopBranchNameStream()
+	if msg.err != nil {
+		stream.input.note = fmt.Sprintf("Branch name script error: %v", msg.err)
+		m.debugf("branch name script: %v", msg.err)
+	}
+	return nil
+}
+
+// showCreateFromDescription asks what the work is about, then creates a
+// worktree whose branch name branch_name_script suggests from it, or the
+// sanitised description without a script.
+func (m *Model) showCreateFromDescription() tea.Cmd {
+	if m.readOnlyDenied("Creating worktrees") {
+		return nil
+	}
+	m.clearListSelection()
+	m.inputScreen = NewInputScreen("Create worktree: describe the work", "e.g. fix the login redirect loop", "", m.theme)
+	m.inputSubmit = func(value string, checked bool) (tea.Cmd, bool) {
+		description := strings.TrimSpace(value)
+		if description == "" {
+			m.inputScreen.errorMsg = "Description cannot be empty."
+			return nil, false
+		}
+		defaultBase := m.git.GetMainBranch(m.ctx)
+		return m.showBranchSelection(
+			"Select base branch",
+			"Filter branches...",
+			"No branches found.",
+			defaultBase,
+			func(baseBranch string) tea.Cmd {
+				cmd := m.showBranchNameInput(baseBranch, sanitizeBranchNameFromTitle(description, ""))
+				if stream := m.startBranchNameStream(branchNameScriptRun{
+					content:    description,
+					scriptType: "description",
+				}, func(generated string) string {
+					return sanitizeBranchNameFromTitle(generated, description)
+				}); stream != nil {
+					return stream
+				}
+				return cmd
+			},
+		), true
+	}
+	m.currentScreen = screenInput
+	return textinput.Blink
+}
@@ -0,0 +1,101 @@
+package app
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chmouel/lazyworktree/internal/config"
+)
+
+// drainBranchNameStream feeds the stream's updates to the model until it
+// stops.
+func drainBranchNameStream(t *testing.T, m *Model) {
+	t.Helper()
+	for m.branchNameStream != nil {
+		msg, ok := waitForBranchNameStream(m.branchNameStream.updates)().(branchNameStreamMsg)
+		if !ok {
+			t.Fatal("expected a branch name stream message")
+		}
+		m.handleBranchNameStream(msg)
+	}
+}
+
+func newBranchNameStreamModel(t *testing.T, script string) *Model {
+	t.Helper()
+	cfg := &config.AppConfig{WorktreeDir: t.TempDir(), BranchNameScript: script}
+	m := NewModel(cfg, "")
+	m.inputScreen = NewInputScreen("Create worktree: branch name", "", "issue-7-crash", m.theme)
+	m.currentScreen = screenInput
+	return m
+}
+
+func TestBranchNameStream(t *testing.T) {
+	format := func(generated string) string { return "issue-7-" + sanitizeBranchNameFromTitle(generated, "") }
+
+	t.Run("streams the script's name into the input", func(t *testing.T) {
+		m := newBranchNameStreamModel(t, "printf 'fix null '; sleep 0.1; printf 'deref\\nignored\\n'")
+		if m.startBranchNameStream(branchNameScriptRun{content: "Crash", scriptType: "issue"}, format) == nil {
+			t.Fatal("expected the script to start")
+		}
+		if m.inputScreen.note != branchNameStreamNote {
+			t.Fatalf("expected the generating note, got %q", m.inputScreen.note)
+		}
+		drainBranchNameStream(t, m)
+		if got := m.inputScreen.input.Value(); got != "issue-7-fix-null-deref" {
+			t.Fatalf("expected the generated name, got %q", got)
+		}
+		if m.inputScreen.note != "" {
+			t.Fatalf("expected the note to clear, got %q", m.inputScreen.note)
+		}
+	})
+
+	t.Run("falls back when the script fails", func(t *testing.T) {
+		m := newBranchNameStreamModel(t, "echo half; exit 3")
+		m.startBranchNameStream(branchNameScriptRun{scriptType: "issue"}, format)
+		drainBranchNameStream(t, m)
+		if got := m.inputScreen.input.Value(); got != "issue-7-crash" {
+			t.Fatalf("expected the fallback name, got %q", got)
+		}
+		if !strings.Contains(m.inputScreen.note, "Branch name script error") {
+			t.Fatalf("expected the error to be shown, got %q", m.inputScreen.note)
+		}
+	})
+
+	t.Run("leaves a name the user edits alone", func(t *testing.T) {
+		m := newBranchNameStreamModel(t, "echo generated")
+		m.startBranchNameStream(branchNameScriptRun{scriptType: "issue"}, format)
+		m.inputScreen.input.SetValue("my-own-name")
+		drainBranchNameStream(t, m)
+		if got := m.inputScreen.input.Value(); got != "my-own-name" {
+			t.Fatalf("expected the user's name to stay, got %q", got)
+		}
+	})
+
+	t.Run("does nothing without a script", func(t *testing.T) {
+		m := newBranchNameStreamModel(t, "")
+		if m.startBranchNameStream(branchNameScriptRun{scriptType: "issue"}, format) != nil || m.inputScreen.note != "" {
+			t.Fatal("expected no stream without branch_name_script")
+		}
+	})
+}
+
+func TestBranchNameScriptRunStreamsAndTimesOut(t *testing.T) {
+	var outputs []string
+	out, err := branchNameScriptRun{
+		script:   "printf a; sleep 0.1; printf b",
+		onOutput: func(output string) { outputs = append(outputs, output) },
+	}.run(context.Background())
+	if err != nil || out != "ab" {
+		t.Fatalf("expected ab, got %q (%v)", out, err)
+	}
+	if len(outputs) < 2 || outputs[0] != "a" {
+		t.Fatalf("expected the output as it arrived, got %q", outputs)
+	}
+
+	_, err = branchNameScriptRun{script: "sleep 5", timeout: 100 * time.Millisecond}.run(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "timed out after 100ms") {
+		t.Fatalf("expected a timeout error, got %v", err)
+	}
+}
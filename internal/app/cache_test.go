@@ -0,0 +1,151 @@
+package app
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/chmouel/lazyworktree/internal/config"
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+// TestMain keeps the caches written by the tests out of the user's cache
+// directory.
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "lazyworktree-cache-")
+	if err != nil {
+		panic(err)
+	}
+	_ = os.Setenv("XDG_CACHE_HOME", dir)
+	code := m.Run()
+	_ = os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+func newCacheTestModel(t *testing.T) (*Model, string) {
+	t.Helper()
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	m := NewModel(&config.AppConfig{WorktreeDir: t.TempDir()}, "")
+	m.repoKey = testRepoKey
+	return m, m.cachePath()
+}
+
+func writeTestCache(t *testing.T, path string, cache worktreeCache) {
+	t.Helper()
+	data, err := json.Marshal(cache)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSaveCacheRoundTrip(t *testing.T) {
+	m, cachePath := newCacheTestModel(t)
+	mainPath := m.git.GetMainWorktreePath(m.ctx)
+	m.worktrees = []*models.WorktreeInfo{
+		{Path: mainPath, Branch: mainWorktreeName, IsMain: true},
+		{Path: "/tmp/feat", Branch: featureBranch},
+	}
+
+	m.saveCache()
+
+	matches, _ := filepath.Glob(cachePath + ".*.tmp")
+	if len(matches) != 0 {
+		t.Fatalf("expected no temporary files left behind, got %v", matches)
+	}
+	msg, ok := m.loadCache()().(cachedWorktreesMsg)
+	if !ok {
+		t.Fatal("expected the saved cache to load")
+	}
+	if len(msg.worktrees) != 2 || msg.worktrees[1].Branch != featureBranch {
+		t.Fatalf("unexpected cached worktrees: %+v", msg.worktrees)
+	}
+}
+
+func TestLoadCacheDiscardsIncompatible(t *testing.T) {
+	tests := []struct {
+		name  string
+		cache func(mainPath string) worktreeCache
+	}{
+		{
+			name: "old schema",
+			cache: func(mainPath string) worktreeCache {
+				return worktreeCache{Repo: mainPath, Worktrees: []*models.WorktreeInfo{{Path: mainPath, IsMain: true}}}
+			},
+		},
+		{
+			name: "other repository",
+			cache: func(string) worktreeCache {
+				return worktreeCache{Version: cacheSchemaVersion, Repo: "/elsewhere", Worktrees: []*models.WorktreeInfo{{Path: "/elsewhere", IsMain: true}}}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, cachePath := newCacheTestModel(t)
+			writeTestCache(t, cachePath, tt.cache(m.git.GetMainWorktreePath(m.ctx)))
+
+			if msg := m.loadCache()(); msg != nil {
+				t.Fatalf("expected incompatible cache to be ignored, got %T", msg)
+			}
+			if _, err := os.Stat(cachePath); !os.IsNotExist(err) {
+				t.Fatal("expected incompatible cache to be removed")
+			}
+		})
+	}
+}
+
+func TestLoadCacheDiscardsCorrupt(t *testing.T) {
+	m, cachePath := newCacheTestModel(t)
+	if err := os.MkdirAll(filepath.Dir(cachePath), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(cachePath, []byte(`{"version": 1, "worktrees": [`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if msg := m.loadCache()(); msg != nil {
+		t.Fatalf("expected corrupt cache to be ignored, got %T", msg)
+	}
+	if _, err := os.Stat(cachePath); !os.IsNotExist(err) {
+		t.Fatal("expected corrupt cache to be removed")
+	}
+}
+
+func TestCacheKeptOutOfWorktreeDir(t *testing.T) {
+	m, cachePath := newCacheTestModel(t)
+	if strings.HasPrefix(cachePath, m.getWorktreeDir()) {
+		t.Fatalf("expected the cache outside the worktree directory, got %s", cachePath)
+	}
+	if want := filepath.Join(os.Getenv("XDG_CACHE_HOME"), "lazyworktree", testRepoKey, models.CacheFilename); cachePath != want {
+		t.Fatalf("expected cache at %s, got %s", want, cachePath)
+	}
+}
+
+func TestLoadCacheMigratesLegacyCache(t *testing.T) {
+	m, cachePath := newCacheTestModel(t)
+	mainPath := m.git.GetMainWorktreePath(m.ctx)
+	legacy := filepath.Join(m.getRepoWorktreeDir(), models.CacheFilename)
+	writeTestCache(t, legacy, worktreeCache{
+		Version:   cacheSchemaVersion,
+		Repo:      mainPath,
+		Worktrees: []*models.WorktreeInfo{{Path: mainPath, IsMain: true}},
+	})
+
+	if _, ok := m.loadCache()().(cachedWorktreesMsg); !ok {
+		t.Fatal("expected the legacy cache to load")
+	}
+	if _, err := os.Stat(legacy); !os.IsNotExist(err) {
+		t.Fatal("expected the legacy cache to be moved")
+	}
+	if _, err := os.Stat(cachePath); err != nil {
+		t.Fatalf("expected the cache in the cache directory: %v", err)
+	}
+}
@@ -0,0 +1,169 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/chmouel/lazyworktree/internal/utils"
+)
+
+const changelogFilename = "CHANGELOG.md"
+
+// showChangelog generates a changelog snippet from the commits on the selected
+// worktree's branch that are not yet on the main branch.
+func (m *Model) showChangelog() tea.Cmd {
+	wt := m.selectedWorktree()
+	if wt == nil {
+		m.showInfo(errNoWorktreeSelected, nil)
+		return nil
+	}
+	if wt.IsMain {
+		m.showInfo("The main worktree has no branch changes to summarise.", nil)
+		return nil
+	}
+	path, branch := wt.Path, wt.Branch
+	return func() tea.Msg {
+		base := m.git.GetMainBranch(m.ctx)
+		commits := m.git.GetCommitMessages(m.ctx, base, path)
+		return changelogReadyMsg{
+			path:   path,
+			branch: branch,
+			base:   base,
+			text:   utils.GenerateChangelog(commits),
+		}
+	}
+}
+
+// handleChangelogReady offers to preview, copy or write a generated changelog.
+func (m *Model) handleChangelogReady(msg changelogReadyMsg) tea.Cmd {
+	if strings.TrimSpace(msg.text) == "" {
+		m.showInfo(fmt.Sprintf("No commits on %s since %s.", msg.branch, msg.base), nil)
+		return nil
+	}
+
+	items := []selectionItem{
+		{id: "preview", label: "Preview", description: "Show the changelog in the pager"},
+		{id: "copy", label: "Copy to clipboard", description: "Copy the Markdown snippet"},
+		{id: "write", label: "Write to " + changelogFilename, description: "Prepend under a heading for " + msg.branch},
+	}
+	title := fmt.Sprintf("Changelog for %s (%d lines)", msg.branch, strings.Count(msg.text, "\n"))
+	m.listScreen = NewListSelectionScreen(items, title, "Filter...", "", m.windowWidth, m.windowHeight, "", m.theme)
+	m.listSubmit = func(item selectionItem) tea.Cmd {
+		m.listScreen = nil
+		m.listSubmit = nil
+		m.currentScreen = screenNone
+
+		switch item.id {
+		case "preview":
+			return m.previewChangelog(msg)
+		case "copy":
+			if err := m.copyToClipboard(msg.text); err != nil {
+				m.showInfo(fmt.Sprintf("Failed to copy changelog: %v", err), nil)
+				return nil
+			}
+			m.statusContent = "Changelog copied to clipboard"
+		case "write":
+			if m.readOnlyDenied("Writing " + changelogFilename) {
+				return nil
+			}
+			target := filepath.Join(msg.path, changelogFilename)
+			if err := prependChangelog(target, msg.branch, msg.text); err != nil {
+				m.showInfo(fmt.Sprintf("Failed to write %s: %v", changelogFilename, err), nil)
+				return nil
+			}
+			m.statusContent = fmt.Sprintf("Changelog written to %s", target)
+			return m.requestWorktreeRefresh(msg.path)
+		}
+		return nil
+	}
+	m.currentScreen = screenListSelect
+	return textinput.Blink
+}
+
+// previewChangelog pipes the changelog into the pager, then reopens the menu.
+func (m *Model) previewChangelog(msg changelogReadyMsg) tea.Cmd {
+	pager := m.pagerCommand()
+	if pagerEnv := m.pagerEnv(pager); pagerEnv != "" {
+		pager = fmt.Sprintf("%s %s", pagerEnv, pager)
+	}
+	// #nosec G204 -- pager comes from the user's configuration or environment
+	c := m.commandRunner("bash", "-c", pager)
+	c.Dir = msg.path
+	c.Stdin = strings.NewReader(msg.text)
+	return m.execProcess(c, func(err error) tea.Msg {
+		if err != nil {
+			return errMsg{err: err}
+		}
+		return msg
+	})
+}
+
+// clipboardCommand returns the clipboard writer available on this platform.
+func clipboardCommand() []string {
+	switch runtime.GOOS {
+	case osDarwin:
+		return []string{"pbcopy"}
+	case osWindows:
+		return []string{"clip"}
+	}
+	candidates := [][]string{
+		{"xclip", "-selection", "clipboard"},
+		{"xsel", "--clipboard", "--input"},
+		{"wl-copy"},
+	}
+	if os.Getenv("WAYLAND_DISPLAY") != "" {
+		candidates = append([][]string{{"wl-copy"}}, candidates...)
+	}
+	for _, candidate := range candidates {
+		if _, err := exec.LookPath(candidate[0]); err == nil {
+			return candidate
+		}
+	}
+	return nil
+}
+
+// copyToClipboard writes text to the system clipboard.
+func (m *Model) copyToClipboard(text string) error {
+	args := clipboardCommand()
+	if len(args) == 0 {
+		return errors.New("no clipboard tool found (install wl-copy, xclip or xsel)")
+	}
+	c := m.commandRunner(args[0], args[1:]...)
+	c.Stdin = strings.NewReader(text)
+	return c.Run()
+}
+
+// prependChangelog adds a section for branch at the top of a changelog file,
+// keeping a leading "# " title in place and creating the file when missing.
+func prependChangelog(path, branch, text string) error {
+	section := fmt.Sprintf("## %s\n\n%s", branch, strings.TrimRight(text, "\n")+"\n")
+	mode := os.FileMode(0o644)
+
+	// #nosec G304 -- path is the changelog inside a worktree we manage
+	existing, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return os.WriteFile(path, []byte("# Changelog\n\n"+section), mode)
+	case err != nil:
+		return err
+	}
+	if info, statErr := os.Stat(path); statErr == nil {
+		mode = info.Mode().Perm()
+	}
+
+	content := string(existing)
+	var updated string
+	if title, rest, found := strings.Cut(content, "\n"); strings.HasPrefix(title, "# ") && found {
+		updated = title + "\n\n" + section + "\n" + strings.TrimLeft(rest, "\n")
+	} else {
+		updated = section + "\n" + content
+	}
+	return os.WriteFile(path, []byte(updated), mode)
+}
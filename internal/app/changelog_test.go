@@ -0,0 +1,69 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/chmouel/lazyworktree/internal/config"
+)
+
+func TestPrependChangelog(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, changelogFilename)
+
+	if err := prependChangelog(path, "feature", "### Features\n\n- one (a1)\n"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := prependChangelog(path, "fix", "### Bug Fixes\n\n- two (b2)\n"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read changelog: %v", err)
+	}
+	want := "# Changelog\n\n" +
+		"## fix\n\n### Bug Fixes\n\n- two (b2)\n\n" +
+		"## feature\n\n### Features\n\n- one (a1)\n"
+	if string(data) != want {
+		t.Fatalf("unexpected changelog:\n%s", data)
+	}
+}
+
+func TestPrependChangelogWithoutTitle(t *testing.T) {
+	path := filepath.Join(t.TempDir(), changelogFilename)
+	if err := os.WriteFile(path, []byte("Older notes\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := prependChangelog(path, "feature", "- one\n"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	if string(data) != "## feature\n\n- one\n\nOlder notes\n" {
+		t.Fatalf("unexpected changelog:\n%s", data)
+	}
+}
+
+func TestHandleChangelogReady(t *testing.T) {
+	cfg := &config.AppConfig{
+		WorktreeDir: t.TempDir(),
+	}
+	m := NewModel(cfg, "")
+
+	m.handleChangelogReady(changelogReadyMsg{branch: "feature", base: "main"})
+	if m.currentScreen != screenInfo {
+		t.Fatalf("expected info screen for empty changelog, got %v", m.currentScreen)
+	}
+
+	m.currentScreen = screenNone
+	dir := t.TempDir()
+	m.handleChangelogReady(changelogReadyMsg{path: dir, branch: "feature", base: "main", text: "### Features\n\n- one\n"})
+	if m.currentScreen != screenListSelect || m.listScreen == nil {
+		t.Fatalf("expected list selection, got %v", m.currentScreen)
+	}
+	m.listSubmit(selectionItem{id: "write"})
+	if _, err := os.Stat(filepath.Join(dir, changelogFilename)); err != nil {
+		t.Fatalf("expected changelog to be written: %v", err)
+	}
+}
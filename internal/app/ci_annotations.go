@@ -0,0 +1,230 @@
+package app
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+const (
+	// ciAnnotationsTTL is how long fetched CI findings are reused, as
+	// fetching them takes a request per failing check.
+	ciAnnotationsTTL = 2 * time.Minute
+	// ciFindingsInfoLimit caps the CI findings listed in the info pane.
+	ciFindingsInfoLimit = 5
+)
+
+type ciAnnotationsEntry struct {
+	annotations []*models.CIAnnotation
+	fetchedAt   time.Time
+}
+
+// ciAnnotationsLoadedMsg carries the CI findings of a branch's PR.
+type ciAnnotationsLoadedMsg struct {
+	branch      string
+	annotations []*models.CIAnnotation
+	err         error
+}
+
+// ciFailed reports whether a cached CI check of branch failed.
+func (m *Model) ciFailed(branch string) bool {
+	cached, ok := m.ciCache[branch]
+	if !ok {
+		return false
+	}
+	return slices.ContainsFunc(cached.checks, func(c *models.CICheck) bool { return c.Conclusion == "failure" })
+}
+
+// maybeFetchCIAnnotations fetches, once CI failed for the selected
+// worktree's PR, the findings its checks attach to files, unless recently
+// fetched.
+func (m *Model) maybeFetchCIAnnotations() tea.Cmd {
+	if m.selectedIndex < 0 || m.selectedIndex >= len(m.filteredWts) {
+		return nil
+	}
+	wt := m.filteredWts[m.selectedIndex]
+	if wt.PR == nil || !m.ciFailed(wt.Branch) {
+		return nil
+	}
+	if m.ciAnnotations == nil {
+		m.ciAnnotations = make(map[string]*ciAnnotationsEntry)
+	}
+	entry, ok := m.ciAnnotations[wt.Branch]
+	if ok && time.Since(entry.fetchedAt) < ciAnnotationsTTL {
+		return nil
+	}
+	if !ok {
+		entry = &ciAnnotationsEntry{}
+		m.ciAnnotations[wt.Branch] = entry
+	}
+	// Marked now, so moving back and forth does not fetch them twice.
+	entry.fetchedAt = time.Now()
+	prNumber, branch := wt.PR.Number, wt.Branch
+	return func() tea.Msg {
+		annotations, err := m.git.FetchCIAnnotations(m.ctx, prNumber)
+		return ciAnnotationsLoadedMsg{branch: branch, annotations: annotations, err: err}
+	}
+}
+
+// handleCIAnnotationsLoaded stores the findings and shows them on the
+// selected worktree.
+func (m *Model) handleCIAnnotationsLoaded(msg ciAnnotationsLoadedMsg) tea.Cmd {
+	if msg.err != nil {
+		m.debugf("ci annotations: %v", msg.err)
+		return nil
+	}
+	if m.ciAnnotations == nil {
+		m.ciAnnotations = make(map[string]*ciAnnotationsEntry)
+	}
+	m.ciAnnotations[msg.branch] = &ciAnnotationsEntry{annotations: msg.annotations, fetchedAt: time.Now()}
+	if wt := m.selectedWorktree(); wt != nil && wt.Branch == msg.branch {
+		m.infoContent = m.buildInfoContent(wt)
+		m.rebuildStatusContentWithHighlight()
+	}
+	return nil
+}
+
+// ciFindings returns the CI findings of the worktree's PR, while its CI
+// fails.
+func (m *Model) ciFindings(wt *models.WorktreeInfo) []*models.CIAnnotation {
+	if wt == nil || wt.PR == nil || !m.ciFailed(wt.Branch) {
+		return nil
+	}
+	if entry, ok := m.ciAnnotations[wt.Branch]; ok {
+		return entry.annotations
+	}
+	return nil
+}
+
+// ciFindingCounts counts the selected worktree's CI findings per file, for
+// the markers of the status pane.
+func (m *Model) ciFindingCounts() map[string]int {
+	findings := m.ciFindings(m.selectedWorktree())
+	if len(findings) == 0 {
+		return nil
+	}
+	counts := make(map[string]int, len(findings))
+	for _, a := range findings {
+		counts[a.Path]++
+	}
+	return counts
+}
+
+// ciFindingLocation renders where a finding points, e.g. "pkg/a.go:3-4".
+func ciFindingLocation(a *models.CIAnnotation) string {
+	switch {
+	case a.StartLine <= 0:
+		return a.Path
+	case a.EndLine > a.StartLine:
+		return fmt.Sprintf("%s:%d-%d", a.Path, a.StartLine, a.EndLine)
+	default:
+		return fmt.Sprintf("%s:%d", a.Path, a.StartLine)
+	}
+}
+
+// ciFindingSummary is the first line of a finding's message, prefixed with
+// its title.
+func ciFindingSummary(a *models.CIAnnotation) string {
+	message, _, _ := strings.Cut(a.Message, "\n")
+	if a.Title != "" && a.Title != message {
+		return a.Title + ": " + message
+	}
+	return message
+}
+
+// ciFindingSymbol marks failures apart from warnings and notices.
+func ciFindingSymbol(a *models.CIAnnotation) string {
+	if a.Level == "failure" {
+		return "✗"
+	}
+	return "!"
+}
+
+// ciFindingLines lists, in the info pane, the first CI findings of the
+// worktree's PR.
+func (m *Model) ciFindingLines(wt *models.WorktreeInfo, labelStyle lipgloss.Style) []string {
+	findings := m.ciFindings(wt)
+	if len(findings) == 0 {
+		return nil
+	}
+	redStyle := lipgloss.NewStyle().Foreground(m.theme.ErrorFg)
+	yellowStyle := lipgloss.NewStyle().Foreground(m.theme.WarnFg)
+	mutedStyle := lipgloss.NewStyle().Foreground(m.theme.MutedFg)
+	lines := []string{"", labelStyle.Render("CI Findings:")}
+	for i, a := range findings {
+		if i == ciFindingsInfoLimit {
+			lines = append(lines, mutedStyle.Render(fmt.Sprintf("  … %d more (palette: CI findings)", len(findings)-i)))
+			break
+		}
+		style := yellowStyle
+		if a.Level == "failure" {
+			style = redStyle
+		}
+		lines = append(lines, fmt.Sprintf("  %s %s %s", style.Render(ciFindingSymbol(a)), ciFindingLocation(a), mutedStyle.Render(ciFindingSummary(a))))
+	}
+	return lines
+}
+
+// ciFindingsReport lists the worktree's CI findings, limited to files when
+// given, for the top of the diff view.
+func (m *Model) ciFindingsReport(wt *models.WorktreeInfo, files ...string) string {
+	var b strings.Builder
+	for _, a := range m.ciFindings(wt) {
+		if len(files) > 0 && !slices.Contains(files, a.Path) {
+			continue
+		}
+		fmt.Fprintf(&b, "%s %s [%s] %s\n", ciFindingSymbol(a), ciFindingLocation(a), a.Check, ciFindingSummary(a))
+	}
+	if b.Len() == 0 {
+		return ""
+	}
+	return "=== CI findings ===\n" + b.String()
+}
+
+// showCIFindings lists the CI findings of the selected worktree's PR;
+// choosing one opens its file at the line in the editor.
+func (m *Model) showCIFindings() tea.Cmd {
+	wt := m.selectedWorktree()
+	if wt == nil {
+		return nil
+	}
+	name := filepath.Base(wt.Path)
+	findings := m.ciFindings(wt)
+	if len(findings) == 0 {
+		if wt.PR != nil && m.ciFailed(wt.Branch) {
+			if _, ok := m.ciAnnotations[wt.Branch]; !ok {
+				m.statusContent = "Fetching CI findings..."
+				return m.maybeFetchCIAnnotations()
+			}
+		}
+		m.showInfo(fmt.Sprintf("No CI findings for %s.\n\nFindings are the annotations, such as compiler errors, that failing GitHub checks attach to files.", name), nil)
+		return nil
+	}
+	items := make([]selectionItem, 0, len(findings))
+	for i, a := range findings {
+		items = append(items, selectionItem{
+			id:          strconv.Itoa(i),
+			label:       fmt.Sprintf("%s %s", ciFindingSymbol(a), ciFindingLocation(a)),
+			description: fmt.Sprintf("[%s] %s", a.Check, ciFindingSummary(a)),
+		})
+	}
+	m.listScreen = NewListSelectionScreen(items, fmt.Sprintf("CI findings for %s", name), "Filter findings...", "No matching findings.", m.windowWidth, m.windowHeight, "", m.theme)
+	m.listSubmit = func(item selectionItem) tea.Cmd {
+		m.clearListSelection()
+		i, err := strconv.Atoi(item.id)
+		if err != nil || i < 0 || i >= len(findings) {
+			return nil
+		}
+		return m.openFileInEditor(findings[i].Path, findings[i].StartLine)
+	}
+	m.currentScreen = screenListSelect
+	return textinput.Blink
+}
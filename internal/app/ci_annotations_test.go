@@ -0,0 +1,91 @@
+package app
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chmouel/lazyworktree/internal/config"
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+func TestCIFindings(t *testing.T) {
+	cfg := &config.AppConfig{WorktreeDir: t.TempDir()}
+	m := NewModel(cfg, "")
+	wt := &models.WorktreeInfo{Path: t.TempDir(), Branch: "feature", PR: &models.PRInfo{Number: 7, URL: "https://example.com/pull/7"}}
+	m.worktrees = []*models.WorktreeInfo{wt}
+	m.filteredWts = m.worktrees
+	m.selectedIndex = 0
+	m.setStatusFiles([]StatusFile{{Filename: "pkg/a.go", Status: ".M"}, {Filename: "pkg/c.go", Status: ".M"}})
+
+	if m.maybeFetchCIAnnotations() != nil {
+		t.Fatal("expected no findings to be fetched while CI passes")
+	}
+	m.ciCache[wt.Branch] = &ciCacheEntry{checks: []*models.CICheck{{Name: "build", Conclusion: "failure"}}, fetchedAt: time.Now()}
+	if m.maybeFetchCIAnnotations() == nil {
+		t.Fatal("expected failing CI to fetch the findings")
+	}
+	if m.maybeFetchCIAnnotations() != nil {
+		t.Fatal("expected findings being fetched not to be fetched again")
+	}
+
+	m.handleCIAnnotationsLoaded(ciAnnotationsLoadedMsg{branch: wt.Branch, annotations: []*models.CIAnnotation{
+		{Check: "build", Path: "pkg/a.go", StartLine: 3, EndLine: 4, Level: "failure", Message: "undefined: foo\nmore detail"},
+		{Check: "lint", Path: "pkg/a.go", StartLine: 9, Level: "warning", Title: "errcheck", Message: "unchecked error"},
+		{Check: "lint", Path: "pkg/b.go", StartLine: 1, Level: "failure", Message: "syntax error"},
+	}})
+	for _, want := range []string{"CI Findings:", "pkg/a.go:3-4", "undefined: foo", "pkg/a.go:9", "errcheck: unchecked error"} {
+		if !strings.Contains(m.infoContent, want) {
+			t.Fatalf("expected %q in the info pane, got %q", want, m.infoContent)
+		}
+	}
+	if strings.Contains(m.infoContent, "more detail") {
+		t.Fatal("expected only the first line of a message")
+	}
+
+	status := m.renderStatusFiles()
+	var aLine, cLine string
+	for line := range strings.SplitSeq(status, "\n") {
+		switch {
+		case strings.Contains(line, "a.go"):
+			aLine = line
+		case strings.Contains(line, "c.go"):
+			cLine = line
+		}
+	}
+	if !strings.Contains(aLine, "✗2") || strings.Contains(cLine, "✗") {
+		t.Fatalf("expected a.go alone to be marked, got %q and %q", aLine, cLine)
+	}
+
+	report := m.ciFindingsReport(wt, "pkg/b.go")
+	if !strings.Contains(report, "=== CI findings ===") || !strings.Contains(report, "✗ pkg/b.go:1 [lint] syntax error") || strings.Contains(report, "a.go") {
+		t.Fatalf("unexpected diff report %q", report)
+	}
+
+	_ = m.showCIFindings()
+	if m.currentScreen != screenListSelect || len(m.listScreen.items) != 3 {
+		t.Fatalf("expected the findings to be listed, got %s", screenName(m.currentScreen))
+	}
+
+	m.ciCache[wt.Branch].checks[0].Conclusion = "success"
+	if len(m.ciFindings(wt)) != 0 {
+		t.Fatal("expected findings to be hidden once CI passes")
+	}
+}
+
+func TestEditorFileArgs(t *testing.T) {
+	tests := []struct {
+		editor string
+		line   int
+		want   string
+	}{
+		{"nvim", 0, "'a b.go'"},
+		{"nvim", 12, "+12 'a b.go'"},
+		{"/usr/bin/code --wait", 12, "--goto 'a b.go:12'"},
+	}
+	for _, tt := range tests {
+		if got := editorFileArgs(tt.editor, "a b.go", tt.line); got != tt.want {
+			t.Errorf("editorFileArgs(%q, %d) = %q, want %q", tt.editor, tt.line, got, tt.want)
+		}
+	}
+}
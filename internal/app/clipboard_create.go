@@ -0,0 +1,245 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/chmouel/lazyworktree/internal/models"
+	"github.com/chmouel/lazyworktree/internal/utils"
+)
+
+// forgeURLRe matches a GitHub or GitLab pull request, merge request or
+// issue URL, capturing the URL up to its number, the kind and the number.
+var forgeURLRe = regexp.MustCompile(`^(https?://[^\s?#]+?/(?:-/)?(pull|merge_requests|issues)/(\d+))(?:[/?#]\S*)?$`)
+
+// branchCommandPrefixes are git commands often copied along with a branch
+// name.
+var branchCommandPrefixes = []string{"git checkout -b ", "git switch -c ", "git checkout ", "git switch ", "git push -u origin ", "git pull origin "}
+
+// clipboardCreateMsg carries what the clipboard asked to create a worktree
+// from: an open PR/MR, an open issue or a branch name.
+type clipboardCreateMsg struct {
+	pr     *models.PRInfo
+	issue  *models.IssueInfo
+	branch string // sanitised name for the new branch
+	base   string // ref to create the branch from
+	err    error
+}
+
+// clipboardPasteCommand returns the clipboard reader available on this
+// platform.
+func clipboardPasteCommand() []string {
+	switch runtime.GOOS {
+	case osDarwin:
+		return []string{"pbpaste"}
+	case osWindows:
+		return []string{"powershell", "-NoProfile", "-Command", "Get-Clipboard"}
+	}
+	candidates := [][]string{
+		{"xclip", "-selection", "clipboard", "-o"},
+		{"xsel", "--clipboard", "--output"},
+		{"wl-paste", "--no-newline"},
+	}
+	if os.Getenv("WAYLAND_DISPLAY") != "" {
+		candidates = append([][]string{{"wl-paste", "--no-newline"}}, candidates...)
+	}
+	for _, candidate := range candidates {
+		if _, err := exec.LookPath(candidate[0]); err == nil {
+			return candidate
+		}
+	}
+	return nil
+}
+
+// readClipboard returns the text in the system clipboard.
+func (m *Model) readClipboard() (string, error) {
+	args := clipboardPasteCommand()
+	if len(args) == 0 {
+		return "", errors.New("no clipboard tool found (install wl-paste, xclip or xsel)")
+	}
+	out, err := m.commandRunner(args[0], args[1:]...).Output()
+	if err != nil {
+		return "", fmt.Errorf("reading the clipboard: %w", err)
+	}
+	return string(out), nil
+}
+
+// parseForgeURL returns the kind ("pr" or "issue"), number and URL up to
+// the number of a PR/MR or issue URL.
+func parseForgeURL(text string) (kind string, number int, link string, ok bool) {
+	match := forgeURLRe.FindStringSubmatch(strings.TrimSpace(text))
+	if match == nil {
+		return "", 0, "", false
+	}
+	number, err := strconv.Atoi(match[3])
+	if err != nil {
+		return "", 0, "", false
+	}
+	kind = "pr"
+	if match[2] == "issues" {
+		kind = "issue"
+	}
+	return kind, number, match[1], true
+}
+
+// sameForgeURL reports whether a PR/issue's URL is link, ignoring case and
+// anything after the number. Items without a URL match on number alone.
+func sameForgeURL(itemURL, link string) bool {
+	if itemURL == "" {
+		return true
+	}
+	_, _, itemLink, ok := parseForgeURL(itemURL)
+	return ok && strings.EqualFold(itemLink, link)
+}
+
+// clipboardBranchName picks the branch name out of copied text: the first
+// non-empty line without quotes, or the first argument of a copied git
+// command.
+func clipboardBranchName(text string) string {
+	line := ""
+	for candidate := range strings.SplitSeq(text, "\n") {
+		if line = strings.TrimSpace(candidate); line != "" {
+			break
+		}
+	}
+	line = strings.Trim(line, "`'\" ")
+	for _, prefix := range branchCommandPrefixes {
+		if rest, ok := strings.CutPrefix(line, prefix); ok {
+			if fields := strings.Fields(rest); len(fields) > 0 {
+				line = strings.Trim(fields[0], "`'\"")
+			}
+			break
+		}
+	}
+	return strings.TrimPrefix(line, "refs/heads/")
+}
+
+// showCreateFromClipboard creates a worktree from the clipboard: a PR/MR or
+// issue URL goes through the matching create flow, and anything else is
+// taken as a branch name for the branch name prompt.
+func (m *Model) showCreateFromClipboard() tea.Cmd {
+	if m.readOnlyDenied("Creating worktrees") {
+		return nil
+	}
+	m.loading = true
+	m.loadingScreen = NewLoadingScreen("Reading the clipboard...", m.theme)
+	m.currentScreen = screenLoading
+	return func() tea.Msg {
+		text, err := m.readClipboard()
+		if err != nil {
+			return clipboardCreateMsg{err: err}
+		}
+		if kind, number, link, ok := parseForgeURL(text); ok {
+			return m.findClipboardItem(kind, number, link)
+		}
+
+		name := clipboardBranchName(text)
+		if utils.SanitizeBranchName(name, 0) == "" {
+			return clipboardCreateMsg{err: errors.New("the clipboard holds no branch name or PR/issue URL")}
+		}
+		remotes := m.remoteBranches()
+		return clipboardCreateMsg{
+			branch: sanitizeBranchNameFromTitle(stripRemoteBranch(remotes, name), ""),
+			base:   m.clipboardBase(remotes, name),
+		}
+	}
+}
+
+// findClipboardItem looks the PR/MR or issue up among the open ones.
+func (m *Model) findClipboardItem(kind string, number int, link string) clipboardCreateMsg {
+	if kind == "issue" {
+		issues, err := m.git.FetchAllOpenIssues(m.ctx)
+		if err != nil {
+			return clipboardCreateMsg{err: fmt.Errorf("failed to fetch issues: %w", err)}
+		}
+		for _, issue := range issues {
+			if issue.Number == number && sameForgeURL(issue.URL, link) {
+				return clipboardCreateMsg{issue: issue}
+			}
+		}
+		return clipboardCreateMsg{err: fmt.Errorf("issue #%d is not an open issue of this repository", number)}
+	}
+	prs, err := m.git.FetchAllOpenPRs(m.ctx)
+	if err != nil {
+		return clipboardCreateMsg{err: fmt.Errorf("failed to fetch PRs: %w", err)}
+	}
+	for _, pr := range prs {
+		if pr.Number == number && sameForgeURL(pr.URL, link) {
+			return clipboardCreateMsg{pr: pr}
+		}
+	}
+	return clipboardCreateMsg{err: fmt.Errorf("PR/MR #%d is not an open PR/MR of this repository", number)}
+}
+
+// remoteBranches lists the remote-tracking branches, such as origin/main.
+func (m *Model) remoteBranches() []string {
+	raw := m.git.RunGit(m.ctx, []string{"git", "for-each-ref", "--format=%(refname:short)", "refs/remotes"}, "", []int{0}, true, true)
+	branches := []string{}
+	for line := range strings.SplitSeq(raw, "\n") {
+		if line = strings.TrimSpace(line); line != "" && !strings.HasSuffix(line, "/HEAD") {
+			branches = append(branches, line)
+		}
+	}
+	return branches
+}
+
+// stripRemoteBranch drops the remote from name when it names one of the
+// remote-tracking branches, so origin/feature becomes feature.
+func stripRemoteBranch(remotes []string, name string) string {
+	for _, remote := range remotes {
+		if remote == name {
+			return stripRemotePrefix(name)
+		}
+	}
+	return name
+}
+
+// clipboardBase returns the remote-tracking branch a copied branch name
+// refers to, so a colleague's pushed branch is checked out as it is, or
+// the main branch.
+func (m *Model) clipboardBase(remotes []string, name string) string {
+	for _, remote := range remotes {
+		if remote == name || stripRemotePrefix(remote) == name {
+			return remote
+		}
+	}
+	return m.git.GetMainBranch(m.ctx)
+}
+
+// handleClipboardCreate pre-fills the create flow with what the clipboard
+// held.
+func (m *Model) handleClipboardCreate(msg clipboardCreateMsg) tea.Cmd {
+	m.loading = false
+	if m.currentScreen == screenLoading {
+		m.currentScreen = screenNone
+		m.loadingScreen = nil
+	}
+	switch {
+	case msg.err != nil:
+		m.showInfo(fmt.Sprintf("Cannot create a worktree from the clipboard: %v", msg.err), nil)
+		return nil
+	case msg.pr != nil:
+		// Go on as though the PR had been picked from the list.
+		m.handleOpenPRsLoaded(openPRsLoadedMsg{prs: []*models.PRInfo{msg.pr}})
+		submit := m.prSelectionSubmit
+		m.prSelectionScreen = nil
+		m.prSelectionSubmit = nil
+		return submit(msg.pr)
+	case msg.issue != nil:
+		m.handleOpenIssuesLoaded(openIssuesLoadedMsg{issues: []*models.IssueInfo{msg.issue}})
+		submit := m.issueSelectionSubmit
+		m.issueSelectionScreen = nil
+		m.issueSelectionSubmit = nil
+		return submit(msg.issue)
+	}
+	cmd := m.showBranchNameInput(msg.base, msg.branch)
+	m.inputScreen.prompt = fmt.Sprintf("Create worktree from %s: branch name", msg.base)
+	return cmd
+}
@@ -0,0 +1,118 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/chmouel/lazyworktree/internal/config"
+)
+
+// fakeClipboard puts clipboard readers printing text first on PATH.
+func fakeClipboard(t *testing.T, text string) {
+	t.Helper()
+	if runtime.GOOS == osWindows {
+		t.Skip("uses shell scripts as clipboard readers")
+	}
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "clipboard.txt"), []byte(text), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	script := "#!/bin/sh\ncat '" + filepath.Join(dir, "clipboard.txt") + "'\n"
+	for _, name := range []string{"xclip", "pbpaste"} {
+		// #nosec G306 -- the fake reader has to be executable
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(script), 0o700); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv("WAYLAND_DISPLAY", "")
+}
+
+func TestParseForgeURL(t *testing.T) {
+	tests := []struct {
+		text   string
+		kind   string
+		number int
+		link   string
+	}{
+		{"https://github.com/o/r/pull/12", "pr", 12, "https://github.com/o/r/pull/12"},
+		{" https://github.com/o/r/pull/12/files#diff-1\n", "pr", 12, "https://github.com/o/r/pull/12"},
+		{"https://gitlab.com/g/sub/r/-/merge_requests/7", "pr", 7, "https://gitlab.com/g/sub/r/-/merge_requests/7"},
+		{"https://github.com/o/r/issues/45?q=1", "issue", 45, "https://github.com/o/r/issues/45"},
+		{"https://gitlab.com/g/r/-/issues/3", "issue", 3, "https://gitlab.com/g/r/-/issues/3"},
+		{"feature/login", "", 0, ""},
+		{"see https://github.com/o/r/pull/12", "", 0, ""},
+	}
+	for _, tt := range tests {
+		kind, number, link, ok := parseForgeURL(tt.text)
+		if ok != (tt.kind != "") || kind != tt.kind || number != tt.number || link != tt.link {
+			t.Errorf("parseForgeURL(%q) = %q %d %q %v", tt.text, kind, number, link, ok)
+		}
+	}
+	if !sameForgeURL("https://GitHub.com/o/r/pull/12", "https://github.com/o/r/pull/12") || sameForgeURL("https://github.com/x/r/pull/12", "https://github.com/o/r/pull/12") {
+		t.Error("expected URLs to be compared up to the number")
+	}
+}
+
+func TestClipboardBranchName(t *testing.T) {
+	for text, want := range map[string]string{
+		"feature/login\n":                   "feature/login",
+		"\n  `fix/crash-on-start`  ":        "fix/crash-on-start",
+		"git checkout -b feature/x":         "feature/x",
+		"git switch chore/deps origin/main": "chore/deps",
+		"\"refs/heads/topic\"":              "topic",
+		"origin/feature/login":              "origin/feature/login",
+	} {
+		if got := clipboardBranchName(text); got != want {
+			t.Errorf("clipboardBranchName(%q) = %q, want %q", text, got, want)
+		}
+	}
+	remotes := []string{"origin/main", "origin/feature/login"}
+	if got := stripRemoteBranch(remotes, "origin/feature/login"); got != "feature/login" {
+		t.Errorf("expected the remote to be dropped, got %q", got)
+	}
+	if got := stripRemoteBranch(remotes, "feature/login"); got != "feature/login" {
+		t.Errorf("expected a local name to be kept, got %q", got)
+	}
+}
+
+func TestCreateFromClipboardBranch(t *testing.T) {
+	repo := initTestRepo(t)
+	withCwd(t, repo.dir)
+	runGit(t, repo.dir, "update-ref", "refs/remotes/origin/feature/login", "HEAD")
+	m := NewModel(&config.AppConfig{WorktreeDir: t.TempDir()}, "")
+
+	fakeClipboard(t, "git checkout -b feature/login\n")
+	msg, ok := m.showCreateFromClipboard()().(clipboardCreateMsg)
+	if !ok || msg.err != nil {
+		t.Fatalf("unexpected result %+v", msg)
+	}
+	if msg.base != "origin/feature/login" || msg.branch != "feature-login" {
+		t.Fatalf("expected feature-login from origin/feature/login, got %q from %q", msg.branch, msg.base)
+	}
+	_ = m.handleClipboardCreate(msg)
+	if m.currentScreen != screenInput || m.inputScreen.input.Value() != "feature-login" {
+		t.Fatalf("expected the branch name prompt, got %s", screenName(m.currentScreen))
+	}
+	if !strings.Contains(m.inputScreen.prompt, "origin/feature/login") {
+		t.Fatalf("expected the base in the prompt, got %q", m.inputScreen.prompt)
+	}
+
+	fakeClipboard(t, "Quick fix for #12")
+	msg = m.showCreateFromClipboard()().(clipboardCreateMsg)
+	if msg.base != m.git.GetMainBranch(m.ctx) || msg.branch != "quick-fix-for-12" {
+		t.Fatalf("expected quick-fix-for-12 from the main branch, got %q from %q", msg.branch, msg.base)
+	}
+}
+
+func TestCreateFromClipboardEmpty(t *testing.T) {
+	m := NewModel(&config.AppConfig{WorktreeDir: t.TempDir()}, "")
+	fakeClipboard(t, " \n")
+	_ = m.handleClipboardCreate(m.showCreateFromClipboard()().(clipboardCreateMsg))
+	if m.currentScreen != screenInfo || !strings.Contains(m.infoScreen.message, "no branch name") {
+		t.Fatalf("expected an explanation, got %s", screenName(m.currentScreen))
+	}
+}
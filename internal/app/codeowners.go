@@ -0,0 +1,145 @@
+package app
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/chmouel/lazyworktree/internal/codeowners"
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+const (
+	// codeOwnersInfoLimit is how many owners the info pane names.
+	codeOwnersInfoLimit = 4
+	// codeOwnersFileLimit is how many files the summary lists per owner.
+	codeOwnersFileLimit = 20
+)
+
+// codeOwnersMsg carries the main worktree's CODEOWNERS file, nil when it has
+// none.
+type codeOwnersMsg struct {
+	file *codeowners.File
+	err  error
+}
+
+// codeOwnersSummaryMsg carries who owns a worktree's changed files.
+type codeOwnersSummaryMsg struct {
+	name    string
+	base    string
+	summary codeowners.Summary
+}
+
+// loadCodeOwners reads the main worktree's CODEOWNERS once, in the
+// background.
+func (m *Model) loadCodeOwners() tea.Cmd {
+	if m.codeOwnersChecked {
+		return nil
+	}
+	m.codeOwnersChecked = true
+	root := m.getMainWorktreePath()
+	return func() tea.Msg {
+		file, err := codeowners.Load(root)
+		return codeOwnersMsg{file: file, err: err}
+	}
+}
+
+// handleCodeOwners stores the CODEOWNERS rules and refreshes the selected
+// worktree so its info pane names the owners of its changes.
+func (m *Model) handleCodeOwners(msg codeOwnersMsg) tea.Cmd {
+	if msg.err != nil {
+		m.debugf("codeowners: %v", msg.err)
+		return nil
+	}
+	m.codeOwners = msg.file
+	if msg.file == nil {
+		return nil
+	}
+	m.debugf("codeowners: %d rules from %s", len(msg.file.Rules), msg.file.Path)
+	return m.updateDetailsView()
+}
+
+// setCodeOwnership records who owns a worktree's changed files.
+func (m *Model) setCodeOwnership(path string, files []string) {
+	if m.codeOwners == nil {
+		return
+	}
+	if m.codeOwnership == nil {
+		m.codeOwnership = make(map[string]codeowners.Summary)
+	}
+	m.codeOwnership[path] = m.codeOwners.Summarise(files)
+}
+
+// codeOwnerLines names the owners of the worktree's changes, for the info
+// pane.
+func (m *Model) codeOwnerLines(wt *models.WorktreeInfo, labelStyle, valueStyle lipgloss.Style) []string {
+	summary, ok := m.codeOwnership[wt.Path]
+	if !ok || m.codeOwners == nil || (len(summary.Owners) == 0 && len(summary.Unowned) == 0) {
+		return nil
+	}
+	mutedStyle := lipgloss.NewStyle().Foreground(m.theme.MutedFg)
+	parts := make([]string, 0, codeOwnersInfoLimit+2)
+	for _, owner := range summary.Owners[:min(len(summary.Owners), codeOwnersInfoLimit)] {
+		parts = append(parts, valueStyle.Render(owner.Name)+mutedStyle.Render(fmt.Sprintf(" (%d)", len(owner.Files))))
+	}
+	if extra := len(summary.Owners) - codeOwnersInfoLimit; extra > 0 {
+		parts = append(parts, mutedStyle.Render(fmt.Sprintf("+%d more", extra)))
+	}
+	if len(summary.Unowned) > 0 {
+		parts = append(parts, mutedStyle.Render(fmt.Sprintf("%d unowned", len(summary.Unowned))))
+	}
+	return []string{fmt.Sprintf("%s %s", labelStyle.Render("Owners:"), strings.Join(parts, ", "))}
+}
+
+// showCodeOwners summarises who owns the selected worktree's changes, and
+// so who a PR will need reviews from.
+func (m *Model) showCodeOwners() tea.Cmd {
+	if m.codeOwners == nil {
+		m.showInfo(fmt.Sprintf("No CODEOWNERS file was found.\n\nIt is looked for in the main worktree at %s.", strings.Join(codeowners.Locations, ", ")), nil)
+		return nil
+	}
+	wt := m.selectedWorktree()
+	if wt == nil {
+		return nil
+	}
+	name := filepath.Base(wt.Path)
+	if wt.Bare || wt.Prunable {
+		m.showInfo(fmt.Sprintf("%s has no working tree whose changes could be owned.", name), nil)
+		return nil
+	}
+	path := wt.Path
+	owners := m.codeOwners
+	return func() tea.Msg {
+		base := m.git.GetMainBranch(m.ctx)
+		files := m.git.BranchChangedFiles(m.ctx, base, path)
+		return codeOwnersSummaryMsg{name: name, base: base, summary: owners.Summarise(files)}
+	}
+}
+
+// handleCodeOwnersSummary shows the owners of each changed file.
+func (m *Model) handleCodeOwnersSummary(msg codeOwnersSummaryMsg) {
+	if len(msg.summary.Owners) == 0 && len(msg.summary.Unowned) == 0 {
+		m.showInfo(fmt.Sprintf("%s has no changes since %s.", msg.name, msg.base), nil)
+		return
+	}
+	var body strings.Builder
+	fmt.Fprintf(&body, "Changes since `%s`, with owners from `%s`. On GitHub and GitLab, these owners are asked to review the PR/MR.\n", msg.base, m.codeOwners.Path)
+	section := func(title string, files []string) {
+		fmt.Fprintf(&body, "\n## %s (%d)\n\n", title, len(files))
+		for _, file := range files[:min(len(files), codeOwnersFileLimit)] {
+			fmt.Fprintf(&body, "- `%s`\n", file)
+		}
+		if extra := len(files) - codeOwnersFileLimit; extra > 0 {
+			fmt.Fprintf(&body, "- …and %d more\n", extra)
+		}
+	}
+	for _, owner := range msg.summary.Owners {
+		section(owner.Name, owner.Files)
+	}
+	if len(msg.summary.Unowned) > 0 {
+		section("Unowned", msg.summary.Unowned)
+	}
+	m.showMarkdown(fmt.Sprintf("Code owners of %s", msg.name), "", body.String(), screenNone)
+}
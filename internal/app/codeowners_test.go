@@ -0,0 +1,82 @@
+package app
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/chmouel/lazyworktree/internal/config"
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+func TestCodeOwners(t *testing.T) {
+	repo := t.TempDir()
+	runGit(t, repo, "init", "-b", "main")
+	runGit(t, repo, "config", "user.email", "test@example.com")
+	runGit(t, repo, "config", "user.name", "Test User")
+	runGit(t, repo, "config", "commit.gpgsign", "false")
+	writeRepoFile(t, repo, ".github/CODEOWNERS", "* @org/core\n/web/ @org/web\n")
+	writeRepoFile(t, repo, "README.md", "readme\n")
+	runGit(t, repo, "add", ".")
+	runGit(t, repo, "commit", "-m", "Initial commit")
+	withCwd(t, repo)
+
+	wtPath := filepath.Join(t.TempDir(), "web-work")
+	runGit(t, repo, "worktree", "add", "-b", "web-work", wtPath, "main")
+	writeRepoFile(t, wtPath, "web/index.js", "\n")
+	writeRepoFile(t, wtPath, "web/app.js", "\n")
+	runGit(t, wtPath, "add", ".")
+	runGit(t, wtPath, "commit", "-m", "Add web")
+	writeRepoFile(t, wtPath, "main.go", "package main\n")
+
+	m := NewModel(&config.AppConfig{WorktreeDir: t.TempDir()}, "")
+	m.worktrees = []*models.WorktreeInfo{{Path: repo, Branch: "main", IsMain: true}, {Path: wtPath, Branch: "web-work"}}
+	m.updateTable()
+	for i, wt := range m.filteredWts {
+		if wt.Path == wtPath {
+			m.worktreeTable.SetCursor(i)
+			m.selectedIndex = i
+		}
+	}
+
+	loaded, ok := m.loadCodeOwners()().(codeOwnersMsg)
+	if !ok || loaded.err != nil || loaded.file == nil {
+		t.Fatalf("expected the CODEOWNERS file, got %+v", loaded)
+	}
+	_ = m.handleCodeOwners(loaded)
+	if m.loadCodeOwners() != nil {
+		t.Fatal("expected CODEOWNERS to be read once")
+	}
+
+	changed := m.branchChangedFiles(m.worktrees[1])
+	if len(changed) != 3 {
+		t.Fatalf("expected three changed files, got %v", changed)
+	}
+	m.setCodeOwnership(wtPath, changed)
+	lines := m.codeOwnerLines(m.worktrees[1], lipgloss.NewStyle(), lipgloss.NewStyle())
+	if len(lines) != 1 || lines[0] != "Owners: @org/web (2), @org/core (1)" {
+		t.Fatalf("unexpected owner lines %q", lines)
+	}
+
+	summary, ok := m.showCodeOwners()().(codeOwnersSummaryMsg)
+	if !ok || summary.base != "main" || len(summary.summary.Owners) != 2 {
+		t.Fatalf("unexpected summary %+v", summary)
+	}
+	m.handleCodeOwnersSummary(summary)
+	if m.currentScreen != screenMarkdown || !strings.Contains(m.markdownScreen.source, "## @org/web (2)") {
+		t.Fatalf("expected the owners to be listed, got %s", screenName(m.currentScreen))
+	}
+}
+
+func TestCodeOwnersMissing(t *testing.T) {
+	m := NewModel(&config.AppConfig{WorktreeDir: t.TempDir()}, "")
+	m.worktrees = []*models.WorktreeInfo{{Path: t.TempDir(), Branch: "main"}}
+	m.filteredWts = m.worktrees
+	if cmd := m.showCodeOwners(); cmd != nil || m.currentScreen != screenInfo {
+		t.Fatal("expected a repository without CODEOWNERS to be refused")
+	}
+	if changed := m.branchChangedFiles(m.worktrees[0]); changed != nil {
+		t.Fatalf("expected no diff without projects or owners, got %v", changed)
+	}
+}
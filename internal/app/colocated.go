@@ -0,0 +1,70 @@
+package app
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/chmouel/lazyworktree/internal/git"
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+// colocatedMsg reports the tools managing the repository alongside git and,
+// for Jujutsu, the change ID of each worktree's commit.
+type colocatedMsg struct {
+	info      git.Colocated
+	changeIDs map[string]string // commit -> change ID
+}
+
+// loadColocated detects Jujutsu and git-branchless in the background.
+func (m *Model) loadColocated() tea.Cmd {
+	mainPath := ""
+	commits := make([]string, 0, len(m.worktrees))
+	for _, wt := range m.worktrees {
+		if wt.IsMain {
+			mainPath = wt.Path
+		}
+		commits = append(commits, wt.Head)
+	}
+	if mainPath == "" {
+		return nil
+	}
+	return func() tea.Msg {
+		msg := colocatedMsg{info: m.git.Colocated(m.ctx, mainPath)}
+		if msg.info.Jujutsu {
+			msg.changeIDs = m.git.ChangeIDs(m.ctx, mainPath, commits)
+		}
+		return msg
+	}
+}
+
+// handleColocated records the tools and refreshes the info pane.
+func (m *Model) handleColocated(msg colocatedMsg) {
+	m.colocated = msg.info
+	m.changeIDs = msg.changeIDs
+	if wt := m.selectedWorktree(); wt != nil {
+		m.infoContent = m.buildInfoContent(wt)
+	}
+}
+
+// changeLines shows, in the info pane, the Jujutsu change the worktree's
+// commit belongs to.
+func (m *Model) changeLines(wt *models.WorktreeInfo, labelStyle, valueStyle lipgloss.Style) []string {
+	id := m.changeIDs[wt.Head]
+	if !m.colocated.Jujutsu || id == "" {
+		return nil
+	}
+	return []string{fmt.Sprintf("%s %s", labelStyle.Render("Change:"), valueStyle.Render(id))}
+}
+
+// colocatedRestackHint explains, when Jujutsu or git-branchless manages the
+// repository, how to restack with it instead. It is empty otherwise.
+func (m *Model) colocatedRestackHint(branch string) string {
+	switch {
+	case m.colocated.Jujutsu:
+		return fmt.Sprintf("This repository is managed by Jujutsu, which rebases descendants itself when it rewrites a change.\n\nRun jj rebase -s 'children(%s)' -d %s instead.", branch, branch)
+	case m.colocated.Branchless:
+		return "This repository is managed by git-branchless.\n\nRun git restack instead, so its commit graph stays in step."
+	}
+	return ""
+}
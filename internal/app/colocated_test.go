@@ -0,0 +1,73 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/chmouel/lazyworktree/internal/config"
+	"github.com/chmouel/lazyworktree/internal/git"
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+func TestColocatedJujutsu(t *testing.T) {
+	repo := t.TempDir()
+	runGit(t, repo, "init", "-b", "main")
+	runGit(t, repo, "config", "user.email", "test@example.com")
+	runGit(t, repo, "config", "user.name", "Test User")
+	runGit(t, repo, "config", "commit.gpgsign", "false")
+	writeRepoFile(t, repo, "README.md", "readme\n")
+	runGit(t, repo, "add", ".")
+	runGit(t, repo, "commit", "-m", "Initial commit")
+	if err := os.Mkdir(filepath.Join(repo, ".jj"), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	withCwd(t, repo)
+	featureWt := filepath.Join(t.TempDir(), "feature")
+	runGit(t, repo, "worktree", "add", "-b", "feature", featureWt, "main")
+
+	m := NewModel(&config.AppConfig{WorktreeDir: t.TempDir()}, "")
+	m.setWindowSize(120, 40)
+	m.worktrees = []*models.WorktreeInfo{
+		{Path: repo, Branch: "main", IsMain: true, Head: "abc123"},
+		{Path: featureWt, Branch: "feature", Head: "def456"},
+	}
+	m.filteredWts = m.worktrees
+
+	msg, ok := m.loadColocated()().(colocatedMsg)
+	if !ok || !msg.info.Jujutsu || msg.info.Branchless {
+		t.Fatalf("expected a colocated Jujutsu repository, got %+v", msg)
+	}
+	msg.changeIDs = map[string]string{"def456": "kxqyzmno"}
+	m.handleColocated(msg)
+	if header := m.renderHeader(m.computeLayout()); !strings.Contains(header, "jj colocated") {
+		t.Fatalf("expected the header to name jj, got %q", header)
+	}
+	lines := m.changeLines(m.worktrees[1], lipgloss.NewStyle(), lipgloss.NewStyle())
+	if len(lines) != 1 || !strings.Contains(lines[0], "kxqyzmno") {
+		t.Fatalf("expected the change ID, got %v", lines)
+	}
+	if lines := m.changeLines(m.worktrees[0], lipgloss.NewStyle(), lipgloss.NewStyle()); lines != nil {
+		t.Fatalf("expected no change line without an ID, got %v", lines)
+	}
+
+	m.worktreeTable.SetCursor(1)
+	m.stackLinks = map[string]git.StackLink{"child": {Parent: "feature"}}
+	m.showRestack()
+	if m.currentScreen != screenInfo || !strings.Contains(m.infoScreen.message, "jj rebase") {
+		t.Fatalf("expected restack to defer to jj, got %s", screenName(m.currentScreen))
+	}
+
+	pruned, failed := m.removeWorktrees([]*models.WorktreeInfo{m.worktrees[1]}, nil)
+	if pruned != 1 || failed != 0 {
+		t.Fatalf("expected one pruned worktree, got %d pruned, %d failed", pruned, failed)
+	}
+	if _, err := os.Stat(featureWt); !os.IsNotExist(err) {
+		t.Fatalf("expected the worktree to be removed, got %v", err)
+	}
+	if got := runGit(t, repo, "branch", "--list", "feature"); !strings.Contains(got, "feature") {
+		t.Fatal("expected pruning to keep the branch for jj")
+	}
+}
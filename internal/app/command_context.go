@@ -0,0 +1,96 @@
+package app
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/chmouel/lazyworktree/internal/control"
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+// commandContextEnv names the JSON file describing the worktree a command
+// runs for, so that scripts need not piece it together from variables.
+const commandContextEnv = "WT_CONTEXT"
+
+// commandContext is what the WT_CONTEXT file holds.
+type commandContext struct {
+	Repo     commandContextRepo   `json:"repo"`
+	Worktree control.Worktree     `json:"worktree"`
+	Config   commandContextConfig `json:"config"`
+	Env      map[string]string    `json:"env"`
+}
+
+type commandContextRepo struct {
+	Name             string `json:"name"`
+	MainWorktreePath string `json:"main_worktree_path"`
+	WorktreeDir      string `json:"worktree_dir"`
+}
+
+// commandContextConfig is the part of the repository's .wt file a setup
+// script may want.
+type commandContextConfig struct {
+	Path              string   `json:"path,omitempty"`
+	InitCommands      []string `json:"init_commands,omitempty"`
+	TerminateCommands []string `json:"terminate_commands,omitempty"`
+	Services          []string `json:"services,omitempty"`
+}
+
+// writeCommandContext writes the WT_CONTEXT file for the worktree env
+// describes and names it in env, returning the function removing it once
+// the commands are done. Nothing is written when commands run over SSH,
+// where the file could not be read.
+func (m *Model) writeCommandContext(env map[string]string) func() {
+	if env == nil || m.git.RemoteHost() != "" {
+		return func() {}
+	}
+	path := env["WORKTREE_PATH"]
+	wt := &models.WorktreeInfo{Path: path, Branch: env["WORKTREE_BRANCH"]}
+	for _, known := range m.worktrees {
+		if known.Path == path {
+			wt = known
+			break
+		}
+	}
+
+	ctx := commandContext{
+		Repo: commandContextRepo{
+			Name:             env["REPO_NAME"],
+			MainWorktreePath: env["MAIN_WORKTREE_PATH"],
+			WorktreeDir:      m.getRepoWorktreeDir(),
+		},
+		Worktree: control.NewWorktree(wt, false),
+		Env:      env,
+	}
+	if rc := m.repoConfig; rc != nil {
+		ctx.Config = commandContextConfig{
+			Path:              rc.Path,
+			InitCommands:      rc.InitCommands,
+			TerminateCommands: rc.TerminateCommands,
+		}
+		for _, svc := range rc.Services {
+			ctx.Config.Services = append(ctx.Config.Services, svc.Name)
+		}
+	}
+	data, err := json.MarshalIndent(ctx, "", "  ")
+	if err != nil {
+		m.debugf("failed to encode command context: %v", err)
+		return func() {}
+	}
+
+	file, err := os.CreateTemp("", "lazyworktree-context-*.json")
+	if err != nil {
+		m.debugf("failed to create command context: %v", err)
+		return func() {}
+	}
+	_, err = file.Write(data)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		m.debugf("failed to write command context: %v", err)
+		_ = os.Remove(file.Name())
+		return func() {}
+	}
+	env[commandContextEnv] = file.Name()
+	return func() { _ = os.Remove(file.Name()) }
+}
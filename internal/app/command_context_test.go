@@ -0,0 +1,78 @@
+package app
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/chmouel/lazyworktree/internal/config"
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+func TestCommandContextFile(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	cfg := &config.AppConfig{WorktreeDir: t.TempDir(), TrustMode: "always"}
+	m := NewModel(cfg, "")
+	wtPath := t.TempDir()
+	m.worktrees = []*models.WorktreeInfo{{Path: wtPath, Branch: "feat", Dirty: true, Ahead: 2, PR: &models.PRInfo{Number: 7, State: "OPEN"}}}
+	m.repoConfig = &config.RepoConfig{Path: "/repo/.wt", InitCommands: []string{"make setup"}, Services: []*config.Service{{Name: "web"}}}
+
+	env := m.buildCommandEnv("feat", wtPath)
+	cmd := m.runCommands([]string{`cp "$WT_CONTEXT" context.json`}, wtPath, env, nil)
+	contextPath := env[commandContextEnv]
+	if contextPath == "" {
+		t.Fatal("expected WT_CONTEXT set for the commands")
+	}
+	cmd()
+
+	data, err := os.ReadFile(filepath.Join(wtPath, "context.json"))
+	if err != nil {
+		t.Fatalf("expected the commands to read the context file: %v", err)
+	}
+	var got commandContext
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("expected JSON, got %q: %v", data, err)
+	}
+	if got.Worktree.Branch != "feat" || !got.Worktree.Dirty || got.Worktree.Ahead != 2 || got.Worktree.PR == nil || got.Worktree.PR.Number != 7 {
+		t.Fatalf("expected the worktree and its PR, got %+v", got.Worktree)
+	}
+	if got.Config.Path != "/repo/.wt" || len(got.Config.InitCommands) != 1 || len(got.Config.Services) != 1 || got.Config.Services[0] != "web" {
+		t.Fatalf("expected the .wt subset, got %+v", got.Config)
+	}
+	if got.Env["WORKTREE_PATH"] != wtPath {
+		t.Fatalf("expected the environment variables, got %v", got.Env)
+	}
+	if _, err := os.Stat(contextPath); !os.IsNotExist(err) {
+		t.Fatalf("expected the context file removed after the commands, got %v", err)
+	}
+}
+
+func TestCommandContextFileForPruneTerminateCommands(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	cfg := &config.AppConfig{WorktreeDir: t.TempDir()}
+	m := NewModel(cfg, "")
+	wtPath := t.TempDir()
+	outDir := t.TempDir()
+	wt := &models.WorktreeInfo{Path: wtPath, Branch: "merged"}
+	m.worktrees = []*models.WorktreeInfo{wt}
+
+	m.removeWorktrees([]*models.WorktreeInfo{wt}, []string{`cp "$WT_CONTEXT" "` + outDir + `/context.json"; echo "$WT_CONTEXT" > "` + outDir + `/path"`})
+
+	data, err := os.ReadFile(filepath.Join(outDir, "context.json"))
+	if err != nil {
+		t.Fatalf("expected terminate commands to read the context file: %v", err)
+	}
+	var got commandContext
+	if err := json.Unmarshal(data, &got); err != nil || got.Worktree.Branch != "merged" {
+		t.Fatalf("expected the pruned worktree in the context, got %q: %v", data, err)
+	}
+	contextPath, err := os.ReadFile(filepath.Join(outDir, "path"))
+	if err != nil {
+		t.Fatalf("read context path: %v", err)
+	}
+	if _, err := os.Stat(strings.TrimSpace(string(contextPath))); !os.IsNotExist(err) {
+		t.Fatalf("expected the context file removed after the commands, got %v", err)
+	}
+}
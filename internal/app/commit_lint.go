@@ -0,0 +1,105 @@
+package app
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/chmouel/lazyworktree/internal/utils"
+)
+
+// defaultCommitTypes are the Conventional Commits types accepted when
+// commit_types is not configured.
+var defaultCommitTypes = []string{
+	"build", "chore", "ci", "docs", "feat", "fix", "perf", "refactor", "revert", "style", "test",
+}
+
+// allowedCommitTypes returns the commit types that satisfy the convention.
+func (m *Model) allowedCommitTypes() []string {
+	if len(m.config.CommitTypes) > 0 {
+		return m.config.CommitTypes
+	}
+	return defaultCommitTypes
+}
+
+// commitTypeColor picks a theme colour for a commit type badge.
+func (m *Model) commitTypeColor(commitType string) lipgloss.Color {
+	switch commitType {
+	case "feat":
+		return m.theme.SuccessFg
+	case "fix":
+		return m.theme.ErrorFg
+	case "perf":
+		return m.theme.Cyan
+	case "docs":
+		return m.theme.Pink
+	case "refactor", "revert":
+		return m.theme.Yellow
+	default:
+		return m.theme.MutedFg
+	}
+}
+
+// formatLogSubject renders a log pane subject, replacing a Conventional Commit
+// type prefix with a coloured badge.
+func (m *Model) formatLogSubject(message string) (badge, subject string) {
+	cc, ok := utils.ParseConventionalCommit(message)
+	if !ok {
+		return "", formatCommitMessage(message)
+	}
+	label := cc.Type
+	if cc.Breaking {
+		label += "!"
+	}
+	badge = lipgloss.NewStyle().Foreground(m.commitTypeColor(cc.Type)).Bold(true).Render(label)
+	subject = cc.Description
+	if cc.Scope != "" {
+		subject = fmt.Sprintf("%s: %s", cc.Scope, cc.Description)
+	}
+	return badge, formatCommitMessage(subject)
+}
+
+// nonConventionalCommits returns the subjects of branch commits (those not on
+// the main branch) in the current log that break the commit convention.
+func (m *Model) nonConventionalCommits() []string {
+	allowed := m.allowedCommitTypes()
+	var offending []string
+	for _, entry := range m.logEntriesAll {
+		if !entry.isUnmerged {
+			continue
+		}
+		cc, ok := utils.ParseConventionalCommit(entry.message)
+		if ok && slices.ContainsFunc(allowed, func(t string) bool { return strings.EqualFold(t, cc.Type) }) {
+			continue
+		}
+		offending = append(offending, entry.message)
+	}
+	return offending
+}
+
+// commitLintLines warns about branch commits that break the convention, for
+// the info pane of the worktree whose log is currently loaded.
+func (m *Model) commitLintLines(path string) []string {
+	if !m.config.CommitLint || path == "" || path != m.currentDetailsPath {
+		return nil
+	}
+	offending := m.nonConventionalCommits()
+	if len(offending) == 0 {
+		return nil
+	}
+	warnStyle := lipgloss.NewStyle().Foreground(m.theme.WarnFg).Bold(true)
+	mutedStyle := lipgloss.NewStyle().Foreground(m.theme.MutedFg)
+	lines := []string{
+		"",
+		warnStyle.Render(fmt.Sprintf("⚠ %d commit(s) do not follow the commit convention:", len(offending))),
+	}
+	const maxShown = 5
+	for _, subject := range offending[:min(len(offending), maxShown)] {
+		lines = append(lines, "  "+mutedStyle.Render(formatCommitMessage(subject)))
+	}
+	if len(offending) > maxShown {
+		lines = append(lines, "  "+mutedStyle.Render(fmt.Sprintf("…and %d more", len(offending)-maxShown)))
+	}
+	return lines
+}
@@ -0,0 +1,84 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/chmouel/lazyworktree/internal/config"
+)
+
+func TestFormatLogSubject(t *testing.T) {
+	m := NewModel(&config.AppConfig{WorktreeDir: t.TempDir()}, "")
+
+	badge, subject := m.formatLogSubject("feat(ui)!: add badges")
+	if !strings.Contains(badge, "feat!") {
+		t.Fatalf("expected breaking feat badge, got %q", badge)
+	}
+	if subject != "ui: add badges" {
+		t.Fatalf("unexpected subject %q", subject)
+	}
+
+	badge, subject = m.formatLogSubject("Update readme")
+	if badge != "" || subject != "Update readme" {
+		t.Fatalf("expected plain subject, got badge %q subject %q", badge, subject)
+	}
+}
+
+func TestNonConventionalCommits(t *testing.T) {
+	m := NewModel(&config.AppConfig{WorktreeDir: t.TempDir()}, "")
+	m.logEntriesAll = []commitLogEntry{
+		{sha: "a1", message: "fix: handle nil", isUnmerged: true},
+		{sha: "b2", message: "Quick hack", isUnmerged: true},
+		{sha: "c3", message: "wip(core): tidy", isUnmerged: true},
+		{sha: "d4", message: "Merged upstream work"},
+	}
+
+	got := m.nonConventionalCommits()
+	if len(got) != 2 || got[0] != "Quick hack" || got[1] != "wip(core): tidy" {
+		t.Fatalf("unexpected offending commits %v", got)
+	}
+
+	m.config.CommitTypes = []string{"fix", "wip"}
+	got = m.nonConventionalCommits()
+	if len(got) != 1 || got[0] != "Quick hack" {
+		t.Fatalf("expected custom types to be honoured, got %v", got)
+	}
+}
+
+func TestCommitLintLines(t *testing.T) {
+	m := NewModel(&config.AppConfig{WorktreeDir: t.TempDir()}, "")
+	m.currentDetailsPath = "/wt/feature"
+	m.logEntriesAll = []commitLogEntry{
+		{sha: "a1", message: "Quick hack", isUnmerged: true},
+	}
+
+	if lines := m.commitLintLines("/wt/feature"); lines != nil {
+		t.Fatalf("expected no warning with commit_lint disabled, got %v", lines)
+	}
+
+	m.config.CommitLint = true
+	if lines := m.commitLintLines("/wt/other"); lines != nil {
+		t.Fatalf("expected no warning for a worktree whose log is not loaded, got %v", lines)
+	}
+	lines := m.commitLintLines("/wt/feature")
+	joined := strings.Join(lines, "\n")
+	if !strings.Contains(joined, "1 commit(s) do not follow") || !strings.Contains(joined, "Quick hack") {
+		t.Fatalf("unexpected lint lines %q", joined)
+	}
+}
+
+func TestLogMarksCommitsByShape(t *testing.T) {
+	m := NewModel(&config.AppConfig{WorktreeDir: t.TempDir()}, "")
+	m.setLogEntries([]commitLogEntry{
+		{sha: "a1", message: "local", isUnpushed: true, isUnmerged: true},
+		{sha: "b2", message: "pushed", isUnmerged: true},
+		{sha: "c3", message: "merged"},
+	}, true)
+	rows := m.logTable.Rows()
+	for i, marker := range []string{"⬆ ", "◇ ", ""} {
+		msg := rows[i][len(rows[i])-1]
+		if marker != "" && !strings.Contains(msg, marker) || marker == "" && (strings.Contains(msg, "⬆") || strings.Contains(msg, "◇")) {
+			t.Fatalf("expected row %d marked with %q, got %q", i, marker, msg)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package app
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/chmouel/lazyworktree/internal/compose"
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+// composeStatusMsg carries the status of every Compose project, by name.
+type composeStatusMsg struct {
+	statuses map[string]string
+	err      error
+}
+
+// composeDoneMsg reports a Compose action on a worktree's project.
+type composeDoneMsg struct {
+	project string
+	action  string
+	output  string
+	err     error
+}
+
+// composeActions are offered for a worktree's project, in order.
+var composeActions = []struct {
+	id, label string
+	args      []string
+}{
+	{"up", "Start", []string{"up", "-d"}},
+	{"stop", "Stop", []string{"stop"}},
+	{"down", "Down (remove the containers)", []string{"down"}},
+	{"ps", "Show containers", []string{"ps"}},
+}
+
+// composeEnabled reports whether worktrees get Compose projects.
+func (m *Model) composeEnabled() bool {
+	return m.config != nil && m.config.DockerCompose
+}
+
+// composeProject names the Compose project of the worktree at path.
+func (m *Model) composeProject(branch, path string) string {
+	return compose.ProjectName(m.config.ComposeProjectTemplate, m.repoKey, filepath.Base(path), branch)
+}
+
+// loadComposeStatus asks Docker which projects run, in the background.
+func (m *Model) loadComposeStatus() tea.Cmd {
+	if !m.composeEnabled() || !compose.Available() {
+		return nil
+	}
+	return func() tea.Msg {
+		statuses, err := compose.Projects(m.ctx)
+		return composeStatusMsg{statuses: statuses, err: err}
+	}
+}
+
+// handleComposeStatus refreshes the Compose line of the info pane.
+func (m *Model) handleComposeStatus(msg composeStatusMsg) {
+	if msg.err != nil {
+		m.debugf("compose: %v", msg.err)
+		return
+	}
+	m.composeStatuses = msg.statuses
+	if wt := m.selectedWorktree(); wt != nil {
+		m.infoContent = m.buildInfoContent(wt)
+	}
+}
+
+// composeLines shows, in the info pane, whether the worktree's project
+// runs.
+func (m *Model) composeLines(wt *models.WorktreeInfo, labelStyle, valueStyle lipgloss.Style) []string {
+	if !m.composeEnabled() || !compose.HasFile(wt.Path) {
+		return nil
+	}
+	project := m.composeProject(wt.Branch, wt.Path)
+	mutedStyle := lipgloss.NewStyle().Foreground(m.theme.MutedFg)
+	status := mutedStyle.Render("not running")
+	if s := m.composeStatuses[project]; compose.Running(s) {
+		status = lipgloss.NewStyle().Foreground(m.theme.SuccessFg).Render(s)
+	} else if s != "" {
+		status = mutedStyle.Render(s)
+	}
+	return []string{fmt.Sprintf("%s %s %s", labelStyle.Render("Compose:"), status, valueStyle.Render("("+project+")"))}
+}
+
+// showCompose offers to start, stop or inspect the selected worktree's
+// Compose project.
+func (m *Model) showCompose() tea.Cmd {
+	wt := m.selectedWorktree()
+	if wt == nil {
+		return nil
+	}
+	switch {
+	case !m.composeEnabled():
+		m.showInfo("Docker Compose projects are off.\n\nSet docker_compose: true to give each worktree its own.", nil)
+		return nil
+	case !compose.HasFile(wt.Path):
+		m.showInfo(fmt.Sprintf("%s has no Compose file (%s).", filepath.Base(wt.Path), strings.Join(compose.Files, ", ")), nil)
+		return nil
+	case !compose.Available():
+		m.showInfo("The docker command was not found.", nil)
+		return nil
+	}
+
+	project := m.composeProject(wt.Branch, wt.Path)
+	items := make([]selectionItem, 0, len(composeActions))
+	for _, action := range composeActions {
+		items = append(items, selectionItem{
+			id:          action.id,
+			label:       action.label,
+			description: "docker compose -p " + project + " " + strings.Join(action.args, " "),
+		})
+	}
+	m.listScreen = NewListSelectionScreen(items, fmt.Sprintf("Compose project %s", project), "Filter actions...", "No matching actions.", m.windowWidth, m.windowHeight, "", m.theme)
+	m.listSubmit = func(item selectionItem) tea.Cmd {
+		m.clearListSelection()
+		for _, action := range composeActions {
+			if action.id == item.id {
+				return m.runCompose(wt, project, action.id, action.args)
+			}
+		}
+		return nil
+	}
+	m.currentScreen = screenListSelect
+	return textinput.Blink
+}
+
+// runCompose runs docker compose for the worktree's project, with the same
+// environment as the init commands.
+func (m *Model) runCompose(wt *models.WorktreeInfo, project, action string, args []string) tea.Cmd {
+	if action != "ps" && m.readOnlyDenied("Managing Compose projects") {
+		return nil
+	}
+	env := m.buildCommandEnv(wt.Branch, wt.Path)
+	m.statusContent = fmt.Sprintf("Running docker compose %s for %s...", strings.Join(args, " "), project)
+	return func() tea.Msg {
+		output, err := compose.Run(m.ctx, wt.Path, project, env, args...)
+		return composeDoneMsg{project: project, action: action, output: output, err: err}
+	}
+}
+
+// handleComposeDone reports the outcome and refreshes the project statuses.
+func (m *Model) handleComposeDone(msg composeDoneMsg) tea.Cmd {
+	switch {
+	case msg.err != nil:
+		m.showInfo(msg.err.Error(), nil)
+	case msg.action == "ps":
+		m.showInfo(fmt.Sprintf("%s\n\n%s", msg.project, strings.TrimRight(msg.output, "\n")), nil)
+	default:
+		m.statusContent = fmt.Sprintf("Compose project %s: %s done", msg.project, msg.action)
+	}
+	return m.loadComposeStatus()
+}
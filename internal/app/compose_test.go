@@ -0,0 +1,79 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/chmouel/lazyworktree/internal/config"
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+func TestComposeProjectPerWorktree(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as docker")
+	}
+	bin := t.TempDir()
+	script := "#!/bin/sh\necho \"docker $* in $PWD as $COMPOSE_PROJECT_NAME\"\n"
+	if err := os.WriteFile(filepath.Join(bin, "docker"), []byte(script), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	cfg := config.DefaultConfig()
+	cfg.WorktreeDir = t.TempDir()
+	cfg.DockerCompose = true
+	m := NewModel(cfg, "")
+	m.repoKey = "shop"
+	wt := &models.WorktreeInfo{Path: filepath.Join(t.TempDir(), "Feature.A"), Branch: "feature-a"}
+	if err := os.MkdirAll(wt.Path, 0o750); err != nil {
+		t.Fatal(err)
+	}
+	m.worktrees = []*models.WorktreeInfo{wt}
+	m.filteredWts = m.worktrees
+
+	if got := m.buildCommandEnv(wt.Branch, wt.Path)["COMPOSE_PROJECT_NAME"]; got != "shop-feature-a" {
+		t.Fatalf("expected the worktree's project in the environment, got %q", got)
+	}
+
+	_ = m.showCompose()
+	if m.currentScreen != screenInfo || !strings.Contains(m.infoScreen.message, "no Compose file") {
+		t.Fatalf("expected to be told the worktree has no Compose file, got %s", screenName(m.currentScreen))
+	}
+
+	if err := os.WriteFile(filepath.Join(wt.Path, "compose.yaml"), []byte("services: {}\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if info := m.buildInfoContent(wt); !strings.Contains(info, "Compose:") || !strings.Contains(info, "not running") || !strings.Contains(info, "(shop-feature-a)") {
+		t.Fatalf("expected the project in the info pane, got %q", info)
+	}
+	m.handleComposeStatus(composeStatusMsg{statuses: map[string]string{"shop-feature-a": "running(2)"}})
+	if !strings.Contains(m.infoContent, "running(2)") {
+		t.Fatalf("expected the running project in the info pane, got %q", m.infoContent)
+	}
+
+	m.currentScreen = screenNone
+	_ = m.showCompose()
+	if m.currentScreen != screenListSelect || len(m.listScreen.items) != len(composeActions) {
+		t.Fatalf("expected the Compose actions, got %s", screenName(m.currentScreen))
+	}
+	var ps selectionItem
+	for _, item := range m.listScreen.items {
+		if item.id == "ps" {
+			ps = item
+		}
+	}
+	done, ok := m.listSubmit(ps)().(composeDoneMsg)
+	if !ok || done.err != nil {
+		t.Fatalf("expected docker compose to run, got %+v", done)
+	}
+	if want := "docker compose -p shop-feature-a ps in " + wt.Path + " as shop-feature-a"; !strings.Contains(done.output, want) {
+		t.Fatalf("expected %q, got %q", want, done.output)
+	}
+	_ = m.handleComposeDone(done)
+	if m.currentScreen != screenInfo || !strings.Contains(m.infoScreen.message, "shop-feature-a ps") {
+		t.Fatalf("expected the containers to be shown, got %s", screenName(m.currentScreen))
+	}
+}
@@ -0,0 +1,255 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/chmouel/lazyworktree/internal/control"
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+// controlReplyTimeout bounds how long a control client waits for the model,
+// which may be busy rendering or blocked behind a running command.
+const controlReplyTimeout = 10 * time.Second
+
+// controlRequestMsg carries a control request into the update loop.
+type controlRequestMsg struct {
+	req   control.Request
+	reply chan<- control.Response
+}
+
+// ControlSocketPath returns the socket the instance listens on when
+// control_socket is enabled.
+func (m *Model) ControlSocketPath() string {
+	return filepath.Join(m.getRepoCacheDir(), models.ControlSocketFilename)
+}
+
+// ControlHandler passes control requests to the program through send and
+// waits for the model's answer, giving up once ctx is done.
+func ControlHandler(ctx context.Context, send func(tea.Msg)) control.Handler {
+	return func(req control.Request) control.Response {
+		reply := make(chan control.Response, 1)
+		send(controlRequestMsg{req: req, reply: reply})
+		select {
+		case resp := <-reply:
+			return resp
+		case <-ctx.Done():
+			return control.Response{Error: "lazyworktree is exiting"}
+		case <-time.After(controlReplyTimeout):
+			return control.Response{Error: "timed out waiting for lazyworktree"}
+		}
+	}
+}
+
+// handleControlRequest runs a control command and answers the client.
+func (m *Model) handleControlRequest(msg controlRequestMsg) tea.Cmd {
+	resp, cmd := m.runControlCommand(msg.req)
+	m.debugf("control: %s %v: ok=%t %s%s", msg.req.Command, msg.req.Args, resp.OK, resp.Message, resp.Error)
+	msg.reply <- resp
+	return cmd
+}
+
+func controlOK(format string, args ...any) control.Response {
+	return control.Response{OK: true, Message: fmt.Sprintf(format, args...)}
+}
+
+func controlError(format string, args ...any) control.Response {
+	return control.Response{Error: fmt.Sprintf(format, args...)}
+}
+
+// runControlCommand carries out a control request.
+func (m *Model) runControlCommand(req control.Request) (control.Response, tea.Cmd) {
+	switch req.Command {
+	case "select":
+		if len(req.Args) != 1 {
+			return controlError("select expects a branch, worktree name or path"), nil
+		}
+		return m.controlSelect(req.Args[0])
+	case "refresh":
+		if len(req.Args) != 0 {
+			return controlError("refresh takes no arguments"), nil
+		}
+		return controlOK("Refreshing worktrees."), m.requestRefresh()
+	case "create":
+		return m.controlCreate(req.Args)
+	case "list":
+		if len(req.Args) != 0 {
+			return controlError("list takes no arguments"), nil
+		}
+		return m.controlList(), nil
+	case "counterpart":
+		if len(req.Args) != 2 {
+			return controlError("counterpart expects a file and the branch, worktree name or path to find it in"), nil
+		}
+		return m.controlCounterpart(req.Args[0], req.Args[1]), nil
+	case "":
+		return controlError("missing command"), nil
+	default:
+		return controlError("unknown command %q: use select, refresh, create, list or counterpart", req.Command), nil
+	}
+}
+
+// controlData returns a successful response carrying data.
+func controlData(message string, data any) control.Response {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return controlError("failed to encode the answer: %v", err)
+	}
+	return control.Response{OK: true, Message: message, Data: raw}
+}
+
+// controlList describes every worktree, in the order shown, for editor
+// plugins to offer as a picker.
+func (m *Model) controlList() control.Response {
+	selected := ""
+	if m.selectedIndex >= 0 && m.selectedIndex < len(m.filteredWts) {
+		selected = m.filteredWts[m.selectedIndex].Path
+	}
+	list := make([]control.Worktree, 0, len(m.worktrees))
+	lines := make([]string, 0, len(m.worktrees))
+	for _, wt := range m.orderedWorktrees() {
+		list = append(list, control.NewWorktree(wt, wt.Path == selected))
+		lines = append(lines, wt.Branch+"\t"+wt.Path)
+	}
+	return controlData(strings.Join(lines, "\n"), list)
+}
+
+// orderedWorktrees returns the worktrees as the table shows them, followed
+// by any the filter hides.
+func (m *Model) orderedWorktrees() []*models.WorktreeInfo {
+	shown := make(map[string]bool, len(m.filteredWts))
+	ordered := make([]*models.WorktreeInfo, 0, len(m.worktrees))
+	for _, wt := range m.filteredWts {
+		shown[wt.Path] = true
+		ordered = append(ordered, wt)
+	}
+	for _, wt := range m.worktrees {
+		if !shown[wt.Path] {
+			ordered = append(ordered, wt)
+		}
+	}
+	return ordered
+}
+
+// worktreeContaining returns the worktree holding path, preferring the
+// innermost when worktrees are nested inside the main one.
+func (m *Model) worktreeContaining(path string) *models.WorktreeInfo {
+	var best *models.WorktreeInfo
+	for _, wt := range m.worktrees {
+		rel, err := filepath.Rel(wt.Path, path)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		if best == nil || len(wt.Path) > len(best.Path) {
+			best = wt
+		}
+	}
+	return best
+}
+
+// controlCounterpart maps file, inside one worktree, to the same path in
+// the worktree named by target, so an editor can open it there.
+func (m *Model) controlCounterpart(file, target string) control.Response {
+	if !filepath.IsAbs(file) {
+		return controlError("counterpart expects an absolute file path, got %q", file)
+	}
+	file = filepath.Clean(file)
+	from := m.worktreeContaining(file)
+	if from == nil {
+		return controlError("%s is not inside a worktree of this repository", file)
+	}
+	to := m.findWorktree(target)
+	if to == nil {
+		return controlError("no worktree matches %q", target)
+	}
+	rel, err := filepath.Rel(from.Path, file)
+	if err != nil {
+		return controlError("%v", err)
+	}
+	path := filepath.Join(to.Path, rel)
+	_, statErr := os.Stat(path)
+	return controlData(path, control.Counterpart{Path: path, Worktree: to.Path, Exists: statErr == nil})
+}
+
+// findWorktree returns the worktree whose branch, directory name or path is
+// target.
+func (m *Model) findWorktree(target string) *models.WorktreeInfo {
+	target = strings.TrimSpace(target)
+	cleaned := filepath.Clean(target)
+	for _, wt := range m.worktrees {
+		if wt.Branch == target || wt.Path == cleaned {
+			return wt
+		}
+	}
+	for _, wt := range m.worktrees {
+		if filepath.Base(wt.Path) == target {
+			return wt
+		}
+	}
+	return nil
+}
+
+// controlSelect moves the cursor to the worktree named by target.
+func (m *Model) controlSelect(target string) (control.Response, tea.Cmd) {
+	wt := m.findWorktree(target)
+	if wt == nil {
+		return controlError("no worktree matches %q", target), nil
+	}
+	idx := m.filteredIndexForPath(wt.Path)
+	if idx < 0 {
+		return controlError("%s is hidden by the current filter", wt.Path), nil
+	}
+	m.worktreeTable.SetCursor(idx)
+	m.selectedIndex = idx
+	return controlData(fmt.Sprintf("Selected %s.", wt.Path), control.NewWorktree(wt, true)), m.updateDetailsView()
+}
+
+// controlCreate creates a worktree for a new branch, taking the same checks
+// as the create dialogue. Arguments are the branch and an optional
+// "--base <ref>", which defaults to the main branch.
+func (m *Model) controlCreate(args []string) (control.Response, tea.Cmd) {
+	var branch, base string
+	for i := 0; i < len(args); i++ {
+		switch arg := args[i]; {
+		case arg == "--base" && i+1 < len(args):
+			i++
+			base = args[i]
+		case strings.HasPrefix(arg, "--base="):
+			base = strings.TrimPrefix(arg, "--base=")
+		case branch == "" && !strings.HasPrefix(arg, "-"):
+			branch = arg
+		default:
+			return controlError("create expects a branch and an optional --base <ref>"), nil
+		}
+	}
+	if branch == "" {
+		return controlError("create expects a branch and an optional --base <ref>"), nil
+	}
+	if m.config != nil && m.config.ReadOnly {
+		return controlError("creating worktrees is disabled in read-only mode"), nil
+	}
+	if m.currentScreen != screenNone {
+		return controlError("lazyworktree is busy; close the open dialogue and try again"), nil
+	}
+	newBranch := sanitizeBranchNameFromTitle(branch, "")
+	if base == "" {
+		base = m.git.GetMainBranch(m.ctx)
+	}
+	if !m.baseRefExists(base) {
+		return controlError("base ref %q does not exist", base), nil
+	}
+	targetPath, _, reason := m.newWorktreeTarget(newBranch, newBranch)
+	if reason != "" {
+		return controlError("%s", reason), nil
+	}
+	if errMsg := m.policyViolation(newBranch, base); errMsg != "" {
+		return controlError("%s", errMsg), nil
+	}
+	return controlOK("Creating worktree %s from %s.", newBranch, base), m.createWorktreeFromBase(newBranch, targetPath, base)
+}
@@ -0,0 +1,143 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/chmouel/lazyworktree/internal/config"
+	"github.com/chmouel/lazyworktree/internal/control"
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+func TestControlSelect(t *testing.T) {
+	m := NewModel(&config.AppConfig{WorktreeDir: t.TempDir()}, "")
+	m.worktrees = []*models.WorktreeInfo{
+		{Path: "/repo", Branch: "main", IsMain: true},
+		{Path: "/wt/repo/feature-x", Branch: "feature/x"},
+	}
+	m.updateTable()
+
+	for _, target := range []string{"feature/x", "feature-x", "/wt/repo/feature-x/"} {
+		m.selectedIndex = 0
+		m.worktreeTable.SetCursor(0)
+		resp, _ := m.runControlCommand(control.Request{Command: "select", Args: []string{target}})
+		if !resp.OK {
+			t.Fatalf("select %q: %s", target, resp.Error)
+		}
+		if m.selectedIndex != 1 {
+			t.Fatalf("select %q: expected index 1, got %d", target, m.selectedIndex)
+		}
+	}
+
+	resp, _ := m.runControlCommand(control.Request{Command: "select", Args: []string{"missing"}})
+	if resp.OK || !strings.Contains(resp.Error, "no worktree matches") {
+		t.Fatalf("expected a missing worktree error, got %+v", resp)
+	}
+	resp, _ = m.runControlCommand(control.Request{Command: "explode"})
+	if resp.OK || !strings.Contains(resp.Error, "unknown command") {
+		t.Fatalf("expected an unknown command error, got %+v", resp)
+	}
+}
+
+func TestControlCreateRefusals(t *testing.T) {
+	cfg := &config.AppConfig{WorktreeDir: t.TempDir(), ReadOnly: true}
+	m := NewModel(cfg, "")
+
+	resp, cmd := m.runControlCommand(control.Request{Command: "create", Args: []string{"feature", "--base", "main"}})
+	if resp.OK || !strings.Contains(resp.Error, "read-only") || cmd != nil {
+		t.Fatalf("expected read-only refusal, got %+v", resp)
+	}
+	if m.currentScreen != screenNone {
+		t.Fatalf("expected no dialogue, got %s", screenName(m.currentScreen))
+	}
+
+	resp, _ = m.runControlCommand(control.Request{Command: "create", Args: []string{"--base"}})
+	if resp.OK || !strings.Contains(resp.Error, "create expects a branch") {
+		t.Fatalf("expected a usage error, got %+v", resp)
+	}
+
+	cfg.ReadOnly = false
+	m.currentScreen = screenInput
+	resp, _ = m.runControlCommand(control.Request{Command: "create", Args: []string{"feature"}})
+	if resp.OK || !strings.Contains(resp.Error, "busy") {
+		t.Fatalf("expected a busy error, got %+v", resp)
+	}
+}
+
+func TestControlHandler(t *testing.T) {
+	m := NewModel(&config.AppConfig{WorktreeDir: t.TempDir()}, "")
+	send := func(msg tea.Msg) {
+		_, _ = m.Update(msg)
+	}
+	resp := ControlHandler(context.Background(), send)(control.Request{Command: "refresh"})
+	if !resp.OK {
+		t.Fatalf("expected refresh to be accepted, got %+v", resp)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	resp = ControlHandler(ctx, func(tea.Msg) {})(control.Request{Command: "refresh"})
+	if resp.OK || !strings.Contains(resp.Error, "exiting") {
+		t.Fatalf("expected an exiting error, got %+v", resp)
+	}
+}
+
+func TestControlListAndCounterpart(t *testing.T) {
+	root := t.TempDir()
+	mainPath := filepath.Join(root, "repo")
+	featurePath := filepath.Join(root, "worktrees", "feature-x")
+	if err := os.MkdirAll(filepath.Join(featurePath, "cmd"), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(featurePath, "cmd", "main.go"), nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	m := NewModel(&config.AppConfig{WorktreeDir: t.TempDir()}, "")
+	m.worktrees = []*models.WorktreeInfo{
+		{Path: mainPath, Branch: "main", IsMain: true},
+		{Path: featurePath, Branch: "feature/x", Dirty: true, PR: &models.PRInfo{Number: 3, State: "OPEN"}},
+	}
+	m.updateTable()
+
+	resp, _ := m.runControlCommand(control.Request{Command: "list"})
+	if !resp.OK {
+		t.Fatalf("list: %s", resp.Error)
+	}
+	var list []control.Worktree
+	if err := json.Unmarshal(resp.Data, &list); err != nil {
+		t.Fatalf("invalid list data %s: %v", resp.Data, err)
+	}
+	if len(list) != 2 || list[1].Name != "feature-x" || !list[1].Dirty || list[1].PR == nil || list[1].PR.Number != 3 {
+		t.Fatalf("unexpected list %+v", list)
+	}
+	if !list[0].Selected || list[1].Selected {
+		t.Fatalf("expected the main worktree to be selected, got %+v", list)
+	}
+
+	resp, _ = m.runControlCommand(control.Request{Command: "counterpart", Args: []string{filepath.Join(mainPath, "cmd", "main.go"), "feature/x"}})
+	if !resp.OK {
+		t.Fatalf("counterpart: %s", resp.Error)
+	}
+	var counterpart control.Counterpart
+	if err := json.Unmarshal(resp.Data, &counterpart); err != nil {
+		t.Fatal(err)
+	}
+	want := filepath.Join(featurePath, "cmd", "main.go")
+	if counterpart.Path != want || !counterpart.Exists || counterpart.Worktree != featurePath {
+		t.Fatalf("expected %s to exist, got %+v", want, counterpart)
+	}
+
+	resp, _ = m.runControlCommand(control.Request{Command: "counterpart", Args: []string{filepath.Join(root, "elsewhere.go"), "main"}})
+	if resp.OK || !strings.Contains(resp.Error, "not inside a worktree") {
+		t.Fatalf("expected an outside file to be refused, got %+v", resp)
+	}
+	resp, _ = m.runControlCommand(control.Request{Command: "counterpart", Args: []string{"main.go", "main"}})
+	if resp.OK || !strings.Contains(resp.Error, "absolute") {
+		t.Fatalf("expected a relative file to be refused, got %+v", resp)
+	}
+}
@@ -0,0 +1,189 @@
+package app
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/chmouel/lazyworktree/internal/utils"
+)
+
+// stashEntry is one of the repository's stashes.
+type stashEntry struct {
+	hash    string
+	ref     string // stash@{n}
+	date    string
+	subject string
+}
+
+// parseStashList reads git stash list --format=%H%x1f%gd%x1f%ct%x1f%gs.
+func parseStashList(raw string) []stashEntry {
+	var stashes []stashEntry
+	for line := range strings.SplitSeq(raw, "\n") {
+		parts := strings.SplitN(line, "\x1f", 4)
+		if len(parts) != 4 || parts[0] == "" {
+			continue
+		}
+		stashes = append(stashes, stashEntry{hash: parts[0], ref: parts[1], date: parts[2], subject: parts[3]})
+	}
+	return stashes
+}
+
+// stashBranchName suggests a branch name from a stash message such as
+// "On feature: half-done login", keeping the words after the colon.
+func stashBranchName(subject string) string {
+	if _, msg, ok := strings.Cut(subject, ": "); ok {
+		subject = msg
+	}
+	return sanitizeBranchNameFromTitle(subject, "stash")
+}
+
+// showCreateFromStash lists the repository's stashes; the one picked becomes
+// a new branch, started from the commit it was stashed on, in a worktree of
+// its own, and is dropped once applied there.
+func (m *Model) showCreateFromStash() tea.Cmd {
+	if m.readOnlyDenied("Creating worktrees") {
+		return nil
+	}
+	raw := m.git.RunGit(m.ctx, []string{"git", "stash", "list", "--format=%H%x1f%gd%x1f%ct%x1f%gs"}, "", []int{0}, true, false)
+	stashes := parseStashList(raw)
+	if len(stashes) == 0 {
+		m.clearListSelection()
+		m.showInfo("There are no stashes to create a worktree from.", nil)
+		return nil
+	}
+	items := make([]selectionItem, 0, len(stashes))
+	lookup := make(map[string]stashEntry, len(stashes))
+	for _, stash := range stashes {
+		lookup[stash.hash] = stash
+		items = append(items, selectionItem{
+			id:          stash.hash,
+			label:       fmt.Sprintf("%s: %s", stash.ref, stash.subject),
+			description: m.formatCommitDate(stash.date, commitListDateLayout),
+		})
+	}
+	m.listScreen = NewListSelectionScreen(items, "Select a stash", "Filter stashes...", "No stashes found.", m.windowWidth, m.windowHeight, "", m.theme)
+	m.listSubmit = func(item selectionItem) tea.Cmd {
+		stash, ok := lookup[item.id]
+		if !ok {
+			return nil
+		}
+		base := stash.hash + "^1"
+		return m.showNewBranchInput(base, stashBranchName(stash.subject), false, func(newBranch, targetPath string, _ bool) tea.Cmd {
+			return m.startCreateAndApply(newBranch, targetPath, base, stash.ref, func(path string) error {
+				if !m.git.RunCommandChecked(m.ctx, []string{"git", "stash", "apply", "--index", stash.hash}, path, "Failed to apply "+stash.ref) {
+					return fmt.Errorf("failed to apply %s to the new worktree", stash.ref)
+				}
+				m.dropStash(stash.hash)
+				return nil
+			})
+		})
+	}
+	m.currentScreen = screenListSelect
+	return textinput.Blink
+}
+
+// dropStash drops the stash with the given commit, looked up again as its
+// stash@{n} shifts when another is pushed meanwhile.
+func (m *Model) dropStash(hash string) {
+	raw := m.git.RunGit(m.ctx, []string{"git", "stash", "list", "--format=%H%x1f%gd%x1f%ct%x1f%gs"}, "", []int{0}, true, true)
+	for _, stash := range parseStashList(raw) {
+		if stash.hash == hash {
+			m.git.RunCommandChecked(m.ctx, []string{"git", "stash", "drop", stash.ref}, "", "Failed to drop "+stash.ref)
+			return
+		}
+	}
+}
+
+// isMailboxPatch reports whether a patch is a git format-patch mailbox,
+// applied with git am to keep its commits, rather than a plain diff.
+func isMailboxPatch(data []byte) bool {
+	return bytes.HasPrefix(data, []byte("From ")) && bytes.Contains(data, []byte("\nSubject: "))
+}
+
+// showCreateFromPatch asks for a patch file, then for the branch that takes
+// it, started from defaultBase. A format-patch mailbox keeps its commits;
+// a plain diff is left uncommitted in the new worktree.
+func (m *Model) showCreateFromPatch(defaultBase string) tea.Cmd {
+	if m.readOnlyDenied("Creating worktrees") {
+		return nil
+	}
+	m.clearListSelection()
+	m.inputScreen = NewInputScreen("Create from patch file", "~/fix-login.patch", "", m.theme)
+	m.inputSubmit = func(value string, _ bool) (tea.Cmd, bool) {
+		path := strings.TrimSpace(value)
+		if path == "" {
+			m.inputScreen.errorMsg = "Enter the path of a patch file."
+			return nil, false
+		}
+		if expanded, err := utils.ExpandPath(path); err == nil {
+			path = expanded
+		}
+		if abs, err := filepath.Abs(path); err == nil {
+			path = abs
+		}
+		// #nosec G304 -- a patch file the user chose
+		data, err := os.ReadFile(path)
+		if err != nil {
+			m.inputScreen.errorMsg = fmt.Sprintf("Cannot read %s: %v", path, err)
+			return nil, false
+		}
+		if len(bytes.TrimSpace(data)) == 0 {
+			m.inputScreen.errorMsg = fmt.Sprintf("%s is empty.", path)
+			return nil, false
+		}
+		args := []string{"git", "apply", path}
+		if isMailboxPatch(data) {
+			args = []string{"git", "am", "--3way", path}
+		}
+		name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		return m.showNewBranchInput(defaultBase, name, false, func(newBranch, targetPath string, _ bool) tea.Cmd {
+			return m.startCreateAndApply(newBranch, targetPath, defaultBase, filepath.Base(path), func(wtPath string) error {
+				if !m.git.RunCommandChecked(m.ctx, args, wtPath, "Failed to apply "+filepath.Base(path)) {
+					return fmt.Errorf("failed to apply %s to the new worktree", filepath.Base(path))
+				}
+				return nil
+			})
+		}), false
+	}
+	m.currentScreen = screenInput
+	return textinput.Blink
+}
+
+// startCreateAndApply creates newBranch from baseRef at targetPath, then
+// brings what into it with apply. When apply fails the worktree and its
+// branch are removed again; otherwise init commands run as for any new
+// worktree.
+func (m *Model) startCreateAndApply(newBranch, targetPath, baseRef, what string, apply func(path string) error) tea.Cmd {
+	if err := m.ensureWorktreeDir(m.getRepoWorktreeDir()); err != nil {
+		return func() tea.Msg { return errMsg{err: err} }
+	}
+	m.loading = true
+	m.statusContent = fmt.Sprintf("Creating worktree from %s...", what)
+	m.loadingScreen = NewLoadingScreen(m.statusContent, m.theme)
+	m.currentScreen = screenLoading
+	m.pendingSelectWorktreePath = targetPath
+
+	return func() tea.Msg {
+		if !m.git.RunCommandChecked(m.ctx, []string{"git", "worktree", "add", "-b", newBranch, targetPath, baseRef}, "", fmt.Sprintf("Failed to create worktree %s", newBranch)) {
+			return errMsg{err: fmt.Errorf("failed to create worktree %s", newBranch)}
+		}
+		if err := apply(targetPath); err != nil {
+			m.git.RunCommandChecked(m.ctx, []string{"git", "worktree", "remove", "--force", targetPath}, "", "Failed to remove worktree")
+			m.git.RunCommandChecked(m.ctx, []string{"git", "branch", "-D", newBranch}, "", "Failed to delete branch")
+			return errMsg{err: err}
+		}
+		after := func() tea.Msg {
+			worktrees, err := m.git.GetWorktrees(m.ctx)
+			return worktreesLoadedMsg{worktrees: worktrees, err: err}
+		}
+		if cmd := m.runInitCommands(targetPath, m.buildCommandEnv(newBranch, targetPath), after); cmd != nil {
+			return cmd()
+		}
+		return after()
+	}
+}
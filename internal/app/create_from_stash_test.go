@@ -0,0 +1,83 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/chmouel/lazyworktree/internal/config"
+)
+
+func TestParseStashList(t *testing.T) {
+	raw := "abc\x1fstash@{0}\x1f1700000000\x1fOn main: half-done login\n\ndef\x1fstash@{1}\x1f1690000000\x1fWIP on main: 1234 fix"
+	stashes := parseStashList(raw)
+	if len(stashes) != 2 || stashes[0].ref != "stash@{0}" || stashes[1].hash != "def" {
+		t.Fatalf("unexpected stashes: %+v", stashes)
+	}
+	if got := stashBranchName(stashes[0].subject); got != "half-done-login" {
+		t.Fatalf("expected the stash message as branch name, got %q", got)
+	}
+	if !isMailboxPatch([]byte("From 1234 Mon Sep 17 00:00:00 2001\nFrom: A <a@b>\nSubject: [PATCH] fix\n")) || isMailboxPatch([]byte("diff --git a/x b/x\n")) {
+		t.Fatal("expected only format-patch output to be taken as a mailbox")
+	}
+}
+
+func TestCreateFromStash(t *testing.T) {
+	repo := initTestRepo(t)
+	withCwd(t, repo.dir)
+	if err := os.WriteFile(filepath.Join(repo.dir, "file.txt"), []byte("stashed\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, repo.dir, "stash", "push", "-m", "half-done login")
+
+	m := NewModel(&config.AppConfig{WorktreeDir: t.TempDir()}, "")
+	m.showCreateFromStash()
+	if m.currentScreen != screenListSelect || len(m.listScreen.items) != 1 {
+		t.Fatalf("expected the stash listed, got screen %v", m.currentScreen)
+	}
+	m.listSubmit(m.listScreen.items[0])
+	if m.currentScreen != screenInput || m.inputScreen.input.Value() != "half-done-login" {
+		t.Fatalf("expected the branch name prompt filled from the stash, got %q", m.inputScreen.input.Value())
+	}
+	cmd, _ := m.inputSubmit("half-done-login", false)
+	cmd()
+
+	data, err := os.ReadFile(filepath.Join(m.pendingSelectWorktreePath, "file.txt"))
+	if err != nil || string(data) != "stashed\n" {
+		t.Fatalf("expected the stash applied in the new worktree, got %q: %v", data, err)
+	}
+	if stashes := runGit(t, repo.dir, "stash", "list"); stashes != "" {
+		t.Fatalf("expected the stash dropped, got %q", stashes)
+	}
+}
+
+func TestCreateFromPatch(t *testing.T) {
+	repo := initTestRepo(t)
+	withCwd(t, repo.dir)
+	patch := filepath.Join(t.TempDir(), "fix-login.patch")
+	diff := "diff --git a/file.txt b/file.txt\n--- a/file.txt\n+++ b/file.txt\n@@ -1 +1 @@\n-two\n+patched\n"
+	if err := os.WriteFile(patch, []byte(diff), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	m := NewModel(&config.AppConfig{WorktreeDir: t.TempDir()}, "")
+	m.showCreateFromPatch(repo.branch)
+	if _, closed := m.inputSubmit(filepath.Join(t.TempDir(), "missing.patch"), false); closed || !strings.Contains(m.inputScreen.errorMsg, "Cannot read") {
+		t.Fatalf("expected a missing patch refused, got %q", m.inputScreen.errorMsg)
+	}
+	m.inputSubmit(patch, false)
+	if m.inputScreen.input.Value() != "fix-login" {
+		t.Fatalf("expected the branch named after the patch, got %q", m.inputScreen.input.Value())
+	}
+	cmd, _ := m.inputSubmit("fix-login", false)
+	cmd()
+
+	data, err := os.ReadFile(filepath.Join(m.pendingSelectWorktreePath, "file.txt"))
+	if err != nil || string(data) != "patched\n" {
+		t.Fatalf("expected the patch applied in the new worktree, got %q: %v", data, err)
+	}
+	if branch := runGit(t, m.pendingSelectWorktreePath, "branch", "--show-current"); branch != "fix-login" {
+		t.Fatalf("expected the worktree on fix-login, got %q", branch)
+	}
+}
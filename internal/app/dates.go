@@ -0,0 +1,89 @@
+package app
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	// isoDateLayout shows dates for date_format: iso, and for the toggle
+	// when date_format is relative.
+	isoDateLayout = "2006-01-02 15:04"
+	// commitDateLayout is git's own date format, which the commit screen
+	// keeps while dates are relative.
+	commitDateLayout = "Mon Jan 2 15:04:05 2006 -0700"
+	// commitListDateLayout matches git's --date=short, which the commit
+	// list keeps while dates are relative.
+	commitListDateLayout = "2006-01-02"
+)
+
+// usesAbsoluteDates reports whether date_format asks for absolute dates.
+func usesAbsoluteDates(dateFormat string) bool {
+	return dateFormat != "" && dateFormat != "relative"
+}
+
+// dateLayout is the layout absolute dates are shown with.
+func (m *Model) dateLayout() string {
+	if format := m.config.DateFormat; usesAbsoluteDates(format) && format != "iso" {
+		return format
+	}
+	return isoDateLayout
+}
+
+// formatDate shows t relative to now, or with the date layout while
+// absolute dates are shown.
+func (m *Model) formatDate(t time.Time) string {
+	if !m.absoluteDates {
+		return formatRelativeTime(t)
+	}
+	return t.Format(m.dateLayout())
+}
+
+// formatCommitDate shows a commit's unix timestamp with the date layout
+// while absolute dates are shown, and with relativeLayout otherwise, as
+// screens about a single commit are after the exact date anyway. Values
+// that are not timestamps are returned as they are.
+func (m *Model) formatCommitDate(raw, relativeLayout string) string {
+	ts, err := strconv.ParseInt(strings.TrimSpace(raw), 10, 64)
+	if err != nil {
+		return raw
+	}
+	if m.absoluteDates {
+		return time.Unix(ts, 0).Format(m.dateLayout())
+	}
+	return time.Unix(ts, 0).Format(relativeLayout)
+}
+
+// lastActiveLabel is the Last Active column of a worktree: git's relative
+// date, or the commit date with the date layout while absolute dates are
+// shown.
+func (m *Model) lastActiveLabel(lastActive string, lastActiveTS int64) string {
+	if !m.absoluteDates || lastActiveTS <= 0 {
+		return lastActive
+	}
+	return time.Unix(lastActiveTS, 0).Format(m.dateLayout())
+}
+
+// toggleDateFormat switches between relative and absolute dates for the
+// session.
+func (m *Model) toggleDateFormat() tea.Cmd {
+	m.absoluteDates = !m.absoluteDates
+	// The log table's Date column comes and goes with absolute dates, so
+	// the rows are cleared before its columns change.
+	m.logTable.SetRows(nil)
+	m.updateLogColumns(m.logTable.Width())
+	m.applyLogFilter(false)
+	m.updateTable()
+	if wt := m.selectedWorktree(); wt != nil {
+		m.infoContent = m.buildInfoContent(wt)
+	}
+	if m.absoluteDates {
+		m.statusContent = "Dates: absolute (" + time.Now().Format(m.dateLayout()) + ")"
+	} else {
+		m.statusContent = "Dates: relative"
+	}
+	return nil
+}
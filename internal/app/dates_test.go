@@ -0,0 +1,53 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/chmouel/lazyworktree/internal/config"
+)
+
+func TestDateFormat(t *testing.T) {
+	const ts = 1767225600
+	when := time.Unix(ts, 0)
+
+	cfg := &config.AppConfig{WorktreeDir: t.TempDir()}
+	m := NewModel(cfg, "")
+	if m.absoluteDates {
+		t.Fatal("expected relative dates by default")
+	}
+	if got := m.lastActiveLabel("3 days ago", ts); got != "3 days ago" {
+		t.Fatalf("expected git's relative date, got %q", got)
+	}
+	if got := m.formatCommitDate("1767225600", commitListDateLayout); got != when.Format(commitListDateLayout) {
+		t.Fatalf("expected the commit list's date, got %q", got)
+	}
+	if got := m.formatCommitDate("not a timestamp", commitDateLayout); got != "not a timestamp" {
+		t.Fatalf("expected other values unchanged, got %q", got)
+	}
+
+	m.logEntriesAll = []commitLogEntry{{sha: "0123456789", date: when, authorInitials: "cb", message: "fix"}}
+	m.logEntries = m.logEntriesAll
+	m.applyLogFilter(true)
+	m.toggleDateFormat()
+	if !m.absoluteDates {
+		t.Fatal("expected the toggle to show absolute dates")
+	}
+	if got := m.lastActiveLabel("3 days ago", ts); got != when.Format(isoDateLayout) {
+		t.Fatalf("expected an ISO date, got %q", got)
+	}
+	columns, rows := m.logTable.Columns(), m.logTable.Rows()
+	if len(columns) != 4 || columns[1].Title != "Date" || len(rows) != 1 || rows[0][1] != when.Format(isoDateLayout) {
+		t.Fatalf("expected a Date column in the log pane, got %v %v", columns, rows)
+	}
+
+	m.toggleDateFormat()
+	if len(m.logTable.Columns()) != 3 || len(m.logTable.Rows()[0]) != 3 {
+		t.Fatal("expected the Date column to go with relative dates")
+	}
+
+	m = NewModel(&config.AppConfig{WorktreeDir: t.TempDir(), DateFormat: "02 Jan 2006"}, "")
+	if !m.absoluteDates || m.formatDate(when) != when.Format("02 Jan 2006") {
+		t.Fatalf("expected the configured layout, got %q", m.formatDate(when))
+	}
+}
@@ -0,0 +1,135 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/chmouel/lazyworktree/internal/demo"
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+// errDemoForge refuses the forge changes the demo cannot make.
+var errDemoForge = errors.New("the demo has no forge to change; its pull requests are made up")
+
+// demoGitService runs git for real against the demo repository while
+// answering forge calls from its fabricated pull requests and CI checks.
+type demoGitService struct {
+	GitService
+	repo *demo.Repo
+}
+
+func (d *demoGitService) DetectHost(context.Context) string { return "github" }
+
+func (d *demoGitService) IsGitHubOrGitLab(context.Context) bool { return true }
+
+func (d *demoGitService) ResolveRepoName(context.Context) string { return demo.RepoName }
+
+func (d *demoGitService) FetchPRMapForBranches(_ context.Context, branches []string) (map[string]*models.PRInfo, error) {
+	prMap := make(map[string]*models.PRInfo)
+	for _, branch := range branches {
+		if pr, ok := d.repo.PRs[branch]; ok {
+			prMap[branch] = pr
+		}
+	}
+	return prMap, nil
+}
+
+func (d *demoGitService) FetchAllOpenPRs(context.Context) ([]*models.PRInfo, error) {
+	var prs []*models.PRInfo
+	for _, pr := range d.repo.PRs {
+		if pr.State == "OPEN" {
+			prs = append(prs, pr)
+		}
+	}
+	slices.SortFunc(prs, func(a, b *models.PRInfo) int { return b.Number - a.Number })
+	return prs, nil
+}
+
+func (d *demoGitService) FetchMyOpenPRs(ctx context.Context) ([]*models.PRInfo, error) {
+	return d.FetchAllOpenPRs(ctx)
+}
+
+func (d *demoGitService) FetchPRForWorktreeWithError(context.Context, string) (*models.PRInfo, error) {
+	return nil, nil
+}
+
+func (d *demoGitService) FetchCIStatus(_ context.Context, _ int, branch string) ([]*models.CICheck, error) {
+	return d.repo.Checks[branch], nil
+}
+
+func (d *demoGitService) FetchCIAnnotations(context.Context, int) ([]*models.CIAnnotation, error) {
+	return nil, nil
+}
+
+func (d *demoGitService) FetchAllOpenIssues(context.Context) ([]*models.IssueInfo, error) {
+	return nil, nil
+}
+
+func (d *demoGitService) FetchDeployment(context.Context, string) (*models.DeploymentInfo, error) {
+	return nil, nil
+}
+
+func (d *demoGitService) FetchReviewerCandidates(context.Context) []string { return nil }
+
+func (d *demoGitService) SetPRDraft(context.Context, int, bool, string) error { return errDemoForge }
+
+func (d *demoGitService) RequestPRReviewers(context.Context, int, []string, string) error {
+	return errDemoForge
+}
+
+// CreateWorktreeFromPR checks the PR's branch out locally, as there is no
+// forge to fetch its head from.
+func (d *demoGitService) CreateWorktreeFromPR(ctx context.Context, _ int, remoteBranch, localBranch, targetPath string) bool {
+	return d.RunCommandChecked(ctx, []string{"git", "worktree", "add", "-b", localBranch, targetPath, remoteBranch}, "", "Failed to create the worktree of "+remoteBranch)
+}
+
+// demoStep is a step of the demo's guided tour, done once one of keys is
+// pressed.
+type demoStep struct {
+	keys []string
+	hint string
+}
+
+var demoTour = []demoStep{
+	{keys: []string{"j", "k", "down", "up"}, hint: "j/k move between worktrees; the info pane shows each one's state"},
+	{keys: []string{"p"}, hint: "p fetches PR and CI status, made up for the demo"},
+	{keys: []string{"2"}, hint: "2 focuses the Status pane; cart-rounding has uncommitted changes to diff with Enter"},
+	{keys: []string{"1"}, hint: "1 returns to the worktree list"},
+	{keys: []string{"c"}, hint: "c creates a worktree; everything stays in a temporary directory"},
+	{keys: []string{"ctrl+p", ":"}, hint: "ctrl+p opens the command palette with every action"},
+	{keys: []string{"?"}, hint: "? lists every key, and q leaves the demo, removing its files"},
+}
+
+// StartDemo drives the demo repository: forge calls are answered from
+// its fabricated pull requests and CI checks, and a guided tour suggests
+// keys to try in the footer.
+func (m *Model) StartDemo(repo *demo.Repo) {
+	m.git = &demoGitService{GitService: m.git, repo: repo}
+	m.demoStep = 0
+	m.demoRunning = true
+}
+
+// advanceDemoTour moves the tour on once the key its step suggests is
+// pressed over the worktree list.
+func (m *Model) advanceDemoTour(msg tea.KeyMsg) {
+	if !m.demoRunning || m.currentScreen != screenNone || m.showingFilter {
+		return
+	}
+	if slices.Contains(demoTour[m.demoStep].keys, msg.String()) {
+		m.demoStep++
+		m.demoRunning = m.demoStep < len(demoTour)
+	}
+}
+
+// demoTourView shows the tour's current step, or nothing once it is over.
+func (m *Model) demoTourView() string {
+	if !m.demoRunning {
+		return ""
+	}
+	style := lipgloss.NewStyle().Foreground(m.theme.Accent).Bold(true)
+	return style.Render(fmt.Sprintf("Demo %d/%d:", m.demoStep+1, len(demoTour))) + " " + demoTour[m.demoStep].hint
+}
@@ -0,0 +1,67 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/chmouel/lazyworktree/internal/config"
+	"github.com/chmouel/lazyworktree/internal/demo"
+)
+
+func TestDemoMode(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	repo, err := demo.Setup(context.Background())
+	if err != nil {
+		t.Fatalf("demo setup: %v", err)
+	}
+	t.Cleanup(func() { _ = repo.Close() })
+	withCwd(t, repo.Main)
+
+	m := NewModel(&config.AppConfig{WorktreeDir: repo.WorktreeDir}, "")
+	m.StartDemo(repo)
+	m.windowWidth = 200
+	m.windowHeight = 50
+	m.Update(m.startRefresh()())
+	if len(m.worktrees) != 6 {
+		t.Fatalf("expected the main worktree and five demo worktrees, got %d", len(m.worktrees))
+	}
+
+	m.Update(m.fetchPRData()())
+	want := map[string]int{"checkout-flow": 42, "cart-rounding": 43, "search": 44, "update-deps": 40}
+	for _, wt := range m.worktrees {
+		number, ok := want[wt.Branch]
+		if ok && (wt.PR == nil || wt.PR.Number != number) {
+			t.Fatalf("expected %s to have PR #%d, got %+v", wt.Branch, number, wt.PR)
+		}
+		if !ok && wt.PR != nil {
+			t.Fatalf("expected %s to have no PR, got %+v", wt.Branch, wt.PR)
+		}
+	}
+	if err := m.git.SetPRDraft(context.Background(), 44, false, ""); !errors.Is(err, errDemoForge) {
+		t.Fatalf("expected the demo to refuse forge changes, got %v", err)
+	}
+
+	if footer := m.renderFooter(m.computeLayout()); !strings.Contains(footer, "Demo 1/") {
+		t.Fatalf("expected the tour's first step in the footer, got %q", footer)
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+	if m.demoStep != 0 {
+		t.Fatalf("expected an unrelated key to leave the tour, got step %d", m.demoStep)
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'j'}})
+	if footer := m.renderFooter(m.computeLayout()); !strings.Contains(footer, "Demo 2/") {
+		t.Fatalf("expected the tour to move on, got %q", footer)
+	}
+
+	m.demoStep = len(demoTour) - 1
+	m.advanceDemoTour(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'?'}})
+	if footer := m.renderFooter(m.computeLayout()); strings.Contains(footer, "Demo") || !strings.Contains(footer, "Palette") {
+		t.Fatalf("expected the usual hints once the tour is over, got %q", footer)
+	}
+}
@@ -0,0 +1,171 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/chmouel/lazyworktree/internal/crash"
+	"github.com/chmouel/lazyworktree/internal/git"
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+// deploymentsEnabled reports whether deployment status should be looked up.
+func (m *Model) deploymentsEnabled() bool {
+	return m.config.ShowDeployments || m.config.DeploymentScript != ""
+}
+
+// showDeploymentColumn reports whether the worktree table has a Deploy column.
+func (m *Model) showDeploymentColumn() bool {
+	return m.prDataLoaded && m.deploymentsEnabled()
+}
+
+// fetchDeployments looks up the deployment of every worktree with a PR, using
+// deployment_script when configured and GitHub Deployments otherwise.
+func (m *Model) fetchDeployments() tea.Cmd {
+	if !m.deploymentsEnabled() {
+		return nil
+	}
+	worktrees := make([]*models.WorktreeInfo, 0, len(m.worktrees))
+	for _, wt := range m.worktrees {
+		if wt.PR != nil && !wt.IsMain {
+			worktrees = append(worktrees, wt)
+		}
+	}
+	if len(worktrees) == 0 {
+		return nil
+	}
+
+	script := m.config.DeploymentScript
+	return func() tea.Msg {
+		results := make(map[string]*models.DeploymentInfo, len(worktrees))
+		var mu sync.Mutex
+		var wg sync.WaitGroup
+		for _, wt := range worktrees {
+			wg.Add(1)
+			go func(wt *models.WorktreeInfo) {
+				defer wg.Done()
+				defer crash.Recover()
+
+				var info *models.DeploymentInfo
+				var err error
+				if script != "" {
+					env := m.buildCommandEnv(wt.Branch, wt.Path)
+					env["LAZYWORKTREE_PR_NUMBER"] = strconv.Itoa(wt.PR.Number)
+					release := m.git.Acquire(m.ctx, git.PoolCommands)
+					info, err = runDeploymentScript(m.ctx, script, wt.Path, env)
+					release()
+				} else {
+					// Deployments are made for the PR's head, which a
+					// worktree checked out under another name does not share.
+					branch := wt.Branch
+					if wt.PR.Branch != "" {
+						branch = wt.PR.Branch
+					}
+					info, err = m.git.FetchDeployment(m.ctx, branch)
+				}
+				if err != nil {
+					m.debugf("deployment lookup for %s failed: %v", wt.Branch, err)
+					return
+				}
+				if info != nil {
+					mu.Lock()
+					results[wt.Path] = info
+					mu.Unlock()
+				}
+			}(wt)
+		}
+		wg.Wait()
+		return deploymentsLoadedMsg{deployments: results}
+	}
+}
+
+// runDeploymentScript runs deployment_script in a worktree. The script prints
+// either a JSON object with environment, state and url keys, or a bare URL.
+// Empty output means the worktree is not deployed.
+func runDeploymentScript(ctx context.Context, script, dir string, env map[string]string) (*models.DeploymentInfo, error) {
+	stdout, err := runWorktreeScript(ctx, script, dir, env)
+	if err != nil {
+		return nil, fmt.Errorf("deployment script failed: %w", err)
+	}
+
+	output := strings.TrimSpace(stdout)
+	if output == "" {
+		return nil, nil
+	}
+	if strings.HasPrefix(output, "{") {
+		var parsed struct {
+			Environment string `json:"environment"`
+			State       string `json:"state"`
+			URL         string `json:"url"`
+		}
+		if err := json.Unmarshal([]byte(output), &parsed); err != nil {
+			return nil, fmt.Errorf("failed to parse deployment script output: %w", err)
+		}
+		info := &models.DeploymentInfo{
+			Environment: parsed.Environment,
+			State:       strings.ToLower(parsed.State),
+			URL:         parsed.URL,
+		}
+		if info.State == "" {
+			info.State = "success"
+		}
+		return info, nil
+	}
+
+	if idx := strings.IndexAny(output, "\n\r"); idx >= 0 {
+		output = output[:idx]
+	}
+	return &models.DeploymentInfo{Environment: "preview", State: "success", URL: strings.TrimSpace(output)}, nil
+}
+
+// deploymentSymbol returns a compact marker for a deployment state.
+func deploymentSymbol(state string) string {
+	switch state {
+	case "success":
+		return "✓"
+	case "pending", "queued", "in_progress":
+		return "⧗"
+	case "failure", "error":
+		return "✗"
+	case "inactive":
+		return "○"
+	default:
+		return "?"
+	}
+}
+
+// deploymentCell renders the Deploy column for a worktree.
+func (m *Model) deploymentCell(wt *models.WorktreeInfo) string {
+	info := m.deployments[wt.Path]
+	if info == nil {
+		return "-"
+	}
+	env := info.Environment
+	if env == "" {
+		env = "deployed"
+	}
+	return fmt.Sprintf("%s %s", deploymentSymbol(info.State), env)
+}
+
+// openDeployment opens the selected worktree's deployment URL in the browser.
+func (m *Model) openDeployment() tea.Cmd {
+	wt := m.selectedWorktree()
+	if wt == nil {
+		return nil
+	}
+	info := m.deployments[wt.Path]
+	if info == nil || info.URL == "" {
+		if !m.deploymentsEnabled() {
+			m.showInfo("Deployment status is disabled.\n\nSet show_deployments or deployment_script to enable it.", nil)
+		} else {
+			m.showInfo("No deployment URL for this worktree.", nil)
+		}
+		return nil
+	}
+	return m.openURLInBrowser(info.URL)
+}
@@ -0,0 +1,103 @@
+package app
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/chmouel/lazyworktree/internal/config"
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+func TestRunDeploymentScriptJSON(t *testing.T) {
+	script := `echo "{\"environment\":\"pr-$LAZYWORKTREE_PR_NUMBER\",\"state\":\"SUCCESS\",\"url\":\"https://pr.example.com\"}"`
+	info, err := runDeploymentScript(context.Background(), script, t.TempDir(), map[string]string{"LAZYWORKTREE_PR_NUMBER": "12"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if info == nil || info.Environment != "pr-12" || info.State != "success" || info.URL != "https://pr.example.com" {
+		t.Fatalf("unexpected deployment %+v", info)
+	}
+}
+
+func TestRunDeploymentScriptURLAndEmpty(t *testing.T) {
+	info, err := runDeploymentScript(context.Background(), "echo https://preview.example.com", t.TempDir(), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if info == nil || info.URL != "https://preview.example.com" || info.State != "success" {
+		t.Fatalf("unexpected deployment %+v", info)
+	}
+
+	info, err = runDeploymentScript(context.Background(), "true", t.TempDir(), nil)
+	if err != nil || info != nil {
+		t.Fatalf("expected no deployment, got %+v (err %v)", info, err)
+	}
+}
+
+func TestDeploymentColumn(t *testing.T) {
+	cfg := &config.AppConfig{
+		WorktreeDir:     t.TempDir(),
+		ShowDeployments: true,
+	}
+	m := NewModel(cfg, "")
+	m.worktreeTable.SetWidth(120)
+	m.prDataLoaded = true
+	m.worktrees = []*models.WorktreeInfo{
+		{Path: "/wt/feature", Branch: "feature", PR: &models.PRInfo{Number: 4, State: "OPEN"}},
+		{Path: "/wt/other", Branch: "other"},
+	}
+	m.filteredWts = m.worktrees
+	m.updateTableColumns(m.worktreeTable.Width())
+	m.updateTable()
+
+	m.Update(deploymentsLoadedMsg{deployments: map[string]*models.DeploymentInfo{
+		"/wt/feature": {Environment: "preview", State: "success", URL: "https://preview.example.com"},
+	}})
+
+	columns := m.worktreeTable.Columns()
+	if columns[len(columns)-1].Title != "Deploy" {
+		t.Fatalf("expected Deploy column, got %+v", columns)
+	}
+	rows := m.worktreeTable.Rows()
+	if got := rows[0][len(rows[0])-1]; !strings.Contains(got, "preview") {
+		t.Fatalf("expected deployment cell, got %q", got)
+	}
+	if got := rows[1][len(rows[1])-1]; got != "-" {
+		t.Fatalf("expected empty deployment cell, got %q", got)
+	}
+}
+
+func TestFetchDeploymentsUsesPRHeadBranch(t *testing.T) {
+	fake := newFakeGitService()
+	cfg := &config.AppConfig{WorktreeDir: t.TempDir(), ShowDeployments: true}
+	m := NewModelWithGit(cfg, "", fake)
+	m.worktrees = []*models.WorktreeInfo{
+		{Path: "/wt/review", Branch: "review-42", PR: &models.PRInfo{Number: 42, Branch: "contributor-fix"}},
+		{Path: "/wt/feature", Branch: "feature", PR: &models.PRInfo{Number: 4}},
+	}
+
+	m.fetchDeployments()()
+	if !fake.ran("FetchDeployment contributor-fix") || fake.ran("FetchDeployment review-42") {
+		t.Fatal("expected the lookup to use the PR head branch")
+	}
+	if !fake.ran("FetchDeployment feature") {
+		t.Fatal("expected the lookup to fall back to the local branch")
+	}
+}
+
+func TestOpenDeploymentWithoutURL(t *testing.T) {
+	cfg := &config.AppConfig{
+		WorktreeDir: t.TempDir(),
+	}
+	m := NewModel(cfg, "")
+	m.worktrees = []*models.WorktreeInfo{{Path: "/wt/feature", Branch: "feature"}}
+	m.filteredWts = m.worktrees
+
+	if cmd := m.openDeployment(); cmd != nil {
+		t.Fatal("expected no command without a deployment")
+	}
+	if m.currentScreen != screenInfo {
+		t.Fatalf("expected info screen, got %v", m.currentScreen)
+	}
+}
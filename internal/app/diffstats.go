@@ -0,0 +1,162 @@
+package app
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+var (
+	shortstatInsertions = regexp.MustCompile(`(\d+) insertions?\(\+\)`)
+	shortstatDeletions  = regexp.MustCompile(`(\d+) deletions?\(-\)`)
+)
+
+// diffStat counts the lines a diff adds and removes.
+type diffStat struct {
+	added   int
+	deleted int
+}
+
+func (s diffStat) empty() bool { return s.added == 0 && s.deleted == 0 }
+
+// worktreeDiffStat is the size of a worktree's changes: its branch against
+// the main branch, and its uncommitted changes against HEAD. The branch
+// side is kept until the worktree's HEAD or the main branch moves.
+type worktreeDiffStat struct {
+	head        string
+	base        string
+	branch      diffStat
+	uncommitted diffStat
+}
+
+// diffStatsMsg carries the diff statistics of every worktree, by path.
+type diffStatsMsg struct {
+	stats map[string]worktreeDiffStat
+}
+
+// parseShortstat reads the output of git diff --shortstat.
+func parseShortstat(out string) diffStat {
+	var s diffStat
+	if match := shortstatInsertions.FindStringSubmatch(out); match != nil {
+		s.added, _ = strconv.Atoi(match[1])
+	}
+	if match := shortstatDeletions.FindStringSubmatch(out); match != nil {
+		s.deleted, _ = strconv.Atoi(match[1])
+	}
+	return s
+}
+
+// showDiffStatsColumn reports whether the ±Lines column is shown.
+func (m *Model) showDiffStatsColumn() bool {
+	return m.config.ShowDiffStats
+}
+
+// loadDiffStats measures the worktrees' changes in the background. Only
+// dirty worktrees and those whose HEAD or main branch moved run git diff;
+// the rest keep what was measured before.
+func (m *Model) loadDiffStats() tea.Cmd {
+	if !m.showDiffStatsColumn() {
+		return nil
+	}
+	worktrees := make([]models.WorktreeInfo, 0, len(m.worktrees))
+	for _, wt := range m.worktrees {
+		if !wt.Bare && !wt.Prunable {
+			worktrees = append(worktrees, *wt)
+		}
+	}
+	cached := make(map[string]worktreeDiffStat, len(m.diffStats))
+	for path, stat := range m.diffStats {
+		cached[path] = stat
+	}
+	return func() tea.Msg {
+		mainBranch := m.git.GetMainBranch(m.ctx)
+		base := m.git.RunGit(m.ctx, []string{"git", "rev-parse", "--verify", "--quiet", mainBranch}, "", []int{0, 1}, true, true)
+		stats := make(map[string]worktreeDiffStat, len(worktrees))
+		for _, wt := range worktrees {
+			stat, ok := cached[wt.Path]
+			if !ok || stat.head != wt.Head || stat.base != base {
+				stat = worktreeDiffStat{head: wt.Head, base: base}
+				if !wt.IsMain && base != "" {
+					out := m.git.RunGit(m.ctx, []string{"git", "diff", "--shortstat", mainBranch + "...HEAD"}, wt.Path, []int{0}, true, true)
+					stat.branch = parseShortstat(out)
+				}
+			}
+			stat.uncommitted = diffStat{}
+			if wt.Dirty {
+				out := m.git.RunGit(m.ctx, []string{"git", "diff", "--shortstat", "HEAD"}, wt.Path, []int{0}, true, true)
+				stat.uncommitted = parseShortstat(out)
+			}
+			stats[wt.Path] = stat
+		}
+		return diffStatsMsg{stats: stats}
+	}
+}
+
+// handleDiffStats refreshes the ±Lines column.
+func (m *Model) handleDiffStats(msg diffStatsMsg) tea.Cmd {
+	m.diffStats = msg.stats
+	m.updateTable()
+	return m.updateDetailsView()
+}
+
+// compactCount shortens large line counts, such as 12k.
+func compactCount(n int) string {
+	switch {
+	case n < 1000:
+		return strconv.Itoa(n)
+	case n < 10000:
+		return fmt.Sprintf("%.1fk", float64(n)/1000)
+	default:
+		return fmt.Sprintf("%dk", n/1000)
+	}
+}
+
+func formatDiffStat(s diffStat) string {
+	return fmt.Sprintf("+%s -%s", compactCount(s.added), compactCount(s.deleted))
+}
+
+// diffStatsCell renders the ±Lines column: the branch against the main
+// branch, then uncommitted changes in parentheses.
+func (m *Model) diffStatsCell(wt *models.WorktreeInfo) string {
+	stat, ok := m.diffStats[wt.Path]
+	if !ok {
+		return "-"
+	}
+	cell := "-"
+	if !stat.branch.empty() {
+		cell = formatDiffStat(stat.branch)
+	}
+	if !stat.uncommitted.empty() {
+		cell += " (" + formatDiffStat(stat.uncommitted) + ")"
+	}
+	return cell
+}
+
+// diffStatsLines shows the worktree's diff statistics in the info pane.
+func (m *Model) diffStatsLines(wt *models.WorktreeInfo, labelStyle, valueStyle lipgloss.Style) []string {
+	stat, ok := m.diffStats[wt.Path]
+	if !ok || (stat.branch.empty() && stat.uncommitted.empty()) {
+		return nil
+	}
+	addedStyle := lipgloss.NewStyle().Foreground(m.theme.SuccessFg)
+	deletedStyle := lipgloss.NewStyle().Foreground(m.theme.ErrorFg)
+	render := func(s diffStat, what string) string {
+		return fmt.Sprintf("%s %s %s", addedStyle.Render(fmt.Sprintf("+%d", s.added)), deletedStyle.Render(fmt.Sprintf("-%d", s.deleted)), valueStyle.Render(what))
+	}
+	var parts []string
+	if !stat.branch.empty() {
+		parts = append(parts, render(stat.branch, "vs "+m.git.GetMainBranch(m.ctx)))
+	}
+	if !stat.uncommitted.empty() {
+		parts = append(parts, render(stat.uncommitted, "uncommitted"))
+	}
+	line := labelStyle.Render("Lines:") + " " + parts[0]
+	if len(parts) > 1 {
+		line += valueStyle.Render(", ") + parts[1]
+	}
+	return []string{line}
+}
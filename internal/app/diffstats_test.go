@@ -0,0 +1,86 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/chmouel/lazyworktree/internal/config"
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+func TestParseShortstat(t *testing.T) {
+	tests := []struct {
+		out  string
+		want diffStat
+	}{
+		{"", diffStat{}},
+		{" 3 files changed, 120 insertions(+), 45 deletions(-)", diffStat{120, 45}},
+		{" 1 file changed, 1 insertion(+)", diffStat{1, 0}},
+		{" 1 file changed, 1 deletion(-)", diffStat{0, 1}},
+	}
+	for _, tt := range tests {
+		if got := parseShortstat(tt.out); got != tt.want {
+			t.Errorf("parseShortstat(%q) = %+v, want %+v", tt.out, got, tt.want)
+		}
+	}
+	for n, want := range map[int]string{999: "999", 1234: "1.2k", 45678: "45k"} {
+		if got := compactCount(n); got != want {
+			t.Errorf("compactCount(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
+
+func countCalls(f *fakeGitService, line string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	n := 0
+	for _, call := range f.calls {
+		if call == line {
+			n++
+		}
+	}
+	return n
+}
+
+func TestDiffStatsColumn(t *testing.T) {
+	const branchDiff = "git diff --shortstat main...HEAD"
+	fake := newFakeGitService()
+	fake.worktrees = []*models.WorktreeInfo{
+		{Path: "/repo", Branch: "main", IsMain: true, Head: "m1"},
+		{Path: "/wt/feature", Branch: "feature", Head: "f1", Dirty: true},
+	}
+	fake.on("git rev-parse --verify --quiet main", "m1").
+		on(branchDiff, " 4 files changed, 1500 insertions(+), 20 deletions(-)").
+		on("git diff --shortstat HEAD", " 1 file changed, 2 insertions(+)")
+	m := NewModelWithGit(&config.AppConfig{WorktreeDir: t.TempDir()}, "", fake)
+	m.Update(m.startRefresh()())
+	if m.loadDiffStats() != nil {
+		t.Fatal("expected no diff statistics without show_diff_stats")
+	}
+
+	m.config.ShowDiffStats = true
+	m.Update(m.loadDiffStats()())
+	feature := m.worktrees[1]
+	if got := m.diffStatsCell(feature); got != "+1.5k -20 (+2 -0)" {
+		t.Fatalf("expected branch and uncommitted lines, got %q", got)
+	}
+	if got := m.diffStatsCell(m.worktrees[0]); got != "-" {
+		t.Fatalf("expected nothing for the clean main worktree, got %q", got)
+	}
+	if info := m.buildInfoContent(feature); !strings.Contains(info, "Lines:") || !strings.Contains(info, "uncommitted") {
+		t.Fatalf("expected the info pane to show the lines, got %q", info)
+	}
+	if cols := m.worktreeTable.Columns(); cols[len(cols)-1].Title != "±Lines" {
+		t.Fatalf("expected the ±Lines column, got %+v", cols)
+	}
+
+	m.Update(m.loadDiffStats()())
+	if n := countCalls(fake, branchDiff); n != 1 {
+		t.Fatalf("expected the branch diff cached, ran %d times", n)
+	}
+	feature.Head = "f2"
+	m.Update(m.loadDiffStats()())
+	if n := countCalls(fake, branchDiff); n != 2 {
+		t.Fatalf("expected a new HEAD to be measured again, ran %d times", n)
+	}
+}
@@ -0,0 +1,108 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+// branchFetchedMsg reports the end of a single-branch fetch.
+type branchFetchedMsg struct {
+	path string
+	refs []string
+	ok   bool
+}
+
+// fetchRefs returns the remote-tracking refs the worktree's ahead/behind
+// depends on, without duplicates.
+func fetchRefs(wt *models.WorktreeInfo) []string {
+	var refs []string
+	for _, ref := range []string{wt.DivergenceRef, wt.UpstreamBranch} {
+		ref = strings.TrimSpace(ref)
+		if ref != "" && (len(refs) == 0 || refs[0] != ref) {
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
+
+// fetchSelectedBranch fetches only the refs the selected worktree compares
+// against, which is far quicker than fetching every remote.
+func (m *Model) fetchSelectedBranch() tea.Cmd {
+	wt := m.selectedWorktree()
+	if wt == nil {
+		return nil
+	}
+	refs := fetchRefs(wt)
+	if len(refs) == 0 {
+		m.showInfo("Cannot fetch this branch because no upstream is configured.", nil)
+		return nil
+	}
+	type target struct{ remote, branch string }
+	targets := make([]target, 0, len(refs))
+	for _, ref := range refs {
+		remote, branch, ok := parseUpstreamRef(ref)
+		if !ok {
+			m.showInfo(fmt.Sprintf("Cannot fetch %q because it is not in remote/branch format.", ref), nil)
+			return nil
+		}
+		targets = append(targets, target{remote: remote, branch: branch})
+	}
+
+	path := wt.Path
+	m.statusContent = fmt.Sprintf("Fetching %s...", strings.Join(refs, ", "))
+	return func() tea.Msg {
+		ok := true
+		for _, t := range targets {
+			ok = m.git.FetchBranch(m.ctx, t.remote, t.branch, path) && ok
+		}
+		return branchFetchedMsg{path: path, refs: refs, ok: ok}
+	}
+}
+
+// handleBranchFetched refreshes the fetched worktree so its divergence and
+// fetch time are current.
+func (m *Model) handleBranchFetched(msg branchFetchedMsg) tea.Cmd {
+	if msg.ok {
+		m.statusContent = fmt.Sprintf("Fetched %s", strings.Join(msg.refs, ", "))
+	} else {
+		m.statusContent = fmt.Sprintf("Fetching %s failed", strings.Join(msg.refs, ", "))
+	}
+	return m.requestWorktreeRefresh(msg.path)
+}
+
+// divergenceLine describes how far the worktree is from the ref it compares
+// against, and how old that ref is, since nothing is fetched to compute it.
+func (m *Model) divergenceLine(wt *models.WorktreeInfo, labelStyle, valueStyle lipgloss.Style) string {
+	if !wt.HasUpstream {
+		return ""
+	}
+	ref := wt.DivergenceRef
+	if ref == "" {
+		ref = wt.UpstreamBranch
+	}
+	var counts []string
+	if wt.Ahead > 0 {
+		counts = append(counts, lipgloss.NewStyle().Foreground(m.theme.Cyan).Render(fmt.Sprintf("↑%d", wt.Ahead)))
+	}
+	if wt.Behind > 0 {
+		counts = append(counts, lipgloss.NewStyle().Foreground(m.theme.ErrorFg).Render(fmt.Sprintf("↓%d", wt.Behind)))
+	}
+	if len(counts) == 0 {
+		counts = append(counts, valueStyle.Render("in sync"))
+	}
+	mutedStyle := lipgloss.NewStyle().Foreground(m.theme.MutedFg)
+	return fmt.Sprintf("%s %s %s %s", labelStyle.Render("Upstream:"), valueStyle.Render(ref), strings.Join(counts, " "), mutedStyle.Render("("+lastFetchLabel(wt.LastFetchTS)+")"))
+}
+
+// lastFetchLabel phrases how old the remote-tracking refs are.
+func lastFetchLabel(ts int64) string {
+	if ts == 0 {
+		return "never fetched"
+	}
+	return "as of last fetch " + formatRelativeTime(time.Unix(ts, 0))
+}
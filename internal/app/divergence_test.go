@@ -0,0 +1,105 @@
+package app
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/chmouel/lazyworktree/internal/config"
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+func TestFetchRefs(t *testing.T) {
+	tests := []struct {
+		name string
+		wt   *models.WorktreeInfo
+		want []string
+	}{
+		{name: "no upstream", wt: &models.WorktreeInfo{}},
+		{name: "upstream only", wt: &models.WorktreeInfo{UpstreamBranch: "origin/feature"}, want: []string{"origin/feature"}},
+		{name: "same ref once", wt: &models.WorktreeInfo{UpstreamBranch: "origin/main", DivergenceRef: "origin/main"}, want: []string{"origin/main"}},
+		{name: "divergence ref first", wt: &models.WorktreeInfo{UpstreamBranch: "origin/feature", DivergenceRef: "origin/main"}, want: []string{"origin/main", "origin/feature"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fetchRefs(tt.wt); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("fetchRefs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLastFetchLabel(t *testing.T) {
+	if got := lastFetchLabel(0); got != "never fetched" {
+		t.Fatalf("expected never fetched, got %q", got)
+	}
+	got := lastFetchLabel(time.Now().Add(-3 * time.Hour).Unix())
+	if got != "as of last fetch 3 hours ago" {
+		t.Fatalf("unexpected label %q", got)
+	}
+}
+
+func TestDivergenceLine(t *testing.T) {
+	m := NewModel(&config.AppConfig{WorktreeDir: t.TempDir()}, "")
+	style := lipgloss.NewStyle()
+
+	if line := m.divergenceLine(&models.WorktreeInfo{}, style, style); line != "" {
+		t.Fatalf("expected no line without an upstream, got %q", line)
+	}
+
+	wt := &models.WorktreeInfo{
+		HasUpstream:    true,
+		UpstreamBranch: "origin/feature",
+		DivergenceRef:  "origin/main",
+		Ahead:          2,
+		Behind:         1,
+		LastFetchTS:    time.Now().Add(-10 * time.Minute).Unix(),
+	}
+	line := m.divergenceLine(wt, style, style)
+	for _, want := range []string{"origin/main", "↑2", "↓1", "as of last fetch 10 minutes ago"} {
+		if !strings.Contains(line, want) {
+			t.Fatalf("expected %q in %q", want, line)
+		}
+	}
+
+	wt = &models.WorktreeInfo{HasUpstream: true, UpstreamBranch: "origin/feature"}
+	line = m.divergenceLine(wt, style, style)
+	for _, want := range []string{"origin/feature", "in sync", "never fetched"} {
+		if !strings.Contains(line, want) {
+			t.Fatalf("expected %q in %q", want, line)
+		}
+	}
+}
+
+func TestFetchSelectedBranchWithoutUpstream(t *testing.T) {
+	m := NewModel(&config.AppConfig{WorktreeDir: t.TempDir()}, "")
+	m.worktrees = []*models.WorktreeInfo{{Path: "/tmp/feature", Branch: "feature"}}
+	m.updateTable()
+
+	if cmd := m.fetchSelectedBranch(); cmd != nil {
+		t.Fatal("expected no command without an upstream")
+	}
+	if m.currentScreen != screenInfo || !strings.Contains(m.infoScreen.message, "no upstream") {
+		t.Fatalf("expected an info message about the missing upstream, got screen %v", m.currentScreen)
+	}
+}
+
+func TestFetchSelectedBranchStartsFetch(t *testing.T) {
+	m := NewModel(&config.AppConfig{WorktreeDir: t.TempDir()}, "")
+	m.worktrees = []*models.WorktreeInfo{{Path: "/tmp/feature", Branch: "feature", HasUpstream: true, UpstreamBranch: "origin/feature"}}
+	m.updateTable()
+
+	if cmd := m.fetchSelectedBranch(); cmd == nil {
+		t.Fatal("expected a fetch command")
+	}
+	if m.statusContent != "Fetching origin/feature..." {
+		t.Fatalf("unexpected status %q", m.statusContent)
+	}
+
+	m.handleBranchFetched(branchFetchedMsg{path: "/tmp/feature", refs: []string{"origin/feature"}, ok: false})
+	if m.statusContent != "Fetching origin/feature failed" {
+		t.Fatalf("unexpected status %q", m.statusContent)
+	}
+}
@@ -0,0 +1,149 @@
+package app
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/chmouel/lazyworktree/internal/envtools"
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+// envToolsMsg reports direnv and mise approving a worktree.
+type envToolsMsg struct {
+	path   string
+	states map[string]string // tool -> state
+	errs   []string
+}
+
+// envToolStatesMsg carries whether direnv and mise trust each worktree.
+type envToolStatesMsg struct {
+	states map[string]map[string]string // worktree path -> tool -> state
+}
+
+// envToolsEnabled reports whether worktrees get direnv and mise approval.
+func (m *Model) envToolsEnabled() bool {
+	return m.config != nil && m.config.AllowEnvTools
+}
+
+// allowEnvTools runs direnv allow and mise trust in the worktree at path,
+// for those of them it configures.
+func (m *Model) allowEnvTools(path string) tea.Cmd {
+	if !m.envToolsEnabled() || m.config.ReadOnly {
+		return nil
+	}
+	return func() tea.Msg {
+		msg := envToolsMsg{path: path, states: map[string]string{}}
+		for _, tool := range envtools.Detect(path) {
+			if err := envtools.Allow(m.ctx, path, tool); err != nil {
+				msg.errs = append(msg.errs, firstLine(err.Error()))
+			}
+			msg.states[tool.Name] = envtools.State(m.ctx, path, tool)
+		}
+		return msg
+	}
+}
+
+// handleEnvTools records the outcome and reports a failure in the status
+// line.
+func (m *Model) handleEnvTools(msg envToolsMsg) {
+	m.setEnvToolStates(msg.path, msg.states)
+	if len(msg.errs) > 0 {
+		m.statusContent = fmt.Sprintf("%s: %s", filepath.Base(msg.path), strings.Join(msg.errs, "; "))
+	}
+}
+
+// loadEnvToolStates asks direnv and mise whether they trust each worktree,
+// in the background.
+func (m *Model) loadEnvToolStates() tea.Cmd {
+	if !m.envToolsEnabled() {
+		return nil
+	}
+	paths := make([]string, 0, len(m.worktrees))
+	for _, wt := range m.worktrees {
+		paths = append(paths, wt.Path)
+	}
+	return func() tea.Msg {
+		msg := envToolStatesMsg{states: map[string]map[string]string{}}
+		for _, path := range paths {
+			tools := envtools.Detect(path)
+			if len(tools) == 0 {
+				continue
+			}
+			msg.states[path] = map[string]string{}
+			for _, tool := range tools {
+				msg.states[path][tool.Name] = envtools.State(m.ctx, path, tool)
+			}
+		}
+		return msg
+	}
+}
+
+// handleEnvToolStates replaces the known states.
+func (m *Model) handleEnvToolStates(msg envToolStatesMsg) {
+	m.envToolStates = msg.states
+	if wt := m.selectedWorktree(); wt != nil {
+		m.infoContent = m.buildInfoContent(wt)
+	}
+}
+
+// setEnvToolStates records the states of one worktree.
+func (m *Model) setEnvToolStates(path string, states map[string]string) {
+	if m.envToolStates == nil {
+		m.envToolStates = map[string]map[string]string{}
+	}
+	m.envToolStates[path] = states
+	if wt := m.selectedWorktree(); wt != nil && wt.Path == path {
+		m.infoContent = m.buildInfoContent(wt)
+	}
+}
+
+// envToolLines shows, in the info pane, whether direnv and mise load the
+// worktree's configuration.
+func (m *Model) envToolLines(wt *models.WorktreeInfo, labelStyle lipgloss.Style) []string {
+	states := m.envToolStates[wt.Path]
+	if !m.envToolsEnabled() || len(states) == 0 {
+		return nil
+	}
+	okStyle := lipgloss.NewStyle().Foreground(m.theme.SuccessFg)
+	warnStyle := lipgloss.NewStyle().Foreground(m.theme.WarnFg)
+	mutedStyle := lipgloss.NewStyle().Foreground(m.theme.MutedFg)
+	parts := make([]string, 0, len(states))
+	for _, tool := range envtools.Tools {
+		state, ok := states[tool.Name]
+		switch {
+		case !ok:
+			continue
+		case state == "":
+			parts = append(parts, mutedStyle.Render(tool.Name+" unknown"))
+		case envtools.Approved(state):
+			parts = append(parts, okStyle.Render(tool.Name+" "+state))
+		default:
+			parts = append(parts, warnStyle.Render(tool.Name+" "+state))
+		}
+	}
+	return []string{fmt.Sprintf("%s %s", labelStyle.Render("Env tools:"), strings.Join(parts, ", "))}
+}
+
+// showAllowEnvTools approves direnv and mise in the selected worktree.
+func (m *Model) showAllowEnvTools() tea.Cmd {
+	wt := m.selectedWorktree()
+	if wt == nil {
+		return nil
+	}
+	if !m.envToolsEnabled() {
+		m.showInfo("direnv and mise approval is off.\n\nSet allow_env_tools: true to approve them in new worktrees and from here.", nil)
+		return nil
+	}
+	if m.readOnlyDenied("Approving direnv and mise") {
+		return nil
+	}
+	if len(envtools.Detect(wt.Path)) == 0 {
+		m.showInfo(fmt.Sprintf("%s has no configuration for an installed direnv or mise.", filepath.Base(wt.Path)), nil)
+		return nil
+	}
+	m.statusContent = fmt.Sprintf("Approving direnv and mise in %s...", filepath.Base(wt.Path))
+	return m.allowEnvTools(wt.Path)
+}
@@ -0,0 +1,63 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/chmouel/lazyworktree/internal/config"
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+func TestAllowEnvToolsInWorktree(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as direnv")
+	}
+	bin := t.TempDir()
+	direnv := `#!/bin/sh
+case "$1" in
+allow) touch .direnv-allowed ;;
+status) if [ -e .direnv-allowed ]; then echo '{"state":{"foundRC":{"allowed":0}}}'; else echo '{"state":{"foundRC":{"allowed":1}}}'; fi ;;
+esac
+`
+	if err := os.WriteFile(filepath.Join(bin, "direnv"), []byte(direnv), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	cfg := &config.AppConfig{WorktreeDir: t.TempDir(), AllowEnvTools: true}
+	m := NewModel(cfg, "")
+	wt := &models.WorktreeInfo{Path: t.TempDir(), Branch: "feature"}
+	m.worktrees = []*models.WorktreeInfo{wt}
+	m.filteredWts = m.worktrees
+
+	_ = m.showAllowEnvTools()
+	if m.currentScreen != screenInfo {
+		t.Fatalf("expected to be told nothing needs approving, got %s", screenName(m.currentScreen))
+	}
+	m.currentScreen = screenNone
+	if err := os.WriteFile(filepath.Join(wt.Path, ".envrc"), []byte("export FOO=1\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	states, ok := m.loadEnvToolStates()().(envToolStatesMsg)
+	if !ok {
+		t.Fatal("expected the states of the worktrees")
+	}
+	m.handleEnvToolStates(states)
+	if lines := m.envToolLines(wt, lipgloss.NewStyle()); len(lines) != 1 || !strings.Contains(lines[0], "direnv blocked") {
+		t.Fatalf("expected direnv to be blocked, got %v", lines)
+	}
+
+	msg, ok := m.showAllowEnvTools()().(envToolsMsg)
+	if !ok || len(msg.errs) != 0 {
+		t.Fatalf("expected direnv to be allowed, got %+v", msg)
+	}
+	m.handleEnvTools(msg)
+	if !strings.Contains(m.infoContent, "Env tools:") || !strings.Contains(m.infoContent, "direnv allowed") {
+		t.Fatalf("expected the info pane to show direnv allowed, got %q", m.infoContent)
+	}
+}
@@ -0,0 +1,399 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/chmouel/lazyworktree/internal/theme"
+)
+
+const (
+	// maxErrorDetailLines caps the command output shown per error; copying
+	// the details gets all of it.
+	maxErrorDetailLines = 12
+	// notifyErrorBuffer is how many git notifications wait for the UI
+	// before further ones are dropped.
+	notifyErrorBuffer = 16
+)
+
+// appError is a failure shown on the error screen.
+type appError struct {
+	message string  // What failed
+	command string  // The failing command, when known
+	detail  string  // Its output, such as git's stderr
+	retry   tea.Cmd // Runs the failed action again, when it can be
+}
+
+// notifyErrorMsg carries a git.Service error notification to the UI.
+type notifyErrorMsg struct {
+	err        appError
+	background bool // From a background command rather than an action
+}
+
+// remediation suggests a fix for errors whose text contains any of its
+// patterns, matched in lower case.
+type remediation struct {
+	patterns []string
+	hint     string
+}
+
+var remediations = []remediation{
+	{[]string{"not found: gh", "\"gh\": executable file not found"}, "Install the GitHub CLI (https://cli.github.com), then run gh auth login."},
+	{[]string{"not found: glab", "\"glab\": executable file not found"}, "Install the GitLab CLI (https://gitlab.com/gitlab-org/cli), then run glab auth login."},
+	{[]string{"not found: lazygit", "\"lazygit\": executable file not found"}, "Install lazygit (https://github.com/jesseduffield/lazygit)."},
+	{[]string{"gh auth login", "glab auth login", "bad credentials", "http 401", "401 unauthorized"}, "Log in with gh auth login or glab auth login, or set github_token / gitlab_token."},
+	{[]string{"could not read username", "could not read password", "permission denied (publickey)", "passphrase", "terminal prompts disabled"}, "Load your SSH key into an agent (ssh-add) or set up a credential helper, then retry."},
+	{[]string{"couldn't find remote ref", "unknown revision", "not a valid object name", "invalid reference", "not a valid ref"}, "Run git fetch (R) so the branch or commit is known locally."},
+	{[]string{"non-fast-forward", "[rejected]", "fetch first"}, "The remote has commits this branch lacks: synchronise (S) before pushing."},
+	{[]string{"no upstream", "has no upstream branch"}, "Push with P, which offers to set the upstream."},
+	{[]string{"index.lock"}, "Another git process holds the index lock; once it has finished, remove .git/index.lock."},
+	{[]string{"is already checked out", "is already used by worktree"}, "The branch is checked out in another worktree: pick another branch or remove that worktree."},
+	{[]string{"already exists"}, "Something already exists at that path or name: choose another one or remove it first."},
+	{[]string{"contains modified or untracked files", "uncommitted changes", "would be overwritten"}, "Commit or stash the changes first."},
+	{[]string{"conflict"}, "Resolve the conflicts in the worktree, then continue or abort the operation."},
+	{[]string{"not a git repository"}, "Start lazyworktree from within a git repository."},
+	{[]string{"could not resolve host", "network is unreachable", "connection timed out", "connection refused", "connection reset"}, "Check your network connection or VPN, then retry."},
+	{[]string{"no space left on device"}, "Free some disk space, then retry."},
+}
+
+// remediationHints returns the fixes suggested for an error's text.
+func remediationHints(text string) []string {
+	text = strings.ToLower(text)
+	var hints []string
+	for _, r := range remediations {
+		for _, pattern := range r.patterns {
+			if strings.Contains(text, pattern) {
+				hints = append(hints, r.hint)
+				break
+			}
+		}
+	}
+	return hints
+}
+
+// errorFromErr splits an error into its first line and the output after it.
+func errorFromErr(err error, retry tea.Cmd) appError {
+	message, detail, _ := strings.Cut(strings.TrimSpace(err.Error()), "\n")
+	return appError{message: message, detail: strings.TrimSpace(detail), retry: retry}
+}
+
+// errorFromNotify parses a git.Service error notification, such as
+// "Command failed: git fetch origin: fatal: ...", into its parts.
+func errorFromNotify(message string) appError {
+	for _, prefix := range []string{"Command failed", "Command not found", "Unsupported command"} {
+		rest, ok := strings.CutPrefix(message, prefix+": ")
+		if !ok {
+			continue
+		}
+		command, detail, found := strings.Cut(rest, ": ")
+		if !found {
+			command, detail, _ = strings.Cut(rest, "\n")
+		}
+		return appError{message: prefix, command: strings.TrimSpace(command), detail: strings.TrimSpace(detail)}
+	}
+	return errorFromErr(fmt.Errorf("%s", message), nil)
+}
+
+// hints returns the fixes suggested for the error.
+func (e appError) hints() []string {
+	return remediationHints(e.message + ": " + e.command + "\n" + e.detail)
+}
+
+// details is the error as plain text, for the clipboard.
+func (e appError) details() string {
+	var b strings.Builder
+	b.WriteString(e.message + "\n")
+	if e.command != "" {
+		b.WriteString("\nCommand: " + e.command + "\n")
+	}
+	if e.detail != "" {
+		b.WriteString("\n" + e.detail + "\n")
+	}
+	if hints := e.hints(); len(hints) > 0 {
+		b.WriteString("\nSuggested fixes:\n")
+		for _, hint := range hints {
+			b.WriteString("- " + hint + "\n")
+		}
+	}
+	return b.String()
+}
+
+// ErrorScreen shows failed actions and commands one at a time, with their
+// output and suggested fixes.
+type ErrorScreen struct {
+	errors []appError
+	index  int
+	note   string // Muted line after an action, such as "Copied."
+	width  int
+	thm    *theme.Theme
+}
+
+// NewErrorScreen creates the error screen for errs.
+func NewErrorScreen(errs []appError, maxWidth int, thm *theme.Theme) *ErrorScreen {
+	s := &ErrorScreen{errors: errs, thm: thm}
+	s.SetSize(maxWidth)
+	return s
+}
+
+// SetSize fits the screen to the terminal width.
+func (s *ErrorScreen) SetSize(maxWidth int) {
+	s.width = 80
+	if maxWidth > 0 {
+		s.width = maxInt(40, minInt(100, maxWidth-4))
+	}
+}
+
+// Add queues err unless the same error is already waiting, returning its
+// index either way.
+func (s *ErrorScreen) Add(err appError) int {
+	for i, existing := range s.errors {
+		if existing.message == err.message && existing.command == err.command && existing.detail == err.detail {
+			return i
+		}
+	}
+	s.errors = append(s.errors, err)
+	return len(s.errors) - 1
+}
+
+// Current returns the error shown.
+func (s *ErrorScreen) Current() (appError, bool) {
+	if len(s.errors) == 0 {
+		return appError{}, false
+	}
+	return s.errors[s.index], true
+}
+
+// Dismiss drops the error shown and reports whether any are left.
+func (s *ErrorScreen) Dismiss() bool {
+	if len(s.errors) == 0 {
+		return false
+	}
+	s.errors = append(s.errors[:s.index], s.errors[s.index+1:]...)
+	s.index = minInt(s.index, maxInt(0, len(s.errors)-1))
+	s.note = ""
+	return len(s.errors) > 0
+}
+
+// Update moves between errors.
+func (s *ErrorScreen) Update(msg tea.Msg) (*ErrorScreen, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok || len(s.errors) == 0 {
+		return s, nil
+	}
+	switch keyMsg.String() {
+	case "n", "l", "right", keyTab:
+		s.index = (s.index + 1) % len(s.errors)
+		s.note = ""
+	case "p", "h", "left", keyShiftTab:
+		s.index = (s.index - 1 + len(s.errors)) % len(s.errors)
+		s.note = ""
+	}
+	return s, nil
+}
+
+// View renders the error shown.
+func (s *ErrorScreen) View() string {
+	err, ok := s.Current()
+	if !ok {
+		return ""
+	}
+	innerWidth := s.width - 4
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(s.thm.ErrorFg).
+		Width(s.width).
+		Padding(0)
+	titleStyle := lipgloss.NewStyle().
+		Foreground(s.thm.ErrorFg).
+		Bold(true).
+		Border(lipgloss.NormalBorder(), false, false, true, false).
+		BorderForeground(s.thm.BorderDim).
+		Width(s.width-2).
+		Padding(0, 1)
+	textStyle := lipgloss.NewStyle().Foreground(s.thm.TextFg).Width(innerWidth)
+	mutedStyle := lipgloss.NewStyle().Foreground(s.thm.MutedFg).Width(innerWidth)
+	headingStyle := lipgloss.NewStyle().Foreground(s.thm.Accent).Bold(true)
+
+	title := "Error"
+	if len(s.errors) > 1 {
+		title = fmt.Sprintf("Error %d/%d", s.index+1, len(s.errors))
+	}
+
+	lines := []string{textStyle.Bold(true).Render(err.message)}
+	if err.command != "" {
+		lines = append(lines, mutedStyle.Render("$ "+err.command))
+	}
+	if err.detail != "" {
+		detail := strings.Split(err.detail, "\n")
+		if over := len(detail) - maxErrorDetailLines; over > 0 {
+			detail = append(detail[:maxErrorDetailLines], fmt.Sprintf("… %d more lines (y copies them all)", over))
+		}
+		lines = append(lines, "", textStyle.Render(strings.Join(detail, "\n")))
+	}
+	if hints := err.hints(); len(hints) > 0 {
+		lines = append(lines, "", headingStyle.Render("Suggested fixes"))
+		for _, hint := range hints {
+			lines = append(lines, textStyle.Render("• "+hint))
+		}
+	}
+	if s.note != "" {
+		lines = append(lines, "", mutedStyle.Italic(true).Render(s.note))
+	}
+
+	body := lipgloss.NewStyle().
+		Padding(1, 1, 0, 1).
+		Width(s.width - 2).
+		Render(strings.Join(lines, "\n"))
+	hints := []string{}
+	if err.retry != nil {
+		hints = append(hints, "r: retry")
+	}
+	hints = append(hints, "y: copy details")
+	if len(s.errors) > 1 {
+		hints = append(hints, "n/p: next/previous", "enter: dismiss")
+	}
+	hints = append(hints, "esc: close")
+	footer := lipgloss.NewStyle().
+		Foreground(s.thm.MutedFg).
+		Width(s.width-2).
+		Padding(1, 1, 0, 1).
+		Render(strings.Join(hints, " • "))
+
+	return boxStyle.Render(lipgloss.JoinVertical(lipgloss.Left, titleStyle.Render(title), body, footer))
+}
+
+// showError adds err to the error screen and shows it.
+func (m *Model) showError(err appError) {
+	m.errorScreen.index = m.queueError(err)
+	m.errorScreen.note = ""
+	m.showErrors()
+}
+
+// queueError adds err to the error screen, for E to show, and returns
+// its index there.
+func (m *Model) queueError(err appError) int {
+	if m.errorScreen == nil {
+		m.errorScreen = NewErrorScreen(nil, m.windowWidth, m.theme)
+	}
+	return m.errorScreen.Add(err)
+}
+
+// showErrors opens the error screen, when there are errors.
+func (m *Model) showErrors() {
+	if m.errorScreen == nil {
+		m.statusContent = "No errors."
+		return
+	}
+	m.errorScreen.SetSize(m.windowWidth)
+	m.currentScreen = screenError
+}
+
+// showPendingErrors opens the error screen for actions that failed while
+// another screen was open, such as the loading one.
+func (m *Model) showPendingErrors() {
+	if m.errorsPending && m.currentScreen == screenNone {
+		m.errorsPending = false
+		m.showErrors()
+	}
+}
+
+// errorCountView shows, in the footer, how many errors wait for E.
+func (m *Model) errorCountView() string {
+	if m.errorScreen == nil || m.currentScreen == screenError {
+		return ""
+	}
+	label := "1 error"
+	if n := len(m.errorScreen.errors); n != 1 {
+		label = fmt.Sprintf("%d errors", n)
+	}
+	return lipgloss.NewStyle().Foreground(m.theme.ErrorFg).Render("⚠ " + label + " (E)")
+}
+
+// closeErrorScreen drops every error.
+func (m *Model) closeErrorScreen() {
+	m.errorScreen = nil
+	m.errorsPending = false
+	m.currentScreen = screenNone
+}
+
+// handleErrorKey retries, copies or dismisses the error shown.
+func (m *Model) handleErrorKey(msg tea.KeyMsg) tea.Cmd {
+	s := m.errorScreen
+	err, ok := s.Current()
+	keyStr := msg.String()
+	if !ok || keyStr == keyQ || isEscKey(keyStr) {
+		m.closeErrorScreen()
+		return nil
+	}
+	switch keyStr {
+	case keyEnter:
+		if !s.Dismiss() {
+			m.closeErrorScreen()
+		}
+		return nil
+	case "r":
+		if err.retry == nil {
+			return nil
+		}
+		if !s.Dismiss() {
+			m.closeErrorScreen()
+		}
+		return err.retry
+	case "y":
+		if copyErr := m.copyToClipboard(err.details()); copyErr != nil {
+			s.note = fmt.Sprintf("Failed to copy: %v", copyErr)
+		} else {
+			s.note = "Copied to clipboard."
+		}
+		return nil
+	}
+	m.errorScreen, _ = s.Update(msg)
+	return nil
+}
+
+// withRetry makes errors returned by cmd retryable from the error screen,
+// including those of the commands it batches.
+func withRetry(cmd tea.Cmd) tea.Cmd {
+	if cmd == nil {
+		return nil
+	}
+	var wrapped tea.Cmd
+	wrapped = func() tea.Msg {
+		switch msg := cmd().(type) {
+		case errMsg:
+			if msg.retry == nil {
+				msg.retry = wrapped
+			}
+			return msg
+		case tea.BatchMsg:
+			for i, c := range msg {
+				msg[i] = withRetry(c)
+			}
+			return msg
+		default:
+			return msg
+		}
+	}
+	return wrapped
+}
+
+// routeNotifyError passes git.Service error notifications on to the UI,
+// dropping them while it is behind.
+func routeNotifyError(errs chan<- notifyErrorMsg, message, severity string, background bool) {
+	if severity != "error" {
+		return
+	}
+	select {
+	case errs <- notifyErrorMsg{err: errorFromNotify(message), background: background}:
+	default:
+	}
+}
+
+// waitForNotifyError waits for the next git.Service error notification.
+func (m *Model) waitForNotifyError() tea.Cmd {
+	return func() tea.Msg {
+		return <-m.notifyErrors
+	}
+}
@@ -0,0 +1,121 @@
+package app
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/chmouel/lazyworktree/internal/config"
+)
+
+func TestErrorFromNotify(t *testing.T) {
+	err := errorFromNotify("Command failed: git fetch origin: fatal: Could not resolve host: github.com")
+	if err.message != "Command failed" || err.command != "git fetch origin" || err.detail != "fatal: Could not resolve host: github.com" {
+		t.Fatalf("unexpected parse: %+v", err)
+	}
+	hints := remediationHints(err.details())
+	if len(hints) != 1 || !strings.Contains(hints[0], "network") {
+		t.Fatalf("expected a network hint, got %v", hints)
+	}
+
+	err = errorFromNotify("Command not found: gh")
+	if err.command != "gh" || !strings.Contains(err.details(), "Install the GitHub CLI") {
+		t.Fatalf("expected an install hint for gh, got %q", err.details())
+	}
+
+	err = errorFromNotify("Failed to push: ! [rejected] main -> main (fetch first)")
+	if err.message != "Failed to push: ! [rejected] main -> main (fetch first)" || len(remediationHints(err.message)) != 1 {
+		t.Fatalf("expected other notifications as they are, got %+v", err)
+	}
+}
+
+func TestWithRetry(t *testing.T) {
+	runs := 0
+	failing := func() tea.Msg {
+		runs++
+		return errMsg{err: errors.New("boom")}
+	}
+	msg, ok := withRetry(failing)().(errMsg)
+	if !ok || msg.retry == nil {
+		t.Fatalf("expected a retryable error, got %#v", msg)
+	}
+	if _, ok := msg.retry().(errMsg); !ok || runs != 2 {
+		t.Fatalf("expected the retry to run the command again, ran %d times", runs)
+	}
+
+	batch, ok := withRetry(tea.Batch(failing, func() tea.Msg { return nil }))().(tea.BatchMsg)
+	if !ok {
+		t.Fatal("expected a batch")
+	}
+	if msg, ok := batch[0]().(errMsg); !ok || msg.retry == nil {
+		t.Fatalf("expected batched commands to be retryable, got %#v", msg)
+	}
+}
+
+func TestErrorScreen(t *testing.T) {
+	m := NewModel(&config.AppConfig{WorktreeDir: t.TempDir()}, "")
+	key := func(s string) tea.Cmd {
+		_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)})
+		return cmd
+	}
+
+	// Background failures wait in the footer for E.
+	m.Update(notifyErrorMsg{err: errorFromNotify("Command failed: git symbolic-ref HEAD (exit 1)"), background: true})
+	if m.currentScreen != screenNone || !strings.Contains(m.errorCountView(), "1 error") {
+		t.Fatalf("expected a quiet error count, got screen %v %q", m.currentScreen, m.errorCountView())
+	}
+
+	// Failed actions show once the screen they failed under has gone.
+	m.currentScreen = screenLoading
+	m.Update(notifyErrorMsg{err: errorFromNotify("Failed to push: rejected")})
+	if m.currentScreen != screenLoading {
+		t.Fatal("expected the loading screen to stay")
+	}
+	m.currentScreen = screenNone
+	m.Update(nil)
+	if m.currentScreen != screenError || !strings.Contains(m.errorScreen.View(), "Error 2/2") {
+		t.Fatalf("expected both errors on the error screen, got %v", m.currentScreen)
+	}
+
+	if current, _ := m.errorScreen.Current(); current.message != "Failed to push: rejected" {
+		t.Fatalf("expected the failed action first, got %q", current.message)
+	}
+	key("n")
+	if current, _ := m.errorScreen.Current(); current.message != "Command failed" {
+		t.Fatalf("expected n to show the next error, got %q", current.message)
+	}
+	if cmd := key("r"); cmd != nil || m.currentScreen != screenError {
+		t.Fatal("expected r to do nothing without a retry")
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if len(m.errorScreen.errors) != 1 {
+		t.Fatal("expected enter to dismiss the error shown")
+	}
+
+	retried := false
+	m.Update(errMsg{err: errors.New("create failed\nfatal: 'feature' is already checked out"), retry: func() tea.Msg {
+		retried = true
+		return nil
+	}})
+	view := m.errorScreen.View()
+	if !strings.Contains(view, "r: retry") || !strings.Contains(view, "another worktree") {
+		t.Fatalf("expected a retry and a suggested fix, got %q", view)
+	}
+	if cmd := key("r"); cmd == nil {
+		t.Fatal("expected r to retry")
+	} else {
+		cmd()
+	}
+	if !retried || m.currentScreen != screenError {
+		t.Fatal("expected the retry to run with the other error still shown")
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.currentScreen != screenNone || m.errorScreen != nil {
+		t.Fatal("expected esc to close the error screen")
+	}
+	key("E")
+	if m.currentScreen != screenNone || m.statusContent != "No errors." {
+		t.Fatal("expected E to report no errors")
+	}
+}
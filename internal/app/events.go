@@ -0,0 +1,92 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/chmouel/lazyworktree/internal/events"
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+// SetEvents makes the model report worktree lifecycle events to stream.
+func (m *Model) SetEvents(stream *events.Stream) {
+	m.events = stream
+}
+
+// emit writes e to the event stream, tagged with the repository.
+func (m *Model) emit(e events.Event) {
+	if m.events == nil {
+		return
+	}
+	if repoKey := strings.TrimSpace(m.repoKey); repoKey != "unknown" && !strings.HasPrefix(repoKey, "local-") {
+		e.Repo = repoKey
+	}
+	m.events.Emit(e)
+}
+
+// emitWorktreeChanges reports the worktrees added or removed between two
+// listings, whether by lazyworktree or behind its back.
+func (m *Model) emitWorktreeChanges(before, after []*models.WorktreeInfo) {
+	if m.events == nil {
+		return
+	}
+	known := make(map[string]bool, len(before))
+	for _, wt := range before {
+		known[wt.Path] = true
+	}
+	current := make(map[string]bool, len(after))
+	for _, wt := range after {
+		current[wt.Path] = true
+		if !known[wt.Path] {
+			m.emit(events.Event{Type: events.Created, Path: wt.Path, Branch: wt.Branch})
+		}
+	}
+	for _, wt := range before {
+		if !current[wt.Path] {
+			m.emit(events.Event{Type: events.Deleted, Path: wt.Path, Branch: wt.Branch})
+		}
+	}
+}
+
+// emitSelection reports wt when it differs from the last selection reported.
+func (m *Model) emitSelection(wt *models.WorktreeInfo) {
+	if m.events == nil || wt.Path == m.lastEventSelection {
+		return
+	}
+	m.lastEventSelection = wt.Path
+	m.emit(events.Event{Type: events.Selected, Path: wt.Path, Branch: wt.Branch})
+}
+
+// prEventStates captures each worktree's PR state, to compare after the PR
+// data is refreshed.
+func (m *Model) prEventStates() map[string]string {
+	if m.events == nil {
+		return nil
+	}
+	states := make(map[string]string, len(m.worktrees))
+	for _, wt := range m.worktrees {
+		states[wt.Path] = prEventKey(wt.PR)
+	}
+	return states
+}
+
+// emitPRChanges reports the worktrees whose PR state differs from before.
+func (m *Model) emitPRChanges(before map[string]string) {
+	if m.events == nil {
+		return
+	}
+	for _, wt := range m.worktrees {
+		if prEventKey(wt.PR) == before[wt.Path] {
+			continue
+		}
+		m.emit(events.Event{Type: events.PRStateChanged, Path: wt.Path, Branch: wt.Branch, PR: events.NewPR(wt.PR)})
+	}
+}
+
+// prEventKey summarises the parts of a PR a pr-state-changed event reports.
+func prEventKey(pr *models.PRInfo) string {
+	if pr == nil {
+		return ""
+	}
+	return fmt.Sprintf("%d|%s|%t|%s|%s", pr.Number, pr.State, pr.IsDraft, pr.CIStatus, pr.ReviewDecision)
+}
@@ -0,0 +1,90 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/chmouel/lazyworktree/internal/config"
+	"github.com/chmouel/lazyworktree/internal/events"
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+// decodeEvents parses the NDJSON written to buf, skipping the selected
+// events emitted as the table is redrawn unless keepSelected is set.
+func decodeEvents(t *testing.T, buf *bytes.Buffer, keepSelected bool) []events.Event {
+	t.Helper()
+	var out []events.Event
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var e events.Event
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			t.Fatalf("invalid event line %q: %v", line, err)
+		}
+		if e.Type == events.Selected && !keepSelected {
+			continue
+		}
+		out = append(out, e)
+	}
+	buf.Reset()
+	return out
+}
+
+func TestEventStream(t *testing.T) {
+	var buf bytes.Buffer
+	m := NewModel(&config.AppConfig{WorktreeDir: t.TempDir()}, "")
+	m.repoKey = "example/repo"
+	m.SetEvents(events.New(&buf))
+
+	mainWt := &models.WorktreeInfo{Path: "/repo", Branch: "main", IsMain: true}
+	feature := &models.WorktreeInfo{Path: "/wt/feature", Branch: "feature"}
+	_, _ = m.handleWorktreesLoaded(worktreesLoadedMsg{worktrees: []*models.WorktreeInfo{mainWt, feature}})
+
+	got := decodeEvents(t, &buf, false)
+	refreshed := false
+	for _, e := range got {
+		if e.Type == events.RefreshComplete && e.Worktrees == 2 {
+			refreshed = true
+		}
+		if e.Type == events.Created {
+			t.Fatalf("expected no created events on the first load, got %+v", e)
+		}
+		if e.Repo != "example/repo" {
+			t.Fatalf("expected events tagged with the repository, got %q", e.Repo)
+		}
+	}
+	if !refreshed {
+		t.Fatalf("expected refresh-complete for 2 worktrees, got %+v", got)
+	}
+
+	bugfix := &models.WorktreeInfo{Path: "/wt/bugfix", Branch: "bugfix"}
+	_, _ = m.handleWorktreesLoaded(worktreesLoadedMsg{worktrees: []*models.WorktreeInfo{mainWt, bugfix}})
+	types := map[string]string{}
+	for _, e := range decodeEvents(t, &buf, false) {
+		types[e.Type] = e.Branch
+	}
+	if types[events.Created] != "bugfix" || types[events.Deleted] != "feature" {
+		t.Fatalf("expected bugfix created and feature deleted, got %v", types)
+	}
+
+	m.emitSelection(bugfix)
+	m.emitSelection(bugfix)
+	if got := decodeEvents(t, &buf, true); len(got) != 1 || got[0].Type != events.Selected || got[0].Path != "/wt/bugfix" {
+		t.Fatalf("expected one selected event, got %+v", got)
+	}
+
+	pr := &models.PRInfo{Number: 7, State: "OPEN", URL: "https://example.com/pr/7"}
+	_, _ = m.handlePRDataLoaded(prDataLoadedMsg{prMap: map[string]*models.PRInfo{"bugfix": pr}})
+	got = decodeEvents(t, &buf, false)
+	if len(got) != 1 || got[0].Type != events.PRStateChanged || got[0].PR == nil || got[0].PR.Number != 7 {
+		t.Fatalf("expected a pr-state-changed event for #7, got %+v", got)
+	}
+
+	_, _ = m.handlePRDataLoaded(prDataLoadedMsg{prMap: map[string]*models.PRInfo{"bugfix": pr}})
+	if got := decodeEvents(t, &buf, false); len(got) != 0 {
+		t.Fatalf("expected no event when the PR is unchanged, got %+v", got)
+	}
+}
@@ -0,0 +1,313 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chmouel/lazyworktree/internal/git"
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+// fakeResponse is what the fake answers a scripted call with.
+type fakeResponse struct {
+	output string
+	err    error
+}
+
+// fakeGitService is a scripted, in-memory GitService. Every call is
+// recorded as a line such as "git status --porcelain=v2" or
+// "RenameWorktree /old /new a b", and answered from the script by that
+// line: RunGit returns the output, boolean methods fail when an error is
+// scripted and error methods return it. Unscripted calls succeed with no
+// output. The repository state the app reads back lives in the fields.
+type fakeGitService struct {
+	host         string
+	mainBranch   string
+	mainPath     string
+	repoName     string
+	worktrees    []*models.WorktreeInfo
+	worktreesErr error
+	prs          map[string]*models.PRInfo // By head branch
+	prsErr       error
+	worktreePRs  map[string]*models.PRInfo // By worktree path
+	checks       map[string][]*models.CICheck
+	merged       []string
+
+	mu     sync.Mutex
+	script map[string]fakeResponse
+	calls  []string
+}
+
+var _ GitService = (*fakeGitService)(nil)
+
+func newFakeGitService() *fakeGitService {
+	return &fakeGitService{mainBranch: "main", repoName: "fake/repo", script: map[string]fakeResponse{}}
+}
+
+// on scripts the output of the call line.
+func (f *fakeGitService) on(line, output string) *fakeGitService {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.script[line] = fakeResponse{output: output}
+	return f
+}
+
+// fail scripts the call line to fail.
+func (f *fakeGitService) fail(line string) *fakeGitService {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.script[line] = fakeResponse{err: errors.New(line + " failed")}
+	return f
+}
+
+// call records line and returns its scripted response.
+func (f *fakeGitService) call(name string, args ...string) fakeResponse {
+	line := strings.Join(append([]string{name}, args...), " ")
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, line)
+	return f.script[line]
+}
+
+// ran reports whether a call line starting with prefix was made.
+func (f *fakeGitService) ran(prefix string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return slices.ContainsFunc(f.calls, func(line string) bool { return strings.HasPrefix(line, prefix) })
+}
+
+func (f *fakeGitService) Acquire(context.Context, git.Pool) func() { return func() {} }
+
+func (f *fakeGitService) ActivityDays(context.Context, string, int, time.Time) []int { return nil }
+
+func (f *fakeGitService) BranchChangedFiles(_ context.Context, baseRef, cwd string) []string {
+	if out := f.call("BranchChangedFiles", baseRef, cwd).output; out != "" {
+		return strings.Split(out, "\n")
+	}
+	return nil
+}
+
+func (f *fakeGitService) BranchDescription(_ context.Context, branch, worktreePath string) string {
+	return f.call("BranchDescription", branch, worktreePath).output
+}
+
+func (f *fakeGitService) ChangeIDs(context.Context, string, []string) map[string]string { return nil }
+
+func (f *fakeGitService) CheckoutSparse(_ context.Context, path string, dirs []string) bool {
+	return f.call("CheckoutSparse", append([]string{path}, dirs...)...).err == nil
+}
+
+func (f *fakeGitService) CherryPickCommit(_ context.Context, commitSHA, targetPath string) (bool, error) {
+	err := f.call("CherryPickCommit", commitSHA, targetPath).err
+	return err == nil, err
+}
+
+func (f *fakeGitService) Colocated(context.Context, string) git.Colocated { return git.Colocated{} }
+
+func (f *fakeGitService) CreateWorktreeFromPR(_ context.Context, prNumber int, remoteBranch, localBranch, targetPath string) bool {
+	return f.call("CreateWorktreeFromPR", strconv.Itoa(prNumber), remoteBranch, localBranch, targetPath).err == nil
+}
+
+func (f *fakeGitService) DetectHost(context.Context) string {
+	if f.host == "" {
+		return "unknown"
+	}
+	return f.host
+}
+
+func (f *fakeGitService) DisableSparseCheckout(_ context.Context, path string) bool {
+	return f.call("DisableSparseCheckout", path).err == nil
+}
+
+func (f *fakeGitService) ExecuteCommands(_ context.Context, cmdList []string, cwd string, _ map[string]string) error {
+	return f.call("ExecuteCommands", append([]string{cwd}, cmdList...)...).err
+}
+
+func (f *fakeGitService) ExecuteCommandsTo(_ context.Context, cmdList []string, cwd string, _ map[string]string, out io.Writer) error {
+	resp := f.call("ExecuteCommands", append([]string{cwd}, cmdList...)...)
+	_, _ = io.WriteString(out, resp.output)
+	return resp.err
+}
+
+func (f *fakeGitService) FetchAll(context.Context) bool { return f.call("FetchAll").err == nil }
+
+func (f *fakeGitService) FetchAllOpenIssues(context.Context) ([]*models.IssueInfo, error) {
+	return nil, f.call("FetchAllOpenIssues").err
+}
+
+func (f *fakeGitService) FetchAllOpenPRs(context.Context) ([]*models.PRInfo, error) {
+	f.call("FetchAllOpenPRs")
+	prs := make([]*models.PRInfo, 0, len(f.prs))
+	for _, pr := range f.prs {
+		prs = append(prs, pr)
+	}
+	return prs, f.prsErr
+}
+
+func (f *fakeGitService) FetchArgs(context.Context) []string {
+	return []string{"git", "fetch", "--all"}
+}
+
+func (f *fakeGitService) FetchBranch(_ context.Context, remote, branch, worktreePath string) bool {
+	return f.call("FetchBranch", remote, branch, worktreePath).err == nil
+}
+
+func (f *fakeGitService) FetchCIAnnotations(_ context.Context, prNumber int) ([]*models.CIAnnotation, error) {
+	return nil, f.call("FetchCIAnnotations", strconv.Itoa(prNumber)).err
+}
+
+func (f *fakeGitService) FetchCIStatus(_ context.Context, prNumber int, branch string) ([]*models.CICheck, error) {
+	resp := f.call("FetchCIStatus", strconv.Itoa(prNumber), branch)
+	return f.checks[branch], resp.err
+}
+
+func (f *fakeGitService) FetchDeployment(_ context.Context, branch string) (*models.DeploymentInfo, error) {
+	return nil, f.call("FetchDeployment", branch).err
+}
+
+func (f *fakeGitService) FetchMyOpenPRs(context.Context) ([]*models.PRInfo, error) {
+	return nil, f.call("FetchMyOpenPRs").err
+}
+
+func (f *fakeGitService) FetchObjects(_ context.Context, dir string, _ git.PartialClone, oids []string) error {
+	return f.call("FetchObjects", append([]string{dir}, oids...)...).err
+}
+
+func (f *fakeGitService) FetchPRForWorktreeWithError(_ context.Context, worktreePath string) (*models.PRInfo, error) {
+	resp := f.call("FetchPRForWorktree", worktreePath)
+	return f.worktreePRs[worktreePath], resp.err
+}
+
+func (f *fakeGitService) FetchPRMapForBranches(_ context.Context, branches []string) (map[string]*models.PRInfo, error) {
+	f.call("FetchPRMapForBranches", branches...)
+	if f.prsErr != nil {
+		return nil, f.prsErr
+	}
+	prMap := make(map[string]*models.PRInfo)
+	for _, branch := range branches {
+		if pr, ok := f.prs[branch]; ok {
+			prMap[branch] = pr
+		}
+	}
+	return prMap, nil
+}
+
+func (f *fakeGitService) FetchReviewerCandidates(context.Context) []string { return nil }
+
+func (f *fakeGitService) FixStagedWhitespace(_ context.Context, dir string) (bool, error) {
+	resp := f.call("FixStagedWhitespace", dir)
+	return resp.output != "", resp.err
+}
+
+func (f *fakeGitService) GetCommitFiles(_ context.Context, commitSHA, worktreePath string) ([]models.CommitFile, error) {
+	return nil, f.call("GetCommitFiles", commitSHA, worktreePath).err
+}
+
+func (f *fakeGitService) GetCommitMessages(context.Context, string, string) []models.CommitMessage {
+	return nil
+}
+
+func (f *fakeGitService) GetMainBranch(context.Context) string { return f.mainBranch }
+
+func (f *fakeGitService) GetMainWorktreePath(context.Context) string { return f.mainPath }
+
+func (f *fakeGitService) GetMergedBranches(_ context.Context, baseBranch string) []string {
+	f.call("GetMergedBranches", baseBranch)
+	return f.merged
+}
+
+func (f *fakeGitService) GetWorktrees(context.Context) ([]*models.WorktreeInfo, error) {
+	f.call("GetWorktrees")
+	return f.worktrees, f.worktreesErr
+}
+
+func (f *fakeGitService) IsGitHubOrGitLab(ctx context.Context) bool {
+	host := f.DetectHost(ctx)
+	return host == "github" || host == "gitlab"
+}
+
+func (f *fakeGitService) MissingObjects(context.Context, string, []string, []string) []string {
+	return nil
+}
+
+func (f *fakeGitService) PartialClone(context.Context) git.PartialClone { return git.PartialClone{} }
+
+func (f *fakeGitService) QueueDepth() git.PoolStats { return git.PoolStats{} }
+
+func (f *fakeGitService) RefreshWorktree(_ context.Context, path string) (*models.WorktreeInfo, error) {
+	f.call("RefreshWorktree", path)
+	for _, wt := range f.worktrees {
+		if wt.Path == path {
+			return wt, nil
+		}
+	}
+	return nil, errors.New("worktree not found: " + path)
+}
+
+func (f *fakeGitService) RemoteHost() string { return "" }
+
+func (f *fakeGitService) InvalidateRemoteCache() { f.call("InvalidateRemoteCache") }
+
+func (f *fakeGitService) RemoteLatency() time.Duration { return 0 }
+
+func (f *fakeGitService) RemoteURL(_ context.Context, remote, dir string) string {
+	return f.call("RemoteURL", remote, dir).output
+}
+
+func (f *fakeGitService) RenameWorktree(_ context.Context, oldPath, newPath, oldBranch, newBranch string) bool {
+	return f.call("RenameWorktree", oldPath, newPath, oldBranch, newBranch).err == nil
+}
+
+func (f *fakeGitService) RequestPRReviewers(_ context.Context, prNumber int, reviewers []string, _ string) error {
+	return f.call("RequestPRReviewers", append([]string{strconv.Itoa(prNumber)}, reviewers...)...).err
+}
+
+func (f *fakeGitService) ResolveRepoName(context.Context) string { return f.repoName }
+
+func (f *fakeGitService) Restack(_ context.Context, dir, branch string, link git.StackLink) error {
+	return f.call("Restack", dir, branch, link.Parent).err
+}
+
+func (f *fakeGitService) RunCommandChecked(_ context.Context, args []string, _, _ string) bool {
+	return f.call(strings.Join(args, " ")).err == nil
+}
+
+func (f *fakeGitService) RunGit(_ context.Context, args []string, _ string, _ []int, strip, _ bool) string {
+	out := f.call(strings.Join(args, " ")).output
+	if strip {
+		out = strings.TrimSpace(out)
+	}
+	return out
+}
+
+func (f *fakeGitService) SetPRDraft(_ context.Context, prNumber int, draft bool, _ string) error {
+	return f.call("SetPRDraft", strconv.Itoa(prNumber), strconv.FormatBool(draft)).err
+}
+
+func (f *fakeGitService) SetRemoteURL(_ context.Context, remote, rawURL, dir string) bool {
+	return f.call("SetRemoteURL", remote, rawURL, dir).err == nil
+}
+
+func (f *fakeGitService) SetSparseCheckout(_ context.Context, path string, dirs []string) bool {
+	return f.call("SetSparseCheckout", append([]string{path}, dirs...)...).err == nil
+}
+
+func (f *fakeGitService) SparseCheckoutDirs(context.Context, string) []string { return nil }
+
+func (f *fakeGitService) StackLinks(context.Context, []string, string, bool) map[string]git.StackLink {
+	return nil
+}
+
+func (f *fakeGitService) TreeDirectories(context.Context, string) []string { return nil }
+
+func (f *fakeGitService) UpstreamHeadBranch(_ context.Context, branch, worktreePath string) string {
+	return f.call("UpstreamHeadBranch", branch, worktreePath).output
+}
+
+func (f *fakeGitService) UseGitPager() bool { return false }
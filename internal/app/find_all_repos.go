@@ -0,0 +1,80 @@
+package app
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/chmouel/lazyworktree/internal/search"
+	"github.com/chmouel/lazyworktree/internal/utils"
+)
+
+// showFindAllRepos asks for a branch, directory or PR and lists the
+// matching worktrees of every repository under the worktree root.
+func (m *Model) showFindAllRepos() tea.Cmd {
+	if m.git.RemoteHost() != "" {
+		m.showInfo("Finding worktrees in all repositories works on local repositories only.", nil)
+		return nil
+	}
+	m.clearListSelection()
+	m.inputScreen = NewInputScreen("Find in all repositories: branch, directory or PR", "e.g. JIRA-123 or #42", "", m.theme)
+	m.inputSubmit = func(value string, checked bool) (tea.Cmd, bool) {
+		query := strings.TrimSpace(value)
+		if query == "" {
+			m.inputScreen.errorMsg = "Query cannot be empty."
+			return nil, false
+		}
+		cacheRoot := utils.CacheDir()
+		matches := search.Find(m.ctx, search.Repositories(m.getWorktreeDir(), cacheRoot), cacheRoot, query)
+		if len(matches) == 0 {
+			m.inputScreen.errorMsg = fmt.Sprintf("No worktree matches %q.", query)
+			return nil, false
+		}
+		return m.showFindMatches(query, matches), true
+	}
+	m.currentScreen = screenInput
+	return textinput.Blink
+}
+
+// showFindMatches lists the matches; choosing one in this repository
+// selects it, and one elsewhere quits to it like the shell integration's
+// jump.
+func (m *Model) showFindMatches(query string, matches []search.Match) tea.Cmd {
+	items := make([]selectionItem, 0, len(matches))
+	for i, match := range matches {
+		branch := match.Branch
+		if branch == "" {
+			branch = "(detached)"
+		}
+		description := match.Path
+		if match.PR != nil {
+			description = fmt.Sprintf("#%d %s · %s", match.PR.Number, match.PR.Title, match.Path)
+		}
+		items = append(items, selectionItem{
+			id:          strconv.Itoa(i),
+			label:       fmt.Sprintf("%s › %s", match.RepoName(), branch),
+			description: description,
+		})
+	}
+	m.listScreen = NewListSelectionScreen(items, fmt.Sprintf("Worktrees matching %q", query), "Filter matches...", "No matching worktrees.", m.windowWidth, m.windowHeight, "", m.theme)
+	m.listSubmit = func(item selectionItem) tea.Cmd {
+		m.clearListSelection()
+		i, err := strconv.Atoi(item.id)
+		if err != nil || i < 0 || i >= len(matches) {
+			return nil
+		}
+		path := matches[i].Path
+		if idx := m.filteredIndexForPath(path); idx >= 0 {
+			m.worktreeTable.SetCursor(idx)
+			m.selectedIndex = idx
+			return m.updateDetailsView()
+		}
+		m.selectedPath = path
+		m.stopGitWatcher()
+		return tea.Quit
+	}
+	m.currentScreen = screenListSelect
+	return textinput.Blink
+}
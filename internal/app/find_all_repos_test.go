@@ -0,0 +1,48 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/chmouel/lazyworktree/internal/config"
+	"github.com/chmouel/lazyworktree/internal/models"
+	"github.com/chmouel/lazyworktree/internal/search"
+)
+
+func TestShowFindMatches(t *testing.T) {
+	cfg := &config.AppConfig{WorktreeDir: t.TempDir()}
+	m := NewModel(cfg, "")
+	m.worktrees = []*models.WorktreeInfo{{Path: "/wt/api/main", Branch: "main", IsMain: true}, {Path: "/wt/api/login", Branch: "login"}}
+	m.filteredWts = m.worktrees
+	m.worktreeTable.SetRows([]table.Row{{"main", "", "", ""}, {"login", "", "", ""}})
+	matches := []search.Match{
+		{Repo: "/src/api", Path: "/wt/api/login", Branch: "login"},
+		{Repo: "/src/web", Path: "/wt/web/login", Branch: "login", PR: &models.PRInfo{Number: 42, Title: "Fix login"}},
+	}
+
+	m.showFindMatches("login", matches)
+	if m.currentScreen != screenListSelect || len(m.listScreen.items) != 2 {
+		t.Fatalf("expected the matches to be listed, got %s", screenName(m.currentScreen))
+	}
+	if got := m.listScreen.items[1].label; got != "web › login" {
+		t.Fatalf("unexpected label %q", got)
+	}
+	if got := m.listScreen.items[1].description; got != "#42 Fix login · /wt/web/login" {
+		t.Fatalf("unexpected description %q", got)
+	}
+
+	m.listSubmit(m.listScreen.items[0])
+	if m.selectedIndex != 1 || m.selectedPath != "" {
+		t.Fatalf("expected this repository's worktree to be selected, got %d %q", m.selectedIndex, m.selectedPath)
+	}
+
+	m.showFindMatches("login", matches)
+	cmd := m.listSubmit(m.listScreen.items[1])
+	if m.selectedPath != "/wt/web/login" || cmd == nil {
+		t.Fatalf("expected to quit to the other repository's worktree, got %q", m.selectedPath)
+	}
+	if _, ok := cmd().(tea.QuitMsg); !ok {
+		t.Fatal("expected the TUI to quit")
+	}
+}
@@ -0,0 +1,113 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// genericBranchPrefixes name the kind of change rather than the feature, so
+// branches sharing only one of them are not one family.
+var genericBranchPrefixes = map[string]bool{
+	"feature": true, "feat": true, "fix": true, "bugfix": true, "hotfix": true,
+	"chore": true, "docs": true, "refactor": true, "release": true, "test": true,
+}
+
+// branchFamily returns the part of a branch name its relatives share: the
+// name without its last "/" segment, or, under a generic prefix such as
+// feature/, that prefix and the first word of the rest. It is empty when
+// the branch has no family name.
+func branchFamily(branch string) string {
+	i := strings.LastIndexByte(branch, '/')
+	if i < 0 {
+		return ""
+	}
+	prefix := branch[:i]
+	if strings.Contains(prefix, "/") || !genericBranchPrefixes[strings.ToLower(prefix)] {
+		return prefix
+	}
+	rest := branch[i+1:]
+	word, _, found := strings.Cut(rest, "-")
+	if !found {
+		return ""
+	}
+	return prefix + "/" + word
+}
+
+// focusFamily returns the branches focus mode lists: the focused branch and,
+// in turn, those sharing a family name with one listed or stacked on or
+// under one through pull requests. It is nil when focus mode is off.
+func (m *Model) focusFamily() map[string]bool {
+	if m.focusBranch == "" {
+		return nil
+	}
+	mainBranch := ""
+	for _, wt := range m.worktrees {
+		if wt.IsMain {
+			mainBranch = wt.Branch
+		}
+	}
+
+	// Add relatives until nothing changes: branches sharing a family name
+	// and those whose pull request is based on, or is the base of, one
+	// already in. Stacks are never followed through the main branch, which
+	// every stack shares.
+	family := map[string]bool{m.focusBranch: true}
+	for changed := true; changed; {
+		changed = false
+		names := map[string]bool{}
+		for branch := range family {
+			if name := branchFamily(branch); name != "" {
+				names[name] = true
+			}
+		}
+		for _, wt := range m.worktrees {
+			if family[wt.Branch] || wt.Branch == mainBranch {
+				continue
+			}
+			if names[branchFamily(wt.Branch)] {
+				family[wt.Branch] = true
+				changed = true
+			}
+		}
+		for _, wt := range m.worktrees {
+			if wt.PR == nil || wt.PR.BaseBranch == "" || wt.Branch == mainBranch || wt.PR.BaseBranch == mainBranch {
+				continue
+			}
+			switch {
+			case family[wt.Branch] && !family[wt.PR.BaseBranch]:
+				family[wt.PR.BaseBranch] = true
+				changed = true
+			case family[wt.PR.BaseBranch] && !family[wt.Branch]:
+				family[wt.Branch] = true
+				changed = true
+			}
+		}
+	}
+	return family
+}
+
+// toggleFocusMode lists only the selected branch's family and widens the
+// detail panes, or lists every worktree again.
+func (m *Model) toggleFocusMode() tea.Cmd {
+	if m.focusBranch != "" {
+		m.focusBranch = ""
+		m.statusContent = "Focus mode off"
+		m.updateTable()
+		return m.updateDetailsView()
+	}
+	wt := m.selectedWorktree()
+	if wt == nil || wt.Branch == "" {
+		return nil
+	}
+	m.focusBranch = wt.Branch
+	m.updateTable()
+	m.statusContent = fmt.Sprintf("Focusing on %s (%d worktree(s)); run \"Focus mode\" again or press Esc to show all", wt.Branch, len(m.filteredWts))
+	for i, candidate := range m.filteredWts {
+		if candidate.Path == wt.Path {
+			m.worktreeTable.SetCursor(i)
+		}
+	}
+	return m.updateDetailsView()
+}
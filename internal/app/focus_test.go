@@ -0,0 +1,74 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/chmouel/lazyworktree/internal/config"
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+func TestBranchFamily(t *testing.T) {
+	tests := map[string]string{
+		"main":                 "",
+		"auth/login":           "auth",
+		"team/auth/login":      "team/auth",
+		"feature/auth-login":   "feature/auth",
+		"feature/auth-logout":  "feature/auth",
+		"feature/billing":      "",
+		"Fix/parser-overflow":  "Fix/parser",
+		"chmouel/cache-expiry": "chmouel",
+	}
+	for branch, want := range tests {
+		if got := branchFamily(branch); got != want {
+			t.Errorf("branchFamily(%q) = %q, want %q", branch, got, want)
+		}
+	}
+}
+
+func TestToggleFocusMode(t *testing.T) {
+	m := NewModel(&config.AppConfig{WorktreeDir: t.TempDir()}, "")
+	m.setWindowSize(120, 40)
+	m.worktrees = []*models.WorktreeInfo{
+		{Path: "/repo", Branch: "main", IsMain: true},
+		{Path: "/wt/login", Branch: "feature/auth-login", PR: &models.PRInfo{BaseBranch: "main"}},
+		{Path: "/wt/logout", Branch: "feature/auth-logout"},
+		{Path: "/wt/tokens", Branch: "tokens", PR: &models.PRInfo{BaseBranch: "feature/auth-login"}},
+		{Path: "/wt/tokens-ui", Branch: "tokens-ui", PR: &models.PRInfo{BaseBranch: "tokens"}},
+		{Path: "/wt/billing", Branch: "feature/billing", PR: &models.PRInfo{BaseBranch: "main"}},
+	}
+	m.updateTable()
+	m.worktreeTable.SetCursor(3)
+	before := m.computeLayout().rightWidth
+
+	m.toggleFocusMode()
+	if m.focusBranch != "tokens" {
+		t.Fatalf("expected focus on tokens, got %q", m.focusBranch)
+	}
+	var got []string
+	for _, wt := range m.filteredWts {
+		got = append(got, wt.Branch)
+	}
+	want := []string{"feature/auth-login", "feature/auth-logout", "tokens", "tokens-ui"}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("expected %v, got %v", want, got)
+		}
+	}
+	if wt := m.selectedWorktree(); wt == nil || wt.Branch != "tokens" {
+		t.Fatalf("expected the focused worktree to stay selected, got %+v", wt)
+	}
+	if after := m.computeLayout().rightWidth; after <= before {
+		t.Fatalf("expected wider details in focus mode, got %d then %d", before, after)
+	}
+	if !m.hasActiveFilterForPane(0) {
+		t.Fatal("expected focus mode to count as a filter")
+	}
+
+	m.clearCurrentPaneFilter()
+	if m.focusBranch != "" || len(m.filteredWts) != len(m.worktrees) {
+		t.Fatalf("expected Esc to leave focus mode, got %q with %d worktrees", m.focusBranch, len(m.filteredWts))
+	}
+}
@@ -0,0 +1,74 @@
+package app
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/chmouel/lazyworktree/internal/git"
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+// GitService is the git and forge backend the model drives. *git.Service
+// implements it; tests substitute a scripted fake so that refreshes, PR
+// loading and pruning run without the git binary.
+type GitService interface {
+	Acquire(ctx context.Context, kind git.Pool) func()
+	ActivityDays(ctx context.Context, path string, days int, now time.Time) []int
+	BranchChangedFiles(ctx context.Context, baseRef, cwd string) []string
+	BranchDescription(ctx context.Context, branch, worktreePath string) string
+	ChangeIDs(ctx context.Context, mainPath string, commits []string) map[string]string
+	CheckoutSparse(ctx context.Context, path string, dirs []string) bool
+	CherryPickCommit(ctx context.Context, commitSHA, targetPath string) (bool, error)
+	Colocated(ctx context.Context, mainPath string) git.Colocated
+	CreateWorktreeFromPR(ctx context.Context, prNumber int, remoteBranch, localBranch, targetPath string) bool
+	DetectHost(ctx context.Context) string
+	DisableSparseCheckout(ctx context.Context, path string) bool
+	ExecuteCommands(ctx context.Context, cmdList []string, cwd string, env map[string]string) error
+	ExecuteCommandsTo(ctx context.Context, cmdList []string, cwd string, env map[string]string, out io.Writer) error
+	FetchAll(ctx context.Context) bool
+	FetchAllOpenIssues(ctx context.Context) ([]*models.IssueInfo, error)
+	FetchAllOpenPRs(ctx context.Context) ([]*models.PRInfo, error)
+	FetchArgs(ctx context.Context) []string
+	FetchBranch(ctx context.Context, remote, branch, worktreePath string) bool
+	FetchCIAnnotations(ctx context.Context, prNumber int) ([]*models.CIAnnotation, error)
+	FetchCIStatus(ctx context.Context, prNumber int, branch string) ([]*models.CICheck, error)
+	FetchDeployment(ctx context.Context, branch string) (*models.DeploymentInfo, error)
+	FetchMyOpenPRs(ctx context.Context) ([]*models.PRInfo, error)
+	FetchObjects(ctx context.Context, dir string, pc git.PartialClone, oids []string) error
+	FetchPRForWorktreeWithError(ctx context.Context, worktreePath string) (*models.PRInfo, error)
+	FetchPRMapForBranches(ctx context.Context, branches []string) (map[string]*models.PRInfo, error)
+	FetchReviewerCandidates(ctx context.Context) []string
+	FixStagedWhitespace(ctx context.Context, dir string) (bool, error)
+	GetCommitFiles(ctx context.Context, commitSHA, worktreePath string) ([]models.CommitFile, error)
+	GetCommitMessages(ctx context.Context, baseRef, cwd string) []models.CommitMessage
+	GetMainBranch(ctx context.Context) string
+	GetMainWorktreePath(ctx context.Context) string
+	GetMergedBranches(ctx context.Context, baseBranch string) []string
+	GetWorktrees(ctx context.Context) ([]*models.WorktreeInfo, error)
+	InvalidateRemoteCache()
+	IsGitHubOrGitLab(ctx context.Context) bool
+	MissingObjects(ctx context.Context, dir string, revs []string, paths []string) []string
+	PartialClone(ctx context.Context) git.PartialClone
+	QueueDepth() git.PoolStats
+	RefreshWorktree(ctx context.Context, path string) (*models.WorktreeInfo, error)
+	RemoteHost() string
+	RemoteLatency() time.Duration
+	RemoteURL(ctx context.Context, remote, dir string) string
+	RenameWorktree(ctx context.Context, oldPath, newPath, oldBranch, newBranch string) bool
+	RequestPRReviewers(ctx context.Context, prNumber int, reviewers []string, cwd string) error
+	ResolveRepoName(ctx context.Context) string
+	Restack(ctx context.Context, dir, branch string, link git.StackLink) error
+	RunCommandChecked(ctx context.Context, args []string, cwd, errorPrefix string) bool
+	RunGit(ctx context.Context, args []string, cwd string, okReturncodes []int, strip, silent bool) string
+	SetPRDraft(ctx context.Context, prNumber int, draft bool, cwd string) error
+	SetRemoteURL(ctx context.Context, remote, rawURL, dir string) bool
+	SetSparseCheckout(ctx context.Context, path string, dirs []string) bool
+	SparseCheckoutDirs(ctx context.Context, path string) []string
+	StackLinks(ctx context.Context, branches []string, mainBranch string, record bool) map[string]git.StackLink
+	TreeDirectories(ctx context.Context, ref string) []string
+	UpstreamHeadBranch(ctx context.Context, branch, worktreePath string) string
+	UseGitPager() bool
+}
+
+var _ GitService = (*git.Service)(nil)
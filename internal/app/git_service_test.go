@@ -0,0 +1,101 @@
+package app
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/chmouel/lazyworktree/internal/config"
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+func TestFakeGitServiceRefresh(t *testing.T) {
+	fake := newFakeGitService()
+	fake.worktrees = []*models.WorktreeInfo{
+		{Path: "/repo", Branch: "main", IsMain: true},
+		{Path: "/wt/feature", Branch: "feature", Dirty: true, Modified: 2},
+	}
+	m := NewModelWithGit(&config.AppConfig{WorktreeDir: t.TempDir()}, "", fake)
+
+	m.Update(m.startRefresh()())
+	if !fake.ran("GetWorktrees") || len(m.worktrees) != 2 || len(m.worktreeTable.Rows()) != 2 {
+		t.Fatalf("expected both worktrees listed, got %d", len(m.worktrees))
+	}
+
+	fake.worktrees = fake.worktrees[:1]
+	m.Update(m.startRefresh()())
+	if len(m.worktrees) != 1 || len(m.worktreeTable.Rows()) != 1 {
+		t.Fatalf("expected the removed worktree to disappear, got %d", len(m.worktrees))
+	}
+}
+
+func TestFakeGitServicePRData(t *testing.T) {
+	fake := newFakeGitService()
+	fake.host = "github"
+	fake.worktrees = []*models.WorktreeInfo{
+		{Path: "/repo", Branch: "main", IsMain: true},
+		{Path: "/wt/feature", Branch: "feature"},
+		{Path: "/wt/fork", Branch: "local-name"},
+		{Path: "/wt/lookup", Branch: "lookup"},
+		{Path: "/wt/broken", Branch: "broken"},
+	}
+	fake.prs = map[string]*models.PRInfo{
+		"feature":      {Number: 7, State: "OPEN"},
+		"their-branch": {Number: 8, State: "OPEN"},
+	}
+	fake.worktreePRs = map[string]*models.PRInfo{"/wt/lookup": {Number: 9, State: "MERGED"}}
+	fake.on("UpstreamHeadBranch local-name /wt/fork", "their-branch").fail("FetchPRForWorktree /wt/broken")
+	m := NewModelWithGit(&config.AppConfig{WorktreeDir: t.TempDir()}, "", fake)
+	m.Update(m.startRefresh()())
+
+	m.Update(m.fetchPRData()())
+	want := map[string]int{"feature": 7, "local-name": 8, "lookup": 9}
+	for _, wt := range m.worktrees {
+		if number, ok := want[wt.Branch]; ok && (wt.PR == nil || wt.PR.Number != number) {
+			t.Fatalf("expected %s to have PR #%d, got %+v", wt.Branch, number, wt.PR)
+		}
+	}
+	if !m.prDataLoaded || !fake.ran("FetchPRForWorktree /wt/lookup") {
+		t.Fatal("expected the unmatched worktree to be looked up")
+	}
+	if broken := m.worktrees[4]; broken.PR != nil || broken.PRFetchError == "" {
+		t.Fatalf("expected the failed lookup to be recorded, got %+v", broken)
+	}
+}
+
+func TestFakeGitServicePruneMerged(t *testing.T) {
+	cfg := &config.AppConfig{WorktreeDir: t.TempDir()}
+	fake := newFakeGitService()
+	fake.host = "github"
+	fake.repoName = "prune-test"
+	dir := filepath.Join(cfg.WorktreeDir, "prune-test")
+	fake.worktrees = []*models.WorktreeInfo{
+		{Path: "/repo", Branch: "main", IsMain: true},
+		{Path: filepath.Join(dir, "shipped"), Branch: "shipped"},
+		{Path: filepath.Join(dir, "old"), Branch: "old", Dirty: true},
+		{Path: filepath.Join(dir, "open"), Branch: "open"},
+	}
+	fake.prs = map[string]*models.PRInfo{"shipped": {Number: 3, State: "MERGED"}, "open": {Number: 4, State: "OPEN"}}
+	fake.merged = []string{"shipped", "old"}
+	m := NewModelWithGit(cfg, "", fake)
+	m.Update(m.startRefresh()())
+
+	cmd := m.showPruneMerged()
+	if cmd == nil || m.currentScreen != screenLoading {
+		t.Fatal("expected PR data to be refreshed first")
+	}
+	m.Update(cmd())
+	if m.currentScreen != screenChecklist || !fake.ran("GetMergedBranches main") {
+		t.Fatalf("expected the prune checklist, got %s", screenName(m.currentScreen))
+	}
+	items := m.checklistScreen.items
+	if len(items) != 2 || items[0].ID != "old" || items[1].ID != "shipped" {
+		t.Fatalf("expected old and shipped as candidates, got %+v", items)
+	}
+	if items[0].Checked || !strings.Contains(items[0].Description, "branch merged") || !strings.Contains(items[0].Description, "UNCOMMITTED") {
+		t.Fatalf("expected the dirty branch unchecked, got %+v", items[0])
+	}
+	if !items[1].Checked || !strings.Contains(items[1].Description, "PR + branch merged") {
+		t.Fatalf("expected the shipped PR checked, got %+v", items[1])
+	}
+}
@@ -2,12 +2,19 @@ package app
 
 import (
 	"path/filepath"
-	"sort"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/chmouel/lazyworktree/internal/models"
 )
 
+// dispatchKey sends a key to the open dialog, or else to the main view.
+func (m *Model) dispatchKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.currentScreen != screenNone {
+		return m.handleScreenKey(msg)
+	}
+	return m.handleKeyMsg(msg)
+}
+
 // handleKeyMsg processes keyboard input when not in a modal screen.
 func (m *Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
@@ -80,11 +87,17 @@ func (m *Model) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 	}
 
-	// Check for custom commands first - allows users to override built-in keys
+	// Macros and custom commands come first - allows users to override built-in keys
+	if _, ok := m.config.Macros[msg.String()]; ok {
+		return m, m.runMacro(msg.String())
+	}
 	if _, ok := m.config.CustomCommands[msg.String()]; ok {
 		return m, m.executeCustomCommand(msg.String())
 	}
 
+	if cmd, ok := m.handleMotionKey(msg.String()); ok {
+		return m, cmd
+	}
 	return m.handleBuiltInKey(msg)
 }
 
@@ -143,9 +156,7 @@ func (m *Model) restoreFocusAfterFilter() {
 func (m *Model) clearCurrentPaneFilter() (tea.Model, tea.Cmd) {
 	switch m.focusedPane {
 	case 0:
-		m.filterQuery = ""
-		m.filterInput.SetValue("")
-		m.updateTable()
+		m.clearWorktreeFilter()
 	case 1:
 		m.statusFilterQuery = ""
 		m.filterInput.SetValue("")
@@ -158,6 +169,17 @@ func (m *Model) clearCurrentPaneFilter() (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// clearWorktreeFilter drops every filter of the worktree list: the query,
+// the project, focus mode and the owner filter.
+func (m *Model) clearWorktreeFilter() {
+	m.filterQuery = ""
+	m.projectFilter = ""
+	m.focusBranch = ""
+	m.ownerFilter = false
+	m.filterInput.SetValue("")
+	m.updateTable()
+}
+
 func (m *Model) handleGotoTop() (tea.Model, tea.Cmd) {
 	switch m.focusedPane {
 	case 0:
@@ -383,7 +405,13 @@ func (m *Model) handleBuiltInKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.statusViewport.GotoBottom()
 			return m, nil
 		}
-		return m, nil
+		return m.handleGotoBottom()
+
+	case "H":
+		return m, m.gotoVisibleRow(func(top, _ int) int { return top })
+
+	case "L":
+		return m, m.gotoVisibleRow(func(top, visible int) int { return top + visible - 1 })
 
 	case keyEnter:
 		return m.handleEnterKey()
@@ -392,7 +420,7 @@ func (m *Model) handleBuiltInKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.loading = true
 		m.loadingScreen = NewLoadingScreen(loadingRefreshWorktrees, m.theme)
 		m.currentScreen = screenLoading
-		return m, m.refreshWorktrees()
+		return m, m.requestRefresh()
 
 	case "c":
 		if m.focusedPane == 1 {
@@ -432,17 +460,7 @@ func (m *Model) handleBuiltInKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case "p":
-		m.ciCache = make(map[string]*ciCacheEntry)
-		m.prDataLoaded = false
-		// Must update table rows immediately to match the column count change
-		// Otherwise View() -> applyLayout() -> updateTableColumns() will create
-		// a mismatch between column count (4) and row element count (5)
-		m.updateTable()
-		m.loading = true
-		m.statusContent = "Fetching PR data..."
-		m.loadingScreen = NewLoadingScreen("Fetching PR data...", m.theme)
-		m.currentScreen = screenLoading
-		return m, m.fetchPRData()
+		return m, m.startPRPipeline()
 
 	case "P":
 		return m, m.pushToUpstream()
@@ -450,6 +468,12 @@ func (m *Model) handleBuiltInKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case "S":
 		return m, m.syncWithUpstream()
 
+	case "u":
+		return m, m.pullFromUpstream()
+
+	case "U":
+		return m, m.forcePushToUpstream()
+
 	case "R":
 		m.loading = true
 		m.statusContent = "Fetching remotes..."
@@ -457,6 +481,9 @@ func (m *Model) handleBuiltInKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.currentScreen = screenLoading
 		return m, m.fetchRemotes()
 
+	case "F":
+		return m, m.fetchSelectedBranch()
+
 	case "f":
 		target := filterTargetWorktrees
 		switch m.focusedPane {
@@ -467,6 +494,13 @@ func (m *Model) handleBuiltInKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return m, m.startFilter(target)
 
+	case "x":
+		// Clears the worktree filter shown in the header from any pane
+		if m.hasActiveFilterForPane(0) {
+			m.clearWorktreeFilter()
+		}
+		return m, nil
+
 	case "/":
 		target := searchTargetWorktrees
 		switch m.focusedPane {
@@ -486,14 +520,37 @@ func (m *Model) handleBuiltInKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			}
 			return m, m.stageCurrentFile(*node.File)
 		}
-		// Otherwise: cycle through sort modes: path -> active -> switched -> path
-		m.sortMode = (m.sortMode + 1) % 3
-		m.updateTable()
-		return m, nil
+		// Otherwise: cycle through sort modes: path -> active -> switched -> manual -> path
+		return m, m.cycleSortMode(1)
+
+	case "b":
+		return m, m.togglePin()
+
+	case "K":
+		return m, m.moveWorktree(-1)
+
+	case "J":
+		return m, m.moveWorktree(1)
+
+	case "{":
+		return m, m.cycleSortMode(-1)
+
+	case "}":
+		return m, m.cycleSortMode(1)
+
+	case "<":
+		return m, m.navigateHistory(-1)
+
+	case ">":
+		return m, m.navigateHistory(1)
 
 	case "ctrl+p", ":":
 		return m, m.showCommandPalette()
 
+	case whichKeyLeader:
+		m.showWhichKey()
+		return m, nil
+
 	case "?":
 		m.currentScreen = screenHelp
 		m.helpScreen = NewHelpScreen(m.windowWidth, m.windowHeight, m.config.CustomCommands, m.theme)
@@ -502,9 +559,18 @@ func (m *Model) handleBuiltInKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case "g":
 		return m, m.openLazyGit()
 
+	case "O":
+		return m, m.openDeployment()
+
+	case "v":
+		return m, m.togglePreview()
+
 	case "o":
 		return m, m.openPR()
 
+	case "i":
+		return m, m.showPRDescription()
+
 	case "m":
 		return m, m.showRenameWorktree()
 
@@ -514,6 +580,22 @@ func (m *Model) handleBuiltInKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case "X":
 		return m, m.showPruneMerged()
 
+	case "M":
+		return m, m.showSyncMyPRs()
+
+	case "T":
+		return m, m.toggleDateFormat()
+
+	case "E":
+		m.showErrors()
+		return m, nil
+
+	case "W":
+		return m, m.fixStagedWhitespace()
+
+	case macroRecordKey:
+		return m, m.toggleMacroRecording(false)
+
 	case "!":
 		return m, m.showRunCommand()
 
@@ -537,6 +619,10 @@ func (m *Model) handleBuiltInKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.paletteScreen = nil
 			return m, nil
 		}
+		if m.prPipeline != nil {
+			m.cancelPRPipeline()
+			return m, nil
+		}
 		if m.hasActiveFilterForPane(m.focusedPane) {
 			return m.clearCurrentPaneFilter()
 		}
@@ -584,6 +670,10 @@ func (m *Model) handleNavigationDown(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		cmds = append(cmds, cmd)
 		cmds = append(cmds, m.debouncedUpdateDetailsView())
 	case 1:
+		if m.previewMode || m.showingInitOutput() {
+			m.statusViewport.ScrollDown(1)
+			return m, nil
+		}
 		// Navigate through status tree items
 		if len(m.statusTreeFlat) > 0 {
 			if m.statusTreeIndex < len(m.statusTreeFlat)-1 {
@@ -610,6 +700,10 @@ func (m *Model) handleNavigationUp(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		cmds = append(cmds, cmd)
 		cmds = append(cmds, m.debouncedUpdateDetailsView())
 	case 1:
+		if m.previewMode || m.showingInitOutput() {
+			m.statusViewport.ScrollUp(1)
+			return m, nil
+		}
 		// Navigate through status tree items
 		if len(m.statusTreeFlat) > 0 {
 			if m.statusTreeIndex > 0 {
@@ -632,20 +726,7 @@ func (m *Model) handleFilterNavigation(keyStr string, fillInput bool) (tea.Model
 		// Alt+n/Alt+p: navigate through all worktrees (sorted)
 		workList = make([]*models.WorktreeInfo, len(m.worktrees))
 		copy(workList, m.worktrees)
-		switch m.sortMode {
-		case sortModeLastActive:
-			sort.Slice(workList, func(i, j int) bool {
-				return workList[i].LastActiveTS > workList[j].LastActiveTS
-			})
-		case sortModeLastSwitched:
-			sort.Slice(workList, func(i, j int) bool {
-				return workList[i].LastSwitchedTS > workList[j].LastSwitchedTS
-			})
-		default: // sortModePath
-			sort.Slice(workList, func(i, j int) bool {
-				return workList[i].Path < workList[j].Path
-			})
-		}
+		m.sortWorktrees(workList)
 	} else {
 		// Up/Down: navigate through filtered worktrees
 		workList = m.filteredWts
@@ -779,11 +860,10 @@ func (m *Model) handleEnterKey() (tea.Model, tea.Cmd) {
 	case 0:
 		// Jump to worktree
 		if m.selectedIndex >= 0 && m.selectedIndex < len(m.filteredWts) {
-			selectedPath := m.filteredWts[m.selectedIndex].Path
-			m.persistLastSelected(selectedPath)
-			m.selectedPath = selectedPath
-			m.stopGitWatcher()
-			return m, tea.Quit
+			if wt := m.filteredWts[m.selectedIndex]; wt.Bare {
+				return m, m.enterBareRepository()
+			}
+			return m, m.switchToWorktree(m.filteredWts[m.selectedIndex].Path)
 		}
 	case 1:
 		// Handle Enter on status tree items
@@ -880,6 +960,13 @@ func (m *Model) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
 			}
 		}
 
+		// A click on the worktree table's header sorts by that column;
+		// the header sits below the pane border and title.
+		if targetPane == 0 && mouseY-leftY == 2 {
+			// Columns start after the pane border and padding
+			cmds = append(cmds, m.handleHeaderClick(mouseX-leftX-2))
+		}
+
 		// Handle clicks within the pane to select items
 		if targetPane == 0 && len(m.filteredWts) > 0 {
 			// Calculate which row was clicked in the worktree table
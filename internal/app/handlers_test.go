@@ -2094,6 +2094,43 @@ func TestEscClearsWorktreeFilter(t *testing.T) {
 	}
 }
 
+func TestQuickFilterPill(t *testing.T) {
+	cfg := &config.AppConfig{
+		WorktreeDir: t.TempDir(),
+	}
+	m := NewModel(cfg, "")
+	m.worktrees = []*models.WorktreeInfo{
+		{Path: filepath.Join(cfg.WorktreeDir, "test-wt"), Branch: testFeat},
+	}
+	m.updateTable()
+	layout := layoutDims{width: 160}
+
+	if header := m.renderHeader(layout); strings.Contains(header, "x clear") {
+		t.Fatalf("expected no filter pill without a filter, got %q", header)
+	}
+
+	m.filterQuery = testFilterQuery
+	m.ownerFilter = true
+	m.showingFilter = true
+	if header := m.renderHeader(layout); strings.Contains(header, "x clear") {
+		t.Fatalf("expected no pill while the filter is being typed, got %q", header)
+	}
+	m.showingFilter = false
+	header := m.renderHeader(layout)
+	if !strings.Contains(header, `"`+testFilterQuery+`" + mine`) || !strings.Contains(header, "f edit · x clear") {
+		t.Fatalf("expected the filter pill in the header, got %q", header)
+	}
+
+	m.focusedPane = 2
+	if header := m.renderHeader(layout); strings.Contains(header, "f edit") || !strings.Contains(header, "x clear") {
+		t.Fatalf("expected only the clear key away from the worktree list, got %q", header)
+	}
+	m.handleBuiltInKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}})
+	if m.hasActiveFilterForPane(0) || strings.Contains(m.renderHeader(layout), "x clear") {
+		t.Fatalf("expected x to clear the worktree filter, got %q and owner %t", m.filterQuery, m.ownerFilter)
+	}
+}
+
 func TestEscClearsStatusFilter(t *testing.T) {
 	cfg := &config.AppConfig{
 		WorktreeDir: t.TempDir(),
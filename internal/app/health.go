@@ -0,0 +1,396 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/chmouel/lazyworktree/internal/config"
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+const (
+	// healthCIColumn heads the matrix column summarising remote CI.
+	healthCIColumn = "CI"
+	// healthOutputLimit is how much of a failing check's output is kept.
+	healthOutputLimit = 8000
+)
+
+// healthResult is the latest outcome of a health check in a worktree.
+type healthResult struct {
+	Passed     bool          `json:"passed"`
+	Commit     string        `json:"commit,omitempty"` // HEAD when the check ran
+	FinishedAt time.Time     `json:"finished_at"`
+	Duration   time.Duration `json:"duration"`
+	Output     string        `json:"output,omitempty"`
+}
+
+// healthResults maps worktree paths to check names to their latest result.
+type healthResults map[string]map[string]*healthResult
+
+// healthCheckDoneMsg reports a finished health check.
+type healthCheckDoneMsg struct {
+	path   string
+	check  string
+	result *healthResult
+}
+
+func healthKey(path, check string) string {
+	return path + "\x00" + check
+}
+
+func (m *Model) healthStatePath() string {
+	return filepath.Join(m.getRepoCacheDir(), models.HealthFilename)
+}
+
+// loadHealthResults reads the stored results once; a missing or damaged
+// file yields none.
+func (m *Model) loadHealthResults() {
+	if m.healthResults != nil {
+		return
+	}
+	m.healthResults = healthResults{}
+	// #nosec G304 -- path is derived from the cache directory
+	if data, err := os.ReadFile(m.healthStatePath()); err == nil {
+		if err := json.Unmarshal(data, &m.healthResults); err != nil || m.healthResults == nil {
+			m.healthResults = healthResults{}
+		}
+	}
+}
+
+// saveHealthResults stores the results, forgetting worktrees that are gone.
+func (m *Model) saveHealthResults() {
+	known := make(map[string]bool, len(m.worktrees))
+	for _, wt := range m.worktrees {
+		known[wt.Path] = true
+	}
+	for path := range m.healthResults {
+		if !known[path] {
+			delete(m.healthResults, path)
+		}
+	}
+	data, err := json.MarshalIndent(m.healthResults, "", "  ")
+	if err != nil {
+		m.debugf("health: encode results: %v", err)
+		return
+	}
+	path := m.healthStatePath()
+	if err := os.MkdirAll(filepath.Dir(path), defaultDirPerms); err != nil {
+		m.debugf("health: %v", err)
+		return
+	}
+	if err := os.WriteFile(path, data, defaultFilePerms); err != nil {
+		m.debugf("health: %v", err)
+	}
+}
+
+// healthChecksEnabled reports whether the matrix has anything to show.
+func (m *Model) healthChecksEnabled() bool {
+	return len(m.config.HealthChecks) > 0 || m.prDataLoaded
+}
+
+// healthColumns returns the configured checks followed by remote CI.
+func (m *Model) healthColumns() []string {
+	columns := make([]string, 0, len(m.config.HealthChecks)+1)
+	for _, check := range m.config.HealthChecks {
+		columns = append(columns, check.Name)
+	}
+	return append(columns, healthCIColumn)
+}
+
+// healthCheck returns the configured check named name.
+func (m *Model) healthCheck(name string) *config.HealthCheck {
+	for _, check := range m.config.HealthChecks {
+		if check.Name == name {
+			return check
+		}
+	}
+	return nil
+}
+
+// runHealthCheck runs a check in a worktree, unless it is already running.
+func (m *Model) runHealthCheck(wt *models.WorktreeInfo, check *config.HealthCheck) tea.Cmd {
+	if wt.Bare || wt.Prunable {
+		return nil
+	}
+	key := healthKey(wt.Path, check.Name)
+	if m.healthRunning[key] {
+		return nil
+	}
+	if m.healthRunning == nil {
+		m.healthRunning = make(map[string]bool)
+	}
+	m.healthRunning[key] = true
+	path, branch, head := wt.Path, wt.Branch, wt.Head
+	name, command := check.Name, check.Command
+	return func() tea.Msg {
+		env := m.buildCommandEnv(branch, path)
+		env["LAZYWORKTREE_HEALTH_CHECK"] = name
+		start := time.Now()
+		err := m.git.ExecuteCommands(m.ctx, []string{command}, path, env)
+		result := &healthResult{Passed: err == nil, Commit: head, FinishedAt: time.Now(), Duration: time.Since(start)}
+		if err != nil {
+			output := strings.TrimPrefix(err.Error(), command+": ")
+			if len(output) > healthOutputLimit {
+				output = "…" + output[len(output)-healthOutputLimit:]
+			}
+			result.Output = output
+		}
+		return healthCheckDoneMsg{path: path, check: name, result: result}
+	}
+}
+
+// handleHealthCheckDone records a result and refreshes the matrix.
+func (m *Model) handleHealthCheckDone(msg healthCheckDoneMsg) {
+	delete(m.healthRunning, healthKey(msg.path, msg.check))
+	m.loadHealthResults()
+	if m.healthResults[msg.path] == nil {
+		m.healthResults[msg.path] = make(map[string]*healthResult)
+	}
+	m.healthResults[msg.path][msg.check] = msg.result
+	m.saveHealthResults()
+
+	outcome := "passed"
+	if !msg.result.Passed {
+		outcome = "failed"
+	}
+	m.statusContent = fmt.Sprintf("%s %s in %s (%s)", msg.check, outcome, filepath.Base(msg.path), msg.result.Duration.Round(time.Second))
+	m.refreshHealthScreen()
+}
+
+// healthCell describes one worktree's state for a matrix column.
+func (m *Model) healthCell(wt *models.WorktreeInfo, column string) healthCell {
+	if column == healthCIColumn {
+		return m.healthCICell(wt)
+	}
+	if m.healthRunning[healthKey(wt.Path, column)] {
+		return healthCell{state: healthRunning}
+	}
+	result := m.healthResults[wt.Path][column]
+	if result == nil {
+		return healthCell{state: healthUnknown}
+	}
+	cell := healthCell{state: healthFail, age: compactAge(result.FinishedAt)}
+	if result.Passed {
+		cell.state = healthPass
+	}
+	cell.stale = result.Commit != "" && wt.Head != "" && result.Commit != wt.Head
+	return cell
+}
+
+// healthCICell summarises the cached CI checks of the worktree's PR.
+func (m *Model) healthCICell(wt *models.WorktreeInfo) healthCell {
+	if wt.PR == nil {
+		return healthCell{state: healthNone}
+	}
+	cached, ok := m.ciCache[wt.Branch]
+	if !ok || len(cached.checks) == 0 {
+		return healthCell{state: healthUnknown}
+	}
+	cell := healthCell{state: healthPass, age: compactAge(cached.fetchedAt)}
+	for _, check := range cached.checks {
+		switch check.Conclusion {
+		case "failure", "cancelled":
+			cell.state = healthFail
+		case "pending", "":
+			if cell.state != healthFail {
+				cell.state = healthRunning
+			}
+		}
+	}
+	return cell
+}
+
+// healthRows builds a matrix row per worktree, in table order.
+func (m *Model) healthRows() []healthRow {
+	columns := m.healthColumns()
+	rows := make([]healthRow, 0, len(m.filteredWts))
+	for _, wt := range m.filteredWts {
+		name := filepath.Base(wt.Path)
+		if wt.IsMain {
+			name = mainWorktreeName
+		}
+		row := healthRow{path: wt.Path, name: name, cells: make([]healthCell, 0, len(columns))}
+		for _, column := range columns {
+			row.cells = append(row.cells, m.healthCell(wt, column))
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// refreshHealthScreen redraws the matrix if it is open.
+func (m *Model) refreshHealthScreen() {
+	if m.healthScreen != nil {
+		m.healthScreen.SetRows(m.healthRows())
+	}
+}
+
+// showHealthMatrix opens the matrix of local check and CI results, and
+// refreshes stale CI results of worktrees with a PR.
+func (m *Model) showHealthMatrix() tea.Cmd {
+	if !m.healthChecksEnabled() {
+		m.showInfo("No health checks are configured.\n\nAdd commands under health_checks in the configuration, or fetch PR data (p) to see CI results.", nil)
+		return nil
+	}
+	m.loadHealthResults()
+	m.healthScreen = NewHealthScreen(m.healthColumns(), m.healthRows(), m.windowWidth, m.windowHeight, m.theme)
+	m.currentScreen = screenHealth
+
+	var cmds []tea.Cmd
+	for _, wt := range m.filteredWts {
+		if wt.PR == nil {
+			continue
+		}
+		if cached, ok := m.ciCache[wt.Branch]; ok && time.Since(cached.fetchedAt) < ciCacheTTL {
+			continue
+		}
+		cmds = append(cmds, m.fetchCIStatus(wt.PR.Number, wt.Branch))
+	}
+	return tea.Batch(cmds...)
+}
+
+// healthWorktree returns the worktree at path.
+func (m *Model) healthWorktree(path string) *models.WorktreeInfo {
+	for _, wt := range m.worktrees {
+		if wt.Path == path {
+			return wt
+		}
+	}
+	return nil
+}
+
+// runHealthCells runs the checks of the given columns in the given rows;
+// the CI column fetches the PR's checks again.
+func (m *Model) runHealthCells(paths, columns []string) tea.Cmd {
+	var cmds []tea.Cmd
+	for _, path := range paths {
+		wt := m.healthWorktree(path)
+		if wt == nil {
+			continue
+		}
+		for _, column := range columns {
+			if column == healthCIColumn {
+				if wt.PR != nil {
+					cmds = append(cmds, m.fetchCIStatus(wt.PR.Number, wt.Branch))
+				}
+				continue
+			}
+			if check := m.healthCheck(column); check != nil {
+				cmds = append(cmds, m.runHealthCheck(wt, check))
+			}
+		}
+	}
+	m.refreshHealthScreen()
+	return tea.Batch(cmds...)
+}
+
+// handleHealthKey runs checks from the matrix or shows a failure's output.
+func (m *Model) handleHealthKey(msg tea.KeyMsg) tea.Cmd {
+	s := m.healthScreen
+	keyStr := msg.String()
+	if keyStr == keyQ || isEscKey(keyStr) {
+		m.healthScreen = nil
+		m.currentScreen = screenNone
+		return nil
+	}
+	path, column, ok := s.Selected()
+	switch keyStr {
+	case keyEnter:
+		if ok {
+			return m.runHealthCells([]string{path}, []string{column})
+		}
+		return nil
+	case "r":
+		if ok {
+			return m.runHealthCells([]string{path}, m.healthColumns())
+		}
+		return nil
+	case "c":
+		if ok {
+			return m.runHealthCells(s.Paths(), []string{column})
+		}
+		return nil
+	case "a":
+		return m.runHealthCells(s.Paths(), m.healthColumns())
+	case "o":
+		if ok {
+			m.showHealthOutput(path, column)
+		}
+		return nil
+	}
+	m.healthScreen, _ = s.Update(msg)
+	return nil
+}
+
+// showHealthOutput shows a check's last result, with its output when it
+// failed.
+func (m *Model) showHealthOutput(path, column string) {
+	name := filepath.Base(path)
+	if column == healthCIColumn {
+		wt := m.healthWorktree(path)
+		if wt != nil && wt.PR != nil {
+			m.showMarkdown(fmt.Sprintf("CI of %s", name), wt.PR.URL, m.healthCIMarkdown(wt), screenHealth)
+		}
+		return
+	}
+	result := m.healthResults[path][column]
+	if result == nil {
+		m.statusContent = fmt.Sprintf("%s has not run in %s yet", column, name)
+		return
+	}
+	outcome := "Passed"
+	if !result.Passed {
+		outcome = "Failed"
+	}
+	var body strings.Builder
+	fmt.Fprintf(&body, "%s %s, taking %s", outcome, formatRelativeTime(result.FinishedAt), result.Duration.Round(time.Second))
+	if result.Commit != "" {
+		fmt.Fprintf(&body, ", at commit `%s`", result.Commit[:min(7, len(result.Commit))])
+	}
+	body.WriteString(".\n")
+	if check := m.healthCheck(column); check != nil {
+		fmt.Fprintf(&body, "\nCommand: `%s`\n", check.Command)
+	}
+	if result.Output != "" {
+		fmt.Fprintf(&body, "\n```\n%s\n```\n", result.Output)
+	}
+	m.showMarkdown(fmt.Sprintf("%s in %s", column, name), "", body.String(), screenHealth)
+}
+
+// healthCIMarkdown lists the cached CI checks of a worktree's PR.
+func (m *Model) healthCIMarkdown(wt *models.WorktreeInfo) string {
+	cached, ok := m.ciCache[wt.Branch]
+	if !ok || len(cached.checks) == 0 {
+		return "No CI checks have been fetched for this PR yet."
+	}
+	var body strings.Builder
+	fmt.Fprintf(&body, "Checks of PR #%d, fetched %s.\n\n", wt.PR.Number, formatRelativeTime(cached.fetchedAt))
+	for _, check := range cached.checks {
+		conclusion := check.Conclusion
+		if conclusion == "" {
+			conclusion = "pending"
+		}
+		fmt.Fprintf(&body, "- **%s**: %s\n", check.Name, conclusion)
+	}
+	return body.String()
+}
+
+// compactAge phrases how long ago t was in a few characters.
+func compactAge(t time.Time) string {
+	d := time.Since(t)
+	switch {
+	case d < time.Minute:
+		return "now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+}
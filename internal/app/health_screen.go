@@ -0,0 +1,232 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/chmouel/lazyworktree/internal/theme"
+	"github.com/muesli/reflow/truncate"
+)
+
+type healthState int
+
+const (
+	healthUnknown healthState = iota // never run
+	healthPass
+	healthFail
+	healthRunning
+	healthNone // not applicable, e.g. CI without a PR
+)
+
+// healthCell is one worktree's result for a matrix column.
+type healthCell struct {
+	state healthState
+	age   string // how long ago the result was recorded
+	stale bool   // recorded at an older commit than the worktree's HEAD
+}
+
+// healthRow is a worktree's line in the matrix.
+type healthRow struct {
+	path  string
+	name  string
+	cells []healthCell
+}
+
+const (
+	healthNameWidth = 28
+	healthCellWidth = 10
+)
+
+// HealthScreen shows, for each worktree, the latest result of every health
+// check and of remote CI, with a cell cursor for re-running them.
+type HealthScreen struct {
+	columns []string
+	rows    []healthRow
+	row     int
+	col     int
+	offset  int
+	width   int
+	height  int
+	thm     *theme.Theme
+}
+
+// NewHealthScreen builds the matrix.
+func NewHealthScreen(columns []string, rows []healthRow, maxWidth, maxHeight int, thm *theme.Theme) *HealthScreen {
+	s := &HealthScreen{columns: columns, rows: rows, thm: thm}
+	s.SetSize(maxWidth, maxHeight)
+	return s
+}
+
+// SetSize fits the matrix to the terminal.
+func (s *HealthScreen) SetSize(maxWidth, maxHeight int) {
+	s.width = 80
+	s.height = 24
+	if maxWidth > 0 {
+		s.width = minInt(maxWidth-4, maxInt(60, healthNameWidth+4+len(s.columns)*(healthCellWidth+1)))
+	}
+	if maxHeight > 0 {
+		s.height = maxInt(10, int(float64(maxHeight)*0.8))
+	}
+	s.clamp()
+}
+
+// SetRows replaces the rows, keeping the cursor on the same worktree.
+func (s *HealthScreen) SetRows(rows []healthRow) {
+	current := ""
+	if s.row < len(s.rows) {
+		current = s.rows[s.row].path
+	}
+	s.rows = rows
+	for i, row := range rows {
+		if row.path == current {
+			s.row = i
+			break
+		}
+	}
+	s.clamp()
+}
+
+// visibleRows is how many worktrees fit below the header.
+func (s *HealthScreen) visibleRows() int {
+	return maxInt(1, s.height-7)
+}
+
+func (s *HealthScreen) clamp() {
+	s.row = maxInt(0, minInt(s.row, len(s.rows)-1))
+	s.col = maxInt(0, minInt(s.col, len(s.columns)-1))
+	if s.row < s.offset {
+		s.offset = s.row
+	}
+	if s.row >= s.offset+s.visibleRows() {
+		s.offset = s.row - s.visibleRows() + 1
+	}
+}
+
+// Selected returns the worktree path and column under the cursor.
+func (s *HealthScreen) Selected() (path, column string, ok bool) {
+	if len(s.rows) == 0 || len(s.columns) == 0 {
+		return "", "", false
+	}
+	return s.rows[s.row].path, s.columns[s.col], true
+}
+
+// Paths returns every worktree in the matrix.
+func (s *HealthScreen) Paths() []string {
+	paths := make([]string, 0, len(s.rows))
+	for _, row := range s.rows {
+		paths = append(paths, row.path)
+	}
+	return paths
+}
+
+// Update moves the cursor.
+func (s *HealthScreen) Update(msg tea.Msg) (*HealthScreen, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return s, nil
+	}
+	switch keyMsg.String() {
+	case "j", keyDown:
+		s.row++
+	case "k", keyUp:
+		s.row--
+	case "l", "right", keyTab:
+		s.col++
+	case "h", "left", keyShiftTab:
+		s.col--
+	case "g", "home":
+		s.row = 0
+	case "G", "end":
+		s.row = len(s.rows) - 1
+	}
+	s.clamp()
+	return s, nil
+}
+
+// cellText renders a cell's symbol and age, padded to the cell width.
+func (s *HealthScreen) cellText(cell healthCell) (string, lipgloss.Style) {
+	style := lipgloss.NewStyle()
+	var text string
+	switch cell.state {
+	case healthPass:
+		text = "✓ " + cell.age
+		style = style.Foreground(s.thm.SuccessFg)
+	case healthFail:
+		text = "✗ " + cell.age
+		style = style.Foreground(s.thm.ErrorFg)
+	case healthRunning:
+		text = symbolFilledCircle + " running"
+		style = style.Foreground(s.thm.WarnFg)
+	case healthNone:
+		text = "–"
+		style = style.Foreground(s.thm.MutedFg)
+	default:
+		text = "·"
+		style = style.Foreground(s.thm.MutedFg)
+	}
+	if cell.stale {
+		text += "*"
+		style = style.Faint(true)
+	}
+	return fmt.Sprintf("%-*s", healthCellWidth, truncate.String(text, healthCellWidth)), style
+}
+
+// View renders the matrix.
+func (s *HealthScreen) View() string {
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(s.thm.Accent).
+		Width(s.width).
+		Padding(0)
+	titleStyle := lipgloss.NewStyle().
+		Foreground(s.thm.Accent).
+		Bold(true).
+		Border(lipgloss.NormalBorder(), false, false, true, false).
+		BorderForeground(s.thm.BorderDim).
+		Width(s.width-2).
+		Padding(0, 1)
+	headerStyle := lipgloss.NewStyle().Foreground(s.thm.MutedFg).Bold(true)
+	nameStyle := lipgloss.NewStyle().Foreground(s.thm.TextFg)
+	cursorStyle := lipgloss.NewStyle().Background(s.thm.Accent).Foreground(s.thm.AccentFg).Bold(true)
+
+	nameWidth := minInt(healthNameWidth, maxInt(8, s.width-6-len(s.columns)*(healthCellWidth+1)))
+	header := make([]string, 0, len(s.columns)+1)
+	header = append(header, fmt.Sprintf("%-*s", nameWidth, "Worktree"))
+	for _, column := range s.columns {
+		header = append(header, fmt.Sprintf("%-*s", healthCellWidth, truncate.String(column, healthCellWidth)))
+	}
+	lines := []string{headerStyle.Render(strings.Join(header, " "))}
+
+	if len(s.rows) == 0 {
+		lines = append(lines, lipgloss.NewStyle().Foreground(s.thm.MutedFg).Italic(true).Render("No worktrees."))
+	}
+	end := minInt(len(s.rows), s.offset+s.visibleRows())
+	for i := s.offset; i < end; i++ {
+		row := s.rows[i]
+		name := fmt.Sprintf("%-*s", nameWidth, truncate.StringWithTail(row.name, uint(nameWidth), "…"))
+		parts := []string{nameStyle.Bold(i == s.row).Render(name)}
+		for j, cell := range row.cells {
+			text, style := s.cellText(cell)
+			if i == s.row && j == s.col {
+				style = cursorStyle
+			}
+			parts = append(parts, style.Render(text))
+		}
+		lines = append(lines, strings.Join(parts, " "))
+	}
+
+	body := lipgloss.NewStyle().
+		Padding(0, 1).
+		Width(s.width - 2).
+		Render(strings.Join(lines, "\n"))
+	hints := []string{"h/j/k/l: move", "enter: run", "r: run row", "c: run column", "a: run all", "o: output", "*: older commit", "esc: close"}
+	footer := lipgloss.NewStyle().
+		Foreground(s.thm.MutedFg).
+		Width(s.width-2).
+		Padding(1, 1, 0, 1).
+		Render(strings.Join(hints, " • "))
+
+	return boxStyle.Render(lipgloss.JoinVertical(lipgloss.Left, titleStyle.Render("Branch health"), body, footer))
+}
@@ -0,0 +1,138 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/chmouel/lazyworktree/internal/config"
+	"github.com/chmouel/lazyworktree/internal/models"
+	"github.com/chmouel/lazyworktree/internal/theme"
+)
+
+func newHealthModel(t *testing.T) (*Model, string) {
+	t.Helper()
+	wtPath := t.TempDir()
+	cfg := &config.AppConfig{
+		WorktreeDir: t.TempDir(),
+		HealthChecks: []*config.HealthCheck{
+			{Name: "lint", Command: "true"},
+			{Name: "test", Command: "echo boom >&2; exit 1"},
+		},
+	}
+	m := NewModel(cfg, "")
+	m.repoKey = "health-test"
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	m.worktrees = []*models.WorktreeInfo{{Path: wtPath, Branch: "feature", Head: "abc1234"}}
+	m.filteredWts = m.worktrees
+	return m, wtPath
+}
+
+func TestHealthMatrixRunsChecks(t *testing.T) {
+	m, wtPath := newHealthModel(t)
+	_ = m.showHealthMatrix()
+	if m.currentScreen != screenHealth {
+		t.Fatalf("expected the matrix, got %s", screenName(m.currentScreen))
+	}
+	if got := strings.Join(m.healthScreen.columns, ","); got != "lint,test,CI" {
+		t.Fatalf("unexpected columns %q", got)
+	}
+
+	cmd := m.handleHealthKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+	if cmd == nil {
+		t.Fatal("expected the row to run")
+	}
+	if cell := m.healthScreen.rows[0].cells[0]; cell.state != healthRunning {
+		t.Fatalf("expected lint to be running, got %v", cell.state)
+	}
+	if again := m.runHealthCheck(m.worktrees[0], m.config.HealthChecks[0]); again != nil {
+		t.Fatal("expected a running check not to start twice")
+	}
+	for _, check := range m.config.HealthChecks {
+		delete(m.healthRunning, healthKey(wtPath, check.Name))
+		msg, ok := m.runHealthCheck(m.worktrees[0], check)().(healthCheckDoneMsg)
+		if !ok {
+			t.Fatal("expected a health result")
+		}
+		m.handleHealthCheckDone(msg)
+	}
+
+	cells := m.healthScreen.rows[0].cells
+	if cells[0].state != healthPass || cells[1].state != healthFail || cells[2].state != healthNone {
+		t.Fatalf("unexpected cells %+v", cells)
+	}
+	m.healthScreen, _ = m.healthScreen.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("l")})
+	_ = m.handleHealthKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("o")})
+	if m.currentScreen != screenMarkdown || !strings.Contains(m.markdownScreen.source, "boom") {
+		t.Fatalf("expected the failure output, got %s", screenName(m.currentScreen))
+	}
+	if m.markdownScreen.returnTo != screenHealth {
+		t.Fatal("expected the output to return to the matrix")
+	}
+
+	if _, err := os.Stat(m.healthStatePath()); err != nil {
+		t.Fatalf("expected the results to be stored: %v", err)
+	}
+	m.healthResults = nil
+	m.worktrees[0].Head = "def5678"
+	m.loadHealthResults()
+	if cell := m.healthCell(m.worktrees[0], "lint"); cell.state != healthPass || !cell.stale {
+		t.Fatalf("expected a stored result from an older commit, got %+v", cell)
+	}
+}
+
+func TestHealthMatrixCIColumn(t *testing.T) {
+	m, _ := newHealthModel(t)
+	m.worktrees[0].PR = &models.PRInfo{Number: 7}
+	m.ciCache["feature"] = &ciCacheEntry{
+		checks:    []*models.CICheck{{Name: "build", Conclusion: "success"}, {Name: "e2e", Conclusion: "pending"}},
+		fetchedAt: time.Now(),
+	}
+	if cell := m.healthCICell(m.worktrees[0]); cell.state != healthRunning {
+		t.Fatalf("expected pending CI, got %+v", cell)
+	}
+	m.ciCache["feature"].checks[1].Conclusion = "failure"
+	if cell := m.healthCICell(m.worktrees[0]); cell.state != healthFail {
+		t.Fatalf("expected failing CI, got %+v", cell)
+	}
+	if cmd := m.showHealthMatrix(); cmd != nil {
+		t.Fatal("expected fresh CI results not to be fetched again")
+	}
+}
+
+func TestHealthMatrixWithoutChecks(t *testing.T) {
+	m := NewModel(&config.AppConfig{WorktreeDir: t.TempDir()}, "")
+	if cmd := m.showHealthMatrix(); cmd != nil || m.currentScreen != screenInfo {
+		t.Fatal("expected the matrix to explain how to configure checks")
+	}
+}
+
+func TestHealthScreenNavigation(t *testing.T) {
+	rows := []healthRow{
+		{path: "/a", name: "a", cells: []healthCell{{state: healthPass, age: "2m"}, {state: healthNone}}},
+		{path: "/b", name: "b", cells: []healthCell{{state: healthFail, age: "1h", stale: true}, {state: healthUnknown}}},
+	}
+	s := NewHealthScreen([]string{"test", "CI"}, rows, 120, 40, theme.Dracula())
+	s, _ = s.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	s, _ = s.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("l")})
+	s, _ = s.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("l")})
+	if path, column, ok := s.Selected(); !ok || path != "/b" || column != "CI" {
+		t.Fatalf("unexpected selection %q %q", path, column)
+	}
+	s.SetRows([]healthRow{rows[1]})
+	if path, _, _ := s.Selected(); path != "/b" {
+		t.Fatalf("expected the cursor to follow the worktree, got %q", path)
+	}
+	view := s.View()
+	for _, want := range []string{"Branch health", "test", "✗ 1h*"} {
+		if !strings.Contains(view, want) {
+			t.Fatalf("expected %q in the view", want)
+		}
+	}
+	if got := filepath.Base(strings.Join(s.Paths(), "")); got != "b" {
+		t.Fatalf("unexpected paths %q", got)
+	}
+}
@@ -11,52 +11,102 @@ import (
 	"time"
 )
 
+// defaultBranchNameScriptTimeout bounds branch_name_script unless
+// branch_name_script_timeout says otherwise.
+const defaultBranchNameScriptTimeout = 30 * time.Second
+
+// branchNameScriptRun describes a run of the configured branch_name_script.
+type branchNameScriptRun struct {
+	script        string
+	content       string // Given on stdin
+	scriptType    string // "pr", "issue", "diff" or "description"
+	number        string
+	template      string
+	suggestedName string
+	timeout       time.Duration
+	onOutput      func(string) // Called with the output so far as it arrives
+}
+
 // runBranchNameScript executes the configured branch_name_script with the content as stdin.
 // It returns the generated branch name or an error.
 // The scriptType indicates the context: "pr", "issue", or "diff".
 // For PRs and issues, number, template, and suggestedName provide additional context.
 func runBranchNameScript(ctx context.Context, script, content, scriptType, number, template, suggestedName string) (string, error) {
-	if script == "" {
+	return branchNameScriptRun{
+		script:        script,
+		content:       content,
+		scriptType:    scriptType,
+		number:        number,
+		template:      template,
+		suggestedName: suggestedName,
+	}.run(ctx)
+}
+
+// run executes the script and returns the first line of its output.
+func (r branchNameScriptRun) run(ctx context.Context) (string, error) {
+	if r.script == "" {
 		return "", nil
 	}
 
 	// Create a context with timeout to prevent hanging
-	const scriptTimeout = 30 * time.Second
-	ctx, cancel := context.WithTimeout(ctx, scriptTimeout)
+	timeout := r.timeout
+	if timeout <= 0 {
+		timeout = defaultBranchNameScriptTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
 	// #nosec G204 -- script is user-configured and trusted
-	cmd := exec.CommandContext(ctx, "bash", "-c", script)
-	cmd.Stdin = strings.NewReader(content)
+	cmd := exec.CommandContext(ctx, "bash", "-c", r.script)
+	cmd.Stdin = strings.NewReader(r.content)
+	// Children the script left behind must not hold its output open.
+	cmd.WaitDelay = time.Second
 
 	// Set environment variables to provide context to the script
 	cmd.Env = append(os.Environ(),
-		fmt.Sprintf("LAZYWORKTREE_TYPE=%s", scriptType),
-		fmt.Sprintf("LAZYWORKTREE_NUMBER=%s", number),
-		fmt.Sprintf("LAZYWORKTREE_TEMPLATE=%s", template),
-		fmt.Sprintf("LAZYWORKTREE_SUGGESTED_NAME=%s", suggestedName),
+		fmt.Sprintf("LAZYWORKTREE_TYPE=%s", r.scriptType),
+		fmt.Sprintf("LAZYWORKTREE_NUMBER=%s", r.number),
+		fmt.Sprintf("LAZYWORKTREE_TEMPLATE=%s", r.template),
+		fmt.Sprintf("LAZYWORKTREE_SUGGESTED_NAME=%s", r.suggestedName),
 	)
 
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
+	if r.onOutput != nil {
+		cmd.Stdout = &outputWatcher{buf: &stdout, notify: r.onOutput}
+	}
 	cmd.Stderr = &stderr
 
 	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return "", fmt.Errorf("branch name script timed out after %s", timeout)
+		}
 		return "", fmt.Errorf("branch name script failed: %w (stderr: %s)", err, stderr.String())
 	}
 
-	// Trim whitespace and get first line only
-	output := strings.TrimSpace(stdout.String())
-	if output == "" {
-		return "", nil
-	}
+	return firstOutputLine(stdout.String()), nil
+}
 
-	// Get only the first line
+// firstOutputLine returns the first line of output, trimmed.
+func firstOutputLine(output string) string {
+	// Trim whitespace and get first line only
+	output = strings.TrimSpace(output)
 	if idx := strings.IndexAny(output, "\n\r"); idx >= 0 {
 		output = output[:idx]
 	}
+	return strings.TrimSpace(output)
+}
+
+// outputWatcher collects a command's output, reporting it as it grows.
+type outputWatcher struct {
+	buf    *bytes.Buffer
+	notify func(string)
+}
 
-	return strings.TrimSpace(output), nil
+func (w *outputWatcher) Write(p []byte) (int, error) {
+	w.buf.Write(p)
+	w.notify(w.buf.String())
+	return len(p), nil
 }
 
 type scoredPaletteItem struct {
@@ -208,6 +258,11 @@ func (m *Model) validateNewWorktreeTarget(branch, targetPath string) string {
 }
 
 func (m *Model) ensureWorktreeDir(dir string) error {
+	// Over SSH the directory is on the host, where git worktree add
+	// creates it.
+	if m.git.RemoteHost() != "" {
+		return nil
+	}
 	if err := os.MkdirAll(dir, defaultDirPerms); err != nil {
 		return fmt.Errorf("failed to create worktree directory: %w", err)
 	}
@@ -0,0 +1,165 @@
+package app
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/chmouel/lazyworktree/internal/models"
+	"github.com/chmouel/lazyworktree/internal/utils"
+)
+
+// diskSizeTTL is how long a measured worktree size is reused before walking
+// the worktree again.
+const diskSizeTTL = 5 * time.Minute
+
+// ticketPattern matches issue tracker keys such as "PROJ-123".
+var ticketPattern = regexp.MustCompile(`[A-Z][A-Z0-9]+-[0-9]+`)
+
+// infoExtras holds worktree details that are only gathered when an
+// info_template asks for them.
+type infoExtras struct {
+	notes      string
+	diskSize   int64
+	diskSizeAt time.Time
+}
+
+// infoTemplateData is the value an info_template is executed against.
+type infoTemplateData struct {
+	Path         string
+	Branch       string
+	Upstream     string
+	Divergence   string
+	Ahead        int
+	Behind       int
+	Dirty        bool
+	IsMain       bool
+	Detached     bool
+	Locked       bool
+	LockReason   string
+	Prunable     bool
+	LastAccessed string
+	PR           *models.PRInfo
+	CI           []*models.CICheck
+	Labels       []string
+	Notes        string
+	DiskSize     string
+	Ticket       string
+}
+
+// infoTemplateSource returns the info_template from .wt, falling back to the
+// user configuration.
+func (m *Model) infoTemplateSource() string {
+	if m.repoConfig != nil && m.repoConfig.InfoTemplate != "" {
+		return m.repoConfig.InfoTemplate
+	}
+	return m.config.InfoTemplate
+}
+
+// loadInfoTemplate parses the configured info_template so renders only
+// execute it. It runs whenever the user or .wt configuration is loaded.
+func (m *Model) loadInfoTemplate() {
+	m.infoTemplate, m.infoTemplateErr = nil, nil
+	source := m.infoTemplateSource()
+	if source == "" {
+		return
+	}
+	m.infoTemplate, m.infoTemplateErr = template.New("info").Funcs(m.infoTemplateFuncs()).Parse(source)
+}
+
+// loadInfoExtras gathers the notes and disk size of a worktree when the
+// template references them. Disk sizes are reused from cached, a snapshot
+// taken on the Update side, for diskSizeTTL. It runs inside a tea.Cmd, so it
+// must not touch the model's maps.
+func (m *Model) loadInfoExtras(wt *models.WorktreeInfo, source string, cached infoExtras) *infoExtras {
+	extras := &infoExtras{}
+	if strings.Contains(source, ".Notes") {
+		extras.notes = m.git.BranchDescription(m.ctx, wt.Branch, wt.Path)
+	}
+	if strings.Contains(source, ".DiskSize") {
+		if !cached.diskSizeAt.IsZero() && time.Since(cached.diskSizeAt) < diskSizeTTL {
+			extras.diskSize, extras.diskSizeAt = cached.diskSize, cached.diskSizeAt
+		} else if size, err := utils.DirSize(wt.Path); err == nil {
+			extras.diskSize, extras.diskSizeAt = size, time.Now()
+		}
+	}
+	return extras
+}
+
+// infoTemplateData collects the fields available to an info_template.
+func (m *Model) infoTemplateData(wt *models.WorktreeInfo) infoTemplateData {
+	data := infoTemplateData{
+		Path:       wt.Path,
+		Branch:     wt.Branch,
+		Upstream:   wt.UpstreamBranch,
+		Divergence: wt.Divergence,
+		Ahead:      wt.Ahead,
+		Behind:     wt.Behind,
+		Dirty:      wt.Dirty,
+		IsMain:     wt.IsMain,
+		Detached:   wt.Detached,
+		Locked:     wt.Locked,
+		LockReason: wt.LockReason,
+		Prunable:   wt.Prunable,
+		PR:         wt.PR,
+		Ticket:     ticketPattern.FindString(wt.Branch),
+	}
+	if wt.LastSwitchedTS > 0 {
+		data.LastAccessed = formatRelativeTime(time.Unix(wt.LastSwitchedTS, 0))
+	}
+	if wt.PR != nil {
+		data.Labels = wt.PR.Labels
+		data.CI = wt.PR.Checks
+		if data.Ticket == "" {
+			data.Ticket = ticketPattern.FindString(wt.PR.Title)
+		}
+	}
+	if cached, ok := m.ciCache[wt.Branch]; ok && len(cached.checks) > 0 {
+		data.CI = cached.checks
+	}
+	if extras := m.infoExtras[wt.Path]; extras != nil {
+		data.Notes = extras.notes
+		if !extras.diskSizeAt.IsZero() {
+			data.DiskSize = utils.FormatBytes(extras.diskSize)
+		}
+	}
+	return data
+}
+
+// infoTemplateFuncs returns the theme-aware helpers available to templates.
+// Styles are built on each call so a parsed template follows theme changes.
+func (m *Model) infoTemplateFuncs() template.FuncMap {
+	style := func(s func() lipgloss.Style) func(any) string {
+		return func(v any) string { return s().Render(fmt.Sprint(v)) }
+	}
+	return template.FuncMap{
+		"label":   style(func() lipgloss.Style { return lipgloss.NewStyle().Foreground(m.theme.Cyan).Bold(true) }),
+		"muted":   style(func() lipgloss.Style { return lipgloss.NewStyle().Foreground(m.theme.MutedFg) }),
+		"link":    style(func() lipgloss.Style { return lipgloss.NewStyle().Foreground(m.theme.Cyan).Underline(true) }),
+		"success": style(func() lipgloss.Style { return lipgloss.NewStyle().Foreground(m.theme.SuccessFg) }),
+		"warn":    style(func() lipgloss.Style { return lipgloss.NewStyle().Foreground(m.theme.WarnFg) }),
+		"danger":  style(func() lipgloss.Style { return lipgloss.NewStyle().Foreground(m.theme.ErrorFg) }),
+		"join":    strings.Join,
+		"upper":   strings.ToUpper,
+		"lower":   strings.ToLower,
+		"review":  m.formatReviewDecision,
+	}
+}
+
+// renderInfoTemplate renders the info pane from a user template, reporting
+// template errors in the pane itself.
+func (m *Model) renderInfoTemplate(wt *models.WorktreeInfo) string {
+	errorStyle := lipgloss.NewStyle().Foreground(m.theme.ErrorFg)
+	if m.infoTemplateErr != nil {
+		return errorStyle.Render(fmt.Sprintf("Invalid info_template: %v", m.infoTemplateErr))
+	}
+	var buf bytes.Buffer
+	if err := m.infoTemplate.Execute(&buf, m.infoTemplateData(wt)); err != nil {
+		return errorStyle.Render(fmt.Sprintf("Failed to render info_template: %v", err))
+	}
+	return strings.TrimRight(buf.String(), "\n")
+}
@@ -0,0 +1,77 @@
+package app
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chmouel/lazyworktree/internal/config"
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+func TestRenderInfoTemplate(t *testing.T) {
+	cfg := &config.AppConfig{
+		WorktreeDir:  t.TempDir(),
+		InfoTemplate: "{{.Branch}} ({{.Ticket}}){{if .PR}}\nPR #{{.PR.Number}} [{{join .Labels \", \"}}]{{end}}{{with .Notes}}\nNotes: {{.}}{{end}}",
+	}
+	m := NewModel(cfg, "")
+
+	wt := &models.WorktreeInfo{Path: "/wt/feature", Branch: "feature/PROJ-42-cache"}
+	if got := m.buildInfoContent(wt); got != "feature/PROJ-42-cache (PROJ-42)" {
+		t.Fatalf("unexpected content without PR: %q", got)
+	}
+
+	wt.PR = &models.PRInfo{Number: 7, Title: "Cache", Labels: []string{"perf", "backend"}}
+	m.infoExtras = map[string]*infoExtras{"/wt/feature": {notes: "Try the LRU"}}
+	want := "feature/PROJ-42-cache (PROJ-42)\nPR #7 [perf, backend]\nNotes: Try the LRU"
+	if got := m.buildInfoContent(wt); got != want {
+		t.Fatalf("buildInfoContent() = %q, want %q", got, want)
+	}
+}
+
+func TestRenderInfoTemplateRepoOverrideAndErrors(t *testing.T) {
+	cfg := &config.AppConfig{WorktreeDir: t.TempDir(), InfoTemplate: "global"}
+	m := NewModel(cfg, "")
+	wt := &models.WorktreeInfo{Path: "/wt/main", Branch: "main"}
+
+	m.repoConfig = &config.RepoConfig{InfoTemplate: "repo {{.Branch}}"}
+	m.loadInfoTemplate()
+	if got := m.buildInfoContent(wt); got != "repo main" {
+		t.Fatalf("expected .wt template to win, got %q", got)
+	}
+
+	m.repoConfig.InfoTemplate = "{{.Branch"
+	m.loadInfoTemplate()
+	if got := m.buildInfoContent(wt); !strings.Contains(got, "Invalid info_template") {
+		t.Fatalf("expected parse error, got %q", got)
+	}
+
+	m.repoConfig.InfoTemplate = "{{.Missing}}"
+	m.loadInfoTemplate()
+	if got := m.buildInfoContent(wt); !strings.Contains(got, "Failed to render info_template") {
+		t.Fatalf("expected execution error, got %q", got)
+	}
+}
+
+func TestLoadInfoExtrasCachesDiskSize(t *testing.T) {
+	m := NewModel(&config.AppConfig{WorktreeDir: t.TempDir()}, "")
+	wt := &models.WorktreeInfo{Path: t.TempDir(), Branch: "feature"}
+
+	extras := m.loadInfoExtras(wt, "{{.Branch}}", infoExtras{})
+	if !extras.diskSizeAt.IsZero() {
+		t.Fatal("expected disk size to be skipped when the template does not use it")
+	}
+
+	measuredAt := time.Now().Add(-time.Minute)
+	cached := infoExtras{diskSize: 2048, diskSizeAt: measuredAt}
+	extras = m.loadInfoExtras(wt, "{{.DiskSize}}", cached)
+	if extras.diskSize != 2048 || !extras.diskSizeAt.Equal(measuredAt) {
+		t.Fatalf("expected cached disk size, got %+v", extras)
+	}
+
+	cached.diskSizeAt = time.Now().Add(-2 * diskSizeTTL)
+	extras = m.loadInfoExtras(wt, "{{.DiskSize}}", cached)
+	if extras.diskSize != 0 || !extras.diskSizeAt.After(measuredAt) {
+		t.Fatalf("expected a fresh measurement of the empty worktree, got %+v", extras)
+	}
+}
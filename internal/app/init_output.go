@@ -0,0 +1,242 @@
+package app
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// maxInitOutputBytes caps the init command output kept per worktree; the
+// oldest lines go first.
+const maxInitOutputBytes = 256 * 1024
+
+// initOutput collects the output of a worktree's init commands, or of
+// other commands run for it such as bootstrap ones, as they run.
+type initOutput struct {
+	title   string // What runs, such as "Init commands"
+	mu      sync.Mutex
+	data    []byte
+	started bool
+	running bool
+	err     error
+	shown   bool // the worktree has been listed and selected
+	notify  func()
+}
+
+// Write appends to the output and wakes the UI.
+func (o *initOutput) Write(p []byte) (int, error) {
+	o.mu.Lock()
+	o.data = append(o.data, p...)
+	if over := len(o.data) - maxInitOutputBytes; over > 0 {
+		if nl := bytes.IndexByte(o.data[over:], '\n'); nl >= 0 {
+			over += nl + 1
+		}
+		o.data = append([]byte(nil), o.data[min(over, len(o.data)):]...)
+	}
+	o.mu.Unlock()
+	o.notify()
+	return len(p), nil
+}
+
+// finish records how the init commands ended.
+func (o *initOutput) finish(err error) {
+	o.mu.Lock()
+	o.running, o.err = false, err
+	o.mu.Unlock()
+	o.notify()
+}
+
+// snapshot returns the output so far, whether the commands still run and
+// how they ended.
+func (o *initOutput) snapshot() (string, bool, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return string(o.data), o.running, o.err
+}
+
+// initOutputMsg signals new init command output.
+type initOutputMsg struct{}
+
+// runInitCommands records who created the new worktree at path and runs
+// its init commands, streaming their output into its status pane. direnv
+// and mise are approved and build artifacts synced first, so the commands
+// can rely on them.
+func (m *Model) runInitCommands(path string, env map[string]string, after func() tea.Msg) tea.Cmd {
+	m.recordOwner(path)
+	cmds := m.collectInitCommands()
+	if len(cmds) > 0 {
+		m.initOutputsMu.Lock()
+		m.initOutputs[path] = &initOutput{title: "Init commands", notify: m.signalInitOutput}
+		m.initOutputsMu.Unlock()
+	}
+	steps := make([]tea.Cmd, 0, 4)
+	for _, cmd := range []tea.Cmd{m.allowEnvTools(path), m.syncArtifacts(path), m.runCommandsWithTrust(cmds, path, env, after), m.offerBootstrap(path)} {
+		if cmd != nil {
+			steps = append(steps, cmd)
+		}
+	}
+	if len(steps) == 1 {
+		return steps[0]
+	}
+	return tea.Sequence(steps...)
+}
+
+// initOutputFor returns the output registered for the worktree at path.
+func (m *Model) initOutputFor(path string) *initOutput {
+	m.initOutputsMu.Lock()
+	defer m.initOutputsMu.Unlock()
+	return m.initOutputs[path]
+}
+
+// startInitOutput marks the init commands of cwd as running, returning
+// where their output goes, or nil when cwd streams none.
+func (m *Model) startInitOutput(cwd string) *initOutput {
+	out := m.initOutputFor(cwd)
+	if out == nil {
+		return nil
+	}
+	out.mu.Lock()
+	if out.started {
+		// Already run: these are other commands, such as terminate ones.
+		out.mu.Unlock()
+		return nil
+	}
+	out.started, out.running = true, true
+	out.mu.Unlock()
+	out.notify()
+	return out
+}
+
+// signalInitOutput wakes waitForInitOutput without blocking.
+func (m *Model) signalInitOutput() {
+	select {
+	case m.initOutputEvents <- struct{}{}:
+	default:
+	}
+}
+
+// waitForInitOutput waits for init command output to show.
+func (m *Model) waitForInitOutput() tea.Cmd {
+	if m.initOutputWaiting {
+		return nil
+	}
+	m.initOutputWaiting = true
+	return func() tea.Msg {
+		<-m.initOutputEvents
+		return initOutputMsg{}
+	}
+}
+
+// handleInitOutput brings the worktree whose init commands are running
+// into view: the list is reloaded once to show and select it, in place of
+// the loading screen.
+func (m *Model) handleInitOutput() tea.Cmd {
+	m.initOutputWaiting = false
+	cmds := []tea.Cmd{m.waitForInitOutput()}
+
+	m.initOutputsMu.Lock()
+	var reveal string
+	for path, out := range m.initOutputs {
+		out.mu.Lock()
+		if out.started && !out.shown {
+			out.shown = true
+			reveal = path
+		}
+		out.mu.Unlock()
+	}
+	m.initOutputsMu.Unlock()
+
+	if reveal != "" {
+		if m.currentScreen == screenLoading {
+			m.loading = false
+			m.currentScreen = screenNone
+			m.loadingScreen = nil
+		}
+		m.pendingSelectWorktreePath = reveal
+		cmds = append(cmds, func() tea.Msg {
+			worktrees, err := m.git.GetWorktrees(m.ctx)
+			return worktreesLoadedMsg{worktrees: worktrees, err: err}
+		})
+	}
+	if m.showingInitOutput() {
+		atBottom := m.statusViewport.AtBottom()
+		m.setStatusViewportContent(m.statusPaneContent(m.statusViewport.Width))
+		if atBottom {
+			m.statusViewport.GotoBottom()
+		}
+	}
+	return tea.Batch(cmds...)
+}
+
+// selectedInitOutput returns the init command output of the selected
+// worktree, once its commands have started.
+func (m *Model) selectedInitOutput() *initOutput {
+	wt := m.selectedWorktree()
+	if wt == nil {
+		return nil
+	}
+	out := m.initOutputFor(wt.Path)
+	if out == nil {
+		return nil
+	}
+	out.mu.Lock()
+	defer out.mu.Unlock()
+	if !out.started {
+		return nil
+	}
+	return out
+}
+
+// showingInitOutput reports whether the status pane shows init command
+// output rather than the changed files.
+func (m *Model) showingInitOutput() bool {
+	return !m.previewMode && m.selectedInitOutput() != nil
+}
+
+// dropInitOutputs forgets the finished init command output of worktrees
+// other than the one at keep, once the user has moved on.
+func (m *Model) dropInitOutputs(keep string) {
+	m.initOutputsMu.Lock()
+	defer m.initOutputsMu.Unlock()
+	for path, out := range m.initOutputs {
+		out.mu.Lock()
+		done := out.shown && !out.running
+		out.mu.Unlock()
+		if path != keep && done {
+			delete(m.initOutputs, path)
+		}
+	}
+}
+
+// renderInitOutput shows the command output under a line saying whether
+// they are still running; colours are passed through.
+func (m *Model) renderInitOutput(out *initOutput, path string) string {
+	data, running, err := out.snapshot()
+	muted := lipgloss.NewStyle().Foreground(m.theme.MutedFg)
+	header := muted.Render(fmt.Sprintf("Running %s in %s...", strings.ToLower(out.title), filepath.Base(path)))
+	if !running {
+		header = lipgloss.NewStyle().Foreground(m.theme.SuccessFg).Render(out.title + " finished.")
+		if err != nil {
+			header = lipgloss.NewStyle().Foreground(m.theme.ErrorFg).Render(fmt.Sprintf("%s failed: %v", out.title, firstLine(err.Error())))
+		}
+	}
+	lines := strings.Split(strings.TrimRight(strings.ReplaceAll(data, "\r\n", "\n"), "\n"), "\n")
+	for i, line := range lines {
+		// Progress bars redraw their line after a carriage return.
+		if cr := strings.LastIndexByte(line, '\r'); cr >= 0 {
+			lines[i] = line[cr+1:]
+		}
+	}
+	return header + "\n\n" + strings.Join(lines, "\n")
+}
+
+// firstLine returns s up to its first newline.
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
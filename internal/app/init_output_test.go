@@ -0,0 +1,79 @@
+package app
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/chmouel/lazyworktree/internal/config"
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+func TestInitOutputStreamsIntoStatusPane(t *testing.T) {
+	cfg := &config.AppConfig{
+		WorktreeDir:  t.TempDir(),
+		TrustMode:    "always",
+		InitCommands: []string{`printf 'one\n'; printf '\033[32mtwo\033[0m\n'`, `printf 'step 1\rstep 2\n'`},
+	}
+	m := NewModel(cfg, "")
+	m.setWindowSize(120, 40)
+	path := t.TempDir()
+	m.currentScreen = screenLoading
+
+	after := func() tea.Msg { return worktreesLoadedMsg{} }
+	if msg := m.runInitCommands(path, nil, after)(); msg == nil {
+		t.Fatal("expected the after message once the commands ran")
+	}
+
+	if cmd := m.handleInitOutput(); cmd == nil {
+		t.Fatal("expected the worktrees to reload")
+	}
+	if m.currentScreen != screenNone || m.pendingSelectWorktreePath != path {
+		t.Fatalf("expected the loading screen to give way to the worktree, got %s and %q", screenName(m.currentScreen), m.pendingSelectWorktreePath)
+	}
+
+	m.worktrees = []*models.WorktreeInfo{{Path: path, Branch: "feature"}, {Path: filepath.Join(path, "other"), Branch: "other"}}
+	m.filteredWts = m.worktrees
+	if got := m.statusPaneTitle(); got != "Init commands" {
+		t.Fatalf("expected the init commands title, got %q", got)
+	}
+	content := m.statusPaneContent(80)
+	for _, want := range []string{"Init commands finished.", "one\n", "\x1b[32mtwo\x1b[0m", "step 2"} {
+		if !strings.Contains(content, want) {
+			t.Fatalf("expected %q in the status pane, got %q", want, content)
+		}
+	}
+	if strings.Contains(content, "\nstep 1") {
+		t.Fatalf("expected the carriage return to redraw the line, got %q", content)
+	}
+
+	m.dropInitOutputs(path)
+	if m.initOutputFor(path) == nil {
+		t.Fatal("expected the output to stay while its worktree is selected")
+	}
+	m.dropInitOutputs(m.worktrees[1].Path)
+	if m.initOutputFor(path) != nil || m.statusPaneTitle() != "Status" {
+		t.Fatal("expected the output to go once another worktree is selected")
+	}
+}
+
+func TestInitOutputReportsFailure(t *testing.T) {
+	cfg := &config.AppConfig{WorktreeDir: t.TempDir(), TrustMode: "always", InitCommands: []string{"echo broken; exit 2", "echo never"}}
+	m := NewModel(cfg, "")
+	path := t.TempDir()
+	_ = m.runInitCommands(path, nil, func() tea.Msg { return nil })()
+
+	m.worktrees = []*models.WorktreeInfo{{Path: path, Branch: "feature"}}
+	m.filteredWts = m.worktrees
+	content := m.statusPaneContent(80)
+	if !strings.Contains(content, "Init commands failed: echo broken; exit 2") || strings.Contains(content, "never") {
+		t.Fatalf("expected the failure and no later command, got %q", content)
+	}
+
+	// Commands run later in the same worktree, such as terminate ones, do
+	// not stream into it.
+	if m.startInitOutput(path) != nil {
+		t.Fatal("expected the init output to be used once")
+	}
+}
@@ -0,0 +1,88 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/chmouel/lazyworktree/internal/models"
+	"github.com/chmouel/lazyworktree/internal/utils"
+)
+
+// instancesDir holds one file per running lazyworktree instance of the repo,
+// named after its process ID.
+func (m *Model) instancesDir() string {
+	return filepath.Join(m.getRepoCacheDir(), models.InstancesDirname)
+}
+
+// registerInstance records this process as open on the repository.
+func (m *Model) registerInstance() {
+	dir := m.instancesDir()
+	if err := os.MkdirAll(dir, defaultDirPerms); err != nil {
+		m.debugf("failed to create instances dir: %v", err)
+		return
+	}
+	path := filepath.Join(dir, strconv.Itoa(os.Getpid()))
+	if err := os.WriteFile(path, nil, defaultFilePerms); err != nil {
+		m.debugf("failed to register instance: %v", err)
+		return
+	}
+	m.instanceFile = path
+}
+
+// unregisterInstance removes this process's instance file.
+func (m *Model) unregisterInstance() {
+	if m.instanceFile == "" {
+		return
+	}
+	if err := os.Remove(m.instanceFile); err != nil && !os.IsNotExist(err) {
+		m.debugf("failed to unregister instance: %v", err)
+	}
+	m.instanceFile = ""
+}
+
+// otherInstances returns the process IDs of other running instances open on
+// the repository, clearing files left behind by instances that have exited.
+func (m *Model) otherInstances() []int {
+	dir := m.instancesDir()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	self := os.Getpid()
+	var pids []int
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil || pid == self {
+			continue
+		}
+		if !utils.ProcessAlive(pid) {
+			_ = os.Remove(filepath.Join(dir, entry.Name()))
+			continue
+		}
+		pids = append(pids, pid)
+	}
+	sort.Ints(pids)
+	return pids
+}
+
+// otherInstanceWarning returns a warning to append to destructive
+// confirmations when another instance is open on the repository.
+func (m *Model) otherInstanceWarning() string {
+	pids := m.otherInstances()
+	if len(pids) == 0 {
+		return ""
+	}
+	ids := make([]string, 0, len(pids))
+	for _, pid := range pids {
+		ids = append(ids, strconv.Itoa(pid))
+	}
+	noun := "instance"
+	if len(pids) > 1 {
+		noun = "instances"
+	}
+	return fmt.Sprintf("\n\nWarning: another lazyworktree %s (PID %s) is open on this repository and may be changing these worktrees too.", noun, strings.Join(ids, ", "))
+}
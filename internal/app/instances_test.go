@@ -0,0 +1,63 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/chmouel/lazyworktree/internal/config"
+)
+
+func TestRegisterAndUnregisterInstance(t *testing.T) {
+	m := NewModel(&config.AppConfig{WorktreeDir: t.TempDir()}, "")
+	m.repoKey = testRepoKey
+
+	m.registerInstance()
+	path := filepath.Join(m.instancesDir(), strconv.Itoa(os.Getpid()))
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("expected instance file to exist: %v", err)
+	}
+	if pids := m.otherInstances(); len(pids) != 0 {
+		t.Fatalf("expected own instance to be ignored, got %v", pids)
+	}
+
+	m.unregisterInstance()
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("expected instance file to be removed, got %v", err)
+	}
+}
+
+func TestOtherInstanceWarning(t *testing.T) {
+	m := NewModel(&config.AppConfig{WorktreeDir: t.TempDir()}, "")
+	m.repoKey = testRepoKey
+	if warning := m.otherInstanceWarning(); warning != "" {
+		t.Fatalf("expected no warning without other instances, got %q", warning)
+	}
+
+	if err := os.MkdirAll(m.instancesDir(), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	// The parent process is alive for the duration of the test.
+	alive := filepath.Join(m.instancesDir(), strconv.Itoa(os.Getppid()))
+	// A PID this large is never in use, so its file is stale.
+	stale := filepath.Join(m.instancesDir(), "2147483646")
+	// PID 0 names the process group, never another instance.
+	zero := filepath.Join(m.instancesDir(), "0")
+	for _, path := range []string{alive, stale, zero} {
+		if err := os.WriteFile(path, nil, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	warning := m.otherInstanceWarning()
+	if !strings.Contains(warning, strconv.Itoa(os.Getppid())) {
+		t.Fatalf("expected warning to name the other instance, got %q", warning)
+	}
+	for _, path := range []string{stale, zero} {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Fatalf("expected stale instance file %s to be cleared", filepath.Base(path))
+		}
+	}
+}
@@ -164,8 +164,11 @@ func TestSortCycle(t *testing.T) {
 
 	time.Sleep(100 * time.Millisecond)
 
-	// Press 's' three times to cycle through all modes and back to original
-	// switched (2) -> path (0) -> active (1) -> switched (2)
+	// Press 's' four times to cycle through all modes and back to original
+	// switched (2) -> manual (3) -> path (0) -> active (1) -> switched (2)
+	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
+	time.Sleep(50 * time.Millisecond)
+
 	tm.Send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("s")})
 	time.Sleep(50 * time.Millisecond)
 
@@ -186,9 +189,9 @@ func TestSortCycle(t *testing.T) {
 		t.Fatal("Final model is not *Model type")
 	}
 
-	// Should be back to original state after three cycles
+	// Should be back to original state after four cycles
 	if m.sortMode != sortModeLastSwitched {
-		t.Errorf("Expected sortMode to be %d after three cycles, got %d", sortModeLastSwitched, m.sortMode)
+		t.Errorf("Expected sortMode to be %d after four cycles, got %d", sortModeLastSwitched, m.sortMode)
 	}
 }
 
@@ -0,0 +1,199 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/chmouel/lazyworktree/internal/theme"
+	"github.com/muesli/reflow/truncate"
+)
+
+// whichKeyLeader opens the overlay listing the keys of the focused pane.
+const whichKeyLeader = ","
+
+// Panes a key binding applies to.
+const (
+	keyPaneWorktrees = 1 << iota
+	keyPaneFiles
+	keyPaneCommits
+	keyPaneAll = keyPaneWorktrees | keyPaneFiles | keyPaneCommits
+)
+
+// keyBinding is a key of the main view and what it does in the panes it
+// applies to.
+type keyBinding struct {
+	key   string
+	desc  string
+	panes int
+}
+
+// keyBindings is the registry of main view keys, in the order the which-key
+// overlay lists them. A key doing different things in different panes has
+// an entry per meaning.
+var keyBindings = []keyBinding{
+	{"Enter", "Jump to worktree", keyPaneWorktrees},
+	{"Enter", "Show diff / toggle folder", keyPaneFiles},
+	{"Enter", "View commit files", keyPaneCommits},
+	{"c", "Create worktree", keyPaneWorktrees},
+	{"c", "Commit staged changes", keyPaneFiles},
+	{"C", "Commit all changes", keyPaneFiles},
+	{"C", "Cherry-pick", keyPaneWorktrees | keyPaneCommits},
+	{"m", "Rename worktree", keyPaneWorktrees},
+	{"D", "Delete worktree", keyPaneWorktrees},
+	{"D", "Delete file", keyPaneFiles},
+	{"s", "Stage / unstage", keyPaneFiles},
+	{"e", "Edit file", keyPaneFiles},
+	{"d", "Show diff", keyPaneWorktrees | keyPaneFiles},
+	{"d", "Show commit diff", keyPaneCommits},
+	{"W", "Fix staged whitespace", keyPaneWorktrees | keyPaneFiles},
+	{"A", "Absorb into main", keyPaneWorktrees},
+	{"X", "Prune merged", keyPaneWorktrees},
+	{"M", "Sync my PRs", keyPaneWorktrees},
+	{"P", "Push", keyPaneWorktrees},
+	{"S", "Sync with upstream", keyPaneWorktrees},
+	{"u", "Pull", keyPaneWorktrees},
+	{"U", "Force push with lease", keyPaneWorktrees},
+	{"F", "Fetch this branch", keyPaneWorktrees},
+	{"R", "Fetch all remotes", keyPaneWorktrees},
+	{"p", "Fetch PR data", keyPaneWorktrees},
+	{"o", "Open PR", keyPaneWorktrees},
+	{"i", "PR description", keyPaneWorktrees},
+	{"O", "Open deployment", keyPaneWorktrees},
+	{"g", "LazyGit", keyPaneAll},
+	{"!", "Run command", keyPaneAll},
+	{macroRecordKey, "Record macro", keyPaneAll},
+	{"v", "Toggle preview", keyPaneWorktrees},
+	{"s", "Cycle sort", keyPaneWorktrees},
+	{"{  }", "Previous / next sort column", keyPaneWorktrees},
+	{"b", "Pin / unpin worktree", keyPaneWorktrees},
+	{"K  J", "Move worktree up / down", keyPaneWorktrees},
+	{"T", "Relative / absolute dates", keyPaneWorktrees | keyPaneCommits},
+	{"<  >", "Back / forward", keyPaneWorktrees},
+	{"f", "Filter", keyPaneAll},
+	{"x", "Clear worktree filter", keyPaneAll},
+	{"/", "Search", keyPaneAll},
+	{"1-3", "Focus pane", keyPaneAll},
+	{"H  L", "First / last visible worktree", keyPaneWorktrees},
+	{"G", "Go to bottom", keyPaneAll},
+	{"Tab", "Next pane", keyPaneAll},
+	{"=", "Zoom pane", keyPaneAll},
+	{"r", "Refresh", keyPaneAll},
+	{":", "Command palette", keyPaneAll},
+	{"?", "Full help", keyPaneAll},
+	{"E", "Show errors", keyPaneAll},
+	{"q", "Quit", keyPaneAll},
+}
+
+// paneKeyMask maps a focused pane index to its key binding mask.
+func paneKeyMask(pane int) int {
+	switch pane {
+	case 1:
+		return keyPaneFiles
+	case 2:
+		return keyPaneCommits
+	default:
+		return keyPaneWorktrees
+	}
+}
+
+// paneKeyBindings lists the keys of the focused pane, followed by the
+// macros and custom commands, which take precedence over built-in keys.
+func (m *Model) paneKeyBindings() []keyBinding {
+	mask := paneKeyMask(m.focusedPane)
+	custom := make(map[string]bool, len(m.config.CustomCommands)+len(m.config.Macros))
+	for _, key := range m.customCommandKeys() {
+		custom[key] = true
+	}
+	for key := range m.config.Macros {
+		custom[key] = true
+	}
+	var bindings []keyBinding
+	for _, b := range keyBindings {
+		if b.panes&mask != 0 && !custom[b.key] {
+			bindings = append(bindings, b)
+		}
+	}
+	for _, item := range m.macroPaletteItems() {
+		if key, ok := strings.CutPrefix(item.id, "macro:"); ok {
+			bindings = append(bindings, keyBinding{key: key, desc: m.config.Macros[key].Name, panes: keyPaneAll})
+		}
+	}
+	for _, key := range m.customCommandKeys() {
+		if _, ok := m.config.Macros[key]; ok {
+			continue
+		}
+		// The key has its own column, so drop it from the label.
+		desc := strings.TrimSuffix(m.customCommandLabel(m.config.CustomCommands[key], key), " ("+key+")")
+		bindings = append(bindings, keyBinding{key: key, desc: desc, panes: keyPaneAll})
+	}
+	return bindings
+}
+
+// showWhichKey pops up the keys of the focused pane.
+func (m *Model) showWhichKey() {
+	titles := []string{"Worktrees", "Files", "Commits"}
+	title := titles[0]
+	if m.focusedPane >= 0 && m.focusedPane < len(titles) {
+		title = titles[m.focusedPane]
+	}
+	m.whichKeyScreen = NewWhichKeyScreen(title, m.paneKeyBindings(), m.windowWidth, m.theme)
+	m.currentScreen = screenWhichKey
+}
+
+// WhichKeyScreen is a transient overlay listing the keys available in the
+// focused pane; the next key closes it and runs as usual.
+type WhichKeyScreen struct {
+	title    string
+	bindings []keyBinding
+	width    int
+	thm      *theme.Theme
+}
+
+// NewWhichKeyScreen builds the overlay for a terminal maxWidth wide.
+func NewWhichKeyScreen(title string, bindings []keyBinding, maxWidth int, thm *theme.Theme) *WhichKeyScreen {
+	width := 80
+	if maxWidth > 0 {
+		width = maxInt(40, maxWidth-4)
+	}
+	return &WhichKeyScreen{title: title, bindings: bindings, width: width, thm: thm}
+}
+
+// View lays the keys out in as many columns as fit.
+func (s *WhichKeyScreen) View() string {
+	keyWidth, descWidth := 1, 1
+	for _, b := range s.bindings {
+		keyWidth = maxInt(keyWidth, lipgloss.Width(b.key))
+		descWidth = maxInt(descWidth, lipgloss.Width(b.desc))
+	}
+	descWidth = minInt(descWidth, 28)
+	cellWidth := keyWidth + 1 + descWidth + 3
+	inner := s.width - 4
+	columns := maxInt(1, inner/cellWidth)
+	rows := (len(s.bindings) + columns - 1) / columns
+
+	keyStyle := lipgloss.NewStyle().Foreground(s.thm.Accent).Bold(true).Width(keyWidth).Align(lipgloss.Right)
+	descStyle := lipgloss.NewStyle().Foreground(s.thm.TextFg).Width(descWidth + 3)
+	lines := make([]string, 0, rows+2)
+	lines = append(lines, lipgloss.NewStyle().Foreground(s.thm.Accent).Bold(true).Render(s.title+" keys"))
+	for row := range rows {
+		var line strings.Builder
+		for col := range columns {
+			i := col*rows + row
+			if i >= len(s.bindings) {
+				break
+			}
+			b := s.bindings[i]
+			fmt.Fprintf(&line, "%s %s", keyStyle.Render(b.key), descStyle.Render(truncate.StringWithTail(b.desc, uint(descWidth), "…")))
+		}
+		lines = append(lines, line.String())
+	}
+	lines = append(lines, lipgloss.NewStyle().Foreground(s.thm.MutedFg).Render("Press a key to run it, Esc to close, ? for the full help"))
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(s.thm.Accent).
+		Padding(0, 1).
+		Width(inner).
+		Render(strings.Join(lines, "\n"))
+}
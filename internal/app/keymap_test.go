@@ -0,0 +1,86 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/chmouel/lazyworktree/internal/config"
+)
+
+func TestPaneKeyBindings(t *testing.T) {
+	cfg := &config.AppConfig{
+		WorktreeDir: t.TempDir(),
+		CustomCommands: map[string]*config.CustomCommand{
+			"e": {Command: "nvim", Description: "Editor"},
+		},
+	}
+	m := NewModel(cfg, "")
+
+	descs := func() map[string]string {
+		got := map[string]string{}
+		for _, b := range m.paneKeyBindings() {
+			got[b.key] = b.desc
+		}
+		return got
+	}
+
+	m.focusedPane = 0
+	got := descs()
+	if got["c"] != "Create worktree" || got["D"] != "Delete worktree" {
+		t.Fatalf("expected worktree keys, got %v", got)
+	}
+	if got["e"] != "Editor" {
+		t.Fatalf("expected the custom command to be listed, got %q", got["e"])
+	}
+
+	m.focusedPane = 1
+	got = descs()
+	if got["c"] != "Commit staged changes" || got["s"] != "Stage / unstage" {
+		t.Fatalf("expected file keys, got %v", got)
+	}
+	for _, b := range m.paneKeyBindings() {
+		if b.key == "e" && b.desc == "Edit file" {
+			t.Fatal("expected the custom command to hide the built-in key it overrides")
+		}
+	}
+
+	m.focusedPane = 2
+	if got = descs(); got["d"] != "Show commit diff" || got["m"] != "" {
+		t.Fatalf("expected commit keys only, got %v", got)
+	}
+}
+
+func TestWhichKeyOverlay(t *testing.T) {
+	cfg := &config.AppConfig{WorktreeDir: t.TempDir()}
+	m := NewModel(cfg, "")
+	m.windowWidth = 120
+	m.windowHeight = 40
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{','}})
+	if m.currentScreen != screenWhichKey || m.whichKeyScreen == nil {
+		t.Fatalf("expected the which-key overlay, got %s", screenName(m.currentScreen))
+	}
+	view := m.whichKeyScreen.View()
+	if !strings.Contains(view, "Worktrees keys") || !strings.Contains(view, "Create worktree") {
+		t.Fatalf("expected the worktree keys in the overlay, got %q", view)
+	}
+	if !strings.Contains(m.View(), "Create worktree") {
+		t.Fatal("expected the overlay to be drawn over the main view")
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.currentScreen != screenNone || m.whichKeyScreen != nil {
+		t.Fatalf("expected Esc to close the overlay, got %s", screenName(m.currentScreen))
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{','}})
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'3'}})
+	if m.currentScreen != screenNone || m.focusedPane != 2 {
+		t.Fatalf("expected the key to close the overlay and run, got %s on pane %d", screenName(m.currentScreen), m.focusedPane)
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{','}})
+	if !strings.Contains(m.whichKeyScreen.View(), "Commits keys") {
+		t.Fatal("expected the overlay to follow the focused pane")
+	}
+}
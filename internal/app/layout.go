@@ -1,6 +1,9 @@
 package app
 
 import (
+	"slices"
+	"time"
+
 	"github.com/charmbracelet/bubbles/table"
 )
 
@@ -30,6 +33,7 @@ func (m *Model) setWindowSize(width, height int) {
 	m.windowWidth = width
 	m.windowHeight = height
 	m.applyLayout(m.computeLayout())
+	m.resizeScreens()
 }
 
 // computeLayout calculates the layout dimensions based on window size and UI state.
@@ -90,6 +94,10 @@ func (m *Model) computeLayout() layoutDims {
 	case 1, 2:
 		leftRatio = 0.20
 	}
+	// Focus mode lists few worktrees, so the details get more room.
+	if m.focusBranch != "" && m.focusedPane == 0 {
+		leftRatio = 0.35
+	}
 
 	leftWidth := int(float64(width-gapX) * leftRatio)
 	rightWidth := width - leftWidth - gapX
@@ -166,14 +174,24 @@ func (m *Model) applyLayout(layout layoutDims) {
 
 	// Subtract 2 extra lines for safety margin
 	// Minimum height of 3 is required to prevent viewport slice bounds panic
+	// View applies the layout on every frame; resizing a table re-renders all
+	// of its visible rows, so only do it when the dimensions moved.
+	resize := !m.renders.layoutSet || m.renders.layout != layout
+	m.renders.layout = layout
+	m.renders.layoutSet = true
+
 	tableHeight := maxInt(3, layout.leftInnerHeight-titleHeight-tableHeaderHeight-2)
-	m.worktreeTable.SetWidth(layout.leftInnerWidth)
-	m.worktreeTable.SetHeight(tableHeight)
+	if resize {
+		m.worktreeTable.SetWidth(layout.leftInnerWidth)
+		m.worktreeTable.SetHeight(tableHeight)
+	}
 	m.updateTableColumns(layout.leftInnerWidth)
 
 	logHeight := maxInt(3, layout.rightBottomInnerHeight-titleHeight-tableHeaderHeight-2)
-	m.logTable.SetWidth(layout.rightInnerWidth)
-	m.logTable.SetHeight(logHeight)
+	if resize {
+		m.logTable.SetWidth(layout.rightInnerWidth)
+		m.logTable.SetHeight(logHeight)
+	}
 	m.updateLogColumns(layout.rightInnerWidth)
 
 	m.filterInput.Width = maxInt(20, layout.width-18)
@@ -190,6 +208,22 @@ func (m *Model) updateTableColumns(totalWidth int) {
 	if m.prDataLoaded {
 		pr = 12
 	}
+	deploy := 0
+	if m.showDeploymentColumn() {
+		deploy = 12
+	}
+	svc := 0
+	if m.showServicesColumn() {
+		svc = 9
+	}
+	owner := 0
+	if m.showOwnerColumn() {
+		owner = 10
+	}
+	lines := 0
+	if m.showDiffStatsColumn() {
+		lines = 18
+	}
 
 	// The table library handles separators internally (3 spaces per separator)
 	// So we need to account for them: (numColumns - 1) * 3
@@ -197,10 +231,22 @@ func (m *Model) updateTableColumns(totalWidth int) {
 	if m.prDataLoaded {
 		numColumns = 5
 	}
+	if m.showDeploymentColumn() {
+		numColumns++
+	}
+	if m.showServicesColumn() {
+		numColumns++
+	}
+	if m.showOwnerColumn() {
+		numColumns++
+	}
+	if m.showDiffStatsColumn() {
+		numColumns++
+	}
 	separatorSpace := (numColumns - 1) * 3
 
-	worktree := maxInt(12, totalWidth-status-ab-last-pr-separatorSpace)
-	excess := worktree + status + ab + pr + last + separatorSpace - totalWidth
+	worktree := maxInt(12, totalWidth-status-ab-last-pr-deploy-svc-owner-lines-separatorSpace)
+	excess := worktree + status + ab + pr + deploy + svc + owner + lines + last + separatorSpace - totalWidth
 	for excess > 0 && last > 10 {
 		last--
 		excess--
@@ -211,6 +257,22 @@ func (m *Model) updateTableColumns(totalWidth int) {
 			excess--
 		}
 	}
+	for excess > 0 && deploy > 4 {
+		deploy--
+		excess--
+	}
+	for excess > 0 && svc > 3 {
+		svc--
+		excess--
+	}
+	for excess > 0 && owner > 5 {
+		owner--
+		excess--
+	}
+	for excess > 0 && lines > 9 {
+		lines--
+		excess--
+	}
 	for excess > 0 && worktree > 12 {
 		worktree--
 		excess--
@@ -228,7 +290,7 @@ func (m *Model) updateTableColumns(totalWidth int) {
 	}
 
 	// Final adjustment: ensure column widths + separators sum exactly to totalWidth
-	actualTotal := worktree + status + ab + last + pr + separatorSpace
+	actualTotal := worktree + status + ab + last + pr + deploy + svc + owner + lines + separatorSpace
 	if actualTotal < totalWidth {
 		// Distribute remaining space to the worktree column
 		worktree += (totalWidth - actualTotal)
@@ -238,41 +300,73 @@ func (m *Model) updateTableColumns(totalWidth int) {
 	}
 
 	columns := []table.Column{
-		{Title: "Name", Width: worktree},
+		{Title: m.nameColumnTitle(), Width: worktree},
 		{Title: "Changes", Width: status},
 		{Title: "Status", Width: ab},
-		{Title: "Last Active", Width: last},
+		{Title: m.lastActiveColumnTitle(), Width: last},
 	}
 
 	if m.prDataLoaded {
 		columns = append(columns, table.Column{Title: "PR", Width: pr})
 	}
+	if m.showDeploymentColumn() {
+		columns = append(columns, table.Column{Title: "Deploy", Width: deploy})
+	}
+	if m.showServicesColumn() {
+		columns = append(columns, table.Column{Title: "Svc", Width: svc})
+	}
+	if m.showOwnerColumn() {
+		columns = append(columns, table.Column{Title: "Owner", Width: owner})
+	}
+	if m.showDiffStatsColumn() {
+		columns = append(columns, table.Column{Title: "±Lines", Width: lines})
+	}
 
-	m.worktreeTable.SetColumns(columns)
+	setTableColumns(&m.worktreeTable, columns)
 }
 
 // updateLogColumns updates the log table column widths based on available space.
 func (m *Model) updateLogColumns(totalWidth int) {
 	sha := 8
 	author := 2
+	date := 0
+	if m.absoluteDates {
+		date = len(time.Now().Format(m.dateLayout()))
+	}
 
 	// The table library handles separators internally (3 spaces per separator)
-	// 3 columns = 2 separators = 6 spaces
+	// 3 columns = 2 separators = 6 spaces, and 9 with the Date column
 	separatorSpace := 6
+	if m.absoluteDates {
+		separatorSpace = 9
+	}
 
-	message := maxInt(10, totalWidth-sha-author-separatorSpace)
+	message := maxInt(10, totalWidth-sha-date-author-separatorSpace)
 
 	// Final adjustment: ensure column widths + separator space sum exactly to totalWidth
-	actualTotal := sha + author + message + separatorSpace
+	actualTotal := sha + date + author + message + separatorSpace
 	if actualTotal < totalWidth {
 		message += (totalWidth - actualTotal)
 	} else if actualTotal > totalWidth {
 		message = maxInt(10, message-(actualTotal-totalWidth))
 	}
 
-	m.logTable.SetColumns([]table.Column{
-		{Title: "SHA", Width: sha},
-		{Title: "Au", Width: author},
-		{Title: "Message", Width: message},
-	})
+	columns := []table.Column{{Title: "SHA", Width: sha}}
+	if m.absoluteDates {
+		columns = append(columns, table.Column{Title: "Date", Width: date})
+	}
+	columns = append(columns,
+		table.Column{Title: "Au", Width: author},
+		table.Column{Title: "Message", Width: message},
+	)
+	setTableColumns(&m.logTable, columns)
+}
+
+// setTableColumns skips SetColumns when nothing changed, since it re-renders
+// every visible row.
+func setTableColumns(t *table.Model, columns []table.Column) {
+	if slices.Equal(t.Columns(), columns) {
+		return
+	}
+	t.SetColumns(columns)
 }
@@ -0,0 +1,235 @@
+package app
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/chmouel/lazyworktree/internal/config"
+)
+
+const (
+	// macroRecordKey starts and stops recording a macro.
+	macroRecordKey = "Q"
+	// macroTypePrefix marks a macro step typing text rather than pressing a
+	// key.
+	macroTypePrefix = "type:"
+	// macroStepInterval is how often a running macro checks whether the app
+	// is ready for its next key.
+	macroStepInterval = 100 * time.Millisecond
+)
+
+// macroStepMsg asks a running macro for its next key.
+type macroStepMsg struct{}
+
+// macroKeyTypes maps Bubble Tea's names for special keys back to their
+// types, so recorded keys can be replayed.
+var macroKeyTypes = func() map[string]tea.KeyType {
+	types := make(map[string]tea.KeyType)
+	for t := tea.KeyType(-256); t < 256; t++ {
+		if name := t.String(); name != "" && t != tea.KeyRunes {
+			types[name] = t
+		}
+	}
+	return types
+}()
+
+// macroKeyMsg turns a recorded step back into the key it stands for.
+func macroKeyMsg(step string) tea.KeyMsg {
+	if text, ok := strings.CutPrefix(step, macroTypePrefix); ok {
+		return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(text)}
+	}
+	alt := false
+	if rest, ok := strings.CutPrefix(step, "alt+"); ok && rest != "" {
+		alt, step = true, rest
+	}
+	if t, ok := macroKeyTypes[step]; ok {
+		return tea.KeyMsg{Type: t, Alt: alt}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(step), Alt: alt}
+}
+
+// macroTick schedules the next step of a running macro.
+func macroTick() tea.Cmd {
+	return tea.Tick(macroStepInterval, func(time.Time) tea.Msg { return macroStepMsg{} })
+}
+
+// onMainView reports whether keys go to the main view's shortcuts rather
+// than to a dialog or an input.
+func (m *Model) onMainView() bool {
+	return m.currentScreen == screenNone && !m.showingFilter && !m.showingSearch
+}
+
+// typingText reports whether keys are being typed into a text input, where
+// a run of them is recorded as one step.
+func (m *Model) typingText() bool {
+	return m.showingFilter || m.showingSearch || m.currentScreen == screenInput || m.currentScreen == screenPalette
+}
+
+// recordMacroKey adds a key about to be handled to the macro being
+// recorded. The keys controlling macros themselves are left out.
+func (m *Model) recordMacroKey(msg tea.KeyMsg) {
+	if !m.macroRecording {
+		return
+	}
+	key := msg.String()
+	if m.onMainView() {
+		if _, ok := m.config.Macros[key]; ok || key == macroRecordKey {
+			return
+		}
+		if key == ":" || key == "ctrl+p" {
+			// Stopping from the palette drops the keys that opened it.
+			m.macroPaletteAt = len(m.macroKeys)
+		}
+	}
+	if m.typingText() && msg.Type == tea.KeyRunes && !msg.Alt {
+		if n := len(m.macroKeys); n > 0 && m.macroTyping {
+			m.macroKeys[n-1] += string(msg.Runes)
+			return
+		}
+		m.macroKeys = append(m.macroKeys, macroTypePrefix+string(msg.Runes))
+		m.macroTyping = true
+		return
+	}
+	m.macroTyping = false
+	m.macroKeys = append(m.macroKeys, key)
+}
+
+// toggleMacroRecording starts recording, or stops and offers to save.
+func (m *Model) toggleMacroRecording(fromPalette bool) tea.Cmd {
+	if !m.macroRecording {
+		if len(m.macroQueue) > 0 {
+			return nil
+		}
+		m.macroRecording = true
+		m.macroTyping = false
+		m.macroKeys = nil
+		m.statusContent = fmt.Sprintf("Recording macro; press %s to stop", macroRecordKey)
+		return nil
+	}
+
+	keys := m.macroKeys
+	if fromPalette {
+		keys = keys[:min(m.macroPaletteAt, len(keys))]
+	}
+	m.macroRecording = false
+	m.macroKeys = nil
+	if len(keys) == 0 {
+		m.statusContent = "Nothing was recorded"
+		return nil
+	}
+	return m.showSaveMacro(keys)
+}
+
+// showSaveMacro asks for the macro's name, then for the key to bind it to,
+// and saves it to the configuration file.
+func (m *Model) showSaveMacro(keys []string) tea.Cmd {
+	m.inputScreen = NewInputScreen(fmt.Sprintf("Name the macro (%d steps)", len(keys)), "Pull, test and push", "", m.theme)
+	m.inputSubmit = func(value string, _ bool) (tea.Cmd, bool) {
+		name := strings.TrimSpace(value)
+		if name == "" {
+			m.inputScreen.errorMsg = "Name cannot be empty."
+			return nil, false
+		}
+		m.inputScreen = NewInputScreen(fmt.Sprintf("Bind %q to a key", name), "ctrl+t", "", m.theme)
+		m.inputSubmit = func(value string, _ bool) (tea.Cmd, bool) {
+			key := strings.TrimSpace(value)
+			if key == "" {
+				key = value // A space is a key too
+			}
+			switch {
+			case key == "":
+				m.inputScreen.errorMsg = "Key cannot be empty."
+				return nil, false
+			case key == macroRecordKey:
+				m.inputScreen.errorMsg = fmt.Sprintf("%s starts and stops recording.", macroRecordKey)
+				return nil, false
+			case m.config.CustomCommands[key] != nil:
+				m.inputScreen.errorMsg = fmt.Sprintf("%s already runs a custom command.", key)
+				return nil, false
+			}
+			macro := &config.Macro{Name: name, Keys: keys}
+			if err := config.SaveMacro(m.config, key, macro); err != nil {
+				m.inputScreen.errorMsg = fmt.Sprintf("Could not save the macro: %v", err)
+				return nil, false
+			}
+			m.statusContent = fmt.Sprintf("Saved macro %q on %s", name, key)
+			return nil, true
+		}
+		return textinput.Blink, false
+	}
+	m.currentScreen = screenInput
+	return textinput.Blink
+}
+
+// runMacro replays the macro bound to key against the current selection.
+func (m *Model) runMacro(key string) tea.Cmd {
+	macro := m.config.Macros[key]
+	if macro == nil {
+		return nil
+	}
+	if m.macroRecording {
+		m.statusContent = "Stop recording before running a macro"
+		return nil
+	}
+	if len(m.macroQueue) > 0 {
+		return nil
+	}
+	m.macroQueue = slices.Clone(macro.Keys)
+	m.macroRunning = macro.Name
+	m.statusContent = fmt.Sprintf("Running macro %q; press any key to stop", macro.Name)
+	return macroTick()
+}
+
+// handleMacroStep feeds a running macro's next key once the previous one
+// has settled, waiting out loading screens. Dialogs the keys open are
+// answered by the keys that follow, as when recording.
+func (m *Model) handleMacroStep() tea.Cmd {
+	if len(m.macroQueue) == 0 {
+		return nil
+	}
+	if m.loading || m.currentScreen == screenLoading {
+		return macroTick()
+	}
+	step := m.macroQueue[0]
+	m.macroQueue = m.macroQueue[1:]
+	m.debugf("macro %q: %s", m.macroRunning, step)
+	_, cmd := m.dispatchKey(macroKeyMsg(step))
+	if len(m.macroQueue) == 0 {
+		m.statusContent = fmt.Sprintf("Ran macro %q", m.macroRunning)
+		return cmd
+	}
+	return tea.Batch(cmd, macroTick())
+}
+
+// stopMacro abandons a running macro.
+func (m *Model) stopMacro() {
+	m.macroQueue = nil
+	m.statusContent = fmt.Sprintf("Stopped macro %q", m.macroRunning)
+}
+
+// macroPaletteItems lists the saved macros for the command palette.
+func (m *Model) macroPaletteItems() []paletteItem {
+	if len(m.config.Macros) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(m.config.Macros))
+	for key := range m.config.Macros {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	items := []paletteItem{{label: "Macros", isSection: true}}
+	for _, key := range keys {
+		macro := m.config.Macros[key]
+		items = append(items, paletteItem{
+			id:          "macro:" + key,
+			label:       fmt.Sprintf("%s (%s)", macro.Name, key),
+			description: strings.Join(macro.Keys, " "),
+		})
+	}
+	return items
+}
@@ -0,0 +1,93 @@
+package app
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/chmouel/lazyworktree/internal/config"
+)
+
+func TestMacroKeyMsgRoundTrip(t *testing.T) {
+	for _, key := range []string{"j", "Q", " ", "enter", "esc", "tab", "shift+tab", "up", "pgdown", "ctrl+p", "alt+n", "alt+enter"} {
+		if got := macroKeyMsg(key).String(); got != key {
+			t.Errorf("expected %q to replay as itself, got %q", key, got)
+		}
+	}
+	msg := macroKeyMsg("type:make test")
+	if msg.Type != tea.KeyRunes || string(msg.Runes) != "make test" {
+		t.Fatalf("expected typed text, got %+v", msg)
+	}
+}
+
+func TestRecordAndRunMacro(t *testing.T) {
+	cfg := &config.AppConfig{WorktreeDir: t.TempDir(), ConfigPath: filepath.Join(t.TempDir(), "config.yaml")}
+	m := NewModel(cfg, "")
+	press := func(keys ...string) {
+		for _, key := range keys {
+			m.Update(macroKeyMsg(key))
+		}
+	}
+
+	press("Q")
+	if !m.macroRecording {
+		t.Fatal("expected Q to start recording")
+	}
+	press("2", "f", "a", "b", "enter", "1", "Q")
+	if m.macroRecording || m.currentScreen != screenInput {
+		t.Fatalf("expected Q to stop recording and ask for a name, got %s", screenName(m.currentScreen))
+	}
+	press("type:Filter ab", "enter")
+	if m.currentScreen != screenInput || !strings.Contains(m.inputScreen.prompt, "Filter ab") {
+		t.Fatal("expected to be asked for a key")
+	}
+	press("type:Q", "enter")
+	if m.inputScreen == nil || !strings.Contains(m.inputScreen.errorMsg, "recording") {
+		t.Fatal("expected the record key to be refused")
+	}
+	m.inputScreen.input.SetValue("")
+	press("type:ctrl+t", "enter")
+	if m.currentScreen != screenNone {
+		t.Fatalf("expected the macro to be saved, got %s", screenName(m.currentScreen))
+	}
+	macro := cfg.Macros["ctrl+t"]
+	want := []string{"2", "f", "type:ab", "enter", "1"}
+	if macro == nil || macro.Name != "Filter ab" || !slices.Equal(macro.Keys, want) {
+		t.Fatalf("expected %v to be recorded, got %+v", want, macro)
+	}
+	loaded, err := config.LoadConfig(cfg.ConfigPath)
+	if err != nil || loaded.Macros["ctrl+t"] == nil {
+		t.Fatalf("expected the macro in the configuration file, got %v", err)
+	}
+
+	m.statusFilterQuery = ""
+	m.focusedPane = 0
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlT})
+	if cmd == nil || len(m.macroQueue) != len(want) {
+		t.Fatal("expected ctrl+t to run the macro")
+	}
+	m.loading = true
+	m.handleMacroStep()
+	if len(m.macroQueue) != len(want) {
+		t.Fatal("expected the macro to wait while loading")
+	}
+	m.loading = false
+	for len(m.macroQueue) > 0 {
+		m.handleMacroStep()
+	}
+	if m.statusFilterQuery != "ab" || m.focusedPane != 0 {
+		t.Fatalf("expected the macro to filter the files and come back, got %q on pane %d", m.statusFilterQuery, m.focusedPane)
+	}
+	if m.statusContent != `Ran macro "Filter ab"` {
+		t.Fatalf("unexpected status %q", m.statusContent)
+	}
+
+	m.runMacro("ctrl+t")
+	m.handleMacroStep()
+	press("esc")
+	if len(m.macroQueue) != 0 || !strings.Contains(m.statusContent, "Stopped") {
+		t.Fatal("expected a key pressed during a macro to stop it")
+	}
+}
@@ -0,0 +1,232 @@
+package app
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/chmouel/lazyworktree/internal/maintenance"
+	"github.com/chmouel/lazyworktree/internal/models"
+	"github.com/chmouel/lazyworktree/internal/utils"
+)
+
+// maintenanceCheckInterval is how often scheduled maintenance is checked
+// while lazyworktree stays open.
+const maintenanceCheckInterval = 15 * time.Minute
+
+// maintenanceAllID selects every task on the maintenance screen.
+const maintenanceAllID = "all"
+
+// maintenanceTickMsg triggers a check for due maintenance tasks.
+type maintenanceTickMsg struct{}
+
+// maintenanceDoneMsg reports the tasks run and how each went.
+type maintenanceDoneMsg struct {
+	tasks   []maintenance.Task
+	results map[maintenance.Task]maintenance.Run
+	// manual is set when the user asked for the run, so the results are
+	// shown rather than only noted in the status bar.
+	manual bool
+}
+
+// maintenanceSchedule returns the configured intervals. The configuration
+// is validated at startup, so an invalid schedule is ignored.
+func (m *Model) maintenanceSchedule() maintenance.Schedule {
+	schedule, err := m.config.MaintenanceSchedule()
+	if err != nil {
+		m.debugf("maintenance schedule: %v", err)
+		return nil
+	}
+	return schedule
+}
+
+// maintenanceStatePath is where the last run of each task is recorded.
+func (m *Model) maintenanceStatePath() string {
+	return filepath.Join(m.getRepoWorktreeDir(), models.MaintenanceFilename)
+}
+
+// startMaintenance runs the tasks already due and checks again
+// periodically. It does nothing when no task is scheduled.
+func (m *Model) startMaintenance() tea.Cmd {
+	if m.maintenanceStarted || len(m.maintenanceSchedule()) == 0 {
+		return nil
+	}
+	m.maintenanceStarted = true
+	return tea.Batch(m.runDueMaintenance(), m.maintenanceTick())
+}
+
+func (m *Model) maintenanceTick() tea.Cmd {
+	return tea.Tick(maintenanceCheckInterval, func(time.Time) tea.Msg {
+		return maintenanceTickMsg{}
+	})
+}
+
+// runDueMaintenance runs the scheduled tasks whose interval has elapsed,
+// leaving out those read-only mode forbids.
+func (m *Model) runDueMaintenance() tea.Cmd {
+	state := maintenance.LoadState(m.maintenanceStatePath())
+	var tasks []maintenance.Task
+	for _, task := range state.Due(m.maintenanceSchedule(), time.Now()) {
+		if task.ChangesRepository() && m.config.ReadOnly {
+			continue
+		}
+		tasks = append(tasks, task)
+	}
+	return m.runMaintenance(tasks, false)
+}
+
+// runMaintenance runs tasks one after another in the background.
+func (m *Model) runMaintenance(tasks []maintenance.Task, manual bool) tea.Cmd {
+	if len(tasks) == 0 || m.maintenanceRunning {
+		return nil
+	}
+	mainPath := m.getMainWorktreePath()
+	if mainPath == "" {
+		return nil
+	}
+	m.maintenanceRunning = true
+	runner := maintenance.NewRunner(mainPath, utils.CacheDir())
+	ctx := m.ctx
+	return func() tea.Msg {
+		runner.FetchArgs = m.git.FetchArgs(ctx)
+		results := make(map[maintenance.Task]maintenance.Run, len(tasks))
+		for _, task := range tasks {
+			results[task] = runner.Run(ctx, task)
+		}
+		return maintenanceDoneMsg{tasks: tasks, results: results, manual: manual}
+	}
+}
+
+// handleMaintenanceDone records the runs, reports them and reloads the
+// worktrees when a task may have changed them.
+func (m *Model) handleMaintenanceDone(msg maintenanceDoneMsg) tea.Cmd {
+	m.maintenanceRunning = false
+	path := m.maintenanceStatePath()
+	state := maintenance.LoadState(path)
+	var total int64
+	var failed []string
+	refresh := false
+	lines := make([]string, 0, len(msg.tasks))
+	for _, task := range msg.tasks {
+		run := msg.results[task]
+		state[task] = run
+		total += run.Reclaimed
+		if task == maintenance.Prune || task == maintenance.Fetch {
+			refresh = true
+		}
+		if run.Error != "" {
+			failed = append(failed, string(task))
+			lines = append(lines, fmt.Sprintf("%s: failed: %s", task, run.Error))
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("%s: done in %s, reclaimed %s", task, run.Duration, utils.FormatBytes(run.Reclaimed)))
+	}
+	if err := state.Save(path); err != nil {
+		m.debugf("failed to save maintenance state: %v", err)
+	}
+
+	if len(failed) > 0 {
+		m.statusContent = fmt.Sprintf("Maintenance failed: %s", strings.Join(failed, ", "))
+	} else {
+		m.statusContent = fmt.Sprintf("Maintenance complete, %s reclaimed", utils.FormatBytes(total))
+	}
+	if msg.manual {
+		m.loading = false
+		if m.currentScreen == screenLoading {
+			m.currentScreen = screenNone
+			m.loadingScreen = nil
+		}
+		m.showInfo("Maintenance\n\n"+strings.Join(lines, "\n"), nil)
+	}
+	if !refresh {
+		return nil
+	}
+	return m.refreshWorktrees()
+}
+
+// maintenanceItems lists each task with its schedule and last run.
+func (m *Model) maintenanceItems() []selectionItem {
+	schedule := m.maintenanceSchedule()
+	state := maintenance.LoadState(m.maintenanceStatePath())
+	due := make(map[maintenance.Task]bool)
+	for _, task := range state.Due(schedule, time.Now()) {
+		due[task] = true
+	}
+	items := make([]selectionItem, 0, len(maintenance.Tasks)+1)
+	items = append(items, selectionItem{
+		id:          maintenanceAllID,
+		label:       "Run all",
+		description: "Run every task now",
+	})
+	for _, task := range maintenance.Tasks {
+		parts := []string{task.Description()}
+		if interval, ok := schedule[task]; ok {
+			parts = append(parts, "every "+maintenance.FormatInterval(interval))
+		} else {
+			parts = append(parts, "on demand")
+		}
+		if run, ok := state[task]; ok {
+			last := "last run " + formatRelativeTime(run.At)
+			if run.Error != "" {
+				last += " (failed)"
+			} else if run.Reclaimed > 0 {
+				last += ", reclaimed " + utils.FormatBytes(run.Reclaimed)
+			}
+			parts = append(parts, last)
+		} else {
+			parts = append(parts, "never run")
+		}
+		if due[task] {
+			parts = append(parts, "due")
+		}
+		items = append(items, selectionItem{
+			id:          string(task),
+			label:       string(task),
+			description: strings.Join(parts, " · "),
+		})
+	}
+	return items
+}
+
+// showMaintenance lists the maintenance tasks and runs the chosen one.
+func (m *Model) showMaintenance() tea.Cmd {
+	if m.maintenanceRunning {
+		m.showInfo("Maintenance is already running.", nil)
+		return nil
+	}
+	m.listScreen = NewListSelectionScreen(m.maintenanceItems(), "Maintenance", "Filter tasks...", "No matching tasks.", m.windowWidth, m.windowHeight, "", m.theme)
+	m.listSubmit = func(item selectionItem) tea.Cmd {
+		m.listScreen = nil
+		m.listSubmit = nil
+		var tasks []maintenance.Task
+		var denied []string
+		for _, task := range maintenance.Tasks {
+			if item.id != maintenanceAllID && item.id != string(task) {
+				continue
+			}
+			if task.ChangesRepository() && m.config.ReadOnly {
+				denied = append(denied, string(task))
+				continue
+			}
+			tasks = append(tasks, task)
+		}
+		if len(tasks) == 0 {
+			m.showInfo(fmt.Sprintf("%s is disabled in read-only mode.\n\nRestart without --read-only to make changes.", strings.Join(denied, " and ")), nil)
+			return nil
+		}
+		cmd := m.runMaintenance(tasks, true)
+		if cmd == nil {
+			m.currentScreen = screenNone
+			return nil
+		}
+		m.loading = true
+		m.loadingScreen = NewLoadingScreen("Running maintenance...", m.theme)
+		m.currentScreen = screenLoading
+		return cmd
+	}
+	m.currentScreen = screenListSelect
+	return textinput.Blink
+}
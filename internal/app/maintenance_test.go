@@ -0,0 +1,85 @@
+package app
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/chmouel/lazyworktree/internal/config"
+	"github.com/chmouel/lazyworktree/internal/maintenance"
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+func TestMaintenanceScreenRunsTask(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	repo := t.TempDir()
+	runGit(t, repo, "init", "-b", "main")
+	runGit(t, repo, "config", "user.email", "test@example.com")
+	runGit(t, repo, "config", "user.name", "Test User")
+	runGit(t, repo, "config", "commit.gpgsign", "false")
+	runGit(t, repo, "commit", "--allow-empty", "-m", "Initial commit")
+
+	cfg := &config.AppConfig{
+		WorktreeDir:          t.TempDir(),
+		MaintenanceIntervals: map[string]string{"gc": "7d"},
+	}
+	m := NewModel(cfg, "")
+	m.repoKey = "example/maintenance"
+	m.worktrees = []*models.WorktreeInfo{{Path: repo, Branch: "main", IsMain: true}}
+	m.filteredWts = m.worktrees
+
+	_ = m.showMaintenance()
+	if m.currentScreen != screenListSelect || m.listScreen == nil {
+		t.Fatalf("expected the task list, got %s", screenName(m.currentScreen))
+	}
+	gc := findSelectionItem(m.listScreen.items, string(maintenance.GC))
+	if !strings.Contains(gc.description, "every 7d") || !strings.Contains(gc.description, "never run") || !strings.Contains(gc.description, "due") {
+		t.Fatalf("unexpected gc description %q", gc.description)
+	}
+
+	cmd := m.listSubmit(gc)
+	if cmd == nil || m.currentScreen != screenLoading || !m.maintenanceRunning {
+		t.Fatalf("expected gc to start, got %s", screenName(m.currentScreen))
+	}
+	done, ok := cmd().(maintenanceDoneMsg)
+	if !ok || done.results[maintenance.GC].Error != "" {
+		t.Fatalf("unexpected result %+v", done)
+	}
+	_ = m.handleMaintenanceDone(done)
+	if m.maintenanceRunning || m.currentScreen != screenInfo || !strings.Contains(m.infoScreen.message, "gc: done") {
+		t.Fatalf("expected the results to be shown, got %s", screenName(m.currentScreen))
+	}
+
+	state := maintenance.LoadState(filepath.Join(cfg.WorktreeDir, m.repoKey, models.MaintenanceFilename))
+	if _, ok := state[maintenance.GC]; !ok {
+		t.Fatalf("expected the gc run to be recorded, got %v", state)
+	}
+	if gc := findSelectionItem(m.maintenanceItems(), string(maintenance.GC)); !strings.Contains(gc.description, "last run") {
+		t.Fatalf("expected the last run to be listed, got %q", gc.description)
+	}
+}
+
+func TestMaintenanceReadOnly(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	cfg := &config.AppConfig{WorktreeDir: t.TempDir(), ReadOnly: true}
+	m := NewModel(cfg, "")
+	m.worktrees = []*models.WorktreeInfo{{Path: t.TempDir(), Branch: "main", IsMain: true}}
+
+	_ = m.showMaintenance()
+	_ = m.listSubmit(selectionItem{id: string(maintenance.Prune)})
+	if m.currentScreen != screenInfo || !strings.Contains(m.infoScreen.message, "read-only") {
+		t.Fatalf("expected prune to be refused, got %s", screenName(m.currentScreen))
+	}
+	if m.maintenanceRunning {
+		t.Fatal("expected nothing to run")
+	}
+}
+
+func findSelectionItem(items []selectionItem, id string) selectionItem {
+	for _, item := range items {
+		if item.id == id {
+			return item
+		}
+	}
+	return selectionItem{}
+}
@@ -0,0 +1,201 @@
+package app
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/chmouel/lazyworktree/internal/theme"
+	"github.com/muesli/reflow/wordwrap"
+	"github.com/muesli/reflow/wrap"
+)
+
+var (
+	mdHeadingRe   = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	mdListRe      = regexp.MustCompile(`^(\s*)([-*+]|\d+[.)])\s+(.*)$`)
+	mdTaskRe      = regexp.MustCompile(`^\[([ xX])\]\s+(.*)$`)
+	mdRuleRe      = regexp.MustCompile(`^\s*(?:(?:-\s*){3,}|(?:\*\s*){3,}|(?:_\s*){3,})$`)
+	mdImageRe     = regexp.MustCompile(`!\[([^\]]*)\]\(([^)\s]+)[^)]*\)`)
+	mdLinkRe      = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)[^)]*\)`)
+	mdBoldRe      = regexp.MustCompile(`\*\*([^*]+)\*\*|__([^_]+)__`)
+	mdItalicRe    = regexp.MustCompile(`(^|[^*\w])\*([^*\s][^*]*)\*|(^|[^_\w])_([^_\s][^_]*)_`)
+	mdHTMLTagRe   = regexp.MustCompile(`</?[a-zA-Z][^>]*>`)
+	mdHTMLCommRe  = regexp.MustCompile(`(?m)^[ \t]*<!--(?s:.*?)-->[ \t]*\n?|(?s)<!--.*?-->`)
+	mdStrikeRe    = regexp.MustCompile(`~~([^~]+)~~`)
+	mdFenceOpenRe = regexp.MustCompile("^\\s*(```|~~~)")
+	mdBareURLRe   = regexp.MustCompile(`https?://[^\s<>()\[\]"'` + "`" + `]+`)
+)
+
+// markdownStyles holds the theme styles used to render Markdown.
+type markdownStyles struct {
+	heading lipgloss.Style
+	title   lipgloss.Style
+	bold    lipgloss.Style
+	italic  lipgloss.Style
+	strike  lipgloss.Style
+	code    lipgloss.Style
+	link    lipgloss.Style
+	muted   lipgloss.Style
+	bullet  lipgloss.Style
+}
+
+func newMarkdownStyles(thm *theme.Theme) markdownStyles {
+	return markdownStyles{
+		heading: lipgloss.NewStyle().Foreground(thm.Accent).Bold(true),
+		title:   lipgloss.NewStyle().Foreground(thm.Accent).Bold(true).Underline(true),
+		bold:    lipgloss.NewStyle().Bold(true),
+		italic:  lipgloss.NewStyle().Italic(true),
+		strike:  lipgloss.NewStyle().Strikethrough(true),
+		code:    lipgloss.NewStyle().Foreground(thm.WarnFg),
+		link:    lipgloss.NewStyle().Foreground(thm.Cyan).Underline(true),
+		muted:   lipgloss.NewStyle().Foreground(thm.MutedFg),
+		bullet:  lipgloss.NewStyle().Foreground(thm.Accent),
+	}
+}
+
+// renderMarkdown renders a Markdown document for the terminal, styling
+// headings, emphasis, lists, quotes, code and links and wrapping prose to
+// width. It covers the subset commonly found in READMEs and PR bodies.
+func renderMarkdown(source string, width int, thm *theme.Theme) string {
+	styles := newMarkdownStyles(thm)
+	width = max(width, 10)
+	source = mdHTMLCommRe.ReplaceAllString(strings.ReplaceAll(source, "\r\n", "\n"), "")
+
+	var out []string
+	inFence := false
+	fence := ""
+	for line := range strings.SplitSeq(source, "\n") {
+		if match := mdFenceOpenRe.FindStringSubmatch(line); match != nil {
+			switch {
+			case !inFence:
+				inFence, fence = true, match[1]
+				continue
+			case match[1] == fence:
+				inFence = false
+				continue
+			}
+		}
+		if inFence {
+			out = append(out, "  "+styles.code.Render(strings.ReplaceAll(line, "\t", "    ")))
+			continue
+		}
+
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "":
+			out = append(out, "")
+		case mdRuleRe.MatchString(line):
+			out = append(out, styles.muted.Render(strings.Repeat("─", width)))
+		case mdHeadingRe.MatchString(trimmed):
+			match := mdHeadingRe.FindStringSubmatch(trimmed)
+			style := styles.heading
+			if len(match[1]) == 1 {
+				style = styles.title
+			}
+			out = append(out, wrapMarkdown(style.Render(stripMarkdownInline(match[2])), width, "", ""))
+		case strings.HasPrefix(trimmed, ">"):
+			text := strings.TrimSpace(strings.TrimLeft(trimmed, "> "))
+			bar := styles.muted.Render("│ ")
+			out = append(out, wrapMarkdown(styles.italic.Render(renderMarkdownInline(text, styles)), width, bar, bar))
+		case mdListRe.MatchString(line):
+			match := mdListRe.FindStringSubmatch(line)
+			indent := strings.Repeat(" ", len(strings.ReplaceAll(match[1], "\t", "  ")))
+			marker, text := match[2], match[3]
+			switch {
+			case mdTaskRe.MatchString(text):
+				task := mdTaskRe.FindStringSubmatch(text)
+				marker, text = "☐", task[2]
+				if task[1] != " " {
+					marker = "☑"
+				}
+			case !strings.ContainsAny(marker[:1], "0123456789"):
+				marker = "•"
+			}
+			prefix := indent + styles.bullet.Render(marker) + " "
+			hanging := indent + strings.Repeat(" ", lipgloss.Width(marker)+1)
+			out = append(out, wrapMarkdown(renderMarkdownInline(text, styles), width, prefix, hanging))
+		default:
+			out = append(out, wrapMarkdown(renderMarkdownInline(trimmed, styles), width, "", ""))
+		}
+	}
+	return strings.TrimRight(strings.Join(out, "\n"), "\n")
+}
+
+// renderMarkdownInline styles inline code, links, images and emphasis.
+// Code spans are left untouched by the other rules.
+func renderMarkdownInline(text string, styles markdownStyles) string {
+	parts := strings.Split(text, "`")
+	for i, part := range parts {
+		if i%2 == 1 && i < len(parts)-1 {
+			parts[i] = styles.code.Render(part)
+			continue
+		}
+		part = mdHTMLTagRe.ReplaceAllString(part, "")
+		part = mdImageRe.ReplaceAllStringFunc(part, func(s string) string {
+			alt := mdImageRe.FindStringSubmatch(s)[1]
+			if alt == "" {
+				alt = "image"
+			}
+			return styles.muted.Render("[" + alt + "]")
+		})
+		part = mdLinkRe.ReplaceAllStringFunc(part, func(s string) string {
+			return styles.link.Render(mdLinkRe.FindStringSubmatch(s)[1])
+		})
+		part = mdBoldRe.ReplaceAllStringFunc(part, func(s string) string {
+			match := mdBoldRe.FindStringSubmatch(s)
+			return styles.bold.Render(match[1] + match[2])
+		})
+		part = mdItalicRe.ReplaceAllStringFunc(part, func(s string) string {
+			match := mdItalicRe.FindStringSubmatch(s)
+			return match[1] + match[3] + styles.italic.Render(match[2]+match[4])
+		})
+		part = mdStrikeRe.ReplaceAllStringFunc(part, func(s string) string {
+			return styles.strike.Render(mdStrikeRe.FindStringSubmatch(s)[1])
+		})
+		if i%2 == 1 {
+			// Unbalanced trailing backtick: keep it literally.
+			part = "`" + part
+		}
+		parts[i] = part
+	}
+	return strings.Join(parts, "")
+}
+
+// stripMarkdownInline removes inline Markdown markers from a heading.
+func stripMarkdownInline(text string) string {
+	text = mdLinkRe.ReplaceAllString(text, "$1")
+	text = strings.NewReplacer("`", "", "**", "", "__", "").Replace(text)
+	return text
+}
+
+// wrapMarkdown wraps styled text to width, starting with prefix and
+// indenting continuation lines with hanging.
+func wrapMarkdown(text string, width int, prefix, hanging string) string {
+	avail := max(width-lipgloss.Width(prefix), 10)
+	wrapped := wrap.String(wordwrap.String(text, avail), avail)
+	lines := strings.Split(wrapped, "\n")
+	for i, line := range lines {
+		if i == 0 {
+			lines[i] = prefix + line
+		} else {
+			lines[i] = hanging + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// extractMarkdownLinks returns the distinct http(s) URLs of a Markdown
+// document in order of appearance, from both links and bare URLs.
+func extractMarkdownLinks(source string) []string {
+	source = mdHTMLCommRe.ReplaceAllString(source, "")
+	var links []string
+	seen := make(map[string]bool)
+	for _, match := range mdBareURLRe.FindAllString(source, -1) {
+		link := strings.TrimRight(match, ".,;:!?*_")
+		if !seen[link] {
+			seen[link] = true
+			links = append(links, link)
+		}
+	}
+	return links
+}
@@ -0,0 +1,161 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/chmouel/lazyworktree/internal/theme"
+	"github.com/muesli/reflow/truncate"
+)
+
+// MarkdownScreen shows a rendered PR/issue description in a scrollable
+// viewport, with its links numbered so they can be opened.
+type MarkdownScreen struct {
+	title    string
+	url      string
+	source   string
+	links    []string
+	viewport viewport.Model
+	width    int
+	height   int
+	thm      *theme.Theme
+	// returnTo is the screen shown again when the viewer closes.
+	returnTo screenType
+	// copyText, when set, is copied to the clipboard with y.
+	copyText string
+}
+
+// NewMarkdownScreen builds a viewer for a Markdown body.
+func NewMarkdownScreen(title, url, body string, maxWidth, maxHeight int, thm *theme.Theme) *MarkdownScreen {
+	s := &MarkdownScreen{
+		title:    title,
+		url:      url,
+		source:   body,
+		links:    extractMarkdownLinks(body),
+		viewport: viewport.New(0, 0),
+		thm:      thm,
+	}
+	s.SetSize(maxWidth, maxHeight)
+	return s
+}
+
+// SetSize fits the viewer to the terminal and re-renders the body.
+func (s *MarkdownScreen) SetSize(maxWidth, maxHeight int) {
+	s.width = 80
+	s.height = 30
+	if maxWidth > 0 {
+		s.width = minInt(120, maxInt(60, int(float64(maxWidth)*0.8)))
+	}
+	if maxHeight > 0 {
+		s.height = maxInt(15, int(float64(maxHeight)*0.8))
+	}
+	s.viewport.Width = s.width - 4
+	s.viewport.Height = maxInt(5, s.height-5)
+	s.viewport.SetContent(s.renderContent())
+}
+
+// renderContent renders the body followed by the numbered link list.
+func (s *MarkdownScreen) renderContent() string {
+	mutedStyle := lipgloss.NewStyle().Foreground(s.thm.MutedFg)
+	body := strings.TrimSpace(s.source)
+	if body == "" {
+		return mutedStyle.Italic(true).Render("No description provided.")
+	}
+	content := renderMarkdown(body, s.viewport.Width, s.thm)
+	if len(s.links) == 0 {
+		return content
+	}
+
+	lines := []string{content, "", lipgloss.NewStyle().Foreground(s.thm.Accent).Bold(true).Render("Links")}
+	linkStyle := lipgloss.NewStyle().Foreground(s.thm.Cyan).Underline(true)
+	for i, link := range s.links {
+		lines = append(lines, fmt.Sprintf("%s %s", mutedStyle.Render(fmt.Sprintf("[%d]", i+1)), linkStyle.Render(link)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// linkForKey returns the link opened by a number key, if any.
+func (s *MarkdownScreen) linkForKey(key string) (string, bool) {
+	if len(key) != 1 || key[0] < '1' || key[0] > '9' {
+		return "", false
+	}
+	index := int(key[0] - '1')
+	if index >= len(s.links) {
+		return "", false
+	}
+	return s.links[index], true
+}
+
+// Update scrolls the description.
+func (s *MarkdownScreen) Update(msg tea.Msg) (*MarkdownScreen, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch keyMsg.String() {
+		case keyCtrlD, " ", "pgdown":
+			s.viewport.HalfPageDown()
+			return s, nil
+		case keyCtrlU, "pgup":
+			s.viewport.HalfPageUp()
+			return s, nil
+		case "j", keyDown:
+			s.viewport.ScrollDown(1)
+			return s, nil
+		case "k", keyUp:
+			s.viewport.ScrollUp(1)
+			return s, nil
+		case "g":
+			s.viewport.GotoTop()
+			return s, nil
+		case "G":
+			s.viewport.GotoBottom()
+			return s, nil
+		}
+	}
+	var cmd tea.Cmd
+	s.viewport, cmd = s.viewport.Update(msg)
+	return s, cmd
+}
+
+// View renders the viewer.
+func (s *MarkdownScreen) View() string {
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(s.thm.Accent).
+		Width(s.width).
+		Padding(0)
+
+	titleStyle := lipgloss.NewStyle().
+		Foreground(s.thm.Accent).
+		Bold(true).
+		Border(lipgloss.NormalBorder(), false, false, true, false).
+		BorderForeground(s.thm.BorderDim).
+		Width(s.width-2).
+		Padding(0, 1).
+		Render(truncate.StringWithTail(s.title, uint(maxInt(1, s.width-4)), "…"))
+
+	body := lipgloss.NewStyle().
+		Padding(0, 1).
+		Width(s.width - 2).
+		Render(s.viewport.View())
+
+	hints := []string{"j/k: scroll", "Ctrl+d/u: page"}
+	if len(s.links) > 0 {
+		hints = append(hints, fmt.Sprintf("1-%d: open link", min(len(s.links), 9)))
+	}
+	if s.url != "" {
+		hints = append(hints, "o: open in browser")
+	}
+	if s.copyText != "" {
+		hints = append(hints, "y: copy")
+	}
+	hints = append(hints, "esc: close")
+	footer := lipgloss.NewStyle().
+		Foreground(s.thm.MutedFg).
+		Width(s.width-2).
+		Padding(1, 1, 0, 1).
+		Render(strings.Join(hints, " • "))
+
+	return boxStyle.Render(lipgloss.JoinVertical(lipgloss.Left, titleStyle, body, footer))
+}
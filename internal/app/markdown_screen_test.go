@@ -0,0 +1,97 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/chmouel/lazyworktree/internal/config"
+	"github.com/chmouel/lazyworktree/internal/models"
+	"github.com/chmouel/lazyworktree/internal/theme"
+)
+
+func TestExtractMarkdownLinks(t *testing.T) {
+	body := "See [the docs](https://example.com/docs) and https://example.com/issue/1.\n" +
+		"<!-- https://hidden.example.com -->\n" +
+		"Again: https://example.com/docs, plus ftp://ignored.example.com"
+	got := extractMarkdownLinks(body)
+	want := []string{"https://example.com/docs", "https://example.com/issue/1"}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
+
+func TestMarkdownScreenLinks(t *testing.T) {
+	s := NewMarkdownScreen("#1 Title", "https://example.com/pull/1", "Fixes https://example.com/issue/2", 100, 40, theme.Dracula())
+	if link, ok := s.linkForKey("1"); !ok || link != "https://example.com/issue/2" {
+		t.Fatalf("expected first link, got %q (%v)", link, ok)
+	}
+	for _, key := range []string{"2", "0", "a", "10"} {
+		if _, ok := s.linkForKey(key); ok {
+			t.Fatalf("expected no link for key %q", key)
+		}
+	}
+	view := s.View()
+	for _, want := range []string{"#1 Title", "Fixes", "[1]", "1-1: open link", "o: open in browser"} {
+		if !strings.Contains(view, want) {
+			t.Fatalf("expected view to contain %q, got %q", want, view)
+		}
+	}
+}
+
+func TestMarkdownScreenEmptyBody(t *testing.T) {
+	s := NewMarkdownScreen("#2 Empty", "", "  ", 100, 40, theme.Dracula())
+	view := s.View()
+	if !strings.Contains(view, "No description provided.") || strings.Contains(view, "open in browser") {
+		t.Fatalf("unexpected view %q", view)
+	}
+}
+
+func TestShowPRDescription(t *testing.T) {
+	cfg := &config.AppConfig{WorktreeDir: t.TempDir()}
+	m := NewModel(cfg, "")
+	m.worktrees = []*models.WorktreeInfo{{Path: t.TempDir(), Branch: "feature"}}
+	m.filteredWts = m.worktrees
+	m.worktreeTable.SetCursor(0)
+
+	m.showPRDescription()
+	if m.currentScreen != screenInfo {
+		t.Fatalf("expected info screen without a PR, got %s", screenName(m.currentScreen))
+	}
+
+	m.currentScreen = screenNone
+	m.worktrees[0].PR = &models.PRInfo{Number: 7, Title: "Add cache", URL: "https://example.com/pull/7", Body: "## Summary\n\n- faster"}
+	m.showPRDescription()
+	if m.currentScreen != screenMarkdown || m.markdownScreen == nil {
+		t.Fatal("expected markdown viewer to open")
+	}
+	if !strings.Contains(m.markdownScreen.View(), "• faster") {
+		t.Fatalf("expected rendered list, got %q", m.markdownScreen.View())
+	}
+
+	_, _ = m.handleScreenKey(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.currentScreen != screenNone || m.markdownScreen != nil {
+		t.Fatal("expected viewer to close")
+	}
+}
+
+func TestPRSelectTabShowsDescription(t *testing.T) {
+	cfg := &config.AppConfig{WorktreeDir: t.TempDir()}
+	m := NewModel(cfg, "")
+	prs := []*models.PRInfo{{Number: 3, Title: "Tidy", Body: "Tidied the **drawers**"}}
+	m.prSelectionScreen = NewPRSelectionScreen(prs, 100, 40, m.theme, false)
+	m.currentScreen = screenPRSelect
+
+	_, _ = m.handleScreenKey(tea.KeyMsg{Type: tea.KeyTab})
+	if m.currentScreen != screenMarkdown || m.markdownScreen == nil {
+		t.Fatal("expected Tab to open the description")
+	}
+	if !strings.Contains(m.markdownScreen.View(), "#3 Tidy") {
+		t.Fatalf("unexpected viewer %q", m.markdownScreen.View())
+	}
+
+	_, _ = m.handleScreenKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}})
+	if m.currentScreen != screenPRSelect || m.prSelectionScreen == nil {
+		t.Fatal("expected to return to the PR picker")
+	}
+}
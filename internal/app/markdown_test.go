@@ -0,0 +1,55 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/chmouel/lazyworktree/internal/config"
+)
+
+func TestRenderMarkdown(t *testing.T) {
+	m := NewModel(&config.AppConfig{WorktreeDir: t.TempDir()}, "")
+	source := strings.Join([]string{
+		"# Project *title*",
+		"<!-- hidden note -->",
+		"Some **bold** text with `code` and a [link](https://example.com).",
+		"",
+		"- first item",
+		"  - nested item",
+		"- [x] done task",
+		"1. ordered",
+		"> quoted",
+		"---",
+		"```go",
+		"func main() {}",
+		"```",
+		"![logo](logo.png)",
+	}, "\n")
+
+	got := renderMarkdown(source, 40, m.theme)
+	want := []string{
+		"Project *title*",
+		"Some bold text with code and a link.",
+		"",
+		"• first item",
+		"  • nested item",
+		"☑ done task",
+		"1. ordered",
+		"│ quoted",
+		strings.Repeat("─", 40),
+		"  func main() {}",
+		"[logo]",
+	}
+	if got != strings.Join(want, "\n") {
+		t.Fatalf("unexpected rendering:\n%s", got)
+	}
+}
+
+func TestRenderMarkdownWrapsListItems(t *testing.T) {
+	m := NewModel(&config.AppConfig{WorktreeDir: t.TempDir()}, "")
+	got := renderMarkdown("- alpha beta gamma delta epsilon zeta", 16, m.theme)
+	want := "• alpha beta\n  gamma delta\n  epsilon zeta"
+	if got != want {
+		t.Fatalf("renderMarkdown() = %q, want %q", got, want)
+	}
+}
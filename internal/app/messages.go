@@ -9,6 +9,7 @@ import (
 
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/chmouel/lazyworktree/internal/events"
 	log "github.com/chmouel/lazyworktree/internal/log"
 	"github.com/chmouel/lazyworktree/internal/models"
 	"github.com/chmouel/lazyworktree/internal/utils"
@@ -18,7 +19,16 @@ import (
 func (m *Model) handleWorktreeMessages(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case worktreesLoadedMsg:
-		return m.handleWorktreesLoaded(msg)
+		if msg.refreshGen == 0 {
+			return m.handleWorktreesLoaded(msg)
+		}
+		if msg.refreshGen != m.refreshGen {
+			// A newer refresh superseded this one.
+			return m, nil
+		}
+		followUp := m.finishRefresh()
+		model, cmd := m.handleWorktreesLoaded(msg)
+		return model, tea.Batch(cmd, followUp)
 	case cachedWorktreesMsg:
 		return m.handleCachedWorktrees(msg)
 	case pruneResultMsg:
@@ -32,15 +42,20 @@ func (m *Model) handleWorktreeMessages(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 // handleWorktreesLoaded processes worktrees loaded message.
 func (m *Model) handleWorktreesLoaded(msg worktreesLoadedMsg) (tea.Model, tea.Cmd) {
+	firstLoad := !m.worktreesLoaded
 	m.worktreesLoaded = true
 	// Don't clear loading screen if we're in the middle of push/sync operations
-	if m.loadingOperation != "push" && m.loadingOperation != "sync" {
+	if !msg.background && m.loadingOperation != "push" && m.loadingOperation != "sync" {
 		m.loading = false
 		if m.currentScreen == screenLoading {
 			m.currentScreen = screenNone
 			m.loadingScreen = nil
 		}
 	}
+	if msg.err != nil && msg.background {
+		m.debugf("background refresh failed: %v", msg.err)
+		return m, nil
+	}
 	if msg.err != nil {
 		m.showInfo(fmt.Sprintf("Error loading worktrees: %v", msg.err), nil)
 		return m, nil
@@ -48,6 +63,10 @@ func (m *Model) handleWorktreesLoaded(msg worktreesLoadedMsg) (tea.Model, tea.Cm
 
 	// Preserve PR state across worktree reload to prevent race condition
 	prStateMap := extractPRState(m.worktrees)
+	if !firstLoad {
+		m.emitWorktreeChanges(m.worktrees, msg.worktrees)
+		m.observeChanges(m.worktrees, msg.worktrees)
+	}
 	m.worktrees = msg.worktrees
 	restorePRState(m.worktrees, prStateMap)
 
@@ -87,6 +106,7 @@ func (m *Model) handleWorktreesLoaded(msg worktreesLoadedMsg) (tea.Model, tea.Cm
 		m.pendingSelectWorktreePath = ""
 	}
 	m.saveCache()
+	m.emit(events.Event{Type: events.RefreshComplete, Worktrees: len(m.worktrees)})
 	if len(m.worktrees) == 0 {
 		cwd, _ := os.Getwd()
 		m.welcomeScreen = NewWelcomeScreen(cwd, m.getRepoWorktreeDir(), m.theme)
@@ -112,6 +132,45 @@ func (m *Model) handleWorktreesLoaded(msg worktreesLoadedMsg) (tea.Model, tea.Cm
 	if cmd := m.startGitWatcher(); cmd != nil {
 		cmds = append(cmds, cmd)
 	}
+	if cmd := m.watchWorktrees(); cmd != nil {
+		cmds = append(cmds, cmd)
+	}
+	if cmd := m.measureDiskUsage(); cmd != nil {
+		cmds = append(cmds, cmd)
+	}
+	if cmd := m.loadCodeOwners(); cmd != nil {
+		cmds = append(cmds, cmd)
+	}
+	if cmd := m.detectWorkspace(); cmd != nil {
+		cmds = append(cmds, cmd)
+	}
+	if cmd := m.detectPartialClone(); cmd != nil {
+		cmds = append(cmds, cmd)
+	}
+	if cmd := m.startMaintenance(); cmd != nil {
+		cmds = append(cmds, cmd)
+	}
+	if cmd := m.suggestBranches(); cmd != nil {
+		cmds = append(cmds, cmd)
+	}
+	if cmd := m.loadServices(); cmd != nil {
+		cmds = append(cmds, cmd)
+	}
+	if cmd := m.loadDiffStats(); cmd != nil {
+		cmds = append(cmds, cmd)
+	}
+	if cmd := m.loadComposeStatus(); cmd != nil {
+		cmds = append(cmds, cmd)
+	}
+	if cmd := m.loadEnvToolStates(); cmd != nil {
+		cmds = append(cmds, cmd)
+	}
+	if cmd := m.loadStackLinks(); cmd != nil {
+		cmds = append(cmds, cmd)
+	}
+	if cmd := m.loadColocated(); cmd != nil {
+		cmds = append(cmds, cmd)
+	}
 	return m, tea.Batch(cmds...)
 }
 
@@ -144,6 +203,7 @@ func (m *Model) handlePruneResult(msg pruneResultMsg) (tea.Model, tea.Cmd) {
 	if msg.err == nil && msg.worktrees != nil {
 		// Preserve PR state across worktree reload to prevent race condition
 		prStateMap := extractPRState(m.worktrees)
+		m.emitWorktreeChanges(m.worktrees, msg.worktrees)
 		m.worktrees = msg.worktrees
 		restorePRState(m.worktrees, prStateMap)
 		m.updateTable()
@@ -191,6 +251,8 @@ func (m *Model) handlePRDataLoaded(msg prDataLoadedMsg) (tea.Model, tea.Cmd) {
 		m.loadingScreen = nil
 	}
 	if msg.err == nil {
+		m.storePRLookups(msg.lookups)
+		prBefore := m.prEventStates()
 		log.Printf("handlePRDataLoaded: prMap has %d entries, worktreePRs has %d entries, worktreeErrors has %d entries",
 			len(msg.prMap), len(msg.worktreePRs), len(msg.worktreeErrors))
 
@@ -247,6 +309,16 @@ func (m *Model) handlePRDataLoaded(msg prDataLoadedMsg) (tea.Model, tea.Cmd) {
 				log.Printf("  Final: wt.PR = nil, status = %s, error = %q", wt.PRFetchStatus, wt.PRFetchError)
 			}
 		}
+		// Checks fetched alongside the PR spare a separate CI request
+		for _, wt := range m.worktrees {
+			if wt.PR != nil && wt.PR.Checks != nil {
+				m.ciCache[wt.Branch] = &ciCacheEntry{
+					checks:    wt.PR.Checks,
+					fetchedAt: time.Now(),
+				}
+			}
+		}
+		m.emitPRChanges(prBefore)
 		m.prDataLoaded = true
 		// Update columns before rows to include the PR column
 		m.updateTableColumns(m.worktreeTable.Width())
@@ -258,7 +330,7 @@ func (m *Model) handlePRDataLoaded(msg prDataLoadedMsg) (tea.Model, tea.Cmd) {
 			return m, m.performMergedWorktreeCheck()
 		}
 
-		return m, m.updateDetailsView()
+		return m, tea.Batch(m.updateDetailsView(), m.fetchDeployments())
 	}
 	// Even if PR fetch failed, run merged check if requested (will fall back to git-based detection)
 	if m.checkMergedAfterPRRefresh {
@@ -282,6 +354,8 @@ func (m *Model) handleCIStatusLoaded(msg ciStatusLoadedMsg) (tea.Model, tea.Cmd)
 				m.infoContent = m.buildInfoContent(wt)
 			}
 		}
+		m.refreshHealthScreen()
+		return m, m.maybeFetchCIAnnotations()
 	}
 	return m, nil
 }
@@ -301,111 +375,18 @@ func (m *Model) handleOpenPRsLoaded(msg openPRsLoadedMsg) tea.Cmd {
 	// Show PR selection screen
 	m.prSelectionScreen = NewPRSelectionScreen(msg.prs, m.windowWidth, m.windowHeight, m.theme, m.config.ShowIcons)
 	m.prSelectionSubmit = func(pr *models.PRInfo) tea.Cmd {
-		// Get AI-generated title (if configured)
-		generatedTitle := ""
-		scriptErr := ""
-
-		if m.config.BranchNameScript != "" {
-			prContent := fmt.Sprintf("%s\n\n%s", pr.Title, pr.Body)
-			template := m.config.PRBranchNameTemplate
-			if template == "" {
-				template = "pr-{number}-{title}"
-			}
-			// Pass empty string for generatedTitle since we're getting it now
-			suggestedName := utils.GeneratePRWorktreeName(pr, template, "")
-
-			if aiTitle, err := runBranchNameScript(
-				m.ctx,
-				m.config.BranchNameScript,
-				prContent,
-				"pr",
-				fmt.Sprintf("%d", pr.Number),
-				template,
-				suggestedName,
-			); err != nil {
-				scriptErr = fmt.Sprintf("Branch name script error: %v", err)
-			} else if aiTitle != "" {
-				generatedTitle = aiTitle
-			}
-		}
-
-		// Apply template with both original and generated titles
 		template := m.config.PRBranchNameTemplate
 		if template == "" {
 			template = "pr-{number}-{title}"
 		}
 
-		defaultName := utils.GeneratePRWorktreeName(pr, template, generatedTitle)
-
-		// Suggest branch name (check for duplicates)
-		suggested := strings.TrimSpace(defaultName)
+		// Start from the template's name; branch_name_script, if
+		// configured, streams its own title in.
+		suggested := strings.TrimSpace(utils.GeneratePRWorktreeName(pr, template, ""))
 		if suggested != "" {
 			suggested = m.suggestBranchName(suggested)
 		}
 
-		if scriptErr != "" {
-			m.showInfo(scriptErr, func() tea.Msg {
-				m.inputScreen = NewInputScreen(
-					fmt.Sprintf("Create worktree from PR #%d (branch: %s)", pr.Number, pr.Branch),
-					"Worktree name",
-					suggested,
-					m.theme,
-				)
-				m.inputSubmit = func(value string, checked bool) (tea.Cmd, bool) {
-					newBranch := strings.TrimSpace(value)
-					newBranch = sanitizeBranchNameFromTitle(newBranch, "")
-					if newBranch == "" {
-						m.inputScreen.errorMsg = errBranchEmpty
-						return nil, false
-					}
-
-					targetPath := filepath.Join(m.getRepoWorktreeDir(), newBranch)
-					if errMsg := m.validateNewWorktreeTarget(newBranch, targetPath); errMsg != "" {
-						m.inputScreen.errorMsg = errMsg
-						return nil, false
-					}
-
-					// Validate that PR has a branch
-					if pr.Branch == "" {
-						m.inputScreen.errorMsg = errPRBranchMissing
-						return nil, false
-					}
-
-					m.inputScreen.errorMsg = ""
-					if err := m.ensureWorktreeDir(m.getRepoWorktreeDir()); err != nil {
-						return func() tea.Msg { return errMsg{err: err} }, true
-					}
-
-					// Create worktree from PR branch (can take time, so do it async with a loading pulse)
-					m.loading = true
-					m.statusContent = fmt.Sprintf("Creating worktree from PR/MR #%d...", pr.Number)
-					m.loadingScreen = NewLoadingScreen(m.statusContent, m.theme)
-					m.currentScreen = screenLoading
-					m.pendingSelectWorktreePath = targetPath
-					return func() tea.Msg {
-						ok := m.git.CreateWorktreeFromPR(m.ctx, pr.Number, pr.Branch, newBranch, targetPath)
-						if !ok {
-							return createFromPRResultMsg{
-								prNumber:   pr.Number,
-								branch:     newBranch,
-								targetPath: targetPath,
-								err:        fmt.Errorf("create worktree from PR/MR branch %q", pr.Branch),
-							}
-						}
-						return createFromPRResultMsg{
-							prNumber:   pr.Number,
-							branch:     newBranch,
-							targetPath: targetPath,
-							err:        nil,
-						}
-					}, true
-				}
-				m.currentScreen = screenInput
-				return nil
-			})
-			return nil
-		}
-
 		// Show input screen with generated name
 		m.inputScreen = NewInputScreen(
 			fmt.Sprintf("Create worktree from PR #%d (branch: %s)", pr.Number, pr.Branch),
@@ -413,6 +394,7 @@ func (m *Model) handleOpenPRsLoaded(msg openPRsLoadedMsg) tea.Cmd {
 			suggested,
 			m.theme,
 		)
+		offered := ""
 		m.inputSubmit = func(value string, checked bool) (tea.Cmd, bool) {
 			newBranch := strings.TrimSpace(value)
 			newBranch = sanitizeBranchNameFromTitle(newBranch, "")
@@ -421,11 +403,18 @@ func (m *Model) handleOpenPRsLoaded(msg openPRsLoadedMsg) tea.Cmd {
 				return nil, false
 			}
 
-			targetPath := filepath.Join(m.getRepoWorktreeDir(), newBranch)
-			if errMsg := m.validateNewWorktreeTarget(newBranch, targetPath); errMsg != "" {
+			targetPath, note, reason := m.newWorktreeTarget(newBranch, newBranch)
+			if reason != "" {
+				m.inputScreen.errorMsg = reason
+				return nil, false
+			}
+			if errMsg := m.policyViolation("", ""); errMsg != "" {
 				m.inputScreen.errorMsg = errMsg
 				return nil, false
 			}
+			if m.offerAdjustedPath(targetPath, note, &offered) {
+				return nil, false
+			}
 
 			// Validate that PR has a branch
 			if pr.Branch == "" {
@@ -458,6 +447,17 @@ func (m *Model) handleOpenPRsLoaded(msg openPRsLoadedMsg) tea.Cmd {
 			}, true
 		}
 		m.currentScreen = screenInput
+		if cmd := m.startBranchNameStream(branchNameScriptRun{
+			content:       fmt.Sprintf("%s\n\n%s", pr.Title, pr.Body),
+			scriptType:    "pr",
+			number:        fmt.Sprintf("%d", pr.Number),
+			template:      template,
+			suggestedName: utils.GeneratePRWorktreeName(pr, template, ""),
+		}, func(generated string) string {
+			return utils.GeneratePRWorktreeName(pr, template, generated)
+		}); cmd != nil {
+			return cmd
+		}
 		return textinput.Blink
 	}
 	m.currentScreen = screenPRSelect
@@ -487,59 +487,18 @@ func (m *Model) handleOpenIssuesLoaded(msg openIssuesLoadedMsg) tea.Cmd {
 			"No branches found.",
 			defaultBase,
 			func(baseBranch string) tea.Cmd {
-				// Get AI-generated title (if configured)
-				generatedTitle := ""
-				scriptErr := ""
-
-				if m.config.BranchNameScript != "" {
-					issueContent := fmt.Sprintf("%s\n\n%s", issue.Title, issue.Body)
-					template := m.config.IssueBranchNameTemplate
-					if template == "" {
-						template = "issue-{number}-{title}"
-					}
-					// Pass empty string for generatedTitle since we're getting it now
-					suggestedName := utils.GenerateIssueWorktreeName(issue, template, "")
-
-					if aiTitle, err := runBranchNameScript(
-						m.ctx,
-						m.config.BranchNameScript,
-						issueContent,
-						"issue",
-						fmt.Sprintf("%d", issue.Number),
-						template,
-						suggestedName,
-					); err != nil {
-						scriptErr = fmt.Sprintf("Branch name script error: %v", err)
-					} else if aiTitle != "" {
-						generatedTitle = aiTitle
-					}
-				}
-
-				// Apply template with both original and generated titles
 				template := m.config.IssueBranchNameTemplate
 				if template == "" {
 					template = "issue-{number}-{title}"
 				}
 
-				defaultName := utils.GenerateIssueWorktreeName(issue, template, generatedTitle)
-
-				// Suggest branch name (check for duplicates)
-				suggested := strings.TrimSpace(defaultName)
+				// Start from the template's name; branch_name_script, if
+				// configured, streams its own title in.
+				suggested := strings.TrimSpace(utils.GenerateIssueWorktreeName(issue, template, ""))
 				if suggested != "" {
 					suggested = m.suggestBranchName(suggested)
 				}
 
-				if scriptErr != "" {
-					m.showInfo(scriptErr, func() tea.Msg {
-						cmd := m.showBranchNameInput(baseBranch, suggested)
-						if cmd != nil {
-							return cmd()
-						}
-						return nil
-					})
-					return nil
-				}
-
 				// Show input screen with generated name
 				m.inputScreen = NewInputScreen(
 					fmt.Sprintf("Create worktree from issue #%d", issue.Number),
@@ -547,6 +506,7 @@ func (m *Model) handleOpenIssuesLoaded(msg openIssuesLoadedMsg) tea.Cmd {
 					suggested,
 					m.theme,
 				)
+				offered := ""
 				m.inputSubmit = func(value string, checked bool) (tea.Cmd, bool) {
 					newBranch := strings.TrimSpace(value)
 					newBranch = sanitizeBranchNameFromTitle(newBranch, "")
@@ -555,11 +515,18 @@ func (m *Model) handleOpenIssuesLoaded(msg openIssuesLoadedMsg) tea.Cmd {
 						return nil, false
 					}
 
-					targetPath := filepath.Join(m.getRepoWorktreeDir(), newBranch)
-					if errMsg := m.validateNewWorktreeTarget(newBranch, targetPath); errMsg != "" {
+					targetPath, note, reason := m.newWorktreeTarget(newBranch, newBranch)
+					if reason != "" {
+						m.inputScreen.errorMsg = reason
+						return nil, false
+					}
+					if errMsg := m.policyViolation(newBranch, baseBranch); errMsg != "" {
 						m.inputScreen.errorMsg = errMsg
 						return nil, false
 					}
+					if m.offerAdjustedPath(targetPath, note, &offered) {
+						return nil, false
+					}
 
 					m.inputScreen.errorMsg = ""
 					if err := m.ensureWorktreeDir(m.getRepoWorktreeDir()); err != nil {
@@ -591,6 +558,17 @@ func (m *Model) handleOpenIssuesLoaded(msg openIssuesLoadedMsg) tea.Cmd {
 					}, true
 				}
 				m.currentScreen = screenInput
+				if cmd := m.startBranchNameStream(branchNameScriptRun{
+					content:       fmt.Sprintf("%s\n\n%s", issue.Title, issue.Body),
+					scriptType:    "issue",
+					number:        fmt.Sprintf("%d", issue.Number),
+					template:      template,
+					suggestedName: utils.GenerateIssueWorktreeName(issue, template, ""),
+				}, func(generated string) string {
+					return utils.GenerateIssueWorktreeName(issue, template, generated)
+				}); cmd != nil {
+					return cmd
+				}
 				return textinput.Blink
 			},
 		)
@@ -610,9 +588,7 @@ func (m *Model) handleCreateFromChangesReady(msg createFromChangesReadyMsg) tea.
 	// If branch_name_script is configured, run it to generate a suggested name
 	scriptErr := ""
 	if m.config.BranchNameScript != "" && msg.diff != "" {
-		if generatedName, err := runBranchNameScript(
-			m.ctx,
-			m.config.BranchNameScript,
+		if generatedName, err := m.runBranchNameScript(
 			msg.diff,
 			"diff",
 			"",
@@ -656,7 +632,7 @@ func (m *Model) handleCherryPickResult(msg cherryPickResultMsg) tea.Cmd {
 		msg.commitSHA,
 		filepath.Base(msg.targetWorktree.Path),
 		msg.targetWorktree.Branch)
-	m.showInfo(successMessage, m.refreshWorktrees())
+	m.showInfo(successMessage, queueRefresh)
 	return nil
 }
 
@@ -0,0 +1,118 @@
+package app
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Modal screens shrink with the terminal down to these sizes, below which
+// their buttons would no longer fit on a line.
+const (
+	minModalWidth  = 36
+	minModalHeight = 7
+)
+
+// fitModal returns preferred, or available when that is smaller, but never
+// less than minimum. An unknown (zero) available keeps preferred.
+func fitModal(preferred, available, minimum int) int {
+	if available <= 0 {
+		return preferred
+	}
+	return maxInt(minimum, minInt(preferred, available))
+}
+
+// clipMessage wraps a centred message to width and shows height lines of
+// it from offset, which is clamped. A message longer than height gives its
+// last line to a hint that it scrolls.
+func clipMessage(message string, width, height int, offset *int, hint lipgloss.Style) string {
+	lines := strings.Split(lipgloss.NewStyle().Width(width).Align(lipgloss.Center).Render(message), "\n")
+	if len(lines) <= height {
+		*offset = 0
+		return strings.Join(lines, "\n")
+	}
+	visible := maxInt(height-1, 1)
+	*offset = maxInt(0, minInt(*offset, len(lines)-visible))
+	more := hint.Width(width).Align(lipgloss.Center).Render("↑↓ more")
+	return strings.Join(lines[*offset:*offset+visible], "\n") + "\n" + more
+}
+
+// SetSize fits the confirmation box to a terminal of maxWidth by maxHeight.
+func (s *ConfirmScreen) SetSize(maxWidth, maxHeight int) {
+	s.width = fitModal(60, maxWidth-4, minModalWidth)
+	s.height = fitModal(11, maxHeight-4, minModalHeight)
+}
+
+// SetSize fits the information box to a terminal of maxWidth by maxHeight.
+func (s *InfoScreen) SetSize(maxWidth, maxHeight int) {
+	s.width = fitModal(60, maxWidth-4, minModalWidth)
+	s.height = fitModal(11, maxHeight-4, minModalHeight)
+}
+
+// SetSize fits the input box to a terminal of maxWidth by maxHeight; a
+// terminal too short for its usual spacing gets the lines packed together.
+func (s *InputScreen) SetSize(maxWidth, maxHeight int) {
+	s.boxWidth = fitModal(60, maxWidth-4, minModalWidth)
+	s.maxHeight = maxHeight
+	s.input.Width = s.boxWidth - 8
+}
+
+// SetSize fits the trust prompt to a terminal of maxWidth by maxHeight,
+// wrapping the commands to the narrower box.
+func (s *TrustScreen) SetSize(maxWidth, maxHeight int) {
+	width, height := fitModal(70, maxWidth-2, minModalWidth), fitModal(25, maxHeight-2, 12)
+	if width == s.width && height == s.height {
+		return
+	}
+	s.width, s.height = width, height
+	s.viewport.Width = s.width - 4
+	s.viewport.Height = maxInt(3, s.height-4-lipgloss.Height(s.buttons()))
+	s.viewport.SetContent(lipgloss.NewStyle().Width(s.viewport.Width).Render(s.content))
+}
+
+// SetSize fits the commit viewer to a terminal of maxWidth by maxHeight.
+func (s *CommitScreen) SetSize(maxWidth, maxHeight int) {
+	if maxWidth <= 0 || maxHeight <= 0 {
+		return
+	}
+	s.viewport.Width = fitModal(int(float64(maxWidth)*0.95), maxWidth-4, minModalWidth)
+	s.viewport.Height = fitModal(int(float64(maxHeight)*0.85), maxHeight-4, 5)
+}
+
+// fitModals sizes the confirm, info, input, trust and commit screens, which
+// are created before the window size is known to them, to the window. It is
+// cheap enough to run on every frame.
+func (m *Model) fitModals() {
+	if m.confirmScreen != nil {
+		m.confirmScreen.SetSize(m.windowWidth, m.windowHeight)
+	}
+	if m.infoScreen != nil {
+		m.infoScreen.SetSize(m.windowWidth, m.windowHeight)
+	}
+	if m.inputScreen != nil {
+		m.inputScreen.SetSize(m.windowWidth, m.windowHeight)
+	}
+	if m.trustScreen != nil {
+		m.trustScreen.SetSize(m.windowWidth, m.windowHeight)
+	}
+	if m.commitScreen != nil {
+		m.commitScreen.SetSize(m.windowWidth, m.windowHeight)
+	}
+}
+
+// resizeScreens passes a new window size on to the open screens.
+func (m *Model) resizeScreens() {
+	m.fitModals()
+	if m.helpScreen != nil {
+		m.helpScreen.SetSize(m.windowWidth, m.windowHeight)
+	}
+	if m.markdownScreen != nil {
+		m.markdownScreen.SetSize(m.windowWidth, m.windowHeight)
+	}
+	if m.healthScreen != nil {
+		m.healthScreen.SetSize(m.windowWidth, m.windowHeight)
+	}
+	if m.errorScreen != nil {
+		m.errorScreen.SetSize(m.windowWidth)
+	}
+}
@@ -0,0 +1,105 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/chmouel/lazyworktree/internal/config"
+	"github.com/chmouel/lazyworktree/internal/theme"
+)
+
+// assertFits fails when view is wider or taller than width by height.
+func assertFits(t *testing.T, name, view string, width, height int) {
+	t.Helper()
+	if w, h := lipgloss.Width(view), lipgloss.Height(view); w > width || h > height {
+		t.Fatalf("%s: expected to fit %dx%d, got %dx%d:\n%s", name, width, height, w, h, view)
+	}
+}
+
+func TestModalScreensFitSmallTerminals(t *testing.T) {
+	thm := theme.Dracula()
+	long := strings.Repeat("Delete the worktree and everything in it? ", 20)
+	var commands []string
+	for i := range 40 {
+		commands = append(commands, fmt.Sprintf("make setup-%d", i))
+	}
+
+	for _, size := range [][2]int{{80, 24}, {40, 16}} {
+		width, height := size[0], size[1]
+
+		confirm := NewConfirmScreen(long, thm)
+		confirm.SetSize(width, height)
+		assertFits(t, "confirm", confirm.View(), width, height)
+
+		info := NewInfoScreen(long, thm)
+		info.SetSize(width, height)
+		assertFits(t, "info", info.View(), width, height)
+
+		input := NewInputScreen("Create worktree", "branch", "", thm)
+		input.SetCheckbox("Include current changes", true)
+		input.SetToggle("ctrl+n", "Number the name when taken", true)
+		input.errorMsg = "Branch already exists"
+		input.SetSize(width, height)
+		assertFits(t, "input", input.View(), width, height)
+
+		trust := NewTrustScreen("/home/me/src/some/deeply/nested/repository/.wt", commands, thm)
+		trust.SetSize(width, height)
+		view := trust.View()
+		assertFits(t, "trust", view, width, height)
+		if !strings.Contains(view, "Cancel Operation") {
+			t.Fatalf("expected the trust buttons kept at %dx%d:\n%s", width, height, view)
+		}
+
+		commit := NewCommitScreen(commitMeta{sha: "abc123", subject: "Fix"}, "", strings.Repeat("+ a line of the diff\n", 200), false, thm)
+		commit.SetSize(width, height)
+		assertFits(t, "commit", commit.View(), width, height)
+	}
+}
+
+func TestConfirmScreenScrollsLongMessages(t *testing.T) {
+	var lines []string
+	for i := range 30 {
+		lines = append(lines, fmt.Sprintf("worktree-%02d", i))
+	}
+	s := NewConfirmScreen(strings.Join(lines, "\n"), theme.Dracula())
+	s.SetSize(80, 24)
+	view := s.View()
+	if !strings.Contains(view, "worktree-00") || strings.Contains(view, "worktree-29") || !strings.Contains(view, "↑↓ more") {
+		t.Fatalf("expected the top of the message and a scroll hint:\n%s", view)
+	}
+	for range 40 {
+		s.Update(tea.KeyMsg{Type: tea.KeyDown})
+	}
+	view = s.View()
+	if !strings.Contains(view, "worktree-29") || strings.Contains(view, "worktree-00") {
+		t.Fatalf("expected the message scrolled to its end:\n%s", view)
+	}
+	s.Update(tea.KeyMsg{Type: tea.KeyUp})
+	if s.scroll == 0 {
+		t.Fatal("expected the scroll offset clamped at the end, not counted past it")
+	}
+}
+
+func TestWindowResizeReachesModals(t *testing.T) {
+	m := NewModel(&config.AppConfig{WorktreeDir: t.TempDir()}, "")
+	m.loading = false
+	m.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+	m.showInfo(strings.Repeat("Something went wrong. ", 40), nil)
+	if view := m.View(); !strings.Contains(view, "[OK]") || !strings.Contains(view, "↑↓ more") {
+		t.Fatalf("expected the info box with its button and a scroll hint:\n%s", view)
+	}
+	assertFits(t, "info", m.infoScreen.View(), 80, 24)
+
+	m.helpScreen = NewHelpScreen(80, 24, nil, m.theme)
+	m.Update(tea.WindowSizeMsg{Width: 200, Height: 60})
+	if m.helpScreen.width != 100 || m.infoScreen.width != 60 {
+		t.Fatalf("expected the screens resized with the window, got help %d and info %d", m.helpScreen.width, m.infoScreen.width)
+	}
+	m.Update(tea.WindowSizeMsg{Width: 50, Height: 20})
+	if m.infoScreen.width != 46 || lipgloss.Height(m.infoScreen.View()) > 16 {
+		t.Fatalf("expected the info box narrowed to the window, got %d", m.infoScreen.width)
+	}
+}
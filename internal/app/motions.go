@@ -0,0 +1,169 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// maxMotionCount caps count prefixes, which only need to reach the last
+// worktree.
+const maxMotionCount = 9999
+
+// handleMotionKey handles, with vim_motions, count prefixes such as 5j,
+// gg and M ahead of the built-in keys those would otherwise be. It
+// reports false for the keys it leaves to them.
+func (m *Model) handleMotionKey(keyStr string) (tea.Cmd, bool) {
+	if !m.config.VimMotions {
+		return nil, false
+	}
+	if m.motionPendingG {
+		m.motionPendingG = false
+		count := m.takeMotionCount()
+		if keyStr != "g" {
+			// An unknown sequence is dropped, as vim does.
+			return nil, true
+		}
+		if count > 0 {
+			return m.gotoRow(count - 1), true
+		}
+		_, cmd := m.handleGotoTop()
+		return cmd, true
+	}
+
+	switch {
+	case len(keyStr) == 1 && keyStr[0] >= '1' && keyStr[0] <= '9',
+		keyStr == "0" && m.motionCount > 0:
+		m.motionCount = min(m.motionCount*10+int(keyStr[0]-'0'), maxMotionCount)
+		return nil, true
+	case keyStr == "g":
+		m.motionPendingG = true
+		return nil, true
+	case keyStr == "M":
+		m.motionCount = 0
+		return m.gotoVisibleRow(func(top, visible int) int { return top + (visible-1)/2 }), true
+	}
+
+	count := m.takeMotionCount()
+	if count == 0 {
+		return nil, false
+	}
+	switch keyStr {
+	case "j", keyDown:
+		return m.moveRows(count), true
+	case "k", keyUp:
+		return m.moveRows(-count), true
+	case "G":
+		return m.gotoRow(count - 1), true
+	case "H":
+		return m.gotoVisibleRow(func(top, visible int) int { return top + min(count, visible) - 1 }), true
+	case "L":
+		return m.gotoVisibleRow(func(top, visible int) int { return top + visible - min(count, visible) }), true
+	}
+	// Any other key drops the count and does what it usually does.
+	return nil, false
+}
+
+// takeMotionCount returns the pending count prefix, 0 without one, and
+// clears it.
+func (m *Model) takeMotionCount() int {
+	count := m.motionCount
+	m.motionCount = 0
+	return count
+}
+
+// moveRows moves the focused pane's selection by delta rows.
+func (m *Model) moveRows(delta int) tea.Cmd {
+	switch m.focusedPane {
+	case 0:
+		if delta > 0 {
+			m.worktreeTable.MoveDown(delta)
+		} else {
+			m.worktreeTable.MoveUp(-delta)
+		}
+		m.updateWorktreeArrows()
+		return m.debouncedUpdateDetailsView()
+	case 1:
+		if len(m.statusTreeFlat) > 0 {
+			m.statusTreeIndex = max(0, min(m.statusTreeIndex+delta, len(m.statusTreeFlat)-1))
+			m.rebuildStatusContentWithHighlight()
+		}
+	case 2:
+		if delta > 0 {
+			m.logTable.MoveDown(delta)
+		} else {
+			m.logTable.MoveUp(-delta)
+		}
+	}
+	return nil
+}
+
+// gotoRow selects the focused pane's row at index, the last one when
+// there are fewer.
+func (m *Model) gotoRow(index int) tea.Cmd {
+	cursor := 0
+	switch m.focusedPane {
+	case 0:
+		cursor = m.worktreeTable.Cursor()
+	case 1:
+		cursor = m.statusTreeIndex
+	case 2:
+		cursor = m.logTable.Cursor()
+	}
+	if index == cursor {
+		return nil
+	}
+	return m.moveRows(index - cursor)
+}
+
+// gotoVisibleRow selects the worktree shown on the row pick returns, given
+// the first visible row and how many are visible; H, M and L use it.
+func (m *Model) gotoVisibleRow(pick func(top, visible int) int) tea.Cmd {
+	if m.focusedPane != 0 {
+		return nil
+	}
+	top, visible := m.visibleWorktreeRows()
+	if visible == 0 {
+		return nil
+	}
+	return m.gotoRow(pick(top, visible))
+}
+
+// visibleWorktreeRows returns the first worktree row the table shows and
+// how many it shows. The table keeps its scroll offset to itself, so the
+// selected row's arrow is looked for in what it renders.
+func (m *Model) visibleWorktreeRows() (top, visible int) {
+	rows := len(m.worktreeTable.Rows())
+	if rows == 0 {
+		return 0, 0
+	}
+	lines := strings.Split(m.worktreeTable.View(), "\n")
+	header := len(lines) - m.worktreeTable.Height()
+	if header < 0 {
+		return 0, 0
+	}
+	cursor := m.worktreeTable.Cursor()
+	for i, line := range lines[header:] {
+		if strings.Contains(line, "›") {
+			top = max(0, cursor-i)
+			break
+		}
+	}
+	return top, min(m.worktreeTable.Height(), rows-top)
+}
+
+// worktreePaneTitle names the worktree pane with the selected position,
+// e.g. "Worktrees 17/243", and any count being typed.
+func (m *Model) worktreePaneTitle() string {
+	title := "Worktrees"
+	if n := len(m.filteredWts); n > 0 {
+		title = fmt.Sprintf("%s %d/%d", title, min(m.worktreeTable.Cursor()+1, n), n)
+	}
+	if m.motionCount > 0 {
+		title = fmt.Sprintf("%s · %d", title, m.motionCount)
+	} else if m.motionPendingG {
+		title += " · g"
+	}
+	return title
+}
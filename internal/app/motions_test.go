@@ -0,0 +1,75 @@
+package app
+
+import (
+	"fmt"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/chmouel/lazyworktree/internal/config"
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+func TestMotions(t *testing.T) {
+	m := NewModel(&config.AppConfig{WorktreeDir: t.TempDir(), VimMotions: true}, "")
+	for i := range 30 {
+		m.worktrees = append(m.worktrees, &models.WorktreeInfo{Path: fmt.Sprintf("/wt/%02d", i), Branch: fmt.Sprintf("b%02d", i)})
+	}
+	m.updateTable()
+	m.worktreeTable.SetHeight(8)
+	m.worktreeTable.SetCursor(0)
+	m.focusedPane = 0
+	visible := m.worktreeTable.Height()
+
+	keys := func(s string) {
+		for _, r := range s {
+			m.handleKeyMsg(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		}
+	}
+	if got := m.worktreePaneTitle(); got != "Worktrees 1/30" {
+		t.Fatalf("expected the position in the title, got %q", got)
+	}
+
+	keys("1")
+	if m.focusedPane != 0 || m.worktreePaneTitle() != "Worktrees 1/30 · 1" {
+		t.Fatalf("expected a pending count, got %q", m.worktreePaneTitle())
+	}
+	keys("2j")
+	if m.worktreeTable.Cursor() != 12 {
+		t.Fatalf("expected 12j to move 12 rows, got %d", m.worktreeTable.Cursor())
+	}
+	keys("gg")
+	if m.worktreeTable.Cursor() != 0 {
+		t.Fatalf("expected gg to go to the top, got %d", m.worktreeTable.Cursor())
+	}
+	keys("L")
+	if m.worktreeTable.Cursor() != visible-1 {
+		t.Fatalf("expected L to go to the last visible row, got %d", m.worktreeTable.Cursor())
+	}
+	keys("M")
+	if m.worktreeTable.Cursor() != (visible-1)/2 {
+		t.Fatalf("expected M to go to the middle visible row, got %d", m.worktreeTable.Cursor())
+	}
+	keys("H")
+	if m.worktreeTable.Cursor() != 0 {
+		t.Fatalf("expected H to go to the first visible row, got %d", m.worktreeTable.Cursor())
+	}
+	keys("3gg")
+	if m.worktreeTable.Cursor() != 2 {
+		t.Fatalf("expected 3gg to go to the third row, got %d", m.worktreeTable.Cursor())
+	}
+	keys("G")
+	if m.worktreeTable.Cursor() != 29 || m.worktreePaneTitle() != "Worktrees 30/30" {
+		t.Fatalf("expected G to go to the bottom, got %q", m.worktreePaneTitle())
+	}
+	keys("2k")
+	if m.worktreeTable.Cursor() != 27 {
+		t.Fatalf("expected 2k to move up 2 rows, got %d", m.worktreeTable.Cursor())
+	}
+
+	// Without vim_motions the digits still focus the panes.
+	m.config.VimMotions = false
+	keys("2")
+	if m.focusedPane != 1 {
+		t.Fatalf("expected 2 to focus the status pane, got %d", m.focusedPane)
+	}
+}
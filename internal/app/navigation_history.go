@@ -0,0 +1,152 @@
+package app
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+// maxNavHistory bounds how many visited worktrees are remembered.
+const maxNavHistory = 50
+
+// navHistoryDwell is how long a worktree must stay selected before it joins
+// the back/forward history, so stepping through rows is not recorded.
+const navHistoryDwell = 1500 * time.Millisecond
+
+// navDwellMsg fires once a worktree has been selected for navHistoryDwell.
+type navDwellMsg struct {
+	path string
+	seq  int
+}
+
+// scheduleNavigation records path in the history once it has stayed
+// selected for navHistoryDwell. A later selection supersedes it.
+func (m *Model) scheduleNavigation(path string) tea.Cmd {
+	m.navDwellSeq++
+	seq := m.navDwellSeq
+	return tea.Tick(navHistoryDwell, func(time.Time) tea.Msg {
+		return navDwellMsg{path: path, seq: seq}
+	})
+}
+
+// handleNavDwell records the worktree the user settled on.
+func (m *Model) handleNavDwell(msg navDwellMsg) {
+	if msg.seq != m.navDwellSeq {
+		return
+	}
+	if wt := m.selectedWorktree(); wt != nil && wt.Path == msg.path {
+		m.recordNavigation(msg.path)
+	}
+}
+
+// recordNavigation appends a visited worktree path to the back/forward
+// history, discarding any forward entries like a browser does. The history
+// is written out on Close.
+func (m *Model) recordNavigation(path string) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return
+	}
+	if m.navHistoryPos >= 0 && m.navHistoryPos < len(m.navHistory) && m.navHistory[m.navHistoryPos] == path {
+		return
+	}
+
+	if m.navHistoryPos < len(m.navHistory)-1 {
+		m.navHistory = m.navHistory[:m.navHistoryPos+1]
+	}
+	m.navHistory = append(m.navHistory, path)
+	if len(m.navHistory) > maxNavHistory {
+		m.navHistory = m.navHistory[len(m.navHistory)-maxNavHistory:]
+	}
+	m.navHistoryPos = len(m.navHistory) - 1
+	m.navHistoryDirty = true
+}
+
+// navigateHistory moves back (delta < 0) or forward (delta > 0) through the
+// visited worktrees, skipping entries that are no longer visible.
+func (m *Model) navigateHistory(delta int) tea.Cmd {
+	if delta == 0 || len(m.navHistory) == 0 {
+		return nil
+	}
+	for pos := m.navHistoryPos + delta; pos >= 0 && pos < len(m.navHistory); pos += delta {
+		idx := m.filteredIndexForPath(m.navHistory[pos])
+		if idx < 0 {
+			continue
+		}
+		m.navHistoryPos = pos
+		m.navHistoryDirty = true
+		m.worktreeTable.SetCursor(idx)
+		m.selectedIndex = idx
+		return m.updateDetailsView()
+	}
+	if delta < 0 {
+		m.statusContent = "No earlier worktree in history"
+	} else {
+		m.statusContent = "No later worktree in history"
+	}
+	return nil
+}
+
+// filteredIndexForPath returns the index of the worktree at path in the
+// filtered list, or -1 when it is not shown.
+func (m *Model) filteredIndexForPath(path string) int {
+	for i, wt := range m.filteredWts {
+		if wt.Path == path {
+			return i
+		}
+	}
+	return -1
+}
+
+func (m *Model) loadNavHistory() {
+	repoKey := m.getRepoKey()
+	historyPath := filepath.Join(m.getWorktreeDir(), repoKey, models.NavigationHistoryFilename)
+	// #nosec G304 -- path is constructed from known safe components
+	data, err := os.ReadFile(historyPath)
+	if err != nil {
+		return
+	}
+	var payload struct {
+		Paths    []string `json:"paths"`
+		Position int      `json:"position"`
+	}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		m.debugf("failed to parse navigation history: %v", err)
+		return
+	}
+	m.navHistory = payload.Paths
+	m.navHistoryPos = payload.Position
+	if m.navHistoryPos < 0 || m.navHistoryPos >= len(m.navHistory) {
+		m.navHistoryPos = len(m.navHistory) - 1
+	}
+}
+
+func (m *Model) saveNavHistory() {
+	if !m.navHistoryDirty {
+		return
+	}
+	repoKey := m.getRepoKey()
+	historyPath := filepath.Join(m.getWorktreeDir(), repoKey, models.NavigationHistoryFilename)
+	if err := os.MkdirAll(filepath.Dir(historyPath), defaultDirPerms); err != nil {
+		m.debugf("failed to create navigation history dir: %v", err)
+		return
+	}
+	payload := struct {
+		Paths    []string `json:"paths"`
+		Position int      `json:"position"`
+	}{
+		Paths:    m.navHistory,
+		Position: m.navHistoryPos,
+	}
+	data, _ := json.Marshal(payload)
+	if err := os.WriteFile(historyPath, data, defaultFilePerms); err != nil {
+		m.debugf("failed to write navigation history: %v", err)
+		return
+	}
+	m.navHistoryDirty = false
+}
@@ -0,0 +1,130 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/chmouel/lazyworktree/internal/config"
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+func newNavHistoryModel(t *testing.T) *Model {
+	t.Helper()
+	cfg := &config.AppConfig{
+		WorktreeDir: t.TempDir(),
+	}
+	m := NewModel(cfg, "")
+	m.repoKey = testRepoKey
+	m.worktreesLoaded = true
+	m.filteredWts = []*models.WorktreeInfo{
+		{Path: "/wt/main", Branch: "main", IsMain: true},
+		{Path: "/wt/feature-a", Branch: "feature-a"},
+		{Path: "/wt/feature-b", Branch: "feature-b"},
+	}
+	m.worktrees = m.filteredWts
+	rows := make([]table.Row, 0, len(m.filteredWts))
+	for _, wt := range m.filteredWts {
+		rows = append(rows, table.Row{wt.Branch, "", "", ""})
+	}
+	m.worktreeTable.SetRows(rows)
+	return m
+}
+
+func TestRecordNavigationSkipsDuplicatesAndTruncatesForward(t *testing.T) {
+	m := newNavHistoryModel(t)
+
+	m.recordNavigation("/wt/main")
+	m.recordNavigation("/wt/main")
+	m.recordNavigation("/wt/feature-a")
+	m.recordNavigation("/wt/feature-b")
+	if len(m.navHistory) != 3 || m.navHistoryPos != 2 {
+		t.Fatalf("unexpected history %v at %d", m.navHistory, m.navHistoryPos)
+	}
+
+	m.navHistoryPos = 0
+	m.recordNavigation("/wt/feature-b")
+	if len(m.navHistory) != 2 || m.navHistory[1] != "/wt/feature-b" || m.navHistoryPos != 1 {
+		t.Fatalf("expected forward entries to be discarded, got %v at %d", m.navHistory, m.navHistoryPos)
+	}
+}
+
+func TestNavigateHistoryBackAndForward(t *testing.T) {
+	m := newNavHistoryModel(t)
+	m.recordNavigation("/wt/main")
+	m.recordNavigation("/wt/feature-b")
+	m.recordNavigation("/wt/feature-a")
+
+	m.handleBuiltInKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'<'}})
+	if m.selectedIndex != 2 || m.navHistoryPos != 1 {
+		t.Fatalf("expected back to feature-b, got index %d pos %d", m.selectedIndex, m.navHistoryPos)
+	}
+
+	m.handleBuiltInKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'<'}})
+	if m.selectedIndex != 0 || m.navHistoryPos != 0 {
+		t.Fatalf("expected back to main, got index %d pos %d", m.selectedIndex, m.navHistoryPos)
+	}
+
+	if cmd := m.navigateHistory(-1); cmd != nil {
+		t.Fatal("expected no command at the start of history")
+	}
+
+	m.handleBuiltInKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'>'}})
+	if m.selectedIndex != 2 || m.navHistoryPos != 1 {
+		t.Fatalf("expected forward to feature-b, got index %d pos %d", m.selectedIndex, m.navHistoryPos)
+	}
+	if len(m.navHistory) != 3 {
+		t.Fatalf("navigating must not rewrite history, got %v", m.navHistory)
+	}
+}
+
+func TestNavigateHistorySkipsHiddenWorktrees(t *testing.T) {
+	m := newNavHistoryModel(t)
+	m.recordNavigation("/wt/feature-a")
+	m.recordNavigation("/wt/gone")
+	m.navHistory = append(m.navHistory, "/wt/feature-b")
+	m.navHistoryPos = 2
+
+	m.navigateHistory(-1)
+	if m.selectedIndex != 1 || m.navHistoryPos != 0 {
+		t.Fatalf("expected to skip missing worktree, got index %d pos %d", m.selectedIndex, m.navHistoryPos)
+	}
+}
+
+func TestNavHistoryPersistence(t *testing.T) {
+	m := newNavHistoryModel(t)
+	m.recordNavigation("/wt/main")
+	m.recordNavigation("/wt/feature-a")
+	m.navigateHistory(-1)
+	m.Close()
+
+	m2 := NewModel(m.config, "")
+	m2.repoKey = testRepoKey
+	m2.loadNavHistory()
+	if len(m2.navHistory) != 2 || m2.navHistoryPos != 0 {
+		t.Fatalf("expected persisted history, got %v at %d", m2.navHistory, m2.navHistoryPos)
+	}
+}
+
+func TestNavigationRecordsOnlySettledSelections(t *testing.T) {
+	m := newNavHistoryModel(t)
+
+	m.worktreeTable.SetCursor(1)
+	m.updateDetailsView()
+	stale := navDwellMsg{path: "/wt/feature-a", seq: m.navDwellSeq}
+	m.worktreeTable.SetCursor(2)
+	m.updateDetailsView()
+	if len(m.navHistory) != 0 {
+		t.Fatalf("expected cursor moves not to be recorded straight away, got %v", m.navHistory)
+	}
+
+	m.handleNavDwell(stale)
+	if len(m.navHistory) != 0 {
+		t.Fatalf("expected a superseded selection to be skipped, got %v", m.navHistory)
+	}
+
+	m.handleNavDwell(navDwellMsg{path: "/wt/feature-b", seq: m.navDwellSeq})
+	if len(m.navHistory) != 1 || m.navHistory[0] != "/wt/feature-b" {
+		t.Fatalf("expected the settled selection to be recorded, got %v", m.navHistory)
+	}
+}
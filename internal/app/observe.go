@@ -0,0 +1,131 @@
+package app
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+// observeFeedLimit bounds the changes kept in the observe mode feed.
+const observeFeedLimit = 100
+
+// observeMarker follows the name of the worktree that changed last in
+// observe mode.
+const observeMarker = "◂"
+
+// observeEvent is a change to a worktree seen in observe mode.
+type observeEvent struct {
+	at   time.Time
+	name string
+	text string
+}
+
+// observing reports whether lazyworktree was started with --observe.
+func (m *Model) observing() bool {
+	return m.config != nil && m.config.Observe
+}
+
+// observeChanges records the worktrees added and removed between two scans,
+// and the checkouts and commits of those in both.
+func (m *Model) observeChanges(before, after []*models.WorktreeInfo) {
+	if !m.observing() {
+		return
+	}
+	known := make(map[string]*models.WorktreeInfo, len(before))
+	for _, wt := range before {
+		known[wt.Path] = wt
+	}
+	current := make(map[string]bool, len(after))
+	for _, wt := range after {
+		current[wt.Path] = true
+		if old, ok := known[wt.Path]; ok {
+			m.observeWorktree(old, wt)
+			continue
+		}
+		m.observe(wt, "added on "+observeRef(wt))
+	}
+	for _, wt := range before {
+		if !current[wt.Path] {
+			m.observe(wt, "removed")
+		}
+	}
+}
+
+// observeWorktree records a checkout, or HEAD moving on the same branch,
+// between two states of a worktree.
+func (m *Model) observeWorktree(before, after *models.WorktreeInfo) {
+	switch {
+	case !m.observing():
+	case before.Branch != after.Branch:
+		m.observe(after, fmt.Sprintf("checked out %s (was %s)", observeRef(after), observeRef(before)))
+	case before.Head != "" && before.Head != after.Head:
+		m.observe(after, fmt.Sprintf("HEAD %s → %s", shortHead(before.Head), shortHead(after.Head)))
+	}
+}
+
+// observe adds a change to the feed and marks wt as the latest changed.
+func (m *Model) observe(wt *models.WorktreeInfo, text string) {
+	name := filepath.Base(wt.Path)
+	if wt.IsMain {
+		name = mainWorktreeName
+	}
+	m.observeFeed = append([]observeEvent{{at: time.Now(), name: name, text: text}}, m.observeFeed...)
+	if len(m.observeFeed) > observeFeedLimit {
+		m.observeFeed = m.observeFeed[:observeFeedLimit]
+	}
+	m.observeLatest = wt.Path
+}
+
+// observeRef names what a worktree has checked out: its branch, or the
+// commit when detached.
+func observeRef(wt *models.WorktreeInfo) string {
+	if wt.Branch == "" || wt.Detached {
+		return shortHead(wt.Head)
+	}
+	return wt.Branch
+}
+
+func shortHead(head string) string {
+	if len(head) > 7 {
+		return head[:7]
+	}
+	return head
+}
+
+// renderObservePane renders the feed of changes, newest first, in place of
+// the log pane.
+func (m *Model) renderObservePane(layout layoutDims, focused bool, height int) string {
+	title := m.renderPaneTitle(3, "Activity", focused, layout.rightInnerWidth)
+	feed := m.observeFeedView(layout.rightInnerWidth, height-3)
+	key := renderKey{theme: m.theme, width: layout.rightWidth, height: height, focused: focused, parts: [3]string{title, feed}}
+	return m.renders.panes[2].render(key, func() string {
+		content := lipgloss.JoinVertical(lipgloss.Left, title, feed)
+		return m.paneStyle(focused).
+			Width(layout.rightWidth).
+			Height(height).
+			Render(content)
+	})
+}
+
+// observeFeedView renders up to height changes, each cut to width.
+func (m *Model) observeFeedView(width, height int) string {
+	mutedStyle := lipgloss.NewStyle().Foreground(m.theme.MutedFg)
+	if len(m.observeFeed) == 0 {
+		return mutedStyle.Render("Watching for checkouts and commits in any worktree...")
+	}
+	nameStyle := lipgloss.NewStyle().Foreground(m.theme.Cyan)
+	lineStyle := lipgloss.NewStyle().MaxWidth(width)
+	lines := make([]string, 0, min(len(m.observeFeed), max(height, 1)))
+	for _, ev := range m.observeFeed {
+		if len(lines) >= max(height, 1) {
+			break
+		}
+		line := fmt.Sprintf("%s  %s  %s", mutedStyle.Render(ev.at.Format("15:04:05")), nameStyle.Render(ev.name), ev.text)
+		lines = append(lines, lineStyle.Render(line))
+	}
+	return strings.Join(lines, "\n")
+}
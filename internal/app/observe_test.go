@@ -0,0 +1,55 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/chmouel/lazyworktree/internal/config"
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+func TestObserveMode(t *testing.T) {
+	m := NewModel(&config.AppConfig{WorktreeDir: t.TempDir(), Observe: true, ReadOnly: true}, "")
+	m.windowWidth = 200
+	m.windowHeight = 50
+	before := []*models.WorktreeInfo{
+		{Path: "/repo", Branch: "main", Head: "1111111aaaa", IsMain: true},
+		{Path: "/wt/feature", Branch: "feature", Head: "2222222bbbb"},
+		{Path: "/wt/old", Branch: "old", Head: "3333333cccc"},
+	}
+	after := []*models.WorktreeInfo{
+		{Path: "/repo", Branch: "main", Head: "1111111aaaa", IsMain: true},
+		{Path: "/wt/feature", Branch: "feature", Head: "4444444dddd"},
+		{Path: "/wt/new", Branch: "new", Head: "5555555eeee"},
+	}
+	m.observeChanges(before, after)
+	m.observeWorktree(after[2], &models.WorktreeInfo{Path: "/wt/new", Branch: "other", Head: "5555555eeee"})
+
+	var texts []string
+	for _, ev := range m.observeFeed {
+		texts = append(texts, ev.name+": "+ev.text)
+	}
+	want := []string{"new: checked out other (was new)", "old: removed", "new: added on new", "feature: HEAD 2222222 → 4444444"}
+	if strings.Join(texts, "|") != strings.Join(want, "|") {
+		t.Fatalf("expected the feed newest first\n%v\ngot\n%v", want, texts)
+	}
+
+	m.worktrees = after
+	m.updateTable()
+	for i, wt := range m.filteredWts {
+		if marked := strings.HasSuffix(m.worktreeTable.Rows()[i][0], observeMarker); marked != (wt.Path == "/wt/new") {
+			t.Fatalf("expected only the latest changed worktree marked, got %q", m.worktreeTable.Rows()[i][0])
+		}
+	}
+
+	layout := m.computeLayout()
+	if pane := m.renderLogPane(layout, false, layout.rightBottomHeight); !strings.Contains(pane, "Activity") || !strings.Contains(pane, "checked out other") {
+		t.Fatalf("expected the feed in place of the log, got %q", pane)
+	}
+	if header := m.renderHeader(layout); !strings.Contains(header, "observing") {
+		t.Fatalf("expected the header to mention observing, got %q", header)
+	}
+	if !m.readOnlyDenied("Creating worktrees") || !strings.Contains(m.infoScreen.message, "--observe") {
+		t.Fatalf("expected observe mode to refuse changes, got %q", m.infoScreen.message)
+	}
+}
@@ -0,0 +1,89 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/chmouel/lazyworktree/internal/git"
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+// osUserName is the name of the user running lazyworktree.
+func osUserName() string {
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	if name := os.Getenv("USER"); name != "" {
+		return name
+	}
+	return os.Getenv("USERNAME")
+}
+
+// currentOwner is the identity recorded on the worktrees this user creates.
+func (m *Model) currentOwner() string {
+	if m.config.OwnerIdentity != "" {
+		return m.config.OwnerIdentity
+	}
+	return osUserName()
+}
+
+// ownedByOther reports whether someone else created the worktree. Owners
+// are either a recorded identity or a directory's OS user, so both names
+// count as ours.
+func (m *Model) ownedByOther(wt *models.WorktreeInfo) bool {
+	if wt == nil || wt.Owner == "" {
+		return false
+	}
+	return wt.Owner != m.currentOwner() && wt.Owner != osUserName()
+}
+
+// recordOwner records the current identity as the creator of the new
+// worktree at path.
+func (m *Model) recordOwner(path string) {
+	if err := git.RecordWorktreeOwner(path, m.currentOwner()); err != nil {
+		m.debugf("record owner of %s: %v", path, err)
+	}
+}
+
+// showOwnerColumn reports whether the worktree table has an Owner column,
+// which it has once someone else's worktree is listed.
+func (m *Model) showOwnerColumn() bool {
+	for _, wt := range m.worktrees {
+		if m.ownedByOther(wt) {
+			return true
+		}
+	}
+	return false
+}
+
+// ownerCell renders the Owner column for a worktree.
+func (m *Model) ownerCell(wt *models.WorktreeInfo) string {
+	if wt.Owner == "" {
+		return "-"
+	}
+	return wt.Owner
+}
+
+// ownerWarning is added to confirmations that act on someone else's
+// worktree.
+func (m *Model) ownerWarning(wt *models.WorktreeInfo) string {
+	if !m.ownedByOther(wt) {
+		return ""
+	}
+	return fmt.Sprintf("\n\nWarning: this worktree belongs to %s, not to you (%s).", wt.Owner, m.currentOwner())
+}
+
+// toggleOwnerFilter lists only the worktrees created by the current user,
+// or every worktree again.
+func (m *Model) toggleOwnerFilter() tea.Cmd {
+	m.ownerFilter = !m.ownerFilter
+	m.updateTable()
+	if m.ownerFilter {
+		m.statusContent = fmt.Sprintf("Showing worktrees of %s (%d); run \"Show only my worktrees\" again or press Esc to show all", m.currentOwner(), len(m.filteredWts))
+	} else {
+		m.statusContent = "Showing every owner's worktrees"
+	}
+	return m.updateDetailsView()
+}
@@ -0,0 +1,52 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/chmouel/lazyworktree/internal/config"
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+func TestWorktreeOwners(t *testing.T) {
+	cfg := &config.AppConfig{WorktreeDir: t.TempDir(), OwnerIdentity: "alice"}
+	m := NewModel(cfg, "")
+	mine := &models.WorktreeInfo{Path: "/repo/wt/login", Branch: "login", Owner: "alice"}
+	theirs := &models.WorktreeInfo{Path: "/repo/wt/search", Branch: "search", Owner: "bob"}
+	main := &models.WorktreeInfo{Path: "/repo", Branch: "main", IsMain: true, Owner: "root"}
+	m.worktrees = []*models.WorktreeInfo{main, mine, theirs}
+	m.updateTable()
+
+	if m.ownedByOther(mine) || !m.ownedByOther(theirs) {
+		t.Fatal("expected the configured identity to tell the owners apart")
+	}
+	columns := m.worktreeTable.Columns()
+	if len(columns) == 0 || columns[len(columns)-1].Title != "Owner" {
+		t.Fatalf("expected an Owner column, got %+v", columns)
+	}
+
+	m.toggleOwnerFilter()
+	if len(m.filteredWts) != 2 || m.filteredWts[1] != mine {
+		t.Fatalf("expected the main worktree and mine, got %d worktrees", len(m.filteredWts))
+	}
+	if !m.hasActiveFilterForPane(0) {
+		t.Fatal("expected the owner filter to count as a filter")
+	}
+	m.clearCurrentPaneFilter()
+	if m.ownerFilter || len(m.filteredWts) != 3 {
+		t.Fatal("expected Esc to list every owner's worktrees")
+	}
+
+	if got := m.ownerWarning(theirs); !strings.Contains(got, "belongs to bob, not to you (alice)") {
+		t.Fatalf("unexpected warning %q", got)
+	}
+	if m.ownerWarning(mine) != "" {
+		t.Fatal("expected no warning for my own worktree")
+	}
+
+	theirs.Owner, main.Owner = "alice", "alice"
+	m.updateTable()
+	if m.showOwnerColumn() {
+		t.Fatal("expected no Owner column when every worktree is mine")
+	}
+}
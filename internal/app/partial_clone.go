@@ -0,0 +1,126 @@
+package app
+
+import (
+	"fmt"
+	"path/filepath"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/chmouel/lazyworktree/internal/git"
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+const (
+	// partialCloneWarnObjects is how many missing file versions a commit
+	// diff may download before lazyworktree asks first.
+	partialCloneWarnObjects = 100
+	// prefetchCommitLimit matches the commits the log pane lists.
+	prefetchCommitLimit = 50
+)
+
+// partialCloneMsg reports whether the repository is a partial clone.
+type partialCloneMsg struct {
+	info git.PartialClone
+}
+
+// prefetchDoneMsg reports how many missing objects were downloaded.
+type prefetchDoneMsg struct {
+	name    string
+	fetched int
+	err     error
+}
+
+// detectPartialClone reads the repository's promisor remote once, in the
+// background.
+func (m *Model) detectPartialClone() tea.Cmd {
+	if m.partialCloneChecked {
+		return nil
+	}
+	m.partialCloneChecked = true
+	return func() tea.Msg {
+		return partialCloneMsg{info: m.git.PartialClone(m.ctx)}
+	}
+}
+
+// partialCloneSummary renders the partial clone state for the header.
+func (m *Model) partialCloneSummary() string {
+	if !m.partialClone.Enabled() {
+		return ""
+	}
+	if m.partialClone.Filter == "" {
+		return "partial clone"
+	}
+	return fmt.Sprintf("partial clone (%s)", m.partialClone.Filter)
+}
+
+// showCommitDiff shows a commit's diff, first asking when a partial clone
+// would have to download many file versions to render it.
+func (m *Model) showCommitDiff(commitSHA string, wt *models.WorktreeInfo) tea.Cmd {
+	if m.partialClone.Enabled() {
+		missing := len(m.git.MissingObjects(m.ctx, wt.Path, []string{commitSHA + "^!"}, nil))
+		if missing >= partialCloneWarnObjects {
+			short := commitSHA
+			if len(short) > 7 {
+				short = short[:7]
+			}
+			m.confirmScreen = NewConfirmScreen(fmt.Sprintf("Commit %s touches %d file versions this partial clone does not hold.\n\nShowing its diff downloads them, and their previous versions, from %s. Continue?", short, missing, m.partialClone.Remote), m.theme)
+			m.confirmAction = func() tea.Cmd { return m.openCommitDiff(commitSHA, wt) }
+			m.currentScreen = screenConfirm
+			return nil
+		}
+	}
+	return m.openCommitDiff(commitSHA, wt)
+}
+
+// prefetchBlobs downloads the file versions the selected worktree's recent
+// commits need, within its sparse directories, so their diffs open without
+// waiting on the network.
+func (m *Model) prefetchBlobs() tea.Cmd {
+	wt := m.selectedWorktree()
+	if wt == nil {
+		return nil
+	}
+	if !m.partialClone.Enabled() {
+		m.showInfo("This repository is not a partial clone; every file version is already local.", nil)
+		return nil
+	}
+	name := filepath.Base(wt.Path)
+	if wt.Bare || wt.Prunable {
+		m.showInfo(fmt.Sprintf("%s has no working tree to prefetch for.", name), nil)
+		return nil
+	}
+	pc := m.partialClone
+	path := wt.Path
+	sparse := wt.Sparse
+	m.loading = true
+	m.loadingScreen = NewLoadingScreen(fmt.Sprintf("Prefetching blobs for %s...", name), m.theme)
+	m.currentScreen = screenLoading
+	return func() tea.Msg {
+		var paths []string
+		if sparse {
+			paths = m.git.SparseCheckoutDirs(m.ctx, path)
+		}
+		revs := []string{fmt.Sprintf("--max-count=%d", prefetchCommitLimit), "HEAD"}
+		missing := m.git.MissingObjects(m.ctx, path, revs, paths)
+		if err := m.git.FetchObjects(m.ctx, path, pc, missing); err != nil {
+			return prefetchDoneMsg{name: name, err: err}
+		}
+		return prefetchDoneMsg{name: name, fetched: len(missing)}
+	}
+}
+
+// handlePrefetchDone reports the outcome of prefetchBlobs.
+func (m *Model) handlePrefetchDone(msg prefetchDoneMsg) {
+	m.loading = false
+	if m.currentScreen == screenLoading {
+		m.currentScreen = screenNone
+		m.loadingScreen = nil
+	}
+	switch {
+	case msg.err != nil:
+		m.showInfo(fmt.Sprintf("Error: %v", msg.err), nil)
+	case msg.fetched == 0:
+		m.statusContent = fmt.Sprintf("%s already holds the blobs of its last %d commits", msg.name, prefetchCommitLimit)
+	default:
+		m.statusContent = fmt.Sprintf("Prefetched %d blobs for %s", msg.fetched, msg.name)
+	}
+}
@@ -0,0 +1,121 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/chmouel/lazyworktree/internal/config"
+	"github.com/chmouel/lazyworktree/internal/git"
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+// initPartialClone clones a repository whose single commit holds files
+// blobs, without downloading any of them.
+func initPartialClone(t *testing.T, files int) string {
+	t.Helper()
+	source := t.TempDir()
+	runGit(t, source, "init", "-b", "main")
+	runGit(t, source, "config", "user.email", "test@example.com")
+	runGit(t, source, "config", "user.name", "Test User")
+	runGit(t, source, "config", "commit.gpgsign", "false")
+	runGit(t, source, "config", "uploadpack.allowFilter", "true")
+	runGit(t, source, "config", "uploadpack.allowAnySHA1InWant", "true")
+	if err := os.MkdirAll(filepath.Join(source, "data"), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	for i := range files {
+		name := filepath.Join(source, "data", fmt.Sprintf("file%03d.txt", i))
+		if err := os.WriteFile(name, []byte(name+"\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	runGit(t, source, "add", ".")
+	runGit(t, source, "commit", "-m", "Initial commit")
+
+	clone := filepath.Join(t.TempDir(), "clone")
+	runGit(t, source, "clone", "--quiet", "--filter=blob:none", "--no-checkout", "file://"+source, clone)
+	return clone
+}
+
+func newPartialCloneModel(t *testing.T, clone string) *Model {
+	t.Helper()
+	withCwd(t, clone)
+	m := NewModel(&config.AppConfig{WorktreeDir: t.TempDir()}, "")
+	m.worktrees = []*models.WorktreeInfo{{Path: clone, Branch: "main", IsMain: true}}
+	m.filteredWts = m.worktrees
+	m.selectedIndex = 0
+	msg, ok := m.detectPartialClone()().(partialCloneMsg)
+	if !ok {
+		t.Fatal("expected a partial clone message")
+	}
+	m.partialClone = msg.info
+	if m.detectPartialClone() != nil {
+		t.Fatal("expected detection to run once")
+	}
+	return m
+}
+
+func TestPartialCloneSummary(t *testing.T) {
+	m := NewModel(&config.AppConfig{WorktreeDir: t.TempDir()}, "")
+	if got := m.partialCloneSummary(); got != "" {
+		t.Fatalf("expected no summary for a full clone, got %q", got)
+	}
+	m.partialClone = git.PartialClone{Remote: "origin", Filter: "blob:none"}
+	if got := m.partialCloneSummary(); got != "partial clone (blob:none)" {
+		t.Fatalf("unexpected summary %q", got)
+	}
+}
+
+func TestCommitDiffWarnsInPartialClone(t *testing.T) {
+	clone := initPartialClone(t, partialCloneWarnObjects)
+	m := newPartialCloneModel(t, clone)
+	if !m.partialClone.Enabled() {
+		t.Fatal("expected the clone to be detected as partial")
+	}
+
+	sha := strings.TrimSpace(runGit(t, clone, "rev-parse", "HEAD"))
+	if cmd := m.showCommitDiff(sha, m.worktrees[0]); cmd != nil || m.currentScreen != screenConfirm {
+		t.Fatalf("expected a confirmation, got %s", screenName(m.currentScreen))
+	}
+	if !strings.Contains(m.confirmScreen.message, "from origin") {
+		t.Fatalf("unexpected message %q", m.confirmScreen.message)
+	}
+	if m.confirmAction == nil {
+		t.Fatal("expected the diff to be offered")
+	}
+}
+
+func TestPrefetchBlobs(t *testing.T) {
+	clone := initPartialClone(t, 3)
+	m := newPartialCloneModel(t, clone)
+
+	cmd := m.prefetchBlobs()
+	if cmd == nil || m.currentScreen != screenLoading {
+		t.Fatalf("expected the prefetch to start, got %s", screenName(m.currentScreen))
+	}
+	done, ok := cmd().(prefetchDoneMsg)
+	if !ok || done.err != nil || done.fetched != 3 {
+		t.Fatalf("unexpected result %+v", done)
+	}
+	m.handlePrefetchDone(done)
+	if m.currentScreen != screenNone || !strings.Contains(m.statusContent, "Prefetched 3 blobs") {
+		t.Fatalf("unexpected status %q", m.statusContent)
+	}
+
+	done, _ = m.prefetchBlobs()().(prefetchDoneMsg)
+	if done.fetched != 0 {
+		t.Fatalf("expected nothing left to fetch, got %d", done.fetched)
+	}
+}
+
+func TestPrefetchBlobsFullClone(t *testing.T) {
+	m := NewModel(&config.AppConfig{WorktreeDir: t.TempDir()}, "")
+	m.worktrees = []*models.WorktreeInfo{{Path: t.TempDir(), Branch: "main"}}
+	m.filteredWts = m.worktrees
+	if cmd := m.prefetchBlobs(); cmd != nil || m.currentScreen != screenInfo {
+		t.Fatal("expected a full clone to be refused")
+	}
+}
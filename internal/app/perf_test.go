@@ -0,0 +1,172 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chmouel/lazyworktree/internal/config"
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+// perfBudgetsEnv enables TestPerformanceBudgets, which is too slow and too
+// sensitive to machine load for the default test run.
+const perfBudgetsEnv = "LAZYWORKTREE_PERF_BUDGETS"
+
+func benchWorktrees(n int) []*models.WorktreeInfo {
+	worktrees := make([]*models.WorktreeInfo, 0, n)
+	now := time.Now().Unix()
+	for i := range n {
+		worktrees = append(worktrees, &models.WorktreeInfo{
+			Path:           fmt.Sprintf("/worktrees/repo/feature-%04d", i),
+			Branch:         fmt.Sprintf("feature-%04d", i),
+			IsMain:         i == 0,
+			Dirty:          i%3 == 0,
+			Ahead:          i % 5,
+			Behind:         i % 7,
+			HasUpstream:    i%2 == 0,
+			UpstreamBranch: fmt.Sprintf("origin/feature-%04d", i),
+			LastActive:     "2 days ago",
+			LastActiveTS:   now - int64(i*60),
+			LastSwitchedTS: now - int64(i*30),
+		})
+	}
+	return worktrees
+}
+
+func benchStatusFiles(n int) []StatusFile {
+	files := make([]StatusFile, 0, n)
+	for i := range n {
+		files = append(files, StatusFile{
+			Filename:    fmt.Sprintf("pkg%02d/sub%02d/deep/file%05d.go", i%40, i%13, i),
+			Status:      ".M",
+			IsUntracked: i%10 == 0,
+		})
+	}
+	return files
+}
+
+func benchBranchRaw(n int) string {
+	var b strings.Builder
+	now := time.Now().Unix()
+	for i := range n {
+		switch i % 3 {
+		case 0:
+			fmt.Fprintf(&b, "branch-%05d\trefs/heads/branch-%05d\t%d\n", i, i, now-int64(i))
+		case 1:
+			fmt.Fprintf(&b, "origin/branch-%05d\trefs/remotes/origin/branch-%05d\t%d\n", i, i, now-int64(i*7%n))
+		default:
+			fmt.Fprintf(&b, "v%d.%d.%d\trefs/tags/v%d.%d.%d\t%d\n", i/100, i/10%10, i%10, i/100, i/10%10, i%10, now-int64(i*3%n))
+		}
+	}
+	b.WriteString("main\trefs/heads/main\t0\norigin/HEAD\trefs/remotes/origin/HEAD\t0\n")
+	return b.String()
+}
+
+func BenchmarkUpdateTable1k(b *testing.B) {
+	m := NewModel(&config.AppConfig{WorktreeDir: b.TempDir()}, "")
+	m.windowWidth = 200
+	m.windowHeight = 60
+	m.worktrees = benchWorktrees(1000)
+	b.ResetTimer()
+	for range b.N {
+		m.updateTable()
+	}
+}
+
+func BenchmarkBuildStatusTree10k(b *testing.B) {
+	files := benchStatusFiles(10000)
+	b.ResetTimer()
+	for range b.N {
+		buildStatusTree(files)
+	}
+}
+
+func BenchmarkFlattenStatusTree10k(b *testing.B) {
+	root := buildStatusTree(benchStatusFiles(10000))
+	collapsed := map[string]bool{"pkg01/sub01/deep": true}
+	b.ResetTimer()
+	for range b.N {
+		flattenStatusTree(root, collapsed, 0)
+	}
+}
+
+func BenchmarkParseBranchOptions5k(b *testing.B) {
+	raw := benchBranchRaw(5000)
+	b.ResetTimer()
+	for range b.N {
+		parseBranchOptionsWithDate(raw)
+	}
+}
+
+func BenchmarkSortBranchOptions5k(b *testing.B) {
+	options := parseBranchOptionsWithDate(benchBranchRaw(5000))
+	work := make([]branchOption, len(options))
+	b.ResetTimer()
+	for range b.N {
+		copy(work, options)
+		sortBranchOptions(work)
+	}
+}
+
+func BenchmarkViewUnchanged(b *testing.B) {
+	m := NewModel(&config.AppConfig{WorktreeDir: b.TempDir()}, "")
+	m.windowWidth = 200
+	m.windowHeight = 60
+	m.worktrees = benchWorktrees(1000)
+	m.updateTable()
+	m.View()
+	b.ResetTimer()
+	for range b.N {
+		m.View()
+	}
+}
+
+// TestPerformanceBudgets fails when a hot path exceeds the time per
+// operation recorded in testdata/perf_budgets.json. Budgets are generous so
+// only real regressions trip them; run with LAZYWORKTREE_PERF_BUDGETS=1.
+func TestPerformanceBudgets(t *testing.T) {
+	if os.Getenv(perfBudgetsEnv) == "" {
+		t.Skipf("set %s=1 to check performance budgets", perfBudgetsEnv)
+	}
+
+	data, err := os.ReadFile(filepath.Join("testdata", "perf_budgets.json"))
+	if err != nil {
+		t.Fatalf("failed to read budgets: %v", err)
+	}
+	var budgets map[string]string
+	if err := json.Unmarshal(data, &budgets); err != nil {
+		t.Fatalf("failed to parse budgets: %v", err)
+	}
+
+	benchmarks := map[string]func(*testing.B){
+		"UpdateTable1k":        BenchmarkUpdateTable1k,
+		"BuildStatusTree10k":   BenchmarkBuildStatusTree10k,
+		"FlattenStatusTree10k": BenchmarkFlattenStatusTree10k,
+		"ParseBranchOptions5k": BenchmarkParseBranchOptions5k,
+		"SortBranchOptions5k":  BenchmarkSortBranchOptions5k,
+		"ViewUnchanged":        BenchmarkViewUnchanged,
+	}
+	for name, bench := range benchmarks {
+		t.Run(name, func(t *testing.T) {
+			raw, ok := budgets[name]
+			if !ok {
+				t.Fatalf("no budget recorded for %s", name)
+			}
+			budget, err := time.ParseDuration(raw)
+			if err != nil {
+				t.Fatalf("invalid budget %q for %s: %v", raw, name, err)
+			}
+			result := testing.Benchmark(bench)
+			perOp := time.Duration(result.NsPerOp())
+			t.Logf("%s: %s/op (budget %s)", name, perOp, budget)
+			if perOp > budget {
+				t.Fatalf("%s took %s/op, over its %s budget", name, perOp, budget)
+			}
+		})
+	}
+}
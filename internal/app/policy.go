@@ -0,0 +1,98 @@
+package app
+
+import (
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/chmouel/lazyworktree/internal/policy"
+	"github.com/chmouel/lazyworktree/internal/utils"
+)
+
+// diskUsageMsg reports the measured size of the repository's worktree
+// directory.
+type diskUsageMsg struct {
+	size      int64
+	worktrees int
+	err       error
+}
+
+// worktreePolicy returns the configured quotas and naming rules. The
+// configuration is validated at startup, so an invalid policy is ignored.
+func (m *Model) worktreePolicy() policy.Policy {
+	if m.policy == nil {
+		p, err := m.config.Policy()
+		if err != nil {
+			m.debugf("worktree policy: %v", err)
+		}
+		m.policy = &p
+	}
+	return *m.policy
+}
+
+// quotaUsage counts the worktrees besides the main one and returns the last
+// measured disk usage.
+func (m *Model) quotaUsage() policy.Usage {
+	usage := policy.Usage{DiskBytes: -1}
+	for _, wt := range m.worktrees {
+		if !wt.IsMain {
+			usage.Worktrees++
+		}
+	}
+	if !m.diskUsageAt.IsZero() {
+		usage.DiskBytes = m.diskUsage
+	}
+	return usage
+}
+
+// policyViolation returns why the policy forbids creating a worktree for
+// branch based on base, ready to show to the user. An empty branch or base
+// skips the rule about it.
+func (m *Model) policyViolation(branch, base string) string {
+	return policyMessage(m.worktreePolicy().CheckCreate(m.quotaUsage(), branch, base))
+}
+
+// policyMessage turns a policy error into a sentence, or "" for nil.
+func policyMessage(err error) string {
+	if err == nil {
+		return ""
+	}
+	msg := err.Error()
+	r, size := utf8.DecodeRuneInString(msg)
+	return string(unicode.ToUpper(r)) + msg[size:] + "."
+}
+
+// quotaSummary renders usage against the quotas for the header.
+func (m *Model) quotaSummary() string {
+	return m.worktreePolicy().Summary(m.quotaUsage())
+}
+
+// measureDiskUsage sizes the repository's worktree directory in the
+// background when a disk quota is set. A measurement is reused for
+// diskSizeTTL unless worktrees were added or removed since.
+func (m *Model) measureDiskUsage() tea.Cmd {
+	if m.measuringDiskUsage || !m.worktreePolicy().NeedsDiskUsage() {
+		return nil
+	}
+	worktrees := m.quotaUsage().Worktrees
+	if !m.diskUsageAt.IsZero() && worktrees == m.diskUsageWorktrees && time.Since(m.diskUsageAt) < diskSizeTTL {
+		return nil
+	}
+	m.measuringDiskUsage = true
+	dir := m.getRepoWorktreeDir()
+	return func() tea.Msg {
+		size, err := utils.DirSize(dir)
+		return diskUsageMsg{size: size, worktrees: worktrees, err: err}
+	}
+}
+
+// handleDiskUsage records a disk usage measurement.
+func (m *Model) handleDiskUsage(msg diskUsageMsg) {
+	m.measuringDiskUsage = false
+	if msg.err != nil {
+		m.debugf("measuring worktree disk usage: %v", msg.err)
+		return
+	}
+	m.diskUsage, m.diskUsageAt, m.diskUsageWorktrees = msg.size, time.Now(), msg.worktrees
+}
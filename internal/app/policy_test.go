@@ -0,0 +1,87 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/chmouel/lazyworktree/internal/config"
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+func TestBranchNameInputEnforcesPolicy(t *testing.T) {
+	cfg := &config.AppConfig{
+		WorktreeDir:        t.TempDir(),
+		MaxWorktrees:       2,
+		BranchNamePattern:  "feature-.+",
+		BannedBaseBranches: []string{"production"},
+	}
+	m := NewModel(cfg, "")
+	m.worktrees = []*models.WorktreeInfo{
+		{Path: t.TempDir(), Branch: "main", IsMain: true},
+		{Path: t.TempDir(), Branch: "feature-one"},
+	}
+
+	m.showBranchNameInput("production", "")
+	if _, ok := m.inputSubmit("feature-two", false); ok {
+		t.Fatal("expected a banned base branch to be refused")
+	}
+	if !strings.Contains(m.inputScreen.errorMsg, "banned_base_branches") {
+		t.Fatalf("expected banned base error, got %q", m.inputScreen.errorMsg)
+	}
+
+	m.showBranchNameInput("main", "")
+	if _, ok := m.inputSubmit("two", false); ok {
+		t.Fatal("expected a branch outside the naming pattern to be refused")
+	}
+	if !strings.HasPrefix(m.inputScreen.errorMsg, "Branch \"two\" does not match branch_name_pattern") {
+		t.Fatalf("expected naming error, got %q", m.inputScreen.errorMsg)
+	}
+
+	m.worktrees = append(m.worktrees, &models.WorktreeInfo{Path: t.TempDir(), Branch: "feature-three"})
+	if _, ok := m.inputSubmit("feature-two", false); ok {
+		t.Fatal("expected the worktree quota to be enforced")
+	}
+	if !strings.Contains(m.inputScreen.errorMsg, "2 of the 2 worktrees allowed by max_worktrees") {
+		t.Fatalf("expected quota error, got %q", m.inputScreen.errorMsg)
+	}
+}
+
+func TestDiskUsageQuota(t *testing.T) {
+	cfg := &config.AppConfig{WorktreeDir: t.TempDir(), MaxDiskUsage: "1K"}
+	m := NewModel(cfg, "")
+	m.repoKey = "example/repo"
+	if err := os.MkdirAll(m.getRepoWorktreeDir(), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(m.getRepoWorktreeDir(), "big"), make([]byte, 2048), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if msg := m.policyViolation("any", ""); msg != "" {
+		t.Fatalf("expected unmeasured usage not to block creation, got %q", msg)
+	}
+	if header := m.renderHeader(layoutDims{width: 120}); !strings.Contains(header, "…/1.0 KiB") {
+		t.Fatalf("expected the header to show pending usage, got %q", header)
+	}
+
+	cmd := m.measureDiskUsage()
+	if cmd == nil {
+		t.Fatal("expected disk usage to be measured")
+	}
+	if m.measureDiskUsage() != nil {
+		t.Fatal("expected a single measurement at a time")
+	}
+	m.handleDiskUsage(cmd().(diskUsageMsg))
+	if m.measureDiskUsage() != nil {
+		t.Fatal("expected a fresh measurement to be reused")
+	}
+
+	if msg := m.policyViolation("any", ""); !strings.Contains(msg, "2.0 KiB of the 1.0 KiB allowed by max_disk_usage") {
+		t.Fatalf("expected disk quota error, got %q", msg)
+	}
+	if header := m.renderHeader(layoutDims{width: 120}); !strings.Contains(header, "2.0 KiB/1.0 KiB") {
+		t.Fatalf("expected the header to show disk usage, got %q", header)
+	}
+}
@@ -0,0 +1,201 @@
+package app
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+// selectedOpenPR returns the selected worktree and its open PR, showing an
+// explanation when there is none.
+func (m *Model) selectedOpenPR() (*models.WorktreeInfo, bool) {
+	wt := m.selectedWorktree()
+	if wt == nil {
+		m.showInfo(errNoWorktreeSelected, nil)
+		return nil, false
+	}
+	if wt.PR == nil {
+		m.showInfo("No PR/MR is associated with this worktree.\n\nPress 'p' to fetch PR data first.", nil)
+		return nil, false
+	}
+	if wt.PR.State != "OPEN" {
+		m.showInfo(fmt.Sprintf("PR #%d is %s; only open PRs/MRs can be updated.", wt.PR.Number, strings.ToLower(wt.PR.State)), nil)
+		return nil, false
+	}
+	return wt, true
+}
+
+// togglePRDraft marks the selected worktree's PR as draft or ready for review.
+func (m *Model) togglePRDraft() tea.Cmd {
+	if m.readOnlyDenied("Changing a PR") {
+		return nil
+	}
+	wt, ok := m.selectedOpenPR()
+	if !ok {
+		return nil
+	}
+	draft := !wt.PR.IsDraft
+	number := wt.PR.Number
+	path := wt.Path
+	if draft {
+		m.statusContent = fmt.Sprintf("Marking PR #%d as draft...", number)
+	} else {
+		m.statusContent = fmt.Sprintf("Marking PR #%d as ready for review...", number)
+	}
+	return func() tea.Msg {
+		err := m.git.SetPRDraft(m.ctx, number, draft, path)
+		return prUpdatedMsg{path: path, number: number, draft: &draft, err: err}
+	}
+}
+
+// showRequestReviewers gathers reviewer candidates for the selected PR.
+func (m *Model) showRequestReviewers() tea.Cmd {
+	if m.readOnlyDenied("Requesting reviewers") {
+		return nil
+	}
+	wt, ok := m.selectedOpenPR()
+	if !ok {
+		return nil
+	}
+	path := wt.Path
+	m.loading = true
+	m.loadingScreen = NewLoadingScreen("Fetching reviewers...", m.theme)
+	m.currentScreen = screenLoading
+	return func() tea.Msg {
+		return reviewerCandidatesMsg{
+			path:       path,
+			candidates: m.git.FetchReviewerCandidates(m.ctx),
+		}
+	}
+}
+
+// handleReviewerCandidates offers configured and recent reviewers in a checklist,
+// or a free-form input when none are known.
+func (m *Model) handleReviewerCandidates(msg reviewerCandidatesMsg) tea.Cmd {
+	m.loading = false
+	if m.currentScreen == screenLoading {
+		m.currentScreen = screenNone
+		m.loadingScreen = nil
+	}
+
+	var wt *models.WorktreeInfo
+	for _, candidate := range m.worktrees {
+		if candidate.Path == msg.path {
+			wt = candidate
+			break
+		}
+	}
+	if wt == nil || wt.PR == nil {
+		return nil
+	}
+	number := wt.PR.Number
+
+	reviewers := make([]string, 0, len(m.config.PRReviewers)+len(msg.candidates))
+	for _, login := range append(append([]string{}, m.config.PRReviewers...), msg.candidates...) {
+		if login == "" || login == wt.PR.Author || slices.Contains(reviewers, login) {
+			continue
+		}
+		reviewers = append(reviewers, login)
+	}
+
+	if len(reviewers) == 0 {
+		prompt := fmt.Sprintf("Request reviewers for PR #%d (comma-separated usernames)", number)
+		m.inputScreen = NewInputScreen(prompt, "user1, user2", "", m.theme)
+		m.inputSubmit = func(value string, _ bool) (tea.Cmd, bool) {
+			logins := parseReviewerList(value)
+			if len(logins) == 0 {
+				m.inputScreen.errorMsg = "Please provide at least one username."
+				return nil, false
+			}
+			m.inputScreen.errorMsg = ""
+			return m.requestReviewers(msg.path, number, logins), true
+		}
+		m.currentScreen = screenInput
+		return textinput.Blink
+	}
+
+	items := make([]ChecklistItem, 0, len(reviewers))
+	for _, login := range reviewers {
+		description := "Recent PR participant"
+		if slices.Contains(m.config.PRReviewers, login) {
+			description = "From pr_reviewers"
+		}
+		items = append(items, ChecklistItem{ID: login, Label: login, Description: description})
+	}
+	m.checklistScreen = NewChecklistScreen(
+		items,
+		fmt.Sprintf("Request Reviewers for PR #%d", number),
+		"Filter reviewers...",
+		"No reviewers found.",
+		m.windowWidth,
+		m.windowHeight,
+		m.theme,
+	)
+	m.checklistSubmit = func(selected []ChecklistItem) tea.Cmd {
+		if len(selected) == 0 {
+			return nil
+		}
+		logins := make([]string, 0, len(selected))
+		for _, item := range selected {
+			logins = append(logins, item.ID)
+		}
+		return m.requestReviewers(msg.path, number, logins)
+	}
+	m.currentScreen = screenChecklist
+	return nil
+}
+
+// requestReviewers asks the forge to add reviewers to a PR.
+func (m *Model) requestReviewers(path string, number int, logins []string) tea.Cmd {
+	m.statusContent = fmt.Sprintf("Requesting review from %s...", strings.Join(logins, ", "))
+	return func() tea.Msg {
+		err := m.git.RequestPRReviewers(m.ctx, number, logins, path)
+		return prUpdatedMsg{path: path, number: number, reviewers: logins, err: err}
+	}
+}
+
+// handlePRUpdated applies a successful PR update locally so the PR column
+// reflects it straight away.
+func (m *Model) handlePRUpdated(msg prUpdatedMsg) tea.Cmd {
+	if msg.err != nil {
+		m.showInfo(fmt.Sprintf("Failed to update PR #%d.\n\n%s", msg.number, truncateToHeightFromEnd(msg.err.Error(), 5)), nil)
+		return nil
+	}
+	for _, wt := range m.worktrees {
+		if wt.Path != msg.path || wt.PR == nil {
+			continue
+		}
+		if msg.draft != nil {
+			wt.PR.IsDraft = *msg.draft
+		}
+	}
+	switch {
+	case msg.draft != nil && *msg.draft:
+		m.statusContent = fmt.Sprintf("PR #%d marked as draft", msg.number)
+	case msg.draft != nil:
+		m.statusContent = fmt.Sprintf("PR #%d marked as ready for review", msg.number)
+	default:
+		m.statusContent = fmt.Sprintf("Review requested from %s on PR #%d", strings.Join(msg.reviewers, ", "), msg.number)
+	}
+	m.updateTable()
+	return m.updateDetailsView()
+}
+
+// parseReviewerList splits a comma or space separated list of usernames.
+func parseReviewerList(value string) []string {
+	fields := strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t'
+	})
+	logins := make([]string, 0, len(fields))
+	for _, field := range fields {
+		field = strings.TrimPrefix(strings.TrimSpace(field), "@")
+		if field != "" && !slices.Contains(logins, field) {
+			logins = append(logins, field)
+		}
+	}
+	return logins
+}
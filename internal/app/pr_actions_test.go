@@ -0,0 +1,101 @@
+package app
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/chmouel/lazyworktree/internal/config"
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+func TestHandlePRUpdatedRefreshesDraftState(t *testing.T) {
+	cfg := &config.AppConfig{
+		WorktreeDir: t.TempDir(),
+	}
+	m := NewModel(cfg, "")
+	m.worktreeTable.SetWidth(100)
+	m.prDataLoaded = true
+	m.worktrees = []*models.WorktreeInfo{
+		{Path: "/wt/feature", Branch: "feature", PR: &models.PRInfo{Number: 4, State: "OPEN"}},
+	}
+	m.filteredWts = m.worktrees
+	m.updateTableColumns(m.worktreeTable.Width())
+	m.updateTable()
+
+	draft := true
+	m.handlePRUpdated(prUpdatedMsg{path: "/wt/feature", number: 4, draft: &draft})
+
+	if !m.worktrees[0].PR.IsDraft {
+		t.Fatal("expected PR to be marked as draft")
+	}
+	rows := m.worktreeTable.Rows()
+	if len(rows) == 0 || !strings.Contains(rows[0][len(rows[0])-1], symbolDraftCircle) {
+		t.Fatalf("expected draft symbol in PR column, got %v", rows)
+	}
+	if !strings.Contains(m.statusContent, "draft") {
+		t.Fatalf("unexpected status %q", m.statusContent)
+	}
+}
+
+func TestHandlePRUpdatedError(t *testing.T) {
+	cfg := &config.AppConfig{
+		WorktreeDir: t.TempDir(),
+	}
+	m := NewModel(cfg, "")
+	m.handlePRUpdated(prUpdatedMsg{path: "/wt/feature", number: 4, err: errors.New("forbidden")})
+	if m.currentScreen != screenInfo {
+		t.Fatalf("expected info screen, got %v", m.currentScreen)
+	}
+}
+
+func TestTogglePRDraftRequiresOpenPR(t *testing.T) {
+	cfg := &config.AppConfig{
+		WorktreeDir: t.TempDir(),
+	}
+	m := NewModel(cfg, "")
+	m.worktrees = []*models.WorktreeInfo{
+		{Path: "/wt/feature", Branch: "feature", PR: &models.PRInfo{Number: 4, State: "MERGED"}},
+	}
+	m.filteredWts = m.worktrees
+	m.selectedIndex = 0
+
+	if cmd := m.togglePRDraft(); cmd != nil {
+		t.Fatal("expected no command for a merged PR")
+	}
+	if m.currentScreen != screenInfo {
+		t.Fatalf("expected info screen, got %v", m.currentScreen)
+	}
+}
+
+func TestHandleReviewerCandidatesMergesConfig(t *testing.T) {
+	cfg := &config.AppConfig{
+		WorktreeDir: t.TempDir(),
+		PRReviewers: []string{"bob", "alice"},
+	}
+	m := NewModel(cfg, "")
+	m.worktrees = []*models.WorktreeInfo{
+		{Path: "/wt/feature", Branch: "feature", PR: &models.PRInfo{Number: 4, State: "OPEN", Author: "carol"}},
+	}
+	m.filteredWts = m.worktrees
+
+	m.handleReviewerCandidates(reviewerCandidatesMsg{path: "/wt/feature", candidates: []string{"alice", "carol", "dave"}})
+
+	if m.currentScreen != screenChecklist {
+		t.Fatalf("expected checklist screen, got %v", m.currentScreen)
+	}
+	var ids []string
+	for _, item := range m.checklistScreen.items {
+		ids = append(ids, item.ID)
+	}
+	if strings.Join(ids, ",") != "bob,alice,dave" {
+		t.Fatalf("unexpected reviewers %v", ids)
+	}
+}
+
+func TestParseReviewerList(t *testing.T) {
+	got := parseReviewerList(" @alice, bob  alice ")
+	if strings.Join(got, ",") != "alice,bob" {
+		t.Fatalf("unexpected reviewers %v", got)
+	}
+}
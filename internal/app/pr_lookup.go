@@ -0,0 +1,81 @@
+package app
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/chmouel/lazyworktree/internal/crash"
+	"github.com/chmouel/lazyworktree/internal/git"
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+const (
+	// prLookupTTL bounds how long per-worktree PR fallback lookups are reused.
+	prLookupTTL = git.PRLookupTTL
+	// prLookupConcurrency caps simultaneous gh/glab fallback lookups.
+	prLookupConcurrency = git.PRLookupConcurrency
+)
+
+// prLookupEntry caches the result of a per-worktree PR lookup, including
+// lookups that found no PR, so refreshes do not repeat slow CLI calls.
+type prLookupEntry struct {
+	branch    string
+	pr        *models.PRInfo
+	err       string
+	fetchedAt time.Time
+}
+
+// freshPRLookups returns a snapshot of cached lookups that are still within prLookupTTL.
+func (m *Model) freshPRLookups() map[string]*prLookupEntry {
+	fresh := make(map[string]*prLookupEntry, len(m.prLookupCache))
+	for path, entry := range m.prLookupCache {
+		if time.Since(entry.fetchedAt) < prLookupTTL {
+			fresh[path] = entry
+		}
+	}
+	return fresh
+}
+
+// storePRLookups merges fallback lookup results into the cache.
+func (m *Model) storePRLookups(lookups map[string]*prLookupEntry) {
+	for path, entry := range lookups {
+		m.prLookupCache[path] = entry
+	}
+}
+
+// lookupWorktreePRs asks the host CLI for the PR of each worktree directly,
+// which handles fork PRs whose local branch differs from headRefName.
+func (m *Model) lookupWorktreePRs(worktrees []*models.WorktreeInfo) map[string]*prLookupEntry {
+	results := make(map[string]*prLookupEntry, len(worktrees))
+	if len(worktrees) == 0 {
+		return results
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, prLookupConcurrency)
+	for _, wt := range worktrees {
+		wg.Add(1)
+		go func(wt *models.WorktreeInfo) {
+			defer wg.Done()
+			defer crash.Recover()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+
+			entry := &prLookupEntry{branch: wt.Branch, fetchedAt: time.Now()}
+			pr, err := m.git.FetchPRForWorktreeWithError(m.ctx, wt.Path)
+			entry.pr = pr
+			if err != nil {
+				entry.err = err.Error()
+				log.Printf("FetchPRForWorktree %q error: %v", wt.Path, err)
+			}
+
+			mu.Lock()
+			results[wt.Path] = entry
+			mu.Unlock()
+		}(wt)
+	}
+	wg.Wait()
+	return results
+}
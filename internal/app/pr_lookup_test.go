@@ -0,0 +1,100 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/chmouel/lazyworktree/internal/config"
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+func TestFreshPRLookupsDropsExpiredEntries(t *testing.T) {
+	cfg := &config.AppConfig{
+		WorktreeDir: t.TempDir(),
+	}
+	m := NewModel(cfg, "")
+	m.prLookupCache["/wt/fresh"] = &prLookupEntry{branch: "fresh", fetchedAt: time.Now()}
+	m.prLookupCache["/wt/stale"] = &prLookupEntry{branch: "stale", fetchedAt: time.Now().Add(-2 * prLookupTTL)}
+
+	fresh := m.freshPRLookups()
+	if _, ok := fresh["/wt/fresh"]; !ok {
+		t.Fatal("expected fresh entry to be kept")
+	}
+	if _, ok := fresh["/wt/stale"]; ok {
+		t.Fatal("expected stale entry to be dropped")
+	}
+}
+
+func TestHandlePRDataLoadedStoresLookups(t *testing.T) {
+	cfg := &config.AppConfig{
+		WorktreeDir: t.TempDir(),
+	}
+	m := NewModel(cfg, "")
+	m.worktreeTable.SetWidth(100)
+	m.worktrees = []*models.WorktreeInfo{
+		{Path: "/wt/fork", Branch: "local-name"},
+	}
+	m.filteredWts = m.worktrees
+
+	pr := &models.PRInfo{Number: 12, State: "OPEN", Title: "Fork PR"}
+	msg := prDataLoadedMsg{
+		prMap:       map[string]*models.PRInfo{},
+		worktreePRs: map[string]*models.PRInfo{"/wt/fork": pr},
+		lookups: map[string]*prLookupEntry{
+			"/wt/fork": {branch: "local-name", pr: pr, fetchedAt: time.Now()},
+		},
+	}
+	m.handlePRDataLoaded(msg)
+
+	entry, ok := m.prLookupCache["/wt/fork"]
+	if !ok || entry.pr == nil || entry.pr.Number != 12 {
+		t.Fatalf("expected lookup to be cached, got %+v", entry)
+	}
+	if m.worktrees[0].PR == nil || m.worktrees[0].PR.Number != 12 {
+		t.Fatal("expected PR to be assigned from worktree lookup")
+	}
+}
+
+func TestLookupWorktreePRsEmpty(t *testing.T) {
+	cfg := &config.AppConfig{
+		WorktreeDir: t.TempDir(),
+	}
+	m := NewModel(cfg, "")
+	if got := m.lookupWorktreePRs(nil); len(got) != 0 {
+		t.Fatalf("expected no lookups, got %d", len(got))
+	}
+}
+
+func TestHandlePRDataLoadedSeedsCIChecks(t *testing.T) {
+	cfg := &config.AppConfig{
+		WorktreeDir: t.TempDir(),
+	}
+	m := NewModel(cfg, "")
+	m.worktreeTable.SetWidth(100)
+	m.worktrees = []*models.WorktreeInfo{
+		{Path: "/wt/feature", Branch: "feature"},
+	}
+	m.filteredWts = m.worktrees
+
+	checks := []*models.CICheck{{Name: "build", Status: "completed", Conclusion: "success"}}
+	msg := prDataLoadedMsg{
+		prMap: map[string]*models.PRInfo{
+			"feature": {Number: 3, State: "OPEN", ReviewDecision: "APPROVED", Checks: checks},
+		},
+	}
+	m.handlePRDataLoaded(msg)
+
+	cached, ok := m.ciCache["feature"]
+	if !ok || len(cached.checks) != 1 {
+		t.Fatalf("expected CI checks to be cached from PR data, got %+v", cached)
+	}
+	if cmd := m.maybeFetchCIStatus(); cmd != nil {
+		t.Fatal("expected no separate CI fetch while seeded checks are fresh")
+	}
+	if got := m.formatReviewDecision("APPROVED"); got == "" {
+		t.Fatal("expected approved review decision to render")
+	}
+	if got := m.formatReviewDecision(""); got != "" {
+		t.Fatalf("expected empty review decision to render nothing, got %q", got)
+	}
+}
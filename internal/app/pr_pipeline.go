@@ -0,0 +1,310 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/chmouel/lazyworktree/internal/crash"
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+// prPipeline follows a p press: the PR list, then the PR of each worktree
+// it does not cover and the CI of those without checks, fetched
+// concurrently and applied as they arrive.
+type prPipeline struct {
+	id      int
+	cancel  context.CancelFunc
+	updates chan prPipelineMsg
+	before  map[string]string // PR states when it started, for events
+	total   int
+	done    int
+}
+
+// prPipelineMsg carries one result of the pipeline; which fields are set
+// says which.
+type prPipelineMsg struct {
+	id int
+
+	prMap map[string]*models.PRInfo // the PR list, keyed by head branch
+	heads map[string]string         // worktree path -> upstream head branch
+
+	path   string         // a worktree's own lookup
+	lookup *prLookupEntry // its result
+
+	branch string // a branch's CI
+	checks []*models.CICheck
+
+	resolved int // worktrees with their PR and CI in
+	total    int
+	err      error
+	finished bool
+}
+
+// startPRPipeline fetches PR and CI data for every worktree in the
+// background, replacing any pipeline still running.
+func (m *Model) startPRPipeline() tea.Cmd {
+	m.stopPRPipeline()
+	m.ciCache = make(map[string]*ciCacheEntry)
+	m.prLookupCache = make(map[string]*prLookupEntry)
+	m.prPipelineID++
+	ctx, cancel := context.WithCancel(m.ctx)
+	p := &prPipeline{
+		id:      m.prPipelineID,
+		cancel:  cancel,
+		updates: make(chan prPipelineMsg),
+		before:  m.prEventStates(),
+		total:   len(m.worktrees),
+	}
+	m.prPipeline = p
+	m.statusContent = "Fetching PR data... (Esc cancels)"
+
+	worktrees := append([]*models.WorktreeInfo(nil), m.worktrees...)
+	go m.runPRPipeline(ctx, p, worktrees)
+	return waitForPRPipeline(p.updates)
+}
+
+func waitForPRPipeline(updates chan prPipelineMsg) tea.Cmd {
+	return func() tea.Msg {
+		return <-updates
+	}
+}
+
+// runPRPipeline does the fetching for startPRPipeline.
+func (m *Model) runPRPipeline(ctx context.Context, p *prPipeline, worktrees []*models.WorktreeInfo) {
+	defer crash.Recover()
+	var mu sync.Mutex
+	resolved := 0
+	send := func(msg prPipelineMsg, resolves int) bool {
+		mu.Lock()
+		resolved += resolves
+		msg.id, msg.resolved, msg.total = p.id, resolved, len(worktrees)
+		// Sending while holding the lock keeps the counts in order.
+		defer mu.Unlock()
+		select {
+		case p.updates <- msg:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+	finish := func(err error) {
+		send(prPipelineMsg{err: err, finished: true}, 0)
+	}
+
+	// The remote branch each worktree tracks lets fork or renamed branches
+	// match their PR by headRefName as well.
+	heads := make(map[string]string)
+	var headsMu sync.Mutex
+	var wg sync.WaitGroup
+	for _, wt := range worktrees {
+		wg.Add(1)
+		go func(wt *models.WorktreeInfo) {
+			defer wg.Done()
+			defer crash.Recover()
+			if head := m.git.UpstreamHeadBranch(ctx, wt.Branch, wt.Path); head != "" {
+				headsMu.Lock()
+				heads[wt.Path] = head
+				headsMu.Unlock()
+			}
+		}(wt)
+	}
+	wg.Wait()
+	branches := make([]string, 0, len(worktrees)*2)
+	for _, wt := range worktrees {
+		branches = append(branches, wt.Branch)
+		if head := heads[wt.Path]; head != "" {
+			branches = append(branches, head)
+		}
+	}
+	prMap, err := m.git.FetchPRMapForBranches(ctx, branches)
+	if ctx.Err() != nil {
+		return
+	}
+	if err != nil {
+		finish(err)
+		return
+	}
+	if prMap == nil {
+		prMap = make(map[string]*models.PRInfo)
+	}
+
+	// CI is fetched for PRs that came without their checks.
+	slots := make(chan struct{}, prLookupConcurrency)
+	fetchCI := func(pr *models.PRInfo, branch string) {
+		defer wg.Done()
+		defer crash.Recover()
+		slots <- struct{}{}
+		checks, err := m.git.FetchCIStatus(ctx, pr.Number, branch)
+		<-slots
+		if err != nil {
+			log.Printf("pr pipeline: CI of %q: %v", branch, err)
+		}
+		send(prPipelineMsg{branch: branch, checks: checks}, 1)
+	}
+	matched, listed := 0, make(map[string]bool)
+	var ciNeeded []*models.WorktreeInfo
+	for _, wt := range worktrees {
+		pr, ok := prMap[wt.Branch]
+		if !ok {
+			pr, ok = prMap[heads[wt.Path]]
+		}
+		if !ok {
+			continue
+		}
+		listed[wt.Path] = true
+		if pr.Checks == nil {
+			ciNeeded = append(ciNeeded, wt)
+		} else {
+			matched++
+		}
+	}
+	if !send(prPipelineMsg{prMap: prMap, heads: heads}, matched) {
+		return
+	}
+	for _, wt := range ciNeeded {
+		pr := prMap[wt.Branch]
+		if pr == nil {
+			pr = prMap[heads[wt.Path]]
+		}
+		wg.Add(1)
+		go fetchCI(pr, wt.Branch)
+	}
+
+	// The others are looked up one by one, a few at a time.
+	for _, wt := range worktrees {
+		if listed[wt.Path] {
+			continue
+		}
+		wg.Add(1)
+		go func(wt *models.WorktreeInfo) {
+			defer wg.Done()
+			defer crash.Recover()
+			slots <- struct{}{}
+			entry := &prLookupEntry{branch: wt.Branch, fetchedAt: time.Now()}
+			pr, err := m.git.FetchPRForWorktreeWithError(ctx, wt.Path)
+			<-slots
+			entry.pr = pr
+			if err != nil {
+				entry.err = err.Error()
+				log.Printf("FetchPRForWorktree %q error: %v", wt.Path, err)
+			}
+			if ctx.Err() != nil {
+				return
+			}
+			if pr == nil || pr.Checks != nil {
+				send(prPipelineMsg{path: wt.Path, lookup: entry}, 1)
+				return
+			}
+			if send(prPipelineMsg{path: wt.Path, lookup: entry}, 0) {
+				wg.Add(1)
+				go fetchCI(pr, wt.Branch)
+			}
+		}(wt)
+	}
+	wg.Wait()
+	if ctx.Err() == nil {
+		finish(nil)
+	}
+}
+
+// stopPRPipeline cancels the running pipeline, keeping what it applied.
+func (m *Model) stopPRPipeline() {
+	if m.prPipeline == nil {
+		return
+	}
+	m.prPipeline.cancel()
+	m.prPipeline = nil
+}
+
+// cancelPRPipeline stops the pipeline at the user's request.
+func (m *Model) cancelPRPipeline() {
+	p := m.prPipeline
+	m.stopPRPipeline()
+	m.emitPRChanges(p.before)
+	m.statusContent = fmt.Sprintf("PR fetch cancelled after %d/%d worktrees.", p.done, p.total)
+}
+
+// handlePRPipeline applies one result of the pipeline.
+func (m *Model) handlePRPipeline(msg prPipelineMsg) tea.Cmd {
+	p := m.prPipeline
+	if p == nil || msg.id != p.id {
+		return nil
+	}
+	p.done, p.total = msg.resolved, msg.total
+
+	switch {
+	case msg.finished:
+		m.stopPRPipeline()
+		if msg.err != nil {
+			m.statusContent = fmt.Sprintf("Fetching PR data failed: %v", msg.err)
+			return func() tea.Msg { return errMsg{err: fmt.Errorf("failed to fetch PR data: %w", msg.err)} }
+		}
+		m.emitPRChanges(p.before)
+		m.statusContent = fmt.Sprintf("Fetched PR data for %d worktrees.", msg.total)
+		return tea.Batch(m.updateDetailsView(), m.fetchDeployments())
+	case msg.prMap != nil:
+		for _, wt := range m.worktrees {
+			pr, ok := msg.prMap[wt.Branch]
+			if !ok {
+				pr, ok = msg.prMap[msg.heads[wt.Path]]
+			}
+			if ok {
+				m.setWorktreePR(wt, pr, "")
+			}
+		}
+		if !m.prDataLoaded {
+			m.prDataLoaded = true
+			// Columns go before rows, which gain the PR column.
+			m.updateTableColumns(m.worktreeTable.Width())
+		}
+	case msg.lookup != nil:
+		m.storePRLookups(map[string]*prLookupEntry{msg.path: msg.lookup})
+		for _, wt := range m.worktrees {
+			if wt.Path == msg.path {
+				m.setWorktreePR(wt, msg.lookup.pr, msg.lookup.err)
+			}
+		}
+	case msg.branch != "":
+		if msg.checks != nil {
+			m.ciCache[msg.branch] = &ciCacheEntry{checks: msg.checks, fetchedAt: time.Now()}
+		}
+	}
+	m.updateTable()
+	if wt := m.selectedWorktree(); wt != nil {
+		m.infoContent = m.buildInfoContent(wt)
+	}
+	m.refreshHealthScreen()
+	return waitForPRPipeline(p.updates)
+}
+
+// setWorktreePR records a worktree's PR, or why it has none, as the PR
+// data handlers do.
+func (m *Model) setWorktreePR(wt *models.WorktreeInfo, pr *models.PRInfo, fetchErr string) {
+	wt.PRFetchError = ""
+	wt.PRFetchStatus = models.PRFetchStatusNoPR
+	switch {
+	case pr != nil:
+		wt.PR = pr
+		wt.PRFetchStatus = models.PRFetchStatusLoaded
+		if pr.Checks != nil {
+			m.ciCache[wt.Branch] = &ciCacheEntry{checks: pr.Checks, fetchedAt: time.Now()}
+		}
+	case fetchErr != "":
+		wt.PRFetchError = fetchErr
+		wt.PRFetchStatus = models.PRFetchStatusError
+	}
+}
+
+// prPipelineView shows the pipeline's progress in the footer.
+func (m *Model) prPipelineView() string {
+	p := m.prPipeline
+	if p == nil {
+		return ""
+	}
+	return lipgloss.NewStyle().Foreground(m.theme.MutedFg).Render(fmt.Sprintf("PRs %d/%d worktrees (Esc cancels)", p.done, p.total))
+}
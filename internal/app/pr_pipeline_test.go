@@ -0,0 +1,69 @@
+package app
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/chmouel/lazyworktree/internal/config"
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+func TestPRPipelineAppliesPartialResults(t *testing.T) {
+	m := NewModel(&config.AppConfig{WorktreeDir: t.TempDir()}, "")
+	m.worktreeTable.SetWidth(100)
+	m.worktrees = []*models.WorktreeInfo{
+		{Path: "/wt/listed", Branch: "listed"},
+		{Path: "/wt/fork", Branch: "local-name"},
+		{Path: "/wt/none", Branch: "none"},
+	}
+	m.updateTable()
+	_, cancel := context.WithCancel(context.Background())
+	m.prPipeline = &prPipeline{id: 1, cancel: cancel, updates: make(chan prPipelineMsg), before: m.prEventStates(), total: 3}
+
+	listed := &models.PRInfo{Number: 1, State: "OPEN"}
+	if m.handlePRPipeline(prPipelineMsg{id: 1, prMap: map[string]*models.PRInfo{"listed": listed}, resolved: 0, total: 3}) == nil {
+		t.Fatal("expected to wait for the next result")
+	}
+	if !m.prDataLoaded || m.worktrees[0].PR != listed || len(m.worktreeTable.Rows()[0]) != len(m.worktreeTable.Columns()) {
+		t.Fatal("expected the listed PR and its column at once")
+	}
+
+	checks := []*models.CICheck{{Name: "test", Conclusion: "success"}}
+	m.handlePRPipeline(prPipelineMsg{id: 1, branch: "listed", checks: checks, resolved: 1, total: 3})
+	if entry := m.ciCache["listed"]; entry == nil || len(entry.checks) != 1 {
+		t.Fatal("expected the CI checks to be cached")
+	}
+
+	fork := &models.PRInfo{Number: 2, State: "OPEN", Checks: checks}
+	m.handlePRPipeline(prPipelineMsg{id: 1, path: "/wt/fork", lookup: &prLookupEntry{branch: "local-name", pr: fork, fetchedAt: time.Now()}, resolved: 2, total: 3})
+	if m.worktrees[1].PR != fork || m.prLookupCache["/wt/fork"] == nil || m.ciCache["local-name"] == nil {
+		t.Fatal("expected the looked up PR, its cache entry and its checks")
+	}
+	if view := m.prPipelineView(); !strings.Contains(view, "PRs 2/3 worktrees") {
+		t.Fatalf("expected the progress in the footer, got %q", view)
+	}
+
+	// A result from an earlier pipeline is ignored.
+	if m.handlePRPipeline(prPipelineMsg{id: 0, finished: true}) != nil || m.prPipeline == nil {
+		t.Fatal("expected stale results to be dropped")
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.prPipeline != nil || !strings.Contains(m.statusContent, "cancelled after 2/3") {
+		t.Fatalf("expected Esc to cancel, got %q", m.statusContent)
+	}
+}
+
+func TestPRPipelineFinishes(t *testing.T) {
+	m := NewModel(&config.AppConfig{WorktreeDir: t.TempDir()}, "")
+	_, cancel := context.WithCancel(context.Background())
+	m.prPipeline = &prPipeline{id: 3, cancel: cancel, updates: make(chan prPipelineMsg)}
+
+	m.handlePRPipeline(prPipelineMsg{id: 3, finished: true, resolved: 4, total: 4})
+	if m.prPipeline != nil || m.statusContent != "Fetched PR data for 4 worktrees." {
+		t.Fatalf("expected the pipeline to finish, got %q", m.statusContent)
+	}
+}
@@ -0,0 +1,282 @@
+package app
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/chmouel/lazyworktree/internal/models"
+	"github.com/chmouel/lazyworktree/internal/utils"
+)
+
+const (
+	prSyncCreatePrefix = "create:"
+	prSyncPrunePrefix  = "prune:"
+)
+
+// prSyncWorktree is a worktree created by the PR sync that still needs its
+// init commands run.
+type prSyncWorktree struct {
+	branch string
+	path   string
+}
+
+// showSyncMyPRs fetches the user's open PRs/MRs and the PR state of local
+// worktrees so they can be mirrored locally in one go.
+func (m *Model) showSyncMyPRs() tea.Cmd {
+	if m.readOnlyDenied("Synchronising PR worktrees") {
+		return nil
+	}
+	if !m.git.IsGitHubOrGitLab(m.ctx) {
+		m.showInfo("Syncing PRs requires a GitHub or GitLab remote.", nil)
+		return nil
+	}
+
+	branches := make([]string, 0, len(m.worktrees))
+	for _, wt := range m.worktrees {
+		if !wt.IsMain {
+			branches = append(branches, wt.Branch)
+		}
+	}
+
+	m.loading = true
+	m.loadingScreen = NewLoadingScreen("Fetching your open PRs...", m.theme)
+	m.currentScreen = screenLoading
+	return func() tea.Msg {
+		prs, err := m.git.FetchMyOpenPRs(m.ctx)
+		if err != nil {
+			return prSyncLoadedMsg{err: err}
+		}
+		branchPRs, err := m.git.FetchPRMapForBranches(m.ctx, branches)
+		if err != nil {
+			m.debugf("sync PRs: branch lookup failed: %v", err)
+		}
+		return prSyncLoadedMsg{prs: prs, branchPRs: branchPRs}
+	}
+}
+
+// handlePRSyncLoaded offers a checklist of PR worktrees to create and merged
+// worktrees to prune.
+func (m *Model) handlePRSyncLoaded(msg prSyncLoadedMsg) tea.Cmd {
+	m.loading = false
+	if m.currentScreen == screenLoading {
+		m.currentScreen = screenNone
+		m.loadingScreen = nil
+	}
+	if msg.err != nil {
+		m.showInfo(fmt.Sprintf("Failed to fetch your PRs: %v", msg.err), nil)
+		return nil
+	}
+
+	template := m.config.PRBranchNameTemplate
+	if template == "" {
+		template = "pr-{number}-{title}"
+	}
+
+	toCreate := make(map[string]*models.PRInfo)
+	toPrune := make(map[string]*models.WorktreeInfo)
+	items := make([]ChecklistItem, 0, len(msg.prs))
+
+	for _, pr := range msg.prs {
+		if pr.Branch == "" || m.hasWorktreeForPR(pr) {
+			continue
+		}
+		id := prSyncCreatePrefix + strconv.Itoa(pr.Number)
+		toCreate[id] = pr
+		items = append(items, ChecklistItem{
+			ID:          id,
+			Label:       fmt.Sprintf("Create %s", utils.GeneratePRWorktreeName(pr, template, "")),
+			Description: fmt.Sprintf("PR #%d: %s (branch: %s)", pr.Number, pr.Title, pr.Branch),
+			Checked:     true,
+		})
+	}
+
+	pruneItems := make([]ChecklistItem, 0)
+	for _, wt := range m.worktrees {
+		if wt.IsMain {
+			continue
+		}
+		pr := msg.branchPRs[wt.Branch]
+		if pr == nil {
+			pr = wt.PR
+		}
+		if pr == nil || !strings.EqualFold(pr.State, "MERGED") {
+			continue
+		}
+		id := prSyncPrunePrefix + wt.Path
+		toPrune[id] = wt
+		desc := fmt.Sprintf("PR #%d merged (branch: %s)", pr.Number, wt.Branch)
+		hasDirtyChanges := wt.Dirty || wt.Untracked > 0 || wt.Modified > 0 || wt.Staged > 0
+		if hasDirtyChanges {
+			desc += " - HAS UNCOMMITTED CHANGES!"
+		}
+		external := m.isExternalWorktree(wt)
+		if external {
+			desc += " - outside the worktree directory"
+		}
+		otherOwner := m.ownedByOther(wt)
+		if otherOwner {
+			desc += " - owned by " + wt.Owner
+		}
+		pruneItems = append(pruneItems, ChecklistItem{
+			ID:          id,
+			Label:       fmt.Sprintf("Prune %s", filepath.Base(wt.Path)),
+			Description: desc,
+			Checked:     !hasDirtyChanges && !external && !otherOwner,
+		})
+	}
+	sort.Slice(pruneItems, func(i, j int) bool {
+		return pruneItems[i].Label < pruneItems[j].Label
+	})
+	items = append(items, pruneItems...)
+
+	if len(items) == 0 {
+		m.showInfo("Your worktrees already mirror your open PRs/MRs.", nil)
+		return nil
+	}
+
+	m.checklistScreen = NewChecklistScreen(
+		items,
+		"Sync My PRs",
+		"Filter...",
+		"Nothing to sync.",
+		m.windowWidth,
+		m.windowHeight,
+		m.theme,
+	)
+	m.checklistSubmit = func(selected []ChecklistItem) tea.Cmd {
+		createPRs := make([]*models.PRInfo, 0, len(selected))
+		pruneWts := make([]*models.WorktreeInfo, 0, len(selected))
+		for _, item := range selected {
+			if pr, ok := toCreate[item.ID]; ok {
+				createPRs = append(createPRs, pr)
+			}
+			if wt, ok := toPrune[item.ID]; ok {
+				pruneWts = append(pruneWts, wt)
+			}
+		}
+		if len(createPRs) == 0 && len(pruneWts) == 0 {
+			return nil
+		}
+		return m.runPRSync(createPRs, pruneWts, template)
+	}
+	m.currentScreen = screenChecklist
+	return textinput.Blink
+}
+
+// hasWorktreeForPR reports whether a local worktree already tracks the PR.
+func (m *Model) hasWorktreeForPR(pr *models.PRInfo) bool {
+	for _, wt := range m.worktrees {
+		if wt.Branch == pr.Branch {
+			return true
+		}
+		if wt.PR != nil && wt.PR.Number == pr.Number {
+			return true
+		}
+	}
+	return false
+}
+
+// runPRSync creates worktrees for the selected PRs and prunes the selected
+// merged worktrees.
+func (m *Model) runPRSync(createPRs []*models.PRInfo, pruneWts []*models.WorktreeInfo, template string) tea.Cmd {
+	repoDir := m.getRepoWorktreeDir()
+	if len(createPRs) > 0 {
+		if err := m.ensureWorktreeDir(repoDir); err != nil {
+			return func() tea.Msg { return errMsg{err: err} }
+		}
+	}
+
+	type createJob struct {
+		pr     *models.PRInfo
+		path   string
+		reason string
+	}
+	jobs := make([]createJob, 0, len(createPRs))
+	// Count each accepted worktree against the quota before it exists.
+	usage := m.quotaUsage()
+	for _, pr := range createPRs {
+		name := sanitizeBranchNameFromTitle(utils.GeneratePRWorktreeName(pr, template, ""), "")
+		targetPath, _, reason := m.newWorktreeTarget(pr.Branch, name)
+		if reason == "" {
+			reason = policyMessage(m.worktreePolicy().CheckCreate(usage, "", ""))
+			if reason == "" {
+				usage.Worktrees++
+			}
+		}
+		jobs = append(jobs, createJob{pr: pr, path: targetPath, reason: reason})
+	}
+
+	m.loading = true
+	m.statusContent = "Syncing your PRs..."
+	m.loadingScreen = NewLoadingScreen(m.statusContent, m.theme)
+	m.currentScreen = screenLoading
+
+	terminateCmds := m.collectTerminateCommands()
+	routine := func() tea.Msg {
+		result := prSyncResultMsg{}
+		for _, job := range jobs {
+			if job.reason != "" {
+				result.failures = append(result.failures, fmt.Sprintf("PR #%d: %s", job.pr.Number, job.reason))
+				continue
+			}
+			if !m.git.CreateWorktreeFromPR(m.ctx, job.pr.Number, job.pr.Branch, job.pr.Branch, job.path) {
+				result.failures = append(result.failures, fmt.Sprintf("PR #%d: failed to create worktree", job.pr.Number))
+				continue
+			}
+			result.created = append(result.created, prSyncWorktree{branch: job.pr.Branch, path: job.path})
+		}
+		result.pruned, result.pruneFailed = m.removeWorktrees(pruneWts, terminateCmds)
+		return result
+	}
+
+	if len(pruneWts) == 0 {
+		return routine
+	}
+	// Check trust for repo commands before running
+	return m.runCommandsWithTrust(terminateCmds, "", nil, routine)
+}
+
+// handlePRSyncResult reports the sync outcome and runs init commands for the
+// new worktrees before reloading the list.
+func (m *Model) handlePRSyncResult(msg prSyncResultMsg) tea.Cmd {
+	m.loading = false
+	if m.currentScreen == screenLoading {
+		m.currentScreen = screenNone
+		m.loadingScreen = nil
+	}
+
+	summary := fmt.Sprintf("Created %d and pruned %d worktrees", len(msg.created), msg.pruned)
+	if failed := len(msg.failures) + msg.pruneFailed; failed > 0 {
+		summary = fmt.Sprintf("%s (%d failed)", summary, failed)
+	}
+	m.statusContent = summary
+	if len(msg.failures) > 0 {
+		m.showInfo(fmt.Sprintf("%s\n\n%s", summary, strings.Join(msg.failures, "\n")), nil)
+	}
+	return m.runPRSyncInit(msg.created)
+}
+
+// runPRSyncInit runs init commands for each created worktree in turn, then
+// reloads the worktree list.
+func (m *Model) runPRSyncInit(created []prSyncWorktree) tea.Cmd {
+	reload := func() tea.Msg {
+		worktrees, err := m.git.GetWorktrees(m.ctx)
+		return worktreesLoadedMsg{worktrees: worktrees, err: err}
+	}
+	if len(created) == 0 {
+		return reload
+	}
+
+	next, rest := created[0], created[1:]
+	after := reload
+	if len(rest) > 0 {
+		after = func() tea.Msg { return prSyncInitMsg{pending: rest} }
+	}
+	env := m.buildCommandEnv(next.branch, next.path)
+	return m.runInitCommands(next.path, env, after)
+}
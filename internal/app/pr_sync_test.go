@@ -0,0 +1,109 @@
+package app
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/chmouel/lazyworktree/internal/config"
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+func TestHandlePRSyncLoadedBuildsChecklist(t *testing.T) {
+	cfg := &config.AppConfig{
+		WorktreeDir:   t.TempDir(),
+		OwnerIdentity: "alice",
+	}
+	m := NewModel(cfg, "")
+	wtDir := m.getRepoWorktreeDir()
+	m.worktrees = []*models.WorktreeInfo{
+		{Path: "/src/main", Branch: "main", IsMain: true},
+		{Path: filepath.Join(wtDir, "existing"), Branch: "existing"},
+		{Path: filepath.Join(wtDir, "merged"), Branch: "merged"},
+		{Path: filepath.Join(wtDir, "dirty"), Branch: "dirty", Dirty: true},
+		{Path: "/elsewhere/external", Branch: "external"},
+		{Path: filepath.Join(wtDir, "shared"), Branch: "shared", Owner: "bob"},
+	}
+	m.filteredWts = m.worktrees
+
+	msg := prSyncLoadedMsg{
+		prs: []*models.PRInfo{
+			{Number: 1, State: "OPEN", Title: "Existing", Branch: "existing"},
+			{Number: 2, State: "OPEN", Title: "New work", Branch: "new-work"},
+		},
+		branchPRs: map[string]*models.PRInfo{
+			"merged":   {Number: 3, State: "MERGED", Branch: "merged"},
+			"dirty":    {Number: 4, State: "MERGED", Branch: "dirty"},
+			"external": {Number: 5, State: "MERGED", Branch: "external"},
+			"shared":   {Number: 6, State: "MERGED", Branch: "shared"},
+		},
+	}
+	m.handlePRSyncLoaded(msg)
+
+	if m.currentScreen != screenChecklist {
+		t.Fatalf("expected checklist screen, got %v", m.currentScreen)
+	}
+	checked := map[string]bool{}
+	descriptions := map[string]string{}
+	for _, item := range m.checklistScreen.items {
+		checked[item.ID] = item.Checked
+		descriptions[item.ID] = item.Description
+	}
+	if len(checked) != 5 {
+		t.Fatalf("expected 5 items, got %v", checked)
+	}
+	if !checked[prSyncCreatePrefix+"2"] {
+		t.Fatal("expected new PR to be offered and checked")
+	}
+	if !checked[prSyncPrunePrefix+filepath.Join(wtDir, "merged")] {
+		t.Fatal("expected merged worktree to be offered and checked")
+	}
+	if c, ok := checked[prSyncPrunePrefix+filepath.Join(wtDir, "dirty")]; !ok || c {
+		t.Fatal("expected dirty merged worktree to be offered unchecked")
+	}
+	externalID := prSyncPrunePrefix + "/elsewhere/external"
+	if c, ok := checked[externalID]; !ok || c || !strings.Contains(descriptions[externalID], "outside the worktree directory") {
+		t.Fatalf("expected external merged worktree to be offered unchecked and flagged, got %q", descriptions[externalID])
+	}
+	sharedID := prSyncPrunePrefix + filepath.Join(wtDir, "shared")
+	if c, ok := checked[sharedID]; !ok || c || !strings.Contains(descriptions[sharedID], "owned by bob") {
+		t.Fatalf("expected another user's merged worktree to be offered unchecked and flagged, got %q", descriptions[sharedID])
+	}
+}
+
+func TestHandlePRSyncLoadedNothingToDo(t *testing.T) {
+	cfg := &config.AppConfig{
+		WorktreeDir: t.TempDir(),
+	}
+	m := NewModel(cfg, "")
+	m.worktrees = []*models.WorktreeInfo{
+		{Path: "/wt/feature", Branch: "feature", PR: &models.PRInfo{Number: 7, State: "OPEN"}},
+	}
+
+	m.handlePRSyncLoaded(prSyncLoadedMsg{prs: []*models.PRInfo{{Number: 7, State: "OPEN", Branch: "renamed"}}})
+
+	if m.currentScreen != screenInfo {
+		t.Fatalf("expected info screen, got %v", m.currentScreen)
+	}
+}
+
+func TestHandlePRSyncResultSummary(t *testing.T) {
+	cfg := &config.AppConfig{
+		WorktreeDir: t.TempDir(),
+	}
+	m := NewModel(cfg, "")
+	cmd := m.handlePRSyncResult(prSyncResultMsg{
+		created:  []prSyncWorktree{{branch: "a", path: "/wt/a"}},
+		failures: []string{"PR #9: failed to create worktree"},
+		pruned:   2,
+	})
+	if cmd == nil {
+		t.Fatal("expected reload command")
+	}
+	if !strings.Contains(m.statusContent, "Created 1 and pruned 2") || !strings.Contains(m.statusContent, "1 failed") {
+		t.Fatalf("unexpected status %q", m.statusContent)
+	}
+	if m.currentScreen != screenInfo {
+		t.Fatalf("expected failures to be shown, got %v", m.currentScreen)
+	}
+}
@@ -0,0 +1,147 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/chmouel/lazyworktree/internal/git"
+)
+
+// maxPreviewBytes caps how much of a README or overview output is shown.
+const maxPreviewBytes = 64 * 1024
+
+// readmeCandidates are the README names tried, in order, for the preview.
+var readmeCandidates = []string{"README.md", "readme.md", "README.markdown", "README", "README.rst", "README.txt"}
+
+// togglePreview switches the status area between the changed files and a
+// preview of the selected worktree's README or overview_command output.
+func (m *Model) togglePreview() tea.Cmd {
+	m.previewMode = !m.previewMode
+	if !m.previewMode {
+		m.previewPath = ""
+		m.previewRaw = ""
+		m.previewRendered = ""
+		m.rebuildStatusContentWithHighlight()
+		return nil
+	}
+	m.statusViewport.GotoTop()
+	return m.loadPreview()
+}
+
+// loadPreview reads the preview for the selected worktree in the background.
+func (m *Model) loadPreview() tea.Cmd {
+	wt := m.selectedWorktree()
+	if !m.previewMode || wt == nil {
+		return nil
+	}
+	path := wt.Path
+	script := m.config.OverviewCommand
+	env := m.buildCommandEnv(wt.Branch, wt.Path)
+	return func() tea.Msg {
+		if script != "" {
+			release := m.git.Acquire(m.ctx, git.PoolCommands)
+			content, err := runOverviewCommand(m.ctx, script, path, env)
+			release()
+			return previewLoadedMsg{path: path, content: content, err: err}
+		}
+		content, markdown := readReadme(path)
+		return previewLoadedMsg{path: path, content: content, markdown: markdown}
+	}
+}
+
+// handlePreviewLoaded stores a loaded preview when it still matches the
+// selected worktree.
+func (m *Model) handlePreviewLoaded(msg previewLoadedMsg) tea.Cmd {
+	wt := m.selectedWorktree()
+	if !m.previewMode || wt == nil || wt.Path != msg.path {
+		return nil
+	}
+	if m.previewPath != msg.path {
+		m.statusViewport.GotoTop()
+	}
+	m.previewPath = msg.path
+	m.previewMarkdown = msg.markdown
+	m.previewRendered = ""
+	switch {
+	case msg.err != nil:
+		m.previewRaw = lipgloss.NewStyle().Foreground(m.theme.ErrorFg).Render(fmt.Sprintf("Overview command failed: %v", msg.err))
+		m.previewMarkdown = false
+	case strings.TrimSpace(msg.content) == "":
+		m.previewRaw = lipgloss.NewStyle().Foreground(m.theme.MutedFg).Render("No README or overview for this worktree")
+		m.previewMarkdown = false
+	default:
+		m.previewRaw = msg.content
+	}
+	return nil
+}
+
+// statusPaneTitle names the status pane after what it currently shows.
+func (m *Model) statusPaneTitle() string {
+	if m.previewMode {
+		return "Preview"
+	}
+	if m.showingInitOutput() {
+		return m.selectedInitOutput().title
+	}
+	return "Status"
+}
+
+// statusPaneContent returns what the status area shows: the preview when
+// enabled, the running or just finished init or bootstrap commands,
+// otherwise the changed files. Markdown is rendered for width once.
+func (m *Model) statusPaneContent(width int) string {
+	if !m.previewMode {
+		if out := m.selectedInitOutput(); out != nil {
+			return m.renderInitOutput(out, m.selectedWorktree().Path)
+		}
+		return m.statusContent
+	}
+	if m.previewPath == "" {
+		return lipgloss.NewStyle().Foreground(m.theme.MutedFg).Render("Loading preview...")
+	}
+	if !m.previewMarkdown {
+		return m.previewRaw
+	}
+	if m.previewRendered == "" || m.previewWidth != width {
+		m.previewRendered = renderMarkdown(m.previewRaw, width, m.theme)
+		m.previewWidth = width
+	}
+	return m.previewRendered
+}
+
+// readReadme returns the first README found in dir and whether it is
+// Markdown.
+func readReadme(dir string) (string, bool) {
+	for _, name := range readmeCandidates {
+		path := filepath.Join(dir, name)
+		// #nosec G304 -- path is a README inside a worktree we manage
+		f, err := os.Open(path)
+		if err != nil {
+			continue
+		}
+		data, _ := io.ReadAll(io.LimitReader(f, maxPreviewBytes))
+		_ = f.Close()
+		ext := strings.ToLower(filepath.Ext(name))
+		return string(data), ext == ".md" || ext == ".markdown"
+	}
+	return "", false
+}
+
+// runOverviewCommand runs overview_command in a worktree and returns its
+// output, which may contain ANSI colours.
+func runOverviewCommand(ctx context.Context, script, dir string, env map[string]string) (string, error) {
+	output, err := runWorktreeScript(ctx, script, dir, env)
+	if err != nil {
+		return "", err
+	}
+	if len(output) > maxPreviewBytes {
+		output = output[:maxPreviewBytes]
+	}
+	return strings.TrimRight(output, "\n"), nil
+}
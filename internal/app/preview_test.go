@@ -0,0 +1,85 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/chmouel/lazyworktree/internal/config"
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+func newPreviewTestModel(t *testing.T, cfg *config.AppConfig) (*Model, string) {
+	t.Helper()
+	wtPath := t.TempDir()
+	cfg.WorktreeDir = t.TempDir()
+	m := NewModel(cfg, "")
+	m.worktrees = []*models.WorktreeInfo{{Path: wtPath, Branch: "feature"}}
+	m.filteredWts = m.worktrees
+	m.worktreeTable.SetCursor(0)
+	return m, wtPath
+}
+
+func TestTogglePreviewRendersReadme(t *testing.T) {
+	m, wtPath := newPreviewTestModel(t, &config.AppConfig{})
+	if err := os.WriteFile(filepath.Join(wtPath, "README.md"), []byte("# Feature\n\n- remember the cache"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	m.statusContent = "M file.go"
+
+	cmd := m.togglePreview()
+	if cmd == nil || !m.previewMode || m.statusPaneTitle() != "Preview" {
+		t.Fatal("expected preview mode to start loading")
+	}
+	if got := m.statusPaneContent(40); !strings.Contains(got, "Loading preview") {
+		t.Fatalf("expected loading placeholder, got %q", got)
+	}
+
+	msg, ok := cmd().(previewLoadedMsg)
+	if !ok || !msg.markdown {
+		t.Fatalf("expected markdown preview, got %#v", msg)
+	}
+	m.handlePreviewLoaded(msg)
+	got := m.statusPaneContent(40)
+	if !strings.Contains(got, "Feature") || !strings.Contains(got, "• remember the cache") {
+		t.Fatalf("unexpected preview %q", got)
+	}
+
+	if cmd := m.togglePreview(); cmd != nil || m.previewMode {
+		t.Fatal("expected preview to switch off")
+	}
+	if m.statusPaneTitle() != "Status" {
+		t.Fatalf("expected Status title, got %q", m.statusPaneTitle())
+	}
+}
+
+func TestPreviewOverviewCommand(t *testing.T) {
+	m, wtPath := newPreviewTestModel(t, &config.AppConfig{OverviewCommand: "echo \"branch $WORKTREE_BRANCH\""})
+
+	msg := m.togglePreview()().(previewLoadedMsg)
+	m.handlePreviewLoaded(msg)
+	if got := m.statusPaneContent(40); got != "branch feature" {
+		t.Fatalf("unexpected overview %q", got)
+	}
+
+	// Results for a worktree that is no longer selected are ignored.
+	m.handlePreviewLoaded(previewLoadedMsg{path: "/elsewhere", content: "stale"})
+	if m.previewPath != wtPath || m.statusPaneContent(40) != "branch feature" {
+		t.Fatal("expected stale preview to be ignored")
+	}
+
+	m.config.OverviewCommand = "echo boom >&2; exit 3"
+	m.handlePreviewLoaded(m.loadPreview()().(previewLoadedMsg))
+	if got := m.statusPaneContent(40); !strings.Contains(got, "Overview command failed") || !strings.Contains(got, "boom") {
+		t.Fatalf("expected failure message, got %q", got)
+	}
+}
+
+func TestPreviewWithoutReadme(t *testing.T) {
+	m, _ := newPreviewTestModel(t, &config.AppConfig{})
+	m.handlePreviewLoaded(m.togglePreview()().(previewLoadedMsg))
+	if got := m.statusPaneContent(40); !strings.Contains(got, "No README") {
+		t.Fatalf("expected missing README message, got %q", got)
+	}
+}
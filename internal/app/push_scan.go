@@ -0,0 +1,74 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/chmouel/lazyworktree/internal/models"
+	"github.com/chmouel/lazyworktree/internal/pushscan"
+)
+
+// pushScanMaxShown caps the findings listed in the confirmation.
+const pushScanMaxShown = 10
+
+// pushScanMsg carries what the scan found in the commits to push, and the
+// push to carry on with.
+type pushScanMsg struct {
+	findings []pushscan.Finding
+	err      error
+	proceed  func() tea.Cmd
+}
+
+// scanBeforePush looks through the commits the worktree would push for large
+// files and secrets when push_scan is on, then pushes, or asks first if it
+// found any.
+func (m *Model) scanBeforePush(wt *models.WorktreeInfo, proceed func() tea.Cmd) tea.Cmd {
+	if m.config == nil || !m.config.PushScan {
+		return proceed()
+	}
+	m.loading = true
+	m.loadingScreen = NewLoadingScreen("Scanning the commits to push for large files and secrets...", m.theme)
+	m.currentScreen = screenLoading
+	path := wt.Path
+	maxFileBytes := int64(m.config.PushScanMaxFileMB) << 20
+	return func() tea.Msg {
+		findings, err := pushscan.Scan(m.ctx, path, maxFileBytes)
+		return pushScanMsg{findings: findings, err: err, proceed: proceed}
+	}
+}
+
+// handlePushScan pushes when the scan found nothing, and otherwise lists the
+// findings and pushes only if told to, defaulting to cancel.
+func (m *Model) handlePushScan(msg pushScanMsg) tea.Cmd {
+	m.loading = false
+	if m.currentScreen == screenLoading {
+		m.currentScreen = screenNone
+		m.loadingScreen = nil
+	}
+	if msg.err == nil && len(msg.findings) == 0 {
+		return msg.proceed()
+	}
+
+	var b strings.Builder
+	if msg.err != nil {
+		fmt.Fprintf(&b, "The commits to push could not be scanned:\n%s\n", msg.err)
+	} else {
+		fmt.Fprintf(&b, "The commits to push contain %d finding(s):\n\n", len(msg.findings))
+		for _, f := range msg.findings[:min(len(msg.findings), pushScanMaxShown)] {
+			where := f.Path
+			if f.Commit != "" {
+				where += " @ " + f.Commit
+			}
+			fmt.Fprintf(&b, "%s: %s (%s)\n", f.Rule, where, f.Detail)
+		}
+		if len(msg.findings) > pushScanMaxShown {
+			fmt.Fprintf(&b, "…and %d more\n", len(msg.findings)-pushScanMaxShown)
+		}
+	}
+	b.WriteString("\nPush anyway?")
+	m.confirmScreen = NewConfirmScreenWithDefault(b.String(), 1, m.theme)
+	m.confirmAction = msg.proceed
+	m.currentScreen = screenConfirm
+	return nil
+}
@@ -0,0 +1,22 @@
+package app
+
+import "fmt"
+
+// readOnlyDenied reports whether read-only mode forbids action, telling the
+// user so. Browsing, diffs, fetching and viewing PRs remain available.
+func (m *Model) readOnlyDenied(action string) bool {
+	if m.config == nil || !m.config.ReadOnly {
+		return false
+	}
+	m.debugf("read-only mode: refused %s", action)
+	if m.config.Observe {
+		m.showInfo(fmt.Sprintf("%s is disabled while observing.\n\nRestart without --observe to make changes.", action), nil)
+		return true
+	}
+	if m.config.SafeMode {
+		m.showInfo(fmt.Sprintf("%s is disabled in safe mode.\n\nRestart without --safe to make changes.", action), nil)
+		return true
+	}
+	m.showInfo(fmt.Sprintf("%s is disabled in read-only mode.\n\nRestart without --read-only to make changes.", action), nil)
+	return true
+}
@@ -0,0 +1,91 @@
+package app
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/chmouel/lazyworktree/internal/config"
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+func TestReadOnlyRefusesMutatingKeys(t *testing.T) {
+	for _, tc := range []struct {
+		key  rune
+		pane int
+		want string
+	}{
+		{key: 'c', want: "Creating worktrees"},
+		{key: 'D', want: "Deleting worktrees"},
+		{key: 'm', want: "Renaming worktrees"},
+		{key: 'P', want: "Pushing"},
+		{key: 'S', want: "Synchronising"},
+		{key: '!', want: "Running commands"},
+		{key: 'g', want: "Opening lazygit"},
+		{key: 'c', pane: 1, want: "Committing"},
+		{key: 's', pane: 1, want: "Staging"},
+	} {
+		t.Run(tc.want, func(t *testing.T) {
+			m := NewModel(&config.AppConfig{WorktreeDir: t.TempDir(), ReadOnly: true}, "")
+			m.filteredWts = []*models.WorktreeInfo{{Path: t.TempDir(), Branch: featureBranch, HasUpstream: true, UpstreamBranch: testUpstreamRef}}
+			m.selectedIndex = 0
+			m.focusedPane = tc.pane
+			m.statusTreeFlat = []*StatusTreeNode{{Path: "a.go", File: &StatusFile{Filename: "a.go", Status: " M"}}}
+			m.commandRunner = func(name string, args ...string) *exec.Cmd {
+				t.Fatalf("read-only mode ran %s %v", name, args)
+				return nil
+			}
+
+			_, cmd := m.handleBuiltInKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{tc.key}})
+			if cmd != nil {
+				t.Fatal("expected no command in read-only mode")
+			}
+			if m.currentScreen != screenInfo || !strings.Contains(m.infoScreen.message, tc.want+" is disabled in read-only mode") {
+				t.Fatalf("expected a read-only notice for %q, got screen %v", tc.want, m.currentScreen)
+			}
+		})
+	}
+}
+
+func TestReadOnlyKeepsBrowsing(t *testing.T) {
+	m := NewModel(&config.AppConfig{WorktreeDir: t.TempDir(), ReadOnly: true}, "")
+	m.filteredWts = []*models.WorktreeInfo{{Path: t.TempDir(), Branch: featureBranch}}
+	m.selectedIndex = 0
+
+	m.handleBuiltInKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'?'}})
+	if m.currentScreen != screenHelp {
+		t.Fatalf("expected help to open in read-only mode, got %v", m.currentScreen)
+	}
+	if !m.readOnlyDenied("anything") {
+		t.Fatal("expected read-only mode to refuse changes")
+	}
+
+	m.config.ReadOnly = false
+	m.currentScreen = screenNone
+	if m.readOnlyDenied("anything") || m.currentScreen != screenNone {
+		t.Fatal("expected changes to be allowed outside read-only mode")
+	}
+}
+
+func TestReadOnlyHeader(t *testing.T) {
+	m := NewModel(&config.AppConfig{WorktreeDir: t.TempDir(), ReadOnly: true}, "")
+	if header := m.renderHeader(layoutDims{width: 80}); !strings.Contains(header, "read-only") {
+		t.Fatalf("expected the header to mention read-only mode, got %q", header)
+	}
+}
+
+func TestSafeMode(t *testing.T) {
+	cfg := &config.AppConfig{WorktreeDir: t.TempDir()}
+	cfg.ApplySafeMode()
+	m := NewModel(cfg, "")
+	if header := m.renderHeader(layoutDims{width: 80}); !strings.Contains(header, "safe mode") || strings.Contains(header, "read-only") {
+		t.Fatalf("expected the header to mention safe mode alone, got %q", header)
+	}
+	if !m.readOnlyDenied("Creating worktrees") || !strings.Contains(m.infoScreen.message, "Restart without --safe") {
+		t.Fatalf("expected safe mode to refuse changes, got %q", m.infoScreen.message)
+	}
+	if host := m.git.DetectHost(m.ctx); host != "unknown" {
+		t.Fatalf("expected gh and glab disabled in safe mode, got host %q", host)
+	}
+}
@@ -0,0 +1,179 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/chmouel/lazyworktree/internal/deleted"
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+// deletedRecreatedMsg reports the recreation of a deleted worktree.
+type deletedRecreatedMsg struct {
+	entry deleted.Entry
+	moved bool // the branch still existed, at another commit
+	err   error
+}
+
+// deletedRetention is how long deleted worktrees are remembered, zero when
+// they are not.
+func (m *Model) deletedRetention() time.Duration {
+	if m.config == nil || m.config.RecentlyDeletedDays <= 0 {
+		return 0
+	}
+	return time.Duration(m.config.RecentlyDeletedDays) * 24 * time.Hour
+}
+
+// deletedEntry describes wt, about to be deleted, with the commit it has
+// checked out. Call it before the worktree is removed.
+func (m *Model) deletedEntry(wt *models.WorktreeInfo) deleted.Entry {
+	entry := deleted.Entry{Path: wt.Path, Branch: wt.Branch, Commit: wt.Head}
+	if wt.Detached || wt.Branch == "(detached)" {
+		entry.Branch = ""
+	}
+	if head := m.git.RunGit(m.ctx, []string{"git", "rev-parse", "HEAD"}, wt.Path, []int{0}, true, true); head != "" {
+		entry.Commit = head
+	}
+	pr := wt.PR
+	if pr == nil {
+		for _, known := range m.worktrees {
+			if known.Path == wt.Path {
+				pr = known.PR
+				break
+			}
+		}
+	}
+	if pr != nil {
+		entry.PRNumber = pr.Number
+		entry.PRURL = pr.URL
+	}
+	return entry
+}
+
+// recordDeleted adds a removed worktree to the recently deleted list.
+func (m *Model) recordDeleted(entry deleted.Entry) {
+	keep := m.deletedRetention()
+	if keep == 0 || entry.Commit == "" {
+		return
+	}
+	if err := deleted.Record(deleted.Path(m.getRepoKey()), entry, keep); err != nil {
+		m.debugf("recently deleted: %v", err)
+	}
+}
+
+// showRecentlyDeleted lists the worktrees deleted within
+// recently_deleted_days; choosing one recreates it from its last commit.
+func (m *Model) showRecentlyDeleted() tea.Cmd {
+	keep := m.deletedRetention()
+	if keep == 0 {
+		m.showInfo("Deleted worktrees are not remembered.\n\nSet recently_deleted_days to keep them for that many days.", nil)
+		return nil
+	}
+	entries := deleted.Load(deleted.Path(m.getRepoKey())).Recent(time.Now(), keep)
+	if len(entries) == 0 {
+		m.showInfo(fmt.Sprintf("No worktrees were deleted in the last %d days.", m.config.RecentlyDeletedDays), nil)
+		return nil
+	}
+
+	items := make([]selectionItem, 0, len(entries))
+	byPath := make(map[string]deleted.Entry, len(entries))
+	for _, entry := range entries {
+		details := []string{entry.Branch}
+		if entry.Branch == "" {
+			details = []string{"detached"}
+		}
+		details = append(details, entry.Commit[:min(7, len(entry.Commit))])
+		if entry.PRNumber > 0 {
+			details = append(details, fmt.Sprintf("PR #%d", entry.PRNumber))
+		}
+		details = append(details, "deleted "+formatRelativeTime(entry.DeletedAt))
+		items = append(items, selectionItem{
+			id:          entry.Path,
+			label:       filepath.Base(entry.Path),
+			description: strings.Join(details, " · "),
+		})
+		byPath[entry.Path] = entry
+	}
+	m.listScreen = NewListSelectionScreen(items, "Recently deleted worktrees (enter: recreate)", "Filter deleted worktrees...", "No matching worktrees.", m.windowWidth, m.windowHeight, "", m.theme)
+	m.listSubmit = func(item selectionItem) tea.Cmd {
+		m.clearListSelection()
+		return m.recreateDeleted(byPath[item.id])
+	}
+	m.currentScreen = screenListSelect
+	return textinput.Blink
+}
+
+// recreateDeleted adds the worktree back at its old path: on its branch
+// when the branch survived, or on a new branch of the same name at its last
+// commit.
+func (m *Model) recreateDeleted(entry deleted.Entry) tea.Cmd {
+	if m.readOnlyDenied("Recreating worktrees") {
+		return nil
+	}
+	name := filepath.Base(entry.Path)
+	if _, err := os.Stat(entry.Path); err == nil {
+		m.showInfo(fmt.Sprintf("Cannot recreate %s: %s already exists.", name, entry.Path), nil)
+		return nil
+	}
+	m.loading = true
+	m.statusContent = fmt.Sprintf("Recreating %s...", name)
+	m.loadingScreen = NewLoadingScreen(m.statusContent, m.theme)
+	m.currentScreen = screenLoading
+	return func() tea.Msg {
+		if m.git.RunGit(m.ctx, []string{"git", "cat-file", "-t", entry.Commit}, "", []int{0, 128}, true, true) != "commit" {
+			return deletedRecreatedMsg{entry: entry, err: fmt.Errorf("commit %s no longer exists; git gc may have removed it", entry.Commit)}
+		}
+		args := []string{"git", "worktree", "add", "--detach", entry.Path, entry.Commit}
+		moved := false
+		if entry.Branch != "" {
+			tip := m.git.RunGit(m.ctx, []string{"git", "rev-parse", "--verify", "--quiet", "refs/heads/" + entry.Branch}, "", []int{0, 1}, true, true)
+			if tip == "" {
+				args = []string{"git", "worktree", "add", "-b", entry.Branch, entry.Path, entry.Commit}
+			} else {
+				args = []string{"git", "worktree", "add", entry.Path, entry.Branch}
+				moved = tip != entry.Commit
+			}
+		}
+		if err := os.MkdirAll(filepath.Dir(entry.Path), defaultDirPerms); err != nil {
+			return deletedRecreatedMsg{entry: entry, err: err}
+		}
+		if !m.git.RunCommandChecked(m.ctx, args, "", fmt.Sprintf("Failed to recreate worktree %s", name)) {
+			return deletedRecreatedMsg{entry: entry, err: fmt.Errorf("git worktree add failed")}
+		}
+		return deletedRecreatedMsg{entry: entry, moved: moved}
+	}
+}
+
+// handleDeletedRecreated drops a recreated worktree from the list and runs
+// init commands in it, as for a new worktree.
+func (m *Model) handleDeletedRecreated(msg deletedRecreatedMsg) tea.Cmd {
+	m.loading = false
+	if m.currentScreen == screenLoading {
+		m.currentScreen = screenNone
+		m.loadingScreen = nil
+	}
+	name := filepath.Base(msg.entry.Path)
+	if msg.err != nil {
+		m.showInfo(fmt.Sprintf("Failed to recreate %s: %v", name, msg.err), nil)
+		return nil
+	}
+	if err := deleted.Forget(deleted.Path(m.getRepoKey()), msg.entry.Path); err != nil {
+		m.debugf("recently deleted: %v", err)
+	}
+	m.statusContent = fmt.Sprintf("Recreated %s", name)
+	if msg.moved {
+		m.statusContent = fmt.Sprintf("Recreated %s on %s, which has moved on since it was deleted", name, msg.entry.Branch)
+	}
+	m.pendingSelectWorktreePath = msg.entry.Path
+	env := m.buildCommandEnv(msg.entry.Branch, msg.entry.Path)
+	after := func() tea.Msg {
+		worktrees, err := m.git.GetWorktrees(m.ctx)
+		return worktreesLoadedMsg{worktrees: worktrees, err: err}
+	}
+	return m.runInitCommands(msg.entry.Path, env, after)
+}
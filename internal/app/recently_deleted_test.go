@@ -0,0 +1,66 @@
+package app
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/chmouel/lazyworktree/internal/config"
+	"github.com/chmouel/lazyworktree/internal/deleted"
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+func TestRecentlyDeletedRecreatesWorktree(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	repo := initTestRepo(t)
+	withCwd(t, repo.dir)
+	wtPath := filepath.Join(t.TempDir(), "gone")
+	runGit(t, repo.dir, "worktree", "add", "-b", "gone", wtPath)
+	writeRepoFile(t, wtPath, "gone.txt", "work\n")
+	runGit(t, wtPath, "add", "gone.txt")
+	runGit(t, wtPath, "commit", "-m", "Work on gone")
+	head := runGit(t, wtPath, "rev-parse", "HEAD")
+
+	m := NewModel(&config.AppConfig{WorktreeDir: t.TempDir(), RecentlyDeletedDays: 14}, "")
+	m.repoKey = "recently-deleted-test"
+	wt := &models.WorktreeInfo{Path: wtPath, Branch: "gone", PR: &models.PRInfo{Number: 5, URL: "https://example.com/pull/5"}}
+	m.worktrees = []*models.WorktreeInfo{wt}
+
+	if msg, ok := m.deleteWorktreeOnlyCmd(wt)()().(worktreeDeletedMsg); !ok || msg.err != nil {
+		t.Fatalf("expected the worktree to be deleted, got %+v", msg)
+	}
+	runGit(t, repo.dir, "branch", "-D", "gone")
+
+	_ = m.showRecentlyDeleted()
+	if m.currentScreen != screenListSelect || len(m.listScreen.items) != 1 {
+		t.Fatalf("expected one deleted worktree, got %s", screenName(m.currentScreen))
+	}
+	item := m.listScreen.items[0]
+	if item.label != "gone" || !strings.Contains(item.description, head[:7]) || !strings.Contains(item.description, "PR #5") {
+		t.Fatalf("unexpected entry %+v", item)
+	}
+
+	msg, ok := m.listSubmit(item)().(deletedRecreatedMsg)
+	if !ok || msg.err != nil || msg.moved {
+		t.Fatalf("expected the worktree to be recreated, got %+v", msg)
+	}
+	if got := runGit(t, wtPath, "rev-parse", "--abbrev-ref", "HEAD"); got != "gone" {
+		t.Fatalf("expected branch gone, got %q", got)
+	}
+	if got := runGit(t, wtPath, "rev-parse", "HEAD"); got != head {
+		t.Fatalf("expected the last commit %s, got %s", head, got)
+	}
+	if cmd := m.handleDeletedRecreated(msg); cmd == nil || m.pendingSelectWorktreePath != wtPath {
+		t.Fatal("expected the worktrees to reload with the recreated one selected")
+	}
+	if list := deleted.Load(deleted.Path(m.repoKey)); len(list) != 0 {
+		t.Fatalf("expected the entry to be forgotten, got %+v", list)
+	}
+}
+
+func TestRecentlyDeletedDisabled(t *testing.T) {
+	m := NewModel(&config.AppConfig{WorktreeDir: t.TempDir()}, "")
+	if cmd := m.showRecentlyDeleted(); cmd != nil || m.currentScreen != screenInfo {
+		t.Fatal("expected an explanation when deleted worktrees are not kept")
+	}
+}
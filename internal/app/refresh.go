@@ -0,0 +1,121 @@
+package app
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+// refreshCoalesceDelay gathers refresh requests arriving close together,
+// such as repeated presses of r, into a single worktree scan.
+const refreshCoalesceDelay = 150 * time.Millisecond
+
+// refreshDueMsg fires when the coalescing delay of a refresh request ends.
+type refreshDueMsg struct{}
+
+// requestRefresh asks for a full worktree scan. Requests made while one is
+// scheduled are merged into it, and requests made while a scan is running
+// queue a single follow-up scan rather than starting another.
+func (m *Model) requestRefresh() tea.Cmd {
+	if m.refreshRunning {
+		m.refreshQueued = true
+		return nil
+	}
+	if m.refreshScheduled {
+		return nil
+	}
+	m.refreshScheduled = true
+	return tea.Tick(refreshCoalesceDelay, func(time.Time) tea.Msg {
+		return refreshDueMsg{}
+	})
+}
+
+// queueRefresh is a command requesting a refresh once it runs, for actions
+// deferred until a dialogue closes.
+func queueRefresh() tea.Msg {
+	return refreshDueMsg{}
+}
+
+// startRefresh runs a full worktree scan straight away. In SSH mode it
+// first drops the cached host output, so the scan sees fresh state.
+func (m *Model) startRefresh() tea.Cmd {
+	m.refreshScheduled = false
+	m.refreshRunning = true
+	m.git.InvalidateRemoteCache()
+	return m.refreshWorktrees()
+}
+
+// finishRefresh records the end of a scan and starts the queued one, if any.
+func (m *Model) finishRefresh() tea.Cmd {
+	m.refreshRunning = false
+	if !m.refreshQueued {
+		return nil
+	}
+	m.refreshQueued = false
+	return m.requestRefresh()
+}
+
+// worktreeRefreshedMsg carries a single worktree recomputed by git.
+type worktreeRefreshedMsg struct {
+	wt  *models.WorktreeInfo
+	err error
+}
+
+// requestWorktreeRefresh updates a single worktree instead of rescanning
+// them all. It is a no-op while a full scan is pending, as that covers it.
+func (m *Model) requestWorktreeRefresh(path string) tea.Cmd {
+	if m.refreshScheduled || m.refreshQueued {
+		return nil
+	}
+	delete(m.detailsCache, path)
+	cmds := []tea.Cmd{m.refreshWorktree(path)}
+	if wt := m.selectedWorktree(); wt != nil && wt.Path == path {
+		cmds = append(cmds, m.updateDetailsView())
+	}
+	return tea.Batch(cmds...)
+}
+
+// refreshSelectedWorktree reloads the selected worktree's details and its
+// row in the table, after an action that only touched that worktree.
+func (m *Model) refreshSelectedWorktree() tea.Cmd {
+	wt := m.selectedWorktree()
+	if wt == nil {
+		return m.updateDetailsView()
+	}
+	return tea.Batch(m.updateDetailsView(), m.refreshWorktree(wt.Path))
+}
+
+// refreshWorktree recomputes one worktree in the background.
+func (m *Model) refreshWorktree(path string) tea.Cmd {
+	return func() tea.Msg {
+		wt, err := m.git.RefreshWorktree(m.ctx, path)
+		return worktreeRefreshedMsg{wt: wt, err: err}
+	}
+}
+
+// handleWorktreeRefreshed patches a recomputed worktree into the table,
+// keeping the PR and access data the scan does not know about.
+func (m *Model) handleWorktreeRefreshed(msg worktreeRefreshedMsg) {
+	if msg.err != nil {
+		m.debugf("worktree refresh: %v", msg.err)
+		return
+	}
+	for i, wt := range m.worktrees {
+		if wt.Path != msg.wt.Path {
+			continue
+		}
+		fresh := *msg.wt
+		if fresh.Branch == wt.Branch {
+			fresh.PR = wt.PR
+			fresh.PRFetchError = wt.PRFetchError
+			fresh.PRFetchStatus = wt.PRFetchStatus
+		}
+		fresh.LastSwitchedTS = wt.LastSwitchedTS
+		fresh.Divergence = wt.Divergence
+		m.observeWorktree(wt, &fresh)
+		*m.worktrees[i] = fresh
+		m.updateTable()
+		return
+	}
+}
@@ -0,0 +1,117 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/chmouel/lazyworktree/internal/config"
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+func TestRequestRefreshCoalesces(t *testing.T) {
+	m := NewModel(&config.AppConfig{WorktreeDir: t.TempDir()}, "")
+
+	first := m.requestRefresh()
+	if first == nil || !m.refreshScheduled {
+		t.Fatal("expected the first request to schedule a refresh")
+	}
+	if cmd := m.requestRefresh(); cmd != nil {
+		t.Fatal("expected a second request to merge into the scheduled one")
+	}
+	if _, ok := first().(refreshDueMsg); !ok {
+		t.Fatal("expected the scheduled refresh to fire")
+	}
+
+	_, scan := m.Update(refreshDueMsg{})
+	if scan == nil || !m.refreshRunning || m.refreshScheduled {
+		t.Fatal("expected the refresh to start scanning")
+	}
+	if cmd := m.requestRefresh(); cmd != nil || !m.refreshQueued {
+		t.Fatal("expected a request during a scan to be queued")
+	}
+	if cmd := m.requestRefresh(); cmd != nil {
+		t.Fatal("expected further requests during a scan to be dropped")
+	}
+
+	msg, ok := scan().(worktreesLoadedMsg)
+	if !ok {
+		t.Fatal("expected the scan to load worktrees")
+	}
+	_, followUp := m.Update(msg)
+	if m.refreshRunning || m.refreshQueued || !m.refreshScheduled || followUp == nil {
+		t.Fatal("expected the queued request to schedule one follow-up refresh")
+	}
+}
+
+func TestRefreshDueWhileRunningQueues(t *testing.T) {
+	m := NewModel(&config.AppConfig{WorktreeDir: t.TempDir()}, "")
+	m.startRefresh()
+
+	if _, cmd := m.Update(refreshDueMsg{}); cmd != nil || !m.refreshQueued {
+		t.Fatal("expected a deferred refresh during a scan to be queued")
+	}
+}
+
+func TestRequestWorktreeRefresh(t *testing.T) {
+	m := NewModel(&config.AppConfig{WorktreeDir: t.TempDir()}, "")
+	wt := &models.WorktreeInfo{Path: t.TempDir(), Branch: featureBranch}
+	m.worktrees = []*models.WorktreeInfo{wt}
+	m.updateTable()
+	m.worktreeTable.SetCursor(0)
+	m.worktreesLoaded = true
+	m.detailsCache[wt.Path] = &detailsCacheEntry{}
+
+	if cmd := m.requestWorktreeRefresh(wt.Path); cmd == nil {
+		t.Fatal("expected the selected worktree's details to reload")
+	}
+	if _, ok := m.detailsCache[wt.Path]; ok {
+		t.Fatal("expected the worktree's cached details to be dropped")
+	}
+
+	m.requestRefresh()
+	if cmd := m.requestWorktreeRefresh(wt.Path); cmd != nil {
+		t.Fatal("expected no targeted update while a full refresh is pending")
+	}
+}
+
+func TestHandleWorktreeRefreshedKeepsPRState(t *testing.T) {
+	m := NewModel(&config.AppConfig{WorktreeDir: t.TempDir()}, "")
+	pr := &models.PRInfo{Number: 7}
+	m.worktrees = []*models.WorktreeInfo{
+		{Path: "/tmp/feat", Branch: featureBranch, PR: pr, PRFetchStatus: "loaded", LastSwitchedTS: 42, Ahead: 2},
+	}
+	m.updateTable()
+
+	m.Update(worktreeRefreshedMsg{wt: &models.WorktreeInfo{Path: "/tmp/feat", Branch: featureBranch, Dirty: true, Untracked: 1}})
+
+	wt := m.worktrees[0]
+	if !wt.Dirty || wt.Untracked != 1 || wt.Ahead != 0 {
+		t.Fatalf("expected status fields to be refreshed, got %+v", wt)
+	}
+	if wt.PR != pr || wt.PRFetchStatus != "loaded" || wt.LastSwitchedTS != 42 {
+		t.Fatalf("expected PR and access data to be kept, got %+v", wt)
+	}
+}
+
+func TestRefreshInvalidatesRemoteCache(t *testing.T) {
+	tests := []struct {
+		name    string
+		refresh func(m *Model)
+	}{
+		{"manual", func(m *Model) { m.startRefresh() }},
+		{"rescan", func(m *Model) {
+			m.loading = false
+			m.backgroundRefresh()
+		}},
+		{"watcher", func(m *Model) { m.flushWorktreeWatch() }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := newFakeGitService()
+			m := NewModelWithGit(&config.AppConfig{WorktreeDir: t.TempDir()}, "", fake)
+			tt.refresh(m)
+			if !fake.ran("InvalidateRemoteCache") {
+				t.Fatal("expected the cached remote output to be dropped")
+			}
+		})
+	}
+}
@@ -0,0 +1,20 @@
+package app
+
+import (
+	"fmt"
+	"time"
+)
+
+// remoteSummary renders, in SSH mode, the host and how long its commands
+// take for the header.
+func (m *Model) remoteSummary() string {
+	host := m.git.RemoteHost()
+	if host == "" {
+		return ""
+	}
+	latency := m.git.RemoteLatency()
+	if latency == 0 {
+		return "ssh " + host
+	}
+	return fmt.Sprintf("ssh %s · %s", host, latency.Round(time.Millisecond))
+}
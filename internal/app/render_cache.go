@@ -0,0 +1,55 @@
+package app
+
+import "github.com/chmouel/lazyworktree/internal/theme"
+
+// renderKey captures everything a memoised render depends on. Parts hold the
+// already rendered inputs, so comparing keys is a handful of string compares
+// and far cheaper than running lipgloss again.
+type renderKey struct {
+	theme   *theme.Theme
+	width   int
+	height  int
+	focused bool
+	parts   [3]string
+}
+
+// renderMemo keeps the output of the last render alongside its key.
+type renderMemo struct {
+	key  renderKey
+	view string
+	ok   bool
+}
+
+// render returns the previous output when key is unchanged and calls fn
+// otherwise. View runs on every message, spinner ticks included, so most
+// frames leave the panes untouched.
+func (r *renderMemo) render(key renderKey, fn func() string) string {
+	if r.ok && r.key == key {
+		return r.view
+	}
+	r.key = key
+	r.view = fn()
+	r.ok = true
+	return r.view
+}
+
+// renderCaches groups the memoised pieces of the main layout.
+type renderCaches struct {
+	panes   [3]renderMemo
+	infoBox renderMemo
+	body    renderMemo
+
+	// layout is the last layout applied to the tables.
+	layout    layoutDims
+	layoutSet bool
+}
+
+// setStatusViewportContent only hands content to the status viewport when it
+// changed, since SetContent splits and measures every line.
+func (m *Model) setStatusViewportContent(content string) {
+	if content == m.statusViewportSet {
+		return
+	}
+	m.statusViewportSet = content
+	m.statusViewport.SetContent(content)
+}
@@ -0,0 +1,58 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/chmouel/lazyworktree/internal/config"
+	"github.com/chmouel/lazyworktree/internal/theme"
+)
+
+func TestRenderMemo(t *testing.T) {
+	var memo renderMemo
+	calls := 0
+	render := func() string {
+		calls++
+		return "view"
+	}
+
+	key := renderKey{theme: theme.Dracula(), width: 80, height: 20, parts: [3]string{"title", "body"}}
+	memo.render(key, render)
+	if got := memo.render(key, render); got != "view" || calls != 1 {
+		t.Fatalf("expected a cached render, got %q after %d calls", got, calls)
+	}
+
+	for _, changed := range []renderKey{
+		{theme: theme.Nord(), width: 80, height: 20, parts: key.parts},
+		{theme: key.theme, width: 81, height: 20, parts: key.parts},
+		{theme: key.theme, width: 80, height: 20, focused: true, parts: key.parts},
+		{theme: key.theme, width: 80, height: 20, parts: [3]string{"title", "other"}},
+	} {
+		before := calls
+		memo.render(changed, render)
+		if calls != before+1 {
+			t.Fatalf("expected %+v to invalidate the cache", changed)
+		}
+	}
+}
+
+func TestViewReusesUnchangedPanes(t *testing.T) {
+	m := NewModel(&config.AppConfig{WorktreeDir: t.TempDir()}, "")
+	m.windowWidth = 120
+	m.windowHeight = 40
+	m.worktrees = benchWorktrees(20)
+	m.updateTable()
+
+	first := m.View()
+	cached := m.renders.panes[0].view
+	if m.View() != first {
+		t.Fatal("expected an identical frame when nothing changed")
+	}
+
+	m.focusedPane = 1
+	if m.View() == first {
+		t.Fatal("expected the frame to change with focus")
+	}
+	if m.renders.panes[0].view == cached {
+		t.Fatal("expected the worktree pane to re-render when it loses focus")
+	}
+}
@@ -25,10 +25,81 @@ func (m *Model) renderHeader(layout layoutDims) string {
 	if repoKey != "" && repoKey != "unknown" && !strings.HasPrefix(repoKey, "local-") {
 		content = fmt.Sprintf("%s  •  %s", content, repoKey)
 	}
+	switch {
+	case m.config != nil && m.config.SafeMode:
+		content += "  •  safe mode"
+	case m.config != nil && m.config.Observe:
+		content += "  •  observing"
+	case m.config != nil && m.config.ReadOnly:
+		content += "  •  read-only"
+	}
+	if summary := m.remoteSummary(); summary != "" {
+		content = fmt.Sprintf("%s  •  %s", content, summary)
+	}
+	if m.config != nil {
+		if summary := m.quotaSummary(); summary != "" {
+			content = fmt.Sprintf("%s  •  %s", content, summary)
+		}
+	}
+	if summary := m.partialCloneSummary(); summary != "" {
+		content = fmt.Sprintf("%s  •  %s", content, summary)
+	}
+	if m.colocated.Enabled() {
+		content = fmt.Sprintf("%s  •  %s", content, m.colocated)
+	}
+	if pill := m.quickFilterPill(); pill != "" {
+		content = fmt.Sprintf("%s  •  %s", content, pill)
+	}
+	switch {
+	case m.macroRecording:
+		content += "  •  ● recording macro"
+	case len(m.macroQueue) > 0:
+		content = fmt.Sprintf("%s  •  ▶ %s", content, m.macroRunning)
+	}
 
 	return headerStyle.Render(content)
 }
 
+// quickFilterPill shows the worktree filters still applied once the filter
+// input is closed, with the keys to re-edit and clear them, so a shorter
+// list is never a mystery.
+func (m *Model) quickFilterPill() string {
+	if m.showingFilter && m.filterTarget == filterTargetWorktrees {
+		return ""
+	}
+	var parts []string
+	if query := strings.TrimSpace(m.filterQuery); query != "" {
+		parts = append(parts, fmt.Sprintf("%q", query))
+	}
+	if m.projectFilter != "" {
+		parts = append(parts, "project "+m.projectFilter)
+	}
+	if m.focusBranch != "" {
+		parts = append(parts, "focus "+m.focusBranch)
+	}
+	if m.ownerFilter {
+		parts = append(parts, "mine")
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	keys := "x clear"
+	if m.focusedPane == 0 && strings.TrimSpace(m.filterQuery) != "" {
+		keys = "f edit · " + keys
+	}
+	pill := lipgloss.NewStyle().
+		Foreground(m.theme.Accent).
+		Background(m.theme.AccentFg).
+		Bold(true).
+		Padding(0, 1)
+	// The pill's reset would drop the header colours for what follows it
+	header := lipgloss.NewStyle().
+		Foreground(m.theme.AccentFg).
+		Background(m.theme.Accent).
+		Bold(true)
+	return pill.Render("🔍 "+strings.Join(parts, " + ")) + header.Render(" "+keys)
+}
+
 // renderFilter renders the filter input bar.
 func (m *Model) renderFilter(layout layoutDims) string {
 	labelStyle := lipgloss.NewStyle().
@@ -122,14 +193,48 @@ func (m *Model) renderFooter(layout layoutDims) string {
 	}
 
 	footerContent := strings.Join(hints, "  ")
-	if !m.loading {
+	if tour := m.demoTourView(); tour != "" {
+		footerContent = tour
+	}
+	var extras []string
+	if count := m.errorCountView(); count != "" {
+		extras = append(extras, count)
+	}
+	if prs := m.prPipelineView(); prs != "" {
+		extras = append(extras, prs)
+	}
+	if queue := m.queueDepthView(); queue != "" {
+		extras = append(extras, queue)
+	}
+	if m.loading {
+		extras = append(extras, m.spinner.View())
+	}
+	if len(extras) == 0 {
 		return footerStyle.Width(layout.width).Render(footerContent)
 	}
-	spinnerView := m.spinner.View()
 	gap := "  "
-	available := maxInt(layout.width-lipgloss.Width(spinnerView)-lipgloss.Width(gap), 0)
+	extra := strings.Join(extras, gap)
+	available := maxInt(layout.width-lipgloss.Width(extra)-lipgloss.Width(gap), 0)
 	footer := footerStyle.Width(available).Render(footerContent)
-	return lipgloss.JoinHorizontal(lipgloss.Left, footer, gap, spinnerView)
+	return lipgloss.JoinHorizontal(lipgloss.Left, footer, gap, extra)
+}
+
+// queueDepthView shows how many commands wait for a free slot, per pool.
+func (m *Model) queueDepthView() string {
+	depth := m.git.QueueDepth()
+	if depth.Total() == 0 {
+		return ""
+	}
+	var parts []string
+	for _, pool := range []struct {
+		name  string
+		count int
+	}{{"git", depth.Local}, {"net", depth.Network}, {"cmd", depth.Commands}} {
+		if pool.count > 0 {
+			parts = append(parts, fmt.Sprintf("%s %d", pool.name, pool.count))
+		}
+	}
+	return lipgloss.NewStyle().Foreground(m.theme.MutedFg).Render("queued: " + strings.Join(parts, " · "))
 }
 
 // renderKeyHint renders a single key hint with enhanced styling.
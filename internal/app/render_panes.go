@@ -24,86 +24,69 @@ func (m *Model) renderBody(layout layoutDims) string {
 	}
 
 	left := m.renderLeftPane(layout)
-	right := m.renderRightPane(layout)
-	gap := lipgloss.NewStyle().
-		Width(layout.gapX).
-		Render(strings.Repeat(" ", layout.gapX))
-	return lipgloss.JoinHorizontal(lipgloss.Top, left, gap, right)
+	top := m.renderRightTopPane(layout)
+	bottom := m.renderRightBottomPane(layout)
+	key := renderKey{width: layout.gapX, height: layout.gapY, parts: [3]string{left, top, bottom}}
+	return m.renders.body.render(key, func() string {
+		right := lipgloss.JoinVertical(lipgloss.Left, top, strings.Repeat("\n", layout.gapY), bottom)
+		gap := lipgloss.NewStyle().
+			Width(layout.gapX).
+			Render(strings.Repeat(" ", layout.gapX))
+		return lipgloss.JoinHorizontal(lipgloss.Top, left, gap, right)
+	})
 }
 
 // renderLeftPane renders the left pane (worktree table).
 func (m *Model) renderLeftPane(layout layoutDims) string {
-	title := m.renderPaneTitle(1, "Worktrees", m.focusedPane == 0, layout.leftInnerWidth)
-	tableView := m.worktreeTable.View()
-	content := lipgloss.JoinVertical(lipgloss.Left, title, tableView)
-	return m.paneStyle(m.focusedPane == 0).
-		Width(layout.leftWidth).
-		Height(layout.bodyHeight).
-		Render(content)
-}
-
-// renderRightPane renders the right pane container (status + log).
-func (m *Model) renderRightPane(layout layoutDims) string {
-	top := m.renderRightTopPane(layout)
-	bottom := m.renderRightBottomPane(layout)
-	gap := strings.Repeat("\n", layout.gapY)
-	return lipgloss.JoinVertical(lipgloss.Left, top, gap, bottom)
+	return m.renderWorktreesPane(layout, m.focusedPane == 0)
 }
 
 // renderRightTopPane renders the right top pane (status viewport).
 func (m *Model) renderRightTopPane(layout layoutDims) string {
-	title := m.renderPaneTitle(2, "Status", m.focusedPane == 1, layout.rightInnerWidth)
-	infoBox := m.renderInnerBox("Info", m.infoContent, layout.rightInnerWidth, 0)
-
-	innerBoxStyle := m.baseInnerBoxStyle()
-	statusBoxHeight := maxInt(layout.rightTopInnerHeight-lipgloss.Height(title)-lipgloss.Height(infoBox)-2, 3)
-	statusViewportWidth := maxInt(1, layout.rightInnerWidth-innerBoxStyle.GetHorizontalFrameSize())
-	statusViewportHeight := maxInt(1, statusBoxHeight-innerBoxStyle.GetVerticalFrameSize())
-	m.statusViewport.Width = statusViewportWidth
-	m.statusViewport.Height = statusViewportHeight
-	m.statusViewport.SetContent(m.statusContent)
-	statusBox := innerBoxStyle.
-		Width(layout.rightInnerWidth).
-		Height(statusBoxHeight).
-		Render(m.statusViewport.View())
-
-	content := lipgloss.JoinVertical(
-		lipgloss.Left,
-		title,
-		infoBox,
-		statusBox,
-	)
-	return m.paneStyle(m.focusedPane == 1).
-		Width(layout.rightWidth).
-		Height(layout.rightTopHeight).
-		Render(content)
+	return m.renderStatusPane(layout, m.focusedPane == 1, layout.rightTopHeight)
 }
 
 // renderRightBottomPane renders the right bottom pane (log table).
 func (m *Model) renderRightBottomPane(layout layoutDims) string {
-	title := m.renderPaneTitle(3, "Log", m.focusedPane == 2, layout.rightInnerWidth)
-	content := lipgloss.JoinVertical(lipgloss.Left, title, m.logTable.View())
-	return m.paneStyle(m.focusedPane == 2).
-		Width(layout.rightWidth).
-		Height(layout.rightBottomHeight).
-		Render(content)
+	return m.renderLogPane(layout, m.focusedPane == 2, layout.rightBottomHeight)
 }
 
 // renderZoomedLeftPane renders the zoomed left pane.
 func (m *Model) renderZoomedLeftPane(layout layoutDims) string {
-	title := m.renderPaneTitle(1, "Worktrees", true, layout.leftInnerWidth)
-	tableView := m.worktreeTable.View()
-	content := lipgloss.JoinVertical(lipgloss.Left, title, tableView)
-	return m.paneStyle(true).
-		Width(layout.leftWidth).
-		Height(layout.bodyHeight).
-		Render(content)
+	return m.renderWorktreesPane(layout, true)
 }
 
 // renderZoomedRightTopPane renders the zoomed right top pane.
 func (m *Model) renderZoomedRightTopPane(layout layoutDims) string {
-	title := m.renderPaneTitle(2, "Status", true, layout.rightInnerWidth)
-	infoBox := m.renderInnerBox("Info", m.infoContent, layout.rightInnerWidth, 0)
+	return m.renderStatusPane(layout, true, layout.bodyHeight)
+}
+
+// renderZoomedRightBottomPane renders the zoomed right bottom pane.
+func (m *Model) renderZoomedRightBottomPane(layout layoutDims) string {
+	return m.renderLogPane(layout, true, layout.bodyHeight)
+}
+
+// renderWorktreesPane renders the worktree table pane.
+func (m *Model) renderWorktreesPane(layout layoutDims, focused bool) string {
+	title := m.renderPaneTitle(1, m.worktreePaneTitle(), focused, layout.leftInnerWidth)
+	tableView := m.worktreeTable.View()
+	key := renderKey{theme: m.theme, width: layout.leftWidth, height: layout.bodyHeight, focused: focused, parts: [3]string{title, tableView}}
+	return m.renders.panes[0].render(key, func() string {
+		content := lipgloss.JoinVertical(lipgloss.Left, title, tableView)
+		return m.paneStyle(focused).
+			Width(layout.leftWidth).
+			Height(layout.bodyHeight).
+			Render(content)
+	})
+}
+
+// renderStatusPane renders the info box and the status viewport.
+func (m *Model) renderStatusPane(layout layoutDims, focused bool, height int) string {
+	title := m.renderPaneTitle(2, m.statusPaneTitle(), focused, layout.rightInnerWidth)
+	infoKey := renderKey{theme: m.theme, width: layout.rightInnerWidth, parts: [3]string{m.infoContent}}
+	infoBox := m.renders.infoBox.render(infoKey, func() string {
+		return m.renderInnerBox("Info", m.infoContent, layout.rightInnerWidth, 0)
+	})
 
 	innerBoxStyle := m.baseInnerBoxStyle()
 	statusBoxHeight := maxInt(layout.rightTopInnerHeight-lipgloss.Height(title)-lipgloss.Height(infoBox)-2, 3)
@@ -111,32 +94,43 @@ func (m *Model) renderZoomedRightTopPane(layout layoutDims) string {
 	statusViewportHeight := maxInt(1, statusBoxHeight-innerBoxStyle.GetVerticalFrameSize())
 	m.statusViewport.Width = statusViewportWidth
 	m.statusViewport.Height = statusViewportHeight
-	m.statusViewport.SetContent(m.statusContent)
-	statusBox := innerBoxStyle.
-		Width(layout.rightInnerWidth).
-		Height(statusBoxHeight).
-		Render(m.statusViewport.View())
-
-	content := lipgloss.JoinVertical(
-		lipgloss.Left,
-		title,
-		infoBox,
-		statusBox,
-	)
-	return m.paneStyle(true).
-		Width(layout.rightWidth).
-		Height(layout.bodyHeight).
-		Render(content)
+	m.setStatusViewportContent(m.statusPaneContent(statusViewportWidth))
+	statusView := m.statusViewport.View()
+
+	key := renderKey{theme: m.theme, width: layout.rightWidth, height: height, focused: focused, parts: [3]string{title, infoBox, statusView}}
+	return m.renders.panes[1].render(key, func() string {
+		statusBox := innerBoxStyle.
+			Width(layout.rightInnerWidth).
+			Height(statusBoxHeight).
+			Render(statusView)
+		content := lipgloss.JoinVertical(
+			lipgloss.Left,
+			title,
+			infoBox,
+			statusBox,
+		)
+		return m.paneStyle(focused).
+			Width(layout.rightWidth).
+			Height(height).
+			Render(content)
+	})
 }
 
-// renderZoomedRightBottomPane renders the zoomed right bottom pane.
-func (m *Model) renderZoomedRightBottomPane(layout layoutDims) string {
-	title := m.renderPaneTitle(3, "Log", true, layout.rightInnerWidth)
-	content := lipgloss.JoinVertical(lipgloss.Left, title, m.logTable.View())
-	return m.paneStyle(true).
-		Width(layout.rightWidth).
-		Height(layout.bodyHeight).
-		Render(content)
+// renderLogPane renders the commit log pane.
+func (m *Model) renderLogPane(layout layoutDims, focused bool, height int) string {
+	if m.observing() {
+		return m.renderObservePane(layout, focused, height)
+	}
+	title := m.renderPaneTitle(3, "Log", focused, layout.rightInnerWidth)
+	logView := m.logTable.View()
+	key := renderKey{theme: m.theme, width: layout.rightWidth, height: height, focused: focused, parts: [3]string{title, logView}}
+	return m.renders.panes[2].render(key, func() string {
+		content := lipgloss.JoinVertical(lipgloss.Left, title, logView)
+		return m.paneStyle(focused).
+			Width(layout.rightWidth).
+			Height(height).
+			Render(content)
+	})
 }
 
 // buildInfoContent builds the info content string for a worktree.
@@ -144,6 +138,9 @@ func (m *Model) buildInfoContent(wt *models.WorktreeInfo) string {
 	if wt == nil {
 		return errNoWorktreeSelected
 	}
+	if m.infoTemplate != nil || m.infoTemplateErr != nil {
+		return m.renderInfoTemplate(wt)
+	}
 
 	labelStyle := lipgloss.NewStyle().Foreground(m.theme.Cyan).Bold(true)
 	valueStyle := lipgloss.NewStyle().Foreground(m.theme.TextFg)
@@ -152,17 +149,34 @@ func (m *Model) buildInfoContent(wt *models.WorktreeInfo) string {
 		fmt.Sprintf("%s %s", labelStyle.Render("Path:"), valueStyle.Render(wt.Path)),
 		fmt.Sprintf("%s %s", labelStyle.Render("Branch:"), valueStyle.Render(wt.Branch)),
 	}
+	infoLines = append(infoLines, m.worktreeStateLines(wt, labelStyle, valueStyle)...)
+	if m.isExternalWorktree(wt) {
+		warnStyle := lipgloss.NewStyle().Foreground(m.theme.WarnFg)
+		infoLines = append(infoLines, fmt.Sprintf("%s %s", labelStyle.Render("Location:"), warnStyle.Render("outside the worktree directory; adopt it from the command palette")))
+	}
 	if wt.LastSwitchedTS > 0 {
-		accessTime := time.Unix(wt.LastSwitchedTS, 0)
-		relTime := formatRelativeTime(accessTime)
-		infoLines = append(infoLines, fmt.Sprintf("%s %s", labelStyle.Render("Last Accessed:"), valueStyle.Render(relTime)))
+		accessed := m.formatDate(time.Unix(wt.LastSwitchedTS, 0))
+		infoLines = append(infoLines, fmt.Sprintf("%s %s", labelStyle.Render("Last Accessed:"), valueStyle.Render(accessed)))
 	}
+	infoLines = append(infoLines, m.activityLines(wt, labelStyle, valueStyle)...)
 	if wt.Divergence != "" {
 		// Colorize arrows to match Python: cyan ↑, red ↓
 		coloredDiv := strings.ReplaceAll(wt.Divergence, "↑", lipgloss.NewStyle().Foreground(m.theme.Cyan).Render("↑"))
 		coloredDiv = strings.ReplaceAll(coloredDiv, "↓", lipgloss.NewStyle().Foreground(m.theme.ErrorFg).Render("↓"))
 		infoLines = append(infoLines, fmt.Sprintf("%s %s", labelStyle.Render("Divergence:"), coloredDiv))
 	}
+	if line := m.divergenceLine(wt, labelStyle, valueStyle); line != "" {
+		infoLines = append(infoLines, line)
+	}
+	infoLines = append(infoLines, m.remoteURLLines(wt, labelStyle, valueStyle)...)
+	infoLines = append(infoLines, m.diffStatsLines(wt, labelStyle, valueStyle)...)
+	infoLines = append(infoLines, m.projectLines(wt, labelStyle, valueStyle)...)
+	infoLines = append(infoLines, m.codeOwnerLines(wt, labelStyle, valueStyle)...)
+	infoLines = append(infoLines, m.composeLines(wt, labelStyle, valueStyle)...)
+	infoLines = append(infoLines, m.envToolLines(wt, labelStyle)...)
+	infoLines = append(infoLines, m.stackLines(wt, labelStyle, valueStyle)...)
+	infoLines = append(infoLines, m.changeLines(wt, labelStyle, valueStyle)...)
+	infoLines = append(infoLines, m.commitLintLines(wt.Path)...)
 	if wt.PR != nil {
 		// Match Python: white number, colored state (green=OPEN, magenta=MERGED, red=else)
 		prLabelStyle := lipgloss.NewStyle().Foreground(m.theme.Pink).Bold(true) // Pink for PR prominence
@@ -203,6 +217,16 @@ func (m *Model) buildInfoContent(wt *models.WorktreeInfo) string {
 		// URL styled with cyan for consistency
 		urlStyle := lipgloss.NewStyle().Foreground(m.theme.Cyan).Underline(true)
 		infoLines = append(infoLines, fmt.Sprintf("     %s", urlStyle.Render(wt.PR.URL)))
+		if review := m.formatReviewDecision(wt.PR.ReviewDecision); review != "" {
+			infoLines = append(infoLines, fmt.Sprintf("     %s", review))
+		}
+		if deployment := m.deployments[wt.Path]; deployment != nil {
+			line := fmt.Sprintf("%s Deployed to %s (%s)", deploymentSymbol(deployment.State), deployment.Environment, deployment.State)
+			if deployment.URL != "" {
+				line += " " + urlStyle.Render(deployment.URL)
+			}
+			infoLines = append(infoLines, fmt.Sprintf("     %s", line))
+		}
 
 		// CI status from cache
 		if cached, ok := m.ciCache[wt.Branch]; ok && len(cached.checks) > 0 {
@@ -244,6 +268,7 @@ func (m *Model) buildInfoContent(wt *models.WorktreeInfo) string {
 				}
 				infoLines = append(infoLines, fmt.Sprintf("  %s %s", style.Render(symbol), check.Name))
 			}
+			infoLines = append(infoLines, m.ciFindingLines(wt, labelStyle)...)
 		}
 	} else {
 		// Show PR status/error when PR is nil
@@ -320,14 +345,39 @@ func (m *Model) renderStatusFiles() string {
 	untrackedStyle := lipgloss.NewStyle().Foreground(m.theme.Yellow)
 	stagedStyle := lipgloss.NewStyle().Foreground(m.theme.Cyan)
 	dirStyle := lipgloss.NewStyle().Foreground(m.theme.MutedFg)
+	ciMarkerStyle := lipgloss.NewStyle().Foreground(m.theme.ErrorFg)
 	selectedStyle := lipgloss.NewStyle().
 		Foreground(m.theme.AccentFg).
 		Background(m.theme.Accent).
 		Bold(true)
 
 	viewportWidth := m.statusViewport.Width
+	ciFindings := m.ciFindingCounts()
+	ciMarker := func(node *StatusTreeNode) string {
+		if n := ciFindings[node.Path]; n > 0 && !node.IsDir() {
+			return fmt.Sprintf(" ✗%d", n)
+		}
+		return ""
+	}
+	styledCIMarker := func(node *StatusTreeNode) string {
+		if marker := ciMarker(node); marker != "" {
+			return ciMarkerStyle.Render(marker)
+		}
+		return ""
+	}
 
-	lines := make([]string, 0, len(m.statusTreeFlat))
+	// One builder for the whole tree and one reused for each status column
+	// keep allocations flat on large change sets.
+	var out, statusRendered strings.Builder
+	out.Grow(len(m.statusTreeFlat) * 48)
+	emitted := 0
+	emit := func(line string) {
+		if emitted > 0 {
+			out.WriteByte('\n')
+		}
+		out.WriteString(line)
+		emitted++
+	}
 	for i, node := range m.statusTreeFlat {
 		indent := strings.Repeat("  ", node.depth)
 
@@ -351,7 +401,7 @@ func (m *Model) renderStatusFiles() string {
 			if m.config.ShowIcons {
 				fileIcon = iconWithSpace(deviconForName(node.Name(), false))
 			}
-			lineContent = fmt.Sprintf("%s  %s %s%s", indent, displayStatus, fileIcon, node.Name())
+			lineContent = fmt.Sprintf("%s  %s %s%s%s", indent, displayStatus, fileIcon, node.Name(), ciMarker(node))
 		}
 
 		// Apply styling based on selection and node type
@@ -360,22 +410,21 @@ func (m *Model) renderStatusFiles() string {
 			if viewportWidth > 0 && len(lineContent) < viewportWidth {
 				lineContent += strings.Repeat(" ", viewportWidth-len(lineContent))
 			}
-			lines = append(lines, selectedStyle.Render(lineContent))
+			emit(selectedStyle.Render(lineContent))
 		case node.IsDir():
-			lines = append(lines, dirStyle.Render(lineContent))
+			emit(dirStyle.Render(lineContent))
 		default:
 			// Color based on file status - apply different colors for staged vs unstaged
 			status := node.File.Status
 			if len(status) < 2 {
-				lines = append(lines, lineContent)
+				emit(lineContent)
 				continue
 			}
 
 			// Special case for untracked files
 			if status == " ?" {
 				displayStatus := formatStatusDisplay(status)
-				formatted := fmt.Sprintf("%s  %s %s%s", indent, untrackedStyle.Render(displayStatus), fileIcon, node.Name())
-				lines = append(lines, formatted)
+				emit(fmt.Sprintf("%s  %s %s%s%s", indent, untrackedStyle.Render(displayStatus), fileIcon, node.Name(), styledCIMarker(node)))
 				continue
 			}
 
@@ -384,7 +433,7 @@ func (m *Model) renderStatusFiles() string {
 			displayStatus := formatStatusDisplay(status)
 
 			// Render each character with appropriate color based on position
-			var statusRendered strings.Builder
+			statusRendered.Reset()
 			for i, char := range displayStatus {
 				if char == ' ' {
 					statusRendered.WriteString(" ")
@@ -423,9 +472,22 @@ func (m *Model) renderStatusFiles() string {
 				}
 				statusRendered.WriteString(style.Render(string(char)))
 			}
-			formatted := fmt.Sprintf("%s  %s %s%s", indent, statusRendered.String(), fileIcon, node.Name())
-			lines = append(lines, formatted)
+			emit(fmt.Sprintf("%s  %s %s%s%s", indent, statusRendered.String(), fileIcon, node.Name(), styledCIMarker(node)))
 		}
 	}
-	return strings.Join(lines, "\n")
+	return out.String()
+}
+
+// formatReviewDecision renders a GitHub review decision for the info pane.
+func (m *Model) formatReviewDecision(decision string) string {
+	switch decision {
+	case "APPROVED":
+		return lipgloss.NewStyle().Foreground(m.theme.SuccessFg).Render("✓ Approved")
+	case "CHANGES_REQUESTED":
+		return lipgloss.NewStyle().Foreground(m.theme.ErrorFg).Render("✗ Changes requested")
+	case "REVIEW_REQUIRED":
+		return lipgloss.NewStyle().Foreground(m.theme.WarnFg).Render(symbolFilledCircle + " Review required")
+	default:
+		return ""
+	}
 }
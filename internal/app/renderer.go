@@ -40,6 +40,7 @@ func (m *Model) View() string {
 	sections = append(sections, body, footer)
 
 	baseView := lipgloss.JoinVertical(lipgloss.Left, sections...)
+	m.fitModals()
 
 	// Handle Modal Overlays
 	switch m.currentScreen {
@@ -73,17 +74,6 @@ func (m *Model) View() string {
 		}
 	case screenCommit:
 		if m.commitScreen != nil {
-			// Resize viewport to fit window
-			vpWidth := int(float64(m.windowWidth) * 0.95)
-			vpHeight := int(float64(m.windowHeight) * 0.85)
-			if vpWidth < 80 {
-				vpWidth = 80
-			}
-			if vpHeight < 20 {
-				vpHeight = 20
-			}
-			m.commitScreen.viewport.Width = vpWidth
-			m.commitScreen.viewport.Height = vpHeight
 			return m.overlayPopup(baseView, m.commitScreen.View(), 2)
 		}
 	case screenConfirm:
@@ -106,6 +96,24 @@ func (m *Model) View() string {
 		if m.commitFilesScreen != nil {
 			return m.overlayPopup(baseView, m.commitFilesScreen.View(), 2)
 		}
+	case screenMarkdown:
+		if m.markdownScreen != nil {
+			return m.overlayPopup(baseView, m.markdownScreen.View(), 2)
+		}
+	case screenHealth:
+		if m.healthScreen != nil {
+			return m.overlayPopup(baseView, m.healthScreen.View(), 2)
+		}
+	case screenError:
+		if m.errorScreen != nil {
+			return m.overlayPopup(baseView, m.errorScreen.View(), 3)
+		}
+	case screenWhichKey:
+		if m.whichKeyScreen != nil {
+			popup := m.whichKeyScreen.View()
+			// Sit just above the footer, which-key style.
+			return m.overlayPopup(baseView, popup, maxInt(m.windowHeight-lipgloss.Height(popup)-1, 0))
+		}
 	}
 
 	if m.currentScreen != screenNone {
@@ -130,6 +138,8 @@ func (m *Model) overlayPopup(base, popup string, marginTop int) string {
 
 	baseWidth := lipgloss.Width(baseLines[0])
 	popupWidth := lipgloss.Width(popupLines[0])
+	// Move a popup too tall for its margin up rather than cut its bottom off.
+	marginTop = maxInt(minInt(marginTop, len(baseLines)-len(popupLines)), 0)
 
 	leftPad := maxInt((baseWidth-popupWidth)/2, 0)
 	leftSpace := strings.Repeat(" ", leftPad)
@@ -213,11 +223,19 @@ func (m *Model) renderScreen() string {
 
 // truncateToHeight ensures output doesn't exceed maxLines.
 func truncateToHeight(s string, maxLines int) string {
-	lines := strings.Split(s, "\n")
-	if len(lines) > maxLines {
-		lines = lines[:maxLines]
+	if maxLines <= 0 {
+		return ""
 	}
-	return strings.Join(lines, "\n")
+	// Walk the newlines instead of splitting, as View calls this every frame.
+	end := 0
+	for range maxLines {
+		i := strings.IndexByte(s[end:], '\n')
+		if i < 0 {
+			return s
+		}
+		end += i + 1
+	}
+	return s[:end-1]
 }
 
 // truncateToHeightFromEnd returns the last maxLines lines from the string.
@@ -0,0 +1,78 @@
+package app
+
+import (
+	"path/filepath"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/chmouel/lazyworktree/internal/config"
+	"github.com/chmouel/lazyworktree/internal/theme"
+)
+
+// RepoPicker lets the user choose a repository when lazyworktree starts
+// outside one, instead of showing an empty worktree table.
+type RepoPicker struct {
+	screen   *ListSelectionScreen
+	width    int
+	height   int
+	selected string
+}
+
+// NewRepoPicker builds a picker over the given main repository paths.
+func NewRepoPicker(cfg *config.AppConfig, repos []string) *RepoPicker {
+	thm := theme.GetThemeWithCustoms(cfg.Theme, config.CustomThemesToThemeDataMap(cfg.CustomThemes))
+	items := make([]selectionItem, 0, len(repos))
+	for _, repo := range repos {
+		items = append(items, selectionItem{
+			id:          repo,
+			label:       filepath.Base(repo),
+			description: repo,
+		})
+	}
+	screen := NewListSelectionScreen(
+		items,
+		"Not inside a git repository — select a repository",
+		"Filter repositories...",
+		"No repositories match.",
+		0, 0, "", thm,
+	)
+	return &RepoPicker{screen: screen}
+}
+
+// Init starts the filter input.
+func (p *RepoPicker) Init() tea.Cmd {
+	return p.screen.Init()
+}
+
+// Update resizes the list and records the chosen repository.
+func (p *RepoPicker) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if size, ok := msg.(tea.WindowSizeMsg); ok {
+		p.width = size.Width
+		p.height = size.Height
+		p.screen.width = maxInt(60, int(float64(size.Width)*0.8))
+		p.screen.height = maxInt(20, int(float64(size.Height)*0.8))
+		p.screen.filterInput.Width = p.screen.width - 4
+		return p, nil
+	}
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && keyMsg.String() == keyEnter {
+		if item, ok := p.screen.Selected(); ok {
+			p.selected = item.id
+		}
+		return p, tea.Quit
+	}
+	_, cmd := p.screen.Update(msg)
+	return p, cmd
+}
+
+// View centres the list in the terminal.
+func (p *RepoPicker) View() string {
+	if p.width == 0 || p.height == 0 {
+		return p.screen.View()
+	}
+	return lipgloss.Place(p.width, p.height, lipgloss.Center, lipgloss.Center, p.screen.View())
+}
+
+// Selected returns the chosen repository path, or "" when cancelled.
+func (p *RepoPicker) Selected() string {
+	return p.selected
+}
@@ -0,0 +1,39 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/chmouel/lazyworktree/internal/config"
+)
+
+func TestRepoPickerSelectsRepository(t *testing.T) {
+	picker := NewRepoPicker(config.DefaultConfig(), []string{"/src/alpha", "/src/beta"})
+	picker.Update(tea.WindowSizeMsg{Width: 100, Height: 40})
+
+	view := picker.View()
+	if !strings.Contains(view, "Not inside a git repository") || !strings.Contains(view, "/src/beta") {
+		t.Fatalf("expected title and repositories in view, got %q", view)
+	}
+
+	picker.Update(tea.KeyMsg{Type: tea.KeyDown})
+	_, cmd := picker.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("expected enter to quit the picker")
+	}
+	if got := picker.Selected(); got != "/src/beta" {
+		t.Fatalf("expected /src/beta to be selected, got %q", got)
+	}
+}
+
+func TestRepoPickerCancel(t *testing.T) {
+	picker := NewRepoPicker(config.DefaultConfig(), []string{"/src/alpha"})
+	_, cmd := picker.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if cmd == nil {
+		t.Fatal("expected esc to quit the picker")
+	}
+	if got := picker.Selected(); got != "" {
+		t.Fatalf("expected no selection after cancel, got %q", got)
+	}
+}
@@ -41,6 +41,10 @@ const (
 	screenLoading
 	screenCommitFiles
 	screenChecklist
+	screenMarkdown
+	screenHealth
+	screenWhichKey
+	screenError
 
 	// Key constants (keyEnter and keyEsc are defined in app.go)
 	keyCtrlD    = "ctrl+d"
@@ -58,9 +62,41 @@ const (
 	placeholderFilterFiles = "Filter files..."
 )
 
+// screenNames names each screen for crash reports and debug logs.
+var screenNames = [...]string{
+	screenNone:        "none",
+	screenConfirm:     "confirm",
+	screenInfo:        "info",
+	screenInput:       "input",
+	screenHelp:        "help",
+	screenTrust:       "trust",
+	screenWelcome:     "welcome",
+	screenCommit:      "commit",
+	screenPalette:     "palette",
+	screenDiff:        "diff",
+	screenPRSelect:    "pr-select",
+	screenIssueSelect: "issue-select",
+	screenListSelect:  "list-select",
+	screenLoading:     "loading",
+	screenCommitFiles: "commit-files",
+	screenChecklist:   "checklist",
+	screenMarkdown:    "markdown",
+	screenHealth:      "health",
+	screenWhichKey:    "which-key",
+	screenError:       "error",
+}
+
+func (s screenType) String() string {
+	if s >= 0 && int(s) < len(screenNames) {
+		return screenNames[s]
+	}
+	return fmt.Sprintf("screen(%d)", int(s))
+}
+
 // loadingTips is a list of helpful tips shown during loading.
 var loadingTips = []string{
 	"Press '?' to view the help guide anytime.",
+	"Press ',' to list the keys of the focused pane.",
 	"Use '/' to search in almost any list view.",
 	"Press 'c' to create a worktree from a branch, PR, or issue.",
 	"Use 'D' to delete a worktree (and optionally its branch).",
@@ -78,6 +114,9 @@ type ConfirmScreen struct {
 	message        string
 	result         chan bool
 	selectedButton int // 0 = Confirm, 1 = Cancel
+	width          int
+	height         int
+	scroll         int // First message line shown when it is too long for the box
 	thm            *theme.Theme
 }
 
@@ -85,6 +124,9 @@ type ConfirmScreen struct {
 type InfoScreen struct {
 	message string
 	result  chan bool
+	width   int
+	height  int
+	scroll  int
 	thm     *theme.Theme
 }
 
@@ -95,7 +137,9 @@ type InputScreen struct {
 	value               string
 	input               textinput.Model
 	errorMsg            string
+	note                string // Muted progress line, e.g. while a suggestion streams in
 	boxWidth            int
+	maxHeight           int // Terminal height, 0 while unknown
 	result              chan string
 	validate            func(string) string
 	thm                 *theme.Theme
@@ -110,6 +154,11 @@ type InputScreen struct {
 	checkboxLabel       string   // Label text for checkbox
 	checkboxChecked     bool     // Current checkbox state
 	checkboxFocused     bool     // Track whether checkbox has focus (vs input field)
+
+	preview     func(value string) string // Line under the input telling what the value leads to
+	toggleKey   string                    // Key flipping toggleOn without leaving the input
+	toggleLabel string
+	toggleOn    bool
 }
 
 // HelpScreen renders searchable documentation for the app controls.
@@ -128,7 +177,10 @@ type HelpScreen struct {
 type TrustScreen struct {
 	filePath string
 	commands []string
+	content  string
 	viewport viewport.Model
+	width    int
+	height   int
 	result   chan string
 	thm      *theme.Theme
 }
@@ -230,6 +282,10 @@ type IssueSelectionScreen struct {
 type ListSelectionScreen struct {
 	items        []selectionItem
 	filtered     []selectionItem
+	searchKeys   []string // lowercased label, description and id per item
+	matches      []int    // indices into items of the filtered entries
+	lastQuery    string
+	filterReady  bool
 	filterInput  textinput.Model
 	cursor       int
 	scrollOffset int
@@ -259,6 +315,8 @@ func NewConfirmScreen(message string, thm *theme.Theme) *ConfirmScreen {
 		message:        message,
 		result:         make(chan bool, 1),
 		selectedButton: 0, // Start with Confirm button focused
+		width:          60,
+		height:         11,
 		thm:            thm,
 	}
 }
@@ -269,6 +327,8 @@ func NewConfirmScreenWithDefault(message string, defaultButton int, thm *theme.T
 		message:        message,
 		result:         make(chan bool, 1),
 		selectedButton: defaultButton, // Use provided default
+		width:          60,
+		height:         11,
 		thm:            thm,
 	}
 }
@@ -278,6 +338,8 @@ func NewInfoScreen(message string, thm *theme.Theme) *InfoScreen {
 	return &InfoScreen{
 		message: message,
 		result:  make(chan bool, 1),
+		width:   60,
+		height:  11,
 		thm:     thm,
 	}
 }
@@ -621,6 +683,10 @@ func (s *ConfirmScreen) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		s.selectedButton = (s.selectedButton + 1) % 2
 	case keyShiftTab, "left", "h":
 		s.selectedButton = (s.selectedButton - 1 + 2) % 2
+	case keyUp, "k":
+		s.scroll--
+	case keyDown, "j":
+		s.scroll++
 	case "y", "Y":
 		s.result <- true
 		return s, tea.Quit
@@ -651,14 +717,18 @@ func (s *InfoScreen) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case keyEnter, keyEsc, keyQ, keyCtrlC:
 		s.result <- true
 		return s, tea.Quit
+	case keyUp, "k":
+		s.scroll--
+	case keyDown, "j":
+		s.scroll++
 	}
 	return s, nil
 }
 
 // View renders the confirmation UI box with focused button highlighting.
 func (s *ConfirmScreen) View() string {
-	width := 60
-	height := 11
+	width := s.width
+	height := s.height
 
 	// Enhanced confirm modal with rounded border and accent color
 	boxStyle := lipgloss.NewStyle().
@@ -711,8 +781,9 @@ func (s *ConfirmScreen) View() string {
 		cancelButton = focusedCancelStyle.Render("[Cancel]")
 	}
 
+	message := clipMessage(s.message, width-4, height-6, &s.scroll, lipgloss.NewStyle().Foreground(s.thm.MutedFg))
 	content := fmt.Sprintf("%s\n\n%s  %s",
-		messageStyle.Render(s.message),
+		messageStyle.Render(message),
 		confirmButton,
 		cancelButton,
 	)
@@ -722,8 +793,8 @@ func (s *ConfirmScreen) View() string {
 
 // View renders the informational UI box with a single OK button.
 func (s *InfoScreen) View() string {
-	width := 60
-	height := 11
+	width := s.width
+	height := s.height
 
 	// Enhanced info modal with rounded border
 	boxStyle := lipgloss.NewStyle().
@@ -748,8 +819,9 @@ func (s *InfoScreen) View() string {
 		Background(s.thm.Accent).
 		Bold(true)
 
+	message := clipMessage(s.message, width-4, height-6, &s.scroll, lipgloss.NewStyle().Foreground(s.thm.MutedFg))
 	content := fmt.Sprintf("%s\n\n%s",
-		messageStyle.Render(s.message),
+		messageStyle.Render(message),
 		okStyle.Render("[OK]"),
 	)
 
@@ -880,6 +952,19 @@ func (s *InputScreen) SetFuzzyFinder(enabled bool, suggestions []string) {
 	s.selectedSuggestion = 0
 }
 
+// SetPreview shows, under the input, what the value typed leads to.
+func (s *InputScreen) SetPreview(fn func(value string) string) {
+	s.preview = fn
+}
+
+// SetToggle adds an option, flipped by key while typing, shown under the
+// input.
+func (s *InputScreen) SetToggle(key, label string, on bool) {
+	s.toggleKey = key
+	s.toggleLabel = label
+	s.toggleOn = on
+}
+
 // SetHistory enables bash-style history navigation with up/down arrows.
 func (s *InputScreen) SetHistory(history []string) {
 	s.history = history
@@ -898,6 +983,10 @@ func (s *InputScreen) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	keyMsg, ok := msg.(tea.KeyMsg)
 	if ok {
+		if s.toggleKey != "" && keyMsg.String() == s.toggleKey {
+			s.toggleOn = !s.toggleOn
+			return s, nil
+		}
 		switch keyMsg.String() {
 		case keyTab:
 			// Switch focus between input and checkbox
@@ -965,7 +1054,7 @@ func (s *InputScreen) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 // View renders the prompt, input field, and error message inside a styled box.
 func (s *InputScreen) View() string {
-	width := 60
+	width := s.boxWidth
 
 	// Enhanced input modal with rounded border
 	boxStyle := lipgloss.NewStyle().
@@ -1061,6 +1150,37 @@ func (s *InputScreen) View() string {
 		contentLines = append(contentLines, suggestionsStyle.Render(strings.Join(suggestions, "\n")))
 	}
 
+	if s.note != "" {
+		noteStyle := lipgloss.NewStyle().
+			Foreground(s.thm.MutedFg).
+			Width(width - 6).
+			Align(lipgloss.Center)
+		contentLines = append(contentLines, noteStyle.Render(s.note))
+	}
+
+	if s.preview != nil || s.toggleKey != "" {
+		mutedStyle := lipgloss.NewStyle().
+			Foreground(s.thm.MutedFg).
+			Width(width - 6).
+			Align(lipgloss.Center)
+		var lines []string
+		if s.preview != nil {
+			if line := s.preview(s.input.Value()); line != "" {
+				lines = append(lines, line)
+			}
+		}
+		if s.toggleKey != "" {
+			mark := "[ ]"
+			if s.toggleOn {
+				mark = "[x]"
+			}
+			lines = append(lines, fmt.Sprintf("%s %s (%s)", mark, s.toggleLabel, s.toggleKey))
+		}
+		if len(lines) > 0 {
+			contentLines = append(contentLines, mutedStyle.Render(strings.Join(lines, "\n")))
+		}
+	}
+
 	if s.errorMsg != "" {
 		errorStyle := lipgloss.NewStyle().
 			Foreground(s.thm.ErrorFg).
@@ -1082,9 +1202,14 @@ func (s *InputScreen) View() string {
 	}
 	contentLines = append(contentLines, footerStyle.Render(footerText))
 
-	content := strings.Join(contentLines, "\n\n")
-
-	return boxStyle.Render(content)
+	view := boxStyle.Render(strings.Join(contentLines, "\n\n"))
+	if s.maxHeight > 0 && lipgloss.Height(view) > s.maxHeight {
+		// Too short for the usual spacing: drop the blank lines between parts.
+		footerStyle = footerStyle.MarginTop(0)
+		contentLines[len(contentLines)-1] = footerStyle.Render(footerText)
+		view = boxStyle.Padding(0, 2).Render(strings.Join(contentLines, "\n"))
+	}
+	return view
 }
 
 // NewHelpScreen initializes help content with the available screen size.
@@ -1095,9 +1220,13 @@ func NewHelpScreen(maxWidth, maxHeight int, customCommands map[string]*config.Cu
 - j / ↓: Move cursor down
 - k / ↑: Move cursor up
 - 1 / 2 / 3: Switch to pane (or toggle zoom if already focused)
+- G: Go to the last item; the worktree pane's title shows the selected position (e.g. 17/243)
+- H / L: Select the first / last visible worktree
+- With vim_motions: a count before j / k / G / H / L (5j, 12G), gg (or 3gg) and M (middle visible worktree); digits then count instead of switching panes, and LazyGit and Sync my PRs stay in the palette
 - [ / ]: Previous / Next pane
 - Tab: Cycle to next pane
-- Enter: Jump to selected worktree (exit and cd)
+- < / >: Back / Forward through previously visited worktrees
+- Enter: Jump to selected worktree (exit and cd); with auto_stash, offers to stash changes on the way out and restore them on return; on a [bare] repository, jumps to the default branch's worktree, creating it first
 
 **📝 Status Pane (when focused)**
 - j / k: Navigate files and directories
@@ -1121,6 +1250,7 @@ func NewHelpScreen(maxWidth, maxHeight int, customCommands map[string]*config.Cu
 - Enter: Open commit file tree (browse changed files)
 - C: Cherry-pick commit to another worktree
 - /: Search commit titles
+- Conventional Commit types are shown as coloured badges
 
 **📁 Commit File Tree (viewing files in a commit)**
 - j / k: Navigate files and directories
@@ -1132,7 +1262,8 @@ func NewHelpScreen(maxWidth, maxHeight int, customCommands map[string]*config.Cu
 - q / Esc: Return to commit log
 
 **⚡ Worktree Actions**
-- c: Create new worktree (branch, commit, PR/MR, issue, or custom)
+- c: Create new worktree (branch, commit, PR/MR, issue, clipboard, stash, patch file, or custom)
+- Branch list: PgUp / PgDn page; many remote branches load on request
 - Create from current: suggested name is pre-filled, you may edit it
 - Tab / Shift+Tab: Move focus to the "Include current file changes" checkbox
 - Space: Toggle "Include current file changes"
@@ -1140,6 +1271,38 @@ func NewHelpScreen(maxWidth, maxHeight int, customCommands map[string]*config.Cu
 - D: Delete selected worktree
 - A: Absorb worktree into main (merge + delete)
 - X: Prune merged worktrees (auto-refreshes PR data, then checks PR/branch merge status)
+- M: Sync my PRs (create worktrees for your open PRs/MRs, prune merged ones)
+- Q: Record a macro; Q again names it and binds it to a key that replays it
+- W: Fix whitespace in staged changes (the diff view lists trailing whitespace, mixed line endings and missing final newlines first)
+- [bare], [locked], [prunable] and [sparse] tag worktrees by their git attributes
+- Palette: Edit sparse checkout chooses which directories a worktree checks out (see sparse_checkout)
+- ↗ marks worktrees outside the worktree directory (palette: Adopt worktree)
+- Palette: Snapshot worktree / Restore snapshot keep HEAD, changes and untracked files safe before a risky rebase
+- Monorepos: the info pane lists touched projects (go.work, package.json workspaces, Cargo workspace); Palette: Filter by project
+- Palette: Find in all repositories finds a branch or PR's worktree in any repository and jumps to it
+- Owner column: shown once another user's worktree is listed; Palette: Show only my worktrees hides theirs (see owner_identity)
+- SSH mode (--ssh HOST:PATH, experimental): git, gh and glab run on the host; the header shows it and its latency
+- Palette: Focus mode lists only the selected branch's family (shared prefix, stacked PRs) and widens the details; Esc leaves it
+- Info pane: Activity draws the worktree's reflog entries per day over the last 30 days (· for idle days)
+- Stacked branches are listed under their parent with └; Palette: Restack descendants rebases them onto its tip
+- Jujutsu (colocated) and git-branchless repositories: the info pane shows change IDs; pruning keeps branches, restacking defers to jj or git restack
+- push_scan: Push and Sync first scan unpushed commits for large files and secrets, asking before publishing them
+- CODEOWNERS: the info pane names owners of changed files; Palette: Show code owners lists them before you open a PR
+- Palette: Suggested branches creates a worktree from a recent remote branch or your open PR
+- Branch name prompt: a taken branch or directory is numbered (feature-2) and the final branch and path shown; ctrl+n turns that off
+- Palette: Create worktree from description names the branch from what you type; branch_name_script suggestions stream into the name, typing keeps yours
+- Palette: Services starts, stops or restarts the .wt services of a worktree; the Svc column shows those running
+- Palette: Service ports shows which worktree holds each .wt service_ports port, flags conflicts with ! and kills the process in the way
+- Palette: Docker Compose starts, stops or lists the worktree's own Compose project (docker_compose)
+- Palette: Allow direnv/mise runs direnv allow and mise trust in the worktree (allow_env_tools)
+- Palette: Bootstrap toolchains offers setup commands such as npm ci or go mod download for the worktree
+- Palette: Sync build artifacts copies or links the artifact_sync outputs from the main worktree
+- Palette: Recently deleted worktrees recreates a worktree deleted in the last recently_deleted_days
+- The info pane's Remote: line shows the tracked remote's URL, protocol and host; Palette: Switch remote between ssh and https rewrites it
+- Palette: CI findings lists failing GitHub checks' annotations (also marked ✗N in Status) and opens them at the line
+- Palette: Health matrix shows health_checks and CI per worktree; enter/r/c/a re-run a cell, row, column or all
+- Partial clones: the header shows the filter; large commit diffs ask before downloading; Palette: Prefetch blobs fetches recent file versions
+- Palette: Maintenance runs git gc, worktree prune, fetch and cache cleanup, showing last runs and space reclaimed
 - !: Run arbitrary command in selected worktree
 
 **📝 Branch Naming**
@@ -1151,25 +1314,45 @@ Supported: Letters (a-z, A-Z), numbers (0-9), and hyphens (-). See help for full
 
 **🔍 Viewing & Tools**
 - d: Full-screen diff viewer
-- o: Open PR/MR in browser
+- O: Open deployment URL (needs show_deployments or deployment_script)
+- o: Open PR/MR in browser (palette: Toggle PR draft, Request PR reviewers)
+- i: Read PR/MR description (1-9 open links; Tab does the same in the PR/issue pickers)
 - g: Open LazyGit (or go to top in diff pane)
 - =: Toggle zoom for focused pane
+- v: Toggle README/overview preview in the Status pane
+- Init commands of a new worktree stream into its Status pane; j/k scroll the output until you move on
 - : / Ctrl+P: Command Palette
+- Palette "Generate changelog": group branch commits by Conventional Commit type, then preview, copy or write to CHANGELOG.md
+- Palette "About lazyworktree": versions, tools and paths for bug reports (y copies)
 - ?: Show this help
+- E: Show errors (failed commands, their output and suggested fixes; r retries, y copies the details, n/p move between them, Enter dismisses one, Esc closes)
+- ,: List the keys of the focused pane; the next key runs as usual
 
 **🔄 Repository Operations**
 - r: Refresh worktree list
 - R: Fetch all remotes
+- F: Fetch only the selected worktree's upstream
 - S: Synchronise with upstream (git pull, then git push, current branch only, requires a clean worktree, honours merge_method)
 - P: Push to upstream branch (current branch only, requires a clean worktree, prompts to set upstream when missing)
-- p: Fetch PR/MR status from GitHub/GitLab
-- s: Cycle sort (Path / Last Active / Last Switched)
+- u: Pull from upstream (current branch only, requires a clean worktree, honours merge_method, output follows in the status pane)
+- U: Force push to upstream with --force-with-lease, after a confirmation defaulting to cancel
+- Push, pull and synchronise offer a terminal retry when git needs a passphrase or credentials
+- p: Fetch PR/MR status from GitHub/GitLab (GitHub uses batched GraphQL requests including reviews and checks); runs in the background with progress in the footer, results fill in as they arrive, Esc cancels
+- s: Cycle sort (Path / Last Active / Last Switched / Manual)
+- b: Pin / unpin the worktree; pinned worktrees (★) stay at the top whatever the sort
+- K / J: Move the worktree up / down in a manual order, kept per repository
+- { / }: Previous / next sort column; the header's ▲/▼ shows the column and direction, and clicking a header sorts by it (again reverses it)
+- T: Toggle relative and absolute dates in the list, log and commit screens (see date_format)
 
 **🕰 Background Refresh**
 - Configured via auto_refresh and refresh_interval in the configuration file
+- Each refresh rescans the worktrees' status while no dialogue is open
+- Edits and staged changes in a worktree refresh only its row, as they happen
+- --observe: read-only; the log pane becomes an Activity feed of checkouts, commits and worktrees added or removed, and ◂ marks the worktree that changed last
 
 **🔎 Filtering & Search**
 - f: Filter focused pane
+- x: Clear the worktree filter, from any pane; the header keeps it in view once the input closes
 - /: Search focused pane (incremental)
 - Alt+N / Alt+P: Move selection and fill filter input
 - ↑ / ↓: Move selection (filter active, no fill)
@@ -1195,7 +1378,7 @@ Search Mode:
 - Ctrl+D / Ctrl+U: Scroll half page down / up
 
 **🔧 Shell Completion**
-Generate completions: lazyworktree --completion <bash|zsh|fish>
+Generate completions: lazyworktree completion <bash|zsh|fish>
 
 **⚙️ Configuration & Overrides**
 Configuration is read from multiple sources (in order of precedence):
@@ -1207,6 +1390,12 @@ Configuration is read from multiple sources (in order of precedence):
 
 Example: lazyworktree --config=lw.theme=nord --config=lw.auto_fetch_prs=true
 
+Reduced motion: --no-animations (or no_animations) keeps the spinner still.
+Read-only: --read-only (or read_only) refuses anything that changes worktrees.
+Policy: max_worktrees, max_disk_usage, branch_name_pattern and
+banned_base_branches are checked whenever a worktree is created.
+NO_COLOR: drops colours, delta formatting and coloured pagers.
+
 💡 Tip: PR data is not fetched by default for speed.
        Press 'p' to fetch PR information on demand.`
 
@@ -1485,6 +1674,7 @@ func NewListSelectionScreen(items []selectionItem, title, placeholder, noResults
 	screen := &ListSelectionScreen{
 		items:        items,
 		filtered:     items,
+		filterReady:  true,
 		filterInput:  ti,
 		cursor:       cursor,
 		scrollOffset: 0,
@@ -1619,6 +1809,9 @@ func (s *ListSelectionScreen) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 			}
 			return s, nil
+		case "pgup", "pgdown":
+			s.pageCursor(keyMsg.String() == "pgdown", maxVisible)
+			return s, nil
 		}
 	}
 
@@ -1627,6 +1820,31 @@ func (s *ListSelectionScreen) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return s, cmd
 }
 
+// pageCursor moves the cursor a page through long lists.
+func (s *ListSelectionScreen) pageCursor(down bool, maxVisible int) {
+	if len(s.filtered) == 0 || maxVisible < 1 {
+		return
+	}
+	step := -maxVisible
+	if down {
+		step = maxVisible
+	}
+	cursor := min(max(s.cursor+step, 0), len(s.filtered)-1)
+	if cursor == s.cursor {
+		return
+	}
+	s.cursor = cursor
+	s.scrollOffset = min(max(s.scrollOffset+step, 0), max(len(s.filtered)-maxVisible, 0))
+	if s.cursor < s.scrollOffset || s.cursor >= s.scrollOffset+maxVisible {
+		s.scrollOffset = max(s.cursor-maxVisible+1, 0)
+	}
+	if s.onCursorChange != nil {
+		if item, ok := s.Selected(); ok {
+			s.onCursorChange(item)
+		}
+	}
+}
+
 func (s *PRSelectionScreen) applyFilter() {
 	query := strings.ToLower(strings.TrimSpace(s.filterInput.Value()))
 	if query == "" {
@@ -1655,19 +1873,43 @@ func (s *PRSelectionScreen) applyFilter() {
 
 func (s *ListSelectionScreen) applyFilter() {
 	query := strings.ToLower(strings.TrimSpace(s.filterInput.Value()))
+	// Messages such as the cursor blink leave the query as it was.
+	if s.filterReady && query == s.lastQuery {
+		return
+	}
+	if len(s.searchKeys) != len(s.items) {
+		s.searchKeys = make([]string, len(s.items))
+		for i, item := range s.items {
+			s.searchKeys[i] = strings.ToLower(item.label + "\x00" + item.description + "\x00" + item.id)
+		}
+	}
 	if query == "" {
 		s.filtered = s.items
+		s.matches = nil
 	} else {
-		s.filtered = []selectionItem{}
-		for _, item := range s.items {
-			labelLower := strings.ToLower(item.label)
-			descLower := strings.ToLower(item.description)
-			idLower := strings.ToLower(item.id)
-			if strings.Contains(labelLower, query) || strings.Contains(descLower, query) || strings.Contains(idLower, query) {
-				s.filtered = append(s.filtered, item)
+		// Typing more only narrows the previous matches, so long lists need
+		// not be scanned in full on every keystroke.
+		narrowing := s.filterReady && s.lastQuery != "" && strings.HasPrefix(query, s.lastQuery)
+		candidates := s.matches
+		if !narrowing {
+			candidates = make([]int, len(s.items))
+			for i := range s.items {
+				candidates[i] = i
+			}
+		}
+		matches := make([]int, 0, len(candidates))
+		filtered := make([]selectionItem, 0, len(candidates))
+		for _, i := range candidates {
+			if strings.Contains(s.searchKeys[i], query) {
+				matches = append(matches, i)
+				filtered = append(filtered, s.items[i])
 			}
 		}
+		s.matches = matches
+		s.filtered = filtered
 	}
+	s.lastQuery = query
+	s.filterReady = true
 
 	// Reset cursor if needed
 	if len(s.filtered) == 0 {
@@ -1686,6 +1928,14 @@ func (s *PRSelectionScreen) Selected() (*models.PRInfo, bool) {
 	return s.filtered[s.cursor], true
 }
 
+// setItems replaces the list, keeping the filter typed so far.
+func (s *ListSelectionScreen) setItems(items []selectionItem) {
+	s.items = items
+	s.searchKeys = nil
+	s.filterReady = false
+	s.applyFilter()
+}
+
 // Selected returns the currently selected item, if any.
 func (s *ListSelectionScreen) Selected() (selectionItem, bool) {
 	if s.cursor < 0 || s.cursor >= len(s.filtered) {
@@ -1823,7 +2073,7 @@ func (s *PRSelectionScreen) View() string {
 		Align(lipgloss.Right).
 		Width(s.width - 2).
 		PaddingTop(1)
-	footer := footerStyle.Render("Enter to select • Esc to cancel")
+	footer := footerStyle.Render("Enter to select • Tab to read • Esc to cancel")
 
 	content := lipgloss.JoinVertical(lipgloss.Left,
 		titleStyle,
@@ -2090,7 +2340,7 @@ func (s *IssueSelectionScreen) View() string {
 		Align(lipgloss.Right).
 		Width(s.width - 2).
 		PaddingTop(1)
-	footer := footerStyle.Render("Enter to select • Esc to cancel")
+	footer := footerStyle.Render("Enter to select • Tab to read • Esc to cancel")
 
 	content := lipgloss.JoinVertical(lipgloss.Left,
 		titleStyle,
@@ -2205,7 +2455,12 @@ func (s *ListSelectionScreen) View() string {
 		Align(lipgloss.Right).
 		Width(s.width - 2).
 		PaddingTop(1)
-	footer := footerStyle.Render("Enter to select • Esc to cancel")
+	footerText := "Enter to select • Esc to cancel"
+	if len(s.filtered) > maxVisible && s.cursor >= 0 {
+		// Long lists page with PgUp/PgDn; say where the cursor stands.
+		footerText = fmt.Sprintf("%d/%d • PgUp/PgDn to page • %s", s.cursor+1, len(s.filtered), footerText)
+	}
+	footer := footerStyle.Render(footerText)
 
 	content := lipgloss.JoinVertical(lipgloss.Left,
 		titleStyle,
@@ -2525,18 +2780,16 @@ func NewTrustScreen(filePath string, commands []string, thm *theme.Theme) *Trust
 	commandsText := strings.Join(commands, "\n")
 	question := fmt.Sprintf("The repository config '%s' defines the following commands.\nThis file has changed or hasn't been trusted yet.\nDo you trust these commands to run?", filePath)
 
-	content := fmt.Sprintf("%s\n\n%s", question, commandsText)
-
-	vp := viewport.New(70, 20)
-	vp.SetContent(content)
-
-	return &TrustScreen{
+	s := &TrustScreen{
 		filePath: filePath,
 		commands: commands,
-		viewport: vp,
+		content:  fmt.Sprintf("%s\n\n%s", question, commandsText),
+		viewport: viewport.New(66, 20),
 		result:   make(chan string, 1),
 		thm:      thm,
 	}
+	s.SetSize(0, 0)
+	return s
 }
 
 // SetCheckbox enables a checkbox in the input screen with the given label and default state.
@@ -2575,17 +2828,22 @@ func (s *TrustScreen) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 // View renders the trust warning content inside a styled box.
 func (s *TrustScreen) View() string {
-	width := 70
-	height := 25
-
 	// Enhanced trust warning with rounded border and warning color
 	boxStyle := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
 		BorderForeground(s.thm.WarnFg). // Use warning color for attention
 		Padding(1, 2).
-		Width(width).
-		Height(height)
+		Width(s.width).
+		Height(s.height)
 
+	content := fmt.Sprintf("%s\n\n%s", s.viewport.View(), s.buttons())
+
+	return boxStyle.Render(content)
+}
+
+// buttons lays the trust choices out side by side, or under each other
+// when the box is too narrow for that.
+func (s *TrustScreen) buttons() string {
 	buttonStyle := lipgloss.NewStyle().
 		Width(20).
 		Align(lipgloss.Center).
@@ -2604,14 +2862,11 @@ func (s *TrustScreen) View() string {
 		Foreground(s.thm.ErrorFg).
 		Render("[Cancel Operation]")
 
-	content := fmt.Sprintf("%s\n\n%s  %s  %s",
-		s.viewport.View(),
-		trustButton,
-		blockButton,
-		cancelButton,
-	)
-
-	return boxStyle.Render(content)
+	row := lipgloss.JoinHorizontal(lipgloss.Top, trustButton, blockButton, cancelButton)
+	if lipgloss.Width(row) <= s.width-4 {
+		return row
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, trustButton, blockButton, cancelButton)
 }
 
 // NewWelcomeScreen builds the greeting screen shown when no worktrees exist.
@@ -2792,14 +3047,12 @@ func (s *CommitScreen) renderHeader() string {
 
 // View renders the commit screen
 func (s *CommitScreen) View() string {
-	width := maxInt(100, s.viewport.Width)
-
 	// Enhanced commit view with rounded border
 	boxStyle := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
 		BorderForeground(s.thm.Accent).
 		Padding(0, 1).
-		Width(width)
+		Width(s.viewport.Width + 2)
 
 	return boxStyle.Render(s.viewport.View())
 }
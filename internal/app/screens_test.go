@@ -1,6 +1,7 @@
 package app
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 
@@ -439,6 +440,84 @@ func TestListSelectionScreenUpdate(t *testing.T) {
 	}
 }
 
+func TestListSelectionScreenIncrementalFilter(t *testing.T) {
+	items := []selectionItem{
+		{id: "feature-a", label: "feature-a"},
+		{id: "feature-b", label: "feature-b", description: "remote"},
+		{id: "fix-c", label: "fix-c"},
+	}
+	screen := NewListSelectionScreen(items, "Select", "", "", 100, 40, "", theme.Dracula())
+
+	screen.filterInput.SetValue("fe")
+	screen.applyFilter()
+	if len(screen.filtered) != 2 {
+		t.Fatalf("expected 2 matches for %q, got %d", "fe", len(screen.filtered))
+	}
+
+	screen.filterInput.SetValue("feature-b")
+	screen.applyFilter()
+	if len(screen.filtered) != 1 || screen.filtered[0].id != "feature-b" {
+		t.Fatalf("expected narrowing to feature-b, got %v", screen.filtered)
+	}
+
+	screen.filterInput.SetValue("REMOTE")
+	screen.applyFilter()
+	if len(screen.filtered) != 1 || screen.filtered[0].id != "feature-b" {
+		t.Fatalf("expected a fresh search to match descriptions, got %v", screen.filtered)
+	}
+
+	screen.filterInput.SetValue("f")
+	screen.applyFilter()
+	if len(screen.filtered) != 3 {
+		t.Fatalf("expected widening the query to rescan all items, got %d", len(screen.filtered))
+	}
+
+	screen.setItems(append(items, selectionItem{id: "fourth", label: "fourth"}))
+	if len(screen.filtered) != 4 {
+		t.Fatalf("expected replaced items to be filtered with the current query, got %d", len(screen.filtered))
+	}
+}
+
+func TestListSelectionScreenPaging(t *testing.T) {
+	items := make([]selectionItem, 100)
+	for i := range items {
+		items[i] = selectionItem{id: fmt.Sprintf("item-%03d", i), label: fmt.Sprintf("item-%03d", i)}
+	}
+	screen := NewListSelectionScreen(items, "Select", "", "", 100, 40, "", theme.Dracula())
+	maxVisible := screen.height - 6
+
+	_, _ = screen.Update(tea.KeyMsg{Type: tea.KeyPgDown})
+	if screen.cursor != maxVisible {
+		t.Fatalf("expected cursor a page down at %d, got %d", maxVisible, screen.cursor)
+	}
+	if screen.cursor < screen.scrollOffset || screen.cursor >= screen.scrollOffset+maxVisible {
+		t.Fatalf("expected cursor %d to stay visible from offset %d", screen.cursor, screen.scrollOffset)
+	}
+	if view := screen.View(); !strings.Contains(view, fmt.Sprintf("%d/100", maxVisible+1)) {
+		t.Fatal("expected the footer to show the cursor position")
+	}
+
+	// A blink or other message must not move the view.
+	offset := screen.scrollOffset
+	_, _ = screen.Update(struct{}{})
+	if screen.scrollOffset != offset {
+		t.Fatalf("expected scroll offset %d to be kept, got %d", offset, screen.scrollOffset)
+	}
+
+	for range 10 {
+		_, _ = screen.Update(tea.KeyMsg{Type: tea.KeyPgDown})
+	}
+	if screen.cursor != 99 {
+		t.Fatalf("expected cursor to stop at the last item, got %d", screen.cursor)
+	}
+	for range 10 {
+		_, _ = screen.Update(tea.KeyMsg{Type: tea.KeyPgUp})
+	}
+	if screen.cursor != 0 || screen.scrollOffset != 0 {
+		t.Fatalf("expected to page back to the top, got cursor %d offset %d", screen.cursor, screen.scrollOffset)
+	}
+}
+
 func TestNewConfirmScreenWithDefault(t *testing.T) {
 	thm := theme.Dracula()
 
@@ -963,3 +1042,12 @@ func TestIssueSelectionScreenInit(t *testing.T) {
 		t.Error("expected Init to return textinput.Blink command")
 	}
 }
+
+func TestScreenTypeString(t *testing.T) {
+	if got := screenHelp.String(); got != "help" {
+		t.Fatalf("expected help, got %q", got)
+	}
+	if got := screenType(999).String(); got != "screen(999)" {
+		t.Fatalf("expected a fallback name, got %q", got)
+	}
+}
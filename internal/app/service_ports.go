@@ -0,0 +1,181 @@
+package app
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/chmouel/lazyworktree/internal/config"
+	"github.com/chmouel/lazyworktree/internal/services"
+)
+
+// servicePortsMsg carries the processes listening on the service ports.
+type servicePortsMsg struct {
+	listeners []services.Listener
+	err       error
+}
+
+// portUse is a process listening on a service port and the worktree it
+// runs in, empty when it runs outside them.
+type portUse struct {
+	services.Listener
+	owner string
+}
+
+// servicePortRanges returns the ports the repository's services listen on.
+func (m *Model) servicePortRanges() []config.PortRange {
+	if m.repoConfig == nil {
+		return nil
+	}
+	return m.repoConfig.ServicePorts
+}
+
+// portUses attributes each listener to the worktree it runs in.
+func (m *Model) portUses(listeners []services.Listener) []portUse {
+	paths := make([]string, 0, len(m.worktrees))
+	for _, wt := range m.worktrees {
+		paths = append(paths, wt.Path)
+	}
+	var running services.List
+	for _, list := range m.runningServices {
+		running = append(running, list...)
+	}
+	uses := make([]portUse, 0, len(listeners))
+	for _, l := range listeners {
+		uses = append(uses, portUse{Listener: l, owner: l.Owner(paths, running, m.servicePorts)})
+	}
+	return uses
+}
+
+// portConflicts returns the ports held from more than one place: several
+// worktrees, or a worktree and a process outside them.
+func portConflicts(uses []portUse) map[int][]string {
+	owners := map[int][]string{}
+	for _, u := range uses {
+		if !slices.Contains(owners[u.Port], u.owner) {
+			owners[u.Port] = append(owners[u.Port], u.owner)
+		}
+	}
+	conflicts := map[int][]string{}
+	for port, held := range owners {
+		if len(held) > 1 {
+			conflicts[port] = held
+		}
+	}
+	return conflicts
+}
+
+// conflictingPort returns a port the worktree at path holds in conflict
+// with another, or 0.
+func (m *Model) conflictingPort(path string) int {
+	conflicts := portConflicts(m.portUses(m.serviceListeners))
+	port := 0
+	for p, owners := range conflicts {
+		if slices.Contains(owners, path) && (port == 0 || p < port) {
+			port = p
+		}
+	}
+	return port
+}
+
+// ownerName names the worktree a port is held from.
+func ownerName(owner string) string {
+	if owner == "" {
+		return "outside the worktrees"
+	}
+	return filepath.Base(owner)
+}
+
+// showServicePorts scans the service ports for the list of who holds them.
+func (m *Model) showServicePorts() tea.Cmd {
+	ranges := m.servicePortRanges()
+	if len(ranges) == 0 {
+		m.showInfo("This repository declares no service ports.\n\nAdd the ports its services listen on to .wt:\n\nservice_ports: [3000-3010, 5173]", nil)
+		return nil
+	}
+	m.loading = true
+	m.loadingScreen = NewLoadingScreen("Scanning service ports...", m.theme)
+	m.currentScreen = screenLoading
+	return func() tea.Msg {
+		listeners, err := services.Listeners(m.ctx, ranges)
+		return servicePortsMsg{listeners: listeners, err: err}
+	}
+}
+
+// handleServicePorts lists each service port in use with the worktree
+// holding it, conflicts first; choosing one offers to kill its process.
+func (m *Model) handleServicePorts(msg servicePortsMsg) tea.Cmd {
+	m.loading = false
+	m.loadingScreen = nil
+	if m.currentScreen == screenLoading {
+		m.currentScreen = screenNone
+	}
+	if msg.err != nil {
+		m.showInfo(fmt.Sprintf("Cannot scan the service ports: %v", msg.err), nil)
+		return nil
+	}
+	m.serviceListeners = msg.listeners
+	if len(msg.listeners) == 0 {
+		m.showInfo("Nothing listens on the service ports.", nil)
+		return nil
+	}
+
+	uses := m.portUses(msg.listeners)
+	conflicts := portConflicts(uses)
+	var first, rest []selectionItem
+	byID := map[string]portUse{}
+	for _, u := range uses {
+		id := fmt.Sprintf("%d:%d", u.Port, u.PID)
+		byID[id] = u
+		item := selectionItem{
+			id:          id,
+			label:       fmt.Sprintf(":%d  %s", u.Port, ownerName(u.owner)),
+			description: fmt.Sprintf("%s · pid %d", u.Process, u.PID),
+		}
+		if held, ok := conflicts[u.Port]; ok {
+			var others []string
+			for _, owner := range held {
+				if owner != u.owner {
+					others = append(others, ownerName(owner))
+				}
+			}
+			item.label = "! " + item.label
+			item.description += " · conflicts with " + strings.Join(others, ", ")
+			first = append(first, item)
+			continue
+		}
+		rest = append(rest, item)
+	}
+
+	m.listScreen = NewListSelectionScreen(append(first, rest...), "Service ports", "Filter ports...", "No matching ports.", m.windowWidth, m.windowHeight, "", m.theme)
+	m.listSubmit = func(item selectionItem) tea.Cmd {
+		m.clearListSelection()
+		if u, ok := byID[item.id]; ok {
+			return m.confirmKillListener(u)
+		}
+		return nil
+	}
+	m.currentScreen = screenListSelect
+	m.updateTable()
+	return textinput.Blink
+}
+
+// confirmKillListener asks before killing the process holding a port.
+func (m *Model) confirmKillListener(u portUse) tea.Cmd {
+	if m.readOnlyDenied("Killing processes") {
+		return nil
+	}
+	name := fmt.Sprintf("%s (pid %d)", u.Process, u.PID)
+	m.confirmScreen = NewConfirmScreen(fmt.Sprintf("Kill %s?\n\nIt listens on port %d from %s.", name, u.Port, ownerName(u.owner)), m.theme)
+	m.confirmAction = func() tea.Cmd {
+		pid := u.PID
+		return func() tea.Msg {
+			return serviceDoneMsg{name: name, action: "Killed", err: services.Kill(pid)}
+		}
+	}
+	m.currentScreen = screenConfirm
+	return nil
+}
@@ -0,0 +1,263 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/chmouel/lazyworktree/internal/config"
+	"github.com/chmouel/lazyworktree/internal/models"
+	"github.com/chmouel/lazyworktree/internal/services"
+)
+
+// serviceLogLines is how much of a service's log is shown.
+const serviceLogLines = 40
+
+// servicesLoadedMsg carries the services running in each worktree, the
+// ports each listens on and who holds the service ports.
+type servicesLoadedMsg struct {
+	running   map[string]services.List
+	ports     map[int][]int
+	listeners []services.Listener
+}
+
+// serviceDoneMsg reports a service started, stopped or restarted.
+type serviceDoneMsg struct {
+	name   string
+	action string
+	err    error
+}
+
+// configuredServices returns the services the repository declares.
+func (m *Model) configuredServices() []*config.Service {
+	if m.repoConfig == nil {
+		return nil
+	}
+	return m.repoConfig.Services
+}
+
+// servicesPath is where the running services of the repository are listed.
+func (m *Model) servicesPath() string {
+	return services.Path(m.getRepoKey())
+}
+
+// showServicesColumn reports whether the worktree table has a Svc column.
+func (m *Model) showServicesColumn() bool {
+	return len(m.configuredServices()) > 0
+}
+
+// loadServices finds which services still run, their ports and who holds
+// the service ports, in the background.
+func (m *Model) loadServices() tea.Cmd {
+	if len(m.configuredServices()) == 0 {
+		return nil
+	}
+	path := m.servicesPath()
+	ranges := m.servicePortRanges()
+	return func() tea.Msg {
+		msg := servicesLoadedMsg{running: map[string]services.List{}, ports: map[int][]int{}}
+		for _, p := range services.Load(path) {
+			msg.running[p.Worktree] = append(msg.running[p.Worktree], p)
+			msg.ports[p.PID] = services.Ports(m.ctx, p.PID)
+		}
+		msg.listeners, _ = services.Listeners(m.ctx, ranges)
+		return msg
+	}
+}
+
+// handleServicesLoaded refreshes the Svc column.
+func (m *Model) handleServicesLoaded(msg servicesLoadedMsg) {
+	m.runningServices = msg.running
+	m.servicePorts = msg.ports
+	m.serviceListeners = msg.listeners
+	m.updateTable()
+}
+
+// servicesCell renders the Svc column: a port the worktree holds in
+// conflict with another, the first port listened on, or how many services
+// run.
+func (m *Model) servicesCell(wt *models.WorktreeInfo) string {
+	if port := m.conflictingPort(wt.Path); port > 0 {
+		return fmt.Sprintf("! :%d", port)
+	}
+	running := m.runningServices[wt.Path]
+	if len(running) == 0 {
+		return "-"
+	}
+	var ports []int
+	for _, p := range running {
+		ports = append(ports, m.servicePorts[p.PID]...)
+	}
+	switch {
+	case len(ports) == 1:
+		return fmt.Sprintf("%s :%d", symbolFilledCircle, ports[0])
+	case len(ports) > 1:
+		return fmt.Sprintf("%s :%d+%d", symbolFilledCircle, ports[0], len(ports)-1)
+	}
+	return fmt.Sprintf("%s %d", symbolFilledCircle, len(running))
+}
+
+// showServices lists the selected worktree's services with whether each
+// runs; choosing one offers to start, stop or restart it.
+func (m *Model) showServices() tea.Cmd {
+	wt := m.selectedWorktree()
+	if wt == nil {
+		return nil
+	}
+	declared := m.configuredServices()
+	if len(declared) == 0 {
+		m.showInfo("This repository declares no services.\n\nAdd them to .wt, by name:\n\nservices:\n  web: npm run dev", nil)
+		return nil
+	}
+	running := services.Load(m.servicesPath())
+	items := make([]selectionItem, 0, len(declared))
+	for _, svc := range declared {
+		description := "stopped · " + svc.Command
+		if p, ok := running.Find(wt.Path, svc.Name); ok {
+			details := []string{"running", fmt.Sprintf("pid %d", p.PID)}
+			for _, port := range m.servicePorts[p.PID] {
+				details = append(details, fmt.Sprintf(":%d", port))
+			}
+			details = append(details, "since "+formatRelativeTime(p.StartedAt))
+			description = strings.Join(details, " · ")
+		}
+		items = append(items, selectionItem{id: svc.Name, label: svc.Name, description: description})
+	}
+	m.listScreen = NewListSelectionScreen(items, fmt.Sprintf("Services of %s", filepath.Base(wt.Path)), "Filter services...", "No matching services.", m.windowWidth, m.windowHeight, "", m.theme)
+	m.listSubmit = func(item selectionItem) tea.Cmd {
+		m.clearListSelection()
+		for _, svc := range declared {
+			if svc.Name == item.id {
+				return m.showServiceActions(wt, svc)
+			}
+		}
+		return nil
+	}
+	m.currentScreen = screenListSelect
+	return textinput.Blink
+}
+
+// showServiceActions offers what can be done with a service: start it when
+// stopped, stop or restart it when running, and show its log.
+func (m *Model) showServiceActions(wt *models.WorktreeInfo, svc *config.Service) tea.Cmd {
+	p, running := services.Load(m.servicesPath()).Find(wt.Path, svc.Name)
+	var items []selectionItem
+	if running {
+		items = append(items,
+			selectionItem{id: "restart", label: "Restart", description: svc.Command},
+			selectionItem{id: "stop", label: "Stop", description: fmt.Sprintf("pid %d", p.PID)},
+		)
+	} else {
+		items = append(items, selectionItem{id: "start", label: "Start", description: svc.Command})
+	}
+	logPath := services.LogPath(m.servicesPath(), wt.Path, svc.Name)
+	if _, err := os.Stat(logPath); err == nil {
+		items = append(items, selectionItem{id: "log", label: "Show log", description: logPath})
+	}
+	m.listScreen = NewListSelectionScreen(items, fmt.Sprintf("%s in %s", svc.Name, filepath.Base(wt.Path)), "Filter actions...", "No matching actions.", m.windowWidth, m.windowHeight, "", m.theme)
+	m.listSubmit = func(item selectionItem) tea.Cmd {
+		m.clearListSelection()
+		switch item.id {
+		case "start", "restart":
+			return m.startService(wt, svc, item.id == "restart")
+		case "stop":
+			return m.stopService(wt, svc.Name)
+		case "log":
+			m.showServiceLog(svc.Name, logPath)
+		}
+		return nil
+	}
+	m.currentScreen = screenListSelect
+	return textinput.Blink
+}
+
+// startService starts a service in the worktree, stopping it first for a
+// restart, once the repository config that declares it is trusted.
+func (m *Model) startService(wt *models.WorktreeInfo, svc *config.Service, restart bool) tea.Cmd {
+	if m.readOnlyDenied("Running services") {
+		return nil
+	}
+	return m.withCommandTrust(svc.Command, func() tea.Cmd {
+		path := m.servicesPath()
+		env := m.buildCommandEnv(wt.Branch, wt.Path)
+		env["LAZYWORKTREE_SERVICE"] = svc.Name
+		action := "Started"
+		if restart {
+			action = "Restarted"
+		}
+		return func() tea.Msg {
+			if restart {
+				if err := services.Stop(path, wt.Path, svc.Name); err != nil {
+					return serviceDoneMsg{name: svc.Name, action: action, err: err}
+				}
+			}
+			_, err := services.Start(path, wt.Path, svc.Name, svc.Command, env)
+			return serviceDoneMsg{name: svc.Name, action: action, err: err}
+		}
+	})
+}
+
+// stopService stops a service of the worktree.
+func (m *Model) stopService(wt *models.WorktreeInfo, name string) tea.Cmd {
+	path := m.servicesPath()
+	return func() tea.Msg {
+		return serviceDoneMsg{name: name, action: "Stopped", err: services.Stop(path, wt.Path, name)}
+	}
+}
+
+// handleServiceDone reports the outcome and refreshes the Svc column.
+func (m *Model) handleServiceDone(msg serviceDoneMsg) tea.Cmd {
+	if msg.err != nil {
+		m.showInfo(fmt.Sprintf("%s: %v", msg.name, msg.err), nil)
+	} else {
+		m.statusContent = fmt.Sprintf("%s %s", msg.action, msg.name)
+	}
+	return m.loadServices()
+}
+
+// showServiceLog shows the end of a service's log.
+func (m *Model) showServiceLog(name, logPath string) {
+	// #nosec G304 -- logPath is derived from the cache directory
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		m.showInfo(fmt.Sprintf("Cannot read the log of %s: %v", name, err), nil)
+		return
+	}
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	if len(lines) > serviceLogLines {
+		lines = lines[len(lines)-serviceLogLines:]
+	}
+	m.showInfo(fmt.Sprintf("%s (%s)\n\n%s", name, logPath, strings.Join(lines, "\n")), nil)
+}
+
+// stopWorktreeServices stops the services of a worktree being deleted.
+func (m *Model) stopWorktreeServices(path string) {
+	if err := services.StopAll(m.servicesPath(), path); err != nil {
+		m.debugf("services: %v", err)
+	}
+}
+
+// withCommandTrust runs start once the repository config the command comes
+// from is trusted, asking first as for init commands.
+func (m *Model) withCommandTrust(command string, start func() tea.Cmd) tea.Cmd {
+	trustMode := strings.ToLower(strings.TrimSpace(m.config.TrustMode))
+	if trustMode == "never" {
+		m.showInfo("Repository commands are disabled (trust_mode: never).", nil)
+		return nil
+	}
+	trustPath := m.untrustedRepoConfigPath()
+	if trustMode == "always" || trustPath == "" {
+		return start()
+	}
+	m.pendingTrust = trustPath
+	m.pendingTrustAction = func() tea.Cmd {
+		// Another file, such as the team configuration, may still need trust.
+		return m.withCommandTrust(command, start)
+	}
+	m.trustScreen = NewTrustScreen(trustPath, []string{command}, m.theme)
+	m.currentScreen = screenTrust
+	return nil
+}
@@ -0,0 +1,143 @@
+package app
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/chmouel/lazyworktree/internal/config"
+	"github.com/chmouel/lazyworktree/internal/models"
+	"github.com/chmouel/lazyworktree/internal/services"
+)
+
+func TestServicesStartAndStop(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a POSIX shell")
+	}
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	m := NewModel(&config.AppConfig{WorktreeDir: t.TempDir(), TrustMode: "always"}, "")
+	m.repoKey = "services-test"
+	m.repoConfig = &config.RepoConfig{Services: []*config.Service{{Name: "web", Command: "exec sleep 60"}}}
+	wt := &models.WorktreeInfo{Path: t.TempDir(), Branch: "feature"}
+	m.worktrees = []*models.WorktreeInfo{wt}
+	m.filteredWts = m.worktrees
+	t.Cleanup(func() { m.stopWorktreeServices(wt.Path) })
+
+	_ = m.showServices()
+	if m.currentScreen != screenListSelect || len(m.listScreen.items) != 1 || !strings.HasPrefix(m.listScreen.items[0].description, "stopped") {
+		t.Fatalf("expected the stopped web service, got %s", screenName(m.currentScreen))
+	}
+	_ = m.listSubmit(m.listScreen.items[0])
+	if got := m.listScreen.items[0].id; got != "start" {
+		t.Fatalf("expected to be offered to start it, got %q", got)
+	}
+
+	done, ok := m.listSubmit(m.listScreen.items[0])().(serviceDoneMsg)
+	if !ok || done.err != nil {
+		t.Fatalf("expected the service to start, got %+v", done)
+	}
+	loaded, ok := m.handleServiceDone(done)().(servicesLoadedMsg)
+	if !ok {
+		t.Fatal("expected the services to reload")
+	}
+	m.handleServicesLoaded(loaded)
+	if got := m.servicesCell(wt); got != symbolFilledCircle+" 1" {
+		t.Fatalf("expected one running service in the Svc column, got %q", got)
+	}
+
+	_ = m.showServices()
+	if !strings.HasPrefix(m.listScreen.items[0].description, "running") {
+		t.Fatalf("expected the service to show as running, got %+v", m.listScreen.items[0])
+	}
+	_ = m.listSubmit(m.listScreen.items[0])
+	ids := []string{}
+	for _, item := range m.listScreen.items {
+		ids = append(ids, item.id)
+	}
+	if strings.Join(ids, ",") != "restart,stop,log" {
+		t.Fatalf("unexpected actions %v", ids)
+	}
+
+	m.stopWorktreeServices(wt.Path)
+	if running := services.Load(m.servicesPath()); len(running) != 0 {
+		t.Fatalf("expected the service to be stopped with its worktree, got %+v", running)
+	}
+}
+
+func TestServicesAskForTrust(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	m := NewModel(&config.AppConfig{WorktreeDir: t.TempDir()}, "")
+	m.repoConfigPath = filepath.Join(t.TempDir(), ".wt")
+	if err := os.WriteFile(m.repoConfigPath, []byte("services:\n  web: exec sleep 60\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	m.repoConfig = &config.RepoConfig{Path: m.repoConfigPath, Services: []*config.Service{{Name: "web", Command: "exec sleep 60"}}}
+
+	started := false
+	if cmd := m.withCommandTrust("exec sleep 60", func() tea.Cmd { started = true; return nil }); cmd != nil || m.currentScreen != screenTrust {
+		t.Fatalf("expected the trust prompt, got %s", screenName(m.currentScreen))
+	}
+	if started {
+		t.Fatal("expected nothing to run before the file is trusted")
+	}
+	_, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("t")})
+	if !started || m.currentScreen != screenNone {
+		t.Fatalf("expected the service to start once trusted, got %s", screenName(m.currentScreen))
+	}
+}
+
+func TestServicePortsShowConflicts(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sleep")
+	}
+	m := NewModel(&config.AppConfig{WorktreeDir: t.TempDir()}, "")
+	m.repoConfig = &config.RepoConfig{
+		Services:     []*config.Service{{Name: "web", Command: "npm run dev"}},
+		ServicePorts: []config.PortRange{{First: 3000, Last: 3010}},
+	}
+	a := &models.WorktreeInfo{Path: filepath.Join(t.TempDir(), "feature-a"), Branch: "feature-a"}
+	b := &models.WorktreeInfo{Path: filepath.Join(t.TempDir(), "feature-b"), Branch: "feature-b"}
+	c := &models.WorktreeInfo{Path: filepath.Join(t.TempDir(), "feature-c"), Branch: "feature-c"}
+	m.worktrees = []*models.WorktreeInfo{a, b, c}
+	m.filteredWts = m.worktrees
+
+	stray := exec.Command("sleep", "60")
+	if err := stray.Start(); err != nil {
+		t.Fatal(err)
+	}
+	go func() { _ = stray.Wait() }()
+	t.Cleanup(func() { _ = stray.Process.Kill() })
+
+	_ = m.handleServicePorts(servicePortsMsg{listeners: []services.Listener{
+		{Port: 3000, PID: 11, Process: "node", Dir: a.Path},
+		{Port: 3001, PID: 12, Process: "node", Dir: c.Path},
+		{Port: 3000, PID: stray.Process.Pid, Process: "node", Dir: filepath.Join(b.Path, "web")},
+	}})
+	if m.currentScreen != screenListSelect {
+		t.Fatalf("expected the service ports list, got %s", screenName(m.currentScreen))
+	}
+	items := m.listScreen.items
+	if len(items) != 3 || !strings.HasPrefix(items[0].label, "! :3000  feature-a") || !strings.HasSuffix(items[1].description, "conflicts with feature-a") || items[2].label != ":3001  feature-c" {
+		t.Fatalf("expected the conflicting port first, got %+v", items)
+	}
+	if got := m.servicesCell(b); got != "! :3000" {
+		t.Fatalf("expected the Svc column to flag the conflict, got %q", got)
+	}
+	if got := m.servicesCell(c); got != "-" {
+		t.Fatalf("expected no flag without a conflict, got %q", got)
+	}
+
+	_ = m.listSubmit(items[1])
+	if m.currentScreen != screenConfirm || !strings.Contains(m.confirmScreen.message, "from feature-b") {
+		t.Fatalf("expected to be asked before killing, got %s", screenName(m.currentScreen))
+	}
+	done, ok := m.confirmAction()().(serviceDoneMsg)
+	if !ok || done.err != nil || done.action != "Killed" {
+		t.Fatalf("expected the process to be killed, got %+v", done)
+	}
+}
@@ -0,0 +1,182 @@
+package app
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/chmouel/lazyworktree/internal/models"
+	"github.com/chmouel/lazyworktree/internal/snapshot"
+)
+
+// snapshotDoneMsg reports a finished snapshot operation on path.
+type snapshotDoneMsg struct {
+	path    string
+	message string
+	err     error
+	// changed is set when the worktree itself was modified.
+	changed bool
+}
+
+// snapshotStore keeps the repository's snapshots under the cache directory.
+func (m *Model) snapshotStore() *snapshot.Store {
+	return snapshot.NewStore(filepath.Join(m.getRepoCacheDir(), models.SnapshotsDirname))
+}
+
+// showSnapshotWorktree asks for a label, then records the selected
+// worktree's HEAD, changes and untracked files.
+func (m *Model) showSnapshotWorktree() tea.Cmd {
+	wt := m.selectedWorktree()
+	if wt == nil {
+		return nil
+	}
+	m.inputScreen = NewInputScreen(fmt.Sprintf("Snapshot %s: label", filepath.Base(wt.Path)), "before rebase", "", m.theme)
+	m.inputSubmit = func(value string, _ bool) (tea.Cmd, bool) {
+		label := strings.TrimSpace(value)
+		if label == "" {
+			m.inputScreen.errorMsg = "Label cannot be empty."
+			return nil, false
+		}
+		store := m.snapshotStore()
+		path := wt.Path
+		return func() tea.Msg {
+			snap, err := store.Create(m.ctx, path, label)
+			if err != nil {
+				return snapshotDoneMsg{path: path, err: fmt.Errorf("failed to snapshot %s: %w", filepath.Base(path), err)}
+			}
+			return snapshotDoneMsg{path: path, message: fmt.Sprintf("Snapshot %q saved (%s).\n\nRestore it from the command palette with \"Restore snapshot\".", snap.Label, snap.Summary())}
+		}, true
+	}
+	m.currentScreen = screenInput
+	return textinput.Blink
+}
+
+// snapshotItems lists the snapshots of wt for a selection screen.
+func (m *Model) snapshotItems(wt *models.WorktreeInfo) ([]selectionItem, map[string]*snapshot.Snapshot) {
+	snaps, err := m.snapshotStore().List(wt.Path)
+	if err != nil {
+		m.debugf("failed to list snapshots: %v", err)
+	}
+	items := make([]selectionItem, 0, len(snaps))
+	byID := make(map[string]*snapshot.Snapshot, len(snaps))
+	for _, snap := range snaps {
+		byID[snap.ID] = snap
+		head := snap.Head
+		if len(head) > 7 {
+			head = head[:7]
+		}
+		items = append(items, selectionItem{
+			id:          snap.ID,
+			label:       snap.Label,
+			description: fmt.Sprintf("%s · %s · %s", snap.Created.Local().Format(time.DateTime), head, snap.Summary()),
+		})
+	}
+	return items, byID
+}
+
+// showRestoreSnapshot offers the selected worktree's snapshots and, once
+// confirmed, brings the worktree back to the chosen one.
+func (m *Model) showRestoreSnapshot() tea.Cmd {
+	if m.readOnlyDenied("Restoring snapshots") {
+		return nil
+	}
+	wt := m.selectedWorktree()
+	if wt == nil {
+		return nil
+	}
+	items, byID := m.snapshotItems(wt)
+	if len(items) == 0 {
+		m.showInfo(fmt.Sprintf("No snapshots of %s yet.\n\nTake one from the command palette with \"Snapshot worktree\".", filepath.Base(wt.Path)), nil)
+		return nil
+	}
+	m.listScreen = NewListSelectionScreen(items, fmt.Sprintf("Restore %s", filepath.Base(wt.Path)), "Filter snapshots...", "No matching snapshots.", m.windowWidth, m.windowHeight, "", m.theme)
+	m.listSubmit = func(item selectionItem) tea.Cmd {
+		m.listScreen = nil
+		m.listSubmit = nil
+		snap := byID[item.id]
+		if snap == nil {
+			m.currentScreen = screenNone
+			return nil
+		}
+		m.confirmScreen = NewConfirmScreen(fmt.Sprintf("Restore snapshot %q?\n\nHEAD, staged, unstaged and untracked files in %s return to %s. The current state is snapshotted first.%s", snap.Label, wt.Path, snap.Created.Local().Format(time.DateTime), m.otherInstanceWarning()), m.theme)
+		m.confirmAction = m.restoreSnapshotCmd(wt.Path, snap)
+		m.currentScreen = screenConfirm
+		return nil
+	}
+	m.currentScreen = screenListSelect
+	return textinput.Blink
+}
+
+func (m *Model) restoreSnapshotCmd(path string, snap *snapshot.Snapshot) func() tea.Cmd {
+	return func() tea.Cmd {
+		store := m.snapshotStore()
+		return func() tea.Msg {
+			backup, err := store.Restore(m.ctx, path, snap)
+			if err != nil {
+				if backup != nil {
+					err = fmt.Errorf("%w\n\nThe state before the restore was kept as %q", err, backup.Label)
+				}
+				return snapshotDoneMsg{path: path, err: err, changed: backup != nil}
+			}
+			return snapshotDoneMsg{
+				path:    path,
+				message: fmt.Sprintf("Restored %q.\n\nThe state it replaced was kept as %q.", snap.Label, backup.Label),
+				changed: true,
+			}
+		}
+	}
+}
+
+// showDeleteSnapshot offers the selected worktree's snapshots for deletion.
+func (m *Model) showDeleteSnapshot() tea.Cmd {
+	wt := m.selectedWorktree()
+	if wt == nil {
+		return nil
+	}
+	items, byID := m.snapshotItems(wt)
+	if len(items) == 0 {
+		m.showInfo(fmt.Sprintf("No snapshots of %s to delete.", filepath.Base(wt.Path)), nil)
+		return nil
+	}
+	m.listScreen = NewListSelectionScreen(items, fmt.Sprintf("Delete snapshot of %s", filepath.Base(wt.Path)), "Filter snapshots...", "No matching snapshots.", m.windowWidth, m.windowHeight, "", m.theme)
+	m.listSubmit = func(item selectionItem) tea.Cmd {
+		m.listScreen = nil
+		m.listSubmit = nil
+		snap := byID[item.id]
+		if snap == nil {
+			m.currentScreen = screenNone
+			return nil
+		}
+		m.confirmScreen = NewConfirmScreen(fmt.Sprintf("Delete snapshot %q?\n\nTaken %s.", snap.Label, snap.Created.Local().Format(time.DateTime)), m.theme)
+		m.confirmAction = func() tea.Cmd {
+			store := m.snapshotStore()
+			return func() tea.Msg {
+				if err := store.Delete(m.ctx, wt.Path, snap); err != nil {
+					return snapshotDoneMsg{path: wt.Path, err: err}
+				}
+				return snapshotDoneMsg{path: wt.Path, message: fmt.Sprintf("Snapshot %q deleted.", snap.Label)}
+			}
+		}
+		m.currentScreen = screenConfirm
+		return nil
+	}
+	m.currentScreen = screenListSelect
+	return textinput.Blink
+}
+
+// handleSnapshotDone reports the outcome and reloads a modified worktree.
+func (m *Model) handleSnapshotDone(msg snapshotDoneMsg) tea.Cmd {
+	if msg.err != nil {
+		m.showInfo(fmt.Sprintf("Error: %v", msg.err), nil)
+	} else {
+		m.showInfo(msg.message, nil)
+	}
+	if !msg.changed {
+		return nil
+	}
+	delete(m.detailsCache, msg.path)
+	return m.refreshWorktrees()
+}
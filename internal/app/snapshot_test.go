@@ -0,0 +1,82 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/chmouel/lazyworktree/internal/config"
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+func TestSnapshotAndRestore(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	repo := t.TempDir()
+	runGit(t, repo, "init", "-b", "main")
+	runGit(t, repo, "config", "user.email", "test@example.com")
+	runGit(t, repo, "config", "user.name", "Test User")
+	runGit(t, repo, "config", "commit.gpgsign", "false")
+	runGit(t, repo, "commit", "--allow-empty", "-m", "Initial commit")
+	notes := filepath.Join(repo, "notes.txt")
+	if err := os.WriteFile(notes, []byte("draft\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	m := NewModel(&config.AppConfig{WorktreeDir: t.TempDir()}, "")
+	m.repoKey = "example/snapshots"
+	m.worktrees = []*models.WorktreeInfo{{Path: repo, Branch: "main", IsMain: true}}
+	m.filteredWts = m.worktrees
+	m.selectedIndex = 0
+
+	_ = m.showSnapshotWorktree()
+	if m.currentScreen != screenInput {
+		t.Fatalf("expected the label input, got %s", screenName(m.currentScreen))
+	}
+	if _, ok := m.inputSubmit("  ", false); ok {
+		t.Fatal("expected an empty label to be refused")
+	}
+	cmd, ok := m.inputSubmit("before rebase", false)
+	if !ok || cmd == nil {
+		t.Fatal("expected the snapshot to start")
+	}
+	done, ok := cmd().(snapshotDoneMsg)
+	if !ok || done.err != nil || !strings.Contains(done.message, "1 untracked") {
+		t.Fatalf("unexpected snapshot result %+v", done)
+	}
+	_ = m.handleSnapshotDone(done)
+
+	if err := os.Remove(notes); err != nil {
+		t.Fatal(err)
+	}
+	_ = m.showRestoreSnapshot()
+	if m.currentScreen != screenListSelect || m.listScreen == nil || len(m.listScreen.items) != 1 {
+		t.Fatalf("expected one snapshot to choose from, got %s", screenName(m.currentScreen))
+	}
+	_ = m.listSubmit(m.listScreen.items[0])
+	if m.currentScreen != screenConfirm {
+		t.Fatalf("expected a confirmation, got %s", screenName(m.currentScreen))
+	}
+	restored, ok := m.confirmAction()().(snapshotDoneMsg)
+	if !ok || restored.err != nil || !restored.changed {
+		t.Fatalf("unexpected restore result %+v", restored)
+	}
+	data, err := os.ReadFile(notes)
+	if err != nil || string(data) != "draft\n" {
+		t.Fatalf("expected notes.txt back, got %q (%v)", data, err)
+	}
+	if cmd := m.handleSnapshotDone(restored); cmd == nil {
+		t.Fatal("expected the worktrees to be reloaded")
+	}
+}
+
+func TestRestoreSnapshotReadOnly(t *testing.T) {
+	m := NewModel(&config.AppConfig{WorktreeDir: t.TempDir(), ReadOnly: true}, "")
+	m.worktrees = []*models.WorktreeInfo{{Path: t.TempDir(), Branch: "main", IsMain: true}}
+	m.filteredWts = m.worktrees
+	m.selectedIndex = 0
+	_ = m.showRestoreSnapshot()
+	if m.currentScreen != screenInfo || !strings.Contains(m.infoScreen.message, "read-only") {
+		t.Fatalf("expected restore to be refused in read-only mode, got %s", screenName(m.currentScreen))
+	}
+}
@@ -0,0 +1,115 @@
+package app
+
+import (
+	"sort"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+// sortModeCount is the number of sort modes { and } cycle through.
+const sortModeCount = 4
+
+// sortWorktrees orders worktrees by the sort mode: names ascending, dates
+// newest first and the manual order as arranged, or the other way round
+// when the order is reversed. Pinned worktrees come first either way.
+func (m *Model) sortWorktrees(wts []*models.WorktreeInfo) {
+	less := func(a, b *models.WorktreeInfo) bool { return a.Path < b.Path }
+	switch m.sortMode {
+	case sortModeLastActive:
+		less = func(a, b *models.WorktreeInfo) bool { return a.LastActiveTS > b.LastActiveTS }
+	case sortModeLastSwitched:
+		less = func(a, b *models.WorktreeInfo) bool { return a.LastSwitchedTS > b.LastSwitchedTS }
+	case sortModeManual:
+		less = func(a, b *models.WorktreeInfo) bool {
+			if ra, rb := m.manualRank(a.Path), m.manualRank(b.Path); ra != rb {
+				return ra < rb
+			}
+			return a.Path < b.Path
+		}
+	}
+	sort.Slice(wts, func(i, j int) bool {
+		if m.sortReversed {
+			return less(wts[j], wts[i])
+		}
+		return less(wts[i], wts[j])
+	})
+	if len(m.worktreeOrder.Pinned) > 0 {
+		sort.SliceStable(wts, func(i, j int) bool {
+			return m.pinRank(wts[i].Path) < m.pinRank(wts[j].Path)
+		})
+	}
+}
+
+// cycleSortMode sorts by the next (delta 1) or previous (delta -1) sort
+// mode, in its usual direction.
+func (m *Model) cycleSortMode(delta int) tea.Cmd {
+	m.sortMode = (m.sortMode + delta + sortModeCount) % sortModeCount
+	m.sortReversed = false
+	m.updateTable()
+	return nil
+}
+
+// sortBy sorts by mode, or reverses the order when already sorted by it.
+func (m *Model) sortBy(mode int) tea.Cmd {
+	if m.sortMode == mode {
+		m.sortReversed = !m.sortReversed
+	} else {
+		m.sortMode = mode
+		m.sortReversed = false
+	}
+	m.updateTable()
+	return nil
+}
+
+// sortArrow points up while the list ascends and down while it descends.
+func (m *Model) sortArrow() string {
+	if (m.sortMode == sortModePath || m.sortMode == sortModeManual) != m.sortReversed {
+		return "▲"
+	}
+	return "▼"
+}
+
+// nameColumnTitle marks the Name column with the sort direction when
+// sorting by path, and with the last switched and manual orders, which
+// have no column of their own.
+func (m *Model) nameColumnTitle() string {
+	switch m.sortMode {
+	case sortModePath:
+		return "Name " + m.sortArrow()
+	case sortModeLastSwitched:
+		return "Name (switched " + m.sortArrow() + ")"
+	case sortModeManual:
+		return "Name (manual " + m.sortArrow() + ")"
+	}
+	return "Name"
+}
+
+// lastActiveColumnTitle marks the Last Active column while sorting by it.
+func (m *Model) lastActiveColumnTitle() string {
+	if m.sortMode == sortModeLastActive {
+		return "Last Active " + m.sortArrow()
+	}
+	return "Last Active"
+}
+
+// handleHeaderClick sorts by the worktree table column at x, counted from
+// the table's left edge; columns that cannot be sorted by are ignored.
+func (m *Model) handleHeaderClick(x int) tea.Cmd {
+	for i, column := range m.worktreeTable.Columns() {
+		// Header cells are padded by a space on each side.
+		width := column.Width + 2
+		if x >= width {
+			x -= width
+			continue
+		}
+		switch i {
+		case 0:
+			return m.sortBy(sortModePath)
+		case 3:
+			return m.sortBy(sortModeLastActive)
+		}
+		return nil
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/chmouel/lazyworktree/internal/config"
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+func TestSortColumns(t *testing.T) {
+	m := NewModel(&config.AppConfig{WorktreeDir: t.TempDir(), SortMode: "path"}, "")
+	m.worktrees = []*models.WorktreeInfo{
+		{Path: "/wt/b", Branch: "b", LastActiveTS: 300},
+		{Path: "/wt/a", Branch: "a", LastActiveTS: 100},
+		{Path: "/wt/c", Branch: "c", LastActiveTS: 200},
+	}
+	order := func() string {
+		s := ""
+		for _, wt := range m.filteredWts {
+			s += wt.Branch
+		}
+		return s
+	}
+	m.updateTable()
+	m.updateTableColumns(80)
+	if order() != "abc" || m.worktreeTable.Columns()[0].Title != "Name ▲" {
+		t.Fatalf("expected names ascending, got %s %q", order(), m.worktreeTable.Columns()[0].Title)
+	}
+
+	m.sortBy(sortModePath)
+	m.updateTableColumns(80)
+	if order() != "cba" || m.worktreeTable.Columns()[0].Title != "Name ▼" {
+		t.Fatalf("expected sorting by the same column to reverse, got %s %q", order(), m.worktreeTable.Columns()[0].Title)
+	}
+
+	m.cycleSortMode(1)
+	m.updateTableColumns(80)
+	columns := m.worktreeTable.Columns()
+	if order() != "bca" || columns[0].Title != "Name" || columns[3].Title != "Last Active ▼" {
+		t.Fatalf("expected newest first on Last Active, got %s %v", order(), columns)
+	}
+
+	m.cycleSortMode(1)
+	m.updateTableColumns(80)
+	if m.sortMode != sortModeLastSwitched || m.worktreeTable.Columns()[0].Title != "Name (switched ▼)" {
+		t.Fatalf("expected the switched order on the Name column, got %q", m.worktreeTable.Columns()[0].Title)
+	}
+	m.cycleSortMode(-1)
+	if m.sortMode != sortModeLastActive {
+		t.Fatal("expected { to go back to Last Active")
+	}
+
+	// A click on the Last Active header reverses it, one on Name sorts by path.
+	columns = m.worktreeTable.Columns()
+	lastActiveX := columns[0].Width + columns[1].Width + columns[2].Width + 6
+	m.handleHeaderClick(lastActiveX)
+	if order() != "acb" {
+		t.Fatalf("expected oldest first after clicking Last Active, got %s", order())
+	}
+	m.handleHeaderClick(0)
+	if m.sortMode != sortModePath || m.sortReversed || order() != "abc" {
+		t.Fatalf("expected a click on Name to sort by path, got %s", order())
+	}
+	m.handleHeaderClick(columns[0].Width + 3)
+	if m.sortMode != sortModePath {
+		t.Fatal("expected the Changes column not to change the sort")
+	}
+}
+
+func TestPinnedAndManualOrder(t *testing.T) {
+	cfg := &config.AppConfig{WorktreeDir: t.TempDir(), SortMode: "path"}
+	m := NewModel(cfg, "")
+	m.worktrees = []*models.WorktreeInfo{
+		{Path: "/wt/a", Branch: "a", LastActiveTS: 100},
+		{Path: "/wt/b", Branch: "b", LastActiveTS: 300},
+		{Path: "/wt/c", Branch: "c", LastActiveTS: 200},
+		{Path: "/wt/d", Branch: "d", LastActiveTS: 400},
+	}
+	order := func() string {
+		s := ""
+		for _, wt := range m.filteredWts {
+			s += wt.Branch
+		}
+		return s
+	}
+	m.updateTable()
+
+	m.worktreeTable.SetCursor(2)
+	m.togglePin()
+	if order() != "cabd" || m.worktreeTable.Cursor() != 0 {
+		t.Fatalf("expected c pinned to the top and still selected, got %s at %d", order(), m.worktreeTable.Cursor())
+	}
+	if m.cycleSortMode(1); order() != "cdba" {
+		t.Fatalf("expected c to stay on top sorted by Last Active, got %s", order())
+	}
+	if !strings.HasSuffix(m.worktreeTable.Rows()[0][0], " "+pinnedMarker) {
+		t.Fatalf("expected the pinned marker, got %q", m.worktreeTable.Rows()[0][0])
+	}
+
+	// Moving the pinned worktree down over an unpinned one is refused.
+	m.worktreeTable.SetCursor(0)
+	if m.moveWorktree(1); order() != "cdba" {
+		t.Fatalf("expected a pinned worktree to stay above the others, got %s", order())
+	}
+	// Moving another switches to the manual order, started from the one shown.
+	m.worktreeTable.SetCursor(3)
+	m.moveWorktree(-1)
+	if order() != "cdab" || m.sortMode != sortModeManual || m.worktreeTable.Cursor() != 2 {
+		t.Fatalf("expected a moved up in a manual order, got %s in mode %d at %d", order(), m.sortMode, m.worktreeTable.Cursor())
+	}
+
+	// The order is kept per repository across restarts.
+	reloaded := NewModel(cfg, "")
+	reloaded.loadWorktreeOrder()
+	reloaded.sortMode = sortModeManual
+	reloaded.worktrees = m.worktrees
+	reloaded.updateTable()
+	if got := reloaded.filteredWts[0].Branch + reloaded.filteredWts[2].Branch; got != "ca" {
+		t.Fatalf("expected the pins and manual order reloaded, got %s", got)
+	}
+}
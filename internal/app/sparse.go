@@ -0,0 +1,145 @@
+package app
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	sparsePickID = "\x00sparse-pick"
+	sparseFullID = "\x00sparse-full"
+)
+
+// sparseCheckoutMsg reports a worktree whose sparse checkout was changed.
+type sparseCheckoutMsg struct {
+	path    string
+	message string
+}
+
+// sparseCheckoutOffered reports whether the create flow offers a sparse
+// checkout.
+func (m *Model) sparseCheckoutOffered() bool {
+	return m.config.SparseCheckout || len(m.config.SparseCheckoutPresets) > 0
+}
+
+// showSparseChoice offers the configured presets and a directory picker,
+// then calls onChoose with the directories to check out. With allowFull, a
+// full checkout may be chosen too, passing nil.
+func (m *Model) showSparseChoice(title, ref string, current []string, allowFull bool, onChoose func([]string) tea.Cmd) tea.Cmd {
+	presets := m.config.SparseCheckoutPresets
+	if len(presets) == 0 && !allowFull {
+		return m.showSparseDirectoryPicker(title, ref, current, onChoose)
+	}
+	names := make([]string, 0, len(presets))
+	for name := range presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	items := make([]selectionItem, 0, len(names)+2)
+	for _, name := range names {
+		items = append(items, selectionItem{id: name, label: name, description: strings.Join(presets[name], ", ")})
+	}
+	items = append(items, selectionItem{id: sparsePickID, label: "Pick directories…", description: "Choose from the repository tree"})
+	if allowFull {
+		items = append(items, selectionItem{id: sparseFullID, label: "Full checkout", description: "Disable sparse checkout"})
+	}
+
+	m.listScreen = NewListSelectionScreen(items, title, "Filter presets...", "No matching presets.", m.windowWidth, m.windowHeight, "", m.theme)
+	m.listSubmit = func(item selectionItem) tea.Cmd {
+		m.clearListSelection()
+		switch item.id {
+		case sparsePickID:
+			return m.showSparseDirectoryPicker(title, ref, current, onChoose)
+		case sparseFullID:
+			return onChoose(nil)
+		default:
+			return onChoose(presets[item.id])
+		}
+	}
+	m.currentScreen = screenListSelect
+	return textinput.Blink
+}
+
+// showSparseDirectoryPicker lists the directories of ref, with current
+// ones checked, for choosing what to check out.
+func (m *Model) showSparseDirectoryPicker(title, ref string, current []string, onChoose func([]string) tea.Cmd) tea.Cmd {
+	dirs := m.git.TreeDirectories(m.ctx, ref)
+	for _, dir := range current {
+		if !slices.Contains(dirs, dir) {
+			dirs = append(dirs, dir)
+		}
+	}
+	if len(dirs) == 0 {
+		m.showInfo(fmt.Sprintf("No directories found in %s to check out sparsely.", ref), nil)
+		return nil
+	}
+	sort.Strings(dirs)
+	items := make([]ChecklistItem, 0, len(dirs))
+	for _, dir := range dirs {
+		items = append(items, ChecklistItem{ID: dir, Label: dir, Checked: slices.Contains(current, dir)})
+	}
+	m.checklistScreen = NewChecklistScreen(items, title, "Filter directories...", "No matching directories.", m.windowWidth, m.windowHeight, m.theme)
+	m.checklistSubmit = func(selected []ChecklistItem) tea.Cmd {
+		if len(selected) == 0 {
+			m.showInfo("No directories were chosen; nothing was changed.\n\nTop-level files are always checked out, so choose at least one directory.", nil)
+			return nil
+		}
+		chosen := make([]string, 0, len(selected))
+		for _, item := range selected {
+			chosen = append(chosen, item.ID)
+		}
+		return onChoose(chosen)
+	}
+	m.currentScreen = screenChecklist
+	return textinput.Blink
+}
+
+// showEditSparseCheckout changes which directories the selected worktree
+// checks out, or restores a full checkout.
+func (m *Model) showEditSparseCheckout() tea.Cmd {
+	if m.readOnlyDenied("Changing the sparse checkout") {
+		return nil
+	}
+	wt := m.selectedWorktree()
+	if wt == nil {
+		return nil
+	}
+	name := filepath.Base(wt.Path)
+	if wt.Bare || wt.Prunable {
+		m.showInfo(fmt.Sprintf("%s has no working tree to check out sparsely.", name), nil)
+		return nil
+	}
+	ref := wt.Head
+	if ref == "" {
+		ref = "HEAD"
+	}
+	current := m.git.SparseCheckoutDirs(m.ctx, wt.Path)
+	path := wt.Path
+	return m.showSparseChoice(fmt.Sprintf("Sparse checkout of %s", name), ref, current, wt.Sparse, func(dirs []string) tea.Cmd {
+		return func() tea.Msg {
+			if len(dirs) == 0 {
+				if !m.git.DisableSparseCheckout(m.ctx, path) {
+					return errMsg{err: fmt.Errorf("failed to restore a full checkout of %s", name)}
+				}
+				return sparseCheckoutMsg{path: path, message: fmt.Sprintf("%s now has a full checkout", name)}
+			}
+			if !m.git.SetSparseCheckout(m.ctx, path, dirs) {
+				return errMsg{err: fmt.Errorf("failed to update the sparse checkout of %s", name)}
+			}
+			return sparseCheckoutMsg{path: path, message: fmt.Sprintf("%s checks out %s", name, strings.Join(dirs, ", "))}
+		}
+	})
+}
+
+// handleSparseCheckout notes the change and reloads the worktree.
+func (m *Model) handleSparseCheckout(msg sparseCheckoutMsg) tea.Cmd {
+	m.statusContent = msg.message
+	delete(m.detailsCache, msg.path)
+	return m.refreshWorktrees()
+}
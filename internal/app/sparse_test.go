@@ -0,0 +1,140 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/chmouel/lazyworktree/internal/config"
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+func initSparseRepo(t *testing.T) string {
+	t.Helper()
+	repo := t.TempDir()
+	runGit(t, repo, "init", "-b", "main")
+	runGit(t, repo, "config", "user.email", "test@example.com")
+	runGit(t, repo, "config", "user.name", "Test User")
+	runGit(t, repo, "config", "commit.gpgsign", "false")
+	for _, file := range []string{"README.md", "web/index.js", "api/main.go", "docs/guide.md"} {
+		path := filepath.Join(repo, file)
+		if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(file+"\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	runGit(t, repo, "add", ".")
+	runGit(t, repo, "commit", "-m", "Initial commit")
+	return repo
+}
+
+func TestCreateSparseWorktreeFromPreset(t *testing.T) {
+	repo := initSparseRepo(t)
+	withCwd(t, repo)
+
+	cfg := &config.AppConfig{
+		WorktreeDir:           t.TempDir(),
+		SparseCheckoutPresets: map[string][]string{"frontend": {"web"}},
+	}
+	m := NewModel(cfg, "")
+	_ = m.showBranchNameInput("main", "")
+	if !m.inputScreen.checkboxEnabled {
+		t.Fatal("expected the sparse checkout checkbox with presets configured")
+	}
+	cmd, ok := m.inputSubmit("frontend-work", true)
+	if !ok || cmd == nil || m.currentScreen != screenListSelect {
+		t.Fatalf("expected the preset list, got %s", screenName(m.currentScreen))
+	}
+	labels := make([]string, 0, len(m.listScreen.items))
+	for _, item := range m.listScreen.items {
+		labels = append(labels, item.label)
+	}
+	if got := strings.Join(labels, ","); got != "frontend,Pick directories…" {
+		t.Fatalf("unexpected choices %q", got)
+	}
+
+	create := m.listSubmit(m.listScreen.items[0])
+	if create == nil || m.currentScreen != screenLoading {
+		t.Fatalf("expected the worktree to be created, got %s", screenName(m.currentScreen))
+	}
+	loaded, ok := create().(worktreesLoadedMsg)
+	if !ok || loaded.err != nil {
+		t.Fatalf("unexpected result %+v", loaded)
+	}
+	target := filepath.Join(m.getRepoWorktreeDir(), "frontend-work")
+	if _, err := os.Stat(filepath.Join(target, "web", "index.js")); err != nil {
+		t.Fatalf("expected web to be checked out: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(target, "api")); !os.IsNotExist(err) {
+		t.Fatalf("expected api to be left out, got %v", err)
+	}
+	for _, wt := range loaded.worktrees {
+		if wt.Branch == "frontend-work" && !wt.Sparse {
+			t.Fatal("expected the new worktree to be marked sparse")
+		}
+	}
+}
+
+func TestBranchNameInputWithoutSparseCheckout(t *testing.T) {
+	cfg := &config.AppConfig{WorktreeDir: t.TempDir()}
+	m := NewModel(cfg, "")
+	_ = m.showBranchNameInput("main", "")
+	if m.inputScreen.checkboxEnabled {
+		t.Fatal("expected no sparse checkout checkbox by default")
+	}
+}
+
+func TestEditSparseCheckout(t *testing.T) {
+	repo := initSparseRepo(t)
+	withCwd(t, repo)
+	target := filepath.Join(t.TempDir(), "docs-work")
+	runGit(t, repo, "worktree", "add", "--no-checkout", "-b", "docs-work", target, "main")
+	runGit(t, target, "sparse-checkout", "set", "--cone", "docs")
+	runGit(t, target, "read-tree", "-mu", "HEAD")
+
+	m := NewModel(&config.AppConfig{WorktreeDir: t.TempDir()}, "")
+	m.worktrees = []*models.WorktreeInfo{{Path: target, Branch: "docs-work", Sparse: true}}
+	m.filteredWts = m.worktrees
+	m.selectedIndex = 0
+
+	_ = m.showEditSparseCheckout()
+	if m.currentScreen != screenListSelect {
+		t.Fatalf("expected the choices, got %s", screenName(m.currentScreen))
+	}
+	if last := m.listScreen.items[len(m.listScreen.items)-1]; last.id != sparseFullID {
+		t.Fatalf("expected a full checkout choice, got %q", last.label)
+	}
+
+	_ = m.listSubmit(m.listScreen.items[0])
+	if m.currentScreen != screenChecklist {
+		t.Fatalf("expected the directory picker, got %s", screenName(m.currentScreen))
+	}
+	selected := m.checklistScreen.SelectedItems()
+	if len(selected) != 1 || selected[0].ID != "docs" {
+		t.Fatalf("expected the current directory to be checked, got %+v", selected)
+	}
+	if cmd := m.checklistSubmit(nil); cmd != nil || m.currentScreen != screenInfo {
+		t.Fatal("expected an empty choice to be refused")
+	}
+
+	msg := m.checklistSubmit([]ChecklistItem{{ID: "docs"}, {ID: "api"}})()
+	done, ok := msg.(sparseCheckoutMsg)
+	if !ok || !strings.Contains(done.message, "docs, api") {
+		t.Fatalf("unexpected result %#v", msg)
+	}
+	if _, err := os.Stat(filepath.Join(target, "api", "main.go")); err != nil {
+		t.Fatalf("expected api to be checked out: %v", err)
+	}
+}
+
+func TestEditSparseCheckoutReadOnly(t *testing.T) {
+	m := NewModel(&config.AppConfig{WorktreeDir: t.TempDir(), ReadOnly: true}, "")
+	m.worktrees = []*models.WorktreeInfo{{Path: t.TempDir(), Branch: "main"}}
+	m.filteredWts = m.worktrees
+	if cmd := m.showEditSparseCheckout(); cmd != nil || m.currentScreen != screenInfo {
+		t.Fatal("expected read-only mode to refuse")
+	}
+}
@@ -0,0 +1,245 @@
+package app
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/chmouel/lazyworktree/internal/git"
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+// stackLinksMsg carries which branches are stacked on which.
+type stackLinksMsg struct {
+	links map[string]git.StackLink // branch -> parent
+}
+
+// restackDoneMsg reports restacking a branch's descendants.
+type restackDoneMsg struct {
+	branch    string
+	restacked []string
+	err       error
+}
+
+// loadStackLinks works out the branch stacks in the background.
+func (m *Model) loadStackLinks() tea.Cmd {
+	branches := make([]string, 0, len(m.worktrees))
+	for _, wt := range m.worktrees {
+		if wt.Branch != "" {
+			branches = append(branches, wt.Branch)
+		}
+	}
+	if len(branches) < 2 {
+		return nil
+	}
+	record := m.config == nil || !m.config.ReadOnly
+	return func() tea.Msg {
+		return stackLinksMsg{links: m.git.StackLinks(m.ctx, branches, m.git.GetMainBranch(m.ctx), record)}
+	}
+}
+
+// handleStackLinks lists stacked branches under their parents.
+func (m *Model) handleStackLinks(msg stackLinksMsg) {
+	m.stackLinks = msg.links
+	m.updateTable()
+	if wt := m.selectedWorktree(); wt != nil {
+		m.infoContent = m.buildInfoContent(wt)
+	}
+}
+
+// stackChildren returns the branches stacked directly on branch, in name
+// order.
+func (m *Model) stackChildren(branch string) []string {
+	var children []string
+	for child, link := range m.stackLinks {
+		if link.Parent == branch {
+			children = append(children, child)
+		}
+	}
+	slices.Sort(children)
+	return children
+}
+
+// stackDescendants returns the branches stacked on branch, each after its
+// parent.
+func (m *Model) stackDescendants(branch string) []string {
+	var descendants []string
+	seen := map[string]bool{branch: true}
+	queue := []string{branch}
+	for len(queue) > 0 {
+		for _, child := range m.stackChildren(queue[0]) {
+			if !seen[child] {
+				seen[child] = true
+				descendants = append(descendants, child)
+				queue = append(queue, child)
+			}
+		}
+		queue = queue[1:]
+	}
+	return descendants
+}
+
+// orderByStack moves stacked worktrees right under their parent, keeping the
+// order of wts otherwise, and returns how deep each one is in its stack.
+func (m *Model) orderByStack(wts []*models.WorktreeInfo) ([]*models.WorktreeInfo, map[string]int) {
+	if len(m.stackLinks) == 0 {
+		return wts, nil
+	}
+	listed := make(map[string]bool, len(wts))
+	for _, wt := range wts {
+		listed[wt.Branch] = true
+	}
+	children := make(map[string][]*models.WorktreeInfo)
+	var roots []*models.WorktreeInfo
+	for _, wt := range wts {
+		if link, ok := m.stackLinks[wt.Branch]; ok && listed[link.Parent] && link.Parent != wt.Branch {
+			children[link.Parent] = append(children[link.Parent], wt)
+		} else {
+			roots = append(roots, wt)
+		}
+	}
+
+	ordered := make([]*models.WorktreeInfo, 0, len(wts))
+	depths := make(map[string]int)
+	placed := make(map[*models.WorktreeInfo]bool, len(wts))
+	var place func(wt *models.WorktreeInfo, depth int)
+	place = func(wt *models.WorktreeInfo, depth int) {
+		if placed[wt] {
+			return
+		}
+		placed[wt] = true
+		ordered = append(ordered, wt)
+		if depth > 0 {
+			depths[wt.Path] = depth
+		}
+		for _, child := range children[wt.Branch] {
+			place(child, depth+1)
+		}
+	}
+	for _, wt := range roots {
+		place(wt, 0)
+	}
+	// Recorded links may loop; list whatever they left out as is.
+	for _, wt := range wts {
+		place(wt, 0)
+	}
+	return ordered, depths
+}
+
+// stackPrefix indents a stacked worktree's name by its depth.
+func stackPrefix(depth int) string {
+	if depth <= 0 {
+		return ""
+	}
+	return strings.Repeat("  ", depth-1) + "└ "
+}
+
+// stackLines shows, in the info pane, what the branch is stacked on and
+// what is stacked on it.
+func (m *Model) stackLines(wt *models.WorktreeInfo, labelStyle, valueStyle lipgloss.Style) []string {
+	link, stacked := m.stackLinks[wt.Branch]
+	children := m.stackChildren(wt.Branch)
+	if !stacked && len(children) == 0 {
+		return nil
+	}
+	var parts []string
+	if stacked {
+		parts = append(parts, valueStyle.Render("on "+link.Parent))
+	}
+	if len(children) > 0 {
+		parts = append(parts, valueStyle.Render("under "+strings.Join(children, ", ")))
+	}
+	return []string{fmt.Sprintf("%s %s", labelStyle.Render("Stack:"), strings.Join(parts, "; "))}
+}
+
+// showRestack offers to rebase the branches stacked on the selected one onto
+// its current tip, parents first.
+func (m *Model) showRestack() tea.Cmd {
+	wt := m.selectedWorktree()
+	if wt == nil {
+		return nil
+	}
+	if m.readOnlyDenied("Restacking") {
+		return nil
+	}
+	if hint := m.colocatedRestackHint(wt.Branch); hint != "" {
+		m.showInfo(hint, nil)
+		return nil
+	}
+	descendants := m.stackDescendants(wt.Branch)
+	if len(descendants) == 0 {
+		m.showInfo(fmt.Sprintf("No branch is stacked on %s.\n\nA branch is stacked on another when it was created from it rather than from the main branch.", wt.Branch), nil)
+		return nil
+	}
+
+	byBranch := make(map[string]*models.WorktreeInfo, len(m.worktrees))
+	for _, other := range m.worktrees {
+		byBranch[other.Branch] = other
+	}
+	paths := make(map[string]string, len(descendants))
+	var dirty []string
+	for _, branch := range descendants {
+		if other := byBranch[branch]; other != nil {
+			paths[branch] = other.Path
+			if hasLocalChanges(other) {
+				dirty = append(dirty, branch)
+			}
+		}
+	}
+	if len(dirty) > 0 {
+		m.showInfo(fmt.Sprintf("Cannot restack while %s has local changes.\n\nPlease commit, stash, or discard them first.", strings.Join(dirty, ", ")), nil)
+		return nil
+	}
+
+	links := make(map[string]git.StackLink, len(descendants))
+	for _, branch := range descendants {
+		links[branch] = m.stackLinks[branch]
+	}
+	parent := wt.Branch
+	m.confirmScreen = NewConfirmScreen(
+		fmt.Sprintf("Restack onto %s?\n\nRebase, parents first:\n%s\n\nA branch that conflicts is left as it was, and the rest are skipped.", parent, strings.Join(descendants, "\n")),
+		m.theme,
+	)
+	m.confirmAction = func() tea.Cmd {
+		m.loading = true
+		m.loadingScreen = NewLoadingScreen(fmt.Sprintf("Restacking onto %s...", parent), m.theme)
+		m.currentScreen = screenLoading
+		return func() tea.Msg {
+			msg := restackDoneMsg{branch: parent}
+			for _, branch := range descendants {
+				if err := m.git.Restack(m.ctx, paths[branch], branch, links[branch]); err != nil {
+					msg.err = err
+					break
+				}
+				msg.restacked = append(msg.restacked, branch)
+			}
+			return msg
+		}
+	}
+	m.currentScreen = screenConfirm
+	return nil
+}
+
+// handleRestackDone reports the outcome and reloads the worktrees.
+func (m *Model) handleRestackDone(msg restackDoneMsg) tea.Cmd {
+	m.loading = false
+	if m.currentScreen == screenLoading {
+		m.currentScreen = screenNone
+		m.loadingScreen = nil
+	}
+	for _, wt := range m.worktrees {
+		delete(m.detailsCache, wt.Path)
+	}
+	if msg.err != nil {
+		done := ""
+		if len(msg.restacked) > 0 {
+			done = fmt.Sprintf("Restacked %s first.\n\n", strings.Join(msg.restacked, ", "))
+		}
+		m.showInfo(done+msg.err.Error(), nil)
+	} else {
+		m.statusContent = fmt.Sprintf("Restacked %s onto %s", strings.Join(msg.restacked, ", "), msg.branch)
+	}
+	return m.refreshWorktrees()
+}
@@ -0,0 +1,102 @@
+package app
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/chmouel/lazyworktree/internal/config"
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+func TestStackedWorktrees(t *testing.T) {
+	repo := t.TempDir()
+	runGit(t, repo, "init", "-b", "main")
+	runGit(t, repo, "config", "user.email", "test@example.com")
+	runGit(t, repo, "config", "user.name", "Test User")
+	runGit(t, repo, "config", "commit.gpgsign", "false")
+	writeRepoFile(t, repo, "README.md", "readme\n")
+	runGit(t, repo, "add", ".")
+	runGit(t, repo, "commit", "-m", "Initial commit")
+	withCwd(t, repo)
+
+	commit := func(dir, name string) {
+		writeRepoFile(t, dir, name, name+"\n")
+		runGit(t, dir, "add", ".")
+		runGit(t, dir, "commit", "-m", "Add "+name)
+	}
+	apiWt := filepath.Join(t.TempDir(), "a-api")
+	runGit(t, repo, "worktree", "add", "-b", "api", apiWt, "main")
+	commit(apiWt, "api.go")
+	otherWt := filepath.Join(t.TempDir(), "b-other")
+	runGit(t, repo, "worktree", "add", "-b", "other", otherWt, "main")
+	commit(otherWt, "other.go")
+	uiWt := filepath.Join(t.TempDir(), "c-ui")
+	runGit(t, repo, "worktree", "add", "-b", "ui", uiWt, "api")
+	commit(uiWt, "ui.go")
+
+	m := NewModel(&config.AppConfig{WorktreeDir: t.TempDir()}, "")
+	m.setWindowSize(120, 40)
+	m.worktrees = []*models.WorktreeInfo{
+		{Path: repo, Branch: "main", IsMain: true},
+		{Path: apiWt, Branch: "api"},
+		{Path: otherWt, Branch: "other"},
+		{Path: uiWt, Branch: "ui"},
+	}
+	m.sortMode = sortModePath
+	m.updateTable()
+
+	msg, ok := m.loadStackLinks()().(stackLinksMsg)
+	if !ok {
+		t.Fatal("expected stack links")
+	}
+	m.handleStackLinks(msg)
+	var order []string
+	for _, wt := range m.filteredWts {
+		order = append(order, wt.Branch)
+	}
+	if strings.Join(order, ",") != "main,api,ui,other" {
+		t.Fatalf("expected ui right under api, got %v", order)
+	}
+	for i, wt := range m.filteredWts {
+		name := m.worktreeTable.Rows()[i][0]
+		if stacked := strings.Contains(name, "└ c-ui"); stacked != (wt.Branch == "ui") {
+			t.Fatalf("unexpected name %q for %s", name, wt.Branch)
+		}
+	}
+	lines := strings.Join(m.stackLines(m.worktrees[1], lipgloss.NewStyle(), lipgloss.NewStyle()), "\n")
+	if !strings.Contains(lines, "under ui") {
+		t.Fatalf("expected api to list ui, got %q", lines)
+	}
+
+	commit(apiWt, "api2.go")
+	for i, wt := range m.filteredWts {
+		if wt.Branch == "api" {
+			m.worktreeTable.SetCursor(i)
+		}
+	}
+	m.showRestack()
+	if m.currentScreen != screenConfirm {
+		t.Fatalf("expected confirm screen, got %s", screenName(m.currentScreen))
+	}
+	done, ok := m.confirmAction()().(restackDoneMsg)
+	if !ok || done.err != nil {
+		t.Fatalf("expected restack to succeed, got %+v", done)
+	}
+	m.handleRestackDone(done)
+	if got := runGit(t, uiWt, "log", "--format=%s", "-3"); got != "Add ui.go\nAdd api2.go\nAdd api.go" {
+		t.Fatalf("expected ui on top of api, got %q", got)
+	}
+
+	m.worktreeTable.SetCursor(0)
+	for i, wt := range m.filteredWts {
+		if wt.Branch == "other" {
+			m.worktreeTable.SetCursor(i)
+		}
+	}
+	m.showRestack()
+	if m.currentScreen != screenInfo {
+		t.Fatalf("expected info screen for a branch with nothing stacked, got %s", screenName(m.currentScreen))
+	}
+}
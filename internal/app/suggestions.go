@@ -0,0 +1,195 @@
+package app
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+// suggestedBranchLimit caps the remote branches suggested.
+const suggestedBranchLimit = 10
+
+// branchSuggestion is a remote branch or an open PR of the user's worth
+// starting a worktree from.
+type branchSuggestion struct {
+	ref     string // remote-tracking branch, such as origin/feature
+	pr      *models.PRInfo
+	updated time.Time
+	author  string
+	subject string
+}
+
+// branchSuggestionsMsg carries the suggestions; open shows them even when
+// worktrees already exist, as when asked for from the palette.
+type branchSuggestionsMsg struct {
+	suggestions []branchSuggestion
+	open        bool
+}
+
+// onlyMainWorktree reports whether the repository has no worktree besides
+// the main one.
+func (m *Model) onlyMainWorktree() bool {
+	if len(m.worktrees) == 0 {
+		return false
+	}
+	for _, wt := range m.worktrees {
+		if !wt.IsMain {
+			return false
+		}
+	}
+	return true
+}
+
+// suggestBranches gathers suggestions once, in the background, when only
+// the main worktree exists.
+func (m *Model) suggestBranches() tea.Cmd {
+	if m.suggestionsChecked || m.config == nil || !m.config.SuggestBranches || m.config.ReadOnly || !m.onlyMainWorktree() {
+		return nil
+	}
+	m.suggestionsChecked = true
+	return m.loadBranchSuggestions(false)
+}
+
+// loadBranchSuggestions lists the user's open PRs and the most recently
+// updated remote branches other than the main one and those checked out.
+func (m *Model) loadBranchSuggestions(open bool) tea.Cmd {
+	checkedOut := make(map[string]bool, len(m.worktrees))
+	for _, wt := range m.worktrees {
+		checkedOut[wt.Branch] = true
+	}
+	return func() tea.Msg {
+		suggestions := []branchSuggestion{}
+		if prs, err := m.git.FetchMyOpenPRs(m.ctx); err == nil {
+			for _, pr := range prs {
+				suggestions = append(suggestions, branchSuggestion{pr: pr, author: pr.Author, subject: pr.Title})
+			}
+		}
+
+		checkedOut[m.git.GetMainBranch(m.ctx)] = true
+		raw := m.git.RunGit(m.ctx, []string{
+			"git", "for-each-ref", "--sort=-committerdate",
+			"--format=%(refname:short)%09%(committerdate:unix)%09%(authorname)%09%(subject)",
+			"refs/remotes",
+		}, "", []int{0}, true, true)
+		count := 0
+		for line := range strings.SplitSeq(raw, "\n") {
+			fields := strings.SplitN(line, "\t", 4)
+			if len(fields) < 4 || strings.HasSuffix(fields[0], "/HEAD") || !strings.Contains(fields[0], "/") {
+				continue
+			}
+			if branch := stripRemotePrefix(fields[0]); checkedOut[branch] || prBranchSuggested(suggestions, branch) {
+				continue
+			}
+			s := branchSuggestion{ref: fields[0], author: fields[2], subject: fields[3]}
+			if ts, err := strconv.ParseInt(fields[1], 10, 64); err == nil {
+				s.updated = time.Unix(ts, 0)
+			}
+			suggestions = append(suggestions, s)
+			if count++; count == suggestedBranchLimit {
+				break
+			}
+		}
+		return branchSuggestionsMsg{suggestions: suggestions, open: open}
+	}
+}
+
+// prBranchSuggested reports whether branch is the head of a suggested PR,
+// which is offered once, as the PR.
+func prBranchSuggested(suggestions []branchSuggestion, branch string) bool {
+	for _, s := range suggestions {
+		if s.pr != nil && s.pr.Branch == branch {
+			return true
+		}
+	}
+	return false
+}
+
+// showSuggestedBranches lists the suggestions from the palette, whatever
+// worktrees exist.
+func (m *Model) showSuggestedBranches() tea.Cmd {
+	if m.readOnlyDenied("Creating worktrees") {
+		return nil
+	}
+	m.loading = true
+	m.loadingScreen = NewLoadingScreen("Looking for branches to start from...", m.theme)
+	m.currentScreen = screenLoading
+	return m.loadBranchSuggestions(true)
+}
+
+// handleBranchSuggestions shows the suggestions over a fresh clone, or
+// when asked for; otherwise the worktrees list has moved on and they are
+// dropped.
+func (m *Model) handleBranchSuggestions(msg branchSuggestionsMsg) tea.Cmd {
+	if msg.open {
+		m.loading = false
+		if m.currentScreen == screenLoading {
+			m.currentScreen = screenNone
+			m.loadingScreen = nil
+		}
+	} else if m.currentScreen != screenNone || !m.onlyMainWorktree() {
+		return nil
+	}
+	if len(msg.suggestions) == 0 {
+		if msg.open {
+			m.showInfo("No remote branches or open PRs of yours to start from.", nil)
+		}
+		return nil
+	}
+
+	items := make([]selectionItem, 0, len(msg.suggestions))
+	byID := make(map[string]branchSuggestion, len(msg.suggestions))
+	for _, s := range msg.suggestions {
+		item := selectionItem{id: s.ref, label: s.ref}
+		details := []string{}
+		if s.pr != nil {
+			item.id = fmt.Sprintf("pr:%d", s.pr.Number)
+			item.label = fmt.Sprintf("PR #%d %s", s.pr.Number, s.pr.Title)
+			details = append(details, s.pr.Branch)
+		} else {
+			details = append(details, s.subject)
+		}
+		if s.author != "" {
+			details = append(details, s.author)
+		}
+		if !s.updated.IsZero() {
+			details = append(details, formatRelativeTime(s.updated))
+		}
+		item.description = strings.Join(details, " · ")
+		items = append(items, item)
+		byID[item.id] = s
+	}
+	m.listScreen = NewListSelectionScreen(items, "Suggested branches (enter: create worktree)", "Filter branches...", "No matching branches.", m.windowWidth, m.windowHeight, "", m.theme)
+	m.listSubmit = func(item selectionItem) tea.Cmd {
+		m.clearListSelection()
+		return m.createFromSuggestion(byID[item.id])
+	}
+	m.currentScreen = screenListSelect
+	return textinput.Blink
+}
+
+// createFromSuggestion creates a worktree from a suggestion straight away
+// when its name is free, and asks for a branch name otherwise. PRs go
+// through the usual PR flow.
+func (m *Model) createFromSuggestion(s branchSuggestion) tea.Cmd {
+	if s.pr != nil {
+		m.handleOpenPRsLoaded(openPRsLoadedMsg{prs: []*models.PRInfo{s.pr}})
+		submit := m.prSelectionSubmit
+		m.prSelectionScreen = nil
+		m.prSelectionSubmit = nil
+		return submit(s.pr)
+	}
+	name := sanitizeBranchNameFromTitle(stripRemotePrefix(s.ref), "")
+	targetPath, note, reason := m.newWorktreeTarget(name, name)
+	if name == "" || m.suggestBranchName(name) != name ||
+		reason != "" || note != "" || m.policyViolation(name, s.ref) != "" {
+		cmd := m.showBranchNameInput(s.ref, name)
+		m.inputScreen.prompt = fmt.Sprintf("Create worktree from %s: branch name", s.ref)
+		return cmd
+	}
+	return m.startCreateFromBase(name, targetPath, s.ref, nil)
+}
@@ -0,0 +1,85 @@
+package app
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/chmouel/lazyworktree/internal/config"
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+func TestBranchSuggestionsForFreshClone(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	repo := initTestRepo(t)
+	withCwd(t, repo.dir)
+	runGit(t, repo.dir, "remote", "add", "origin", "https://example.com/repo.git")
+	runGit(t, repo.dir, "update-ref", "refs/remotes/origin/colleague/fix", "HEAD")
+	runGit(t, repo.dir, "update-ref", "refs/remotes/origin/HEAD", "HEAD")
+
+	worktreeDir := t.TempDir()
+	m := NewModel(&config.AppConfig{WorktreeDir: worktreeDir, SuggestBranches: true}, "")
+	m.worktrees = []*models.WorktreeInfo{{Path: repo.dir, Branch: repo.branch, IsMain: true}}
+
+	cmd := m.suggestBranches()
+	if cmd == nil {
+		t.Fatal("expected suggestions for a repository with only its main worktree")
+	}
+	if m.suggestBranches() != nil {
+		t.Fatal("expected suggestions to be gathered once")
+	}
+	msg, ok := cmd().(branchSuggestionsMsg)
+	if !ok || len(msg.suggestions) != 1 {
+		t.Fatalf("expected only origin/colleague/fix, got %+v", msg)
+	}
+
+	_ = m.handleBranchSuggestions(msg)
+	if m.currentScreen != screenListSelect || len(m.listScreen.items) != 1 {
+		t.Fatalf("expected the suggestions list, got %s", screenName(m.currentScreen))
+	}
+	item := m.listScreen.items[0]
+	if item.label != "origin/colleague/fix" || !strings.Contains(item.description, "Add new feature") {
+		t.Fatalf("unexpected suggestion %+v", item)
+	}
+
+	create := m.listSubmit(item)
+	if create == nil || m.currentScreen != screenLoading {
+		t.Fatalf("expected the worktree to be created at once, got %s", screenName(m.currentScreen))
+	}
+	if !strings.Contains(m.statusContent, "origin/colleague/fix") {
+		t.Fatalf("unexpected status %q", m.statusContent)
+	}
+	_ = create()
+	wtPath := filepath.Join(m.getRepoWorktreeDir(), "colleague-fix")
+	if got := runGit(t, wtPath, "rev-parse", "--abbrev-ref", "HEAD"); got != "colleague-fix" {
+		t.Fatalf("expected branch colleague-fix, got %q", got)
+	}
+}
+
+func TestBranchSuggestionsSkipped(t *testing.T) {
+	m := NewModel(&config.AppConfig{WorktreeDir: t.TempDir(), SuggestBranches: true}, "")
+	m.worktrees = []*models.WorktreeInfo{
+		{Path: "/repo", Branch: "main", IsMain: true},
+		{Path: "/wt/feature", Branch: "feature"},
+	}
+	if m.suggestBranches() != nil {
+		t.Fatal("expected no suggestions once a worktree exists")
+	}
+
+	suggestions := branchSuggestionsMsg{suggestions: []branchSuggestion{{ref: "origin/feature"}}}
+	if m.handleBranchSuggestions(suggestions); m.currentScreen != screenNone {
+		t.Fatalf("expected background suggestions to be dropped, got %s", screenName(m.currentScreen))
+	}
+	suggestions.open = true
+	if m.handleBranchSuggestions(suggestions); m.currentScreen != screenListSelect {
+		t.Fatalf("expected the palette to show them, got %s", screenName(m.currentScreen))
+	}
+
+	m.currentScreen = screenNone
+	m.worktrees = m.worktrees[:1]
+	m.config.SuggestBranches = false
+	m.suggestionsChecked = false
+	if m.suggestBranches() != nil {
+		t.Fatal("expected no suggestions when suggest_branches is off")
+	}
+}
@@ -0,0 +1,98 @@
+package app
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/chmouel/lazyworktree/internal/git"
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+// remoteURLSetMsg reports the end of a remote protocol switch.
+type remoteURLSetMsg struct {
+	remote string
+	url    string
+	ok     bool
+}
+
+// upstreamRemote returns the remote the worktree's branch tracks, or an
+// empty string when it tracks none.
+func upstreamRemote(wt *models.WorktreeInfo) string {
+	remote, _, ok := parseUpstreamRef(wt.UpstreamBranch)
+	if !ok {
+		return ""
+	}
+	return remote
+}
+
+// remoteURLLines shows the URL of the remote the worktree's branch tracks,
+// with its protocol and host, once the details have loaded it.
+func (m *Model) remoteURLLines(wt *models.WorktreeInfo, labelStyle, valueStyle lipgloss.Style) []string {
+	remote := upstreamRemote(wt)
+	u, ok := git.ParseRemoteURL(m.remoteURLs[remote])
+	if remote == "" || !ok {
+		return nil
+	}
+	kind := u.Protocol
+	if u.Host != "" {
+		kind += ", " + u.Host
+	}
+	mutedStyle := lipgloss.NewStyle().Foreground(m.theme.MutedFg)
+	return []string{fmt.Sprintf("%s %s %s", labelStyle.Render("Remote:"), valueStyle.Render(remote+" "+u.Raw), mutedStyle.Render("("+kind+")"))}
+}
+
+// showSwitchRemoteProtocol offers to switch the remote the selected
+// worktree's branch tracks between its ssh and https URLs, for when the
+// credentials of one of them are at hand and not the other's.
+func (m *Model) showSwitchRemoteProtocol() tea.Cmd {
+	wt := m.selectedWorktree()
+	if wt == nil {
+		return nil
+	}
+	remote := upstreamRemote(wt)
+	if remote == "" {
+		m.showInfo(fmt.Sprintf("Cannot switch the remote because %s tracks no remote branch.", wt.Branch), nil)
+		return nil
+	}
+	if m.readOnlyDenied("Changing remotes") {
+		return nil
+	}
+	u, ok := git.ParseRemoteURL(m.git.RemoteURL(m.ctx, remote, wt.Path))
+	if !ok {
+		m.showInfo(fmt.Sprintf("Cannot read the URL of %s.", remote), nil)
+		return nil
+	}
+	switched, ok := u.Switched()
+	if !ok {
+		m.showInfo(fmt.Sprintf("%s uses %s; only ssh and https URLs can be switched.", remote, u.Protocol), nil)
+		return nil
+	}
+	to := git.ProtocolSSH
+	if u.Protocol == git.ProtocolSSH {
+		to = git.ProtocolHTTPS
+	}
+	path := wt.Path
+	m.confirmScreen = NewConfirmScreen(fmt.Sprintf("Switch %s from %s to %s?\n\n%s", remote, u.Protocol, to, switched), m.theme)
+	m.confirmAction = func() tea.Cmd {
+		return func() tea.Msg {
+			ok := m.git.SetRemoteURL(m.ctx, remote, switched, path)
+			return remoteURLSetMsg{remote: remote, url: switched, ok: ok}
+		}
+	}
+	m.currentScreen = screenConfirm
+	return nil
+}
+
+// handleRemoteURLSet shows the new URL in the info pane.
+func (m *Model) handleRemoteURLSet(msg remoteURLSetMsg) tea.Cmd {
+	if !msg.ok {
+		return nil
+	}
+	m.remoteURLs[msg.remote] = msg.url
+	m.statusContent = fmt.Sprintf("%s now points at %s", msg.remote, msg.url)
+	if wt := m.selectedWorktree(); wt != nil {
+		m.infoContent = m.buildInfoContent(wt)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/chmouel/lazyworktree/internal/config"
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+func TestSwitchRemoteProtocol(t *testing.T) {
+	dir := t.TempDir()
+	runGit(t, dir, "init", "-q")
+	runGit(t, dir, "remote", "add", "origin", "git@github.com:owner/repo.git")
+
+	m := NewModel(&config.AppConfig{WorktreeDir: t.TempDir()}, "")
+	m.worktrees = []*models.WorktreeInfo{{Path: dir, Branch: "feature", UpstreamBranch: "origin/feature"}}
+	m.filteredWts = m.worktrees
+	m.updateTable()
+
+	if info := m.buildInfoContent(m.worktrees[0]); strings.Contains(info, "Remote:") {
+		t.Fatal("expected no remote line before the URL is loaded")
+	}
+	m.remoteURLs["origin"] = "git@github.com:owner/repo.git"
+	if info := m.buildInfoContent(m.worktrees[0]); !strings.Contains(info, "git@github.com:owner/repo.git") || !strings.Contains(info, "(ssh, github.com)") {
+		t.Fatalf("expected the remote, its protocol and host, got %q", info)
+	}
+
+	m.showSwitchRemoteProtocol()
+	if m.currentScreen != screenConfirm || !strings.Contains(m.confirmScreen.message, "https://github.com/owner/repo.git") {
+		t.Fatalf("expected a confirmation with the https URL, got %v", m.currentScreen)
+	}
+	msg, ok := m.confirmAction()().(remoteURLSetMsg)
+	if !ok || !msg.ok {
+		t.Fatalf("expected the URL to be set, got %#v", msg)
+	}
+	m.handleRemoteURLSet(msg)
+	if got := runGit(t, dir, "remote", "get-url", "origin"); got != "https://github.com/owner/repo.git" {
+		t.Fatalf("expected origin to use https, got %q", got)
+	}
+	if !strings.Contains(m.infoContent, "(https, github.com)") {
+		t.Fatalf("expected the info pane to show https, got %q", m.infoContent)
+	}
+
+	m.worktrees[0].UpstreamBranch = ""
+	m.showSwitchRemoteProtocol()
+	if m.currentScreen != screenInfo || !strings.Contains(m.infoScreen.message, "tracks no remote branch") {
+		t.Fatal("expected a branch without upstream to be explained")
+	}
+}
@@ -0,0 +1,176 @@
+package app
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+// diffReportEnv passes the whitespace problems and CI findings to the diff
+// script, which prints them ahead of the diff.
+const (
+	diffReportEnv    = "LAZYWORKTREE_DIFF_REPORT"
+	diffReportScript = `
+if [ -n "$` + diffReportEnv + `" ]; then
+  printf '%s\n' "$` + diffReportEnv + `"
+fi
+`
+)
+
+// whitespaceProblem is a whitespace or line-ending error a diff adds.
+type whitespaceProblem struct {
+	path string
+	line int // Line in the new file; 0 for the file as a whole
+	kind string
+}
+
+// whitespaceFixedMsg reports fixing the whitespace of the staged changes.
+type whitespaceFixedMsg struct {
+	path    string
+	changed bool
+	err     error
+}
+
+// whitespaceProblems finds, in a unified diff, added lines with trailing
+// whitespace, additions leaving no newline at the end of a file, and files
+// whose lines, as far as the diff shows them, mix CRLF and LF endings.
+func whitespaceProblems(diff string) []whitespaceProblem {
+	var problems []whitespaceProblem
+	path := ""
+	line := 0
+	lastAdded := false
+	crlf, lf := 0, 0
+	endFile := func() {
+		if path != "" && crlf > 0 && lf > 0 {
+			problems = append(problems, whitespaceProblem{path: path, kind: fmt.Sprintf("mixed line endings (%d CRLF, %d LF)", crlf, lf)})
+		}
+		crlf, lf = 0, 0
+	}
+	countEnding := func(text string) string {
+		if trimmed, ok := strings.CutSuffix(text, "\r"); ok {
+			crlf++
+			return trimmed
+		}
+		lf++
+		return text
+	}
+	for raw := range strings.SplitSeq(diff, "\n") {
+		switch {
+		case strings.HasPrefix(raw, "diff --git "):
+			endFile()
+			path = ""
+		case strings.HasPrefix(raw, "+++ "):
+			path = strings.TrimPrefix(strings.TrimPrefix(raw, "+++ "), "b/")
+		case strings.HasPrefix(raw, "--- "):
+		case strings.HasPrefix(raw, "@@ "):
+			line = hunkNewStart(raw)
+			lastAdded = false
+		case strings.HasPrefix(raw, `\ No newline at end of file`):
+			if lastAdded {
+				problems = append(problems, whitespaceProblem{path: path, line: line - 1, kind: "no newline at end of file"})
+				// The last line has no ending to count.
+				lf--
+			}
+		case strings.HasPrefix(raw, "+"):
+			if text := countEnding(raw[1:]); strings.TrimRight(text, " \t") != text {
+				problems = append(problems, whitespaceProblem{path: path, line: line, kind: "trailing whitespace"})
+			}
+			line++
+			lastAdded = true
+		case strings.HasPrefix(raw, " "):
+			countEnding(raw[1:])
+			line++
+			lastAdded = false
+		case strings.HasPrefix(raw, "-"):
+			lastAdded = false
+		}
+	}
+	endFile()
+	return problems
+}
+
+// hunkNewStart reads the first new-file line of a "@@ -a,b +c,d @@" header.
+func hunkNewStart(header string) int {
+	_, rest, ok := strings.Cut(header, " +")
+	if !ok {
+		return 0
+	}
+	end := strings.IndexAny(rest, ", ")
+	if end < 0 {
+		return 0
+	}
+	start, _ := strconv.Atoi(rest[:end])
+	return start
+}
+
+// whitespaceReport lists the whitespace problems of the worktree's staged
+// and unstaged changes, limited to files when given, for the top of the
+// diff view. It is empty when there are none.
+func (m *Model) whitespaceReport(path string, files ...string) string {
+	var b strings.Builder
+	for _, part := range []struct {
+		label string
+		args  []string
+	}{
+		{"staged", []string{"git", "diff", "--cached", "--patch", "--no-color", "--no-ext-diff"}},
+		{"unstaged", []string{"git", "diff", "--patch", "--no-color", "--no-ext-diff"}},
+	} {
+		args := part.args
+		if len(files) > 0 {
+			args = append(append(args, "--"), files...)
+		}
+		for _, p := range whitespaceProblems(m.git.RunGit(m.ctx, args, path, []int{0}, false, true)) {
+			where := p.path
+			if p.line > 0 {
+				where = fmt.Sprintf("%s:%d", p.path, p.line)
+			}
+			fmt.Fprintf(&b, "%-8s %s: %s\n", part.label, where, p.kind)
+		}
+	}
+	if b.Len() == 0 {
+		return ""
+	}
+	return "=== Whitespace problems (W fixes the staged ones) ===\n" + b.String()
+}
+
+// diffReport is what the diff view of the worktree, limited to files when
+// given, shows ahead of the diff.
+func (m *Model) diffReport(wt *models.WorktreeInfo, files ...string) string {
+	return strings.Join(filterNonEmpty([]string{m.whitespaceReport(wt.Path, files...), m.ciFindingsReport(wt, files...)}), "\n")
+}
+
+// fixStagedWhitespace strips the whitespace errors from the selected
+// worktree's staged changes, in the index and the working tree.
+func (m *Model) fixStagedWhitespace() tea.Cmd {
+	wt := m.selectedWorktree()
+	if wt == nil {
+		return nil
+	}
+	if m.readOnlyDenied("Fixing whitespace") || m.bareDenied(wt, "Fixing whitespace") {
+		return nil
+	}
+	path := wt.Path
+	m.statusContent = "Fixing whitespace in staged changes..."
+	return func() tea.Msg {
+		changed, err := m.git.FixStagedWhitespace(m.ctx, path)
+		return whitespaceFixedMsg{path: path, changed: changed, err: err}
+	}
+}
+
+// handleWhitespaceFixed reports the outcome and refreshes the worktree.
+func (m *Model) handleWhitespaceFixed(msg whitespaceFixedMsg) tea.Cmd {
+	switch {
+	case msg.err != nil:
+		m.showInfo(fmt.Sprintf("Could not fix the whitespace of the staged changes.\n\n%s", msg.err), nil)
+		return nil
+	case !msg.changed:
+		m.statusContent = "Nothing is staged"
+		return nil
+	}
+	m.statusContent = "Fixed whitespace in staged changes"
+	delete(m.detailsCache, msg.path)
+	return m.requestWorktreeRefresh(msg.path)
+}
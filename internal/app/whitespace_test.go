@@ -0,0 +1,107 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/chmouel/lazyworktree/internal/config"
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+func TestWhitespaceProblems(t *testing.T) {
+	diff := strings.Join([]string{
+		"diff --git a/a.txt b/a.txt",
+		"--- a/a.txt",
+		"+++ b/a.txt",
+		"@@ -1,2 +1,3 @@",
+		" one",
+		"-two",
+		"+two  ",
+		"+three",
+		"\\ No newline at end of file",
+		"diff --git a/b.txt b/b.txt",
+		"--- a/b.txt",
+		"+++ b/b.txt",
+		"@@ -4 +4,2 @@ func",
+		" four\r",
+		"+five",
+		"diff --git a/c.txt b/c.txt",
+		"--- a/c.txt",
+		"+++ b/c.txt",
+		"@@ -1 +1 @@",
+		"-old\r",
+		"+new\r",
+		"",
+	}, "\n")
+
+	got := whitespaceProblems(diff)
+	want := []whitespaceProblem{
+		{path: "a.txt", line: 2, kind: "trailing whitespace"},
+		{path: "a.txt", line: 3, kind: "no newline at end of file"},
+		{path: "b.txt", kind: "mixed line endings (1 CRLF, 1 LF)"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d problems, got %+v", len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("problem %d: expected %+v, got %+v", i, want[i], got[i])
+		}
+	}
+}
+
+func TestFixStagedWhitespaceKey(t *testing.T) {
+	repo := t.TempDir()
+	runGit(t, repo, "init", "-b", "main")
+	runGit(t, repo, "config", "user.email", "test@example.com")
+	runGit(t, repo, "config", "user.name", "Test User")
+	runGit(t, repo, "config", "commit.gpgsign", "false")
+	writeRepoFile(t, repo, "a.txt", "one\n")
+	runGit(t, repo, "add", ".")
+	runGit(t, repo, "commit", "-m", "Initial commit")
+	writeRepoFile(t, repo, "a.txt", "one\ntwo \n")
+	runGit(t, repo, "add", ".")
+
+	cfg := &config.AppConfig{WorktreeDir: t.TempDir()}
+	m := NewModel(cfg, "")
+	wt := &models.WorktreeInfo{Path: repo, Branch: "main"}
+	m.worktrees = []*models.WorktreeInfo{wt}
+	m.filteredWts = m.worktrees
+	m.selectedIndex = 0
+
+	report := m.whitespaceReport(repo)
+	if !strings.Contains(report, "staged   a.txt:2: trailing whitespace") {
+		t.Fatalf("expected the staged problem to be reported, got %q", report)
+	}
+
+	_, cmd := m.handleKeyMsg(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'W'}})
+	if cmd == nil {
+		t.Fatal("expected W to fix the staged whitespace")
+	}
+	msg, ok := cmd().(whitespaceFixedMsg)
+	if !ok || msg.err != nil || !msg.changed {
+		t.Fatalf("expected the fix to succeed, got %+v", msg)
+	}
+	m.handleWhitespaceFixed(msg)
+	if m.statusContent != "Fixed whitespace in staged changes" {
+		t.Fatalf("unexpected status %q", m.statusContent)
+	}
+	data, err := os.ReadFile(filepath.Join(repo, "a.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "one\ntwo\n" {
+		t.Fatalf("expected the trailing space to go, got %q", data)
+	}
+	if report := m.whitespaceReport(repo); report != "" {
+		t.Fatalf("expected no problems left, got %q", report)
+	}
+
+	cfg.ReadOnly = true
+	if cmd := m.fixStagedWhitespace(); cmd != nil {
+		t.Fatal("expected read-only mode to refuse the fix")
+	}
+}
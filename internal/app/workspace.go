@@ -0,0 +1,184 @@
+package app
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/chmouel/lazyworktree/internal/models"
+	"github.com/chmouel/lazyworktree/internal/workspace"
+)
+
+const projectFilterAllID = "\x00all-projects"
+
+// workspaceProjectsMsg carries the sub-projects declared in the main
+// worktree's workspace manifests.
+type workspaceProjectsMsg struct {
+	projects []workspace.Project
+}
+
+// projectTouchesMsg carries the projects each worktree's diff touches, by
+// worktree path.
+type projectTouchesMsg struct {
+	touches map[string][]string
+}
+
+// detectWorkspace reads the main worktree's workspace manifests once, in
+// the background.
+func (m *Model) detectWorkspace() tea.Cmd {
+	if m.workspaceChecked {
+		return nil
+	}
+	m.workspaceChecked = true
+	root := m.getMainWorktreePath()
+	return func() tea.Msg {
+		return workspaceProjectsMsg{projects: workspace.Detect(root)}
+	}
+}
+
+// handleWorkspaceProjects stores the detected projects and refreshes the
+// selected worktree so its info pane lists those it touches.
+func (m *Model) handleWorkspaceProjects(msg workspaceProjectsMsg) tea.Cmd {
+	m.workspaceProjects = msg.projects
+	if len(msg.projects) == 0 {
+		return nil
+	}
+	m.debugf("workspace: %d projects", len(msg.projects))
+	return m.updateDetailsView()
+}
+
+// branchChangedFiles lists the files the worktree changed since it left
+// the main branch, when projects or code owners need them, or nil.
+func (m *Model) branchChangedFiles(wt *models.WorktreeInfo) []string {
+	if (len(m.workspaceProjects) == 0 && m.codeOwners == nil) || wt.Bare || wt.Prunable {
+		return nil
+	}
+	files := m.git.BranchChangedFiles(m.ctx, m.git.GetMainBranch(m.ctx), wt.Path)
+	if files == nil {
+		files = []string{}
+	}
+	return files
+}
+
+// touchedProjects returns the directories of the projects the worktree's
+// branch and uncommitted changes touch, or nil outside a monorepo.
+func (m *Model) touchedProjects(wt *models.WorktreeInfo) []string {
+	if len(m.workspaceProjects) == 0 {
+		return nil
+	}
+	return m.projectDirs(m.branchChangedFiles(wt))
+}
+
+// projectDirs returns the directories of the projects owning files.
+func (m *Model) projectDirs(files []string) []string {
+	if files == nil || len(m.workspaceProjects) == 0 {
+		return nil
+	}
+	dirs := []string{}
+	for _, project := range workspace.Touched(m.workspaceProjects, files) {
+		dirs = append(dirs, project.Dir)
+	}
+	return dirs
+}
+
+// setProjectTouches records the projects a worktree touches.
+func (m *Model) setProjectTouches(path string, dirs []string) {
+	if dirs == nil {
+		return
+	}
+	if m.projectTouches == nil {
+		m.projectTouches = make(map[string][]string)
+	}
+	m.projectTouches[path] = dirs
+}
+
+// projectLines lists the projects the worktree touches, for the info pane.
+func (m *Model) projectLines(wt *models.WorktreeInfo, labelStyle, valueStyle lipgloss.Style) []string {
+	dirs, ok := m.projectTouches[wt.Path]
+	if !ok || len(m.workspaceProjects) == 0 {
+		return nil
+	}
+	if len(dirs) == 0 {
+		mutedStyle := lipgloss.NewStyle().Foreground(m.theme.MutedFg)
+		return []string{fmt.Sprintf("%s %s", labelStyle.Render("Projects:"), mutedStyle.Render("none touched"))}
+	}
+	return []string{fmt.Sprintf("%s %s", labelStyle.Render("Projects:"), valueStyle.Render(strings.Join(dirs, ", ")))}
+}
+
+// worktreeTouchesProject reports whether a worktree passes the project
+// filter.
+func (m *Model) worktreeTouchesProject(wt *models.WorktreeInfo) bool {
+	return m.projectFilter == "" || slices.Contains(m.projectTouches[wt.Path], m.projectFilter)
+}
+
+// showProjectFilter works out which projects every worktree touches, then
+// offers to list only the worktrees touching one of them.
+func (m *Model) showProjectFilter() tea.Cmd {
+	if len(m.workspaceProjects) == 0 {
+		m.showInfo("No sub-projects were found.\n\nProjects are read from the main worktree's go.work, package.json workspaces or Cargo.toml [workspace] members.", nil)
+		return nil
+	}
+	worktrees := slices.Clone(m.worktrees)
+	m.loading = true
+	m.loadingScreen = NewLoadingScreen("Finding the projects each worktree touches...", m.theme)
+	m.currentScreen = screenLoading
+	return func() tea.Msg {
+		touches := make(map[string][]string, len(worktrees))
+		for _, wt := range worktrees {
+			if dirs := m.touchedProjects(wt); dirs != nil {
+				touches[wt.Path] = dirs
+			}
+		}
+		return projectTouchesMsg{touches: touches}
+	}
+}
+
+// handleProjectTouches lists the projects with how many worktrees touch
+// each, and filters the worktree table by the one chosen.
+func (m *Model) handleProjectTouches(msg projectTouchesMsg) tea.Cmd {
+	m.loading = false
+	if m.currentScreen == screenLoading {
+		m.currentScreen = screenNone
+		m.loadingScreen = nil
+	}
+	for path, dirs := range msg.touches {
+		m.setProjectTouches(path, dirs)
+	}
+
+	counts := make(map[string]int)
+	for _, dirs := range msg.touches {
+		for _, dir := range dirs {
+			counts[dir]++
+		}
+	}
+	items := make([]selectionItem, 0, len(m.workspaceProjects)+1)
+	if m.projectFilter != "" {
+		items = append(items, selectionItem{id: projectFilterAllID, label: "All projects", description: "Clear the project filter"})
+	}
+	for _, project := range m.workspaceProjects {
+		items = append(items, selectionItem{
+			id:          project.Dir,
+			label:       project.Dir,
+			description: fmt.Sprintf("%s · %d worktree(s)", project.Kind, counts[project.Dir]),
+		})
+	}
+	m.listScreen = NewListSelectionScreen(items, "Show worktrees touching project", "Filter projects...", "No matching projects.", m.windowWidth, m.windowHeight, "", m.theme)
+	m.listSubmit = func(item selectionItem) tea.Cmd {
+		m.clearListSelection()
+		if item.id == projectFilterAllID {
+			m.projectFilter = ""
+			m.statusContent = "Showing worktrees for all projects"
+		} else {
+			m.projectFilter = item.id
+			m.statusContent = fmt.Sprintf("Showing worktrees touching %s", item.id)
+		}
+		m.updateTable()
+		m.worktreeTable.SetCursor(0)
+		return m.updateDetailsView()
+	}
+	m.currentScreen = screenListSelect
+	return textinput.Blink
+}
@@ -0,0 +1,103 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/chmouel/lazyworktree/internal/config"
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+func writeRepoFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	path := filepath.Join(dir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestProjectFilter(t *testing.T) {
+	repo := t.TempDir()
+	runGit(t, repo, "init", "-b", "main")
+	runGit(t, repo, "config", "user.email", "test@example.com")
+	runGit(t, repo, "config", "user.name", "Test User")
+	runGit(t, repo, "config", "commit.gpgsign", "false")
+	writeRepoFile(t, repo, "go.work", "go 1.25\n\nuse (\n\t./api\n\t./worker\n)\n")
+	writeRepoFile(t, repo, "api/go.mod", "module api\n")
+	writeRepoFile(t, repo, "worker/go.mod", "module worker\n")
+	runGit(t, repo, "add", ".")
+	runGit(t, repo, "commit", "-m", "Initial commit")
+	withCwd(t, repo)
+
+	apiWt := filepath.Join(t.TempDir(), "api-work")
+	runGit(t, repo, "worktree", "add", "-b", "api-work", apiWt, "main")
+	writeRepoFile(t, apiWt, "api/handler.go", "package api\n")
+	runGit(t, apiWt, "add", ".")
+	runGit(t, apiWt, "commit", "-m", "Add handler")
+	docsWt := filepath.Join(t.TempDir(), "docs-work")
+	runGit(t, repo, "worktree", "add", "-b", "docs-work", docsWt, "main")
+	writeRepoFile(t, docsWt, "README.md", "untracked\n")
+
+	m := NewModel(&config.AppConfig{WorktreeDir: t.TempDir()}, "")
+	m.worktrees = []*models.WorktreeInfo{
+		{Path: repo, Branch: "main", IsMain: true},
+		{Path: apiWt, Branch: "api-work"},
+		{Path: docsWt, Branch: "docs-work"},
+	}
+	m.filteredWts = m.worktrees
+
+	detected, ok := m.detectWorkspace()().(workspaceProjectsMsg)
+	if !ok || len(detected.projects) != 2 {
+		t.Fatalf("expected two projects, got %+v", detected)
+	}
+	_ = m.handleWorkspaceProjects(detected)
+	if got := m.touchedProjects(m.worktrees[1]); len(got) != 1 || got[0] != "api" {
+		t.Fatalf("expected api to be touched, got %v", got)
+	}
+
+	cmd := m.showProjectFilter()
+	if cmd == nil || m.currentScreen != screenLoading {
+		t.Fatalf("expected the projects to be worked out, got %s", screenName(m.currentScreen))
+	}
+	_ = m.handleProjectTouches(cmd().(projectTouchesMsg))
+	if m.currentScreen != screenListSelect || len(m.listScreen.items) != 2 {
+		t.Fatalf("expected the project list, got %s", screenName(m.currentScreen))
+	}
+	if desc := m.listScreen.items[0].description; desc != "go · 1 worktree(s)" {
+		t.Fatalf("unexpected description %q", desc)
+	}
+	info := m.buildInfoContent(m.worktrees[2])
+	if !strings.Contains(info, "none touched") {
+		t.Fatalf("expected no touched projects in %q", info)
+	}
+
+	_ = m.listSubmit(m.listScreen.items[0])
+	if len(m.filteredWts) != 1 || m.filteredWts[0].Path != apiWt {
+		t.Fatalf("expected only the api worktree, got %d", len(m.filteredWts))
+	}
+	if !m.hasActiveFilterForPane(0) {
+		t.Fatal("expected the project filter to count as a filter")
+	}
+	m.focusedPane = 0
+	_, _ = m.clearCurrentPaneFilter()
+	if m.projectFilter != "" || len(m.filteredWts) != 3 {
+		t.Fatal("expected Esc to clear the project filter")
+	}
+}
+
+func TestProjectFilterWithoutWorkspace(t *testing.T) {
+	m := NewModel(&config.AppConfig{WorktreeDir: t.TempDir()}, "")
+	m.workspaceProjects = nil
+	if cmd := m.showProjectFilter(); cmd != nil || m.currentScreen != screenInfo {
+		t.Fatal("expected a repository without workspaces to be refused")
+	}
+	if lines := m.projectLines(&models.WorktreeInfo{Path: "/x"}, lipgloss.NewStyle(), lipgloss.NewStyle()); lines != nil {
+		t.Fatal("expected no project lines")
+	}
+}
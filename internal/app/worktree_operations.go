@@ -15,12 +15,18 @@ import (
 
 // showCreateWorktree shows the base selection screen for creating a new worktree.
 func (m *Model) showCreateWorktree() tea.Cmd {
+	if m.readOnlyDenied("Creating worktrees") {
+		return nil
+	}
 	defaultBase := m.git.GetMainBranch(m.ctx)
 	return m.showBaseSelection(defaultBase)
 }
 
 // showCreateFromCurrent initiates the "create from current" workflow.
 func (m *Model) showCreateFromCurrent() tea.Cmd {
+	if m.readOnlyDenied("Creating worktrees") {
+		return nil
+	}
 	return func() tea.Msg {
 		currentWt := m.determineCurrentWorktree()
 		if currentWt == nil {
@@ -78,6 +84,9 @@ func (m *Model) getCurrentBranchForMenu() string {
 
 // showCreateFromPR initiates fetching open PRs for worktree creation.
 func (m *Model) showCreateFromPR() tea.Cmd {
+	if m.readOnlyDenied("Creating worktrees") {
+		return nil
+	}
 	// Fetch all open PRs
 	return func() tea.Msg {
 		prs, err := m.git.FetchAllOpenPRs(m.ctx)
@@ -90,6 +99,9 @@ func (m *Model) showCreateFromPR() tea.Cmd {
 
 // showCreateFromIssue initiates fetching open issues for worktree creation.
 func (m *Model) showCreateFromIssue() tea.Cmd {
+	if m.readOnlyDenied("Creating worktrees") {
+		return nil
+	}
 	// Fetch all open issues
 	return func() tea.Msg {
 		issues, err := m.git.FetchAllOpenIssues(m.ctx)
@@ -102,6 +114,9 @@ func (m *Model) showCreateFromIssue() tea.Cmd {
 
 // showCreateWorktreeFromChanges initiates creating a worktree from changes in the selected worktree.
 func (m *Model) showCreateWorktreeFromChanges() tea.Cmd {
+	if m.readOnlyDenied("Creating worktrees") {
+		return nil
+	}
 	// Check if a worktree is selected
 	if m.selectedIndex < 0 || m.selectedIndex >= len(m.filteredWts) {
 		m.showInfo(errNoWorktreeSelected, nil)
@@ -171,6 +186,10 @@ func (m *Model) showCreateFromChangesInput(wt *models.WorktreeInfo, currentBranc
 			m.inputScreen.errorMsg = fmt.Sprintf("Path already exists: %s", targetPath)
 			return nil, false
 		}
+		if errMsg := m.policyViolation(newBranch, currentBranch); errMsg != "" {
+			m.inputScreen.errorMsg = errMsg
+			return nil, false
+		}
 
 		m.inputScreen.errorMsg = ""
 		if err := os.MkdirAll(m.getWorktreeDir(), 0o750); err != nil {
@@ -226,7 +245,6 @@ func (m *Model) showCreateFromChangesInput(wt *models.WorktreeInfo, currentBranc
 
 		// Run init commands and refresh
 		env := m.buildCommandEnv(newBranch, targetPath)
-		initCmds := m.collectInitCommands()
 		after := func() tea.Msg {
 			worktrees, err := m.git.GetWorktrees(m.ctx)
 			return worktreesLoadedMsg{
@@ -234,7 +252,7 @@ func (m *Model) showCreateFromChangesInput(wt *models.WorktreeInfo, currentBranc
 				err:       err,
 			}
 		}
-		return m.runCommandsWithTrust(initCmds, targetPath, env, after), true
+		return m.runInitCommands(targetPath, env, after), true
 	}
 	m.currentScreen = screenInput
 	return textinput.Blink
@@ -243,9 +261,7 @@ func (m *Model) showCreateFromChangesInput(wt *models.WorktreeInfo, currentBranc
 // generateAIBranchName generates a branch name using the configured AI script.
 func (m *Model) generateAIBranchName() tea.Cmd {
 	return func() tea.Msg {
-		name, err := runBranchNameScript(
-			m.ctx,
-			m.config.BranchNameScript,
+		name, err := m.runBranchNameScript(
 			m.createFromCurrentDiff,
 			"diff",
 			"",
@@ -337,6 +353,10 @@ func (m *Model) handleCreateFromCurrentReady(msg createFromCurrentReadyMsg) tea.
 			m.inputScreen.errorMsg = fmt.Sprintf("Path already exists: %s", targetPath)
 			return nil, false
 		}
+		if errMsg := m.policyViolation(newBranch, currentBranch); errMsg != "" {
+			m.inputScreen.errorMsg = errMsg
+			return nil, false
+		}
 
 		// Clear cached state
 		m.createFromCurrentDiff = ""
@@ -362,6 +382,9 @@ func (m *Model) handleCreateFromCurrentReady(msg createFromCurrentReadyMsg) tea.
 
 // executeCreateWithChanges creates a worktree and moves changes from the current worktree.
 func (m *Model) executeCreateWithChanges(wt *models.WorktreeInfo, currentBranch, newBranch, targetPath string) tea.Cmd {
+	if m.readOnlyDenied("Creating worktrees") {
+		return nil
+	}
 	return func() tea.Msg {
 		if err := m.ensureWorktreeDir(m.getWorktreeDir()); err != nil {
 			return errMsg{err: err}
@@ -422,7 +445,6 @@ func (m *Model) executeCreateWithChanges(wt *models.WorktreeInfo, currentBranch,
 
 		// Run init commands and refresh
 		env := m.buildCommandEnv(newBranch, targetPath)
-		initCmds := m.collectInitCommands()
 		after := func() tea.Msg {
 			worktrees, err := m.git.GetWorktrees(m.ctx)
 			return worktreesLoadedMsg{
@@ -430,12 +452,15 @@ func (m *Model) executeCreateWithChanges(wt *models.WorktreeInfo, currentBranch,
 				err:       err,
 			}
 		}
-		return m.runCommandsWithTrust(initCmds, targetPath, env, after)()
+		return m.runInitCommands(targetPath, env, after)()
 	}
 }
 
 // executeCreateWithoutChanges creates a worktree without moving changes.
 func (m *Model) executeCreateWithoutChanges(currentBranch, newBranch, targetPath string) tea.Cmd {
+	if m.readOnlyDenied("Creating worktrees") {
+		return nil
+	}
 	return func() tea.Msg {
 		if err := m.ensureWorktreeDir(m.getWorktreeDir()); err != nil {
 			return errMsg{err: err}
@@ -447,7 +472,6 @@ func (m *Model) executeCreateWithoutChanges(currentBranch, newBranch, targetPath
 		}
 
 		env := m.buildCommandEnv(newBranch, targetPath)
-		initCmds := m.collectInitCommands()
 		after := func() tea.Msg {
 			worktrees, err := m.git.GetWorktrees(m.ctx)
 			return worktreesLoadedMsg{
@@ -455,12 +479,15 @@ func (m *Model) executeCreateWithoutChanges(currentBranch, newBranch, targetPath
 				err:       err,
 			}
 		}
-		return m.runCommandsWithTrust(initCmds, targetPath, env, after)()
+		return m.runInitCommands(targetPath, env, after)()
 	}
 }
 
 // showDeleteWorktree shows a confirmation dialog for deleting a worktree.
 func (m *Model) showDeleteWorktree() tea.Cmd {
+	if m.readOnlyDenied("Deleting worktrees") {
+		return nil
+	}
 	if m.selectedIndex < 0 || m.selectedIndex >= len(m.filteredWts) {
 		return nil
 	}
@@ -468,7 +495,11 @@ func (m *Model) showDeleteWorktree() tea.Cmd {
 	if wt.IsMain {
 		return nil
 	}
-	m.confirmScreen = NewConfirmScreen(fmt.Sprintf("Delete worktree?\n\nPath: %s\nBranch: %s", wt.Path, wt.Branch), m.theme)
+	if wt.Locked {
+		m.showInfo(fmt.Sprintf("%s is locked%s.\n\nPlease unlock it with 'git worktree unlock' before deleting it.", filepath.Base(wt.Path), lockReasonSuffix(wt)), nil)
+		return nil
+	}
+	m.confirmScreen = NewConfirmScreen(fmt.Sprintf("Delete worktree?\n\nPath: %s\nBranch: %s", wt.Path, wt.Branch)+m.ownerWarning(wt)+m.otherInstanceWarning(), m.theme)
 	m.confirmAction = m.deleteWorktreeOnlyCmd(wt)
 	m.currentScreen = screenConfirm
 	return nil
@@ -476,6 +507,9 @@ func (m *Model) showDeleteWorktree() tea.Cmd {
 
 // showRenameWorktree shows an input screen for renaming a worktree.
 func (m *Model) showRenameWorktree() tea.Cmd {
+	if m.readOnlyDenied("Renaming worktrees") {
+		return nil
+	}
 	if m.selectedIndex < 0 || m.selectedIndex >= len(m.filteredWts) {
 		return nil
 	}
@@ -529,12 +563,16 @@ func (m *Model) showRenameWorktree() tea.Cmd {
 
 // showPruneMerged initiates the prune merged worktrees workflow.
 func (m *Model) showPruneMerged() tea.Cmd {
+	if m.readOnlyDenied("Pruning worktrees") {
+		return nil
+	}
 	if !m.git.IsGitHubOrGitLab(m.ctx) {
 		return m.performMergedWorktreeCheck()
 	}
 
 	m.checkMergedAfterPRRefresh = true
 	m.ciCache = make(map[string]*ciCacheEntry)
+	m.prLookupCache = make(map[string]*prLookupEntry)
 	m.prDataLoaded = false
 	m.updateTable()
 	m.updateTableColumns(m.worktreeTable.Width())
@@ -613,12 +651,20 @@ func (m *Model) performMergedWorktreeCheck() tea.Cmd {
 		if hasDirtyChanges {
 			desc += " - HAS UNCOMMITTED CHANGES!"
 		}
+		external := m.isExternalWorktree(info.wt)
+		if external {
+			desc += " - outside the worktree directory"
+		}
+		otherOwner := m.ownedByOther(info.wt)
+		if otherOwner {
+			desc += " - owned by " + info.wt.Owner
+		}
 
 		items = append(items, ChecklistItem{
 			ID:          branch,
 			Label:       wtName,
 			Description: desc,
-			Checked:     !hasDirtyChanges, // Uncheck dirty worktrees by default
+			Checked:     !hasDirtyChanges && !external && !otherOwner, // Uncheck dirty, external and others' worktrees by default
 		})
 	}
 
@@ -627,9 +673,16 @@ func (m *Model) performMergedWorktreeCheck() tea.Cmd {
 		return items[i].Label < items[j].Label
 	})
 
+	title := "Prune Merged Worktrees"
+	if m.colocated.Enabled() {
+		title += fmt.Sprintf(" (branches are kept for %s)", m.colocated)
+	}
+	if len(m.otherInstances()) > 0 {
+		title += " (another lazyworktree is open on this repository)"
+	}
 	m.checklistScreen = NewChecklistScreen(
 		items,
-		"Prune Merged Worktrees",
+		title,
 		"Filter...",
 		"No merged worktrees found.",
 		m.windowWidth,
@@ -655,23 +708,7 @@ func (m *Model) performMergedWorktreeCheck() tea.Cmd {
 
 		// Build the prune routine that runs terminate commands per-worktree
 		pruneRoutine := func() tea.Msg {
-			pruned := 0
-			failed := 0
-			for _, wt := range toPrune {
-				// Run terminate commands for each worktree with its environment
-				if len(terminateCmds) > 0 {
-					env := m.buildCommandEnv(wt.Branch, wt.Path)
-					_ = m.git.ExecuteCommands(m.ctx, terminateCmds, wt.Path, env)
-				}
-
-				ok1 := m.git.RunCommandChecked(m.ctx, []string{"git", "worktree", "remove", "--force", wt.Path}, "", fmt.Sprintf("Failed to remove worktree %s", wt.Path))
-				ok2 := m.git.RunCommandChecked(m.ctx, []string{"git", "branch", "-D", wt.Branch}, "", fmt.Sprintf("Failed to delete branch %s", wt.Branch))
-				if ok1 && ok2 {
-					pruned++
-				} else {
-					failed++
-				}
-			}
+			pruned, failed := m.removeWorktrees(toPrune, terminateCmds)
 			worktrees, err := m.git.GetWorktrees(m.ctx)
 			return pruneResultMsg{
 				worktrees: worktrees,
@@ -688,8 +725,43 @@ func (m *Model) performMergedWorktreeCheck() tea.Cmd {
 	return textinput.Blink
 }
 
+// removeWorktrees runs terminate commands for each worktree, then removes it
+// together with its branch, unless Jujutsu or git-branchless manages the
+// repository. It returns how many were pruned and how many failed.
+func (m *Model) removeWorktrees(toPrune []*models.WorktreeInfo, terminateCmds []string) (pruned, failed int) {
+	for _, wt := range toPrune {
+		// Run terminate commands for each worktree with its environment
+		if len(terminateCmds) > 0 {
+			env := m.buildCommandEnv(wt.Branch, wt.Path)
+			removeContext := m.writeCommandContext(env)
+			_ = m.git.ExecuteCommands(m.ctx, terminateCmds, wt.Path, env)
+			removeContext()
+		}
+
+		m.stopWorktreeServices(wt.Path)
+		m.cleanArtifacts(wt.Path)
+		entry := m.deletedEntry(wt)
+		ok1 := m.git.RunCommandChecked(m.ctx, []string{"git", "worktree", "remove", "--force", wt.Path}, "", fmt.Sprintf("Failed to remove worktree %s", wt.Path))
+		if ok1 {
+			m.recordDeleted(entry)
+		}
+		// Jujutsu and git-branchless track branches themselves, so pruning
+		// leaves them for jj bookmark delete or git hide.
+		ok2 := m.colocated.Enabled() || m.git.RunCommandChecked(m.ctx, []string{"git", "branch", "-D", wt.Branch}, "", fmt.Sprintf("Failed to delete branch %s", wt.Branch))
+		if ok1 && ok2 {
+			pruned++
+		} else {
+			failed++
+		}
+	}
+	return pruned, failed
+}
+
 // showAbsorbWorktree shows a confirmation dialog for absorbing a worktree into main.
 func (m *Model) showAbsorbWorktree() tea.Cmd {
+	if m.readOnlyDenied("Absorbing worktrees") {
+		return nil
+	}
 	if m.selectedIndex < 0 || m.selectedIndex >= len(m.filteredWts) {
 		return nil
 	}
@@ -742,7 +814,7 @@ func (m *Model) showAbsorbWorktree() tea.Cmd {
 		mergeMethod = mergeMethodRebase
 	}
 
-	m.confirmScreen = NewConfirmScreen(fmt.Sprintf("Absorb worktree into %s (%s)?\n\nPath: %s\nBranch: %s -> %s", mainBranch, mergeMethod, wt.Path, wt.Branch, mainBranch), m.theme)
+	m.confirmScreen = NewConfirmScreen(fmt.Sprintf("Absorb worktree into %s (%s)?\n\nPath: %s\nBranch: %s -> %s", mainBranch, mergeMethod, wt.Path, wt.Branch, mainBranch)+m.ownerWarning(wt)+m.otherInstanceWarning(), m.theme)
 	m.confirmAction = func() tea.Cmd {
 		return func() tea.Msg {
 			if mergeMethod == mergeMethodRebase {
@@ -786,7 +858,12 @@ func (m *Model) deleteWorktreeCmd(wt *models.WorktreeInfo) func() tea.Cmd {
 	env := m.buildCommandEnv(wt.Branch, wt.Path)
 	terminateCmds := m.collectTerminateCommands()
 	afterCmd := func() tea.Msg {
-		m.git.RunCommandChecked(m.ctx, []string{"git", "worktree", "remove", "--force", wt.Path}, "", fmt.Sprintf("Failed to remove worktree %s", wt.Path))
+		m.stopWorktreeServices(wt.Path)
+		m.cleanArtifacts(wt.Path)
+		entry := m.deletedEntry(wt)
+		if m.git.RunCommandChecked(m.ctx, []string{"git", "worktree", "remove", "--force", wt.Path}, "", fmt.Sprintf("Failed to remove worktree %s", wt.Path)) {
+			m.recordDeleted(entry)
+		}
 		m.git.RunCommandChecked(m.ctx, []string{"git", "branch", "-D", wt.Branch}, "", fmt.Sprintf("Failed to delete branch %s", wt.Branch))
 
 		worktrees, err := m.git.GetWorktrees(m.ctx)
@@ -808,6 +885,9 @@ func (m *Model) deleteWorktreeOnlyCmd(wt *models.WorktreeInfo) func() tea.Cmd {
 
 	afterCmd := func() tea.Msg {
 		// Only remove worktree
+		m.stopWorktreeServices(wt.Path)
+		m.cleanArtifacts(wt.Path)
+		entry := m.deletedEntry(wt)
 		success := m.git.RunCommandChecked(
 			m.ctx,
 			[]string{"git", "worktree", "remove", "--force", wt.Path},
@@ -822,6 +902,7 @@ func (m *Model) deleteWorktreeOnlyCmd(wt *models.WorktreeInfo) func() tea.Cmd {
 				err:    fmt.Errorf("worktree deletion failed"),
 			}
 		}
+		m.recordDeleted(entry)
 
 		return worktreeDeletedMsg{
 			path:   wt.Path,
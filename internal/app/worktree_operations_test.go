@@ -48,8 +48,8 @@ func TestShowCreateWorktreeStartsWithBasePicker(t *testing.T) {
 	if m.listScreen.title != "Select base for new worktree" {
 		t.Fatalf("unexpected list title: %q", m.listScreen.title)
 	}
-	if len(m.listScreen.items) != 6 {
-		t.Fatalf("expected 6 base options, got %d", len(m.listScreen.items))
+	if len(m.listScreen.items) != 9 {
+		t.Fatalf("expected 9 base options, got %d", len(m.listScreen.items))
 	}
 	if m.listScreen.items[0].id != "from-current" {
 		t.Fatalf("expected first option from-current, got %q", m.listScreen.items[0].id)
@@ -57,6 +57,9 @@ func TestShowCreateWorktreeStartsWithBasePicker(t *testing.T) {
 	if m.listScreen.items[1].id != "branch-list" {
 		t.Fatalf("expected second option branch-list, got %q", m.listScreen.items[1].id)
 	}
+	if m.listScreen.items[5].id != "from-clipboard" {
+		t.Fatalf("expected sixth option from-clipboard, got %q", m.listScreen.items[5].id)
+	}
 }
 
 func TestHandleCreateFromCurrentReadyCheckboxVisibility(t *testing.T) {
@@ -595,7 +598,10 @@ func TestShowPruneMerged(t *testing.T) {
 	cfg := &config.AppConfig{
 		WorktreeDir: t.TempDir(),
 	}
-	m := NewModel(cfg, "")
+	// A GitHub repository, whatever remote the tree running the tests has.
+	fake := newFakeGitService()
+	fake.host = "github"
+	m := NewModelWithGit(cfg, "", fake)
 	m.worktrees = []*models.WorktreeInfo{
 		{Path: "/tmp/main", Branch: mainWorktreeName, IsMain: true},
 		{Path: "/tmp/feat", Branch: featureBranch, PR: &models.PRInfo{State: "OPEN"}},
@@ -625,7 +631,7 @@ func TestShowPruneMerged(t *testing.T) {
 	}
 
 	// Reset and test with a merged PR
-	m = NewModel(cfg, "")
+	m = NewModelWithGit(cfg, "", fake)
 	m.worktrees = []*models.WorktreeInfo{
 		{Path: "/tmp/main", Branch: mainWorktreeName, IsMain: true},
 		{Path: "/tmp/merged", Branch: "merged", PR: &models.PRInfo{State: "MERGED"}},
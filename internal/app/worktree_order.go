@@ -0,0 +1,151 @@
+package app
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+// pinnedMarker follows the name of a pinned worktree.
+const pinnedMarker = "★"
+
+// worktreeOrder is the per-repository ordering kept by the user: the pinned
+// worktrees, in the order they sort above the others whatever the sort mode,
+// and the manual order of the rest.
+type worktreeOrder struct {
+	Pinned []string `json:"pinned,omitempty"`
+	Manual []string `json:"manual,omitempty"`
+}
+
+// isPinned reports whether the worktree at path is pinned.
+func (m *Model) isPinned(path string) bool {
+	return slices.Contains(m.worktreeOrder.Pinned, path)
+}
+
+// pinRank is the position of a pinned worktree, or the number of pinned
+// worktrees for any other, so that pinned ones sort first.
+func (m *Model) pinRank(path string) int {
+	if i := slices.Index(m.worktreeOrder.Pinned, path); i >= 0 {
+		return i
+	}
+	return len(m.worktreeOrder.Pinned)
+}
+
+// manualRank is the position of a worktree in the manual order; worktrees
+// created since it was last changed come after the others.
+func (m *Model) manualRank(path string) int {
+	if i := slices.Index(m.worktreeOrder.Manual, path); i >= 0 {
+		return i
+	}
+	return len(m.worktreeOrder.Manual)
+}
+
+// togglePin pins the selected worktree to the top of the list, or unpins it.
+func (m *Model) togglePin() tea.Cmd {
+	wt := m.selectedWorktree()
+	if wt == nil {
+		return nil
+	}
+	name := filepath.Base(wt.Path)
+	if wt.IsMain {
+		name = mainWorktreeName
+	}
+	if i := slices.Index(m.worktreeOrder.Pinned, wt.Path); i >= 0 {
+		m.worktreeOrder.Pinned = slices.Delete(m.worktreeOrder.Pinned, i, i+1)
+		m.statusContent = "Unpinned " + name
+	} else {
+		m.worktreeOrder.Pinned = append(m.worktreeOrder.Pinned, wt.Path)
+		m.statusContent = "Pinned " + name + " to the top"
+	}
+	m.saveWorktreeOrder()
+	return m.reorderKeepingSelection(wt.Path)
+}
+
+// moveWorktree swaps the selected worktree with the one shown above (delta
+// -1) or below (delta 1) it. Pinned worktrees are reordered among
+// themselves; any other move switches to the manual order, started from
+// the order shown.
+func (m *Model) moveWorktree(delta int) tea.Cmd {
+	idx := m.worktreeTable.Cursor()
+	target := idx + delta
+	if idx < 0 || idx >= len(m.filteredWts) || target < 0 || target >= len(m.filteredWts) {
+		return nil
+	}
+	wt, other := m.filteredWts[idx], m.filteredWts[target]
+	if m.isPinned(wt.Path) != m.isPinned(other.Path) {
+		m.statusContent = "Pinned worktrees stay above the others; unpin with b first"
+		return nil
+	}
+
+	order := &m.worktreeOrder.Manual
+	if m.isPinned(wt.Path) {
+		order = &m.worktreeOrder.Pinned
+	} else if m.sortMode != sortModeManual {
+		all := slices.Clone(m.worktrees)
+		m.sortWorktrees(all)
+		*order = (*order)[:0]
+		for _, w := range all {
+			*order = append(*order, w.Path)
+		}
+		m.sortMode = sortModeManual
+		m.sortReversed = false
+	}
+	for _, path := range []string{wt.Path, other.Path} {
+		if !slices.Contains(*order, path) {
+			*order = append(*order, path)
+		}
+	}
+	i, j := slices.Index(*order, wt.Path), slices.Index(*order, other.Path)
+	(*order)[i], (*order)[j] = (*order)[j], (*order)[i]
+
+	m.saveWorktreeOrder()
+	return m.reorderKeepingSelection(wt.Path)
+}
+
+// reorderKeepingSelection rebuilds the table and keeps the cursor on the
+// worktree at path.
+func (m *Model) reorderKeepingSelection(path string) tea.Cmd {
+	m.updateTable()
+	idx := m.filteredIndexForPath(path)
+	if idx < 0 {
+		return nil
+	}
+	m.worktreeTable.SetCursor(idx)
+	m.selectedIndex = idx
+	m.updateWorktreeArrows()
+	return m.updateDetailsView()
+}
+
+func (m *Model) worktreeOrderPath() string {
+	return filepath.Join(m.getWorktreeDir(), m.getRepoKey(), models.WorktreeOrderFilename)
+}
+
+func (m *Model) loadWorktreeOrder() {
+	// #nosec G304 -- path is constructed from known safe components
+	data, err := os.ReadFile(m.worktreeOrderPath())
+	if err != nil {
+		return
+	}
+	var order worktreeOrder
+	if err := json.Unmarshal(data, &order); err != nil {
+		m.debugf("failed to parse worktree order: %v", err)
+		return
+	}
+	m.worktreeOrder = order
+}
+
+func (m *Model) saveWorktreeOrder() {
+	orderPath := m.worktreeOrderPath()
+	if err := os.MkdirAll(filepath.Dir(orderPath), defaultDirPerms); err != nil {
+		m.debugf("failed to create worktree order dir: %v", err)
+		return
+	}
+	data, _ := json.Marshal(m.worktreeOrder)
+	if err := os.WriteFile(orderPath, data, defaultFilePerms); err != nil {
+		m.debugf("failed to write worktree order: %v", err)
+	}
+}
@@ -0,0 +1,83 @@
+package app
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+
+	"github.com/chmouel/lazyworktree/internal/utils"
+)
+
+// newWorktreeTarget returns where the worktree named name, on branch, is
+// created, or why it cannot be. A path the filesystem would refuse, such
+// as a name too long for eCryptfs or Windows, is swapped for one that
+// works, note saying why.
+func (m *Model) newWorktreeTarget(branch, name string) (path, note, reason string) {
+	path = filepath.Join(m.getRepoWorktreeDir(), name)
+	if reason := m.validateNewWorktreeTarget(branch, path); reason != "" {
+		return "", "", reason
+	}
+	// Over SSH the host's filesystem is unknown.
+	if m.git.RemoteHost() != "" {
+		return path, "", ""
+	}
+	existing := make([]string, 0, len(m.worktrees))
+	for _, wt := range m.worktrees {
+		existing = append(existing, wt.Path)
+	}
+	fixed, problem := utils.CheckWorktreePath(path, existing, utils.PathRulesFor(filepath.Dir(path), m.longPathsEnabled()))
+	switch {
+	case problem == "":
+		return path, "", ""
+	case fixed == "":
+		return "", "", fmt.Sprintf("Cannot create %s: %s.", path, problem)
+	}
+	if reason := m.validateNewWorktreeTarget(branch, fixed); reason != "" {
+		return "", "", reason
+	}
+	return fixed, problem, ""
+}
+
+// maxNameNumber bounds the numbered names tried for a taken one.
+const maxNameNumber = 99
+
+// freeWorktreeTarget returns the branch to create and where, as
+// newWorktreeTarget does. When numbered is set and the branch is among
+// branches or its directory exists, the first free of branch-2, branch-3
+// and so on is used instead.
+func (m *Model) freeWorktreeTarget(branch string, branches map[string]struct{}, numbered bool) (name, path, note, reason string) {
+	taken := func(name string) bool {
+		_, exists := branches[name]
+		return exists || m.worktreePathExists(filepath.Join(m.getRepoWorktreeDir(), name))
+	}
+	name = branch
+	for n := 2; numbered && taken(name) && n <= maxNameNumber; n++ {
+		name = fmt.Sprintf("%s-%d", branch, n)
+	}
+	if _, exists := branches[name]; exists {
+		return name, "", "", fmt.Sprintf("Branch %q already exists.", name)
+	}
+	path, note, reason = m.newWorktreeTarget(name, name)
+	return name, path, note, reason
+}
+
+// longPathsEnabled reports whether git may write paths beyond Windows'
+// MAX_PATH.
+func (m *Model) longPathsEnabled() bool {
+	if runtime.GOOS != "windows" {
+		return false
+	}
+	return m.git.RunGit(m.ctx, []string{"git", "config", "--bool", "core.longpaths"}, "", []int{0, 1}, true, true) == "true"
+}
+
+// offerAdjustedPath tells, on the first Enter, why the worktree directory
+// differs from the name typed, and reports that the input stays open; the
+// next Enter accepts it.
+func (m *Model) offerAdjustedPath(path, note string, offered *string) bool {
+	if note == "" || *offered == path {
+		return false
+	}
+	*offered = path
+	m.inputScreen.errorMsg = fmt.Sprintf("The directory will be %s, as %s. Press Enter to accept.", filepath.Base(path), note)
+	return true
+}
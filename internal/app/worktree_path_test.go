@@ -0,0 +1,66 @@
+package app
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/chmouel/lazyworktree/internal/config"
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+func TestBranchNameInputOffersAdjustedPath(t *testing.T) {
+	cfg := &config.AppConfig{WorktreeDir: t.TempDir()}
+	m := NewModel(cfg, "")
+	// Registered with git, but its directory is gone.
+	registered := filepath.Join(m.getRepoWorktreeDir(), "feature")
+	m.worktrees = []*models.WorktreeInfo{{Path: registered, Branch: "old-feature"}}
+
+	m.showBranchNameInput("main", "feature")
+	if _, closed := m.inputSubmit("feature", false); closed {
+		t.Fatal("expected the adjusted directory to be offered first")
+	}
+	if !strings.Contains(m.inputScreen.errorMsg, "feature-2") || !strings.Contains(m.inputScreen.errorMsg, registered) {
+		t.Fatalf("expected the offer to name the new directory and the clash, got %q", m.inputScreen.errorMsg)
+	}
+	if _, closed := m.inputSubmit("feature", false); !closed {
+		t.Fatal("expected Enter again to accept the adjusted directory")
+	}
+}
+
+func TestNewWorktreeTargetKeepsValidPaths(t *testing.T) {
+	cfg := &config.AppConfig{WorktreeDir: t.TempDir()}
+	m := NewModel(cfg, "")
+	path, note, reason := m.newWorktreeTarget("feature", "feature")
+	if path != filepath.Join(m.getRepoWorktreeDir(), "feature") || note != "" || reason != "" {
+		t.Fatalf("expected the path unchanged, got %q (%q, %q)", path, note, reason)
+	}
+}
+
+func TestBranchNameInputNumbersTakenNames(t *testing.T) {
+	cfg := &config.AppConfig{WorktreeDir: t.TempDir()}
+	m := NewModel(cfg, "")
+	m.worktrees = []*models.WorktreeInfo{{Path: "/elsewhere/demo", Branch: "demo"}, {Path: "/elsewhere/demo-2", Branch: "demo-2"}}
+
+	m.showBranchNameInput("main", "")
+	want := filepath.Join(m.getRepoWorktreeDir(), "demo-3")
+	if preview := m.inputScreen.preview("demo"); !strings.Contains(preview, "demo-3") || !strings.Contains(preview, want) {
+		t.Fatalf("expected the preview to show the numbered branch and path, got %q", preview)
+	}
+	m.inputScreen.Update(tea.KeyMsg{Type: tea.KeyCtrlN})
+	if preview := m.inputScreen.preview("demo"); !strings.Contains(preview, "already exists") {
+		t.Fatalf("expected the clash shown with numbering off, got %q", preview)
+	}
+	m.inputScreen.Update(tea.KeyMsg{Type: tea.KeyCtrlN})
+
+	var created string
+	m.showNewBranchInput("main", "", false, func(newBranch, targetPath string, _ bool) tea.Cmd {
+		created = newBranch + " " + targetPath
+		return nil
+	})
+	if _, closed := m.inputSubmit("demo", false); !closed || created != "demo-3 "+want {
+		t.Fatalf("expected demo-3 created, got %q", created)
+	}
+}
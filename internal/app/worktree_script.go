@@ -0,0 +1,41 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// worktreeScriptTimeout bounds a configured script run for a worktree.
+const worktreeScriptTimeout = 30 * time.Second
+
+// runWorktreeScript runs a user-configured script with bash in dir, adding
+// env to the environment, and returns its standard output. A failure carries
+// the script's standard error.
+func runWorktreeScript(ctx context.Context, script, dir string, env map[string]string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, worktreeScriptTimeout)
+	defer cancel()
+
+	// #nosec G204 -- script is user-configured and trusted
+	cmd := exec.CommandContext(ctx, "bash", "-c", script)
+	cmd.Dir = dir
+	cmd.Env = os.Environ()
+	for k, v := range env {
+		cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", k, v))
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if detail := strings.TrimSpace(stderr.String()); detail != "" {
+			return "", fmt.Errorf("%w: %s", err, detail)
+		}
+		return "", err
+	}
+	return stdout.String(), nil
+}
@@ -0,0 +1,23 @@
+package app
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestRunWorktreeScript(t *testing.T) {
+	dir := t.TempDir()
+	out, err := runWorktreeScript(context.Background(), `printf '%s %s' "$PWD" "$GREETING"`, dir, map[string]string{"GREETING": "hello"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasSuffix(out, " hello") || !strings.Contains(out, dir) {
+		t.Fatalf("expected the script to run in dir with env, got %q", out)
+	}
+
+	_, err = runWorktreeScript(context.Background(), "echo broken >&2; exit 3", dir, nil)
+	if err == nil || !strings.Contains(err.Error(), "broken") {
+		t.Fatalf("expected the error to carry stderr, got %v", err)
+	}
+}
@@ -0,0 +1,71 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+// worktreeStateTags returns the short tags shown after a worktree's name for
+// the attributes reported by git worktree list.
+func worktreeStateTags(wt *models.WorktreeInfo) string {
+	var tags []string
+	if wt.Bare {
+		tags = append(tags, "[bare]")
+	}
+	if wt.Locked {
+		tags = append(tags, "[locked]")
+	}
+	if wt.Prunable {
+		tags = append(tags, "[prunable]")
+	}
+	if wt.Sparse {
+		tags = append(tags, "[sparse]")
+	}
+	return strings.Join(tags, " ")
+}
+
+// lockReasonSuffix formats a lock reason for use after "is locked".
+func lockReasonSuffix(wt *models.WorktreeInfo) string {
+	if wt.LockReason == "" {
+		return ""
+	}
+	return fmt.Sprintf(" (%s)", wt.LockReason)
+}
+
+// worktreeStateLines describes the detached, bare, locked, prunable and
+// sparse attributes of a worktree for the info pane.
+func (m *Model) worktreeStateLines(wt *models.WorktreeInfo, labelStyle, valueStyle lipgloss.Style) []string {
+	warnStyle := lipgloss.NewStyle().Foreground(m.theme.WarnFg)
+	var lines []string
+	if wt.Bare {
+		lines = append(lines, fmt.Sprintf("%s %s", labelStyle.Render("Bare:"), valueStyle.Render("repository without a main working tree; Enter checks out the default branch")))
+	}
+	if wt.Detached {
+		head := wt.Head
+		if len(head) > 7 {
+			head = head[:7]
+		}
+		lines = append(lines, fmt.Sprintf("%s %s", labelStyle.Render("HEAD:"), valueStyle.Render("detached at "+head)))
+	}
+	if wt.Locked {
+		reason := wt.LockReason
+		if reason == "" {
+			reason = "no reason given"
+		}
+		lines = append(lines, fmt.Sprintf("%s %s", labelStyle.Render("Locked:"), warnStyle.Render(reason)))
+	}
+	if wt.Prunable {
+		reason := wt.PrunableReason
+		if reason == "" {
+			reason = "worktree directory is missing"
+		}
+		lines = append(lines, fmt.Sprintf("%s %s", labelStyle.Render("Prunable:"), warnStyle.Render(reason)))
+	}
+	if wt.Sparse {
+		lines = append(lines, fmt.Sprintf("%s %s", labelStyle.Render("Sparse:"), valueStyle.Render("only some directories are checked out")))
+	}
+	return lines
+}
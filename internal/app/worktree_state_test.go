@@ -0,0 +1,64 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/chmouel/lazyworktree/internal/config"
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+func TestWorktreeStateTags(t *testing.T) {
+	tests := []struct {
+		name string
+		wt   *models.WorktreeInfo
+		want string
+	}{
+		{name: "plain", wt: &models.WorktreeInfo{}, want: ""},
+		{name: "bare", wt: &models.WorktreeInfo{Bare: true}, want: "[bare]"},
+		{name: "locked and prunable", wt: &models.WorktreeInfo{Locked: true, Prunable: true}, want: "[locked] [prunable]"},
+		{name: "sparse", wt: &models.WorktreeInfo{Sparse: true}, want: "[sparse]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := worktreeStateTags(tt.wt); got != tt.want {
+				t.Fatalf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestWorktreeStateLines(t *testing.T) {
+	m := NewModel(&config.AppConfig{WorktreeDir: t.TempDir()}, "")
+	wt := &models.WorktreeInfo{
+		Head:       "abcdef1234567890",
+		Detached:   true,
+		Locked:     true,
+		LockReason: "on a removable drive",
+		Prunable:   true,
+	}
+	lines := strings.Join(m.worktreeStateLines(wt, lipgloss.NewStyle(), lipgloss.NewStyle()), "\n")
+	for _, want := range []string{"detached at abcdef1", "on a removable drive", "worktree directory is missing"} {
+		if !strings.Contains(lines, want) {
+			t.Fatalf("expected %q in info lines, got %q", want, lines)
+		}
+	}
+}
+
+func TestShowDeleteLockedWorktree(t *testing.T) {
+	m := NewModel(&config.AppConfig{WorktreeDir: t.TempDir()}, "")
+	m.filteredWts = []*models.WorktreeInfo{
+		{Path: "/tmp/main", Branch: mainWorktreeName, IsMain: true},
+		{Path: "/tmp/usb", Branch: "usb", Locked: true, LockReason: "on a removable drive"},
+	}
+	m.selectedIndex = 1
+
+	m.showDeleteWorktree()
+	if m.confirmScreen != nil {
+		t.Fatal("expected no confirm screen for a locked worktree")
+	}
+	if m.currentScreen != screenInfo || !strings.Contains(m.infoScreen.message, "on a removable drive") {
+		t.Fatalf("expected an info message naming the lock reason, got screen %v", m.currentScreen)
+	}
+}
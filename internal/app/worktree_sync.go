@@ -8,16 +8,40 @@ import (
 
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/chmouel/lazyworktree/internal/git"
 	"github.com/chmouel/lazyworktree/internal/models"
 )
 
+// credentialRetry holds git invocations that stopped at a credential or
+// passphrase prompt, so they can be replayed in the terminal.
+type credentialRetry struct {
+	dir      string
+	env      []string
+	commands [][]string
+}
+
+// gitStreamResultMsg reports a git command run by runStreamedGit, whose
+// output is already in the worktree's status pane.
+type gitStreamResultMsg struct {
+	path  string
+	title string
+	err   error
+	retry *credentialRetry
+}
+
 // pushToUpstream pushes the current branch to its upstream.
 func (m *Model) pushToUpstream() tea.Cmd {
+	if m.readOnlyDenied("Pushing") {
+		return nil
+	}
 	wt := m.selectedWorktree()
 	if wt == nil {
 		m.showInfo(errNoWorktreeSelected, nil)
 		return nil
 	}
+	if m.bareDenied(wt, "Pushing") {
+		return nil
+	}
 	if hasLocalChanges(wt) {
 		m.showInfo("Cannot push while the worktree has local changes.\n\nPlease commit, stash, or discard them first.", nil)
 		return nil
@@ -40,11 +64,17 @@ func (m *Model) pushToUpstream() tea.Cmd {
 
 // syncWithUpstream synchronises the current branch with its upstream (pull + push).
 func (m *Model) syncWithUpstream() tea.Cmd {
+	if m.readOnlyDenied("Synchronising") {
+		return nil
+	}
 	wt := m.selectedWorktree()
 	if wt == nil {
 		m.showInfo(errNoWorktreeSelected, nil)
 		return nil
 	}
+	if m.bareDenied(wt, "Synchronising") {
+		return nil
+	}
 	if hasLocalChanges(wt) {
 		m.showInfo("Cannot synchronise while the worktree has local changes.\n\nPlease commit, stash, or discard them first.", nil)
 		return nil
@@ -74,24 +104,154 @@ func (m *Model) syncWithUpstream() tea.Cmd {
 	})
 }
 
-// beginPush initiates a push operation.
+// pullFromUpstream pulls the upstream of the selected worktree's branch
+// into it, rebasing unless merge_method says otherwise.
+func (m *Model) pullFromUpstream() tea.Cmd {
+	if m.readOnlyDenied("Pulling") {
+		return nil
+	}
+	wt := m.selectedWorktree()
+	if wt == nil {
+		m.showInfo(errNoWorktreeSelected, nil)
+		return nil
+	}
+	if m.bareDenied(wt, "Pulling") {
+		return nil
+	}
+	if hasLocalChanges(wt) {
+		m.showInfo("Cannot pull while the worktree has local changes.\n\nPlease commit, stash, or discard them first.", nil)
+		return nil
+	}
+	if strings.TrimSpace(wt.Branch) == "" {
+		m.showInfo("Cannot pull into a detached worktree.", nil)
+		return nil
+	}
+	remote, branch, ok := m.validatedUpstream(wt, "pull")
+	if !ok {
+		return nil
+	}
+	return m.runStreamedGit(wt, "Pull", append([]string{"pull"}, m.syncPullArgs([]string{remote, branch})...))
+}
+
+// forcePushToUpstream asks before pushing the selected worktree's branch
+// over its upstream with --force-with-lease, which git refuses when the
+// remote branch has commits that were not fetched.
+func (m *Model) forcePushToUpstream() tea.Cmd {
+	if m.readOnlyDenied("Pushing") {
+		return nil
+	}
+	wt := m.selectedWorktree()
+	if wt == nil {
+		m.showInfo(errNoWorktreeSelected, nil)
+		return nil
+	}
+	if m.bareDenied(wt, "Pushing") {
+		return nil
+	}
+	if hasLocalChanges(wt) {
+		m.showInfo("Cannot push while the worktree has local changes.\n\nPlease commit, stash, or discard them first.", nil)
+		return nil
+	}
+	if strings.TrimSpace(wt.Branch) == "" {
+		m.showInfo("Cannot push a detached worktree.", nil)
+		return nil
+	}
+	remote, branch, ok := m.validatedUpstream(wt, "force push")
+	if !ok {
+		return nil
+	}
+	m.confirmScreen = NewConfirmScreenWithDefault(
+		fmt.Sprintf("Force push '%s' to %s/%s?\n\nThe remote branch is overwritten with --force-with-lease, which refuses when it has commits you have not fetched.", wt.Branch, remote, branch),
+		1, // Default to Cancel
+		m.theme,
+	)
+	m.confirmAction = func() tea.Cmd {
+		return m.scanBeforePush(wt, func() tea.Cmd {
+			return m.runStreamedGit(wt, "Force push", []string{"push", "--force-with-lease", remote, "HEAD:" + branch})
+		})
+	}
+	m.currentScreen = screenConfirm
+	return nil
+}
+
+// runStreamedGit runs git with args in wt through the git service,
+// following its output in the worktree's status pane, as for init
+// commands, rather than behind a loading screen.
+func (m *Model) runStreamedGit(wt *models.WorktreeInfo, title string, args []string) tea.Cmd {
+	out := &initOutput{title: title, notify: m.signalInitOutput}
+	m.initOutputsMu.Lock()
+	m.initOutputs[wt.Path] = out
+	m.initOutputsMu.Unlock()
+	m.statusContent = title + "..."
+	delete(m.detailsCache, wt.Path)
+
+	env := m.buildCommandEnv(wt.Branch, wt.Path)
+	environ := os.Environ()
+	for _, kv := range git.NonInteractiveEnv(environ)[len(environ):] {
+		key, value, _ := strings.Cut(kv, "=")
+		env[key] = value
+	}
+	quoted := make([]string, 0, len(args)+1)
+	quoted = append(quoted, "git")
+	for _, arg := range args {
+		quoted = append(quoted, shellQuote(arg))
+	}
+
+	return func() tea.Msg {
+		m.startInitOutput(wt.Path)
+		err := m.git.ExecuteCommandsTo(m.ctx, []string{strings.Join(quoted, " ")}, wt.Path, env, out)
+		out.finish(err)
+		msg := gitStreamResultMsg{path: wt.Path, title: title, err: err}
+		if data, _, _ := out.snapshot(); err != nil && git.IsCredentialPrompt(data) {
+			envVars := filterWorktreeEnvVars(environ)
+			for k, v := range m.buildCommandEnv(wt.Branch, wt.Path) {
+				envVars = append(envVars, fmt.Sprintf("%s=%s", k, v))
+			}
+			msg.retry = &credentialRetry{dir: wt.Path, env: envVars, commands: [][]string{args}}
+		}
+		return msg
+	}
+}
+
+// handleGitStreamResult refreshes the worktree once a streamed git command
+// is done, offering to re-run it in the terminal when it stopped at a
+// credential prompt.
+func (m *Model) handleGitStreamResult(msg gitStreamResultMsg) tea.Cmd {
+	refresh := m.refreshWorktree(msg.path)
+	if msg.err == nil {
+		m.statusContent = msg.title + " completed"
+		return refresh
+	}
+	m.statusContent = msg.title + " failed"
+	if msg.retry != nil {
+		return tea.Batch(refresh, m.showCredentialRetry(msg.title+" failed.", "", msg.retry))
+	}
+	return refresh
+}
+
+// beginPush initiates a push operation, once the push scan is satisfied.
 func (m *Model) beginPush(wt *models.WorktreeInfo, args []string) tea.Cmd {
-	m.loading = true
-	m.loadingOperation = "push"
-	m.statusContent = "Pushing to upstream..."
-	m.loadingScreen = NewLoadingScreen("Pushing to upstream...", m.theme)
-	m.currentScreen = screenLoading
-	return m.runPush(wt, args)
+	return m.scanBeforePush(wt, func() tea.Cmd {
+		m.loading = true
+		m.loadingOperation = "push"
+		m.statusContent = "Pushing to upstream..."
+		m.loadingScreen = NewLoadingScreen("Pushing to upstream...", m.theme)
+		m.currentScreen = screenLoading
+		return m.runPush(wt, args)
+	})
 }
 
-// beginSync initiates a sync operation (pull + push).
+// beginSync initiates a sync operation (pull + push), once the push scan is
+// satisfied.
 func (m *Model) beginSync(wt *models.WorktreeInfo, pullArgs, pushArgs []string) tea.Cmd {
-	m.loading = true
-	m.loadingOperation = "sync"
-	m.statusContent = "Synchronising with upstream..."
-	m.loadingScreen = NewLoadingScreen("Synchronising with upstream...", m.theme)
-	m.currentScreen = screenLoading
-	return m.runSync(wt, pullArgs, pushArgs)
+	return m.scanBeforePush(wt, func() tea.Cmd {
+		m.loading = true
+		m.loadingOperation = "sync"
+		m.statusContent = "Synchronising with upstream..."
+		m.loadingScreen = NewLoadingScreen("Synchronising with upstream...", m.theme)
+		m.currentScreen = screenLoading
+		return m.runSync(wt, pullArgs, pushArgs)
+	})
 }
 
 // isBehindBase checks if the current branch is behind its base branch.
@@ -268,14 +428,19 @@ func (m *Model) runPush(wt *models.WorktreeInfo, args []string) tea.Cmd {
 	cmdArgs := append([]string{"push"}, args...)
 	c := m.commandRunner("git", cmdArgs...)
 	c.Dir = wt.Path
-	c.Env = envVars
+	c.Env = git.NonInteractiveEnv(envVars)
 
 	return func() tea.Msg {
 		output, err := c.CombinedOutput()
-		return pushResultMsg{
-			output: strings.TrimSpace(string(output)),
+		text := strings.TrimSpace(string(output))
+		msg := pushResultMsg{
+			output: text,
 			err:    err,
 		}
+		if err != nil && git.IsCredentialPrompt(text) {
+			msg.retry = &credentialRetry{dir: wt.Path, env: envVars, commands: [][]string{cmdArgs}}
+		}
+		return msg
 	}
 }
 
@@ -293,34 +458,42 @@ func (m *Model) runSync(wt *models.WorktreeInfo, pullArgs, pushArgs []string) te
 	pullCmdArgs := append([]string{"pull"}, m.syncPullArgs(pullArgs)...)
 	pullCmd := m.commandRunner("git", pullCmdArgs...)
 	pullCmd.Dir = wt.Path
-	pullCmd.Env = envVars
+	pullCmd.Env = git.NonInteractiveEnv(envVars)
+	pushCmdArgs := append([]string{"push"}, pushArgs...)
 
 	return func() tea.Msg {
 		pullOutput, pullErr := pullCmd.CombinedOutput()
 		pullText := strings.TrimSpace(string(pullOutput))
 		if pullErr != nil {
-			return syncResultMsg{
+			msg := syncResultMsg{
 				stage:  "pull",
 				output: pullText,
 				err:    pullErr,
 			}
+			if git.IsCredentialPrompt(pullText) {
+				msg.retry = &credentialRetry{dir: wt.Path, env: envVars, commands: [][]string{pullCmdArgs, pushCmdArgs}}
+			}
+			return msg
 		}
 
-		pushCmdArgs := append([]string{"push"}, pushArgs...)
 		pushCmd := m.commandRunner("git", pushCmdArgs...)
 		pushCmd.Dir = wt.Path
-		pushCmd.Env = envVars
+		pushCmd.Env = git.NonInteractiveEnv(envVars)
 
 		pushOutput, pushErr := pushCmd.CombinedOutput()
 		pushText := strings.TrimSpace(string(pushOutput))
 		combined := strings.TrimSpace(strings.Join(filterNonEmpty([]string{pullText, pushText}), "\n"))
 
 		if pushErr != nil {
-			return syncResultMsg{
+			msg := syncResultMsg{
 				stage:  "push",
 				output: combined,
 				err:    pushErr,
 			}
+			if git.IsCredentialPrompt(pushText) {
+				msg.retry = &credentialRetry{dir: wt.Path, env: envVars, commands: [][]string{pushCmdArgs}}
+			}
+			return msg
 		}
 		return syncResultMsg{
 			output: combined,
@@ -340,3 +513,51 @@ func (m *Model) syncPullArgs(pullArgs []string) []string {
 	}
 	return pullArgs
 }
+
+// showCredentialRetry explains that git stopped at a credential prompt and
+// offers to replay the commands in the terminal so the user can answer it.
+func (m *Model) showCredentialRetry(heading, output string, retry *credentialRetry) tea.Cmd {
+	message := fmt.Sprintf("%s\n\nGit is waiting for a passphrase or credentials.", heading)
+	if output != "" {
+		message = fmt.Sprintf("%s\n\n%s", message, truncateToHeightFromEnd(output, 3))
+	}
+	message += "\n\nRun it in the terminal so you may answer the prompt?"
+	m.confirmScreen = NewConfirmScreen(message, m.theme)
+	m.confirmAction = func() tea.Cmd {
+		return m.runCredentialRetry(retry)
+	}
+	m.currentScreen = screenConfirm
+	return nil
+}
+
+// runCredentialRetry replays git commands interactively, handing the terminal
+// over so ssh, gpg or a credential helper can prompt.
+func (m *Model) runCredentialRetry(retry *credentialRetry) tea.Cmd {
+	if retry == nil || len(retry.commands) == 0 {
+		return nil
+	}
+	steps := make([]string, 0, len(retry.commands))
+	for _, args := range retry.commands {
+		quoted := make([]string, 0, len(args)+1)
+		quoted = append(quoted, "git")
+		for _, arg := range args {
+			quoted = append(quoted, shellQuote(arg))
+		}
+		steps = append(steps, strings.Join(quoted, " "))
+	}
+	cmdStr := strings.Join(steps, " && ")
+
+	delete(m.detailsCache, retry.dir)
+
+	// #nosec G204 -- arguments are quoted and come from the push/sync workflow
+	c := m.commandRunner("bash", "-c", cmdStr)
+	c.Dir = retry.dir
+	c.Env = retry.env
+
+	return m.execProcess(c, func(err error) tea.Msg {
+		if err != nil {
+			return errMsg{err: fmt.Errorf("git failed: %w", err)}
+		}
+		return refreshCompleteMsg{}
+	})
+}
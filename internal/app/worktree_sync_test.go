@@ -826,3 +826,132 @@ func TestConfirmNoDoesNormalSync(t *testing.T) {
 		t.Fatalf("expected git push, got %v %v", calls[1].name, calls[1].args)
 	}
 }
+
+func TestPushCredentialPromptOffersTerminalRetry(t *testing.T) {
+	cfg := &config.AppConfig{
+		WorktreeDir: t.TempDir(),
+	}
+	m := NewModel(cfg, "")
+
+	wtPath := filepath.Join(cfg.WorktreeDir, "wt1")
+	if err := os.MkdirAll(wtPath, 0o700); err != nil {
+		t.Fatalf("failed to create worktree dir: %v", err)
+	}
+
+	m.filteredWts = []*models.WorktreeInfo{
+		{Path: wtPath, Branch: featureBranch, HasUpstream: true, UpstreamBranch: testUpstreamRef},
+	}
+	m.selectedIndex = 0
+
+	m.commandRunner = func(_ string, _ ...string) *exec.Cmd {
+		return exec.Command("sh", "-c", "echo \"fatal: could not read Username for 'https://example.com': terminal prompts disabled\"; exit 128")
+	}
+
+	_, cmd := m.handleBuiltInKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'P'}})
+	if cmd == nil {
+		t.Fatal("expected command to be returned")
+	}
+	msg := cmd()
+	pushMsg, ok := msg.(pushResultMsg)
+	if !ok {
+		t.Fatalf("expected pushResultMsg, got %T", msg)
+	}
+	if pushMsg.err == nil || pushMsg.retry == nil {
+		t.Fatalf("expected credential retry, got %+v", pushMsg)
+	}
+
+	_, _ = m.Update(pushMsg)
+	if m.currentScreen != screenConfirm || m.confirmScreen == nil {
+		t.Fatalf("expected confirm screen, got %v", m.currentScreen)
+	}
+	if !strings.Contains(m.confirmScreen.message, "credentials") {
+		t.Fatalf("unexpected confirm message: %q", m.confirmScreen.message)
+	}
+
+	var gotName string
+	var gotArgs []string
+	var pushEnv []string
+	m.commandRunner = func(name string, args ...string) *exec.Cmd {
+		gotName = name
+		gotArgs = append([]string{}, args...)
+		return exec.Command("true")
+	}
+	m.execProcess = func(c *exec.Cmd, cb tea.ExecCallback) tea.Cmd {
+		pushEnv = c.Env
+		return func() tea.Msg { return cb(nil) }
+	}
+	retryCmd := m.confirmAction()
+	if retryCmd == nil {
+		t.Fatal("expected interactive retry command")
+	}
+	if _, ok := retryCmd().(refreshCompleteMsg); !ok {
+		t.Fatal("expected refreshCompleteMsg after interactive retry")
+	}
+	if gotName != "bash" || len(gotArgs) != 2 {
+		t.Fatalf("expected bash -c invocation, got %s %v", gotName, gotArgs)
+	}
+	if !strings.HasPrefix(gotArgs[1], "git 'push' 'origin'") {
+		t.Fatalf("unexpected retry command: %q", gotArgs[1])
+	}
+	for _, kv := range pushEnv {
+		if kv == "GIT_TERMINAL_PROMPT=0" {
+			t.Fatal("interactive retry must not disable terminal prompts")
+		}
+	}
+}
+
+func TestPullAndForcePushStreamOutput(t *testing.T) {
+	remote := t.TempDir()
+	runGit(t, remote, "init", "--bare", "-b", "main")
+	repo := t.TempDir()
+	runGit(t, repo, "init", "-b", "main")
+	runGit(t, repo, "config", "user.email", "test@example.com")
+	runGit(t, repo, "config", "user.name", "Test User")
+	runGit(t, repo, "config", "commit.gpgsign", "false")
+	runGit(t, repo, "remote", "add", "origin", remote)
+	writeRepoFile(t, repo, "README.md", "readme\n")
+	runGit(t, repo, "add", ".")
+	runGit(t, repo, "commit", "-m", "Initial commit")
+	runGit(t, repo, "push", "-q", "-u", "origin", "main")
+
+	other := t.TempDir()
+	runGit(t, other, "clone", "-q", remote, ".")
+	runGit(t, other, "-c", "user.email=test@example.com", "-c", "user.name=Test User", "commit", "-q", "--allow-empty", "-m", "From elsewhere")
+	runGit(t, other, "push", "-q", "origin", "main")
+
+	m := NewModel(&config.AppConfig{WorktreeDir: t.TempDir()}, "")
+	m.filteredWts = []*models.WorktreeInfo{{Path: repo, Branch: "main", HasUpstream: true, UpstreamBranch: "origin/main"}}
+	m.selectedIndex = 0
+
+	_, cmd := m.handleBuiltInKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'u'}})
+	if cmd == nil || m.currentScreen == screenLoading {
+		t.Fatal("expected the pull to run without a loading screen")
+	}
+	msg, ok := cmd().(gitStreamResultMsg)
+	if !ok || msg.err != nil || msg.title != "Pull" {
+		t.Fatalf("expected the pull to succeed, got %+v", msg)
+	}
+	if subject := runGit(t, repo, "log", "-1", "--format=%s"); subject != "From elsewhere" {
+		t.Fatalf("expected the remote commit pulled, got %q", subject)
+	}
+	if data, running, err := m.initOutputs[repo].snapshot(); running || err != nil || !strings.Contains(data, "git 'pull'") {
+		t.Fatalf("expected the pull output kept for the status pane, got %q (running %v, err %v)", data, running, err)
+	}
+	m.handleGitStreamResult(msg)
+	if m.statusContent != "Pull completed" {
+		t.Fatalf("unexpected status %q", m.statusContent)
+	}
+
+	runGit(t, repo, "commit", "-q", "--amend", "--allow-empty", "-m", "Rewritten")
+	m.handleBuiltInKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'U'}})
+	if m.currentScreen != screenConfirm || m.confirmScreen.selectedButton != 1 || !strings.Contains(m.confirmScreen.message, "--force-with-lease") {
+		t.Fatalf("expected a confirmation defaulting to cancel, got %s", screenName(m.currentScreen))
+	}
+	msg, ok = m.confirmAction()().(gitStreamResultMsg)
+	if !ok || msg.err != nil || msg.title != "Force push" {
+		t.Fatalf("expected the force push to succeed, got %+v", msg)
+	}
+	if head, pushed := runGit(t, repo, "rev-parse", "HEAD"), runGit(t, remote, "rev-parse", "main"); head != pushed {
+		t.Fatalf("expected the remote overwritten with %s, got %s", head, pushed)
+	}
+}
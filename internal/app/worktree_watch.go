@@ -0,0 +1,215 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// worktreeWatchDirLimit bounds the directories watched in each worktree, as
+// every one takes an inotify watch.
+const worktreeWatchDirLimit = 200
+
+// worktreeWatchFlushMsg fires once the edits seen in worktrees settle.
+type worktreeWatchFlushMsg struct{}
+
+// worktreeWatchLimit is the number of directories watched per worktree.
+func worktreeWatchLimit() int {
+	switch runtime.GOOS {
+	case "darwin", "freebsd", "netbsd", "openbsd", "dragonfly":
+		// kqueue holds a descriptor for every file of a watched directory.
+		return 1
+	}
+	return worktreeWatchDirLimit
+}
+
+// watchWorktrees keeps the git watcher on each worktree's tracked
+// directories and git directory in step with the worktree list, so an edit
+// or a staged change refreshes only that worktree's row rather than
+// rescanning them all. New worktrees' directories are listed in the
+// background.
+func (m *Model) watchWorktrees() tea.Cmd {
+	if !m.gitWatchStarted {
+		return nil
+	}
+	current := make(map[string]struct{}, len(m.worktrees))
+	for _, wt := range m.worktrees {
+		current[wt.Path] = struct{}{}
+	}
+
+	var added []string
+	m.gitWatchMu.Lock()
+	for path, dirs := range m.gitWatchWorktrees {
+		if _, ok := current[path]; ok {
+			continue
+		}
+		for _, dir := range dirs {
+			delete(m.gitWatchOwners, dir)
+			delete(m.gitWatchPaths, dir)
+			_ = m.gitWatcher.Remove(dir)
+		}
+		for gitDir, owner := range m.gitWatchGitDirs {
+			if owner == path {
+				delete(m.gitWatchGitDirs, gitDir)
+			}
+		}
+		delete(m.gitWatchWorktrees, path)
+	}
+	for path := range current {
+		if _, ok := m.gitWatchWorktrees[path]; !ok {
+			m.gitWatchWorktrees[path] = nil
+			added = append(added, path)
+		}
+	}
+	m.gitWatchMu.Unlock()
+
+	if len(added) == 0 {
+		return nil
+	}
+	return func() tea.Msg {
+		for _, path := range added {
+			m.watchWorktree(path)
+		}
+		return nil
+	}
+}
+
+// watchWorktree watches the worktree's root and tracked directories, up to
+// worktreeWatchLimit, and records its git directory, whose index and HEAD
+// the common directory watch already covers.
+func (m *Model) watchWorktree(path string) {
+	dirs := []string{path}
+	raw := m.git.RunGit(m.ctx, []string{"git", "ls-tree", "-r", "-d", "-z", "--name-only", "HEAD"}, path, []int{0}, true, true)
+	for name := range strings.SplitSeq(raw, "\x00") {
+		if len(dirs) >= worktreeWatchLimit() {
+			break
+		}
+		if name != "" {
+			dirs = append(dirs, filepath.Join(path, filepath.FromSlash(name)))
+		}
+	}
+	gitDir := worktreeGitDir(path)
+
+	m.gitWatchMu.Lock()
+	if _, ok := m.gitWatchWorktrees[path]; !ok {
+		// Removed while its directories were listed.
+		m.gitWatchMu.Unlock()
+		return
+	}
+	m.gitWatchWorktrees[path] = dirs
+	for _, dir := range dirs {
+		m.gitWatchOwners[dir] = path
+	}
+	if gitDir != "" {
+		m.gitWatchGitDirs[gitDir] = path
+	}
+	m.gitWatchMu.Unlock()
+
+	for _, dir := range dirs {
+		m.addGitWatchDir(dir)
+	}
+}
+
+// worktreeGitDir returns the git directory of the worktree at path: its .git
+// directory, or the one its .git file points to.
+func worktreeGitDir(path string) string {
+	dotGit := filepath.Join(path, ".git")
+	info, err := os.Stat(dotGit)
+	if err != nil {
+		return ""
+	}
+	if info.IsDir() {
+		return dotGit
+	}
+	// #nosec G304 -- the .git file of a worktree git listed
+	data, err := os.ReadFile(dotGit)
+	if err != nil {
+		return ""
+	}
+	gitDir, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir: ")
+	if !ok {
+		return ""
+	}
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(path, gitDir)
+	}
+	return filepath.Clean(gitDir)
+}
+
+// noteGitWatchEvent records what a watcher event touched. A worktree's files,
+// index or HEAD concern that worktree alone; anything else, such as a ref,
+// calls for a full rescan.
+func (m *Model) noteGitWatchEvent(name string) {
+	dir, base := filepath.Dir(name), filepath.Base(name)
+	m.gitWatchMu.Lock()
+	defer m.gitWatchMu.Unlock()
+
+	path, ok := m.gitWatchOwners[dir]
+	if !ok {
+		switch base {
+		case "index", "index.lock", "HEAD", "HEAD.lock":
+			path, ok = m.gitWatchGitDirs[dir]
+		}
+	}
+	if !ok {
+		m.gitWatchFull = true
+		return
+	}
+	if m.gitWatchChanged == nil {
+		m.gitWatchChanged = make(map[string]struct{})
+	}
+	m.gitWatchChanged[path] = struct{}{}
+}
+
+// takeGitWatchChanges returns and clears what events recorded since the last
+// call.
+func (m *Model) takeGitWatchChanges() (full bool, changed []string) {
+	m.gitWatchMu.Lock()
+	defer m.gitWatchMu.Unlock()
+	full = m.gitWatchFull
+	m.gitWatchFull = false
+	for path := range m.gitWatchChanged {
+		changed = append(changed, path)
+	}
+	m.gitWatchChanged = nil
+	return full, changed
+}
+
+// queueWorktreeWatchRefresh refreshes the given worktrees gitWatchDebounce
+// after the first event, so a save touching several files, or git rewriting
+// the index, costs a single status per worktree.
+func (m *Model) queueWorktreeWatchRefresh(paths []string) tea.Cmd {
+	if len(paths) == 0 {
+		return nil
+	}
+	if m.worktreeWatchPending == nil {
+		m.worktreeWatchPending = make(map[string]struct{})
+	}
+	for _, path := range paths {
+		m.worktreeWatchPending[path] = struct{}{}
+	}
+	if m.worktreeWatchFlushing {
+		return nil
+	}
+	m.worktreeWatchFlushing = true
+	return tea.Tick(gitWatchDebounce, func(time.Time) tea.Msg {
+		return worktreeWatchFlushMsg{}
+	})
+}
+
+// flushWorktreeWatch refreshes the rows, and the info pane when selected, of
+// the worktrees queued by queueWorktreeWatchRefresh.
+func (m *Model) flushWorktreeWatch() tea.Cmd {
+	m.worktreeWatchFlushing = false
+	m.git.InvalidateRemoteCache()
+	cmds := make([]tea.Cmd, 0, len(m.worktreeWatchPending))
+	for path := range m.worktreeWatchPending {
+		cmds = append(cmds, m.requestWorktreeRefresh(path))
+	}
+	m.worktreeWatchPending = nil
+	return tea.Batch(cmds...)
+}
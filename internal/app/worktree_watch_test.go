@@ -0,0 +1,58 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/chmouel/lazyworktree/internal/config"
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+func TestWatchWorktrees(t *testing.T) {
+	repo := initTestRepo(t)
+	if err := os.MkdirAll(filepath.Join(repo.dir, "src"), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(repo.dir, "src", "main.go"), []byte("package main\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, repo.dir, "add", "src")
+	runGit(t, repo.dir, "commit", "-m", "Add src")
+	linked := filepath.Join(t.TempDir(), "feature")
+	runGit(t, repo.dir, "worktree", "add", "-b", "watched", linked)
+	withCwd(t, repo.dir)
+
+	m := NewModel(&config.AppConfig{WorktreeDir: t.TempDir(), AutoRefresh: true}, "")
+	if m.startGitWatcher() == nil {
+		t.Fatal("expected the git watcher to start")
+	}
+	defer m.stopGitWatcher()
+	m.worktrees = []*models.WorktreeInfo{{Path: repo.dir, IsMain: true}, {Path: linked, Branch: "watched"}}
+	m.watchWorktrees()()
+
+	m.noteGitWatchEvent(filepath.Join(repo.dir, "src", "main.go"))
+	m.noteGitWatchEvent(filepath.Join(worktreeGitDir(linked), "index.lock"))
+	full, changed := m.takeGitWatchChanges()
+	if full || len(changed) != 2 {
+		t.Fatalf("expected only the two worktrees refreshed, got full=%v %v", full, changed)
+	}
+	m.noteGitWatchEvent(filepath.Join(m.gitCommonDir, "refs", "heads", "watched"))
+	if full, _ := m.takeGitWatchChanges(); !full {
+		t.Fatal("expected a ref change to rescan every worktree")
+	}
+
+	if m.queueWorktreeWatchRefresh(changed) == nil || m.queueWorktreeWatchRefresh(changed) != nil {
+		t.Fatal("expected a single flush scheduled for a burst of events")
+	}
+	m.flushWorktreeWatch()
+	if m.worktreeWatchFlushing || len(m.worktreeWatchPending) != 0 {
+		t.Fatal("expected the flush to clear the queue")
+	}
+
+	m.worktrees = m.worktrees[:1]
+	m.watchWorktrees()
+	if _, ok := m.gitWatchWorktrees[linked]; ok || m.gitWatchOwners[linked] != "" {
+		t.Fatal("expected the removed worktree no longer watched")
+	}
+}
@@ -0,0 +1,233 @@
+// Package artifacts brings build outputs, such as generated protobuf code
+// or compiled assets, from the main worktree into the others, copied or
+// linked, and remembers what it brought so deleting a worktree cleans them
+// up.
+package artifacts
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/chmouel/lazyworktree/internal/models"
+	"github.com/chmouel/lazyworktree/internal/utils"
+)
+
+// Spec is a build output to bring over, relative to the worktree root.
+type Spec struct {
+	Path string
+	Link bool // Symlink to the main worktree's copy instead of copying it
+}
+
+// Synced records an artifact brought into a worktree.
+type Synced struct {
+	Path     string    `json:"path"`
+	Link     bool      `json:"link,omitempty"`
+	SyncedAt time.Time `json:"synced_at"`
+}
+
+// Result describes a sync.
+type Result struct {
+	Synced  []Synced
+	Missing []string // Not built in the main worktree
+	Skipped []string // Already in the worktree and not brought by a sync
+}
+
+// Manifest maps worktree paths to the artifacts synced into them.
+type Manifest map[string][]Synced
+
+// mu serialises the read-modify-write of Record and Forget, as several
+// worktrees may be synced or deleted at once.
+var mu sync.Mutex
+
+// Path returns where the synced artifacts of the repository named repoKey
+// are listed.
+func Path(repoKey string) string {
+	return filepath.Join(utils.CacheDir(), repoKey, models.ArtifactsFilename)
+}
+
+// Load reads the manifest at path; a missing or damaged file yields an
+// empty one.
+func Load(path string) Manifest {
+	manifest := Manifest{}
+	// #nosec G304 -- path is derived from the cache directory
+	if data, err := os.ReadFile(path); err == nil {
+		if err := json.Unmarshal(data, &manifest); err != nil || manifest == nil {
+			manifest = Manifest{}
+		}
+	}
+	return manifest
+}
+
+// Save writes the manifest to path.
+func (m Manifest) Save(path string) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+// Record lists synced as the artifacts of the worktree at worktreePath.
+func Record(path, worktreePath string, synced []Synced) error {
+	mu.Lock()
+	defer mu.Unlock()
+	manifest := Load(path)
+	if len(synced) == 0 {
+		delete(manifest, worktreePath)
+	} else {
+		manifest[worktreePath] = synced
+	}
+	return manifest.Save(path)
+}
+
+// Forget removes the artifacts listed for the worktree at worktreePath from
+// it and from the manifest at path.
+func Forget(path, worktreePath string) error {
+	mu.Lock()
+	defer mu.Unlock()
+	manifest := Load(path)
+	synced, ok := manifest[worktreePath]
+	if !ok {
+		return nil
+	}
+	err := Remove(worktreePath, synced)
+	delete(manifest, worktreePath)
+	if saveErr := manifest.Save(path); err == nil {
+		err = saveErr
+	}
+	return err
+}
+
+// Remove deletes the given artifacts from the worktree; a link is removed,
+// never what it points to.
+func Remove(worktreePath string, synced []Synced) error {
+	for _, s := range synced {
+		if !filepath.IsLocal(s.Path) {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(worktreePath, s.Path)); err != nil {
+			return fmt.Errorf("failed to remove %s: %w", s.Path, err)
+		}
+	}
+	return nil
+}
+
+// Sync brings specs from mainPath into worktreePath. Artifacts an earlier
+// sync brought, listed in previous, are replaced; anything else already
+// at an artifact's path is left alone, as it may be tracked.
+func Sync(mainPath, worktreePath string, specs []Spec, previous []Synced) (Result, error) {
+	var result Result
+	if mainPath == "" || worktreePath == "" {
+		return result, fmt.Errorf("missing paths for artifact sync")
+	}
+	if filepath.Clean(mainPath) == filepath.Clean(worktreePath) {
+		return result, fmt.Errorf("cannot sync artifacts into the main worktree")
+	}
+	// Artifacts no longer configured go too.
+	if err := Remove(worktreePath, previous); err != nil {
+		return result, err
+	}
+	now := time.Now()
+	for _, spec := range specs {
+		rel := filepath.Clean(filepath.FromSlash(spec.Path))
+		if !filepath.IsLocal(rel) {
+			continue
+		}
+		src := filepath.Join(mainPath, rel)
+		if _, err := os.Stat(src); err != nil {
+			result.Missing = append(result.Missing, spec.Path)
+			continue
+		}
+		dst := filepath.Join(worktreePath, rel)
+		if _, err := os.Lstat(dst); err == nil {
+			result.Skipped = append(result.Skipped, spec.Path)
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(dst), 0o750); err != nil {
+			return result, fmt.Errorf("failed to create directory for %s: %w", spec.Path, err)
+		}
+		var err error
+		if spec.Link {
+			err = os.Symlink(src, dst)
+		} else {
+			err = copyTree(src, dst)
+		}
+		if err != nil {
+			return result, fmt.Errorf("failed to sync %s: %w", spec.Path, err)
+		}
+		result.Synced = append(result.Synced, Synced{Path: spec.Path, Link: spec.Link, SyncedAt: now})
+	}
+	return result, nil
+}
+
+// Paths returns the paths of the synced artifacts.
+func Paths(synced []Synced) []string {
+	paths := make([]string, 0, len(synced))
+	for _, s := range synced {
+		paths = append(paths, s.Path)
+	}
+	slices.Sort(paths)
+	return paths
+}
+
+// copyTree copies the file or directory src to dst, recreating symbolic
+// links rather than following them.
+func copyTree(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		switch {
+		case d.IsDir():
+			return os.MkdirAll(target, info.Mode().Perm()|0o700)
+		case info.Mode()&os.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		case info.Mode().IsRegular():
+			return copyFile(path, target, info.Mode().Perm())
+		default:
+			return nil // Sockets and devices are no build output
+		}
+	})
+}
+
+func copyFile(src, dst string, perm fs.FileMode) error {
+	// #nosec G304 -- src is under an artifact path of the main worktree
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = in.Close() }()
+	// #nosec G304 -- dst mirrors src under the worktree
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,72 @@
+package artifacts
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o750))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+}
+
+func TestSyncAndForget(t *testing.T) {
+	mainPath, wtPath := t.TempDir(), t.TempDir()
+	writeFile(t, filepath.Join(mainPath, "gen", "proto", "api.pb.go"), "package api")
+	writeFile(t, filepath.Join(mainPath, "web", "dist", "app.js"), "app")
+	require.NoError(t, os.Symlink("api.pb.go", filepath.Join(mainPath, "gen", "proto", "latest.go")))
+	writeFile(t, filepath.Join(wtPath, "vendor", "tracked.txt"), "mine")
+	writeFile(t, filepath.Join(mainPath, "vendor", "tracked.txt"), "theirs")
+
+	specs := []Spec{{Path: "gen/proto"}, {Path: "web/dist", Link: true}, {Path: "vendor"}, {Path: "missing"}, {Path: "../outside"}}
+	result, err := Sync(mainPath, wtPath, specs, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"gen/proto", "web/dist"}, Paths(result.Synced))
+	assert.Equal(t, []string{"missing"}, result.Missing)
+	assert.Equal(t, []string{"vendor"}, result.Skipped, "what the worktree already has is left alone")
+
+	data, err := os.ReadFile(filepath.Join(wtPath, "gen", "proto", "api.pb.go"))
+	require.NoError(t, err)
+	assert.Equal(t, "package api", string(data))
+	target, err := os.Readlink(filepath.Join(wtPath, "gen", "proto", "latest.go"))
+	require.NoError(t, err)
+	assert.Equal(t, "api.pb.go", target, "links inside an artifact are recreated")
+	target, err = os.Readlink(filepath.Join(wtPath, "web", "dist"))
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(mainPath, "web", "dist"), target)
+	data, err = os.ReadFile(filepath.Join(wtPath, "vendor", "tracked.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "mine", string(data))
+
+	// Refreshing replaces what the previous sync brought.
+	writeFile(t, filepath.Join(mainPath, "gen", "proto", "api.pb.go"), "package api // v2")
+	result, err = Sync(mainPath, wtPath, specs, result.Synced)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"gen/proto", "web/dist"}, Paths(result.Synced))
+	data, err = os.ReadFile(filepath.Join(wtPath, "gen", "proto", "api.pb.go"))
+	require.NoError(t, err)
+	assert.Equal(t, "package api // v2", string(data))
+
+	manifest := filepath.Join(t.TempDir(), "repo", ".artifacts.json")
+	require.NoError(t, Record(manifest, wtPath, result.Synced))
+	assert.Len(t, Load(manifest)[wtPath], 2)
+
+	require.NoError(t, Forget(manifest, wtPath))
+	assert.Empty(t, Load(manifest))
+	assert.NoDirExists(t, filepath.Join(wtPath, "gen", "proto"))
+	_, err = os.Lstat(filepath.Join(wtPath, "web", "dist"))
+	assert.True(t, os.IsNotExist(err))
+	assert.FileExists(t, filepath.Join(mainPath, "web", "dist", "app.js"), "removing a link keeps its target")
+	assert.FileExists(t, filepath.Join(wtPath, "vendor", "tracked.txt"))
+}
+
+func TestSyncIntoMainWorktree(t *testing.T) {
+	dir := t.TempDir()
+	_, err := Sync(dir, dir, []Spec{{Path: "gen"}}, nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,137 @@
+// Package bootstrap works out, from the files a worktree holds, the commands
+// that set up its language toolchains, such as npm ci or go mod download.
+package bootstrap
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Step is a command that prepares a worktree for one toolchain.
+type Step struct {
+	Tool      string // Program the command runs, such as npm
+	Command   string // Shell command run in the worktree
+	Reason    string // File that called for it
+	Installed bool   // Whether Tool is on the PATH
+}
+
+// rule offers a command when a file exists and the worktree has not been
+// set up already.
+type rule struct {
+	file    string   // File whose presence calls for the command
+	locks   []lock   // Lock files picking a package manager, in order
+	tool    string   // Program used without a lock file
+	command string   // Command used without a lock file
+	done    []string // Files or directories present once set up
+}
+
+// lock picks a command when its file exists next to the rule's.
+type lock struct {
+	file, tool, command string
+}
+
+// rules are checked in order; each yields at most one step.
+var rules = []rule{
+	{
+		file: "package.json",
+		locks: []lock{
+			{"pnpm-lock.yaml", "pnpm", "pnpm install --frozen-lockfile"},
+			{"yarn.lock", "yarn", "yarn install --frozen-lockfile"},
+			{"bun.lock", "bun", "bun install --frozen-lockfile"},
+			{"bun.lockb", "bun", "bun install --frozen-lockfile"},
+			{"package-lock.json", "npm", "npm ci"},
+		},
+		tool: "npm", command: "npm install",
+		done: []string{"node_modules"},
+	},
+	{file: "go.mod", tool: "go", command: "go mod download"},
+	{file: "Cargo.toml", tool: "cargo", command: "cargo fetch"},
+	{
+		file: "pyproject.toml",
+		locks: []lock{
+			{"uv.lock", "uv", "uv sync"},
+			{"poetry.lock", "poetry", "poetry install"},
+		},
+		done: []string{".venv"},
+	},
+	{file: "requirements.txt", tool: "python3", command: "python3 -m venv .venv && .venv/bin/pip install -r requirements.txt", done: []string{".venv"}},
+	{file: "Gemfile", tool: "bundle", command: "bundle install"},
+	{file: "composer.json", tool: "composer", command: "composer install", done: []string{"vendor"}},
+}
+
+// lookPath finds a program; tests replace it.
+var lookPath = exec.LookPath
+
+// Detect returns the steps the worktree at dir calls for. A toolchain
+// already set up, such as one whose node_modules exists, is left out, as
+// is a second step for the same directory of packages.
+func Detect(dir string) []Step {
+	var steps []Step
+	exists := func(name string) bool {
+		_, err := os.Stat(filepath.Join(dir, name))
+		return err == nil
+	}
+	done := map[string]bool{}
+	for _, r := range rules {
+		if !exists(r.file) {
+			continue
+		}
+		tool, command, reason := r.tool, r.command, r.file
+		for _, l := range r.locks {
+			if exists(l.file) {
+				tool, command, reason = l.tool, l.command, l.file
+				break
+			}
+		}
+		if command == "" || hasAny(done, r.done) || anyExists(exists, r.done) {
+			continue
+		}
+		for _, d := range r.done {
+			done[d] = true
+		}
+		_, err := lookPath(tool)
+		steps = append(steps, Step{Tool: tool, Command: command, Reason: reason, Installed: err == nil})
+	}
+	return steps
+}
+
+// Without drops the steps whose command already appears in one of
+// commands, such as the init commands.
+func Without(steps []Step, commands []string) []Step {
+	var kept []Step
+	for _, step := range steps {
+		covered := false
+		for _, command := range commands {
+			if strings.Contains(command, step.Command) {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			kept = append(kept, step)
+		}
+	}
+	return kept
+}
+
+// hasAny reports whether set holds one of names.
+func hasAny(set map[string]bool, names []string) bool {
+	for _, name := range names {
+		if set[name] {
+			return true
+		}
+	}
+	return false
+}
+
+// anyExists reports whether one of names exists.
+func anyExists(exists func(string) bool, names []string) bool {
+	for _, name := range names {
+		if exists(name) {
+			return true
+		}
+	}
+	return false
+}
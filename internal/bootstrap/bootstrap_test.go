@@ -0,0 +1,61 @@
+package bootstrap
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFiles(t *testing.T, dir string, names ...string) {
+	t.Helper()
+	for _, name := range names {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0o750))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), nil, 0o600))
+	}
+}
+
+func TestDetect(t *testing.T) {
+	orig := lookPath
+	lookPath = func(name string) (string, error) {
+		if name == "cargo" {
+			return "", errors.New("not found")
+		}
+		return "/usr/bin/" + name, nil
+	}
+	t.Cleanup(func() { lookPath = orig })
+
+	dir := t.TempDir()
+	assert.Empty(t, Detect(dir))
+
+	writeFiles(t, dir, "package.json", "pnpm-lock.yaml", "package-lock.json", "go.mod", "Cargo.toml", "pyproject.toml", "uv.lock", "requirements.txt")
+	assert.Equal(t, []Step{
+		{Tool: "pnpm", Command: "pnpm install --frozen-lockfile", Reason: "pnpm-lock.yaml", Installed: true},
+		{Tool: "go", Command: "go mod download", Reason: "go.mod", Installed: true},
+		{Tool: "cargo", Command: "cargo fetch", Reason: "Cargo.toml", Installed: false},
+		{Tool: "uv", Command: "uv sync", Reason: "uv.lock", Installed: true},
+	}, Detect(dir), "the lock file picks the package manager and one Python step is enough")
+
+	writeFiles(t, dir, "node_modules/.keep", ".venv/pyvenv.cfg")
+	steps := Detect(dir)
+	require.Len(t, steps, 2, "a toolchain already set up is left out")
+	assert.Equal(t, "go mod download", steps[0].Command)
+}
+
+func TestDetectRequirementsWithoutLock(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, "pyproject.toml", "requirements.txt", "package.json")
+	steps := Detect(dir)
+	require.Len(t, steps, 2)
+	assert.Equal(t, "npm install", steps[0].Command)
+	assert.Equal(t, "requirements.txt", steps[1].Reason)
+}
+
+func TestWithout(t *testing.T) {
+	steps := []Step{{Command: "npm ci"}, {Command: "go mod download"}}
+	assert.Equal(t, []Step{{Command: "go mod download"}}, Without(steps, []string{"link_topsymlinks", "npm ci && npm run build"}))
+	assert.Equal(t, steps, Without(steps, nil))
+}
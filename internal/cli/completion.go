@@ -0,0 +1,40 @@
+package cli
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+)
+
+// WorktreeNames returns the names of the repository's worktrees, as accepted
+// by wt-delete, for shell completion. The main worktree is left out as it
+// cannot be deleted.
+func WorktreeNames(ctx context.Context, gitSvc gitService) []string {
+	raw := gitSvc.RunGit(ctx, []string{"git", "worktree", "list", "--porcelain"}, "", []int{0}, true, true)
+	var names []string
+	isMain := true
+	for line := range strings.SplitSeq(raw, "\n") {
+		path, ok := strings.CutPrefix(line, "worktree ")
+		if !ok {
+			continue
+		}
+		if isMain {
+			isMain = false
+			continue
+		}
+		names = append(names, filepath.Base(path))
+	}
+	return names
+}
+
+// BranchNames returns the repository's local branches for shell completion.
+func BranchNames(ctx context.Context, gitSvc gitService) []string {
+	raw := gitSvc.RunGit(ctx, []string{"git", "for-each-ref", "--format=%(refname:short)", "refs/heads"}, "", []int{0}, true, true)
+	var branches []string
+	for line := range strings.SplitSeq(raw, "\n") {
+		if branch := strings.TrimSpace(line); branch != "" {
+			branches = append(branches, branch)
+		}
+	}
+	return branches
+}
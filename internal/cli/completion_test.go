@@ -0,0 +1,42 @@
+package cli
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestWorktreeNames(t *testing.T) {
+	t.Parallel()
+
+	svc := &fakeGitService{
+		runGitOutput: map[string]string{
+			filepath.Join("git", "worktree", "list", "--porcelain"): "worktree /repo\nHEAD abc\nbranch refs/heads/main\n\n" +
+				"worktree /worktrees/repo/feature\nHEAD def\nbranch refs/heads/feature\n\n" +
+				"worktree /worktrees/repo/detached\nHEAD 123\ndetached\n",
+		},
+	}
+	got := WorktreeNames(context.Background(), svc)
+	if want := []string{"feature", "detached"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+
+	if got := WorktreeNames(context.Background(), &fakeGitService{}); len(got) != 0 {
+		t.Fatalf("expected no names outside a repository, got %v", got)
+	}
+}
+
+func TestBranchNames(t *testing.T) {
+	t.Parallel()
+
+	svc := &fakeGitService{
+		runGitOutput: map[string]string{
+			filepath.Join("git", "for-each-ref", "--format=%(refname:short)", "refs/heads"): "main\nfeature/login\n\n",
+		},
+	}
+	got := BranchNames(context.Background(), svc)
+	if want := []string{"main", "feature/login"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
@@ -5,13 +5,19 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"time"
 
+	"github.com/chmouel/lazyworktree/internal/compose"
 	"github.com/chmouel/lazyworktree/internal/config"
+	"github.com/chmouel/lazyworktree/internal/deleted"
+	"github.com/chmouel/lazyworktree/internal/envtools"
 	"github.com/chmouel/lazyworktree/internal/git"
 	"github.com/chmouel/lazyworktree/internal/models"
+	"github.com/chmouel/lazyworktree/internal/policy"
 	"github.com/chmouel/lazyworktree/internal/security"
+	"github.com/chmouel/lazyworktree/internal/services"
 	"github.com/chmouel/lazyworktree/internal/utils"
 )
 
@@ -25,6 +31,7 @@ type gitService interface {
 	CreateWorktreeFromPR(ctx context.Context, prNumber int, branch string, worktreeName string, targetPath string) bool
 	ExecuteCommands(ctx context.Context, cmdList []string, cwd string, env map[string]string) error
 	FetchAllOpenPRs(ctx context.Context) ([]*models.PRInfo, error)
+	GetMainBranch(ctx context.Context) string
 	GetMainWorktreePath(ctx context.Context) string
 	GetWorktrees(ctx context.Context) ([]*models.WorktreeInfo, error)
 	ResolveRepoName(ctx context.Context) string
@@ -76,13 +83,19 @@ func CreateFromBranch(ctx context.Context, gitSvc gitService, cfg *config.AppCon
 		worktreeName = sanitised
 	}
 
-	targetPath := filepath.Join(cfg.WorktreeDir, repoName, worktreeName)
+	targetPath, err := checkTargetPath(ctx, gitSvc, filepath.Join(cfg.WorktreeDir, repoName, worktreeName), silent)
+	if err != nil {
+		return err
+	}
 
-	// Check for path conflicts
-	if _, err := osStat(targetPath); err == nil {
-		return fmt.Errorf("path already exists: %s", targetPath)
-	} else if !os.IsNotExist(err) {
-		return fmt.Errorf("failed to check path %s: %w", targetPath, err)
+	// A worktree named after its branch checks that branch out rather than
+	// starting a new one, so only the quotas apply.
+	newBranch, base := worktreeName, branchName
+	if worktreeName == branchName {
+		newBranch, base = "", ""
+	}
+	if err := checkPolicy(ctx, gitSvc, cfg, repoName, newBranch, base); err != nil {
+		return err
 	}
 
 	// Create parent directory
@@ -150,9 +163,129 @@ func CreateFromBranch(ctx context.Context, gitSvc gitService, cfg *config.AppCon
 	return nil
 }
 
+// CreateFromBase starts branch from base in a worktree named after it,
+// with the same checks, init commands and trust prompts as the TUI's create
+// dialogue. An empty base uses the main branch.
+func CreateFromBase(ctx context.Context, gitSvc gitService, cfg *config.AppConfig, branch, base string, silent bool) error {
+	newBranch := utils.SanitizeBranchName(strings.TrimSpace(branch), 50)
+	if newBranch == "" {
+		return fmt.Errorf("invalid branch name %q: must contain at least one alphanumeric character", branch)
+	}
+	if base == "" {
+		base = gitSvc.GetMainBranch(ctx)
+	}
+	if !branchExists(ctx, gitSvc, base) {
+		return fmt.Errorf("base %q does not exist", base)
+	}
+	if gitSvc.RunGit(ctx, []string{"git", "show-ref", "refs/heads/" + newBranch}, "", []int{0, 1}, true, true) != "" {
+		return fmt.Errorf("branch %q already exists", newBranch)
+	}
+
+	repoName := gitSvc.ResolveRepoName(ctx)
+	targetPath, err := checkTargetPath(ctx, gitSvc, filepath.Join(cfg.WorktreeDir, repoName, newBranch), silent)
+	if err != nil {
+		return err
+	}
+	if err := checkPolicy(ctx, gitSvc, cfg, repoName, newBranch, base); err != nil {
+		return err
+	}
+	if err := osMkdirAll(filepath.Dir(targetPath), utils.DefaultDirPerms); err != nil {
+		return fmt.Errorf("failed to create worktree directory: %w", err)
+	}
+
+	if !silent {
+		fmt.Fprintf(os.Stderr, "Creating worktree %s from %s at: %s\n", newBranch, base, targetPath)
+	}
+	args := []string{"git", "worktree", "add", "-b", newBranch}
+	if strings.Contains(base, "/") {
+		args = append(args, "--track")
+	}
+	args = append(args, targetPath, base)
+	if !gitSvc.RunCommandChecked(ctx, args, "", fmt.Sprintf("Failed to create worktree %s", newBranch)) {
+		return fmt.Errorf("failed to create worktree %s", newBranch)
+	}
+	if err := runInitCommands(ctx, gitSvc, cfg, newBranch, targetPath, silent); err != nil {
+		gitSvc.RunCommandChecked(ctx, []string{"git", "worktree", "remove", "--force", targetPath}, "", "Failed to cleanup worktree")
+		return err
+	}
+
+	fmt.Println(targetPath)
+	return nil
+}
+
+// checkPolicy refuses a worktree the configured quotas or naming rules
+// forbid. An empty branch or base skips the rule about it.
+func checkPolicy(ctx context.Context, gitSvc gitService, cfg *config.AppConfig, repoName, branch, base string) error {
+	p, err := cfg.Policy()
+	if err != nil {
+		return err
+	}
+	usage := policy.Usage{DiskBytes: -1}
+	if p.MaxWorktrees > 0 {
+		worktrees, err := gitSvc.GetWorktrees(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list worktrees: %w", err)
+		}
+		for _, wt := range worktrees {
+			if !wt.IsMain {
+				usage.Worktrees++
+			}
+		}
+	}
+	if p.NeedsDiskUsage() {
+		size, err := utils.DirSize(filepath.Join(cfg.WorktreeDir, repoName))
+		if err != nil {
+			return fmt.Errorf("failed to measure worktree disk usage: %w", err)
+		}
+		usage.DiskBytes = size
+	}
+	return p.CheckCreate(usage, branch, base)
+}
+
 // generateUniqueWorktreeName generates a unique worktree name with retries.
 // Format: <branch>-<random-adjective>-<random-noun>
 // Retries up to 10 times if path already exists.
+// checkTargetPath refuses a target path that exists, and swaps one the
+// filesystem would refuse, such as a name too long for eCryptfs or
+// Windows, for one that works, telling why unless silent.
+func checkTargetPath(ctx context.Context, gitSvc gitService, targetPath string, silent bool) (string, error) {
+	if err := checkPathFree(targetPath); err != nil {
+		return "", err
+	}
+	var existing []string
+	if worktrees, err := gitSvc.GetWorktrees(ctx); err == nil {
+		for _, wt := range worktrees {
+			existing = append(existing, wt.Path)
+		}
+	}
+	longPaths := runtime.GOOS == "windows" &&
+		gitSvc.RunGit(ctx, []string{"git", "config", "--bool", "core.longpaths"}, "", []int{0, 1}, true, true) == "true"
+	fixed, problem := utils.CheckWorktreePath(targetPath, existing, utils.PathRulesFor(filepath.Dir(targetPath), longPaths))
+	switch {
+	case problem == "":
+		return targetPath, nil
+	case fixed == "":
+		return "", fmt.Errorf("cannot create %s: %s", targetPath, problem)
+	}
+	if err := checkPathFree(fixed); err != nil {
+		return "", err
+	}
+	if !silent {
+		fmt.Fprintf(os.Stderr, "Using %s instead, as %s\n", fixed, problem)
+	}
+	return fixed, nil
+}
+
+// checkPathFree fails when something already exists at path.
+func checkPathFree(path string) error {
+	if _, err := osStat(path); err == nil {
+		return fmt.Errorf("path already exists: %s", path)
+	} else if !os.IsNotExist(err) {
+		return fmt.Errorf("failed to check path %s: %w", path, err)
+	}
+	return nil
+}
+
 func generateUniqueWorktreeName(cfg *config.AppConfig, repoName, branchName string) string {
 	const maxRetries = 10
 
@@ -206,13 +339,14 @@ func CreateFromPR(ctx context.Context, gitSvc gitService, cfg *config.AppConfig,
 
 	// Construct target path
 	repoName := gitSvc.ResolveRepoName(ctx)
-	targetPath := filepath.Join(cfg.WorktreeDir, repoName, branchName)
+	targetPath, err := checkTargetPath(ctx, gitSvc, filepath.Join(cfg.WorktreeDir, repoName, branchName), silent)
+	if err != nil {
+		return err
+	}
 
-	// Check for path conflicts
-	if _, err := osStat(targetPath); err == nil {
-		return fmt.Errorf("path already exists: %s", targetPath)
-	} else if !os.IsNotExist(err) {
-		return fmt.Errorf("failed to check path %s: %w", targetPath, err)
+	// The PR branch already exists, so only the quotas apply.
+	if err := checkPolicy(ctx, gitSvc, cfg, repoName, "", ""); err != nil {
+		return err
 	}
 
 	// Create parent directory
@@ -281,7 +415,13 @@ func DeleteWorktree(ctx context.Context, gitSvc gitService, cfg *config.AppConfi
 		}
 	}
 
+	// Stop the services lazyworktree started in it
+	if err := services.StopAll(services.Path(gitSvc.ResolveRepoName(ctx)), selectedWorktree.Path); err != nil && !silent {
+		fmt.Fprintf(os.Stderr, "Warning: stopping services failed: %v\n", err)
+	}
+
 	// Delete worktree
+	head := gitSvc.RunGit(ctx, []string{"git", "rev-parse", "HEAD"}, selectedWorktree.Path, []int{0}, true, true)
 	if !gitSvc.RunCommandChecked(
 		ctx,
 		[]string{"git", "worktree", "remove", "--force", selectedWorktree.Path},
@@ -290,6 +430,7 @@ func DeleteWorktree(ctx context.Context, gitSvc gitService, cfg *config.AppConfi
 	) {
 		return fmt.Errorf("failed to remove worktree")
 	}
+	recordDeleted(ctx, gitSvc, cfg, selectedWorktree, head)
 
 	// Delete branch only if worktree name matches branch name (unless --no-branch was specified)
 	if deleteBranch {
@@ -311,6 +452,26 @@ func DeleteWorktree(ctx context.Context, gitSvc gitService, cfg *config.AppConfi
 	return nil
 }
 
+// recordDeleted remembers a deleted worktree for the TUI's recently deleted
+// list, keeping them for recently_deleted_days.
+func recordDeleted(ctx context.Context, gitSvc gitService, cfg *config.AppConfig, wt *models.WorktreeInfo, head string) {
+	if cfg.RecentlyDeletedDays <= 0 {
+		return
+	}
+	if head == "" {
+		head = wt.Head
+	}
+	if head == "" {
+		return
+	}
+	entry := deleted.Entry{Path: wt.Path, Branch: wt.Branch, Commit: head}
+	if wt.Detached {
+		entry.Branch = ""
+	}
+	keep := time.Duration(cfg.RecentlyDeletedDays) * 24 * time.Hour
+	_ = deleted.Record(deleted.Path(gitSvc.ResolveRepoName(ctx)), entry, keep)
+}
+
 // findWorktreeByPathOrName finds a worktree by its path or name.
 func findWorktreeByPathOrName(pathOrName string, worktrees []*models.WorktreeInfo, worktreeDir, repoName string) (*models.WorktreeInfo, error) {
 	// Try to match by exact path
@@ -347,13 +508,17 @@ func findWorktreeByPathOrName(pathOrName string, worktrees []*models.WorktreeInf
 
 // runInitCommands runs init commands with TOFU trust checks.
 func runInitCommands(ctx context.Context, gitSvc gitService, cfg *config.AppConfig, branch, wtPath string, silent bool) error {
+	if cfg.AllowEnvTools {
+		allowEnvTools(ctx, wtPath, silent)
+	}
+
 	// Collect init commands from global and repo config
 	commands := make([]string, 0)
 	commands = append(commands, cfg.InitCommands...)
 
 	// Load repo config from main worktree
 	mainPath := gitSvc.GetMainWorktreePath(ctx)
-	repoConfig, wtFilePath, err := config.LoadRepoConfig(mainPath)
+	repoConfig, _, err := config.LoadRepoConfig(mainPath)
 	if err != nil {
 		return fmt.Errorf("failed to load repo config: %w", err)
 	}
@@ -368,14 +533,17 @@ func runInitCommands(ctx context.Context, gitSvc gitService, cfg *config.AppConf
 
 	// Check trust for .wt file commands
 	if repoConfig != nil && len(repoConfig.InitCommands) > 0 {
-		if err := checkTrust(ctx, cfg, wtFilePath); err != nil {
-			return err
+		for _, path := range repoConfig.TrustPaths() {
+			if err := checkTrust(ctx, cfg, path); err != nil {
+				return err
+			}
 		}
 	}
 
 	// Build environment
 	repoName := gitSvc.ResolveRepoName(ctx)
 	env := buildCommandEnv(branch, wtPath, mainPath, repoName)
+	addComposeEnv(cfg, env, branch, wtPath, repoName)
 
 	// Run commands
 	if !silent {
@@ -396,7 +564,7 @@ func runTerminateCommands(ctx context.Context, gitSvc gitService, cfg *config.Ap
 
 	// Load repo config
 	mainPath := gitSvc.GetMainWorktreePath(ctx)
-	repoConfig, wtFilePath, err := config.LoadRepoConfig(mainPath)
+	repoConfig, _, err := config.LoadRepoConfig(mainPath)
 	if err != nil {
 		// Don't fail if repo config can't be loaded during deletion
 		fmt.Fprintf(os.Stderr, "Warning: failed to load repo config: %v\n", err)
@@ -410,14 +578,17 @@ func runTerminateCommands(ctx context.Context, gitSvc gitService, cfg *config.Ap
 
 	// Check trust for .wt file commands
 	if repoConfig != nil && len(repoConfig.TerminateCommands) > 0 {
-		if err := checkTrust(ctx, cfg, wtFilePath); err != nil {
-			return err
+		for _, path := range repoConfig.TrustPaths() {
+			if err := checkTrust(ctx, cfg, path); err != nil {
+				return err
+			}
 		}
 	}
 
 	// Build environment
 	repoName := gitSvc.ResolveRepoName(ctx)
 	env := buildCommandEnv(branch, wtPath, mainPath, repoName)
+	addComposeEnv(cfg, env, branch, wtPath, repoName)
 
 	// Run commands
 	if !silent {
@@ -468,6 +639,27 @@ func buildCommandEnv(branch, wtPath, mainPath, repoName string) map[string]strin
 	}
 }
 
+// allowEnvTools runs direnv allow and mise trust in the new worktree, only
+// warning when they fail.
+func allowEnvTools(ctx context.Context, wtPath string, silent bool) {
+	for _, tool := range envtools.Detect(wtPath) {
+		if !silent {
+			fmt.Fprintf(os.Stderr, "Approving %s...\n", tool.Name)
+		}
+		if err := envtools.Allow(ctx, wtPath, tool); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+	}
+}
+
+// addComposeEnv names the worktree's Docker Compose project in env when
+// docker_compose is on.
+func addComposeEnv(cfg *config.AppConfig, env map[string]string, branch, wtPath, repoName string) {
+	if cfg.DockerCompose {
+		env["COMPOSE_PROJECT_NAME"] = compose.ProjectName(cfg.ComposeProjectTemplate, repoName, filepath.Base(wtPath), branch)
+	}
+}
+
 // branchExists checks if a branch exists.
 func branchExists(ctx context.Context, gitSvc gitService, branch string) bool {
 	// Try to verify the branch exists
@@ -44,6 +44,10 @@ func (f *fakeGitService) FetchAllOpenPRs(_ context.Context) ([]*models.PRInfo, e
 	return f.prs, f.prsErr
 }
 
+func (f *fakeGitService) GetMainBranch(_ context.Context) string {
+	return "main"
+}
+
 func (f *fakeGitService) GetMainWorktreePath(_ context.Context) string {
 	return f.mainWorktreePath
 }
@@ -176,6 +180,23 @@ func TestBuildCommandEnv(t *testing.T) {
 	}
 }
 
+func TestAddComposeEnv(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.DefaultConfig()
+	env := buildCommandEnv("feature", "/wt/feature", "/main", "repo")
+	addComposeEnv(cfg, env, "feature", "/wt/feature", "repo")
+	if _, ok := env["COMPOSE_PROJECT_NAME"]; ok {
+		t.Fatal("expected no project name while docker_compose is off")
+	}
+
+	cfg.DockerCompose = true
+	addComposeEnv(cfg, env, "feature", "/wt/feature", "repo")
+	if got := env["COMPOSE_PROJECT_NAME"]; got != "repo-feature" {
+		t.Fatalf("unexpected project name %q", got)
+	}
+}
+
 func TestCheckTrust(t *testing.T) {
 	t.Parallel()
 
@@ -505,6 +526,29 @@ func TestCreateFromBranch(t *testing.T) {
 		}
 	})
 
+	t.Run("registered worktree path gets numbered", func(t *testing.T) {
+		sourceBranch := "main"
+		// Registered with git, but its directory is gone.
+		registered := filepath.Join(tmpDir, testRepoName, "feature-2")
+		svc := &fakeGitService{
+			resolveRepoName:     testRepoName,
+			mainWorktreePath:    filepath.Join(tmpDir, "main"),
+			runCommandCheckedOK: true,
+			worktrees:           []*models.WorktreeInfo{{Path: registered}},
+			runGitOutput: map[string]string{
+				filepath.Join("git", "rev-parse", "--verify", sourceBranch):              "abc123\n",
+				filepath.Join("git", "show-ref", "--verify", "refs/heads/"+sourceBranch): "abc123\n",
+			},
+		}
+
+		if err := CreateFromBranch(ctx, svc, cfg, sourceBranch, "feature-2", false, true); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if expected := registered + "-2"; svc.lastWorktreeAddPath != expected {
+			t.Errorf("expected path %q, got %q", expected, svc.lastWorktreeAddPath)
+		}
+	})
+
 	t.Run("invalid branch name all special chars", func(t *testing.T) {
 		repoName := testRepoName
 		sourceBranch := "main"
@@ -525,6 +569,93 @@ func TestCreateFromBranch(t *testing.T) {
 			t.Errorf("expected 'invalid worktree name' error, got: %v", err)
 		}
 	})
+
+	t.Run("policy refuses the worktree", func(t *testing.T) {
+		policyCfg := &config.AppConfig{
+			WorktreeDir:        tmpDir,
+			MaxWorktrees:       1,
+			BranchNamePattern:  "feature-.+",
+			BannedBaseBranches: []string{"production"},
+		}
+		svc := &fakeGitService{
+			resolveRepoName:     testRepoName,
+			runCommandCheckedOK: true,
+			worktrees:           []*models.WorktreeInfo{{Path: tmpDir, IsMain: true}},
+			runGitOutput: map[string]string{
+				filepath.Join("git", "rev-parse", "--verify", "main"):       "abc123\n",
+				filepath.Join("git", "rev-parse", "--verify", "production"): "abc123\n",
+			},
+		}
+
+		err := CreateFromBranch(ctx, svc, policyCfg, "main", "login", false, true)
+		if err == nil || !contains(err.Error(), "branch_name_pattern") {
+			t.Fatalf("expected branch_name_pattern error, got: %v", err)
+		}
+		err = CreateFromBranch(ctx, svc, policyCfg, "production", "feature-login", false, true)
+		if err == nil || !contains(err.Error(), "banned_base_branches") {
+			t.Fatalf("expected banned_base_branches error, got: %v", err)
+		}
+
+		svc.worktrees = append(svc.worktrees, &models.WorktreeInfo{Path: filepath.Join(tmpDir, "other")})
+		err = CreateFromBranch(ctx, svc, policyCfg, "main", "feature-login", false, true)
+		if err == nil || !contains(err.Error(), "max_worktrees") {
+			t.Fatalf("expected max_worktrees error, got: %v", err)
+		}
+		if svc.lastWorktreeAddPath != "" {
+			t.Errorf("expected no worktree to be added, got %q", svc.lastWorktreeAddPath)
+		}
+	})
+}
+
+func TestCreateFromBase(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	tmpDir := t.TempDir()
+	cfg := &config.AppConfig{WorktreeDir: tmpDir}
+	newSvc := func() *fakeGitService {
+		return &fakeGitService{
+			resolveRepoName:     testRepoName,
+			mainWorktreePath:    filepath.Join(tmpDir, "main"),
+			runCommandCheckedOK: true,
+			runGitOutput: map[string]string{
+				filepath.Join("git", "rev-parse", "--verify", "main"):        "abc123\n",
+				filepath.Join("git", "rev-parse", "--verify", "origin/dev"):  "def456\n",
+				filepath.Join("git", "show-ref", "refs/heads/taken"):         "abc123 refs/heads/taken\n",
+				filepath.Join("git", "rev-parse", "--verify", "no-such-ref"): "",
+			},
+		}
+	}
+
+	svc := newSvc()
+	if err := CreateFromBase(ctx, svc, cfg, "Login Page", "", true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := filepath.Join(tmpDir, testRepoName, "login-page"); svc.lastWorktreeAddPath != want || svc.lastWorktreeAddBranch != "login-page" {
+		t.Errorf("expected login-page at %q from main, got %q at %q", want, svc.lastWorktreeAddBranch, svc.lastWorktreeAddPath)
+	}
+
+	svc = newSvc()
+	if err := CreateFromBase(ctx, svc, cfg, "hotfix", "origin/dev", true); err != nil {
+		t.Fatalf("unexpected error from a remote base: %v", err)
+	}
+
+	for _, tc := range []struct {
+		branch, base, want string
+	}{
+		{branch: "taken", want: `branch "taken" already exists`},
+		{branch: "other", base: "no-such-ref", want: `base "no-such-ref" does not exist`},
+		{branch: "!!!", want: "invalid branch name"},
+	} {
+		svc = newSvc()
+		err := CreateFromBase(ctx, svc, cfg, tc.branch, tc.base, true)
+		if err == nil || !contains(err.Error(), tc.want) {
+			t.Errorf("%s from %q: expected %q, got %v", tc.branch, tc.base, tc.want, err)
+		}
+		if svc.lastWorktreeAddPath != "" {
+			t.Errorf("%s: expected no worktree to be added, got %q", tc.branch, svc.lastWorktreeAddPath)
+		}
+	}
 }
 
 func TestDeleteWorktree(t *testing.T) {
@@ -0,0 +1,200 @@
+// Package codeowners reads CODEOWNERS files and works out who owns a set of
+// changed files, following GitHub's rules and GitLab's sections.
+package codeowners
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Locations are the paths, relative to the repository root, searched for a
+// CODEOWNERS file, in the order GitHub and GitLab look.
+var Locations = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS", ".gitlab/CODEOWNERS"}
+
+// Rule assigns owners to the files matching a pattern.
+type Rule struct {
+	Pattern string
+	Owners  []string
+	section int
+	re      *regexp.Regexp
+}
+
+// File is a parsed CODEOWNERS file.
+type File struct {
+	// Path is relative to the repository root.
+	Path  string
+	Rules []Rule
+}
+
+// Owner lists the changed files a single owner is responsible for.
+type Owner struct {
+	Name  string
+	Files []string
+}
+
+// Summary groups changed files by owner, most files first.
+type Summary struct {
+	Owners []Owner
+	// Unowned holds the files no rule assigns an owner to.
+	Unowned []string
+}
+
+// Load reads the first CODEOWNERS file found under root, returning nil when
+// there is none.
+func Load(root string) (*File, error) {
+	for _, location := range Locations {
+		data, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(location)))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return &File{Path: location, Rules: Parse(string(data))}, nil
+	}
+	return nil, nil
+}
+
+var sectionHeader = regexp.MustCompile(`^\^?\[([^\]]+)\](?:\[\d+\])?\s*(.*)$`)
+
+// Parse reads CODEOWNERS rules. GitLab section headers, such as
+// "[Docs] @docs-team", start a section whose owners apply to its rules that
+// name none.
+func Parse(data string) []Rule {
+	var rules []Rule
+	section := 0
+	var defaults []string
+	for line := range strings.SplitSeq(data, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if m := sectionHeader.FindStringSubmatch(line); m != nil {
+			section++
+			defaults = ownerFields(m[2])
+			continue
+		}
+		fields := strings.Fields(line)
+		owners := ownerFields(strings.Join(fields[1:], " "))
+		if len(owners) == 0 && len(fields) == 1 {
+			owners = defaults
+		}
+		re, err := compile(fields[0])
+		if err != nil {
+			continue
+		}
+		rules = append(rules, Rule{Pattern: fields[0], Owners: owners, section: section, re: re})
+	}
+	return rules
+}
+
+// ownerFields splits owners, stopping at a trailing comment.
+func ownerFields(s string) []string {
+	var owners []string
+	for _, field := range strings.Fields(s) {
+		if strings.HasPrefix(field, "#") {
+			break
+		}
+		owners = append(owners, field)
+	}
+	return owners
+}
+
+// compile turns a gitignore-style pattern into an expression matching
+// slash-separated paths relative to the root.
+func compile(pattern string) (*regexp.Regexp, error) {
+	dirOnly := strings.HasSuffix(pattern, "/")
+	pattern = strings.TrimSuffix(pattern, "/")
+	anchored := strings.HasPrefix(pattern, "/") || strings.Contains(pattern, "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+
+	var expr strings.Builder
+	if anchored {
+		expr.WriteString("^")
+	} else {
+		expr.WriteString("^(?:.*/)?")
+	}
+	for i := 0; i < len(pattern); i++ {
+		switch {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			expr.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			expr.WriteString(".*")
+			i++
+		case pattern[i] == '*':
+			expr.WriteString("[^/]*")
+		case pattern[i] == '?':
+			expr.WriteString("[^/]")
+		default:
+			expr.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+	if dirOnly {
+		expr.WriteString("/.*$")
+	} else {
+		expr.WriteString("(?:/.*)?$")
+	}
+	return regexp.Compile(expr.String())
+}
+
+// Owners returns who owns file, a slash-separated path relative to the
+// root. Within a section the last matching rule wins; GitLab sections each
+// add their owners.
+func (f *File) Owners(file string) []string {
+	if f == nil {
+		return nil
+	}
+	last := make(map[int][]string)
+	var sections []int
+	for _, rule := range f.Rules {
+		if !rule.re.MatchString(file) {
+			continue
+		}
+		if _, seen := last[rule.section]; !seen {
+			sections = append(sections, rule.section)
+		}
+		last[rule.section] = rule.Owners
+	}
+	var owners []string
+	seen := make(map[string]bool)
+	for _, section := range sections {
+		for _, owner := range last[section] {
+			if !seen[owner] {
+				seen[owner] = true
+				owners = append(owners, owner)
+			}
+		}
+	}
+	return owners
+}
+
+// Summarise groups files by owner.
+func (f *File) Summarise(files []string) Summary {
+	var summary Summary
+	byOwner := make(map[string][]string)
+	for _, file := range files {
+		owners := f.Owners(file)
+		if len(owners) == 0 {
+			summary.Unowned = append(summary.Unowned, file)
+			continue
+		}
+		for _, owner := range owners {
+			byOwner[owner] = append(byOwner[owner], file)
+		}
+	}
+	for name, owned := range byOwner {
+		summary.Owners = append(summary.Owners, Owner{Name: name, Files: owned})
+	}
+	sort.Slice(summary.Owners, func(i, j int) bool {
+		a, b := summary.Owners[i], summary.Owners[j]
+		if len(a.Files) != len(b.Files) {
+			return len(a.Files) > len(b.Files)
+		}
+		return a.Name < b.Name
+	})
+	return summary
+}
@@ -0,0 +1,81 @@
+package codeowners
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOwners(t *testing.T) {
+	f := &File{Rules: Parse(`
+# Default owners
+*                 @org/core
+*.js              @org/web   # front end
+/docs/            @org/docs
+apps/             @alice
+/build/logs/      @bob
+src/**/generated  @carol
+/vendor/
+`)}
+
+	for file, want := range map[string][]string{
+		"main.go":                     {"@org/core"},
+		"web/index.js":                {"@org/web"},
+		"docs/guide.md":               {"@org/docs"},
+		"docs/api/intro.md":           {"@org/docs"},
+		"lib/docs/notes.md":           {"@org/core"},
+		"apps/cli/main.go":            {"@alice"},
+		"services/apps/api/main.go":   {"@alice"},
+		"build/logs/out.txt":          {"@bob"},
+		"other/build/logs/out.txt":    {"@org/core"},
+		"src/a/b/generated/schema.go": {"@carol"},
+		"src/generated/schema.go":     {"@carol"},
+		"vendor/github.com/x/x.go":    nil,
+	} {
+		assert.Equal(t, want, f.Owners(file), file)
+	}
+	assert.Nil(t, (*File)(nil).Owners("main.go"))
+}
+
+func TestOwnersGitLabSections(t *testing.T) {
+	f := &File{Rules: Parse(`
+* @admins
+[Docs] @docs-team
+*.md
+/api/*.md @api-writers
+^[Security][2]
+auth/ @sec
+`)}
+	assert.Equal(t, []string{"@admins", "@docs-team"}, f.Owners("README.md"))
+	assert.Equal(t, []string{"@admins", "@api-writers"}, f.Owners("api/v1.md"))
+	assert.Equal(t, []string{"@admins", "@sec"}, f.Owners("pkg/auth/login.go"))
+}
+
+func TestSummarise(t *testing.T) {
+	f := &File{Rules: Parse("web/ @org/web @alice\napi/ @org/api\n")}
+	summary := f.Summarise([]string{"web/a.js", "api/main.go", "web/b.js", "README.md"})
+	assert.Equal(t, []Owner{
+		{Name: "@alice", Files: []string{"web/a.js", "web/b.js"}},
+		{Name: "@org/web", Files: []string{"web/a.js", "web/b.js"}},
+		{Name: "@org/api", Files: []string{"api/main.go"}},
+	}, summary.Owners)
+	assert.Equal(t, []string{"README.md"}, summary.Unowned)
+}
+
+func TestLoad(t *testing.T) {
+	root := t.TempDir()
+	f, err := Load(root)
+	require.NoError(t, err)
+	assert.Nil(t, f)
+
+	require.NoError(t, os.WriteFile(filepath.Join(root, "CODEOWNERS"), []byte("* @root\n"), 0o600))
+	require.NoError(t, os.MkdirAll(filepath.Join(root, ".github"), 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(root, ".github", "CODEOWNERS"), []byte("* @github\n"), 0o600))
+	f, err = Load(root)
+	require.NoError(t, err)
+	assert.Equal(t, ".github/CODEOWNERS", f.Path)
+	assert.Equal(t, []string{"@github"}, f.Owners("main.go"))
+}
@@ -0,0 +1,123 @@
+// Package compose gives each worktree a Docker Compose project of its own,
+// so that parallel branches run isolated container stacks.
+package compose
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// DefaultProjectTemplate names a worktree's project after the repository
+// and the worktree.
+const DefaultProjectTemplate = "{repo}-{worktree}"
+
+// Files are the names Docker Compose looks for, in its order.
+var Files = []string{"compose.yaml", "compose.yml", "docker-compose.yaml", "docker-compose.yml"}
+
+// ProjectName fills in the {repo}, {worktree} and {branch} placeholders of
+// template and makes the result a valid project name: lowercase letters,
+// digits, dashes and underscores, starting with a letter or digit.
+func ProjectName(template, repo, worktree, branch string) string {
+	if strings.TrimSpace(template) == "" {
+		template = DefaultProjectTemplate
+	}
+	name := strings.NewReplacer("{repo}", repo, "{worktree}", worktree, "{branch}", branch).Replace(template)
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '_', r == '-':
+			b.WriteRune(r)
+		default:
+			b.WriteRune('-')
+		}
+	}
+	return strings.TrimLeft(b.String(), "-_")
+}
+
+// HasFile reports whether dir holds a Compose file.
+func HasFile(dir string) bool {
+	for _, name := range Files {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// Available reports whether the docker command is installed.
+func Available() bool {
+	_, err := exec.LookPath("docker")
+	return err == nil
+}
+
+// Projects returns the status, such as "running(2)", of each Compose
+// project Docker knows, stopped ones included, by name.
+func Projects(ctx context.Context) (map[string]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, "docker", "compose", "ls", "--all", "--format", "json").Output()
+	if err != nil {
+		return nil, commandError(err)
+	}
+	return parseProjects(out)
+}
+
+// parseProjects reads the output of docker compose ls --format json.
+func parseProjects(out []byte) (map[string]string, error) {
+	var projects []struct {
+		Name   string `json:"Name"`
+		Status string `json:"Status"`
+	}
+	if len(bytes.TrimSpace(out)) > 0 {
+		if err := json.Unmarshal(out, &projects); err != nil {
+			return nil, fmt.Errorf("reading docker compose ls: %w", err)
+		}
+	}
+	statuses := make(map[string]string, len(projects))
+	for _, p := range projects {
+		statuses[p.Name] = p.Status
+	}
+	return statuses, nil
+}
+
+// Running reports whether a status from Projects has containers running.
+func Running(status string) bool {
+	return strings.Contains(status, "running")
+}
+
+// Run runs docker compose with args for the project in dir, with env added
+// to the environment, returning its output.
+func Run(ctx context.Context, dir, project string, env map[string]string, args ...string) (string, error) {
+	// #nosec G204 -- the project name is sanitised and the arguments are fixed subcommands
+	cmd := exec.CommandContext(ctx, "docker", append([]string{"compose", "-p", project}, args...)...)
+	cmd.Dir = dir
+	cmd.Env = os.Environ()
+	for k, v := range env {
+		cmd.Env = append(cmd.Env, k+"="+v)
+	}
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return string(out), fmt.Errorf("docker compose %s: %s", args[0], msg)
+		}
+		return string(out), fmt.Errorf("docker compose %s: %w", args[0], err)
+	}
+	return string(out), nil
+}
+
+// commandError includes what docker printed on stderr in err.
+func commandError(err error) error {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && len(bytes.TrimSpace(exitErr.Stderr)) > 0 {
+		return fmt.Errorf("%s", strings.TrimSpace(string(exitErr.Stderr)))
+	}
+	return err
+}
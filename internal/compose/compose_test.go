@@ -0,0 +1,38 @@
+package compose
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectName(t *testing.T) {
+	assert.Equal(t, "myrepo-feature-x", ProjectName("", "MyRepo", "feature-x", "feature/x"))
+	assert.Equal(t, "myrepo_feature-x", ProjectName("{repo}_{branch}", "myrepo", "wt", "feature/x"))
+	assert.Equal(t, "app-1", ProjectName("_.{worktree}", "repo", "App 1", ""))
+}
+
+func TestParseProjects(t *testing.T) {
+	out := `[{"Name":"repo-a","Status":"running(2)","ConfigFiles":"/wt/a/compose.yaml"},{"Name":"repo-b","Status":"exited(1)","ConfigFiles":"/wt/b/compose.yaml"}]`
+	statuses, err := parseProjects([]byte(out))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"repo-a": "running(2)", "repo-b": "exited(1)"}, statuses)
+	assert.True(t, Running(statuses["repo-a"]))
+	assert.False(t, Running(statuses["repo-b"]))
+
+	statuses, err = parseProjects([]byte("\n"))
+	require.NoError(t, err)
+	assert.Empty(t, statuses)
+	_, err = parseProjects([]byte("not json"))
+	assert.Error(t, err)
+}
+
+func TestHasFile(t *testing.T) {
+	dir := t.TempDir()
+	assert.False(t, HasFile(dir))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "docker-compose.yml"), []byte("services: {}\n"), 0o600))
+	assert.True(t, HasFile(dir))
+}
@@ -6,15 +6,23 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/chmouel/lazyworktree/internal/maintenance"
+	"github.com/chmouel/lazyworktree/internal/policy"
 	"github.com/chmouel/lazyworktree/internal/theme"
 	"github.com/chmouel/lazyworktree/internal/utils"
 	"gopkg.in/yaml.v3"
 )
 
+var (
+	hexColorRe  = regexp.MustCompile(`^[0-9A-Fa-f]+$`)
+	themeLineRe = regexp.MustCompile(`(?m)^theme:\s*.*$`)
+)
+
 // CustomCommand represents a user-defined command binding.
 type CustomCommand struct {
 	Command     string
@@ -52,6 +60,40 @@ type CustomCreateMenu struct {
 	PostInteractive bool   // Run post-command interactively (default: false)
 }
 
+// HealthCheck is a local command, such as a linter or the test suite, whose
+// latest result per worktree is shown in the health matrix.
+type HealthCheck struct {
+	Name    string // Column heading in the matrix
+	Command string // Shell command run in the worktree; exit status 0 passes
+}
+
+// ArtifactSync is a build output, such as generated code or compiled
+// assets, brought from the main worktree into new worktrees.
+type ArtifactSync struct {
+	Path string // Relative to the worktree root
+	Link bool   // Symlink to the main worktree's copy instead of copying it
+}
+
+// Macro is a recorded sequence of keys, bound to a key of its own and
+// replayed against the current selection.
+type Macro struct {
+	Name string   // Shown in the palette and the status line
+	Keys []string // Keys as Bubble Tea names them; "type:<text>" types text
+}
+
+// Service is a long-running command, such as a dev server, that can be
+// started and stopped in each worktree.
+type Service struct {
+	Name    string
+	Command string // Shell command run in the worktree until stopped
+}
+
+// PortRange is an inclusive range of TCP ports the services listen on.
+type PortRange struct {
+	First int
+	Last  int
+}
+
 // CustomTheme represents a user-defined theme that can inherit from built-in or other custom themes.
 type CustomTheme struct {
 	Base       string // Optional base theme name (built-in or custom)
@@ -76,9 +118,11 @@ type AppConfig struct {
 	WorktreeDir             string
 	InitCommands            []string
 	TerminateCommands       []string
-	SortMode                string // Sort mode: "path", "active" (commit date), "switched" (last accessed)
+	SortMode                string // Sort mode: "path", "active" (commit date), "switched" (last accessed), "manual"
+	DateFormat              string // Dates: "relative" (default when empty), "iso" or a Go time layout such as "02 Jan 2006 15:04"
 	AutoFetchPRs            bool
 	SearchAutoSelect        bool // Start with filter focused and select first match on Enter.
+	VimMotions              bool // Count prefixes, gg and M in the panes, in place of 1-3, g and M (default: false)
 	MaxUntrackedDiffs       int
 	MaxDiffChars            int
 	MaxNameLength           int // Maximum length for worktree names in table display (0 disables truncation)
@@ -92,8 +136,12 @@ type AppConfig struct {
 	Editor                  string
 	AutoRefresh             bool
 	RefreshIntervalSeconds  int
+	GitConcurrency          int // Concurrent local git commands (0 uses twice the CPU count, capped at 32)
+	NetworkConcurrency      int // Concurrent gh/glab calls and fetches (0 uses 4)
+	CommandConcurrency      int // Concurrent user commands and scripts (0 uses 4)
 	CustomCommands          map[string]*CustomCommand
 	BranchNameScript        string // Script to generate branch name suggestions from diff
+	BranchNameScriptTimeout int    // Seconds branch_name_script may run before the suggestion falls back (default: 30)
 	Theme                   string // Theme name: see AvailableThemes in internal/theme
 	MergeMethod             string // Merge method for absorb: "rebase" or "merge" (default: "rebase")
 	FuzzyFinderInput        bool   // Enable fuzzy finder for input suggestions (default: false)
@@ -101,9 +149,58 @@ type AppConfig struct {
 	IssueBranchNameTemplate string // Template for issue branch names with placeholders: {number}, {title} (default: "issue-{number}-{title}")
 	PRBranchNameTemplate    string // Template for PR branch names with placeholders: {number}, {title} (default: "pr-{number}-{title}")
 	SessionPrefix           string // Prefix for tmux/zellij session names (default: "wt-")
+	OwnerIdentity           string // Name recorded as the creator of new worktrees (default: the OS user)
 	PaletteMRU              bool   // Enable MRU sorting for command palette (default: false)
 	PaletteMRULimit         int    // Number of MRU items to show (default: 5)
 	CustomCreateMenus       []*CustomCreateMenu
+	PRReviewers             []string                // Usernames always offered when requesting PR/MR reviewers
+	ShowDeployments         bool                    // Show the Deploy column from GitHub Deployments (default: false)
+	ShowDiffStats           bool                    // Show the ±Lines column of lines changed vs main and uncommitted (default: false)
+	DeploymentScript        string                  // Script reporting a worktree's preview deployment
+	CommitLint              bool                    // Warn about branch commits that break the commit convention (default: false)
+	CommitTypes             []string                // Accepted Conventional Commit types (default: the standard set)
+	InfoTemplate            string                  // Go template replacing the info pane content
+	OverviewCommand         string                  // Command whose output replaces the README preview
+	DivergenceRef           string                  // Remote-tracking ref ahead/behind count against instead of each upstream
+	FetchProtocolV2         bool                    // Fetch with protocol.version=2 (default: false, git's own default)
+	FetchNegotiateWorktrees bool                    // Offer only worktree branches as fetch negotiation tips (default: false)
+	FetchWorktreesOnly      bool                    // Fetch only the upstreams of worktree branches and main with R (default: false)
+	FetchPrune              bool                    // Prune deleted remote branches on every fetch (default: false)
+	FetchDepth              int                     // Limit fetched history to this many commits (0 = full history)
+	FetchFilter             string                  // Partial clone filter for fetches, e.g. "blob:none"
+	NoAnimations            bool                    // Disable the loading spinner and border cycling (default: false)
+	ReadOnly                bool                    // Disable every action that changes worktrees, branches or files (default: false)
+	SafeMode                bool                    `yaml:"-"` // Set by --safe: read-only, with no hooks, scripts or external CLIs besides git
+	Observe                 bool                    `yaml:"-"` // Set by --observe: read-only, following checkouts and commits in every worktree
+	SelfUpdate              bool                    // Let "lazyworktree update" check for and install releases (default: true)
+	ControlSocket           bool                    // Listen on a Unix socket for "lazyworktree ctl" commands (default: false)
+	SSHHost                 string                  // Host git, gh and glab run on over SSH (experimental)
+	SSHPath                 string                  // Repository path on the SSH host
+	GitHubToken             string                  // Token handed to gh and the updater; may be a "secret:<name>" reference
+	GitLabToken             string                  // Token handed to glab; may be a "secret:<name>" reference
+	MaxWorktrees            int                     // Worktrees allowed per repository besides the main one (0 = unlimited)
+	MaxDiskUsage            string                  // Size the repository's worktree directory may reach, e.g. "20GiB" (empty = unlimited)
+	BranchNamePattern       string                  // Regular expression new branch names must match in full
+	BannedBaseBranches      []string                // Glob patterns of branches new worktrees may not be based on
+	TeamConfig              string                  // HTTPS URL or git ref publishing the team's .wt file
+	TeamConfigSHA256        string                  // Expected SHA-256 of the team file
+	TeamConfigVerify        bool                    // Require the team ref's commit to carry a valid signature
+	MaintenanceIntervals    map[string]string       // How often each maintenance task runs, from the maintenance_<task> keys
+	SparseCheckout          bool                    // Offer sparse checkout when creating worktrees (default: false)
+	SparseCheckoutPresets   map[string][]string     // Named lists of directories a sparse worktree checks out
+	HealthChecks            []*HealthCheck          // Commands whose results the health matrix shows per worktree
+	Macros                  map[string]*Macro       // Recorded key sequences by the key replaying them
+	ArtifactSync            []*ArtifactSync         // Build outputs copied or linked from the main worktree into new ones
+	AutoStash               bool                    // Offer to stash a dirty worktree when switching away and to pop it on return
+	RecentlyDeletedDays     int                     // Days deleted worktrees stay in the recently deleted list; 0 keeps none (default: 14)
+	SuggestBranches         bool                    // Suggest branches to start from while only the main worktree exists (default: true)
+	DockerCompose           bool                    // Give each worktree its own Docker Compose project (default: false)
+	AllowEnvTools           bool                    // Run direnv allow and mise trust in new worktrees (default: false)
+	SuggestBootstrap        bool                    // Offer toolchain setup commands, such as npm ci, for new worktrees (default: true)
+	PushScan                bool                    // Scan commits for large files and secrets before pushing (default: false)
+	PushScanMaxFileMB       int                     // Files above this many MiB are reported by the push scan (default: 5)
+	ComposeProjectTemplate  string                  // Compose project name with placeholders: {repo}, {worktree}, {branch} (default: "{repo}-{worktree}")
+	NoColor                 bool                    `yaml:"-"` // Set when NO_COLOR is present: colourless diffs and pagers
 	CustomThemes            map[string]*CustomTheme // User-defined custom themes
 	ConfigPath              string                  `yaml:"-"` // Path to the configuration file
 }
@@ -112,7 +209,11 @@ type AppConfig struct {
 type RepoConfig struct {
 	InitCommands      []string
 	TerminateCommands []string
+	InfoTemplate      string
+	Services          []*Service  // Sorted by name
+	ServicePorts      []PortRange // Ports scanned for conflicts between worktrees
 	Path              string
+	TeamPath          string // Synchronised team file merged under the .wt, if any
 }
 
 // DefaultConfig returns the default configuration values.
@@ -122,6 +223,7 @@ func DefaultConfig() *AppConfig {
 		AutoFetchPRs:            false,
 		AutoRefresh:             true,
 		RefreshIntervalSeconds:  10,
+		BranchNameScriptTimeout: 30,
 		SearchAutoSelect:        false,
 		MaxUntrackedDiffs:       10,
 		MaxDiffChars:            200000,
@@ -137,7 +239,14 @@ func DefaultConfig() *AppConfig {
 		SessionPrefix:           "wt-",
 		PaletteMRU:              true,
 		PaletteMRULimit:         5,
+		RecentlyDeletedDays:     14,
+		SuggestBranches:         true,
+		ComposeProjectTemplate:  "{repo}-{worktree}",
+		SuggestBootstrap:        true,
+		PushScanMaxFileMB:       5,
 		ShowIcons:               true,
+		SelfUpdate:              true,
+		NoColor:                 os.Getenv("NO_COLOR") != "",
 		CustomThemes:            make(map[string]*CustomTheme),
 		CustomCommands: map[string]*CustomCommand{
 			"t": {
@@ -204,7 +313,7 @@ func parseConfig(data map[string]any) *AppConfig {
 	if sortMode, ok := data["sort_mode"].(string); ok {
 		sortMode = strings.ToLower(strings.TrimSpace(sortMode))
 		switch sortMode {
-		case "path", "active", "switched":
+		case "path", "active", "switched", "manual":
 			cfg.SortMode = sortMode
 		}
 	} else if _, hasOld := data["sort_by_active"]; hasOld {
@@ -216,15 +325,27 @@ func parseConfig(data map[string]any) *AppConfig {
 		}
 	}
 
+	if dateFormat, ok := data["date_format"].(string); ok {
+		dateFormat = strings.TrimSpace(dateFormat)
+		if lower := strings.ToLower(dateFormat); lower == "relative" || lower == "iso" {
+			dateFormat = lower
+		}
+		cfg.DateFormat = dateFormat
+	}
+
 	cfg.AutoFetchPRs = coerceBool(data["auto_fetch_prs"], false)
 	cfg.AutoRefresh = coerceBool(data["auto_refresh"], cfg.AutoRefresh)
-	cfg.RefreshIntervalSeconds = coerceInt(data["refresh_interval"], cfg.RefreshIntervalSeconds)
+	cfg.RefreshIntervalSeconds = coerceSeconds(data["refresh_interval"], cfg.RefreshIntervalSeconds)
 	cfg.SearchAutoSelect = coerceBool(data["search_auto_select"], false)
+	cfg.VimMotions = coerceBool(data["vim_motions"], false)
 	cfg.FuzzyFinderInput = coerceBool(data["fuzzy_finder_input"], false)
 	cfg.ShowIcons = coerceBool(data["show_icons"], cfg.ShowIcons)
 	cfg.MaxUntrackedDiffs = coerceInt(data["max_untracked_diffs"], 10)
 	cfg.MaxDiffChars = coerceInt(data["max_diff_chars"], 200000)
 	cfg.MaxNameLength = coerceInt(data["max_name_length"], 95)
+	cfg.GitConcurrency = max(coerceInt(data["git_concurrency"], 0), 0)
+	cfg.NetworkConcurrency = max(coerceInt(data["network_concurrency"], 0), 0)
+	cfg.CommandConcurrency = max(coerceInt(data["command_concurrency"], 0), 0)
 	// Diff formatter/pager configuration (new keys: git_pager, git_pager_args)
 	if _, ok := data["git_pager_args"]; ok {
 		cfg.GitPagerArgs = normalizeArgsList(data["git_pager_args"])
@@ -272,6 +393,14 @@ func parseConfig(data map[string]any) *AppConfig {
 		}
 	}
 
+	if ownerIdentity, ok := data["owner_identity"].(string); ok {
+		cfg.OwnerIdentity = strings.TrimSpace(ownerIdentity)
+	}
+
+	if timeout := coerceInt(data["branch_name_script_timeout"], 0); timeout > 0 {
+		cfg.BranchNameScriptTimeout = timeout
+	}
+
 	if issueBranchNameTemplate, ok := data["issue_branch_name_template"].(string); ok {
 		issueBranchNameTemplate = strings.TrimSpace(issueBranchNameTemplate)
 		if issueBranchNameTemplate != "" {
@@ -316,6 +445,100 @@ func parseConfig(data map[string]any) *AppConfig {
 		cfg.MaxNameLength = 0
 	}
 
+	cfg.PRReviewers = normalizeCommandList(data["pr_reviewers"])
+
+	cfg.ShowDeployments = coerceBool(data["show_deployments"], false)
+	cfg.ShowDiffStats = coerceBool(data["show_diff_stats"], false)
+	cfg.CommitLint = coerceBool(data["commit_lint"], false)
+	cfg.CommitTypes = normalizeCommandList(data["commit_types"])
+	cfg.InfoTemplate = normalizeInfoTemplate(data["info_template"])
+	cfg.NoAnimations = coerceBool(data["no_animations"], false)
+	cfg.ReadOnly = coerceBool(data["read_only"], false)
+	cfg.SelfUpdate = coerceBool(data["self_update"], true)
+	cfg.ControlSocket = coerceBool(data["control_socket"], false)
+	if sshHost, ok := data["ssh_host"].(string); ok {
+		cfg.SSHHost = strings.TrimSpace(sshHost)
+	}
+	if sshPath, ok := data["ssh_path"].(string); ok {
+		cfg.SSHPath = strings.TrimSpace(sshPath)
+	}
+	if overviewCommand, ok := data["overview_command"].(string); ok {
+		cfg.OverviewCommand = strings.TrimSpace(overviewCommand)
+	}
+	if divergenceRef, ok := data["divergence_ref"].(string); ok {
+		cfg.DivergenceRef = strings.TrimSpace(divergenceRef)
+	}
+	cfg.FetchProtocolV2 = coerceBool(data["fetch_protocol_v2"], false)
+	cfg.FetchNegotiateWorktrees = coerceBool(data["fetch_negotiate_worktrees"], false)
+	cfg.FetchWorktreesOnly = coerceBool(data["fetch_worktrees_only"], false)
+	cfg.FetchPrune = coerceBool(data["fetch_prune"], false)
+	cfg.FetchDepth = max(coerceInt(data["fetch_depth"], 0), 0)
+	if fetchFilter, ok := data["fetch_filter"].(string); ok {
+		cfg.FetchFilter = strings.TrimSpace(fetchFilter)
+	}
+	cfg.MaxWorktrees = max(coerceInt(data["max_worktrees"], 0), 0)
+	if maxDiskUsage, ok := data["max_disk_usage"].(string); ok {
+		cfg.MaxDiskUsage = strings.TrimSpace(maxDiskUsage)
+	}
+	if branchNamePattern, ok := data["branch_name_pattern"].(string); ok {
+		cfg.BranchNamePattern = strings.TrimSpace(branchNamePattern)
+	}
+	cfg.BannedBaseBranches = normalizeCommandList(data["banned_base_branches"])
+	if teamConfig, ok := data["team_config"].(string); ok {
+		cfg.TeamConfig = strings.TrimSpace(teamConfig)
+	}
+	if teamConfigSHA256, ok := data["team_config_sha256"].(string); ok {
+		cfg.TeamConfigSHA256 = strings.ToLower(strings.TrimSpace(teamConfigSHA256))
+	}
+	cfg.TeamConfigVerify = coerceBool(data["team_config_verify_signature"], false)
+	for _, task := range maintenance.Tasks {
+		if interval, ok := data["maintenance_"+string(task)].(string); ok && strings.TrimSpace(interval) != "" {
+			if cfg.MaintenanceIntervals == nil {
+				cfg.MaintenanceIntervals = map[string]string{}
+			}
+			cfg.MaintenanceIntervals[string(task)] = strings.TrimSpace(interval)
+		}
+	}
+	cfg.SparseCheckout = coerceBool(data["sparse_checkout"], false)
+	if _, ok := data["sparse_checkout_presets"]; ok {
+		cfg.SparseCheckoutPresets = parseSparseCheckoutPresets(data)
+	}
+	if _, ok := data["health_checks"]; ok {
+		cfg.HealthChecks = parseHealthChecks(data)
+	}
+	if _, ok := data["artifact_sync"]; ok {
+		cfg.ArtifactSync = parseArtifactSync(data)
+	}
+	cfg.AutoStash = coerceBool(data["auto_stash"], false)
+	cfg.SuggestBranches = coerceBool(data["suggest_branches"], true)
+	cfg.DockerCompose = coerceBool(data["docker_compose"], false)
+	cfg.AllowEnvTools = coerceBool(data["allow_env_tools"], false)
+	cfg.SuggestBootstrap = coerceBool(data["suggest_bootstrap"], true)
+	cfg.PushScan = coerceBool(data["push_scan"], false)
+	cfg.PushScanMaxFileMB = coerceInt(data["push_scan_max_file_mb"], 5)
+	if cfg.PushScanMaxFileMB <= 0 {
+		cfg.PushScanMaxFileMB = 5
+	}
+	if composeProjectTemplate, ok := data["compose_project_template"].(string); ok {
+		composeProjectTemplate = strings.TrimSpace(composeProjectTemplate)
+		if composeProjectTemplate != "" {
+			cfg.ComposeProjectTemplate = composeProjectTemplate
+		}
+	}
+	cfg.RecentlyDeletedDays = coerceInt(data["recently_deleted_days"], 14)
+	if cfg.RecentlyDeletedDays < 0 {
+		cfg.RecentlyDeletedDays = 14
+	}
+	if deploymentScript, ok := data["deployment_script"].(string); ok {
+		cfg.DeploymentScript = strings.TrimSpace(deploymentScript)
+	}
+	if githubToken, ok := data["github_token"].(string); ok {
+		cfg.GitHubToken = strings.TrimSpace(githubToken)
+	}
+	if gitlabToken, ok := data["gitlab_token"].(string); ok {
+		cfg.GitLabToken = strings.TrimSpace(gitlabToken)
+	}
+
 	if _, ok := data["custom_commands"]; ok {
 		customCommands := parseCustomCommands(data)
 		for key, cmd := range customCommands {
@@ -323,6 +546,10 @@ func parseConfig(data map[string]any) *AppConfig {
 		}
 	}
 
+	if _, ok := data["macros"]; ok {
+		cfg.Macros = parseMacros(data)
+	}
+
 	if _, ok := data["custom_create_menus"]; ok {
 		cfg.CustomCreateMenus = parseCustomCreateMenus(data)
 	}
@@ -369,6 +596,38 @@ func parseCustomCommands(data map[string]any) map[string]*CustomCommand {
 	return cmds
 }
 
+// parseMacros reads the recorded macros, dropping those without keys.
+func parseMacros(data map[string]any) map[string]*Macro {
+	raw, ok := data["macros"].(map[string]any)
+	if !ok {
+		return nil
+	}
+	macros := make(map[string]*Macro, len(raw))
+	for key, val := range raw {
+		mData, ok := val.(map[string]any)
+		if !ok || strings.TrimSpace(key) == "" {
+			continue
+		}
+		var keys []string
+		if list, ok := mData["keys"].([]any); ok {
+			for _, item := range list {
+				if k, ok := item.(string); ok && k != "" {
+					keys = append(keys, k)
+				}
+			}
+		}
+		if len(keys) == 0 {
+			continue
+		}
+		name := strings.TrimSpace(getString(mData, "name"))
+		if name == "" {
+			name = key
+		}
+		macros[key] = &Macro{Name: name, Keys: keys}
+	}
+	return macros
+}
+
 func parseTmuxCommand(data map[string]any) *TmuxCommand {
 	cmd := &TmuxCommand{
 		SessionName: getString(data, "session_name"),
@@ -430,6 +689,88 @@ func parseCustomCreateMenus(data map[string]any) []*CustomCreateMenu {
 	return menus
 }
 
+// parseSparseCheckoutPresets reads the named directory lists, dropping
+// presets without a usable directory.
+func parseSparseCheckoutPresets(data map[string]any) map[string][]string {
+	raw, ok := data["sparse_checkout_presets"].(map[string]any)
+	if !ok {
+		return nil
+	}
+	presets := make(map[string][]string, len(raw))
+	for name, val := range raw {
+		name = strings.TrimSpace(name)
+		var dirs []string
+		for _, dir := range normalizeCommandList(val) {
+			dir = strings.Trim(filepath.ToSlash(dir), "/")
+			if dir == "" || strings.HasPrefix(dir, "-") {
+				continue
+			}
+			dirs = append(dirs, dir)
+		}
+		if name != "" && len(dirs) > 0 {
+			presets[name] = dirs
+		}
+	}
+	return presets
+}
+
+// parseHealthChecks reads the health check list, dropping entries without a
+// name or command and repeated names.
+func parseHealthChecks(data map[string]any) []*HealthCheck {
+	raw, ok := data["health_checks"].([]any)
+	if !ok {
+		return nil
+	}
+	checks := make([]*HealthCheck, 0, len(raw))
+	seen := make(map[string]bool, len(raw))
+	for _, val := range raw {
+		cData, ok := val.(map[string]any)
+		if !ok {
+			continue
+		}
+		check := &HealthCheck{
+			Name:    strings.TrimSpace(getString(cData, "name")),
+			Command: strings.TrimSpace(getString(cData, "command")),
+		}
+		if check.Name != "" && check.Command != "" && !seen[check.Name] {
+			seen[check.Name] = true
+			checks = append(checks, check)
+		}
+	}
+	return checks
+}
+
+// parseArtifactSync reads the artifacts to sync, either paths or entries
+// with a path and a mode of copy or link, dropping paths outside the
+// worktree and repeated ones.
+func parseArtifactSync(data map[string]any) []*ArtifactSync {
+	raw, ok := data["artifact_sync"].([]any)
+	if !ok {
+		return nil
+	}
+	specs := make([]*ArtifactSync, 0, len(raw))
+	seen := make(map[string]bool, len(raw))
+	for _, val := range raw {
+		spec := &ArtifactSync{}
+		switch v := val.(type) {
+		case string:
+			spec.Path = v
+		case map[string]any:
+			spec.Path = getString(v, "path")
+			spec.Link = strings.EqualFold(strings.TrimSpace(getString(v, "mode")), "link")
+		default:
+			continue
+		}
+		spec.Path = filepath.ToSlash(filepath.Clean(strings.TrimSpace(spec.Path)))
+		if spec.Path == "." || !filepath.IsLocal(filepath.FromSlash(spec.Path)) || seen[spec.Path] {
+			continue
+		}
+		seen[spec.Path] = true
+		specs = append(specs, spec)
+	}
+	return specs
+}
+
 func parseCustomThemes(data map[string]any) map[string]*CustomTheme {
 	raw, ok := data["custom_themes"].(map[string]any)
 	if !ok {
@@ -600,8 +941,7 @@ func validateColorHex(color string) bool {
 		return false
 	}
 
-	matched, _ := regexp.MatchString("^[0-9A-Fa-f]+$", hex)
-	return matched
+	return hexColorRe.MatchString(hex)
 }
 
 // validateThemeInheritance validates inheritance chains, checking for circular dependencies and ensuring base themes exist.
@@ -657,6 +997,16 @@ func normalizeCommandList(val any) []string {
 	return res
 }
 
+// normalizeInfoTemplate keeps an info_template verbatim apart from trailing
+// whitespace, so leading indentation in the template survives.
+func normalizeInfoTemplate(val any) string {
+	s, ok := val.(string)
+	if !ok || strings.TrimSpace(s) == "" {
+		return ""
+	}
+	return strings.TrimRight(s, " \t\r\n")
+}
+
 func normalizeArgsList(val any) []string {
 	if s, ok := val.(string); ok {
 		s = strings.TrimSpace(s)
@@ -745,6 +1095,9 @@ func (cfg *AppConfig) ApplyCLIOverrides(overrides []string) error {
 	if overrideCfg.SortMode != "" {
 		cfg.SortMode = overrideCfg.SortMode
 	}
+	if overrideCfg.DateFormat != "" {
+		cfg.DateFormat = overrideCfg.DateFormat
+	}
 	if overrideCfg.Theme != "" {
 		cfg.Theme = overrideCfg.Theme
 	}
@@ -769,15 +1122,51 @@ func (cfg *AppConfig) ApplyCLIOverrides(overrides []string) error {
 	if overrideCfg.BranchNameScript != "" {
 		cfg.BranchNameScript = overrideCfg.BranchNameScript
 	}
+	if overrideCfg.OwnerIdentity != "" {
+		cfg.OwnerIdentity = overrideCfg.OwnerIdentity
+	}
+	if overrideCfg.OverviewCommand != "" {
+		cfg.OverviewCommand = overrideCfg.OverviewCommand
+	}
+	if overrideCfg.DivergenceRef != "" {
+		cfg.DivergenceRef = overrideCfg.DivergenceRef
+	}
+	if _, ok := overrideData["fetch_protocol_v2"]; ok {
+		cfg.FetchProtocolV2 = overrideCfg.FetchProtocolV2
+	}
+	if _, ok := overrideData["fetch_negotiate_worktrees"]; ok {
+		cfg.FetchNegotiateWorktrees = overrideCfg.FetchNegotiateWorktrees
+	}
+	if _, ok := overrideData["fetch_worktrees_only"]; ok {
+		cfg.FetchWorktreesOnly = overrideCfg.FetchWorktreesOnly
+	}
+	if _, ok := overrideData["fetch_prune"]; ok {
+		cfg.FetchPrune = overrideCfg.FetchPrune
+	}
+	if _, ok := overrideData["fetch_depth"]; ok {
+		cfg.FetchDepth = overrideCfg.FetchDepth
+	}
+	if overrideCfg.FetchFilter != "" {
+		cfg.FetchFilter = overrideCfg.FetchFilter
+	}
 	if overrideCfg.IssueBranchNameTemplate != "" {
 		cfg.IssueBranchNameTemplate = overrideCfg.IssueBranchNameTemplate
 	}
 	if overrideCfg.PRBranchNameTemplate != "" {
 		cfg.PRBranchNameTemplate = overrideCfg.PRBranchNameTemplate
 	}
+	if overrideCfg.DeploymentScript != "" {
+		cfg.DeploymentScript = overrideCfg.DeploymentScript
+	}
 	if overrideCfg.SessionPrefix != "" {
 		cfg.SessionPrefix = overrideCfg.SessionPrefix
 	}
+	if overrideCfg.GitHubToken != "" {
+		cfg.GitHubToken = overrideCfg.GitHubToken
+	}
+	if overrideCfg.GitLabToken != "" {
+		cfg.GitLabToken = overrideCfg.GitLabToken
+	}
 
 	// Arrays - check if they exist in override data
 	if _, ok := overrideData["init_commands"]; ok {
@@ -798,6 +1187,9 @@ func (cfg *AppConfig) ApplyCLIOverrides(overrides []string) error {
 	if _, ok := overrideData["search_auto_select"]; ok {
 		cfg.SearchAutoSelect = overrideCfg.SearchAutoSelect
 	}
+	if _, ok := overrideData["vim_motions"]; ok {
+		cfg.VimMotions = overrideCfg.VimMotions
+	}
 	if _, ok := overrideData["auto_refresh"]; ok {
 		cfg.AutoRefresh = overrideCfg.AutoRefresh
 	}
@@ -813,7 +1205,103 @@ func (cfg *AppConfig) ApplyCLIOverrides(overrides []string) error {
 	if _, ok := overrideData["palette_mru"]; ok {
 		cfg.PaletteMRU = overrideCfg.PaletteMRU
 	}
+	if _, ok := overrideData["show_deployments"]; ok {
+		cfg.ShowDeployments = overrideCfg.ShowDeployments
+	}
+	if _, ok := overrideData["show_diff_stats"]; ok {
+		cfg.ShowDiffStats = overrideCfg.ShowDiffStats
+	}
+	if _, ok := overrideData["commit_lint"]; ok {
+		cfg.CommitLint = overrideCfg.CommitLint
+	}
+	if _, ok := overrideData["no_animations"]; ok {
+		cfg.NoAnimations = overrideCfg.NoAnimations
+	}
+	if _, ok := overrideData["read_only"]; ok {
+		cfg.ReadOnly = overrideCfg.ReadOnly
+	}
+	if _, ok := overrideData["self_update"]; ok {
+		cfg.SelfUpdate = overrideCfg.SelfUpdate
+	}
+	if _, ok := overrideData["control_socket"]; ok {
+		cfg.ControlSocket = overrideCfg.ControlSocket
+	}
+	if overrideCfg.SSHHost != "" {
+		cfg.SSHHost = overrideCfg.SSHHost
+	}
+	if overrideCfg.SSHPath != "" {
+		cfg.SSHPath = overrideCfg.SSHPath
+	}
+	if _, ok := overrideData["max_worktrees"]; ok {
+		cfg.MaxWorktrees = overrideCfg.MaxWorktrees
+	}
+	if overrideCfg.MaxDiskUsage != "" {
+		cfg.MaxDiskUsage = overrideCfg.MaxDiskUsage
+	}
+	if overrideCfg.BranchNamePattern != "" {
+		cfg.BranchNamePattern = overrideCfg.BranchNamePattern
+	}
+	if _, ok := overrideData["banned_base_branches"]; ok {
+		cfg.BannedBaseBranches = overrideCfg.BannedBaseBranches
+	}
+	if overrideCfg.TeamConfig != "" {
+		cfg.TeamConfig = overrideCfg.TeamConfig
+	}
+	if overrideCfg.TeamConfigSHA256 != "" {
+		cfg.TeamConfigSHA256 = overrideCfg.TeamConfigSHA256
+	}
+	if _, ok := overrideData["team_config_verify_signature"]; ok {
+		cfg.TeamConfigVerify = overrideCfg.TeamConfigVerify
+	}
+	if _, ok := overrideData["sparse_checkout"]; ok {
+		cfg.SparseCheckout = overrideCfg.SparseCheckout
+	}
+	if _, ok := overrideData["sparse_checkout_presets"]; ok {
+		cfg.SparseCheckoutPresets = overrideCfg.SparseCheckoutPresets
+	}
+	if _, ok := overrideData["health_checks"]; ok {
+		cfg.HealthChecks = overrideCfg.HealthChecks
+	}
+	if _, ok := overrideData["artifact_sync"]; ok {
+		cfg.ArtifactSync = overrideCfg.ArtifactSync
+	}
+	if _, ok := overrideData["auto_stash"]; ok {
+		cfg.AutoStash = overrideCfg.AutoStash
+	}
+	if _, ok := overrideData["suggest_branches"]; ok {
+		cfg.SuggestBranches = overrideCfg.SuggestBranches
+	}
+	if _, ok := overrideData["docker_compose"]; ok {
+		cfg.DockerCompose = overrideCfg.DockerCompose
+	}
+	if _, ok := overrideData["compose_project_template"]; ok {
+		cfg.ComposeProjectTemplate = overrideCfg.ComposeProjectTemplate
+	}
+	if _, ok := overrideData["allow_env_tools"]; ok {
+		cfg.AllowEnvTools = overrideCfg.AllowEnvTools
+	}
+	if _, ok := overrideData["suggest_bootstrap"]; ok {
+		cfg.SuggestBootstrap = overrideCfg.SuggestBootstrap
+	}
+	if _, ok := overrideData["push_scan"]; ok {
+		cfg.PushScan = overrideCfg.PushScan
+	}
+	if _, ok := overrideData["push_scan_max_file_mb"]; ok {
+		cfg.PushScanMaxFileMB = overrideCfg.PushScanMaxFileMB
+	}
+	if _, ok := overrideData["recently_deleted_days"]; ok {
+		cfg.RecentlyDeletedDays = overrideCfg.RecentlyDeletedDays
+	}
+	for task, interval := range overrideCfg.MaintenanceIntervals {
+		if cfg.MaintenanceIntervals == nil {
+			cfg.MaintenanceIntervals = map[string]string{}
+		}
+		cfg.MaintenanceIntervals[task] = interval
+	}
 
+	if _, ok := overrideData["branch_name_script_timeout"]; ok {
+		cfg.BranchNameScriptTimeout = overrideCfg.BranchNameScriptTimeout
+	}
 	if _, ok := overrideData["max_untracked_diffs"]; ok {
 		cfg.MaxUntrackedDiffs = overrideCfg.MaxUntrackedDiffs
 	}
@@ -826,6 +1314,15 @@ func (cfg *AppConfig) ApplyCLIOverrides(overrides []string) error {
 	if _, ok := overrideData["palette_mru_limit"]; ok {
 		cfg.PaletteMRULimit = overrideCfg.PaletteMRULimit
 	}
+	if _, ok := overrideData["git_concurrency"]; ok {
+		cfg.GitConcurrency = overrideCfg.GitConcurrency
+	}
+	if _, ok := overrideData["network_concurrency"]; ok {
+		cfg.NetworkConcurrency = overrideCfg.NetworkConcurrency
+	}
+	if _, ok := overrideData["command_concurrency"]; ok {
+		cfg.CommandConcurrency = overrideCfg.CommandConcurrency
+	}
 
 	return nil
 }
@@ -912,23 +1409,35 @@ func LoadConfig(configPath string) (*AppConfig, error) {
 	return cfg, nil
 }
 
-// SaveConfig writes the configuration back to the file.
-// It tries to preserve existing fields by reading the file first.
-func SaveConfig(cfg *AppConfig) error {
-	path := cfg.ConfigPath
-	if path == "" {
-		configBase := filepath.Join(getConfigDir(), "lazyworktree")
-		path = filepath.Join(configBase, "config.yaml")
+// Policy returns the worktree quotas and naming rules, or an error naming the
+// offending key when a value is invalid.
+func (cfg *AppConfig) Policy() (policy.Policy, error) {
+	return policy.New(cfg.MaxWorktrees, cfg.MaxDiskUsage, cfg.BranchNamePattern, cfg.BannedBaseBranches)
+}
 
-		if err := os.MkdirAll(configBase, 0o700); err != nil { // #nosec G301
-			return err
+// MaintenanceSchedule returns how often each maintenance task runs by
+// itself, or an error naming the offending key.
+func (cfg *AppConfig) MaintenanceSchedule() (maintenance.Schedule, error) {
+	schedule := maintenance.Schedule{}
+	for _, task := range maintenance.Tasks {
+		interval, err := maintenance.ParseInterval(cfg.MaintenanceIntervals[string(task)])
+		if err != nil {
+			return nil, fmt.Errorf("maintenance_%s: %w", task, err)
 		}
-	} else {
-		// Ensure parent directory of the specific ConfigPath exists if we are saving to a known path
-		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil { // #nosec G301
-			return err
+		if interval > 0 {
+			schedule[task] = interval
 		}
 	}
+	return schedule, nil
+}
+
+// SaveConfig writes the configuration back to the file.
+// It tries to preserve existing fields by reading the file first.
+func SaveConfig(cfg *AppConfig) error {
+	path, err := savePath(cfg)
+	if err != nil {
+		return err
+	}
 
 	// #nosec G304
 	data, err := os.ReadFile(path)
@@ -938,13 +1447,12 @@ func SaveConfig(cfg *AppConfig) error {
 	}
 
 	// Use regex to replace or add theme: line
-	re := regexp.MustCompile(`(?m)^theme:\s*.*$`)
 	newThemeLine := fmt.Sprintf("theme: %s", cfg.Theme)
 
 	var newData []byte
-	if re.MatchString(content) {
+	if themeLineRe.MatchString(content) {
 		// Replace existing theme line
-		newData = []byte(re.ReplaceAllString(content, newThemeLine))
+		newData = []byte(themeLineRe.ReplaceAllString(content, newThemeLine))
 	} else {
 		// Add theme line
 		if content != "" && !strings.HasSuffix(content, "\n") {
@@ -965,35 +1473,183 @@ func SaveConfig(cfg *AppConfig) error {
 	return nil
 }
 
-// LoadRepoConfig loads the repository configuration from a .wt file.
+// savePath returns the file SaveConfig writes to, creating its directory.
+func savePath(cfg *AppConfig) (string, error) {
+	path := cfg.ConfigPath
+	if path == "" {
+		path = filepath.Join(getConfigDir(), "lazyworktree", "config.yaml")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil { // #nosec G301
+		return "", err
+	}
+	return path, nil
+}
+
+// SaveMacro binds macro to key under the macros section of the
+// configuration file, replacing any macro bound to the same key. The rest
+// of the file is kept, comments included, though it is re-indented.
+func SaveMacro(cfg *AppConfig, key string, macro *Macro) error {
+	path, err := savePath(cfg)
+	if err != nil {
+		return err
+	}
+
+	var doc yaml.Node
+	// #nosec G304
+	if data, err := os.ReadFile(path); err == nil && strings.TrimSpace(string(data)) != "" {
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+	}
+	if len(doc.Content) == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return fmt.Errorf("%s is not a YAML mapping", path)
+	}
+
+	var entry yaml.Node
+	if err := entry.Encode(struct {
+		Name string   `yaml:"name"`
+		Keys []string `yaml:"keys,flow"`
+	}{macro.Name, macro.Keys}); err != nil {
+		return err
+	}
+	macros := mappingValue(root, "macros")
+	if macros.Kind != yaml.MappingNode {
+		*macros = yaml.Node{Kind: yaml.MappingNode}
+	}
+	*mappingValue(macros, key) = entry
+
+	var out strings.Builder
+	enc := yaml.NewEncoder(&out)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return err
+	}
+	if err := enc.Close(); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, []byte(out.String()), 0o600); err != nil { // #nosec G306
+		return err
+	}
+
+	if cfg.Macros == nil {
+		cfg.Macros = make(map[string]*Macro)
+	}
+	cfg.Macros[key] = macro
+	if cfg.ConfigPath == "" {
+		cfg.ConfigPath = path
+	}
+	return nil
+}
+
+// mappingValue returns the value node under key in a YAML mapping, adding
+// an empty one when the key is missing.
+func mappingValue(mapping *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	value := &yaml.Node{Kind: yaml.MappingNode}
+	mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, value)
+	return value
+}
+
+// LoadRepoConfig loads the repository configuration from a .wt file,
+// merged over the team configuration synchronised by "config sync-team".
 func LoadRepoConfig(repoPath string) (*RepoConfig, string, error) {
 	if repoPath == "" {
 		return nil, "", fmt.Errorf("repo path cannot be empty")
 	}
 
 	path := filepath.Join(repoPath, ".wt")
+	cfg, err := readRepoConfig(path)
+	if err != nil {
+		return nil, path, err
+	}
+	teamPath := TeamConfigPath(repoPath)
+	team, err := readRepoConfig(teamPath)
+	if err != nil {
+		return nil, path, fmt.Errorf("team configuration %s: %w", teamPath, err)
+	}
+	return mergeRepoConfig(team, cfg), path, nil
+}
+
+// readRepoConfig parses a .wt file, returning nil when it does not exist.
+func readRepoConfig(path string) (*RepoConfig, error) {
 	if _, err := os.Stat(path); os.IsNotExist(err) {
-		return nil, path, nil
+		return nil, nil
 	}
 
 	// #nosec G304 -- path is constructed from safe repo path
 	data, err := os.ReadFile(path)
 	if err != nil {
-		return nil, path, err
+		return nil, err
 	}
 
 	var raw map[string]any
 	if err := yaml.Unmarshal(data, &raw); err != nil {
-		return nil, path, err
+		return nil, err
 	}
 
-	cfg := &RepoConfig{
+	return &RepoConfig{
 		Path:              path,
 		InitCommands:      normalizeCommandList(raw["init_commands"]),
 		TerminateCommands: normalizeCommandList(raw["terminate_commands"]),
+		InfoTemplate:      normalizeInfoTemplate(raw["info_template"]),
+		Services:          parseServices(raw["services"]),
+		ServicePorts:      parsePortRanges(raw["service_ports"]),
+	}, nil
+}
+
+// parseServices reads the services map of name to command, dropping
+// entries without a command.
+func parseServices(val any) []*Service {
+	raw, ok := val.(map[string]any)
+	if !ok {
+		return nil
+	}
+	services := make([]*Service, 0, len(raw))
+	for name, cmd := range raw {
+		command, _ := cmd.(string)
+		name, command = strings.TrimSpace(name), strings.TrimSpace(command)
+		if name != "" && command != "" {
+			services = append(services, &Service{Name: name, Command: command})
+		}
 	}
+	sort.Slice(services, func(i, j int) bool { return services[i].Name < services[j].Name })
+	return services
+}
 
-	return cfg, path, nil
+// parsePortRanges reads a port, a range such as "3000-3010", or a list of
+// them, dropping anything that is not a valid port.
+func parsePortRanges(val any) []PortRange {
+	var entries []any
+	switch v := val.(type) {
+	case []any:
+		entries = v
+	case nil:
+		return nil
+	default:
+		entries = []any{v}
+	}
+	var ranges []PortRange
+	for _, entry := range entries {
+		first, last, isRange := strings.Cut(strings.TrimSpace(fmt.Sprint(entry)), "-")
+		if !isRange {
+			last = first
+		}
+		from, err1 := strconv.Atoi(strings.TrimSpace(first))
+		to, err2 := strconv.Atoi(strings.TrimSpace(last))
+		if err1 != nil || err2 != nil || from < 1 || to > 65535 || from > to {
+			continue
+		}
+		ranges = append(ranges, PortRange{First: from, Last: to})
+	}
+	return ranges
 }
 
 // SyntaxThemeForUITheme returns the syntax theme name for a given TUI theme.
@@ -1067,6 +1723,8 @@ func DefaultDeltaArgsForTheme(themeName string) []string {
 		return []string{"--syntax-theme", "Dracula"}
 	case theme.EverforestDarkName:
 		return []string{"--syntax-theme", "Dracula"}
+	case theme.OkabeItoLightName:
+		return []string{"--syntax-theme", "GitHub"}
 	default:
 		return []string{"--syntax-theme", "Dracula"}
 	}
@@ -1076,12 +1734,18 @@ func DefaultDeltaArgsForTheme(themeName string) []string {
 func NormalizeThemeName(name string) string {
 	name = strings.ToLower(strings.TrimSpace(name))
 	switch name {
-	case "dracula", "dracula-light", "narna", "clean-light", "catppuccin-latte", "rose-pine-dawn", "one-light", "everforest-light", "solarized-dark", "solarized-light", "gruvbox-dark", "gruvbox-light", "nord", "monokai", "catppuccin-mocha", "modern", "tokyo-night", "one-dark", "rose-pine", "ayu-mirage", "everforest-dark":
+	case "dracula", "dracula-light", "narna", "clean-light", "catppuccin-latte", "rose-pine-dawn", "one-light", "everforest-light", "solarized-dark", "solarized-light", "gruvbox-dark", "gruvbox-light", "nord", "monokai", "catppuccin-mocha", "modern", "tokyo-night", "one-dark", "rose-pine", "ayu-mirage", "everforest-dark", "okabe-ito", "okabe-ito-light":
 		return name
 	}
 	return ""
 }
 
+// DefaultConfigPath returns where the YAML configuration is read from when no
+// --config-file is given.
+func DefaultConfigPath() string {
+	return filepath.Join(getConfigDir(), "lazyworktree", "config.yaml")
+}
+
 func getConfigDir() string {
 	if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" {
 		return xdg
@@ -1129,6 +1793,17 @@ func coerceInt(v any, def int) int {
 	return def
 }
 
+// coerceSeconds reads a number of seconds, or a duration such as "30s" or
+// "2m".
+func coerceSeconds(v any, def int) int {
+	if s, ok := v.(string); ok {
+		if d, err := time.ParseDuration(strings.TrimSpace(s)); err == nil {
+			return int(d / time.Second)
+		}
+	}
+	return coerceInt(v, def)
+}
+
 func getString(data map[string]any, key string) string {
 	if v, ok := data[key]; ok && v != nil {
 		return strings.TrimSpace(fmt.Sprint(v))
@@ -4,7 +4,12 @@ import (
 	"os"
 	"path/filepath"
 	"slices"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/chmouel/lazyworktree/internal/maintenance"
+	"github.com/chmouel/lazyworktree/internal/policy"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -428,6 +433,340 @@ func TestParseConfig(t *testing.T) {
 				assert.Equal(t, "tofu", cfg.TrustMode)
 			},
 		},
+		{
+			name: "deployments",
+			data: map[string]interface{}{
+				"show_deployments":  true,
+				"deployment_script": "  echo https://preview.example.com  ",
+			},
+			validate: func(t *testing.T, cfg *AppConfig) {
+				assert.True(t, cfg.ShowDeployments)
+				assert.Equal(t, "echo https://preview.example.com", cfg.DeploymentScript)
+			},
+		},
+		{
+			name: "show_diff_stats",
+			data: map[string]interface{}{
+				"show_diff_stats": true,
+			},
+			validate: func(t *testing.T, cfg *AppConfig) {
+				assert.True(t, cfg.ShowDiffStats)
+			},
+		},
+		{
+			name: "overview_command",
+			data: map[string]interface{}{
+				"overview_command": "  cat NOTES.md  ",
+			},
+			validate: func(t *testing.T, cfg *AppConfig) {
+				assert.Equal(t, "cat NOTES.md", cfg.OverviewCommand)
+			},
+		},
+		{
+			name: "concurrency limits",
+			data: map[string]interface{}{
+				"git_concurrency":     16,
+				"network_concurrency": "2",
+				"command_concurrency": -1,
+			},
+			validate: func(t *testing.T, cfg *AppConfig) {
+				assert.Equal(t, 16, cfg.GitConcurrency)
+				assert.Equal(t, 2, cfg.NetworkConcurrency)
+				assert.Zero(t, cfg.CommandConcurrency)
+			},
+		},
+		{
+			name: "divergence_ref",
+			data: map[string]interface{}{
+				"divergence_ref": " origin/main ",
+			},
+			validate: func(t *testing.T, cfg *AppConfig) {
+				assert.Equal(t, "origin/main", cfg.DivergenceRef)
+			},
+		},
+		{
+			name: "fetch tuning",
+			data: map[string]interface{}{
+				"fetch_protocol_v2":         true,
+				"fetch_negotiate_worktrees": "true",
+				"fetch_worktrees_only":      true,
+				"fetch_prune":               true,
+				"fetch_depth":               "-3",
+				"fetch_filter":              " blob:none ",
+			},
+			validate: func(t *testing.T, cfg *AppConfig) {
+				assert.True(t, cfg.FetchProtocolV2)
+				assert.True(t, cfg.FetchNegotiateWorktrees)
+				assert.True(t, cfg.FetchWorktreesOnly)
+				assert.True(t, cfg.FetchPrune)
+				assert.Zero(t, cfg.FetchDepth)
+				assert.Equal(t, "blob:none", cfg.FetchFilter)
+			},
+		},
+		{
+			name: "date_format keywords",
+			data: map[string]interface{}{
+				"date_format": " ISO ",
+			},
+			validate: func(t *testing.T, cfg *AppConfig) {
+				assert.Equal(t, "iso", cfg.DateFormat)
+			},
+		},
+		{
+			name: "date_format layout",
+			data: map[string]interface{}{
+				"date_format": "02 Jan 2006 15:04",
+			},
+			validate: func(t *testing.T, cfg *AppConfig) {
+				assert.Equal(t, "02 Jan 2006 15:04", cfg.DateFormat)
+			},
+		},
+		{
+			name: "info_template keeps indentation",
+			data: map[string]interface{}{
+				"info_template": "  {{.Branch}}\n{{if .PR}}  PR{{end}}\n\n",
+			},
+			validate: func(t *testing.T, cfg *AppConfig) {
+				assert.Equal(t, "  {{.Branch}}\n{{if .PR}}  PR{{end}}", cfg.InfoTemplate)
+			},
+		},
+		{
+			name: "commit lint",
+			data: map[string]interface{}{
+				"commit_lint":  true,
+				"commit_types": []interface{}{"feat", " fix ", ""},
+			},
+			validate: func(t *testing.T, cfg *AppConfig) {
+				assert.True(t, cfg.CommitLint)
+				assert.Equal(t, []string{"feat", "fix"}, cfg.CommitTypes)
+			},
+		},
+		{
+			name: "no_animations",
+			data: map[string]interface{}{
+				"no_animations": "yes",
+			},
+			validate: func(t *testing.T, cfg *AppConfig) {
+				assert.True(t, cfg.NoAnimations)
+			},
+		},
+		{
+			name: "self_update",
+			data: map[string]interface{}{
+				"self_update": false,
+			},
+			validate: func(t *testing.T, cfg *AppConfig) {
+				assert.False(t, cfg.SelfUpdate)
+			},
+		},
+		{
+			name: "read_only",
+			data: map[string]interface{}{
+				"read_only": "true",
+			},
+			validate: func(t *testing.T, cfg *AppConfig) {
+				assert.True(t, cfg.ReadOnly)
+			},
+		},
+		{
+			name: "control_socket",
+			data: map[string]interface{}{
+				"control_socket": true,
+			},
+			validate: func(t *testing.T, cfg *AppConfig) {
+				assert.True(t, cfg.ControlSocket)
+			},
+		},
+		{
+			name: "worktree policy",
+			data: map[string]interface{}{
+				"max_worktrees":        "10",
+				"max_disk_usage":       " 20GiB ",
+				"branch_name_pattern":  "(feature|fix)/.+",
+				"banned_base_branches": []interface{}{"production", "release/*"},
+			},
+			validate: func(t *testing.T, cfg *AppConfig) {
+				assert.Equal(t, 10, cfg.MaxWorktrees)
+				assert.Equal(t, "20GiB", cfg.MaxDiskUsage)
+				assert.Equal(t, []string{"production", "release/*"}, cfg.BannedBaseBranches)
+				p, err := cfg.Policy()
+				require.NoError(t, err)
+				assert.Equal(t, int64(20<<30), p.MaxDiskBytes)
+				assert.Error(t, p.CheckCreate(policy.Usage{}, "login", ""))
+			},
+		},
+		{
+			name: "maintenance intervals",
+			data: map[string]interface{}{
+				"maintenance_gc":    " 7d ",
+				"maintenance_fetch": "1h",
+				"maintenance_prune": "",
+			},
+			validate: func(t *testing.T, cfg *AppConfig) {
+				assert.Equal(t, map[string]string{"gc": "7d", "fetch": "1h"}, cfg.MaintenanceIntervals)
+				schedule, err := cfg.MaintenanceSchedule()
+				require.NoError(t, err)
+				assert.Equal(t, maintenance.Schedule{maintenance.GC: 7 * 24 * time.Hour, maintenance.Fetch: time.Hour}, schedule)
+				cfg.MaintenanceIntervals["cache"] = "weekly"
+				_, err = cfg.MaintenanceSchedule()
+				assert.ErrorContains(t, err, "maintenance_cache")
+			},
+		},
+		{
+			name: "sparse checkout presets",
+			data: map[string]interface{}{
+				"sparse_checkout": true,
+				"sparse_checkout_presets": map[string]interface{}{
+					"frontend": []interface{}{"web/", " packages/ui ", "/docs"},
+					"backend":  "services/api",
+					"empty":    []interface{}{"", "--bad"},
+				},
+			},
+			validate: func(t *testing.T, cfg *AppConfig) {
+				assert.True(t, cfg.SparseCheckout)
+				assert.Equal(t, map[string][]string{
+					"frontend": {"web", "packages/ui", "docs"},
+					"backend":  {"services/api"},
+				}, cfg.SparseCheckoutPresets)
+			},
+		},
+		{
+			name: "auto stash",
+			data: map[string]interface{}{
+				"auto_stash": "true",
+			},
+			validate: func(t *testing.T, cfg *AppConfig) {
+				assert.True(t, cfg.AutoStash)
+			},
+		},
+		{
+			name: "suggest branches disabled",
+			data: map[string]interface{}{
+				"suggest_branches": false,
+			},
+			validate: func(t *testing.T, cfg *AppConfig) {
+				assert.False(t, cfg.SuggestBranches)
+			},
+		},
+		{
+			name: "docker compose",
+			data: map[string]interface{}{
+				"docker_compose":           true,
+				"compose_project_template": " {repo}_{branch} ",
+			},
+			validate: func(t *testing.T, cfg *AppConfig) {
+				assert.True(t, cfg.DockerCompose)
+				assert.Equal(t, "{repo}_{branch}", cfg.ComposeProjectTemplate)
+			},
+		},
+		{
+			name: "allow env tools",
+			data: map[string]interface{}{
+				"allow_env_tools": "yes",
+			},
+			validate: func(t *testing.T, cfg *AppConfig) {
+				assert.True(t, cfg.AllowEnvTools)
+			},
+		},
+		{
+			name: "suggest bootstrap disabled",
+			data: map[string]interface{}{
+				"suggest_bootstrap": false,
+			},
+			validate: func(t *testing.T, cfg *AppConfig) {
+				assert.False(t, cfg.SuggestBootstrap)
+			},
+		},
+		{
+			name: "push scan",
+			data: map[string]interface{}{
+				"push_scan":             true,
+				"push_scan_max_file_mb": 20,
+			},
+			validate: func(t *testing.T, cfg *AppConfig) {
+				assert.True(t, cfg.PushScan)
+				assert.Equal(t, 20, cfg.PushScanMaxFileMB)
+			},
+		},
+		{
+			name: "push scan invalid size",
+			data: map[string]interface{}{
+				"push_scan_max_file_mb": 0,
+			},
+			validate: func(t *testing.T, cfg *AppConfig) {
+				assert.False(t, cfg.PushScan)
+				assert.Equal(t, 5, cfg.PushScanMaxFileMB)
+			},
+		},
+		{
+			name: "recently deleted days",
+			data: map[string]interface{}{
+				"recently_deleted_days": 30,
+			},
+			validate: func(t *testing.T, cfg *AppConfig) {
+				assert.Equal(t, 30, cfg.RecentlyDeletedDays)
+			},
+		},
+		{
+			name: "recently deleted days disabled",
+			data: map[string]interface{}{
+				"recently_deleted_days": "0",
+			},
+			validate: func(t *testing.T, cfg *AppConfig) {
+				assert.Equal(t, 0, cfg.RecentlyDeletedDays)
+			},
+		},
+		{
+			name: "health checks",
+			data: map[string]interface{}{
+				"health_checks": []interface{}{
+					map[string]interface{}{"name": "lint", "command": " make lint "},
+					map[string]interface{}{"name": "test", "command": "go test ./..."},
+					map[string]interface{}{"name": "lint", "command": "golangci-lint run"},
+					map[string]interface{}{"name": "build"},
+					"not a map",
+				},
+			},
+			validate: func(t *testing.T, cfg *AppConfig) {
+				assert.Equal(t, []*HealthCheck{
+					{Name: "lint", Command: "make lint"},
+					{Name: "test", Command: "go test ./..."},
+				}, cfg.HealthChecks)
+			},
+		},
+		{
+			name: "team config",
+			data: map[string]interface{}{
+				"team_config":                  " refs/meta/lazyworktree ",
+				"team_config_sha256":           "ABC123",
+				"team_config_verify_signature": true,
+			},
+			validate: func(t *testing.T, cfg *AppConfig) {
+				assert.Equal(t, "refs/meta/lazyworktree", cfg.TeamConfig)
+				assert.Equal(t, "abc123", cfg.TeamConfigSHA256)
+				assert.True(t, cfg.TeamConfigVerify)
+			},
+		},
+		{
+			name: "forge tokens",
+			data: map[string]interface{}{
+				"github_token": " secret:github ",
+				"gitlab_token": "glpat-plain",
+			},
+			validate: func(t *testing.T, cfg *AppConfig) {
+				assert.Equal(t, "secret:github", cfg.GitHubToken)
+				assert.Equal(t, "glpat-plain", cfg.GitLabToken)
+			},
+		},
+		{
+			name: "pr_reviewers",
+			data: map[string]interface{}{
+				"pr_reviewers": []interface{}{"alice", " bob ", ""},
+			},
+			validate: func(t *testing.T, cfg *AppConfig) {
+				assert.Equal(t, []string{"alice", "bob"}, cfg.PRReviewers)
+			},
+		},
 		{
 			name: "worktree_dir",
 			data: map[string]interface{}{
@@ -500,6 +839,15 @@ func TestParseConfig(t *testing.T) {
 				assert.True(t, cfg.SearchAutoSelect)
 			},
 		},
+		{
+			name: "vim_motions true",
+			data: map[string]interface{}{
+				"vim_motions": "true",
+			},
+			validate: func(t *testing.T, cfg *AppConfig) {
+				assert.True(t, cfg.VimMotions)
+			},
+		},
 		{
 			name: "show_icons false",
 			data: map[string]interface{}{
@@ -818,6 +1166,44 @@ func TestParseConfig(t *testing.T) {
 				assert.Equal(t, "echo test", cfg.BranchNameScript)
 			},
 		},
+		{
+			name: "branch_name_script_timeout",
+			data: map[string]interface{}{
+				"branch_name_script_timeout": 5,
+			},
+			validate: func(t *testing.T, cfg *AppConfig) {
+				assert.Equal(t, 5, cfg.BranchNameScriptTimeout)
+			},
+		},
+		{
+			name: "branch_name_script_timeout ignores non-positive values",
+			data: map[string]interface{}{
+				"branch_name_script_timeout": 0,
+			},
+			validate: func(t *testing.T, cfg *AppConfig) {
+				assert.Equal(t, 30, cfg.BranchNameScriptTimeout)
+			},
+		},
+		{
+			name: "owner_identity is trimmed",
+			data: map[string]interface{}{
+				"owner_identity": "  alice@pairing  ",
+			},
+			validate: func(t *testing.T, cfg *AppConfig) {
+				assert.Equal(t, "alice@pairing", cfg.OwnerIdentity)
+			},
+		},
+		{
+			name: "ssh remote is trimmed",
+			data: map[string]interface{}{
+				"ssh_host": " me@devbox ",
+				"ssh_path": " ~/src/app ",
+			},
+			validate: func(t *testing.T, cfg *AppConfig) {
+				assert.Equal(t, "me@devbox", cfg.SSHHost)
+				assert.Equal(t, "~/src/app", cfg.SSHPath)
+			},
+		},
 		{
 			name: "editor config is trimmed",
 			data: map[string]interface{}{
@@ -1068,6 +1454,13 @@ func TestLoadRepoConfig(t *testing.T) {
   - pwd
 terminate_commands:
   - echo "terminate"
+info_template: |
+  {{label "Branch:"}} {{.Branch}}
+services:
+  web: npm run dev
+  api: go run ./cmd/api
+  broken: ""
+service_ports: [3000-3010, 5173, "8080", 70000, "9-1"]
 `
 		err := os.WriteFile(wtPath, []byte(yamlContent), 0o600)
 		require.NoError(t, err)
@@ -1079,6 +1472,9 @@ terminate_commands:
 		assert.Equal(t, wtPath, cfg.Path)
 		assert.Equal(t, []string{"echo \"init\"", "pwd"}, cfg.InitCommands)
 		assert.Equal(t, []string{"echo \"terminate\""}, cfg.TerminateCommands)
+		assert.Equal(t, `{{label "Branch:"}} {{.Branch}}`, cfg.InfoTemplate)
+		assert.Equal(t, []*Service{{Name: "api", Command: "go run ./cmd/api"}, {Name: "web", Command: "npm run dev"}}, cfg.Services)
+		assert.Equal(t, []PortRange{{First: 3000, Last: 3010}, {First: 5173, Last: 5173}, {First: 8080, Last: 8080}}, cfg.ServicePorts)
 	})
 
 	t.Run("invalid YAML in .wt file", func(t *testing.T) {
@@ -1193,6 +1589,12 @@ terminate_commands:
 	})
 }
 
+func TestParseConfigRefreshIntervalDuration(t *testing.T) {
+	assert.Equal(t, 30, parseConfig(map[string]any{"refresh_interval": "30s"}).RefreshIntervalSeconds)
+	assert.Equal(t, 120, parseConfig(map[string]any{"refresh_interval": "2m"}).RefreshIntervalSeconds)
+	assert.Equal(t, 15, parseConfig(map[string]any{"refresh_interval": 15}).RefreshIntervalSeconds)
+}
+
 func TestParseCustomCommands(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -1724,6 +2126,77 @@ other_field: preserved # Inline comment
 	assert.Contains(t, string(data), "theme: nord")
 }
 
+func TestSaveMacro(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	initialContent := `# LazyWorktree Config
+theme: dracula # Inline comment
+custom_commands:
+    T:
+        command: make test
+`
+	require.NoError(t, os.WriteFile(configPath, []byte(initialContent), 0o600))
+
+	cfg := DefaultConfig()
+	cfg.ConfigPath = configPath
+	macro := &Macro{Name: "Test and push", Keys: []string{"j", "T", "type:make test: all", " ", "enter", "P"}}
+	require.NoError(t, SaveMacro(cfg, "ctrl+t", macro))
+	require.NoError(t, SaveMacro(cfg, "alt+m", &Macro{Name: "Down", Keys: []string{"j"}}))
+	require.NoError(t, SaveMacro(cfg, "ctrl+t", macro))
+	assert.Same(t, macro, cfg.Macros["ctrl+t"])
+
+	// #nosec G304
+	data, err := os.ReadFile(configPath)
+	require.NoError(t, err)
+	content := string(data)
+	assert.Contains(t, content, "# LazyWorktree Config")
+	assert.Contains(t, content, "theme: dracula # Inline comment")
+	assert.Equal(t, 1, strings.Count(content, "ctrl+t:"))
+
+	loaded, err := LoadConfig(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, "make test", loaded.CustomCommands["T"].Command)
+	require.Len(t, loaded.Macros, 2)
+	assert.Equal(t, macro, loaded.Macros["ctrl+t"])
+	assert.Equal(t, []string{"j"}, loaded.Macros["alt+m"].Keys)
+
+	cfg = DefaultConfig()
+	cfg.ConfigPath = filepath.Join(t.TempDir(), "new", "config.yaml")
+	require.NoError(t, SaveMacro(cfg, "ctrl+t", macro))
+	loaded, err = LoadConfig(cfg.ConfigPath)
+	require.NoError(t, err)
+	assert.Equal(t, macro, loaded.Macros["ctrl+t"])
+}
+
+func TestParseMacros(t *testing.T) {
+	cfg := parseConfig(map[string]any{
+		"macros": map[string]any{
+			"ctrl+t": map[string]any{"keys": []any{"S", "P"}},
+			"ctrl+e": map[string]any{"name": "Empty", "keys": []any{}},
+			"ctrl+b": "not a macro",
+		},
+	})
+	require.Len(t, cfg.Macros, 1)
+	assert.Equal(t, &Macro{Name: "ctrl+t", Keys: []string{"S", "P"}}, cfg.Macros["ctrl+t"])
+}
+
+func TestParseArtifactSync(t *testing.T) {
+	cfg := parseConfig(map[string]any{
+		"artifact_sync": []any{
+			"gen/proto/",
+			map[string]any{"path": "web/dist", "mode": "link"},
+			map[string]any{"path": "../outside"},
+			"/abs",
+			"gen/proto",
+			"",
+			42,
+		},
+	})
+	assert.Equal(t, []*ArtifactSync{
+		{Path: "gen/proto"},
+		{Path: "web/dist", Link: true},
+	}, cfg.ArtifactSync)
+}
+
 func TestIsPathWithin(t *testing.T) {
 	base := filepath.Join(t.TempDir(), "base")
 	inside := filepath.Join(base, "child")
@@ -1910,6 +2383,8 @@ func TestApplyCLIOverrides(t *testing.T) {
 		"lw.theme=gruvbox-dark",
 		"lw.auto_fetch_prs=true",
 		"lw.max_diff_chars=500000",
+		"lw.no_animations=true",
+		"lw.self_update=false",
 	}
 
 	err := cfg.ApplyCLIOverrides(overrides)
@@ -1918,6 +2393,8 @@ func TestApplyCLIOverrides(t *testing.T) {
 	assert.Equal(t, "gruvbox-dark", cfg.Theme)
 	assert.True(t, cfg.AutoFetchPRs)
 	assert.Equal(t, 500000, cfg.MaxDiffChars)
+	assert.True(t, cfg.NoAnimations)
+	assert.False(t, cfg.SelfUpdate)
 }
 
 func TestApplyCLIOverridesMultiValue(t *testing.T) {
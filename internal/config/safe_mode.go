@@ -0,0 +1,34 @@
+package config
+
+// ApplySafeMode turns the configuration into safe mode, for telling a
+// problem with the user's hooks and commands apart from one in lazyworktree
+// itself. Safe mode is read-only and drops every hook, custom command and
+// script, the delta pager, the team file and the forge tokens, leaving git
+// as the only program run.
+func (c *AppConfig) ApplySafeMode() {
+	c.SafeMode = true
+	c.ReadOnly = true
+
+	c.InitCommands = nil
+	c.TerminateCommands = nil
+	c.CustomCommands = map[string]*CustomCommand{}
+	c.CustomCreateMenus = nil
+	c.HealthChecks = nil
+	c.BranchNameScript = ""
+	c.DeploymentScript = ""
+	c.OverviewCommand = ""
+
+	c.GitPager = ""
+	c.AutoFetchPRs = false
+	c.ShowDeployments = false
+	c.GitHubToken = ""
+	c.GitLabToken = ""
+	c.TeamConfig = ""
+
+	c.MaintenanceIntervals = nil
+	c.DockerCompose = false
+	c.AllowEnvTools = false
+	c.SuggestBootstrap = false
+	c.ControlSocket = false
+	c.SelfUpdate = false
+}
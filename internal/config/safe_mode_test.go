@@ -0,0 +1,33 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplySafeMode(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.InitCommands = []string{"make setup"}
+	cfg.TerminateCommands = []string{"make clean"}
+	cfg.CustomCommands["x"] = &CustomCommand{Command: "echo hi"}
+	cfg.BranchNameScript = "suggest-branch"
+	cfg.AutoFetchPRs = true
+	cfg.GitHubToken = "secret:github"
+	cfg.TeamConfig = "https://example.com/team.wt"
+	cfg.ControlSocket = true
+
+	cfg.ApplySafeMode()
+
+	assert.True(t, cfg.SafeMode)
+	assert.True(t, cfg.ReadOnly, "safe mode is read-only")
+	assert.Empty(t, cfg.InitCommands)
+	assert.Empty(t, cfg.TerminateCommands)
+	assert.Empty(t, cfg.CustomCommands)
+	assert.Empty(t, cfg.BranchNameScript)
+	assert.Empty(t, cfg.GitPager, "delta never runs")
+	assert.False(t, cfg.AutoFetchPRs)
+	assert.Empty(t, cfg.GitHubToken, "no secret is looked up")
+	assert.Empty(t, cfg.TeamConfig)
+	assert.False(t, cfg.ControlSocket)
+}
@@ -0,0 +1,43 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/chmouel/lazyworktree/internal/secrets"
+)
+
+// secretBackend returns the credential store used to resolve references;
+// tests replace it.
+var secretBackend = secrets.Default
+
+// ResolveSecrets replaces "secret:<name>" token references with the values
+// held in the operating system's credential store. A token that cannot be
+// resolved is cleared, so the forge CLIs fall back to their own login, and
+// reported in the returned error.
+func (c *AppConfig) ResolveSecrets(ctx context.Context) error {
+	var backend secrets.Backend
+	var errs []error
+	for _, field := range []struct {
+		key   string
+		value *string
+	}{
+		{key: "github_token", value: &c.GitHubToken},
+		{key: "gitlab_token", value: &c.GitLabToken},
+	} {
+		if _, ok := secrets.ParseRef(*field.value); !ok {
+			continue
+		}
+		if backend == nil {
+			backend = secretBackend()
+		}
+		resolved, err := secrets.Resolve(ctx, backend, *field.value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", field.key, err))
+			resolved = ""
+		}
+		*field.value = resolved
+	}
+	return errors.Join(errs...)
+}
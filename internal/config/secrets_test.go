@@ -0,0 +1,45 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/chmouel/lazyworktree/internal/secrets"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeSecrets map[string]string
+
+func (f fakeSecrets) Name() string { return "fake store" }
+
+func (f fakeSecrets) Get(_ context.Context, name string) (string, error) {
+	if v, ok := f[name]; ok {
+		return v, nil
+	}
+	return "", errors.New("no such secret")
+}
+
+func (f fakeSecrets) Set(context.Context, string, string) error { return nil }
+
+func TestResolveSecrets(t *testing.T) {
+	orig := secretBackend
+	t.Cleanup(func() { secretBackend = orig })
+	lookups := 0
+	secretBackend = func() secrets.Backend {
+		lookups++
+		return fakeSecrets{"github": "ghp_secret"}
+	}
+
+	cfg := &AppConfig{GitHubToken: "secret:github", GitLabToken: "secret:gitlab"}
+	err := cfg.ResolveSecrets(context.Background())
+	assert.ErrorContains(t, err, `gitlab_token: failed to read secret "gitlab" from fake store`)
+	assert.Equal(t, "ghp_secret", cfg.GitHubToken)
+	assert.Empty(t, cfg.GitLabToken, "an unresolved reference is never handed to glab")
+
+	lookups = 0
+	cfg = &AppConfig{GitHubToken: "ghp_plain"}
+	assert.NoError(t, cfg.ResolveSecrets(context.Background()))
+	assert.Equal(t, "ghp_plain", cfg.GitHubToken)
+	assert.Zero(t, lookups, "the credential store is only consulted for references")
+}
@@ -0,0 +1,86 @@
+package config
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// TeamConfigFilename is the name the synchronised team configuration is kept
+// under, inside the repository's git directory.
+const TeamConfigFilename = "team.wt"
+
+// TeamConfigPath returns where the team configuration for the repository
+// whose main worktree is repoPath is kept. It lives in the git directory so
+// it is never committed and is shared by every worktree.
+func TeamConfigPath(repoPath string) string {
+	return filepath.Join(commonGitDir(repoPath), "lazyworktree", TeamConfigFilename)
+}
+
+// commonGitDir returns the git directory shared by the worktrees of the
+// repository at repoPath. A .git file, left by --separate-git-dir, points
+// elsewhere, so git is asked unless .git is the directory itself.
+func commonGitDir(repoPath string) string {
+	gitDir := filepath.Join(repoPath, ".git")
+	if info, err := os.Stat(gitDir); err == nil && info.IsDir() {
+		return gitDir
+	}
+	cmd := exec.Command("git", "rev-parse", "--path-format=absolute", "--git-common-dir")
+	cmd.Dir = repoPath
+	if out, err := cmd.Output(); err == nil {
+		if dir := strings.TrimSpace(string(out)); dir != "" {
+			return dir
+		}
+	}
+	// A bare repository is its own git directory.
+	return repoPath
+}
+
+// mergeRepoConfig lays local over team: each key the local .wt sets replaces
+// the team's value for it. Either may be nil.
+func mergeRepoConfig(team, local *RepoConfig) *RepoConfig {
+	if team == nil {
+		return local
+	}
+	merged := &RepoConfig{
+		InitCommands:      team.InitCommands,
+		TerminateCommands: team.TerminateCommands,
+		InfoTemplate:      team.InfoTemplate,
+		Services:          team.Services,
+		ServicePorts:      team.ServicePorts,
+		TeamPath:          team.Path,
+	}
+	if local == nil {
+		return merged
+	}
+	merged.Path = local.Path
+	if len(local.InitCommands) > 0 {
+		merged.InitCommands = local.InitCommands
+	}
+	if len(local.TerminateCommands) > 0 {
+		merged.TerminateCommands = local.TerminateCommands
+	}
+	if local.InfoTemplate != "" {
+		merged.InfoTemplate = local.InfoTemplate
+	}
+	if len(local.Services) > 0 {
+		merged.Services = local.Services
+	}
+	if len(local.ServicePorts) > 0 {
+		merged.ServicePorts = local.ServicePorts
+	}
+	return merged
+}
+
+// TrustPaths returns the files whose commands the configuration runs, each
+// of which must be trusted first.
+func (c *RepoConfig) TrustPaths() []string {
+	var paths []string
+	for _, path := range []string{c.Path, c.TeamPath} {
+		if path != "" {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
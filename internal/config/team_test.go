@@ -0,0 +1,80 @@
+package config
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTeamConfig(t *testing.T, repoPath, content string) string {
+	t.Helper()
+	path := TeamConfigPath(repoPath)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o750))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestTeamConfigPath(t *testing.T) {
+	repo := t.TempDir()
+	assert.Equal(t, filepath.Join(repo, "lazyworktree", TeamConfigFilename), TeamConfigPath(repo), "bare repositories keep it at the top")
+
+	require.NoError(t, os.Mkdir(filepath.Join(repo, ".git"), 0o750))
+	assert.Equal(t, filepath.Join(repo, ".git", "lazyworktree", TeamConfigFilename), TeamConfigPath(repo))
+}
+
+func TestTeamConfigPathSeparateGitDir(t *testing.T) {
+	repo := t.TempDir()
+	gitDir := filepath.Join(t.TempDir(), "repo.git")
+	cmd := exec.Command("git", "init", "-q", "--separate-git-dir", gitDir, repo)
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+
+	want, err := filepath.EvalSymlinks(gitDir)
+	require.NoError(t, err)
+	got, err := filepath.EvalSymlinks(filepath.Dir(filepath.Dir(TeamConfigPath(repo))))
+	require.NoError(t, err)
+	assert.Equal(t, want, got, "the team configuration must stay out of the working tree")
+}
+
+func TestLoadRepoConfigMergesTeamConfig(t *testing.T) {
+	t.Run("team only", func(t *testing.T) {
+		repo := t.TempDir()
+		require.NoError(t, os.Mkdir(filepath.Join(repo, ".git"), 0o750))
+		teamPath := writeTeamConfig(t, repo, "init_commands:\n  - npm ci\n")
+
+		cfg, path, err := LoadRepoConfig(repo)
+		require.NoError(t, err)
+		require.NotNil(t, cfg)
+		assert.Equal(t, filepath.Join(repo, ".wt"), path)
+		assert.Empty(t, cfg.Path)
+		assert.Equal(t, teamPath, cfg.TeamPath)
+		assert.Equal(t, []string{"npm ci"}, cfg.InitCommands)
+		assert.Equal(t, []string{teamPath}, cfg.TrustPaths())
+	})
+
+	t.Run("local keys win", func(t *testing.T) {
+		repo := t.TempDir()
+		require.NoError(t, os.Mkdir(filepath.Join(repo, ".git"), 0o750))
+		teamPath := writeTeamConfig(t, repo, "init_commands:\n  - npm ci\nterminate_commands:\n  - make clean\n")
+		wtPath := filepath.Join(repo, ".wt")
+		require.NoError(t, os.WriteFile(wtPath, []byte("init_commands:\n  - make setup\n"), 0o600))
+
+		cfg, _, err := LoadRepoConfig(repo)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"make setup"}, cfg.InitCommands)
+		assert.Equal(t, []string{"make clean"}, cfg.TerminateCommands)
+		assert.Equal(t, []string{wtPath, teamPath}, cfg.TrustPaths())
+	})
+
+	t.Run("invalid team file", func(t *testing.T) {
+		repo := t.TempDir()
+		writeTeamConfig(t, repo, "init_commands: [[[")
+
+		_, _, err := LoadRepoConfig(repo)
+		assert.ErrorContains(t, err, "team configuration")
+	})
+}
@@ -0,0 +1,211 @@
+// Package control lets scripts and editor plugins drive a running
+// lazyworktree through a Unix socket. Each connection carries one JSON
+// request per line and receives one JSON response per line in return.
+package control
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/chmouel/lazyworktree/internal/events"
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+// dialTimeout bounds connecting to a socket, including the check for an
+// instance already listening.
+const dialTimeout = time.Second
+
+// maxRequestSize bounds a single request line.
+const maxRequestSize = 64 * 1024
+
+// ErrNotRunning is returned when no instance is listening on the socket.
+var ErrNotRunning = errors.New("no lazyworktree instance is listening")
+
+// Request is one command for the running instance, such as
+// {"command":"select","args":["feature-x"]}.
+type Request struct {
+	Command string   `json:"command"`
+	Args    []string `json:"args,omitempty"`
+}
+
+// Response reports the outcome of a request. Data carries the structured
+// answer of queries such as list, for editor plugins.
+type Response struct {
+	OK      bool            `json:"ok"`
+	Message string          `json:"message,omitempty"`
+	Error   string          `json:"error,omitempty"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+// Worktree describes a worktree in the answer to list.
+type Worktree struct {
+	Path         string     `json:"path"`
+	Name         string     `json:"name"`
+	Branch       string     `json:"branch,omitempty"`
+	Main         bool       `json:"main,omitempty"`
+	Selected     bool       `json:"selected,omitempty"`
+	Dirty        bool       `json:"dirty,omitempty"`
+	Ahead        int        `json:"ahead,omitempty"`
+	Behind       int        `json:"behind,omitempty"`
+	LastSwitched int64      `json:"last_switched,omitempty"`
+	PR           *events.PR `json:"pr,omitempty"`
+}
+
+// NewWorktree describes wt for an answer, marking the selected one.
+func NewWorktree(wt *models.WorktreeInfo, selected bool) Worktree {
+	return Worktree{
+		Path:         wt.Path,
+		Name:         filepath.Base(wt.Path),
+		Branch:       wt.Branch,
+		Main:         wt.IsMain,
+		Selected:     selected,
+		Dirty:        wt.Dirty,
+		Ahead:        wt.Ahead,
+		Behind:       wt.Behind,
+		LastSwitched: wt.LastSwitchedTS,
+		PR:           events.NewPR(wt.PR),
+	}
+}
+
+// Counterpart answers counterpart: the same file in another worktree.
+type Counterpart struct {
+	Path     string `json:"path"`
+	Worktree string `json:"worktree"`
+	Exists   bool   `json:"exists"`
+}
+
+// Handler answers a request. It may be called from several goroutines.
+type Handler func(Request) Response
+
+// Server accepts control connections on a Unix socket.
+type Server struct {
+	path     string
+	listener net.Listener
+	handler  Handler
+	wg       sync.WaitGroup
+
+	mu    sync.Mutex
+	conns map[net.Conn]struct{}
+}
+
+// Listen serves handler on the socket at path. A socket left behind by an
+// instance that has exited is replaced, but one still answering is not.
+func Listen(path string, handler Handler) (*Server, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create the socket directory: %w", err)
+	}
+	if conn, err := net.DialTimeout("unix", path, dialTimeout); err == nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("another lazyworktree instance is already listening on %s", path)
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to remove the stale socket: %w", err)
+	}
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", path, err)
+	}
+	// Only the owner may drive the instance.
+	if err := os.Chmod(path, 0o600); err != nil {
+		_ = listener.Close()
+		return nil, fmt.Errorf("failed to restrict %s: %w", path, err)
+	}
+	s := &Server{path: path, listener: listener, handler: handler, conns: make(map[net.Conn]struct{})}
+	s.wg.Add(1)
+	go s.serve()
+	return s, nil
+}
+
+// Path returns the socket path.
+func (s *Server) Path() string {
+	return s.path
+}
+
+func (s *Server) serve() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		s.conns[conn] = struct{}{}
+		s.mu.Unlock()
+		s.wg.Add(1)
+		go s.handle(conn)
+	}
+}
+
+func (s *Server) handle(conn net.Conn) {
+	defer s.wg.Done()
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+		_ = conn.Close()
+	}()
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 0, 4096), maxRequestSize)
+	encoder := json.NewEncoder(conn)
+	for scanner.Scan() {
+		var req Request
+		var resp Response
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			resp = Response{Error: fmt.Sprintf("invalid request: %v", err)}
+		} else {
+			resp = s.handler(req)
+		}
+		if err := encoder.Encode(resp); err != nil {
+			return
+		}
+	}
+}
+
+// Close stops accepting connections, closes those open and removes the
+// socket.
+func (s *Server) Close() error {
+	if s == nil {
+		return nil
+	}
+	err := s.listener.Close()
+	s.mu.Lock()
+	for conn := range s.conns {
+		_ = conn.Close()
+	}
+	s.mu.Unlock()
+	s.wg.Wait()
+	if removeErr := os.Remove(s.path); removeErr != nil && !os.IsNotExist(removeErr) && err == nil {
+		err = removeErr
+	}
+	return err
+}
+
+// Send delivers req to the instance listening on path and returns its
+// response.
+func Send(ctx context.Context, path string, req Request) (Response, error) {
+	dialer := net.Dialer{Timeout: dialTimeout}
+	conn, err := dialer.DialContext(ctx, "unix", path)
+	if err != nil {
+		return Response{}, fmt.Errorf("%w on %s", ErrNotRunning, path)
+	}
+	defer func() { _ = conn.Close() }()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return Response{}, fmt.Errorf("failed to send the request: %w", err)
+	}
+	var resp Response
+	if err := json.NewDecoder(conn).Decode(&resp); err != nil {
+		return Response{}, fmt.Errorf("failed to read the response: %w", err)
+	}
+	return resp, nil
+}
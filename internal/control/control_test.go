@@ -0,0 +1,123 @@
+package control
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// socketPath returns a short socket path, as Unix sockets are limited to
+// about a hundred bytes.
+func socketPath(t *testing.T) string {
+	t.Helper()
+	dir, err := os.MkdirTemp("", "lwctl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+	return filepath.Join(dir, "control.sock")
+}
+
+func echo(req Request) Response {
+	if req.Command != "select" {
+		return Response{Error: "unknown command " + req.Command}
+	}
+	return Response{OK: true, Message: "selected " + strings.Join(req.Args, " ")}
+}
+
+func TestSendAndListen(t *testing.T) {
+	path := socketPath(t)
+	server, err := Listen(path, echo)
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	resp, err := Send(ctx, path, Request{Command: "select", Args: []string{"feature"}})
+	if err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if !resp.OK || resp.Message != "selected feature" {
+		t.Fatalf("unexpected response %+v", resp)
+	}
+	resp, err = Send(ctx, path, Request{Command: "explode"})
+	if err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if resp.OK || resp.Error != "unknown command explode" {
+		t.Fatalf("expected an error response, got %+v", resp)
+	}
+
+	if _, err := Listen(path, echo); err == nil || !strings.Contains(err.Error(), "already listening") {
+		t.Fatalf("expected a second listener to be refused, got %v", err)
+	}
+
+	if err := server.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("expected the socket to be removed, got %v", err)
+	}
+	if _, err := Send(ctx, path, Request{Command: "select"}); !errors.Is(err, ErrNotRunning) {
+		t.Fatalf("expected ErrNotRunning, got %v", err)
+	}
+}
+
+func TestListenReplacesStaleSocket(t *testing.T) {
+	path := socketPath(t)
+	if err := os.WriteFile(path, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	server, err := Listen(path, echo)
+	if err != nil {
+		t.Fatalf("expected the stale socket to be replaced: %v", err)
+	}
+	defer func() { _ = server.Close() }()
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode()&os.ModeSocket == 0 {
+		t.Fatalf("expected a socket, got mode %v", info.Mode())
+	}
+}
+
+func TestServerRejectsInvalidRequests(t *testing.T) {
+	path := socketPath(t)
+	server, err := Listen(path, echo)
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer func() { _ = server.Close() }()
+
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = conn.Close() }()
+	if _, err := conn.Write([]byte("select feature\n{\"command\":\"select\",\"args\":[\"a\"]}\n")); err != nil {
+		t.Fatal(err)
+	}
+	reader := bufio.NewReader(conn)
+	first, err := reader.ReadString('\n')
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(first, "invalid request") {
+		t.Fatalf("expected an invalid request error, got %q", first)
+	}
+	second, err := reader.ReadString('\n')
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(second, `"ok":true`) {
+		t.Fatalf("expected the connection to keep serving, got %q", second)
+	}
+}
@@ -0,0 +1,220 @@
+// Package crash turns panics into a restored terminal and a crash report.
+package crash
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/chmouel/lazyworktree/internal/log"
+	"github.com/chmouel/lazyworktree/internal/utils"
+)
+
+const (
+	// logTailLines is how many recent debug log lines a report includes.
+	logTailLines = 50
+	dirPerms     = 0o750
+	filePerms    = 0o600
+)
+
+// Report describes a panic and the state of the application at the time.
+type Report struct {
+	Time    time.Time
+	Version string
+	Panic   string
+	Stack   string
+	LogTail []string
+	State   string
+}
+
+// String renders the report as plain text, ready to attach to a bug report.
+func (r *Report) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "lazyworktree crash report\n\n")
+	fmt.Fprintf(&b, "Time:     %s\n", r.Time.Format(time.RFC3339))
+	fmt.Fprintf(&b, "Version:  %s\n", r.Version)
+	fmt.Fprintf(&b, "Go:       %s %s/%s\n", runtime.Version(), runtime.GOOS, runtime.GOARCH)
+	fmt.Fprintf(&b, "Panic:    %s\n", r.Panic)
+	if r.State != "" {
+		fmt.Fprintf(&b, "\nState:\n%s\n", strings.TrimRight(r.State, "\n"))
+	}
+	fmt.Fprintf(&b, "\nStack:\n%s\n", strings.TrimRight(r.Stack, "\n"))
+	if len(r.LogTail) > 0 {
+		fmt.Fprintf(&b, "\nRecent debug log:\n%s\n", strings.Join(r.LogTail, "\n"))
+	}
+	return b.String()
+}
+
+// Recorder keeps the first panic seen by the guarded model, its commands or
+// the goroutines deferring Recover.
+type Recorder struct {
+	version string
+	state   func() string
+	stop    func()
+
+	mu     sync.Mutex
+	report *Report
+}
+
+// active is the recorder Recover reports to.
+var active atomic.Pointer[Recorder]
+
+// NewRecorder returns a recorder stamping reports with version. state is
+// called once, when a panic is recorded, to summarise the application.
+func NewRecorder(version string, state func() string) *Recorder {
+	return &Recorder{version: version, state: state}
+}
+
+// Install makes r the target of Recover. stop is called after a goroutine
+// panic so the program can shut down and restore the terminal.
+func (r *Recorder) Install(stop func()) {
+	r.stop = stop
+	active.Store(r)
+}
+
+// Uninstall detaches r from Recover.
+func (r *Recorder) Uninstall() {
+	active.CompareAndSwap(r, nil)
+}
+
+// Report returns the recorded panic, or nil when nothing panicked.
+func (r *Recorder) Report() *Report {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.report
+}
+
+// record stores the first panic and ignores the ones that follow, which are
+// usually fallout from the first.
+func (r *Recorder) record(value any, stack []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.report != nil {
+		return
+	}
+	report := &Report{
+		Time:    time.Now(),
+		Version: r.version,
+		Panic:   fmt.Sprint(value),
+		Stack:   string(stack),
+		LogTail: log.Tail(logTailLines),
+	}
+	if r.state != nil {
+		report.State = safeState(r.state)
+	}
+	r.report = report
+	log.Printf("panic: %v", value)
+}
+
+// safeState summarises the application without letting a second panic from
+// an inconsistent model get in the way of the report.
+func safeState(state func() string) (summary string) {
+	defer func() {
+		if v := recover(); v != nil {
+			summary = fmt.Sprintf("unavailable: %v", v)
+		}
+	}()
+	return state()
+}
+
+// Recover records a panic in the current goroutine and asks the program to
+// stop. Defer it at the top of goroutines started outside Bubble Tea, whose
+// panics would otherwise leave the terminal in the alternate screen.
+func Recover() {
+	value := recover()
+	if value == nil {
+		return
+	}
+	r := active.Load()
+	if r == nil {
+		panic(value)
+	}
+	r.record(value, debug.Stack())
+	if r.stop != nil {
+		r.stop()
+	}
+}
+
+// Write saves the report under dir and returns its path.
+func Write(dir string, report *Report) (string, error) {
+	if err := os.MkdirAll(dir, dirPerms); err != nil {
+		return "", fmt.Errorf("failed to create crash directory: %w", err)
+	}
+	name := fmt.Sprintf("crash-%s.txt", report.Time.Format("20060102-150405"))
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(report.String()), filePerms); err != nil {
+		return "", fmt.Errorf("failed to write crash report: %w", err)
+	}
+	return path, nil
+}
+
+// Dir returns where crash reports are kept, under the lazyworktree cache
+// directory.
+func Dir() string {
+	return filepath.Join(utils.CacheDir(), "crashes")
+}
+
+// guard wraps a model so panics in Update, View and the commands they return
+// are recorded before Bubble Tea restores the terminal.
+type guard struct {
+	model    tea.Model
+	recorder *Recorder
+}
+
+// Guard wraps model so that any panic it raises is recorded by r. The panic
+// is re-raised afterwards, letting Bubble Tea shut down as usual.
+func Guard(model tea.Model, r *Recorder) tea.Model {
+	return &guard{model: model, recorder: r}
+}
+
+func (g *guard) Init() tea.Cmd {
+	defer g.rethrow()
+	return g.wrap(g.model.Init())
+}
+
+func (g *guard) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	defer g.rethrow()
+	model, cmd := g.model.Update(msg)
+	g.model = model
+	return g, g.wrap(cmd)
+}
+
+func (g *guard) View() string {
+	defer g.rethrow()
+	return g.model.View()
+}
+
+// rethrow records a panic and re-raises it.
+func (g *guard) rethrow() {
+	if value := recover(); value != nil {
+		g.recorder.record(value, debug.Stack())
+		panic(value)
+	}
+}
+
+// wrap guards cmd, and the commands of any batch it expands to, since
+// Bubble Tea runs them on their own goroutines.
+func (g *guard) wrap(cmd tea.Cmd) tea.Cmd {
+	if cmd == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		defer g.rethrow()
+		msg := cmd()
+		if batch, ok := msg.(tea.BatchMsg); ok {
+			wrapped := make(tea.BatchMsg, len(batch))
+			for i, c := range batch {
+				wrapped[i] = g.wrap(c)
+			}
+			return wrapped
+		}
+		return msg
+	}
+}
@@ -0,0 +1,127 @@
+package crash
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type panicModel struct {
+	updatePanics bool
+	viewPanics   bool
+	cmd          tea.Cmd
+}
+
+func (p *panicModel) Init() tea.Cmd { return nil }
+
+func (p *panicModel) Update(tea.Msg) (tea.Model, tea.Cmd) {
+	if p.updatePanics {
+		panic("update exploded")
+	}
+	return p, p.cmd
+}
+
+func (p *panicModel) View() string {
+	if p.viewPanics {
+		panic("view exploded")
+	}
+	return "ok"
+}
+
+func TestGuardRecordsUpdatePanic(t *testing.T) {
+	recorder := NewRecorder("v1.2.3", func() string { return "screen: none" })
+	model := Guard(&panicModel{updatePanics: true}, recorder)
+
+	assert.PanicsWithValue(t, "update exploded", func() { model.Update(nil) }, "the panic reaches Bubble Tea so it restores the terminal")
+
+	report := recorder.Report()
+	require.NotNil(t, report)
+	assert.Equal(t, "update exploded", report.Panic)
+	assert.Equal(t, "v1.2.3", report.Version)
+	assert.Equal(t, "screen: none", report.State)
+	assert.Contains(t, report.Stack, "panicModel")
+}
+
+func TestGuardKeepsFirstPanic(t *testing.T) {
+	recorder := NewRecorder("dev", nil)
+	model := Guard(&panicModel{viewPanics: true, updatePanics: true}, recorder)
+
+	assert.Panics(t, func() { _ = model.View() })
+	assert.Panics(t, func() { model.Update(nil) })
+	assert.Equal(t, "view exploded", recorder.Report().Panic)
+}
+
+func TestGuardWrapsBatchedCommands(t *testing.T) {
+	recorder := NewRecorder("dev", nil)
+	boom := func() tea.Msg { panic("command exploded") }
+	quiet := func() tea.Msg { return "done" }
+	model := Guard(&panicModel{cmd: tea.Batch(quiet, boom)}, recorder)
+
+	_, cmd := model.Update(nil)
+	batch, ok := cmd().(tea.BatchMsg)
+	require.True(t, ok)
+	require.Len(t, batch, 2)
+	assert.Equal(t, "done", batch[0]())
+	assert.Nil(t, recorder.Report())
+
+	assert.Panics(t, func() { batch[1]() })
+	assert.Equal(t, "command exploded", recorder.Report().Panic)
+}
+
+func TestRecover(t *testing.T) {
+	stopped := make(chan struct{})
+	recorder := NewRecorder("dev", func() string { panic("state exploded") })
+	recorder.Install(func() { close(stopped) })
+	t.Cleanup(recorder.Uninstall)
+
+	go func() {
+		defer Recover()
+		panic("worker exploded")
+	}()
+
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("expected Recover to stop the program")
+	}
+	report := recorder.Report()
+	require.NotNil(t, report)
+	assert.Equal(t, "worker exploded", report.Panic)
+	assert.Equal(t, "unavailable: state exploded", report.State)
+}
+
+func TestRecoverWithoutRecorderRepanics(t *testing.T) {
+	assert.PanicsWithValue(t, "loose", func() {
+		defer Recover()
+		panic("loose")
+	})
+}
+
+func TestWrite(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "crashes")
+	report := &Report{
+		Time:    time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC),
+		Version: "v1.0.0",
+		Panic:   "index out of range",
+		Stack:   "goroutine 1 [running]:\nmain.main()",
+		LogTail: []string{"refresh started", "refresh done"},
+		State:   "screen: help",
+	}
+
+	path, err := Write(dir, report)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "crash-20260304-050607.txt"), path)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	content := string(data)
+	for _, want := range []string{"Version:  v1.0.0", "Panic:    index out of range", "State:\nscreen: help", "Stack:\ngoroutine 1", "Recent debug log:\nrefresh started\nrefresh done"} {
+		assert.True(t, strings.Contains(content, want), "expected %q in report:\n%s", want, content)
+	}
+}
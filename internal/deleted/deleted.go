@@ -0,0 +1,101 @@
+// Package deleted remembers the worktrees deleted recently — their path,
+// branch, last commit and pull request — so they can be recreated from
+// where they were.
+package deleted
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/chmouel/lazyworktree/internal/models"
+	"github.com/chmouel/lazyworktree/internal/utils"
+)
+
+// Entry describes a deleted worktree.
+type Entry struct {
+	Path      string    `json:"path"`
+	Branch    string    `json:"branch,omitempty"` // empty for a detached HEAD
+	Commit    string    `json:"commit"`
+	PRNumber  int       `json:"pr_number,omitempty"`
+	PRURL     string    `json:"pr_url,omitempty"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+// List holds deleted worktrees, most recently deleted first.
+type List []Entry
+
+// mu serialises the read-modify-write of Record and Forget, as several
+// worktrees may be deleted at once.
+var mu sync.Mutex
+
+// Path returns where the deleted worktrees of the repository named
+// repoKey are listed.
+func Path(repoKey string) string {
+	return filepath.Join(utils.CacheDir(), repoKey, models.DeletedWorktreesFilename)
+}
+
+// Load reads the list at path; a missing or damaged file yields an empty
+// list.
+func Load(path string) List {
+	var list List
+	// #nosec G304 -- path is derived from the cache directory
+	if data, err := os.ReadFile(path); err == nil {
+		_ = json.Unmarshal(data, &list)
+	}
+	return list
+}
+
+// Save writes the list to path.
+func (l List) Save(path string) error {
+	if l == nil {
+		l = List{}
+	}
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+// Recent returns the entries deleted within keep of now.
+func (l List) Recent(now time.Time, keep time.Duration) List {
+	recent := List{}
+	for _, e := range l {
+		if now.Sub(e.DeletedAt) <= keep {
+			recent = append(recent, e)
+		}
+	}
+	return recent
+}
+
+// Record adds e to the list at path, replacing an earlier deletion of the
+// same path and dropping entries older than keep.
+func Record(path string, e Entry, keep time.Duration) error {
+	mu.Lock()
+	defer mu.Unlock()
+	if e.DeletedAt.IsZero() {
+		e.DeletedAt = time.Now()
+	}
+	list := slices.DeleteFunc(Load(path), func(old Entry) bool { return old.Path == e.Path })
+	return append(List{e}, list...).Recent(e.DeletedAt, keep).Save(path)
+}
+
+// Forget removes the entry for the worktree at worktreePath, once it has
+// been recreated.
+func Forget(path, worktreePath string) error {
+	mu.Lock()
+	defer mu.Unlock()
+	list := Load(path)
+	kept := slices.DeleteFunc(slices.Clone(list), func(e Entry) bool { return e.Path == worktreePath })
+	if len(kept) == len(list) {
+		return nil
+	}
+	return kept.Save(path)
+}
@@ -0,0 +1,38 @@
+package deleted
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordAndForget(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "repo", ".deleted.json")
+	assert.Empty(t, Load(path))
+
+	now := time.Now()
+	keep := 14 * 24 * time.Hour
+	require.NoError(t, Record(path, Entry{Path: "/wt/old", Branch: "old", Commit: "aaa", DeletedAt: now.Add(-20 * 24 * time.Hour)}, 30*24*time.Hour))
+	require.NoError(t, Record(path, Entry{Path: "/wt/a", Branch: "a", Commit: "bbb", DeletedAt: now.Add(-time.Hour)}, keep))
+	require.NoError(t, Record(path, Entry{Path: "/wt/b", Commit: "ccc", PRNumber: 7, PRURL: "https://example.com/pull/7", DeletedAt: now}, keep))
+
+	list := Load(path)
+	require.Len(t, list, 2, "entries older than the retention are dropped")
+	assert.Equal(t, "/wt/b", list[0].Path)
+	assert.Equal(t, 7, list[0].PRNumber)
+	assert.Equal(t, "/wt/a", list[1].Path)
+
+	require.NoError(t, Record(path, Entry{Path: "/wt/a", Branch: "a", Commit: "ddd", DeletedAt: now}, keep))
+	list = Load(path)
+	require.Len(t, list, 2, "deleting the same path again replaces its entry")
+	assert.Equal(t, "ddd", list[0].Commit)
+
+	require.NoError(t, Forget(path, "/wt/a"))
+	list = Load(path)
+	require.Len(t, list, 1)
+	assert.Equal(t, "/wt/b", list[0].Path)
+	assert.Empty(t, list.Recent(now.Add(15*24*time.Hour), keep))
+}
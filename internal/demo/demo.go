@@ -0,0 +1,231 @@
+// Package demo builds the throwaway repository lazyworktree --demo opens:
+// a small project with worktrees in different states, a local origin to
+// push to, and the pull requests and CI results a forge would report for
+// them.
+package demo
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+// RepoName is the repository the demo pretends to be, which also keys its
+// worktree directory.
+const RepoName = "demo/shop"
+
+// Repo is a demo repository ready to open.
+type Repo struct {
+	Root        string                       // Temporary directory holding everything; Close removes it
+	Main        string                       // Main worktree
+	WorktreeDir string                       // Worktree root to configure
+	PRs         map[string]*models.PRInfo    // Fabricated pull requests, by head branch
+	Checks      map[string][]*models.CICheck // Fabricated CI checks, by branch
+}
+
+// worktreeSpec describes a demo worktree and the state it is left in.
+type worktreeSpec struct {
+	branch   string
+	file     string
+	content  string
+	subject  string
+	age      time.Duration
+	push     bool   // Push the commit and track it
+	unpushed string // Subject of a further commit left unpushed
+	dirty    bool   // Leave uncommitted and untracked changes
+	merge    bool   // Merge into main, as its merged PR was
+}
+
+var worktreeSpecs = []worktreeSpec{
+	{branch: "checkout-flow", file: "shop/checkout.py", content: "def checkout(cart, payment):\n    payment.charge(cart.total())\n    return cart.clear()\n", subject: "Streamline the checkout flow", age: 50 * time.Hour, push: true},
+	{branch: "cart-rounding", file: "shop/cart.py", content: "class Cart:\n    def total(self):\n        return round(sum(item.price for item in self.items), 2)\n", subject: "Round cart totals to cents", age: 26 * time.Hour, push: true, dirty: true},
+	{branch: "search", file: "shop/search.py", content: "def search(products, query):\n    return [p for p in products if query.lower() in p.name.lower()]\n", subject: "Add product search", age: 5 * time.Hour, push: true, unpushed: "Rank search results by popularity"},
+	{branch: "update-deps", file: "requirements.lock", content: "flask==3.1.0\nrequests==2.32.3\n", subject: "Update dependencies", age: 96 * time.Hour, push: true, merge: true},
+	{branch: "spike-recommendations", file: "shop/recommend.py", content: "def recommend(customer):\n    return []  # TODO\n", subject: "Sketch product recommendations", age: 72 * time.Hour},
+}
+
+// Setup creates the demo repository in a new temporary directory.
+func Setup(ctx context.Context) (*Repo, error) {
+	root, err := os.MkdirTemp("", "lazyworktree-demo-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the demo directory: %w", err)
+	}
+	r := &Repo{
+		Root:        root,
+		Main:        filepath.Join(root, "shop"),
+		WorktreeDir: filepath.Join(root, "worktrees"),
+		PRs:         fabricatedPRs(),
+		Checks:      fabricatedChecks(),
+	}
+	for _, pr := range r.PRs {
+		pr.Checks = r.Checks[pr.Branch]
+	}
+	if err := r.build(ctx); err != nil {
+		_ = r.Close()
+		return nil, err
+	}
+	return r, nil
+}
+
+// Close removes the demo repository and everything created in it.
+func (r *Repo) Close() error {
+	return os.RemoveAll(r.Root)
+}
+
+// build lays out the main worktree and its history, the origin and the
+// worktrees.
+func (r *Repo) build(ctx context.Context) error {
+	main := r.Main
+	if err := os.MkdirAll(main, 0o750); err != nil {
+		return err
+	}
+	if err := r.git(ctx, main, 0, "init", "-b", "main"); err != nil {
+		return err
+	}
+	if err := r.commit(ctx, main, "README.md", "# Shop\n\nA small online shop, for trying lazyworktree out.\n", "Initial shop", 240*time.Hour); err != nil {
+		return err
+	}
+	if err := r.commit(ctx, main, "shop/cart.py", "class Cart:\n    def total(self):\n        return sum(item.price for item in self.items)\n", "Add cart totals", 168*time.Hour); err != nil {
+		return err
+	}
+
+	origin := filepath.Join(r.Root, "origin.git")
+	steps := [][]string{
+		{"clone", "--bare", "--quiet", main, origin},
+		{"remote", "add", "origin", origin},
+		{"fetch", "--quiet", "origin"},
+		{"branch", "--set-upstream-to=origin/main", "main"},
+		{"remote", "set-head", "origin", "main"},
+	}
+	for _, args := range steps {
+		if err := r.git(ctx, main, 0, args...); err != nil {
+			return err
+		}
+	}
+
+	for _, spec := range worktreeSpecs {
+		if err := r.addWorktree(ctx, spec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addWorktree creates the worktree spec describes under the worktree
+// directory, where lazyworktree would have.
+func (r *Repo) addWorktree(ctx context.Context, spec worktreeSpec) error {
+	path := filepath.Join(r.WorktreeDir, RepoName, spec.branch)
+	if err := r.git(ctx, r.Main, 0, "worktree", "add", "--quiet", "-b", spec.branch, path, "main"); err != nil {
+		return err
+	}
+	if err := r.commit(ctx, path, spec.file, spec.content, spec.subject, spec.age); err != nil {
+		return err
+	}
+	if spec.push {
+		if err := r.git(ctx, path, 0, "push", "--quiet", "-u", "origin", spec.branch); err != nil {
+			return err
+		}
+	}
+	if spec.unpushed != "" {
+		if err := r.commit(ctx, path, spec.file, spec.content+"\n# Most popular first.\n", spec.unpushed, time.Hour); err != nil {
+			return err
+		}
+	}
+	if spec.merge {
+		if err := r.git(ctx, r.Main, spec.age-time.Hour, "merge", "--quiet", "--no-ff", "-m", "Merge pull request #40 from update-deps", spec.branch); err != nil {
+			return err
+		}
+		if err := r.git(ctx, r.Main, 0, "push", "--quiet", "origin", "main"); err != nil {
+			return err
+		}
+	}
+	if spec.dirty {
+		if err := os.WriteFile(filepath.Join(path, spec.file), []byte(spec.content+"\n    def empty(self):\n        return not self.items\n"), 0o600); err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(path, "NOTES.md"), []byte("- Check rounding with three items at 0.10\n"), 0o600); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// commit writes content to file in dir and commits it, dated age ago.
+func (r *Repo) commit(ctx context.Context, dir, file, content, subject string, age time.Duration) error {
+	path := filepath.Join(dir, file)
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		return err
+	}
+	if err := r.git(ctx, dir, 0, "add", file); err != nil {
+		return err
+	}
+	return r.git(ctx, dir, age, "commit", "--quiet", "-m", subject)
+}
+
+// git runs a git command in dir, dating any commit age ago. The user's
+// signing and hooks are left out, as the demo's commits are fabricated.
+func (r *Repo) git(ctx context.Context, dir string, age time.Duration, args ...string) error {
+	args = append([]string{"-c", "commit.gpgsign=false", "-c", "core.hooksPath=" + filepath.Join(r.Root, "no-hooks")}, args...)
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	date := time.Now().Add(-age).Format(time.RFC3339)
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME=Alice Demo", "GIT_AUTHOR_EMAIL=alice@example.com", "GIT_AUTHOR_DATE="+date,
+		"GIT_COMMITTER_NAME=Alice Demo", "GIT_COMMITTER_EMAIL=alice@example.com", "GIT_COMMITTER_DATE="+date,
+	)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("git %s: %w: %s", strings.Join(args[4:], " "), err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// fabricatedPRs returns the pull requests of the demo's branches, one of
+// each state worth showing.
+func fabricatedPRs() map[string]*models.PRInfo {
+	pr := func(number int, branch, title, state, ci, review, author string) *models.PRInfo {
+		return &models.PRInfo{
+			Number:         number,
+			State:          state,
+			Title:          title,
+			Body:           "Part of the lazyworktree demo; nothing here reached a real forge.",
+			URL:            fmt.Sprintf("https://github.com/%s/pull/%d", RepoName, number),
+			Branch:         branch,
+			BaseBranch:     "main",
+			Author:         author,
+			CIStatus:       ci,
+			ReviewDecision: review,
+		}
+	}
+	prs := map[string]*models.PRInfo{
+		"checkout-flow": pr(42, "checkout-flow", "Streamline the checkout flow", "OPEN", "success", "APPROVED", "alice"),
+		"cart-rounding": pr(43, "cart-rounding", "Round cart totals to cents", "OPEN", "failure", "CHANGES_REQUESTED", "bob"),
+		"search":        pr(44, "search", "Add product search", "OPEN", "pending", "REVIEW_REQUIRED", "alice"),
+		"update-deps":   pr(40, "update-deps", "Update dependencies", "MERGED", "success", "APPROVED", "renovate[bot]"),
+	}
+	prs["search"].IsDraft = true
+	prs["update-deps"].AuthorIsBot = true
+	return prs
+}
+
+// fabricatedChecks returns the CI checks of the demo's pull requests.
+func fabricatedChecks() map[string][]*models.CICheck {
+	check := func(name, status, conclusion string) *models.CICheck {
+		return &models.CICheck{Name: name, Status: status, Conclusion: conclusion}
+	}
+	passed := []*models.CICheck{check("lint", "completed", "success"), check("test", "completed", "success"), check("build", "completed", "success")}
+	return map[string][]*models.CICheck{
+		"checkout-flow": passed,
+		"cart-rounding": {check("lint", "completed", "success"), check("test", "completed", "failure"), check("build", "completed", "success")},
+		"search":        {check("lint", "completed", "success"), check("test", "in_progress", ""), check("build", "queued", "")},
+		"update-deps":   passed,
+	}
+}
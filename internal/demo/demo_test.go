@@ -0,0 +1,67 @@
+package demo
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func gitOutput(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func TestSetup(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	r, err := Setup(context.Background())
+	if err != nil {
+		t.Fatalf("setup: %v", err)
+	}
+	t.Cleanup(func() { _ = r.Close() })
+
+	list := gitOutput(t, r.Main, "worktree", "list", "--porcelain")
+	for _, spec := range worktreeSpecs {
+		path := filepath.Join(r.WorktreeDir, RepoName, spec.branch)
+		if !strings.Contains(list, "worktree "+path+"\n") {
+			t.Fatalf("expected a worktree at %s, got\n%s", path, list)
+		}
+	}
+
+	search := filepath.Join(r.WorktreeDir, RepoName, "search")
+	if got := gitOutput(t, search, "rev-list", "--count", "@{upstream}..HEAD"); got != "1" {
+		t.Fatalf("expected search to be one commit ahead, got %s", got)
+	}
+	if got := gitOutput(t, filepath.Join(r.WorktreeDir, RepoName, "cart-rounding"), "status", "--porcelain"); !strings.Contains(got, "M shop/cart.py") || !strings.Contains(got, "?? NOTES.md") {
+		t.Fatalf("expected cart-rounding to have changes, got %q", got)
+	}
+	if got := gitOutput(t, r.Main, "branch", "--merged", "main", "--format=%(refname:short)"); !strings.Contains(got, "update-deps") {
+		t.Fatalf("expected update-deps merged, got %q", got)
+	}
+	if got := gitOutput(t, r.Main, "symbolic-ref", "--short", "refs/remotes/origin/HEAD"); got != "origin/main" {
+		t.Fatalf("expected origin/HEAD to name main, got %q", got)
+	}
+
+	for branch, pr := range r.PRs {
+		if pr.Branch != branch || (pr.State == "OPEN" && len(pr.Checks) == 0) {
+			t.Fatalf("expected the PR of %s to carry its checks, got %+v", branch, pr)
+		}
+	}
+
+	if err := r.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if _, err := os.Stat(r.Root); !os.IsNotExist(err) {
+		t.Fatalf("expected %s removed, got %v", r.Root, err)
+	}
+}
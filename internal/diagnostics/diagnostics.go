@@ -0,0 +1,167 @@
+// Package diagnostics gathers the version and environment details needed
+// when reporting a bug.
+package diagnostics
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"time"
+)
+
+// toolTimeout bounds each external --version call.
+const toolTimeout = 3 * time.Second
+
+const notFound = "not found"
+
+// Build describes the lazyworktree binary, as set by the release ldflags.
+type Build struct {
+	Version string
+	Commit  string
+	Date    string
+	BuiltBy string
+}
+
+// Resolve fills in the commit and builder from the Go build info when the
+// binary was not built by the release pipeline.
+func (b Build) Resolve() Build {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return b
+	}
+	if b.Commit == "" || b.Commit == "none" {
+		for _, setting := range info.Settings {
+			if setting.Key == "vcs.revision" {
+				b.Commit = setting.Value
+			}
+		}
+	}
+	if b.BuiltBy == "" || b.BuiltBy == "unknown" {
+		b.BuiltBy = info.GoVersion
+	}
+	return b
+}
+
+// Tool is an external program lazyworktree relies on.
+type Tool struct {
+	Name    string
+	Version string
+}
+
+// tools lists the programs reported, with the arguments printing a version.
+var tools = []struct {
+	name string
+	args []string
+}{
+	{name: "git", args: []string{"--version"}},
+	{name: "gh", args: []string{"--version"}},
+	{name: "glab", args: []string{"--version"}},
+	{name: "delta", args: []string{"--version"}},
+	{name: "lazygit", args: []string{"--version"}},
+}
+
+// LookPath finds executables; tests replace it.
+var LookPath = exec.LookPath
+
+// ToolVersions runs each known tool concurrently and returns their versions
+// in a stable order. Missing tools are reported as not found.
+func ToolVersions(ctx context.Context) []Tool {
+	result := make([]Tool, len(tools))
+	var wg sync.WaitGroup
+	for i, tool := range tools {
+		result[i] = Tool{Name: tool.name, Version: notFound}
+		path, err := LookPath(tool.name)
+		if err != nil {
+			continue
+		}
+		wg.Add(1)
+		go func(i int, path string, args []string) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(ctx, toolTimeout)
+			defer cancel()
+			// #nosec G204 -- the tools and their arguments are fixed above
+			out, err := exec.CommandContext(ctx, path, args...).Output()
+			if err != nil {
+				result[i].Version = fmt.Sprintf("error: %v", err)
+				return
+			}
+			result[i].Version = parseToolVersion(result[i].Name, string(out))
+		}(i, path, tool.args)
+	}
+	wg.Wait()
+	return result
+}
+
+// parseToolVersion trims a tool's --version output to its version line.
+func parseToolVersion(name, out string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(out), "\n")
+	line = strings.TrimSpace(line)
+	// lazygit prints "commit=…, build date=…, version=0.44.1, os=…".
+	if name == "lazygit" {
+		for field := range strings.SplitSeq(line, ",") {
+			if v, ok := strings.CutPrefix(strings.TrimSpace(field), "version="); ok {
+				return v
+			}
+		}
+	}
+	for _, prefix := range []string{name + " version ", name + " "} {
+		if v, ok := strings.CutPrefix(line, prefix); ok {
+			return v
+		}
+	}
+	if line == "" {
+		return "unknown"
+	}
+	return line
+}
+
+// Report is everything printed by --version --verbose and the about screen.
+type Report struct {
+	Build       Build
+	Tools       []Tool
+	Host        string
+	ConfigPath  string
+	WorktreeDir string
+}
+
+// String renders the report as aligned plain text, ready to paste into an
+// issue.
+func (r *Report) String() string {
+	configPath := r.ConfigPath
+	if configPath == "" {
+		configPath = "none (defaults)"
+	}
+	host := r.Host
+	if host == "" {
+		host = "not in a repository"
+	}
+	rows := [][2]string{
+		{"version", r.Build.Version},
+		{"commit", r.Build.Commit},
+		{"built at", r.Build.Date},
+		{"built by", r.Build.BuiltBy},
+		{"go", fmt.Sprintf("%s %s/%s", runtime.Version(), runtime.GOOS, runtime.GOARCH)},
+	}
+	for _, tool := range r.Tools {
+		rows = append(rows, [2]string{tool.Name, tool.Version})
+	}
+	rows = append(rows,
+		[2]string{"git host", host},
+		[2]string{"config", configPath},
+		[2]string{"worktree dir", r.WorktreeDir},
+	)
+
+	width := 0
+	for _, row := range rows {
+		width = max(width, len(row[0]))
+	}
+	var b strings.Builder
+	for _, row := range rows {
+		fmt.Fprintf(&b, "%-*s  %s\n", width+1, row[0]+":", row[1])
+	}
+	return b.String()
+}
@@ -0,0 +1,80 @@
+package diagnostics
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseToolVersion(t *testing.T) {
+	tests := []struct {
+		name, out, want string
+	}{
+		{name: "git", out: "git version 2.43.0\n", want: "2.43.0"},
+		{name: "gh", out: "gh version 2.40.1 (2023-12-13)\nhttps://github.com/cli/cli/releases/tag/v2.40.1\n", want: "2.40.1 (2023-12-13)"},
+		{name: "glab", out: "glab 1.36.0 (2024-01-19)\n", want: "1.36.0 (2024-01-19)"},
+		{name: "delta", out: "delta 0.16.5\n", want: "0.16.5"},
+		{name: "lazygit", out: "commit=abc, build date=2024-01-01, build source=binaryRelease, version=0.44.1, os=linux, arch=amd64\n", want: "0.44.1"},
+		{name: "other", out: "something else\n", want: "something else"},
+		{name: "git", out: "", want: "unknown"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, parseToolVersion(tt.name, tt.out), tt.out)
+	}
+}
+
+func TestToolVersions(t *testing.T) {
+	script := filepath.Join(t.TempDir(), "fake-git")
+	if err := os.WriteFile(script, []byte("#!/bin/sh\necho 'git version 9.9.9'\n"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	orig := LookPath
+	t.Cleanup(func() { LookPath = orig })
+	LookPath = func(name string) (string, error) {
+		if name == "git" {
+			return script, nil
+		}
+		return "", errors.New("missing")
+	}
+
+	got := ToolVersions(context.Background())
+	want := []Tool{
+		{Name: "git", Version: "9.9.9"},
+		{Name: "gh", Version: notFound},
+		{Name: "glab", Version: notFound},
+		{Name: "delta", Version: notFound},
+		{Name: "lazygit", Version: notFound},
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestReportString(t *testing.T) {
+	report := &Report{
+		Build:       Build{Version: "v1.2.3", Commit: "abc123", Date: "2026-01-01", BuiltBy: "goreleaser"},
+		Tools:       []Tool{{Name: "git", Version: "2.43.0"}},
+		Host:        "github",
+		WorktreeDir: "/home/me/worktrees",
+	}
+	out := report.String()
+	for _, want := range []string{
+		"version:       v1.2.3\n",
+		"commit:        abc123\n",
+		"git:           2.43.0\n",
+		"git host:      github\n",
+		"config:        none (defaults)\n",
+		"worktree dir:  /home/me/worktrees\n",
+	} {
+		assert.True(t, strings.Contains(out, want), "expected %q in:\n%s", want, out)
+	}
+}
+
+func TestBuildResolveKeepsReleaseValues(t *testing.T) {
+	b := Build{Version: "v1.0.0", Commit: "abc", Date: "today", BuiltBy: "goreleaser"}.Resolve()
+	assert.Equal(t, "abc", b.Commit)
+	assert.Equal(t, "goreleaser", b.BuiltBy)
+}
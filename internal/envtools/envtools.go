@@ -0,0 +1,135 @@
+// Package envtools approves the per-directory environment tools, direnv and
+// mise, for a new worktree, as each refuses to load a directory's
+// configuration until told to trust it.
+package envtools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// commandTimeout bounds each direnv or mise invocation.
+const commandTimeout = 30 * time.Second
+
+// Tool is an environment tool that must approve a directory's configuration.
+type Tool struct {
+	Name  string
+	files []string // Configuration files, relative to the worktree
+	allow []string // Command approving them, run in the worktree
+}
+
+// Tools are the supported environment tools.
+var Tools = []Tool{
+	{Name: "direnv", files: []string{".envrc"}, allow: []string{"direnv", "allow"}},
+	{Name: "mise", files: []string{"mise.toml", ".mise.toml", "mise.local.toml", ".mise.local.toml", ".config/mise.toml", ".config/mise/config.toml"}, allow: []string{"mise", "trust"}},
+}
+
+// Detect returns the tools that are installed and configured in dir.
+func Detect(dir string) []Tool {
+	var found []Tool
+	for _, tool := range Tools {
+		if _, err := exec.LookPath(tool.Name); err != nil {
+			continue
+		}
+		for _, name := range tool.files {
+			if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+				found = append(found, tool)
+				break
+			}
+		}
+	}
+	return found
+}
+
+// Allow approves the tool's configuration in dir.
+func Allow(ctx context.Context, dir string, tool Tool) error {
+	ctx, cancel := context.WithTimeout(ctx, commandTimeout)
+	defer cancel()
+	// #nosec G204 -- the command is one of the fixed approval commands
+	cmd := exec.CommandContext(ctx, tool.allow[0], tool.allow[1:]...)
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return fmt.Errorf("%s: %s", strings.Join(tool.allow, " "), msg)
+		}
+		return fmt.Errorf("%s: %w", strings.Join(tool.allow, " "), err)
+	}
+	return nil
+}
+
+// State reports whether the tool trusts its configuration in dir, as
+// "allowed", "blocked" or "denied" for direnv and "trusted" or "untrusted"
+// for mise. It is empty when the tool cannot tell.
+func State(ctx context.Context, dir string, tool Tool) string {
+	ctx, cancel := context.WithTimeout(ctx, commandTimeout)
+	defer cancel()
+	var args []string
+	switch tool.Name {
+	case "direnv":
+		args = []string{"direnv", "status", "--json"}
+	case "mise":
+		args = []string{"mise", "trust", "--show"}
+	default:
+		return ""
+	}
+	// #nosec G204 -- the command is one of the fixed status commands
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	if tool.Name == "direnv" {
+		return parseDirenvStatus(out)
+	}
+	return parseMiseTrust(string(out))
+}
+
+// parseDirenvStatus reads direnv status --json, whose foundRC.allowed is 0
+// when the .envrc is allowed, 1 when not yet and 2 when denied.
+func parseDirenvStatus(out []byte) string {
+	var status struct {
+		State struct {
+			FoundRC *struct {
+				Allowed int `json:"allowed"`
+			} `json:"foundRC"`
+		} `json:"state"`
+	}
+	if err := json.Unmarshal(out, &status); err != nil || status.State.FoundRC == nil {
+		return ""
+	}
+	switch status.State.FoundRC.Allowed {
+	case 0:
+		return "allowed"
+	case 2:
+		return "denied"
+	default:
+		return "blocked"
+	}
+}
+
+// parseMiseTrust reads mise trust --show, a "path: trusted" line per
+// configuration file; one untrusted file is enough to block the rest.
+func parseMiseTrust(out string) string {
+	state := ""
+	for _, line := range strings.Split(out, "\n") {
+		switch strings.TrimSpace(line[strings.LastIndexByte(line, ':')+1:]) {
+		case "untrusted":
+			return "untrusted"
+		case "trusted":
+			state = "trusted"
+		}
+	}
+	return state
+}
+
+// Approved reports whether a state from State lets the tool load.
+func Approved(state string) bool {
+	return state == "allowed" || state == "trusted"
+}
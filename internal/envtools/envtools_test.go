@@ -0,0 +1,72 @@
+package envtools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeTools puts direnv and mise scripts on the PATH that remember, in the
+// directory they run in, being asked to approve it.
+func fakeTools(t *testing.T) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("uses shell scripts")
+	}
+	bin := t.TempDir()
+	direnv := `#!/bin/sh
+case "$1" in
+allow) touch .direnv-allowed ;;
+status) if [ -e .direnv-allowed ]; then echo '{"state":{"foundRC":{"allowed":0}}}'; else echo '{"state":{"foundRC":{"allowed":1}}}'; fi ;;
+esac
+`
+	mise := `#!/bin/sh
+if [ "$2" = "--show" ]; then
+  if [ -e .mise-trusted ]; then echo "$PWD/mise.toml: trusted"; else echo "$PWD/mise.toml: untrusted"; fi
+else
+  echo "mise refuses" >&2; exit 1
+fi
+`
+	require.NoError(t, os.WriteFile(filepath.Join(bin, "direnv"), []byte(direnv), 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(bin, "mise"), []byte(mise), 0o700))
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func TestAllowAndState(t *testing.T) {
+	fakeTools(t)
+	dir := t.TempDir()
+	assert.Empty(t, Detect(dir))
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".envrc"), []byte("use mise\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "mise.toml"), []byte("[tools]\n"), 0o600))
+	tools := Detect(dir)
+	require.Len(t, tools, 2)
+	direnv, mise := tools[0], tools[1]
+
+	ctx := context.Background()
+	assert.Equal(t, "blocked", State(ctx, dir, direnv))
+	require.NoError(t, Allow(ctx, dir, direnv))
+	assert.Equal(t, "allowed", State(ctx, dir, direnv))
+
+	assert.Equal(t, "untrusted", State(ctx, dir, mise))
+	err := Allow(ctx, dir, mise)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "mise trust: mise refuses")
+}
+
+func TestParseStates(t *testing.T) {
+	assert.Equal(t, "denied", parseDirenvStatus([]byte(`{"state":{"foundRC":{"allowed":2}}}`)))
+	assert.Empty(t, parseDirenvStatus([]byte(`{"state":{"foundRC":null}}`)))
+	assert.Empty(t, parseDirenvStatus([]byte("direnv exec path")))
+
+	assert.Equal(t, "trusted", parseMiseTrust("/wt/mise.toml: trusted\n/wt/.tool-versions: trusted\n"))
+	assert.Equal(t, "untrusted", parseMiseTrust("/wt/mise.toml: trusted\n/wt/.mise.toml: untrusted\n"))
+	assert.Empty(t, parseMiseTrust(""))
+	assert.True(t, Approved("allowed"))
+	assert.False(t, Approved("untrusted"))
+}
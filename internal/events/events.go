@@ -0,0 +1,151 @@
+// Package events writes an NDJSON stream describing what lazyworktree does,
+// one JSON object per line, for status bars, window managers and scripts.
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+// Event types.
+const (
+	Created         = "created"
+	Deleted         = "deleted"
+	Selected        = "selected"
+	RefreshComplete = "refresh-complete"
+	PRStateChanged  = "pr-state-changed"
+)
+
+// PR describes a worktree's pull request in a pr-state-changed event.
+type PR struct {
+	Number         int    `json:"number"`
+	State          string `json:"state"`
+	Draft          bool   `json:"draft,omitempty"`
+	CIStatus       string `json:"ci_status,omitempty"`
+	ReviewDecision string `json:"review_decision,omitempty"`
+	URL            string `json:"url,omitempty"`
+}
+
+// NewPR describes pr for the stream, or returns nil without one.
+func NewPR(pr *models.PRInfo) *PR {
+	if pr == nil {
+		return nil
+	}
+	return &PR{
+		Number:         pr.Number,
+		State:          pr.State,
+		Draft:          pr.IsDraft,
+		CIStatus:       pr.CIStatus,
+		ReviewDecision: pr.ReviewDecision,
+		URL:            pr.URL,
+	}
+}
+
+// Event is a single line of the stream.
+type Event struct {
+	Time   time.Time `json:"time"`
+	Type   string    `json:"type"`
+	Repo   string    `json:"repo,omitempty"`
+	Path   string    `json:"path,omitempty"`
+	Branch string    `json:"branch,omitempty"`
+	// PR is nil in a pr-state-changed event when the PR went away.
+	PR *PR `json:"pr,omitempty"`
+	// Worktrees counts the worktrees in a refresh-complete event.
+	Worktrees int `json:"worktrees,omitempty"`
+}
+
+// Stream writes events. A nil *Stream discards them, so callers need not
+// check whether streaming is enabled.
+type Stream struct {
+	mu     sync.Mutex
+	w      io.Writer
+	closer io.Closer
+	now    func() time.Time
+	err    error
+}
+
+// New returns a stream writing to w.
+func New(w io.Writer) *Stream {
+	return &Stream{w: w, now: time.Now}
+}
+
+// Open returns a stream writing to target: "fd:N" for an inherited file
+// descriptor, such as one opened by the shell with 3>file, or else a path,
+// which is appended to so a named pipe or a shared log both work.
+func Open(target string) (*Stream, error) {
+	if fd, ok := strings.CutPrefix(target, "fd:"); ok {
+		n, err := strconv.ParseUint(fd, 10, 31)
+		if err != nil || n < 3 {
+			return nil, fmt.Errorf("invalid events target %q: use fd:N with N of 3 or more", target)
+		}
+		f := os.NewFile(uintptr(n), target)
+		if f == nil {
+			return nil, fmt.Errorf("invalid events target %q", target)
+		}
+		if _, err := f.Stat(); err != nil {
+			return nil, fmt.Errorf("events target %s is not open: %w", target, err)
+		}
+		s := New(f)
+		s.closer = f
+		return s, nil
+	}
+	// #nosec G304 -- the events path is supplied by the user
+	f, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open events file: %w", err)
+	}
+	s := New(f)
+	s.closer = f
+	return s, nil
+}
+
+// Emit writes e as one line, stamping it with the current time. After a
+// write fails, for instance because the reader went away, further events
+// are dropped and the error is returned by Close.
+func (s *Stream) Emit(e Event) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return
+	}
+	if e.Time.IsZero() {
+		e.Time = s.now().UTC()
+	}
+	line, err := json.Marshal(e)
+	if err != nil {
+		s.err = err
+		return
+	}
+	// A single write keeps lines whole for readers of a pipe.
+	if _, err := s.w.Write(append(line, '\n')); err != nil {
+		s.err = fmt.Errorf("failed to write event: %w", err)
+	}
+}
+
+// Close closes the underlying file and reports the first write error.
+func (s *Stream) Close() error {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	err := s.err
+	if s.closer != nil {
+		if closeErr := s.closer.Close(); err == nil {
+			err = closeErr
+		}
+		s.closer = nil
+	}
+	return err
+}
@@ -0,0 +1,96 @@
+package events
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmitWritesOneLinePerEvent(t *testing.T) {
+	var buf bytes.Buffer
+	s := New(&buf)
+	s.now = func() time.Time { return time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC) }
+
+	s.Emit(Event{Type: Created, Repo: "acme/app", Path: "/wt/feature", Branch: "feature"})
+	s.Emit(Event{Type: PRStateChanged, Path: "/wt/feature", PR: &PR{Number: 7, State: "OPEN", Draft: true}})
+	s.Emit(Event{Type: RefreshComplete, Worktrees: 3})
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	require.Len(t, lines, 3)
+	assert.JSONEq(t, `{"time":"2026-01-02T03:04:05Z","type":"created","repo":"acme/app","path":"/wt/feature","branch":"feature"}`, string(lines[0]))
+	assert.JSONEq(t, `{"time":"2026-01-02T03:04:05Z","type":"pr-state-changed","path":"/wt/feature","pr":{"number":7,"state":"OPEN","draft":true}}`, string(lines[1]))
+	assert.JSONEq(t, `{"time":"2026-01-02T03:04:05Z","type":"refresh-complete","worktrees":3}`, string(lines[2]))
+}
+
+func TestNilStreamDiscards(t *testing.T) {
+	var s *Stream
+	s.Emit(Event{Type: Selected})
+	assert.NoError(t, s.Close())
+}
+
+type failingWriter struct{ writes int }
+
+func (f *failingWriter) Write([]byte) (int, error) {
+	f.writes++
+	return 0, errors.New("broken pipe")
+}
+
+func TestEmitStopsAfterWriteError(t *testing.T) {
+	w := &failingWriter{}
+	s := New(w)
+	s.Emit(Event{Type: Selected})
+	s.Emit(Event{Type: Selected})
+	assert.Equal(t, 1, w.writes)
+	assert.ErrorContains(t, s.Close(), "broken pipe")
+}
+
+func TestOpenAppendsToFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.ndjson")
+	for range 2 {
+		s, err := Open(path)
+		require.NoError(t, err)
+		s.Emit(Event{Type: Deleted, Path: "/wt/old"})
+		require.NoError(t, s.Close())
+	}
+
+	f, err := os.Open(path) // #nosec G304 -- test path
+	require.NoError(t, err)
+	defer func() { _ = f.Close() }()
+	scanner := bufio.NewScanner(f)
+	count := 0
+	for scanner.Scan() {
+		var e Event
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &e))
+		assert.Equal(t, Deleted, e.Type)
+		count++
+	}
+	assert.Equal(t, 2, count)
+}
+
+func TestOpenFileDescriptor(t *testing.T) {
+	for _, bad := range []string{"fd:", "fd:x", "fd:1", "fd:-3"} {
+		_, err := Open(bad)
+		assert.Error(t, err, bad)
+	}
+
+	r, w, err := os.Pipe()
+	require.NoError(t, err)
+	defer func() { _ = r.Close() }()
+	s, err := Open("fd:" + strconv.Itoa(int(w.Fd())))
+	require.NoError(t, err)
+	s.Emit(Event{Type: Selected, Path: "/wt/x"})
+	require.NoError(t, s.Close())
+
+	var e Event
+	require.NoError(t, json.NewDecoder(r).Decode(&e))
+	assert.Equal(t, "/wt/x", e.Path)
+}
@@ -0,0 +1,68 @@
+package git
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// activityReflogLimit bounds the reflog entries read for ActivityDays.
+const activityReflogLimit = 2000
+
+// ActivityDays counts, for each of the days days ending with now's, oldest
+// first, what happened in the worktree at path: the entries of its HEAD
+// reflog, that is commits, checkouts, rebases and resets, or, should it
+// have no reflog, the commits reachable from HEAD.
+func (s *Service) ActivityDays(ctx context.Context, path string, days int, now time.Time) []int {
+	if days <= 0 {
+		return nil
+	}
+	counts := make([]int, days)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	since := today.AddDate(0, 0, -(days - 1))
+	add := func(ts int64) {
+		t := time.Unix(ts, 0).In(now.Location())
+		day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, now.Location())
+		// Rounding absorbs daylight saving changes.
+		ago := int(math.Round(today.Sub(day).Hours() / 24))
+		if ago >= 0 && ago < days {
+			counts[days-1-ago]++
+		}
+	}
+
+	reflog := s.RunGit(ctx, []string{
+		"git", "log", "-g", "-n", strconv.Itoa(activityReflogLimit), "--date=unix", "--format=%gd", "HEAD",
+	}, path, []int{0, 128}, true, true)
+	found := false
+	for line := range strings.SplitSeq(reflog, "\n") {
+		// HEAD@{1700000000}
+		_, rest, ok := strings.Cut(strings.TrimSpace(line), "@{")
+		if !ok {
+			continue
+		}
+		ts, err := strconv.ParseInt(strings.TrimSuffix(rest, "}"), 10, 64)
+		if err != nil {
+			continue
+		}
+		found = true
+		if ts < since.Unix() {
+			break // The reflog is newest first
+		}
+		add(ts)
+	}
+	if found {
+		return counts
+	}
+
+	commits := s.RunGit(ctx, []string{
+		"git", "log", "--format=%ct", "--since=@" + strconv.FormatInt(since.Unix(), 10), "HEAD",
+	}, path, []int{0, 128}, true, true)
+	for line := range strings.SplitSeq(commits, "\n") {
+		if ts, err := strconv.ParseInt(strings.TrimSpace(line), 10, 64); err == nil {
+			add(ts)
+		}
+	}
+	return counts
+}
@@ -0,0 +1,45 @@
+package git
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestActivityDays(t *testing.T) {
+	repo := t.TempDir()
+	runGit(t, repo, "init", "-b", "main")
+	runGit(t, repo, "config", "user.email", "test@example.com")
+	runGit(t, repo, "config", "user.name", "Test User")
+	runGit(t, repo, "config", "commit.gpgsign", "false")
+
+	now := time.Date(2026, 3, 20, 12, 0, 0, 0, time.Local)
+	commitAt := func(name string, at time.Time) {
+		t.Setenv("GIT_COMMITTER_DATE", at.Format(time.RFC3339))
+		t.Setenv("GIT_AUTHOR_DATE", at.Format(time.RFC3339))
+		require.NoError(t, os.WriteFile(filepath.Join(repo, name), []byte(name), 0o600))
+		runGit(t, repo, "add", name)
+		runGit(t, repo, "commit", "-m", name)
+	}
+	commitAt("old", now.AddDate(0, 0, -40))
+	commitAt("a", now.AddDate(0, 0, -2))
+	commitAt("b", now.AddDate(0, 0, -2).Add(time.Hour))
+	commitAt("c", now.Add(-time.Hour))
+
+	ctx := context.Background()
+	service := NewService(func(string, string) {}, func(string, string, string) {})
+	want := make([]int, 30)
+	want[27], want[29] = 2, 1
+	assert.Equal(t, want, service.ActivityDays(ctx, repo, 30, now))
+
+	// Without a reflog, the commits stand in.
+	runGit(t, repo, "reflog", "expire", "--expire=all", "--all")
+	assert.Equal(t, want, service.ActivityDays(ctx, repo, 30, now))
+
+	assert.Nil(t, service.ActivityDays(ctx, repo, 0, now))
+}
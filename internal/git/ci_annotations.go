@@ -0,0 +1,112 @@
+package git
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+// ciAnnotationsJobPath is where GitHub Actions files annotations about a
+// job as a whole, such as "Process completed with exit code 1".
+const ciAnnotationsJobPath = ".github"
+
+// FetchCIAnnotations returns the annotations the failing check runs of
+// the PR's head commit attach to files, sorted by file and line. Only
+// GitHub reports them.
+func (s *Service) FetchCIAnnotations(ctx context.Context, prNumber int) ([]*models.CIAnnotation, error) {
+	if s.DetectHost(ctx) != gitHostGithub {
+		return nil, nil
+	}
+
+	raw := s.RunGit(ctx, []string{
+		"gh", "api", fmt.Sprintf("repos/{owner}/{repo}/pulls/%d", prNumber),
+	}, "", []int{0}, false, true)
+	if raw == "" {
+		return nil, nil
+	}
+	var pr struct {
+		Head struct {
+			SHA string `json:"sha"`
+		} `json:"head"`
+	}
+	if err := json.Unmarshal([]byte(raw), &pr); err != nil {
+		return nil, fmt.Errorf("failed to parse pull request: %w", err)
+	}
+	if pr.Head.SHA == "" {
+		return nil, nil
+	}
+
+	raw = s.RunGit(ctx, []string{
+		"gh", "api", fmt.Sprintf("repos/{owner}/{repo}/commits/%s/check-runs?filter=latest&per_page=100", pr.Head.SHA),
+	}, "", []int{0}, false, true)
+	if raw == "" {
+		return nil, nil
+	}
+	var runs struct {
+		CheckRuns []struct {
+			ID         int64  `json:"id"`
+			Name       string `json:"name"`
+			Conclusion string `json:"conclusion"`
+			Output     struct {
+				AnnotationsCount int `json:"annotations_count"`
+			} `json:"output"`
+		} `json:"check_runs"`
+	}
+	if err := json.Unmarshal([]byte(raw), &runs); err != nil {
+		return nil, fmt.Errorf("failed to parse check runs: %w", err)
+	}
+
+	result := []*models.CIAnnotation{}
+	for _, run := range runs.CheckRuns {
+		switch run.Conclusion {
+		case "failure", "timed_out", "action_required":
+		default:
+			continue
+		}
+		if run.Output.AnnotationsCount == 0 {
+			continue
+		}
+		raw := s.RunGit(ctx, []string{
+			"gh", "api", fmt.Sprintf("repos/{owner}/{repo}/check-runs/%d/annotations?per_page=100", run.ID),
+		}, "", []int{0}, false, true)
+		if raw == "" {
+			continue
+		}
+		var annotations []struct {
+			Path      string `json:"path"`
+			StartLine int    `json:"start_line"`
+			EndLine   int    `json:"end_line"`
+			Level     string `json:"annotation_level"`
+			Title     string `json:"title"`
+			Message   string `json:"message"`
+		}
+		if err := json.Unmarshal([]byte(raw), &annotations); err != nil {
+			return nil, fmt.Errorf("failed to parse annotations of %s: %w", run.Name, err)
+		}
+		for _, a := range annotations {
+			if a.Path == "" || a.Path == ciAnnotationsJobPath {
+				continue
+			}
+			result = append(result, &models.CIAnnotation{
+				Check:     run.Name,
+				Path:      a.Path,
+				StartLine: a.StartLine,
+				EndLine:   a.EndLine,
+				Level:     a.Level,
+				Title:     strings.TrimSpace(a.Title),
+				Message:   strings.TrimSpace(a.Message),
+			})
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Path != result[j].Path {
+			return result[i].Path < result[j].Path
+		}
+		return result[i].StartLine < result[j].StartLine
+	})
+	return result, nil
+}
@@ -0,0 +1,51 @@
+package git
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFetchCIAnnotations(t *testing.T) {
+	stub := "#!/bin/sh\n" +
+		"case \"$2\" in\n" +
+		"  */pulls/7)\n" +
+		"    echo '{\"head\":{\"sha\":\"abc123\"}}'\n" +
+		"    ;;\n" +
+		"  */commits/abc123/check-runs*)\n" +
+		"    echo '{\"check_runs\":[{\"id\":1,\"name\":\"lint\",\"conclusion\":\"failure\",\"output\":{\"annotations_count\":2}},{\"id\":2,\"name\":\"test\",\"conclusion\":\"success\",\"output\":{\"annotations_count\":1}},{\"id\":3,\"name\":\"build\",\"conclusion\":\"failure\",\"output\":{\"annotations_count\":1}}]}'\n" +
+		"    ;;\n" +
+		"  */check-runs/1/annotations*)\n" +
+		"    echo '[{\"path\":\"pkg/b.go\",\"start_line\":9,\"end_line\":9,\"annotation_level\":\"failure\",\"title\":\"errcheck\",\"message\":\"unchecked error \"},{\"path\":\".github\",\"start_line\":1,\"end_line\":1,\"annotation_level\":\"failure\",\"message\":\"Process completed with exit code 1.\"}]'\n" +
+		"    ;;\n" +
+		"  */check-runs/3/annotations*)\n" +
+		"    echo '[{\"path\":\"pkg/a.go\",\"start_line\":3,\"end_line\":4,\"annotation_level\":\"failure\",\"message\":\"undefined: foo\"}]'\n" +
+		"    ;;\n" +
+		"  *)\n" +
+		"    echo 'unexpected call' >&2; exit 1\n" +
+		"    ;;\n" +
+		"esac\n"
+	dir := writeStub(t, "gh", stub)
+	withStubbedPath(t, dir)
+
+	service := NewService(func(string, string) {}, func(string, string, string) {})
+	service.gitHost = gitHostGithub
+
+	annotations, err := service.FetchCIAnnotations(context.Background(), 7)
+	require.NoError(t, err)
+	require.Len(t, annotations, 2, "passing runs and job-level annotations are left out")
+	assert.Equal(t, "build", annotations[0].Check)
+	assert.Equal(t, "pkg/a.go", annotations[0].Path)
+	assert.Equal(t, 3, annotations[0].StartLine)
+	assert.Equal(t, 4, annotations[0].EndLine)
+	assert.Equal(t, "lint", annotations[1].Check)
+	assert.Equal(t, "errcheck", annotations[1].Title)
+	assert.Equal(t, "unchecked error", annotations[1].Message)
+
+	service.gitHost = gitHostGitLab
+	annotations, err = service.FetchCIAnnotations(context.Background(), 7)
+	require.NoError(t, err)
+	assert.Nil(t, annotations)
+}
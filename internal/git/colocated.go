@@ -0,0 +1,71 @@
+package git
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Colocated describes the tools managing the repository alongside git,
+// which keep their own view of its commits and branches.
+type Colocated struct {
+	Jujutsu    bool // A colocated Jujutsu repository, with .jj next to .git
+	Branchless bool // Initialised with git branchless init
+}
+
+// Enabled reports whether another tool manages the repository.
+func (c Colocated) Enabled() bool {
+	return c.Jujutsu || c.Branchless
+}
+
+// String names the tools, for the header.
+func (c Colocated) String() string {
+	var names []string
+	if c.Jujutsu {
+		names = append(names, "jj colocated")
+	}
+	if c.Branchless {
+		names = append(names, "git-branchless")
+	}
+	return strings.Join(names, ", ")
+}
+
+// Colocated detects Jujutsu and git-branchless in the repository whose main
+// worktree is mainPath.
+func (s *Service) Colocated(ctx context.Context, mainPath string) Colocated {
+	var c Colocated
+	if info, err := os.Stat(filepath.Join(mainPath, ".jj")); err == nil && info.IsDir() {
+		c.Jujutsu = true
+	}
+	commonDir := s.RunGit(ctx, []string{"git", "rev-parse", "--path-format=absolute", "--git-common-dir"}, mainPath, []int{0}, true, true)
+	if commonDir != "" {
+		if info, err := os.Stat(filepath.Join(commonDir, "branchless")); err == nil && info.IsDir() {
+			c.Branchless = true
+		}
+	}
+	if !c.Branchless && s.RunGit(ctx, []string{"git", "config", "--get", "branchless.core.mainBranch"}, mainPath, []int{0, 1}, true, true) != "" {
+		c.Branchless = true
+	}
+	return c
+}
+
+// ChangeIDs returns the shortest unique Jujutsu change ID of each commit,
+// read without snapshotting the working copy. Commits jj has not imported
+// yet are left out.
+func (s *Service) ChangeIDs(ctx context.Context, mainPath string, commits []string) map[string]string {
+	ids := make(map[string]string, len(commits))
+	for _, commit := range commits {
+		if commit == "" || ids[commit] != "" {
+			continue
+		}
+		id := s.RunGit(ctx, []string{
+			"jj", "--ignore-working-copy", "--no-pager", "--color=never",
+			"-R", mainPath, "log", "--no-graph", "-r", commit, "-T", "change_id.shortest(8)",
+		}, mainPath, []int{0}, true, true)
+		if id != "" && !strings.ContainsAny(id, " \n") {
+			ids[commit] = id
+		}
+	}
+	return ids
+}
@@ -0,0 +1,51 @@
+package git
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestColocated(t *testing.T) {
+	repo := t.TempDir()
+	runGit(t, repo, "init", "-b", "main")
+	ctx := context.Background()
+	service := NewService(func(string, string) {}, func(string, string, string) {})
+
+	c := service.Colocated(ctx, repo)
+	assert.False(t, c.Enabled())
+
+	require.NoError(t, os.Mkdir(filepath.Join(repo, ".jj"), 0o750))
+	c = service.Colocated(ctx, repo)
+	assert.Equal(t, Colocated{Jujutsu: true}, c)
+	assert.Equal(t, "jj colocated", c.String())
+
+	require.NoError(t, os.Mkdir(filepath.Join(repo, ".git", "branchless"), 0o750))
+	c = service.Colocated(ctx, repo)
+	assert.Equal(t, Colocated{Jujutsu: true, Branchless: true}, c)
+	assert.Equal(t, "jj colocated, git-branchless", c.String())
+
+	other := t.TempDir()
+	runGit(t, other, "init", "-b", "main")
+	runGit(t, other, "config", "branchless.core.mainBranch", "main")
+	assert.Equal(t, Colocated{Branchless: true}, service.Colocated(ctx, other))
+}
+
+func TestChangeIDs(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script in place of jj")
+	}
+	bin := t.TempDir()
+	script := "#!/bin/sh\nfor arg; do case \"$arg\" in abc123) echo kxqyzmno; exit 0;; esac; done\necho 'Error: Revision not found' >&2\nexit 1\n"
+	require.NoError(t, os.WriteFile(filepath.Join(bin, "jj"), []byte(script), 0o700))
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	service := NewService(func(string, string) {}, func(string, string, string) {})
+	ids := service.ChangeIDs(context.Background(), t.TempDir(), []string{"abc123", "def456", "", "abc123"})
+	assert.Equal(t, map[string]string{"abc123": "kxqyzmno"}, ids)
+}
@@ -0,0 +1,67 @@
+package git
+
+import (
+	"strings"
+)
+
+// credentialPromptMarkers are fragments git, ssh, credential helpers and gpg
+// print when they wanted to ask for input but could not.
+var credentialPromptMarkers = []string{
+	"terminal prompts disabled",
+	"could not read username",
+	"could not read password",
+	"authentication failed",
+	"permission denied (publickey",
+	"host key verification failed",
+	"enter passphrase",
+	"bad passphrase",
+	"incorrect passphrase",
+	"ssh_askpass",
+	"gpg failed to sign",
+	"gpg: signing failed",
+	"no pinentry",
+	"inappropriate ioctl for device",
+}
+
+// NonInteractiveEnv returns env with variables set so git, ssh and Git
+// Credential Manager fail fast instead of waiting on a prompt nobody can
+// see. GIT_TERMINAL_PROMPT and GCM_INTERACTIVE always override the user's
+// values, since background git must never prompt; an SSH_ASKPASS the user
+// already exported is kept, and preferred over the terminal unless
+// SSH_ASKPASS_REQUIRE says otherwise.
+func NonInteractiveEnv(env []string) []string {
+	out := append([]string{}, env...)
+	has := func(key string) bool {
+		prefix := key + "="
+		for _, kv := range out {
+			if strings.HasPrefix(kv, prefix) {
+				return true
+			}
+		}
+		return false
+	}
+
+	out = append(out, "GIT_TERMINAL_PROMPT=0", "GCM_INTERACTIVE=never")
+	// Route ssh passphrase and host key prompts to an askpass program that
+	// refuses immediately, unless the user already relies on a graphical one.
+	// OpenSSH ignores askpass while a terminal is attached unless told
+	// otherwise, and would prompt underneath the UI.
+	if !has("SSH_ASKPASS") {
+		out = append(out, "SSH_ASKPASS=false", "SSH_ASKPASS_REQUIRE=force")
+	} else if !has("SSH_ASKPASS_REQUIRE") {
+		out = append(out, "SSH_ASKPASS_REQUIRE=prefer")
+	}
+	return out
+}
+
+// IsCredentialPrompt reports whether command output indicates the command
+// stopped because it needed a passphrase or credentials.
+func IsCredentialPrompt(output string) bool {
+	lower := strings.ToLower(output)
+	for _, marker := range credentialPromptMarkers {
+		if strings.Contains(lower, marker) {
+			return true
+		}
+	}
+	return false
+}
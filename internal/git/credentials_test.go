@@ -0,0 +1,74 @@
+package git
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNonInteractiveEnv(t *testing.T) {
+	t.Run("adds prompt guards", func(t *testing.T) {
+		env := NonInteractiveEnv([]string{"HOME=/tmp"})
+		assert.Contains(t, env, "HOME=/tmp")
+		assert.Contains(t, env, "GIT_TERMINAL_PROMPT=0")
+		assert.Contains(t, env, "GCM_INTERACTIVE=never")
+		assert.Contains(t, env, "SSH_ASKPASS=false")
+		assert.Contains(t, env, "SSH_ASKPASS_REQUIRE=force")
+	})
+
+	t.Run("keeps user askpass", func(t *testing.T) {
+		env := NonInteractiveEnv([]string{"SSH_ASKPASS=/usr/bin/ksshaskpass"})
+		assert.Contains(t, env, "SSH_ASKPASS=/usr/bin/ksshaskpass")
+		assert.Contains(t, env, "SSH_ASKPASS_REQUIRE=prefer")
+		assert.NotContains(t, env, "SSH_ASKPASS=false")
+		assert.NotContains(t, env, "SSH_ASKPASS_REQUIRE=force")
+	})
+
+	t.Run("keeps user askpass requirement", func(t *testing.T) {
+		env := NonInteractiveEnv([]string{"SSH_ASKPASS=/usr/bin/ksshaskpass", "SSH_ASKPASS_REQUIRE=never"})
+		assert.Contains(t, env, "SSH_ASKPASS_REQUIRE=never")
+		assert.NotContains(t, env, "SSH_ASKPASS_REQUIRE=prefer")
+	})
+
+	t.Run("overrides user prompt settings", func(t *testing.T) {
+		env := NonInteractiveEnv([]string{"GIT_TERMINAL_PROMPT=1", "GCM_INTERACTIVE=always"})
+		// exec.Cmd keeps the last value of a duplicated variable.
+		last := map[string]string{}
+		for _, kv := range env {
+			key, value, _ := strings.Cut(kv, "=")
+			last[key] = value
+		}
+		assert.Equal(t, "0", last["GIT_TERMINAL_PROMPT"])
+		assert.Equal(t, "never", last["GCM_INTERACTIVE"])
+	})
+
+	t.Run("does not mutate input", func(t *testing.T) {
+		base := make([]string, 1, 8)
+		base[0] = "A=1"
+		_ = NonInteractiveEnv(base)
+		assert.Len(t, base, 1)
+		assert.Equal(t, "A=1", base[:cap(base)][0])
+		assert.Empty(t, base[:cap(base)][1])
+	})
+}
+
+func TestIsCredentialPrompt(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   bool
+	}{
+		{"https prompt disabled", "fatal: could not read Username for 'https://github.com': terminal prompts disabled", true},
+		{"ssh publickey", "git@github.com: Permission denied (publickey).\nfatal: Could not read from remote repository.", true},
+		{"host key", "Host key verification failed.", true},
+		{"gpg signing", "error: gpg failed to sign the data", true},
+		{"rejected push", "! [rejected] main -> main (fetch first)", false},
+		{"empty", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsCredentialPrompt(tt.output))
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package git
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	neturl "net/url"
+
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+// FetchDeployment returns the most recent GitHub deployment for a branch and
+// its latest status. It returns nil when the branch has never been deployed or
+// the host is not GitHub.
+func (s *Service) FetchDeployment(ctx context.Context, branch string) (*models.DeploymentInfo, error) {
+	if branch == "" || s.DetectHost(ctx) != gitHostGithub {
+		return nil, nil
+	}
+
+	raw := s.RunGit(ctx, []string{
+		"gh", "api",
+		fmt.Sprintf("repos/{owner}/{repo}/deployments?ref=%s&per_page=1", neturl.QueryEscape(branch)),
+	}, "", []int{0}, false, true)
+	if raw == "" {
+		return nil, nil
+	}
+	var deployments []struct {
+		ID          int64  `json:"id"`
+		Environment string `json:"environment"`
+	}
+	if err := json.Unmarshal([]byte(raw), &deployments); err != nil {
+		return nil, fmt.Errorf("failed to parse deployments: %w", err)
+	}
+	if len(deployments) == 0 {
+		return nil, nil
+	}
+
+	info := &models.DeploymentInfo{
+		Environment: deployments[0].Environment,
+		State:       "pending",
+	}
+	raw = s.RunGit(ctx, []string{
+		"gh", "api",
+		fmt.Sprintf("repos/{owner}/{repo}/deployments/%d/statuses?per_page=1", deployments[0].ID),
+	}, "", []int{0}, false, true)
+	if raw == "" {
+		return info, nil
+	}
+	var statuses []struct {
+		State          string `json:"state"`
+		EnvironmentURL string `json:"environment_url"`
+		TargetURL      string `json:"target_url"`
+	}
+	if err := json.Unmarshal([]byte(raw), &statuses); err != nil {
+		return nil, fmt.Errorf("failed to parse deployment statuses: %w", err)
+	}
+	if len(statuses) > 0 {
+		info.State = statuses[0].State
+		info.URL = statuses[0].EnvironmentURL
+		if info.URL == "" {
+			info.URL = statuses[0].TargetURL
+		}
+	}
+	return info, nil
+}
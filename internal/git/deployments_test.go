@@ -0,0 +1,49 @@
+package git
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFetchDeployment(t *testing.T) {
+	stub := "#!/bin/sh\n" +
+		"case \"$2\" in\n" +
+		"  *deployments/42/statuses*)\n" +
+		"    echo '[{\"state\":\"success\",\"environment_url\":\"https://pr-5.example.com\",\"target_url\":\"https://ci.example.com\"}]'\n" +
+		"    ;;\n" +
+		"  *deployments?ref=feature*)\n" +
+		"    echo '[{\"id\":42,\"environment\":\"preview\"}]'\n" +
+		"    ;;\n" +
+		"  *)\n" +
+		"    echo '[]'\n" +
+		"    ;;\n" +
+		"esac\n"
+	dir := writeStub(t, "gh", stub)
+	withStubbedPath(t, dir)
+
+	service := NewService(func(string, string) {}, func(string, string, string) {})
+	service.gitHost = gitHostGithub
+
+	info, err := service.FetchDeployment(context.Background(), "feature")
+	require.NoError(t, err)
+	require.NotNil(t, info)
+	assert.Equal(t, "preview", info.Environment)
+	assert.Equal(t, "success", info.State)
+	assert.Equal(t, "https://pr-5.example.com", info.URL)
+
+	info, err = service.FetchDeployment(context.Background(), "undeployed")
+	require.NoError(t, err)
+	assert.Nil(t, info)
+}
+
+func TestFetchDeploymentNonGitHub(t *testing.T) {
+	service := NewService(func(string, string) {}, func(string, string, string) {})
+	service.gitHost = gitHostGitLab
+
+	info, err := service.FetchDeployment(context.Background(), "feature")
+	require.NoError(t, err)
+	assert.Nil(t, info)
+}
@@ -0,0 +1,239 @@
+package git
+
+import (
+	"context"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// fetchHeadFile is written by every git fetch, so its modification time tells
+// how old the remote-tracking refs are.
+const fetchHeadFile = "FETCH_HEAD"
+
+// SetDivergenceRef makes ahead/behind count against ref, a remote-tracking
+// branch such as origin/main, instead of each branch's own upstream. An empty
+// ref restores the upstream behaviour.
+func (s *Service) SetDivergenceRef(ref string) {
+	s.divergence = strings.TrimSpace(ref)
+}
+
+// FetchOptions tunes the fetches lazyworktree runs, which on huge
+// repositories otherwise take minutes.
+type FetchOptions struct {
+	ProtocolV2         bool   // Force protocol.version=2, whose servers send only the refs asked for
+	NegotiateWorktrees bool   // Offer only the worktree branches as negotiation tips
+	Prune              bool   // Drop remote-tracking branches deleted on the remote
+	Depth              int    // Fetch at most this many commits of history (0 = all)
+	Filter             string // Partial clone filter, such as "blob:none"
+	WorktreesOnly      bool   // Fetch only the upstreams of worktree branches and the main branch
+}
+
+// SetFetchOptions applies opts to every fetch that follows.
+func (s *Service) SetFetchOptions(opts FetchOptions) {
+	opts.Filter = strings.TrimSpace(opts.Filter)
+	opts.Depth = max(opts.Depth, 0)
+	s.fetch = opts
+}
+
+// FetchArgs returns git's arguments, without git itself, for a fetch
+// honouring the fetch options; remotes and refspecs go after them.
+func (s *Service) FetchArgs(ctx context.Context) []string {
+	var args []string
+	if s.fetch.ProtocolV2 {
+		args = append(args, "-c", "protocol.version=2")
+	}
+	args = append(args, "fetch", "--quiet")
+	if s.fetch.Prune {
+		args = append(args, "--prune")
+	}
+	if s.fetch.Depth > 0 {
+		args = append(args, "--depth="+strconv.Itoa(s.fetch.Depth))
+	}
+	if s.fetch.Filter != "" {
+		args = append(args, "--filter="+s.fetch.Filter)
+	}
+	if s.fetch.NegotiateWorktrees {
+		// Without tips git offers every local ref it has, which in a
+		// repository with thousands of branches dominates the fetch.
+		for _, ref := range s.worktreeBranchRefs(ctx) {
+			args = append(args, "--negotiation-tip="+ref)
+		}
+	}
+	return args
+}
+
+// worktreeBranchRefs lists the branches checked out in worktrees.
+func (s *Service) worktreeBranchRefs(ctx context.Context) []string {
+	out := s.RunGit(ctx, []string{"git", "worktree", "list", "--porcelain"}, "", []int{0}, true, true)
+	var refs []string
+	for line := range strings.SplitSeq(out, "\n") {
+		if ref, ok := strings.CutPrefix(line, "branch "); ok {
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
+
+// FetchAll fetches every remote, as the fetch action does, or with
+// WorktreesOnly just the branches worktrees track.
+func (s *Service) FetchAll(ctx context.Context) bool {
+	if s.fetch.WorktreesOnly {
+		if upstreams := s.worktreeUpstreams(ctx); len(upstreams) > 0 {
+			return s.fetchUpstreams(ctx, upstreams)
+		}
+	}
+	args := append(append([]string{"git"}, s.FetchArgs(ctx)...), "--all")
+	return s.RunCommandChecked(ctx, args, "", "Failed to fetch remotes")
+}
+
+// upstreamRef is a branch's upstream: its ref on the remote and the
+// remote-tracking ref mirroring it.
+type upstreamRef struct {
+	remoteRef   string
+	trackingRef string
+}
+
+// worktreeUpstreams lists, by remote, the upstreams of the branches checked
+// out in worktrees and of the main branch.
+func (s *Service) worktreeUpstreams(ctx context.Context) map[string][]upstreamRef {
+	wanted := map[string]bool{"refs/heads/" + s.GetMainBranch(ctx): true}
+	for _, ref := range s.worktreeBranchRefs(ctx) {
+		wanted[ref] = true
+	}
+	out := s.RunGit(ctx, []string{"git", "for-each-ref", "--format=%(refname)%09%(upstream:remotename)%09%(upstream:remoteref)%09%(upstream)", "refs/heads/"}, "", []int{0}, true, true)
+	upstreams := make(map[string][]upstreamRef)
+	seen := make(map[string]bool)
+	for line := range strings.SplitSeq(out, "\n") {
+		fields := strings.Split(line, "\t")
+		// A remote of "." is a branch tracking another local branch
+		if len(fields) != 4 || !wanted[fields[0]] || fields[1] == "" || fields[1] == "." || fields[2] == "" || fields[3] == "" || seen[fields[3]] {
+			continue
+		}
+		seen[fields[3]] = true
+		upstreams[fields[1]] = append(upstreams[fields[1]], upstreamRef{remoteRef: fields[2], trackingRef: fields[3]})
+	}
+	return upstreams
+}
+
+// fetchUpstreams fetches exactly the given upstreams, one fetch per remote.
+// An upstream deleted on the remote would fail the whole fetch, so the
+// remote is asked first which still exist; with Prune the tracking refs of
+// the others are dropped, as --prune would have.
+func (s *Service) fetchUpstreams(ctx context.Context, upstreams map[string][]upstreamRef) bool {
+	remotes := slices.Sorted(maps.Keys(upstreams))
+	ok := true
+	for _, remote := range remotes {
+		refs := upstreams[remote]
+		lsArgs := []string{"git", "ls-remote", "--heads", remote}
+		for _, ref := range refs {
+			lsArgs = append(lsArgs, ref.remoteRef)
+		}
+		out := s.RunGit(ctx, lsArgs, "", []int{0}, true, false)
+		if out == "" {
+			// Nothing at all is more likely a failure than every branch gone
+			ok = false
+			continue
+		}
+		existing := make(map[string]bool)
+		for line := range strings.SplitSeq(out, "\n") {
+			if _, ref, found := strings.Cut(line, "\t"); found {
+				existing[ref] = true
+			}
+		}
+		args := append(append([]string{"git"}, s.FetchArgs(ctx)...), remote)
+		fetched := 0
+		for _, ref := range refs {
+			switch {
+			case existing[ref.remoteRef]:
+				args = append(args, "+"+ref.remoteRef+":"+ref.trackingRef)
+				fetched++
+			case s.fetch.Prune:
+				s.RunGit(ctx, []string{"git", "update-ref", "-d", ref.trackingRef}, "", []int{0}, true, true)
+			}
+		}
+		if fetched > 0 && !s.RunCommandChecked(ctx, args, "", "Failed to fetch "+remote) {
+			ok = false
+		}
+	}
+	return ok
+}
+
+// FetchBranch fetches a single branch from remote, updating its
+// remote-tracking ref without contacting every remote.
+func (s *Service) FetchBranch(ctx context.Context, remote, branch, worktreePath string) bool {
+	args := append(append([]string{"git"}, s.FetchArgs(ctx)...), remote, branch)
+	return s.RunCommandChecked(ctx, args, worktreePath, "Failed to fetch "+remote+"/"+branch)
+}
+
+// divergenceFrom counts the commits HEAD is ahead of and behind ref, using
+// only what is known locally.
+func (s *Service) divergenceFrom(ctx context.Context, ref, worktreePath string) (ahead, behind int, ok bool) {
+	out := s.RunGit(ctx, []string{"git", "rev-list", "--left-right", "--count", "HEAD..." + ref}, worktreePath, []int{0}, true, true)
+	fields := strings.Fields(out)
+	if len(fields) != 2 {
+		return 0, 0, false
+	}
+	ahead, errAhead := strconv.Atoi(fields[0])
+	behind, errBehind := strconv.Atoi(fields[1])
+	if errAhead != nil || errBehind != nil {
+		return 0, 0, false
+	}
+	return ahead, behind, true
+}
+
+// lastFetchTime returns when the worktree last saw a fetch, as a Unix
+// timestamp, or 0 when it never has. Linked worktrees keep their own
+// FETCH_HEAD, so the repository-wide one is checked as well.
+func lastFetchTime(worktreePath string, bare bool) int64 {
+	gitDir := worktreePath
+	if !bare {
+		gitDir = worktreeGitDir(worktreePath)
+	}
+	if gitDir == "" {
+		return 0
+	}
+	latest := fetchHeadTime(gitDir)
+	if worktrees := filepath.Dir(gitDir); filepath.Base(worktrees) == "worktrees" {
+		latest = max(latest, fetchHeadTime(filepath.Dir(worktrees)))
+	}
+	return latest
+}
+
+// worktreeGitDir resolves the git directory of a worktree, following the
+// .git file of linked worktrees.
+func worktreeGitDir(worktreePath string) string {
+	dotGit := filepath.Join(worktreePath, ".git")
+	info, err := os.Stat(dotGit)
+	if err != nil {
+		return ""
+	}
+	if info.IsDir() {
+		return dotGit
+	}
+	// #nosec G304 - path is the .git file of a worktree listed by git
+	data, err := os.ReadFile(dotGit)
+	if err != nil {
+		return ""
+	}
+	gitDir, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir:")
+	if !ok {
+		return ""
+	}
+	gitDir = strings.TrimSpace(gitDir)
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(worktreePath, gitDir)
+	}
+	return filepath.Clean(gitDir)
+}
+
+func fetchHeadTime(gitDir string) int64 {
+	info, err := os.Stat(filepath.Join(gitDir, fetchHeadFile))
+	if err != nil {
+		return 0
+	}
+	return info.ModTime().Unix()
+}
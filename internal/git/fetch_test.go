@@ -0,0 +1,157 @@
+package git
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/chmouel/lazyworktree/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLastFetchTime(t *testing.T) {
+	repo := t.TempDir()
+	commonDir := filepath.Join(repo, ".git")
+	gitDir := filepath.Join(commonDir, "worktrees", "feature")
+	require.NoError(t, os.MkdirAll(gitDir, 0o750))
+	wtPath := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(wtPath, ".git"), []byte("gitdir: "+gitDir+"\n"), 0o600))
+
+	assert.Equal(t, gitDir, worktreeGitDir(wtPath))
+	assert.Equal(t, commonDir, worktreeGitDir(repo))
+	assert.Zero(t, lastFetchTime(wtPath, false), "never fetched")
+
+	older := time.Now().Add(-2 * time.Hour).Truncate(time.Second)
+	newer := time.Now().Add(-time.Minute).Truncate(time.Second)
+	writeFetchHead := func(dir string, at time.Time) {
+		t.Helper()
+		path := filepath.Join(dir, fetchHeadFile)
+		require.NoError(t, os.WriteFile(path, nil, 0o600))
+		require.NoError(t, os.Chtimes(path, at, at))
+	}
+
+	writeFetchHead(gitDir, older)
+	assert.Equal(t, older.Unix(), lastFetchTime(wtPath, false))
+
+	// A fetch from the main worktree also updates the linked worktree's refs.
+	writeFetchHead(commonDir, newer)
+	assert.Equal(t, newer.Unix(), lastFetchTime(wtPath, false))
+	assert.Equal(t, newer.Unix(), lastFetchTime(repo, false))
+
+	assert.Zero(t, lastFetchTime(filepath.Join(repo, "missing"), false))
+}
+
+func TestDivergenceRefAndFetchBranch(t *testing.T) {
+	remote := t.TempDir()
+	runGit(t, remote, "init", "-b", "main")
+	runGit(t, remote, "-c", "user.name=t", "-c", "user.email=t@e", "commit", "--allow-empty", "-m", "init")
+
+	clone := filepath.Join(t.TempDir(), "clone")
+	runGit(t, remote, "clone", "--quiet", remote, clone)
+	runGit(t, clone, "-c", "user.name=t", "-c", "user.email=t@e", "commit", "--allow-empty", "-m", "local")
+	runGit(t, remote, "-c", "user.name=t", "-c", "user.email=t@e", "commit", "--allow-empty", "-m", "upstream one")
+	runGit(t, remote, "-c", "user.name=t", "-c", "user.email=t@e", "commit", "--allow-empty", "-m", "upstream two")
+	withCwd(t, clone)
+
+	service := NewService(func(string, string) {}, func(string, string, string) {})
+	service.SetDivergenceRef(" origin/main ")
+	ctx := context.Background()
+
+	mainWt := mainWorktree(t, service)
+	assert.Equal(t, "origin/main", mainWt.DivergenceRef)
+	assert.Equal(t, 1, mainWt.Ahead)
+	assert.Zero(t, mainWt.Behind, "nothing is fetched to compute divergence")
+
+	require.True(t, service.FetchBranch(ctx, "origin", "main", mainWt.Path))
+	mainWt = mainWorktree(t, service)
+	assert.Equal(t, 1, mainWt.Ahead)
+	assert.Equal(t, 2, mainWt.Behind)
+	assert.NotZero(t, mainWt.LastFetchTS)
+
+	service.SetDivergenceRef("origin/missing")
+	mainWt = mainWorktree(t, service)
+	assert.Equal(t, "origin/main", mainWt.DivergenceRef, "an unknown ref falls back to the upstream")
+}
+
+func mainWorktree(t *testing.T, service *Service) *models.WorktreeInfo {
+	t.Helper()
+	worktrees, err := service.GetWorktrees(context.Background())
+	require.NoError(t, err)
+	for _, wt := range worktrees {
+		if wt.IsMain {
+			return wt
+		}
+	}
+	t.Fatal("main worktree not listed")
+	return nil
+}
+
+func TestFetchArgs(t *testing.T) {
+	remote := t.TempDir()
+	runGit(t, remote, "init", "-b", "main")
+	runGit(t, remote, "-c", "user.name=t", "-c", "user.email=t@e", "commit", "--allow-empty", "-m", "init")
+	clone := filepath.Join(t.TempDir(), "clone")
+	runGit(t, remote, "clone", "--quiet", remote, clone)
+	runGit(t, clone, "worktree", "add", "--quiet", "-b", "feature", filepath.Join(t.TempDir(), "feature"))
+	runGit(t, clone, "branch", "unused")
+	withCwd(t, clone)
+
+	service := NewService(func(string, string) {}, func(string, string, string) {})
+	ctx := context.Background()
+	assert.Equal(t, []string{"fetch", "--quiet"}, service.FetchArgs(ctx))
+
+	service.SetFetchOptions(FetchOptions{ProtocolV2: true, NegotiateWorktrees: true, Prune: true, Depth: 50, Filter: " blob:none "})
+	assert.Equal(t, []string{
+		"-c", "protocol.version=2", "fetch", "--quiet", "--prune", "--depth=50", "--filter=blob:none",
+		"--negotiation-tip=refs/heads/main", "--negotiation-tip=refs/heads/feature",
+	}, service.FetchArgs(ctx), "branches without a worktree are not offered")
+
+	runGit(t, remote, "-c", "user.name=t", "-c", "user.email=t@e", "commit", "--allow-empty", "-m", "upstream")
+	service.SetFetchOptions(FetchOptions{ProtocolV2: true, NegotiateWorktrees: true, Prune: true})
+	require.True(t, service.FetchAll(ctx))
+	assert.Equal(t, "upstream", service.RunGit(ctx, []string{"git", "log", "-1", "--format=%s", "origin/main"}, "", []int{0}, true, true))
+}
+
+func TestFetchAllWorktreesOnly(t *testing.T) {
+	remote := t.TempDir()
+	commit := func(dir, msg string) {
+		runGit(t, dir, "-c", "user.name=t", "-c", "user.email=t@e", "commit", "--allow-empty", "-m", msg)
+	}
+	runGit(t, remote, "init", "-b", "main")
+	commit(remote, "init")
+	runGit(t, remote, "branch", "feature")
+	runGit(t, remote, "branch", "gone")
+	clone := filepath.Join(t.TempDir(), "clone")
+	runGit(t, remote, "clone", "--quiet", remote, clone)
+	runGit(t, clone, "worktree", "add", "--quiet", "-b", "feature", filepath.Join(t.TempDir(), "feature"), "origin/feature")
+	runGit(t, clone, "worktree", "add", "--quiet", "-b", "gone", filepath.Join(t.TempDir(), "gone"), "origin/gone")
+	withCwd(t, clone)
+
+	commit(remote, "main moved")
+	runGit(t, remote, "checkout", "--quiet", "feature")
+	commit(remote, "feature moved")
+	runGit(t, remote, "checkout", "--quiet", "-b", "unused")
+	commit(remote, "unused")
+	runGit(t, remote, "branch", "-D", "gone")
+
+	service := NewService(func(string, string) {}, func(string, string, string) {})
+	ctx := context.Background()
+	service.SetFetchOptions(FetchOptions{WorktreesOnly: true, Prune: true})
+	assert.Equal(t, map[string][]upstreamRef{"origin": {
+		{remoteRef: "refs/heads/feature", trackingRef: "refs/remotes/origin/feature"},
+		{remoteRef: "refs/heads/gone", trackingRef: "refs/remotes/origin/gone"},
+		{remoteRef: "refs/heads/main", trackingRef: "refs/remotes/origin/main"},
+	}}, service.worktreeUpstreams(ctx))
+
+	require.True(t, service.FetchAll(ctx))
+	subject := func(ref string) string {
+		return service.RunGit(ctx, []string{"git", "log", "-1", "--format=%s", ref}, "", []int{0}, true, true)
+	}
+	assert.Equal(t, "main moved", subject("origin/main"))
+	assert.Equal(t, "feature moved", subject("origin/feature"))
+	assert.Empty(t, subject("origin/unused"), "branches without a worktree are not fetched")
+	assert.Empty(t, subject("origin/gone"), "an upstream deleted on the remote is pruned")
+}
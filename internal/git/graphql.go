@@ -0,0 +1,215 @@
+package git
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+// graphQLBatchSize caps how many head branches are queried per GraphQL request.
+const graphQLBatchSize = 25
+
+const graphQLPRFields = `number state title body url isDraft headRefName baseRefName reviewDecision
+author { login __typename ... on User { name } }
+labels(first: 20) { nodes { name } }
+commits(last: 1) { nodes { commit { statusCheckRollup { state contexts(first: 100) { nodes {
+  __typename
+  ... on CheckRun { name status conclusion }
+  ... on StatusContext { context state }
+} } } } } }`
+
+type graphQLCheckContext struct {
+	Typename   string `json:"__typename"`
+	Name       string `json:"name"`
+	Status     string `json:"status"`
+	Conclusion string `json:"conclusion"`
+	Context    string `json:"context"`
+	State      string `json:"state"`
+}
+
+type graphQLPullRequest struct {
+	Number         int    `json:"number"`
+	State          string `json:"state"`
+	Title          string `json:"title"`
+	Body           string `json:"body"`
+	URL            string `json:"url"`
+	IsDraft        bool   `json:"isDraft"`
+	HeadRefName    string `json:"headRefName"`
+	BaseRefName    string `json:"baseRefName"`
+	ReviewDecision string `json:"reviewDecision"`
+	Author         *struct {
+		Login    string `json:"login"`
+		Name     string `json:"name"`
+		Typename string `json:"__typename"`
+	} `json:"author"`
+	Labels struct {
+		Nodes []struct {
+			Name string `json:"name"`
+		} `json:"nodes"`
+	} `json:"labels"`
+	Commits struct {
+		Nodes []struct {
+			Commit struct {
+				StatusCheckRollup *struct {
+					State    string `json:"state"`
+					Contexts struct {
+						Nodes []graphQLCheckContext `json:"nodes"`
+					} `json:"contexts"`
+				} `json:"statusCheckRollup"`
+			} `json:"commit"`
+		} `json:"nodes"`
+	} `json:"commits"`
+}
+
+type graphQLPRResponse struct {
+	Data struct {
+		Repository map[string]*struct {
+			Nodes []graphQLPullRequest `json:"nodes"`
+		} `json:"repository"`
+	} `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// buildPRGraphQLQuery returns a query with one aliased pullRequests lookup per
+// head branch; branch names are passed as variables $h0..$hN.
+func buildPRGraphQLQuery(count int) string {
+	var b strings.Builder
+	b.WriteString("query($owner: String!, $name: String!")
+	for i := 0; i < count; i++ {
+		fmt.Fprintf(&b, ", $h%d: String!", i)
+	}
+	b.WriteString(") { repository(owner: $owner, name: $name) {\n")
+	for i := 0; i < count; i++ {
+		fmt.Fprintf(&b, "b%d: pullRequests(headRefName: $h%d, first: 1, orderBy: {field: CREATED_AT, direction: DESC}) { nodes { %s } }\n", i, i, graphQLPRFields)
+	}
+	b.WriteString("} }")
+	return b.String()
+}
+
+// fetchGitHubPRsGraphQL resolves PRs for the given head branches with as few
+// GitHub GraphQL requests as possible, returning a map keyed by branch.
+func (s *Service) fetchGitHubPRsGraphQL(ctx context.Context, branches []string) (map[string]*models.PRInfo, error) {
+	prMap := make(map[string]*models.PRInfo)
+	for start := 0; start < len(branches); start += graphQLBatchSize {
+		end := min(start+graphQLBatchSize, len(branches))
+		batch := branches[start:end]
+
+		args := []string{
+			"gh", "api", "graphql",
+			"-F", "owner={owner}",
+			"-F", "name={repo}",
+			"-f", "query=" + buildPRGraphQLQuery(len(batch)),
+		}
+		for i, branch := range batch {
+			args = append(args, "-f", fmt.Sprintf("h%d=%s", i, branch))
+		}
+		raw := s.RunGit(ctx, args, "", []int{0}, false, true)
+		if raw == "" {
+			return nil, fmt.Errorf("empty GraphQL response")
+		}
+
+		var resp graphQLPRResponse
+		if err := json.Unmarshal([]byte(raw), &resp); err != nil {
+			return nil, fmt.Errorf("failed to parse GraphQL response: %w", err)
+		}
+		if len(resp.Errors) > 0 && resp.Data.Repository == nil {
+			return nil, fmt.Errorf("graphql: %s", resp.Errors[0].Message)
+		}
+
+		for i, branch := range batch {
+			conn := resp.Data.Repository[fmt.Sprintf("b%d", i)]
+			if conn == nil || len(conn.Nodes) == 0 {
+				continue
+			}
+			prMap[branch] = conn.Nodes[0].toPRInfo()
+		}
+	}
+	return prMap, nil
+}
+
+// toPRInfo converts a GraphQL pull request node into PRInfo, including checks.
+func (p *graphQLPullRequest) toPRInfo() *models.PRInfo {
+	pr := &models.PRInfo{
+		Number:         p.Number,
+		State:          p.State,
+		Title:          p.Title,
+		Body:           p.Body,
+		URL:            p.URL,
+		Branch:         p.HeadRefName,
+		BaseBranch:     p.BaseRefName,
+		IsDraft:        p.IsDraft,
+		ReviewDecision: p.ReviewDecision,
+		CIStatus:       "none",
+	}
+	if p.Author != nil {
+		pr.Author = p.Author.Login
+		pr.AuthorName = p.Author.Name
+		pr.AuthorIsBot = p.Author.Typename == "Bot"
+	}
+	for _, label := range p.Labels.Nodes {
+		pr.Labels = append(pr.Labels, label.Name)
+	}
+	if len(p.Commits.Nodes) == 0 {
+		return pr
+	}
+	rollup := p.Commits.Nodes[0].Commit.StatusCheckRollup
+	if rollup == nil {
+		return pr
+	}
+	pr.CIStatus = graphQLRollupStatus(rollup.State)
+	pr.Checks = make([]*models.CICheck, 0, len(rollup.Contexts.Nodes))
+	for _, c := range rollup.Contexts.Nodes {
+		if c.Typename == "StatusContext" {
+			pr.Checks = append(pr.Checks, &models.CICheck{
+				Name:       c.Context,
+				Status:     strings.ToLower(c.State),
+				Conclusion: graphQLRollupStatus(c.State),
+			})
+			continue
+		}
+		pr.Checks = append(pr.Checks, &models.CICheck{
+			Name:       c.Name,
+			Status:     strings.ToLower(c.Status),
+			Conclusion: graphQLCheckConclusion(c.Status, c.Conclusion),
+		})
+	}
+	return pr
+}
+
+// graphQLRollupStatus maps a StatusState value onto our CI status vocabulary.
+func graphQLRollupStatus(state string) string {
+	switch strings.ToUpper(state) {
+	case "SUCCESS":
+		return ciSuccess
+	case "FAILURE", "ERROR":
+		return ciFailure
+	case "PENDING", "EXPECTED":
+		return ciPending
+	default:
+		return "none"
+	}
+}
+
+// graphQLCheckConclusion maps a CheckRun status and conclusion onto our conclusions.
+func graphQLCheckConclusion(status, conclusion string) string {
+	if !strings.EqualFold(status, "COMPLETED") {
+		return ciPending
+	}
+	switch strings.ToUpper(conclusion) {
+	case "SUCCESS":
+		return ciSuccess
+	case "FAILURE", "TIMED_OUT", "ACTION_REQUIRED", "STARTUP_FAILURE":
+		return ciFailure
+	case "SKIPPED", "NEUTRAL", "STALE":
+		return ciSkipped
+	case "CANCELLED":
+		return ciCancelled
+	default:
+		return strings.ToLower(conclusion)
+	}
+}
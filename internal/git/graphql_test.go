@@ -0,0 +1,91 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildPRGraphQLQuery(t *testing.T) {
+	query := buildPRGraphQLQuery(2)
+	assert.Contains(t, query, "$h0: String!")
+	assert.Contains(t, query, "$h1: String!")
+	assert.Contains(t, query, "b0: pullRequests(headRefName: $h0")
+	assert.Contains(t, query, "b1: pullRequests(headRefName: $h1")
+	assert.NotContains(t, query, "$h2")
+	assert.Contains(t, query, "reviewDecision")
+	assert.Contains(t, query, "statusCheckRollup")
+}
+
+func TestFetchGitHubPRsGraphQL(t *testing.T) {
+	response := `{"data":{"repository":{` +
+		`"b0":{"nodes":[{"number":5,"state":"OPEN","title":"Feature","url":"https://github.com/o/r/pull/5","isDraft":true,` +
+		`"headRefName":"feature","baseRefName":"main","reviewDecision":"CHANGES_REQUESTED",` +
+		`"author":{"login":"dependabot","__typename":"Bot"},` +
+		`"commits":{"nodes":[{"commit":{"statusCheckRollup":{"state":"FAILURE","contexts":{"nodes":[` +
+		`{"__typename":"CheckRun","name":"build","status":"COMPLETED","conclusion":"FAILURE"},` +
+		`{"__typename":"CheckRun","name":"lint","status":"IN_PROGRESS","conclusion":""},` +
+		`{"__typename":"StatusContext","context":"ci/legacy","state":"SUCCESS"}` +
+		`]}}}}]}}]},` +
+		`"b1":{"nodes":[]}}}}`
+	stub := "#!/bin/sh\n" +
+		"if [ \"$1\" = \"api\" ] && [ \"$2\" = \"graphql\" ]; then\n" +
+		"  echo '" + response + "'\n" +
+		"  exit 0\n" +
+		"fi\n" +
+		"exit 1\n"
+	dir := writeStub(t, "gh", stub)
+	withStubbedPath(t, dir)
+
+	service := NewService(func(string, string) {}, func(string, string, string) {})
+	service.gitHost = gitHostGithub
+	service.mainBranch = "main"
+
+	prMap, err := service.FetchPRMapForBranches(context.Background(), []string{"feature", "no-pr"})
+	require.NoError(t, err)
+	require.Len(t, prMap, 1)
+
+	pr := prMap["feature"]
+	require.NotNil(t, pr)
+	assert.Equal(t, 5, pr.Number)
+	assert.True(t, pr.IsDraft)
+	assert.True(t, pr.AuthorIsBot)
+	assert.Equal(t, "main", pr.BaseBranch)
+	assert.Equal(t, "CHANGES_REQUESTED", pr.ReviewDecision)
+	assert.Equal(t, ciFailure, pr.CIStatus)
+	require.Len(t, pr.Checks, 3)
+	assert.Equal(t, ciFailure, pr.Checks[0].Conclusion)
+	assert.Equal(t, ciPending, pr.Checks[1].Conclusion)
+	assert.Equal(t, "ci/legacy", pr.Checks[2].Name)
+	assert.Equal(t, ciSuccess, pr.Checks[2].Conclusion)
+}
+
+func TestFetchGitHubPRsGraphQLBatches(t *testing.T) {
+	stub := "#!/bin/sh\n" +
+		"echo \"$@\" >> \"$LW_STUB_LOG\"\n" +
+		"echo '{\"data\":{\"repository\":{}}}'\n" +
+		"exit 0\n"
+	dir := writeStub(t, "gh", stub)
+	withStubbedPath(t, dir)
+	logPath := dir + "/calls.log"
+	t.Setenv("LW_STUB_LOG", logPath)
+
+	branches := make([]string, graphQLBatchSize+1)
+	for i := range branches {
+		branches[i] = fmt.Sprintf("branch-%d", i)
+	}
+
+	service := NewService(func(string, string) {}, func(string, string, string) {})
+	prMap, err := service.fetchGitHubPRsGraphQL(context.Background(), branches)
+	require.NoError(t, err)
+	assert.Empty(t, prMap)
+
+	calls, err := os.ReadFile(logPath)
+	require.NoError(t, err)
+	assert.Equal(t, 2, strings.Count(string(calls), "api graphql"))
+}
@@ -0,0 +1,41 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ownerFile, kept in a worktree's git directory, names who created the
+// worktree, so people sharing a machine can tell theirs apart. Git removes
+// it with the worktree.
+const ownerFile = "lazyworktree-owner"
+
+// RecordWorktreeOwner records owner as the creator of the worktree at path.
+func RecordWorktreeOwner(path, owner string) error {
+	owner = strings.TrimSpace(owner)
+	if owner == "" {
+		return nil
+	}
+	gitDir := worktreeGitDir(path)
+	if gitDir == "" {
+		return fmt.Errorf("no git directory for %s", path)
+	}
+	return os.WriteFile(filepath.Join(gitDir, ownerFile), []byte(owner+"\n"), 0o600)
+}
+
+// WorktreeOwner returns who created the worktree at path: the owner
+// recorded when lazyworktree created it, or else the OS user owning its
+// directory.
+func WorktreeOwner(path string) string {
+	if gitDir := worktreeGitDir(path); gitDir != "" {
+		// #nosec G304 - the owner file sits in the git directory of a listed worktree
+		if data, err := os.ReadFile(filepath.Join(gitDir, ownerFile)); err == nil {
+			if owner := strings.TrimSpace(string(data)); owner != "" {
+				return owner
+			}
+		}
+	}
+	return directoryOwner(path)
+}
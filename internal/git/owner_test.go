@@ -0,0 +1,43 @@
+package git
+
+import (
+	"context"
+	"os/user"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorktreeOwner(t *testing.T) {
+	repo := t.TempDir()
+	runGit(t, repo, "init", "-b", "main")
+	runGit(t, repo, "config", "user.email", "test@example.com")
+	runGit(t, repo, "config", "user.name", "Test User")
+	runGit(t, repo, "config", "commit.gpgsign", "false")
+	commitFile(t, repo, "README.md")
+	withCwd(t, repo)
+	wtPath := filepath.Join(t.TempDir(), "feature")
+	runGit(t, repo, "worktree", "add", "-b", "feature", wtPath, "main")
+
+	if runtime.GOOS != "windows" {
+		current, err := user.Current()
+		require.NoError(t, err)
+		assert.Equal(t, current.Username, WorktreeOwner(wtPath), "unrecorded worktrees fall back to the directory's owner")
+	}
+
+	require.NoError(t, RecordWorktreeOwner(wtPath, " alice@pairing "))
+	assert.Equal(t, "alice@pairing", WorktreeOwner(wtPath))
+	assert.Error(t, RecordWorktreeOwner(t.TempDir(), "alice"))
+
+	service := NewService(func(string, string) {}, func(string, string, string) {})
+	worktrees, err := service.GetWorktrees(context.Background())
+	require.NoError(t, err)
+	owners := map[string]string{}
+	for _, wt := range worktrees {
+		owners[filepath.Base(wt.Path)] = wt.Owner
+	}
+	assert.Equal(t, "alice@pairing", owners["feature"])
+}
@@ -0,0 +1,38 @@
+//go:build !windows
+
+package git
+
+import (
+	"os"
+	"os/user"
+	"strconv"
+	"sync"
+	"syscall"
+)
+
+// userNames caches user names by ID, as looking one up reads the user
+// database.
+var userNames sync.Map
+
+// directoryOwner returns the name of the user owning the directory at
+// path, or "" when it cannot be told.
+func directoryOwner(path string) string {
+	info, err := os.Stat(path)
+	if err != nil {
+		return ""
+	}
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return ""
+	}
+	uid := strconv.FormatUint(uint64(stat.Uid), 10)
+	if name, ok := userNames.Load(uid); ok {
+		return name.(string)
+	}
+	name := uid
+	if u, err := user.LookupId(uid); err == nil {
+		name = u.Username
+	}
+	userNames.Store(uid, name)
+	return name
+}
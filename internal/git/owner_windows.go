@@ -0,0 +1,9 @@
+//go:build windows
+
+package git
+
+// directoryOwner returns "" on Windows, where files carry security
+// descriptors rather than an owning user ID; only recorded owners show.
+func directoryOwner(string) string {
+	return ""
+}
@@ -0,0 +1,101 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+)
+
+// PartialClone describes a repository cloned with an object filter, whose
+// missing objects are downloaded from the promisor remote on demand.
+type PartialClone struct {
+	Remote string // Promisor remote, e.g. "origin"
+	Filter string // Object filter, e.g. "blob:none"
+}
+
+// Enabled reports whether the repository is a partial clone.
+func (p PartialClone) Enabled() bool {
+	return p.Remote != ""
+}
+
+// PartialClone returns the repository's promisor remote and filter, or the
+// zero value when it holds every object.
+func (s *Service) PartialClone(ctx context.Context) PartialClone {
+	raw := s.RunGit(ctx, []string{"git", "config", "--get-regexp", `^remote\..*\.(promisor|partialclonefilter)$`}, "", []int{0, 1}, true, true)
+	var pc PartialClone
+	for line := range strings.SplitSeq(raw, "\n") {
+		key, value, _ := strings.Cut(strings.TrimSpace(line), " ")
+		rest, ok := strings.CutPrefix(key, "remote.")
+		if !ok {
+			continue
+		}
+		dot := strings.LastIndex(rest, ".")
+		if dot <= 0 {
+			continue
+		}
+		remote, name := rest[:dot], rest[dot+1:]
+		switch name {
+		case "promisor":
+			if strings.EqualFold(value, "true") && pc.Remote == "" {
+				pc.Remote = remote
+			}
+		case "partialclonefilter":
+			pc.Remote = remote
+			pc.Filter = value
+		}
+	}
+	return pc
+}
+
+// MissingObjects lists the objects reachable from revs, limited to paths,
+// that the partial clone left out. Listing them does not download them.
+func (s *Service) MissingObjects(ctx context.Context, dir string, revs []string, paths []string) []string {
+	args := append([]string{"git", "rev-list", "--objects", "--missing=print"}, revs...)
+	if len(paths) > 0 {
+		args = append(append(args, "--"), paths...)
+	}
+	raw := s.RunGit(ctx, args, dir, []int{0}, true, true)
+	var missing []string
+	for line := range strings.SplitSeq(raw, "\n") {
+		if oid, ok := strings.CutPrefix(strings.TrimSpace(line), "?"); ok && oid != "" {
+			missing = append(missing, oid)
+		}
+	}
+	return missing
+}
+
+// FetchObjects downloads oids from the promisor remote in one request, as
+// git does when it needs a missing object, rather than one at a time.
+func (s *Service) FetchObjects(ctx context.Context, dir string, pc PartialClone, oids []string) error {
+	if len(oids) == 0 {
+		return nil
+	}
+	args := []string{
+		"git", "-c", "fetch.negotiationAlgorithm=noop", "fetch",
+		"--no-tags", "--no-write-fetch-head", "--recurse-submodules=no",
+	}
+	if pc.Filter != "" {
+		args = append(args, "--filter="+pc.Filter)
+	}
+	args = append(args, "--stdin", pc.Remote)
+	cmd, err := s.command(ctx, args, dir)
+	if err != nil {
+		return err
+	}
+	cmd.Stdin = strings.NewReader(strings.Join(oids, "\n") + "\n")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	s.debugf("run: %s (cwd=%s, %d objects)", strings.Join(args, " "), dir, len(oids))
+
+	release := s.Acquire(ctx, commandPool(args))
+	err = cmd.Run()
+	release()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("failed to fetch objects from %s: %s", pc.Remote, msg)
+		}
+		return fmt.Errorf("failed to fetch objects from %s: %w", pc.Remote, err)
+	}
+	return nil
+}
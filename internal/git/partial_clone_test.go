@@ -0,0 +1,51 @@
+package git
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPartialClone(t *testing.T) {
+	source := t.TempDir()
+	runGit(t, source, "init", "-b", "main")
+	runGit(t, source, "config", "user.email", "test@example.com")
+	runGit(t, source, "config", "user.name", "Test User")
+	runGit(t, source, "config", "commit.gpgsign", "false")
+	runGit(t, source, "config", "uploadpack.allowFilter", "true")
+	runGit(t, source, "config", "uploadpack.allowAnySHA1InWant", "true")
+	for _, file := range []string{"README.md", "web/index.js", "api/main.go"} {
+		path := filepath.Join(source, file)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o750))
+		require.NoError(t, os.WriteFile(path, []byte(file+"\n"), 0o600))
+	}
+	runGit(t, source, "add", ".")
+	runGit(t, source, "commit", "-m", "Initial commit")
+
+	clone := filepath.Join(t.TempDir(), "clone")
+	runGit(t, source, "clone", "--quiet", "--filter=blob:none", "--no-checkout", "file://"+source, clone)
+	withCwd(t, clone)
+
+	ctx := context.Background()
+	service := NewService(func(string, string) {}, func(string, string, string) {})
+	pc := service.PartialClone(ctx)
+	assert.Equal(t, PartialClone{Remote: "origin", Filter: "blob:none"}, pc)
+	assert.True(t, pc.Enabled())
+
+	assert.Len(t, service.MissingObjects(ctx, clone, []string{"HEAD"}, nil), 3)
+	missing := service.MissingObjects(ctx, clone, []string{"HEAD"}, []string{"web"})
+	require.Len(t, missing, 1, "paths limit the walk")
+	assert.Len(t, service.MissingObjects(ctx, clone, []string{"HEAD"}, nil), 3, "listing does not download")
+
+	require.NoError(t, service.FetchObjects(ctx, clone, pc, missing))
+	assert.Empty(t, service.MissingObjects(ctx, clone, []string{"HEAD"}, []string{"web"}))
+	assert.Len(t, service.MissingObjects(ctx, clone, []string{"HEAD"}, nil), 2)
+	assert.NoError(t, service.FetchObjects(ctx, clone, pc, nil))
+
+	withCwd(t, source)
+	assert.False(t, service.PartialClone(ctx).Enabled())
+}
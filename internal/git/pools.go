@@ -0,0 +1,147 @@
+package git
+
+import (
+	"context"
+	"runtime"
+	"strings"
+	"sync/atomic"
+)
+
+// Pool names a class of external command with its own concurrency limit, so
+// slow network calls cannot starve quick local git calls.
+type Pool int
+
+const (
+	// PoolLocal runs git commands that only touch the local repository.
+	PoolLocal Pool = iota
+	// PoolNetwork runs gh/glab calls and git commands talking to a remote.
+	PoolNetwork
+	// PoolCommands runs user-configured commands and scripts.
+	PoolCommands
+
+	poolCount
+)
+
+// defaultNetworkLimit and defaultCommandLimit keep forge rate limits and
+// user scripts in check regardless of the CPU count.
+const (
+	defaultNetworkLimit = 4
+	defaultCommandLimit = 4
+)
+
+// PoolLimits holds the concurrency of each pool. Zero keeps the default.
+type PoolLimits struct {
+	Local    int
+	Network  int
+	Commands int
+}
+
+// PoolStats reports how many commands wait for a free slot in each pool.
+type PoolStats struct {
+	Local    int
+	Network  int
+	Commands int
+}
+
+// Total returns the number of commands waiting across all pools.
+func (p PoolStats) Total() int {
+	return p.Local + p.Network + p.Commands
+}
+
+// pool is a counting semaphore that also tracks its queue depth.
+type pool struct {
+	tokens  chan struct{}
+	waiting atomic.Int32
+}
+
+func newPool(limit int) *pool {
+	// The channel starts full; acquiring takes a token, releasing returns it.
+	p := &pool{tokens: make(chan struct{}, limit)}
+	for range limit {
+		p.tokens <- struct{}{}
+	}
+	return p
+}
+
+// acquire blocks until a slot is free or ctx is done. The returned function
+// releases the slot and is a no-op when none was taken.
+func (p *pool) acquire(ctx context.Context) func() {
+	select {
+	case <-p.tokens:
+		return func() { p.tokens <- struct{}{} }
+	default:
+	}
+	p.waiting.Add(1)
+	defer p.waiting.Add(-1)
+	select {
+	case <-p.tokens:
+		return func() { p.tokens <- struct{}{} }
+	case <-ctx.Done():
+		return func() {}
+	}
+}
+
+// defaultLocalLimit scales local git concurrency with the CPU count.
+func defaultLocalLimit() int {
+	return min(max(runtime.NumCPU()*2, 4), 32)
+}
+
+// SetPoolLimits replaces the pools whose limit is positive. It must be
+// called before any command runs.
+func (s *Service) SetPoolLimits(limits PoolLimits) {
+	if limits.Local > 0 {
+		s.pools[PoolLocal] = newPool(limits.Local)
+	}
+	if limits.Network > 0 {
+		s.pools[PoolNetwork] = newPool(limits.Network)
+	}
+	if limits.Commands > 0 {
+		s.pools[PoolCommands] = newPool(limits.Commands)
+	}
+}
+
+// Acquire waits for a slot in the given pool and returns its release
+// function, for commands run outside the service.
+func (s *Service) Acquire(ctx context.Context, kind Pool) func() {
+	return s.pools[kind].acquire(ctx)
+}
+
+// QueueDepth returns how many commands are waiting in each pool.
+func (s *Service) QueueDepth() PoolStats {
+	return PoolStats{
+		Local:    int(s.pools[PoolLocal].waiting.Load()),
+		Network:  int(s.pools[PoolNetwork].waiting.Load()),
+		Commands: int(s.pools[PoolCommands].waiting.Load()),
+	}
+}
+
+// commandPool picks the pool for a command from its arguments.
+func commandPool(args []string) Pool {
+	if len(args) == 0 {
+		return PoolLocal
+	}
+	switch args[0] {
+	case "gh", "glab":
+		return PoolNetwork
+	case "git":
+		switch gitSubcommand(args[1:]) {
+		case "fetch", "pull", "push", "ls-remote", "clone":
+			return PoolNetwork
+		}
+	}
+	return PoolLocal
+}
+
+// gitSubcommand returns the first argument after git's global options.
+func gitSubcommand(args []string) string {
+	for i := 0; i < len(args); i++ {
+		switch arg := args[i]; {
+		case arg == "-C" || arg == "-c":
+			i++
+		case strings.HasPrefix(arg, "-"):
+		default:
+			return arg
+		}
+	}
+	return ""
+}
@@ -0,0 +1,75 @@
+package git
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommandPool(t *testing.T) {
+	tests := []struct {
+		args []string
+		want Pool
+	}{
+		{args: nil, want: PoolLocal},
+		{args: []string{"git", "status", "--porcelain"}, want: PoolLocal},
+		{args: []string{"git", "log", "push"}, want: PoolLocal},
+		{args: []string{"git", "fetch", "--all"}, want: PoolNetwork},
+		{args: []string{"git", "-C", "/repo", "push", "origin"}, want: PoolNetwork},
+		{args: []string{"git", "-c", "fetch.prune=true", "status"}, want: PoolLocal},
+		{args: []string{"git", "--no-pager", "pull"}, want: PoolNetwork},
+		{args: []string{"gh", "pr", "list"}, want: PoolNetwork},
+		{args: []string{"glab", "api", "merge_requests"}, want: PoolNetwork},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, commandPool(tt.args), "%v", tt.args)
+	}
+}
+
+func TestSetPoolLimits(t *testing.T) {
+	service := NewService(func(string, string) {}, func(string, string, string) {})
+	local := service.pools[PoolLocal]
+
+	service.SetPoolLimits(PoolLimits{Network: 1, Commands: 2})
+	assert.Same(t, local, service.pools[PoolLocal], "zero keeps the default pool")
+	assert.Equal(t, 1, cap(service.pools[PoolNetwork].tokens))
+	assert.Equal(t, 2, cap(service.pools[PoolCommands].tokens))
+}
+
+func TestPoolQueueDepth(t *testing.T) {
+	service := NewService(func(string, string) {}, func(string, string, string) {})
+	service.SetPoolLimits(PoolLimits{Network: 1})
+	ctx := context.Background()
+
+	release := service.Acquire(ctx, PoolNetwork)
+	assert.Zero(t, service.QueueDepth().Total())
+
+	acquired := make(chan func())
+	go func() { acquired <- service.Acquire(ctx, PoolNetwork) }()
+	require.Eventually(t, func() bool {
+		return service.QueueDepth() == PoolStats{Network: 1}
+	}, time.Second, time.Millisecond)
+
+	release()
+	releaseSecond := <-acquired
+	assert.Zero(t, service.QueueDepth().Total())
+	releaseSecond()
+}
+
+func TestPoolAcquireCancelled(t *testing.T) {
+	p := newPool(1)
+	release := p.acquire(context.Background())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	noop := p.acquire(ctx)
+	noop()
+	assert.Empty(t, p.tokens, "a cancelled acquire must not hand back a slot")
+	assert.Zero(t, p.waiting.Load())
+
+	release()
+	assert.Len(t, p.tokens, 1)
+}
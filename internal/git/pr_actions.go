@@ -0,0 +1,148 @@
+package git
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// runForgeCommand runs a gh/glab command and returns its output as the error
+// detail when it fails.
+func (s *Service) runForgeCommand(ctx context.Context, args []string, cwd string) error {
+	s.debugf("run: %s (cwd=%s)", strings.Join(args, " "), cwd)
+	cmd, err := s.command(ctx, args, cwd)
+	if err != nil {
+		return err
+	}
+	release := s.Acquire(ctx, PoolNetwork)
+	output, err := cmd.CombinedOutput()
+	release()
+	if err != nil {
+		if detail := strings.TrimSpace(string(output)); detail != "" {
+			return errors.New(detail)
+		}
+		return err
+	}
+	return nil
+}
+
+// SetPRDraft marks a PR/MR as draft (true) or ready for review (false).
+func (s *Service) SetPRDraft(ctx context.Context, prNumber int, draft bool, cwd string) error {
+	number := strconv.Itoa(prNumber)
+	switch s.DetectHost(ctx) {
+	case gitHostGithub:
+		args := []string{"gh", "pr", "ready", number}
+		if draft {
+			args = append(args, "--undo")
+		}
+		return s.runForgeCommand(ctx, args, cwd)
+	case gitHostGitLab:
+		flag := "--ready"
+		if draft {
+			flag = "--draft"
+		}
+		return s.runForgeCommand(ctx, []string{"glab", "mr", "update", number, flag}, cwd)
+	default:
+		return fmt.Errorf("no GitHub or GitLab remote detected")
+	}
+}
+
+// RequestPRReviewers asks the given users to review a PR/MR.
+func (s *Service) RequestPRReviewers(ctx context.Context, prNumber int, reviewers []string, cwd string) error {
+	if len(reviewers) == 0 {
+		return nil
+	}
+	number := strconv.Itoa(prNumber)
+	switch s.DetectHost(ctx) {
+	case gitHostGithub:
+		return s.runForgeCommand(ctx, []string{"gh", "pr", "edit", number, "--add-reviewer", strings.Join(reviewers, ",")}, cwd)
+	case gitHostGitLab:
+		// glab replaces the reviewers unless each login is prefixed with +.
+		added := make([]string, 0, len(reviewers))
+		for _, reviewer := range reviewers {
+			added = append(added, "+"+reviewer)
+		}
+		return s.runForgeCommand(ctx, []string{"glab", "mr", "update", number, "--reviewer", strings.Join(added, ",")}, cwd)
+	default:
+		return fmt.Errorf("no GitHub or GitLab remote detected")
+	}
+}
+
+// FetchReviewerCandidates returns usernames seen recently as PR/MR authors or
+// reviewers, sorted alphabetically.
+func (s *Service) FetchReviewerCandidates(ctx context.Context) []string {
+	seen := make(map[string]bool)
+	add := func(login string) {
+		login = strings.TrimSpace(login)
+		if login != "" {
+			seen[login] = true
+		}
+	}
+
+	switch s.DetectHost(ctx) {
+	case gitHostGithub:
+		raw := s.RunGit(ctx, []string{
+			"gh", "pr", "list",
+			"--state", "all",
+			"--limit", "50",
+			"--json", "author,reviews",
+		}, "", []int{0}, false, true)
+		var prs []struct {
+			Author struct {
+				Login string `json:"login"`
+				IsBot bool   `json:"is_bot"`
+			} `json:"author"`
+			Reviews []struct {
+				Author struct {
+					Login string `json:"login"`
+				} `json:"author"`
+			} `json:"reviews"`
+		}
+		if raw == "" || json.Unmarshal([]byte(raw), &prs) != nil {
+			return nil
+		}
+		for _, pr := range prs {
+			if !pr.Author.IsBot {
+				add(pr.Author.Login)
+			}
+			for _, review := range pr.Reviews {
+				add(review.Author.Login)
+			}
+		}
+	case gitHostGitLab:
+		raw := s.RunGit(ctx, []string{"glab", "api", "merge_requests?state=all&per_page=50"}, "", []int{0}, false, true)
+		var mrs []struct {
+			Author struct {
+				Username string `json:"username"`
+				Bot      bool   `json:"bot"`
+			} `json:"author"`
+			Reviewers []struct {
+				Username string `json:"username"`
+			} `json:"reviewers"`
+		}
+		if raw == "" || json.Unmarshal([]byte(raw), &mrs) != nil {
+			return nil
+		}
+		for _, mr := range mrs {
+			if !mr.Author.Bot {
+				add(mr.Author.Username)
+			}
+			for _, reviewer := range mr.Reviewers {
+				add(reviewer.Username)
+			}
+		}
+	default:
+		return nil
+	}
+
+	candidates := make([]string, 0, len(seen))
+	for login := range seen {
+		candidates = append(candidates, login)
+	}
+	sort.Strings(candidates)
+	return candidates
+}
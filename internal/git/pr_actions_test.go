@@ -0,0 +1,103 @@
+package git
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetPRDraftGitHub(t *testing.T) {
+	stub := "#!/bin/sh\n" +
+		"echo \"$@\" >> \"$LW_STUB_LOG\"\n" +
+		"exit 0\n"
+	dir := writeStub(t, "gh", stub)
+	withStubbedPath(t, dir)
+	logPath := dir + "/calls.log"
+	t.Setenv("LW_STUB_LOG", logPath)
+
+	service := NewService(func(string, string) {}, func(string, string, string) {})
+	service.gitHost = gitHostGithub
+
+	require.NoError(t, service.SetPRDraft(context.Background(), 7, true, dir))
+	require.NoError(t, service.SetPRDraft(context.Background(), 7, false, dir))
+
+	calls, err := os.ReadFile(logPath)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"pr ready 7 --undo", "pr ready 7"}, strings.Split(strings.TrimSpace(string(calls)), "\n"))
+}
+
+func TestSetPRDraftGitLabFailure(t *testing.T) {
+	stub := "#!/bin/sh\n" +
+		"if [ \"$4\" = \"--ready\" ]; then\n" +
+		"  echo 'merge request is locked' >&2\n" +
+		"  exit 1\n" +
+		"fi\n" +
+		"exit 0\n"
+	dir := writeStub(t, "glab", stub)
+	withStubbedPath(t, dir)
+
+	service := NewService(func(string, string) {}, func(string, string, string) {})
+	service.gitHost = gitHostGitLab
+
+	require.NoError(t, service.SetPRDraft(context.Background(), 3, true, dir))
+	err := service.SetPRDraft(context.Background(), 3, false, dir)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "merge request is locked")
+}
+
+func TestRequestPRReviewers(t *testing.T) {
+	stub := "#!/bin/sh\n" +
+		"echo \"$@\" >> \"$LW_STUB_LOG\"\n" +
+		"exit 0\n"
+	dir := writeStub(t, "gh", stub)
+	withStubbedPath(t, dir)
+	logPath := dir + "/calls.log"
+	t.Setenv("LW_STUB_LOG", logPath)
+
+	service := NewService(func(string, string) {}, func(string, string, string) {})
+	service.gitHost = gitHostGithub
+
+	require.NoError(t, service.RequestPRReviewers(context.Background(), 9, nil, dir))
+	require.NoError(t, service.RequestPRReviewers(context.Background(), 9, []string{"alice", "bob"}, dir))
+
+	calls, err := os.ReadFile(logPath)
+	require.NoError(t, err)
+	assert.Equal(t, "pr edit 9 --add-reviewer alice,bob", strings.TrimSpace(string(calls)))
+}
+
+func TestRequestPRReviewersGitLabAddsReviewers(t *testing.T) {
+	stub := "#!/bin/sh\n" +
+		"printf '%s\\n' \"$@\" >> \"$LW_STUB_LOG\"\n" +
+		"exit 0\n"
+	dir := writeStub(t, "glab", stub)
+	withStubbedPath(t, dir)
+	logPath := dir + "/calls.log"
+	t.Setenv("LW_STUB_LOG", logPath)
+
+	service := NewService(func(string, string) {}, func(string, string, string) {})
+	service.gitHost = gitHostGitLab
+
+	require.NoError(t, service.RequestPRReviewers(context.Background(), 9, []string{"alice", "bob"}, dir))
+
+	calls, err := os.ReadFile(logPath)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"mr", "update", "9", "--reviewer", "+alice,+bob"}, strings.Split(strings.TrimSpace(string(calls)), "\n"))
+}
+
+func TestFetchReviewerCandidatesGitHub(t *testing.T) {
+	stub := "#!/bin/sh\n" +
+		"echo '[{\"author\":{\"login\":\"zoe\",\"is_bot\":false},\"reviews\":[{\"author\":{\"login\":\"alice\"}}]}," +
+		"{\"author\":{\"login\":\"renovate\",\"is_bot\":true},\"reviews\":[{\"author\":{\"login\":\"zoe\"}}]}]'\n" +
+		"exit 0\n"
+	dir := writeStub(t, "gh", stub)
+	withStubbedPath(t, dir)
+
+	service := NewService(func(string, string) {}, func(string, string, string) {})
+	service.gitHost = gitHostGithub
+
+	assert.Equal(t, []string{"alice", "zoe"}, service.FetchReviewerCandidates(context.Background()))
+}
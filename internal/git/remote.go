@@ -0,0 +1,247 @@
+package git
+
+import (
+	"context"
+	"os/exec"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// remoteControlPath shares one SSH connection between the commands,
+	// sparing each a new handshake. It stays short, as socket paths are
+	// limited to about a hundred bytes.
+	remoteControlPath = "~/.ssh/lazyworktree-%C"
+	// remoteCacheLatencyFactor scales the round trip of remote commands into
+	// how long their read-only output is reused: the slower the host, the
+	// longer.
+	remoteCacheLatencyFactor = 20
+	// remoteCacheMaxTTL caps how long remote output is reused.
+	remoteCacheMaxTTL = 30 * time.Second
+)
+
+// remoteGitEnv keeps git on the host from prompting for credentials.
+var remoteGitEnv = []string{"GIT_TERMINAL_PROMPT=0", "GCM_INTERACTIVE=never"}
+
+// remoteReadOnlyGit lists the git subcommands whose output may be reused.
+var remoteReadOnlyGit = map[string]bool{
+	"status":       true,
+	"log":          true,
+	"show":         true,
+	"diff":         true,
+	"rev-parse":    true,
+	"rev-list":     true,
+	"for-each-ref": true,
+	"ls-files":     true,
+	"merge-base":   true,
+	"cat-file":     true,
+	"describe":     true,
+}
+
+// remoteHost runs commands on another host over SSH, remembering the
+// output of read-only ones for a while.
+type remoteHost struct {
+	host string // SSH destination, such as "me@devbox"
+	path string // Repository on the host, where commands without a directory run
+
+	mu      sync.Mutex
+	latency time.Duration // Smoothed time remote commands take
+	cache   map[string]remoteCacheEntry
+}
+
+type remoteCacheEntry struct {
+	output   string
+	storedAt time.Time
+}
+
+// SetRemote makes the service run git, gh and glab on host over SSH, in the
+// repository at path there unless a command names another directory. An
+// empty host runs them locally again.
+func (s *Service) SetRemote(host, path string) {
+	host = strings.TrimSpace(host)
+	if host == "" {
+		s.remote = nil
+		return
+	}
+	s.remote = &remoteHost{host: host, path: strings.TrimSpace(path), cache: make(map[string]remoteCacheEntry)}
+}
+
+// RemoteHost returns the host commands run on over SSH, or "" when they run
+// locally.
+func (s *Service) RemoteHost() string {
+	if s.remote == nil {
+		return ""
+	}
+	return s.remote.host
+}
+
+// RemoteLatency returns the smoothed time commands take on the remote host.
+func (s *Service) RemoteLatency() time.Duration {
+	if s.remote == nil {
+		return 0
+	}
+	s.remote.mu.Lock()
+	defer s.remote.mu.Unlock()
+	return s.remote.latency
+}
+
+// command prepares args to run in cwd, on the remote host in SSH mode.
+// Commands that may change the repository drop the remote output cached
+// so far.
+func (s *Service) command(ctx context.Context, args []string, cwd string) (*exec.Cmd, error) {
+	cmd, err := s.prepareAllowedCommand(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	if s.remote != nil {
+		if !remoteCacheable(args) {
+			s.remote.invalidate()
+		}
+		var env []string
+		if args[0] == "git" {
+			env = remoteGitEnv
+		}
+		return s.remote.command(ctx, args, cwd, env), nil
+	}
+	if cwd != "" {
+		cmd.Dir = cwd
+	}
+	return cmd, nil
+}
+
+// command builds the ssh invocation running args in dir on the host.
+func (r *remoteHost) command(ctx context.Context, args []string, dir string, env []string) *exec.Cmd {
+	// #nosec G204 -- the host is user-configured and every remote argument is quoted
+	return exec.CommandContext(ctx, "ssh", r.sshArgs(args, dir, env)...)
+}
+
+// sshArgs returns the arguments of ssh running args in dir, or in the
+// repository, with env set.
+func (r *remoteHost) sshArgs(args []string, dir string, env []string) []string {
+	if dir == "" {
+		dir = r.path
+	}
+	var b strings.Builder
+	if dir != "" {
+		b.WriteString("cd ")
+		b.WriteString(remoteQuotePath(dir))
+		b.WriteString(" && ")
+	}
+	if len(env) > 0 {
+		b.WriteString("env")
+		for _, kv := range env {
+			b.WriteByte(' ')
+			b.WriteString(remoteQuote(kv))
+		}
+		b.WriteByte(' ')
+	}
+	for i, arg := range args {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(remoteQuote(arg))
+	}
+
+	sshArgs := []string{"-o", "BatchMode=yes"}
+	// Windows' OpenSSH cannot share connections.
+	if runtime.GOOS != "windows" {
+		sshArgs = append(sshArgs, "-o", "ControlMaster=auto", "-o", "ControlPath="+remoteControlPath, "-o", "ControlPersist=10m")
+	}
+	return append(sshArgs, "--", r.host, b.String())
+}
+
+// remoteQuote quotes s for the host's shell.
+func remoteQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// remoteQuotePath quotes a path for the host's shell, leaving a leading ~
+// for it to expand.
+func remoteQuotePath(p string) string {
+	if p == "~" {
+		return p
+	}
+	if rest, ok := strings.CutPrefix(p, "~/"); ok {
+		return "~/" + remoteQuote(rest)
+	}
+	return remoteQuote(p)
+}
+
+// remoteCacheable reports whether the command only reads, so its output
+// may be reused.
+func remoteCacheable(args []string) bool {
+	if len(args) < 2 {
+		return false
+	}
+	switch args[0] {
+	case "git":
+		switch args[1] {
+		case "worktree":
+			return len(args) > 2 && args[2] == "list"
+		case "config":
+			return len(args) > 2 && strings.HasPrefix(args[2], "--get")
+		}
+		return remoteReadOnlyGit[args[1]]
+	case "gh", "glab":
+		if args[1] == "api" {
+			return !slices.ContainsFunc(args[2:], func(arg string) bool {
+				switch arg {
+				case "-X", "--method", "-f", "-F", "--field", "--raw-field", "--input":
+					return true
+				}
+				return false
+			})
+		}
+		return len(args) > 2 && (args[2] == "list" || args[2] == "view" || args[2] == "checks")
+	}
+	return false
+}
+
+func remoteCacheKey(args []string, cwd string, strip bool) string {
+	key := cwd + "\x00" + strings.Join(args, "\x00")
+	if strip {
+		key += "\x00strip"
+	}
+	return key
+}
+
+// ttl is how long output stays fresh given how slow the host has been.
+func (r *remoteHost) ttl() time.Duration {
+	return min(r.latency*remoteCacheLatencyFactor, remoteCacheMaxTTL)
+}
+
+// lookup returns the output stored under key while it is fresh.
+func (r *remoteHost) lookup(key string) (string, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	entry, ok := r.cache[key]
+	if !ok || time.Since(entry.storedAt) >= r.ttl() {
+		return "", false
+	}
+	return entry.output, true
+}
+
+// record folds the time a command took into the latency and stores the
+// output of a read-only one that succeeded.
+func (r *remoteHost) record(key string, args []string, output string, ok bool, took time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.latency == 0 {
+		r.latency = took
+	} else {
+		r.latency = (3*r.latency + took) / 4
+	}
+	if ok && remoteCacheable(args) {
+		r.cache[key] = remoteCacheEntry{output: output, storedAt: time.Now()}
+	}
+}
+
+// invalidate drops the cached output, once the repository may have changed.
+func (r *remoteHost) invalidate() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	clear(r.cache)
+}
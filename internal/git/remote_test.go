@@ -0,0 +1,103 @@
+package git
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemoteSSHArgs(t *testing.T) {
+	r := &remoteHost{host: "me@devbox", path: "~/src/my app"}
+
+	args := r.sshArgs([]string{"git", "log", "--format=%h it's"}, "", remoteGitEnv)
+	require.GreaterOrEqual(t, len(args), 3)
+	assert.Equal(t, []string{"--", "me@devbox"}, args[len(args)-3:len(args)-1])
+	assert.Equal(t, `cd ~/'src/my app' && env 'GIT_TERMINAL_PROMPT=0' 'GCM_INTERACTIVE=never' 'git' 'log' '--format=%h it'\''s'`, args[len(args)-1])
+	assert.Contains(t, args, "BatchMode=yes")
+
+	args = r.sshArgs([]string{"gh", "pr", "list"}, "/srv/wt/feature", nil)
+	assert.Equal(t, `cd '/srv/wt/feature' && 'gh' 'pr' 'list'`, args[len(args)-1])
+}
+
+func TestRemoteCacheable(t *testing.T) {
+	tests := []struct {
+		args []string
+		want bool
+	}{
+		{[]string{"git", "status", "--porcelain"}, true},
+		{[]string{"git", "worktree", "list", "--porcelain"}, true},
+		{[]string{"git", "worktree", "add", "x"}, false},
+		{[]string{"git", "config", "--get", "remote.origin.url"}, true},
+		{[]string{"git", "config", "user.name", "x"}, false},
+		{[]string{"git", "commit", "-m", "x"}, false},
+		{[]string{"gh", "pr", "list"}, true},
+		{[]string{"gh", "pr", "merge", "7"}, false},
+		{[]string{"gh", "api", "repos/o/r/pulls"}, true},
+		{[]string{"gh", "api", "-X", "POST", "repos/o/r/pulls"}, false},
+		{[]string{"git"}, false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, remoteCacheable(tt.args), strings.Join(tt.args, " "))
+	}
+}
+
+func TestRemoteRunGitCachesReads(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires sh")
+	}
+	repo := t.TempDir()
+	setupGitRepo(t, repo)
+
+	// The stub runs the remote command locally, counting the connections.
+	calls := filepath.Join(t.TempDir(), "calls")
+	stub := "#!/bin/sh\n" +
+		"echo >> '" + calls + "'\n" +
+		"for last; do :; done\n" +
+		"exec sh -c \"$last\"\n"
+	withStubbedPath(t, writeStub(t, "ssh", stub))
+	countCalls := func() int {
+		data, _ := os.ReadFile(calls)
+		return strings.Count(string(data), "\n")
+	}
+
+	service := NewService(func(string, string) {}, func(string, string, string) {})
+	service.SetRemote("devbox", repo)
+	assert.Equal(t, "devbox", service.RemoteHost())
+	ctx := context.Background()
+	topLevel := []string{"git", "rev-parse", "--show-toplevel"}
+
+	want, err := exec.Command("git", "-C", repo, "rev-parse", "--show-toplevel").Output()
+	require.NoError(t, err)
+	assert.Equal(t, strings.TrimSpace(string(want)), service.RunGit(ctx, topLevel, "", []int{0}, true, true))
+	assert.Equal(t, 1, countCalls())
+	assert.Positive(t, service.RemoteLatency())
+
+	// A slow host keeps output for longer.
+	service.remote.latency = time.Second
+	service.RunGit(ctx, topLevel, "", []int{0}, true, true)
+	assert.Equal(t, 1, countCalls(), "read-only output is reused")
+
+	service.RunGit(ctx, []string{"git", "tag", "v1", "HEAD"}, "", []int{0, 128}, true, true)
+	service.RunGit(ctx, topLevel, "", []int{0}, true, true)
+	assert.Equal(t, 3, countCalls(), "a change drops the cached output")
+
+	service.InvalidateRemoteCache()
+	service.RunGit(ctx, topLevel, "", []int{0}, true, true)
+	assert.Equal(t, 4, countCalls(), "a refresh drops the cached output")
+
+	missing := []string{"git", "rev-parse", "--verify", "--quiet", "refs/heads/missing"}
+	service.RunGit(ctx, missing, "", []int{0}, true, true)
+	service.RunGit(ctx, missing, "", []int{0}, true, true)
+	assert.Equal(t, 6, countCalls(), "failed output is not reused")
+
+	service.SetRemote("", "")
+	assert.Empty(t, service.RemoteHost())
+}
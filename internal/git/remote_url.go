@@ -0,0 +1,82 @@
+package git
+
+import (
+	"context"
+	"net/url"
+	"strings"
+)
+
+// Remote URL protocols.
+const (
+	ProtocolSSH   = "ssh"
+	ProtocolHTTPS = "https"
+	ProtocolHTTP  = "http"
+	ProtocolGit   = "git"
+	ProtocolFile  = "file"
+)
+
+// RemoteURL is a remote's URL split into the parts the info pane shows.
+type RemoteURL struct {
+	Raw      string // As configured, e.g. git@github.com:owner/repo.git
+	Protocol string // One of the Protocol constants
+	Host     string // e.g. github.com; empty for local paths
+	Path     string // Repository path on the host, e.g. owner/repo.git
+}
+
+// ParseRemoteURL splits the URL forms git accepts: scp-like ones such as
+// git@github.com:owner/repo.git, URLs with a scheme, and local paths.
+func ParseRemoteURL(raw string) (RemoteURL, bool) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return RemoteURL{}, false
+	}
+	if scheme, _, ok := strings.Cut(raw, "://"); ok {
+		u, err := url.Parse(raw)
+		if err != nil {
+			return RemoteURL{}, false
+		}
+		protocol := strings.ToLower(scheme)
+		if protocol == "git+ssh" || protocol == "ssh+git" {
+			protocol = ProtocolSSH
+		}
+		return RemoteURL{Raw: raw, Protocol: protocol, Host: u.Hostname(), Path: strings.TrimPrefix(u.Path, "/")}, true
+	}
+	// scp-like syntax: [user@]host:path, as long as no slash comes before
+	// the colon, which would make it a local path.
+	if colon := strings.Index(raw, ":"); colon > 0 && !strings.Contains(raw[:colon], "/") {
+		host := raw[:colon]
+		if at := strings.LastIndex(host, "@"); at >= 0 {
+			host = host[at+1:]
+		}
+		return RemoteURL{Raw: raw, Protocol: ProtocolSSH, Host: host, Path: strings.TrimPrefix(raw[colon+1:], "/")}, true
+	}
+	return RemoteURL{Raw: raw, Protocol: ProtocolFile, Path: raw}, true
+}
+
+// Switched returns the URL in the other of the ssh and https forms, e.g.
+// git@github.com:owner/repo.git for https://github.com/owner/repo.git. Ports
+// are dropped as they rarely carry over. It reports false for other
+// protocols.
+func (r RemoteURL) Switched() (string, bool) {
+	if r.Host == "" || r.Path == "" {
+		return "", false
+	}
+	switch r.Protocol {
+	case ProtocolSSH:
+		return "https://" + r.Host + "/" + r.Path, true
+	case ProtocolHTTPS, ProtocolHTTP:
+		return "git@" + r.Host + ":" + r.Path, true
+	}
+	return "", false
+}
+
+// RemoteURL returns the fetch URL of remote, or an empty string when there
+// is no such remote.
+func (s *Service) RemoteURL(ctx context.Context, remote, dir string) string {
+	return s.RunGit(ctx, []string{"git", "remote", "get-url", remote}, dir, []int{0}, true, true)
+}
+
+// SetRemoteURL points remote at rawURL, reporting failures.
+func (s *Service) SetRemoteURL(ctx context.Context, remote, rawURL, dir string) bool {
+	return s.RunCommandChecked(ctx, []string{"git", "remote", "set-url", remote, rawURL}, dir, "Failed to set the URL of "+remote)
+}
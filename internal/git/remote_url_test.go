@@ -0,0 +1,56 @@
+package git
+
+import (
+	"context"
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRemoteURL(t *testing.T) {
+	tests := []struct {
+		raw      string
+		want     RemoteURL
+		switched string
+	}{
+		{"git@github.com:owner/repo.git", RemoteURL{Protocol: ProtocolSSH, Host: "github.com", Path: "owner/repo.git"}, "https://github.com/owner/repo.git"},
+		{"https://github.com/owner/repo.git", RemoteURL{Protocol: ProtocolHTTPS, Host: "github.com", Path: "owner/repo.git"}, "git@github.com:owner/repo.git"},
+		{"https://user@gitlab.example.com:8443/group/sub/repo", RemoteURL{Protocol: ProtocolHTTPS, Host: "gitlab.example.com", Path: "group/sub/repo"}, "git@gitlab.example.com:group/sub/repo"},
+		{"ssh://git@github.com:2222/owner/repo.git", RemoteURL{Protocol: ProtocolSSH, Host: "github.com", Path: "owner/repo.git"}, "https://github.com/owner/repo.git"},
+		{"git://example.com/repo.git", RemoteURL{Protocol: ProtocolGit, Host: "example.com", Path: "repo.git"}, ""},
+		{"/srv/git/repo.git", RemoteURL{Protocol: ProtocolFile, Path: "/srv/git/repo.git"}, ""},
+		{"./a:b", RemoteURL{Protocol: ProtocolFile, Path: "./a:b"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			got, ok := ParseRemoteURL(tt.raw)
+			require.True(t, ok)
+			tt.want.Raw = tt.raw
+			assert.Equal(t, tt.want, got)
+			switched, ok := got.Switched()
+			assert.Equal(t, tt.switched != "", ok)
+			assert.Equal(t, tt.switched, switched)
+		})
+	}
+
+	_, ok := ParseRemoteURL("  ")
+	assert.False(t, ok)
+}
+
+func TestSetRemoteURL(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	dir := t.TempDir()
+	runGit(t, dir, "init", "-q")
+	runGit(t, dir, "remote", "add", "origin", "git@github.com:owner/repo.git")
+
+	s := NewService(func(string, string) {}, func(string, string, string) {})
+	ctx := context.Background()
+	assert.Equal(t, "git@github.com:owner/repo.git", s.RemoteURL(ctx, "origin", dir))
+	require.True(t, s.SetRemoteURL(ctx, "origin", "https://github.com/owner/repo.git", dir))
+	assert.Equal(t, "https://github.com/owner/repo.git", s.RemoteURL(ctx, "origin", dir))
+	assert.Empty(t, s.RemoteURL(ctx, "missing", dir))
+}
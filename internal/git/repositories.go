@@ -0,0 +1,94 @@
+package git
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// maxRepositoryScanDepth bounds how deep KnownRepositories looks below the
+// worktree root, which holds <repo key>/<worktree> directories.
+const maxRepositoryScanDepth = 3
+
+// IsInsideRepository reports whether the current directory belongs to a git
+// repository, bare ones included. It never notifies, so it is safe to call
+// before the UI starts.
+func (s *Service) IsInsideRepository(ctx context.Context) bool {
+	return s.RunGit(ctx, []string{"git", "rev-parse", "--git-dir"}, "", []int{0}, true, true) != ""
+}
+
+// KnownRepositories returns the main repositories owning the worktrees found
+// under root, sorted by path. Worktrees whose repository no longer exists are
+// ignored.
+func KnownRepositories(root string) []string {
+	seen := map[string]bool{}
+	var repos []string
+	_ = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == root {
+				return err
+			}
+			return nil
+		}
+		rel, relErr := filepath.Rel(root, path)
+		if relErr != nil {
+			return nil
+		}
+		depth := 0
+		if rel != "." {
+			depth = len(strings.Split(rel, string(filepath.Separator)))
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" || depth > maxRepositoryScanDepth {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.Name() != ".git" {
+			return nil
+		}
+		repo := mainRepositoryFromGitFile(path)
+		if repo == "" || seen[repo] {
+			return nil
+		}
+		seen[repo] = true
+		repos = append(repos, repo)
+		return filepath.SkipDir
+	})
+	sort.Strings(repos)
+	return repos
+}
+
+// mainRepositoryFromGitFile resolves the main repository of a linked worktree
+// from its .git file, which reads "gitdir: <repo>/.git/worktrees/<name>".
+func mainRepositoryFromGitFile(path string) string {
+	// #nosec G304 - path comes from walking the configured worktree root
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	gitDir, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir:")
+	if !ok {
+		return ""
+	}
+	gitDir = strings.TrimSpace(gitDir)
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(filepath.Dir(path), gitDir)
+	}
+	worktrees := filepath.Dir(filepath.Clean(gitDir))
+	if filepath.Base(worktrees) != "worktrees" {
+		return ""
+	}
+	commonDir := filepath.Dir(worktrees)
+	if info, err := os.Stat(commonDir); err != nil || !info.IsDir() {
+		return ""
+	}
+	if filepath.Base(commonDir) == ".git" {
+		return filepath.Dir(commonDir)
+	}
+	// A bare repository keeps its worktrees directly under the repository.
+	return commonDir
+}
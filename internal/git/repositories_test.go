@@ -0,0 +1,59 @@
+package git
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsInsideRepository(t *testing.T) {
+	// Note: this test modifies the process working directory.
+	oldWd, err := os.Getwd()
+	require.NoError(t, err)
+	defer func() { _ = os.Chdir(oldWd) }()
+
+	notified := false
+	service := NewService(func(string, string) { notified = true }, func(string, string, string) { notified = true })
+
+	repo := t.TempDir()
+	require.NoError(t, exec.Command("git", "-C", repo, "init").Run())
+	require.NoError(t, os.Chdir(repo))
+	assert.True(t, service.IsInsideRepository(context.Background()))
+
+	require.NoError(t, os.Chdir(t.TempDir()))
+	assert.False(t, service.IsInsideRepository(context.Background()))
+	assert.False(t, notified, "detection must not notify")
+}
+
+func TestKnownRepositories(t *testing.T) {
+	root := t.TempDir()
+	repoA := t.TempDir()
+	repoB := t.TempDir()
+	for _, repo := range []string{repoA, repoB} {
+		require.NoError(t, os.MkdirAll(filepath.Join(repo, ".git", "worktrees", "feature"), 0o750))
+	}
+
+	writeGitFile := func(dir, content string) {
+		t.Helper()
+		require.NoError(t, os.MkdirAll(dir, 0o750))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, ".git"), []byte(content), 0o600))
+	}
+	writeGitFile(filepath.Join(root, "owner", "b", "feature"), "gitdir: "+filepath.Join(repoB, ".git", "worktrees", "feature")+"\n")
+	writeGitFile(filepath.Join(root, "a", "feature"), "gitdir: "+filepath.Join(repoA, ".git", "worktrees", "feature")+"\n")
+	writeGitFile(filepath.Join(root, "a", "other"), "gitdir: "+filepath.Join(repoA, ".git", "worktrees", "feature")+"\n")
+	writeGitFile(filepath.Join(root, "gone", "feature"), "gitdir: /nonexistent/.git/worktrees/feature\n")
+	writeGitFile(filepath.Join(root, "junk", "feature"), "not a git file\n")
+	writeGitFile(filepath.Join(root, "x", "y", "z", "too-deep"), "gitdir: "+filepath.Join(repoA, ".git", "worktrees", "feature")+"\n")
+
+	expected := []string{repoA, repoB}
+	if repoB < repoA {
+		expected = []string{repoB, repoA}
+	}
+	assert.Equal(t, expected, KnownRepositories(root))
+	assert.Empty(t, KnownRepositories(filepath.Join(root, "missing")))
+}
@@ -2,21 +2,26 @@
 package git
 
 import (
+	"bytes"
 	"context"
 	"crypto/sha256"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	neturl "net/url"
 	"os"
 	"os/exec"
 	"regexp"
-	"runtime"
 	"slices"
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/chmouel/lazyworktree/internal/commands"
 	"github.com/chmouel/lazyworktree/internal/config"
+	"github.com/chmouel/lazyworktree/internal/crash"
 	log "github.com/chmouel/lazyworktree/internal/log"
 	"github.com/chmouel/lazyworktree/internal/models"
 )
@@ -43,6 +48,14 @@ const (
 // so tests can mock it and avoid depending on system binaries being installed.
 var LookupPath = exec.LookPath
 
+// Remote URL patterns, compiled once since every repository load parses them.
+var (
+	remoteHostRe = regexp.MustCompile(`(?:git@|https?://|ssh://|git://)(?:[^@]+@)?([^/:]+)`)
+	githubRepoRe = regexp.MustCompile(`github\.com[:/](.+)(?:\.git)?$`)
+	gitlabRepoRe = regexp.MustCompile(`gitlab\.com[:/](.+)(?:\.git)?$`)
+	remoteRepoRe = regexp.MustCompile(`[:/]([^/]+/[^/]+)(?:\.git)?$`)
+)
+
 // NotifyFn receives ongoing notifications.
 type NotifyFn func(message string, severity string)
 
@@ -53,39 +66,37 @@ type NotifyOnceFn func(key string, message string, severity string)
 type Service struct {
 	notify       NotifyFn
 	notifyOnce   NotifyOnceFn
-	semaphore    chan struct{}
+	pools        [poolCount]*pool
 	mainBranch   string
 	gitHost      string
 	notifiedSet  map[string]bool
 	useGitPager  bool
 	gitPagerArgs []string
 	gitPager     string
+	divergence   string
+	fetch        FetchOptions
+	githubToken  string
+	gitlabToken  string
+	remote       *remoteHost // Set in SSH mode
+	noForge      bool        // Set in safe mode: gh and glab never run
+
+	prMissMu sync.Mutex
+	prMisses map[string]time.Time // branch -> when a per-branch lookup found no PR
 }
 
+// errForgeDisabled refuses gh and glab once DisableForge was called.
+var errForgeDisabled = errors.New("gh and glab are disabled in safe mode")
+
 // NewService constructs a Service and sets up concurrency limits.
 func NewService(notify NotifyFn, notifyOnce NotifyOnceFn) *Service {
-	limit := runtime.NumCPU() * 2
-	if limit < 4 {
-		limit = 4
-	}
-	if limit > 32 {
-		limit = 32
-	}
-
-	// Initialize counting semaphore: channel starts full with 'limit' tokens.
-	// acquireSemaphore() takes a token (blocks if none available), releaseSemaphore() returns it.
-	// This limits concurrent git operations to 'limit' goroutines.
-	semaphore := make(chan struct{}, limit)
-	for i := 0; i < limit; i++ {
-		semaphore <- struct{}{}
-	}
-
 	s := &Service{
 		notify:      notify,
 		notifyOnce:  notifyOnce,
-		semaphore:   semaphore,
 		notifiedSet: make(map[string]bool),
 	}
+	s.pools[PoolLocal] = newPool(defaultLocalLimit())
+	s.pools[PoolNetwork] = newPool(defaultNetworkLimit)
+	s.pools[PoolCommands] = newPool(defaultCommandLimit)
 
 	// Detect diff pager availability
 	s.detectGitPager()
@@ -108,6 +119,20 @@ func (s *Service) SetGitPager(pager string) {
 	s.detectGitPager()
 }
 
+// SetForgeTokens sets the tokens handed to gh and glab as GH_TOKEN and
+// GITLAB_TOKEN. Empty tokens leave the CLIs on their own login.
+func (s *Service) SetForgeTokens(github, gitlab string) {
+	s.githubToken = github
+	s.gitlabToken = gitlab
+}
+
+// DisableForge stops gh and glab from running, and treats the repository as
+// hosted on neither GitHub nor GitLab, so only git itself is invoked.
+func (s *Service) DisableForge() {
+	s.noForge = true
+	s.gitHost = gitHostUnknown
+}
+
 func (s *Service) isGitPagerAvailable() bool {
 	if s.gitPager == "" {
 		return false
@@ -120,7 +145,7 @@ func (s *Service) debugf(format string, args ...any) {
 	log.Printf(format, args...)
 }
 
-func prepareAllowedCommand(ctx context.Context, args []string) (*exec.Cmd, error) {
+func (s *Service) prepareAllowedCommand(ctx context.Context, args []string) (*exec.Cmd, error) {
 	if len(args) == 0 {
 		return nil, fmt.Errorf("no command provided")
 	}
@@ -128,13 +153,33 @@ func prepareAllowedCommand(ctx context.Context, args []string) (*exec.Cmd, error
 	switch args[0] {
 	case "git":
 		// #nosec G204 -- arguments for git command come from internal logic and are not shell interpolated
-		return exec.CommandContext(ctx, "git", args[1:]...), nil
+		cmd := exec.CommandContext(ctx, "git", args[1:]...)
+		// Background git must never block on a credential prompt.
+		cmd.Env = NonInteractiveEnv(os.Environ())
+		return cmd, nil
+	case "jj":
+		// #nosec G204 -- arguments for jj command come from internal logic and are not shell interpolated
+		return exec.CommandContext(ctx, "jj", args[1:]...), nil
 	case "glab":
+		if s.noForge {
+			return nil, errForgeDisabled
+		}
 		// #nosec G204 -- arguments for glab command are controlled by the application workflow
-		return exec.CommandContext(ctx, "glab", args[1:]...), nil
+		cmd := exec.CommandContext(ctx, "glab", args[1:]...)
+		if s.gitlabToken != "" {
+			cmd.Env = append(os.Environ(), "GITLAB_TOKEN="+s.gitlabToken)
+		}
+		return cmd, nil
 	case "gh":
+		if s.noForge {
+			return nil, errForgeDisabled
+		}
 		// #nosec G204 -- arguments for gh command are supplied by vetted code paths
-		return exec.CommandContext(ctx, "gh", args[1:]...), nil
+		cmd := exec.CommandContext(ctx, "gh", args[1:]...)
+		if s.githubToken != "" {
+			cmd.Env = append(os.Environ(), "GH_TOKEN="+s.githubToken)
+		}
+		return cmd, nil
 	default:
 		return nil, fmt.Errorf("unsupported command %q", args[0])
 	}
@@ -175,12 +220,29 @@ func (s *Service) UseGitPager() bool {
 
 // ExecuteCommands runs provided shell commands sequentially inside the given working directory.
 func (s *Service) ExecuteCommands(ctx context.Context, cmdList []string, cwd string, env map[string]string) error {
+	return s.ExecuteCommandsTo(ctx, cmdList, cwd, env, nil)
+}
+
+// ExecuteCommandsTo runs the commands like ExecuteCommands and, when out is
+// set, writes each command line and its combined output there as it comes.
+// Colour is forced on, as the output is shown in a terminal.
+func (s *Service) ExecuteCommandsTo(ctx context.Context, cmdList []string, cwd string, env map[string]string, out io.Writer) error {
 	for _, cmdStr := range cmdList {
 		if strings.TrimSpace(cmdStr) == "" {
 			continue
 		}
 
 		s.debugf("exec: %s (cwd=%s)", cmdStr, cwd)
+		if out != nil {
+			_, _ = fmt.Fprintf(out, "$ %s\n", cmdStr)
+		}
+		if cmdStr == "link_topsymlinks" && s.remote != nil {
+			// It links files of the local main worktree.
+			if out != nil {
+				_, _ = fmt.Fprintln(out, "skipped over SSH")
+			}
+			continue
+		}
 		if cmdStr == "link_topsymlinks" {
 			mainPath := env["MAIN_WORKTREE_PATH"]
 			wtPath := env["WORKTREE_PATH"]
@@ -198,9 +260,23 @@ func (s *Service) ExecuteCommands(ctx context.Context, cmdList []string, cwd str
 			command.Dir = cwd
 		}
 		command.Env = append(os.Environ(), formatEnv(env)...)
-		out, err := command.CombinedOutput()
+		if s.remote != nil {
+			s.remote.invalidate()
+			command = s.remote.command(ctx, []string{"bash", "-lc", cmdStr}, cwd, formatEnv(env))
+			command.Env = os.Environ()
+		}
+		var output bytes.Buffer
+		command.Stdout = &output
+		if out != nil {
+			command.Env = append(command.Env, "CLICOLOR_FORCE=1", "FORCE_COLOR=1")
+			command.Stdout = io.MultiWriter(&output, out)
+		}
+		command.Stderr = command.Stdout
+		release := s.Acquire(ctx, PoolCommands)
+		err := command.Run()
+		release()
 		if err != nil {
-			detail := strings.TrimSpace(string(out))
+			detail := strings.TrimSpace(output.String())
 			if detail != "" {
 				return fmt.Errorf("%s: %s", cmdStr, detail)
 			}
@@ -221,34 +297,63 @@ func formatEnv(env map[string]string) []string {
 	return formatted
 }
 
-func (s *Service) acquireSemaphore() {
-	<-s.semaphore
+// RunGit executes a git command and optionally trims its output. In SSH
+// mode the output of read-only commands is reused while fresh.
+func (s *Service) RunGit(ctx context.Context, args []string, cwd string, okReturncodes []int, strip, silent bool) string {
+	if s.remote == nil {
+		out, _ := s.runGit(ctx, args, cwd, okReturncodes, strip, silent)
+		return out
+	}
+	key := remoteCacheKey(args, cwd, strip)
+	if out, ok := s.remote.lookup(key); ok {
+		s.debugf("cached: %s (cwd=%s)", strings.Join(args, " "), cwd)
+		return out
+	}
+	start := time.Now()
+	out, ok := s.runGit(ctx, args, cwd, okReturncodes, strip, silent)
+	if ctx.Err() == nil {
+		s.remote.record(key, args, out, ok, time.Since(start))
+	}
+	return out
 }
 
-func (s *Service) releaseSemaphore() {
-	s.semaphore <- struct{}{}
+// InvalidateRemoteCache drops the command output cached in SSH mode, so the
+// next reads reach the host again. It does nothing for a local repository.
+func (s *Service) InvalidateRemoteCache() {
+	if s.remote != nil {
+		s.remote.invalidate()
+	}
 }
 
-// RunGit executes a git command and optionally trims its output.
-func (s *Service) RunGit(ctx context.Context, args []string, cwd string, okReturncodes []int, strip, silent bool) string {
+// runGit runs the command, reporting failures unless silenced. It also
+// reports whether the command succeeded, with an allowed exit code.
+func (s *Service) runGit(ctx context.Context, args []string, cwd string, okReturncodes []int, strip, silent bool) (string, bool) {
 	command := strings.Join(args, " ")
 	if command == "" {
 		command = "<empty>"
 	}
 	s.debugf("run: %s (cwd=%s)", command, cwd)
 
-	cmd, err := prepareAllowedCommand(ctx, args)
+	cmd, err := s.command(ctx, args, cwd)
+	if errors.Is(err, errForgeDisabled) {
+		s.debugf("skipped: %s (safe mode)", command)
+		return "", false
+	}
 	if err != nil {
 		key := fmt.Sprintf("unsupported_cmd:%s", command)
 		s.notifyOnce(key, fmt.Sprintf("Unsupported command: %s", command), "error")
 		s.debugf("error: %s (unsupported command)", command)
-		return ""
-	}
-	if cwd != "" {
-		cmd.Dir = cwd
+		return "", false
 	}
 
+	release := s.Acquire(ctx, commandPool(args))
 	output, err := cmd.Output()
+	release()
+	if err != nil && ctx.Err() != nil {
+		// The caller gave up on the command; its failure is expected.
+		s.debugf("cancelled: %s", command)
+		return "", false
+	}
 	if err != nil {
 		if exitError, ok := err.(*exec.ExitError); ok {
 			returnCode := exitError.ExitCode()
@@ -256,7 +361,7 @@ func (s *Service) RunGit(ctx context.Context, args []string, cwd string, okRetur
 			if !allowed {
 				if silent {
 					s.debugf("error: %s (exit %d, silenced)", command, returnCode)
-					return ""
+					return "", false
 				}
 				stderr := string(exitError.Stderr)
 				suffix := ""
@@ -265,10 +370,13 @@ func (s *Service) RunGit(ctx context.Context, args []string, cwd string, okRetur
 				} else {
 					suffix = fmt.Sprintf(" (exit %d)", returnCode)
 				}
+				if IsCredentialPrompt(stderr) {
+					suffix += "\n\nCredentials or a passphrase are required; please run the command in a terminal or load your key into an agent."
+				}
 				key := fmt.Sprintf("git_fail:%s:%s", cwd, command)
 				s.notifyOnce(key, fmt.Sprintf("Command failed: %s%s", command, suffix), "error")
 				s.debugf("error: %s%s", command, suffix)
-				return ""
+				return "", false
 			}
 		} else {
 			if !silent {
@@ -280,7 +388,7 @@ func (s *Service) RunGit(ctx context.Context, args []string, cwd string, okRetur
 				s.notifyOnce(key, fmt.Sprintf("Command not found: %s", command), "error")
 				s.debugf("error: command not found: %s", command)
 			}
-			return ""
+			return "", false
 		}
 	}
 
@@ -289,7 +397,7 @@ func (s *Service) RunGit(ctx context.Context, args []string, cwd string, okRetur
 		out = strings.TrimSpace(out)
 	}
 	s.debugf("ok: %s", command)
-	return out
+	return out, true
 }
 
 // RunCommandChecked runs the provided git command and reports failures via notify callbacks.
@@ -300,7 +408,7 @@ func (s *Service) RunCommandChecked(ctx context.Context, args []string, cwd, err
 	}
 	s.debugf("run: %s (cwd=%s)", command, cwd)
 
-	cmd, err := prepareAllowedCommand(ctx, args)
+	cmd, err := s.command(ctx, args, cwd)
 	if err != nil {
 		message := fmt.Sprintf("%s: %v", errorPrefix, err)
 		if errorPrefix == "" {
@@ -310,11 +418,10 @@ func (s *Service) RunCommandChecked(ctx context.Context, args []string, cwd, err
 		s.debugf("error: %s", message)
 		return false
 	}
-	if cwd != "" {
-		cmd.Dir = cwd
-	}
 
+	release := s.Acquire(ctx, commandPool(args))
 	output, err := cmd.CombinedOutput()
+	release()
 	if err != nil {
 		detail := strings.TrimSpace(string(output))
 		if detail != "" {
@@ -337,13 +444,19 @@ func (s *Service) GetMainBranch(ctx context.Context) string {
 		return s.mainBranch
 	}
 
-	out := s.RunGit(ctx, []string{"git", "symbolic-ref", "--short", "refs/remotes/origin/HEAD"}, "", []int{0}, true, false)
+	bare := s.BareRepoPath(ctx)
+	out := s.RunGit(ctx, []string{"git", "symbolic-ref", "--short", "refs/remotes/origin/HEAD"}, "", []int{0}, true, bare != "")
 	if out != "" {
 		parts := strings.Split(out, "/")
 		if len(parts) > 0 {
 			s.mainBranch = parts[len(parts)-1]
 		}
 	}
+	if s.mainBranch == "" && bare != "" {
+		// A bare clone keeps no remote-tracking branches; its HEAD names
+		// the default branch instead.
+		s.mainBranch = s.RunGit(ctx, []string{"git", "symbolic-ref", "--short", "HEAD"}, bare, []int{0}, true, true)
+	}
 	if s.mainBranch == "" {
 		s.mainBranch = "main"
 	}
@@ -400,6 +513,49 @@ func (s *Service) GetMergedBranches(ctx context.Context, baseBranch string) []st
 	return merged
 }
 
+// GetCommitMessages returns the non-merge commits in cwd that are not in
+// baseRef, oldest first.
+func (s *Service) GetCommitMessages(ctx context.Context, baseRef, cwd string) []models.CommitMessage {
+	output := s.RunGit(ctx, []string{
+		"git", "log", "--no-merges", "--reverse",
+		"--format=%h%x1f%B%x1e",
+		baseRef + "..HEAD",
+	}, cwd, []int{0}, false, false)
+	if strings.TrimSpace(output) == "" {
+		return nil
+	}
+
+	var commits []models.CommitMessage
+	for record := range strings.SplitSeq(output, "\x1e") {
+		record = strings.TrimSpace(record)
+		if record == "" {
+			continue
+		}
+		sha, message, _ := strings.Cut(record, "\x1f")
+		commits = append(commits, models.CommitMessage{SHA: sha, Message: strings.TrimSpace(message)})
+	}
+	return commits
+}
+
+// BranchChangedFiles lists the files, relative to the repository root, that
+// differ between baseRef's merge base and the worktree, including
+// uncommitted and untracked changes.
+func (s *Service) BranchChangedFiles(ctx context.Context, baseRef, cwd string) []string {
+	seen := make(map[string]bool)
+	var files []string
+	add := func(output string) {
+		for line := range strings.SplitSeq(output, "\n") {
+			if line = strings.TrimSpace(line); line != "" && !seen[line] {
+				seen[line] = true
+				files = append(files, line)
+			}
+		}
+	}
+	add(s.RunGit(ctx, []string{"git", "diff", "--name-only", "--no-renames", "--merge-base", baseRef}, cwd, []int{0}, true, true))
+	add(s.RunGit(ctx, []string{"git", "ls-files", "--others", "--exclude-standard", "--full-name"}, cwd, []int{0}, true, true))
+	return files
+}
+
 // GetWorktrees parses git worktree metadata and returns the list of worktrees.
 // This method concurrently fetches status information for each worktree to improve performance.
 // The first worktree in the list is marked as the main worktree.
@@ -409,170 +565,219 @@ func (s *Service) GetWorktrees(ctx context.Context) ([]*models.WorktreeInfo, err
 		return []*models.WorktreeInfo{}, nil
 	}
 
-	type wtData struct {
-		path   string
-		branch string
-		isMain bool
-	}
+	wts := parseWorktreeList(rawWts)
+	activity := s.branchActivity(ctx, "refs/heads")
 
-	var wts []wtData
-	var currentWt *wtData
+	// Get worktree info concurrently
+	results := make(chan *models.WorktreeInfo, len(wts))
+	var wg sync.WaitGroup
 
-	lines := strings.SplitSeq(rawWts, "\n")
-	for line := range lines {
-		if strings.HasPrefix(line, "worktree ") {
-			if currentWt != nil {
-				wts = append(wts, *currentWt)
-			}
-			path := strings.TrimPrefix(line, "worktree ")
-			currentWt = &wtData{path: path}
-		} else if strings.HasPrefix(line, "branch ") {
-			if currentWt != nil {
-				branch := strings.TrimPrefix(line, "branch ")
-				branch = strings.TrimPrefix(branch, "refs/heads/")
-				currentWt.branch = branch
-			}
-		}
+	for _, wt := range wts {
+		wg.Add(1)
+		go func(entry *models.WorktreeInfo) {
+			defer wg.Done()
+			defer crash.Recover()
+			results <- s.worktreeStatus(ctx, entry, activity)
+		}(wt)
 	}
-	if currentWt != nil {
-		wts = append(wts, *currentWt)
+
+	wg.Wait()
+	close(results)
+
+	worktrees := make([]*models.WorktreeInfo, 0, len(wts))
+	for wt := range results {
+		worktrees = append(worktrees, wt)
 	}
 
-	// Mark first as main
-	for i := range wts {
-		wts[i].isMain = (i == 0)
+	return worktrees, nil
+}
+
+// RefreshWorktree recomputes the status, upstream divergence and branch
+// activity of the single worktree at path, without enumerating the others.
+func (s *Service) RefreshWorktree(ctx context.Context, path string) (*models.WorktreeInfo, error) {
+	rawWts := s.RunGit(ctx, []string{"git", "worktree", "list", "--porcelain"}, "", []int{0}, true, true)
+	for _, entry := range parseWorktreeList(rawWts) {
+		if entry.Path != path {
+			continue
+		}
+		activity := map[string]branchActivity{}
+		if entry.Branch != "" {
+			activity = s.branchActivity(ctx, "refs/heads/"+entry.Branch)
+		}
+		return s.worktreeStatus(ctx, entry, activity), nil
 	}
+	return nil, fmt.Errorf("worktree %s not found", path)
+}
 
-	branchRaw := s.RunGit(ctx, []string{
+// branchActivity holds when a branch last received a commit.
+type branchActivity struct {
+	lastActive   string
+	lastActiveTS int64
+}
+
+// branchActivity returns the last commit date of the branches matching the
+// given ref patterns, keyed by short branch name.
+func (s *Service) branchActivity(ctx context.Context, patterns ...string) map[string]branchActivity {
+	args := append([]string{
 		"git", "for-each-ref",
 		"--format=%(refname:short)|%(committerdate:relative)|%(committerdate:unix)",
-		"refs/heads",
-	}, "", []int{0}, true, false)
-
-	branchInfo := make(map[string]struct {
-		lastActive   string
-		lastActiveTS int64
-	})
+	}, patterns...)
+	branchRaw := s.RunGit(ctx, args, "", []int{0}, true, false)
 
+	activity := make(map[string]branchActivity)
 	for line := range strings.SplitSeq(branchRaw, "\n") {
-		if strings.Contains(line, "|") {
-			parts := strings.Split(line, "|")
-			if len(parts) == 3 {
-				branch := parts[0]
-				lastActive := parts[1]
-				lastActiveTS, _ := strconv.ParseInt(parts[2], 10, 64)
-				branchInfo[branch] = struct {
-					lastActive   string
-					lastActiveTS int64
-				}{lastActive: lastActive, lastActiveTS: lastActiveTS}
-			}
+		parts := strings.Split(line, "|")
+		if len(parts) != 3 {
+			continue
 		}
+		lastActiveTS, _ := strconv.ParseInt(parts[2], 10, 64)
+		activity[parts[0]] = branchActivity{lastActive: parts[1], lastActiveTS: lastActiveTS}
 	}
+	return activity
+}
 
-	// Get worktree info concurrently
-	type result struct {
-		wt  *models.WorktreeInfo
-		err error
-	}
-
-	results := make(chan result, len(wts))
-	var wg sync.WaitGroup
-
-	for _, wt := range wts {
-		wg.Add(1)
-		go func(wtData wtData) {
-			defer wg.Done()
-			s.acquireSemaphore()
-			defer s.releaseSemaphore()
+// worktreeStatus completes a parsed worktree entry with its working tree
+// status and upstream divergence.
+func (s *Service) worktreeStatus(ctx context.Context, entry *models.WorktreeInfo, activity map[string]branchActivity) *models.WorktreeInfo {
+	path := entry.Path
+	branch := entry.Branch
+	switch {
+	case entry.Bare:
+		branch = "(bare)"
+	case branch == "":
+		branch = "(detached)"
+	}
+
+	// A bare repository has no working tree and a prunable worktree's
+	// directory is gone, so neither has a status to read.
+	statusRaw := ""
+	if !entry.Bare && !entry.Prunable {
+		statusRaw = s.RunGit(ctx, []string{"git", "status", "--porcelain=v2", "--branch"}, path, []int{0}, true, false)
+	}
+
+	ahead := 0
+	behind := 0
+	hasUpstream := false
+	upstreamBranch := ""
+	untracked := 0
+	modified := 0
+	staged := 0
 
-			path := wtData.path
-			branch := wtData.branch
-			if branch == "" {
-				branch = "(detached)"
+	for _, line := range strings.Split(statusRaw, "\n") {
+		switch {
+		case strings.HasPrefix(line, "# branch.upstream "):
+			hasUpstream = true
+			upstreamBranch = strings.TrimPrefix(line, "# branch.upstream ")
+		case strings.HasPrefix(line, "# branch.ab "):
+			// branch.ab only appears when upstream is set per Git porcelain v2 spec
+			hasUpstream = true
+			parts := strings.Fields(line)
+			if len(parts) >= 4 {
+				aheadStr := strings.TrimPrefix(parts[2], "+")
+				behindStr := strings.TrimPrefix(parts[3], "-")
+				ahead, _ = strconv.Atoi(aheadStr)
+				behind, _ = strconv.Atoi(behindStr)
 			}
-
-			statusRaw := s.RunGit(ctx, []string{"git", "status", "--porcelain=v2", "--branch"}, path, []int{0}, true, false)
-
-			ahead := 0
-			behind := 0
-			hasUpstream := false
-			upstreamBranch := ""
-			untracked := 0
-			modified := 0
-			staged := 0
-
-			for _, line := range strings.Split(statusRaw, "\n") {
-				switch {
-				case strings.HasPrefix(line, "# branch.upstream "):
-					hasUpstream = true
-					upstreamBranch = strings.TrimPrefix(line, "# branch.upstream ")
-				case strings.HasPrefix(line, "# branch.ab "):
-					// branch.ab only appears when upstream is set per Git porcelain v2 spec
-					hasUpstream = true
-					parts := strings.Fields(line)
-					if len(parts) >= 4 {
-						aheadStr := strings.TrimPrefix(parts[2], "+")
-						behindStr := strings.TrimPrefix(parts[3], "-")
-						ahead, _ = strconv.Atoi(aheadStr)
-						behind, _ = strconv.Atoi(behindStr)
+		case strings.HasPrefix(line, "?"):
+			untracked++
+		case strings.HasPrefix(line, "1 "), strings.HasPrefix(line, "2 "):
+			parts := strings.Fields(line)
+			if len(parts) > 1 {
+				xy := parts[1]
+				if len(xy) >= 2 {
+					if xy[0] != '.' {
+						staged++
 					}
-				case strings.HasPrefix(line, "?"):
-					untracked++
-				case strings.HasPrefix(line, "1 "), strings.HasPrefix(line, "2 "):
-					parts := strings.Fields(line)
-					if len(parts) > 1 {
-						xy := parts[1]
-						if len(xy) >= 2 {
-							if xy[0] != '.' {
-								staged++
-							}
-							if xy[1] != '.' {
-								modified++
-							}
-						}
+					if xy[1] != '.' {
+						modified++
 					}
 				}
 			}
-
-			info, exists := branchInfo[branch]
-			lastActive := ""
-			lastActiveTS := int64(0)
-			if exists {
-				lastActive = info.lastActive
-				lastActiveTS = info.lastActiveTS
-			}
-
-			wt := &models.WorktreeInfo{
-				Path:           path,
-				Branch:         branch,
-				IsMain:         wtData.isMain,
-				Dirty:          (untracked + modified + staged) > 0,
-				Ahead:          ahead,
-				Behind:         behind,
-				HasUpstream:    hasUpstream,
-				UpstreamBranch: upstreamBranch,
-				LastActive:     lastActive,
-				LastActiveTS:   lastActiveTS,
-				Untracked:      untracked,
-				Modified:       modified,
-				Staged:         staged,
-			}
-
-			results <- result{wt: wt, err: nil}
-		}(wt)
+		}
 	}
 
-	wg.Wait()
-	close(results)
+	divergenceRef := upstreamBranch
+	if s.divergence != "" && statusRaw != "" {
+		if a, b, ok := s.divergenceFrom(ctx, s.divergence, path); ok {
+			ahead, behind = a, b
+			hasUpstream = true
+			divergenceRef = s.divergence
+		}
+	}
+
+	info := activity[branch]
+	return &models.WorktreeInfo{
+		Path:           path,
+		Branch:         branch,
+		IsMain:         entry.IsMain,
+		Head:           entry.Head,
+		Detached:       entry.Detached,
+		Bare:           entry.Bare,
+		Locked:         entry.Locked,
+		LockReason:     entry.LockReason,
+		Prunable:       entry.Prunable,
+		PrunableReason: entry.PrunableReason,
+		Sparse:         !entry.Bare && !entry.Prunable && isSparseCheckout(path),
+		Dirty:          (untracked + modified + staged) > 0,
+		Ahead:          ahead,
+		Behind:         behind,
+		HasUpstream:    hasUpstream,
+		UpstreamBranch: upstreamBranch,
+		DivergenceRef:  divergenceRef,
+		LastFetchTS:    lastFetchTime(path, entry.Bare),
+		Owner:          worktreeOwner(entry),
+		LastActive:     info.lastActive,
+		LastActiveTS:   info.lastActiveTS,
+		Untracked:      untracked,
+		Modified:       modified,
+		Staged:         staged,
+	}
+}
 
-	worktrees := make([]*models.WorktreeInfo, 0, len(wts))
-	for r := range results {
-		if r.err == nil {
-			worktrees = append(worktrees, r.wt)
-		}
+// worktreeOwner returns who created the worktree; a prunable worktree's
+// directory is gone, so it has no owner to read.
+func worktreeOwner(entry *models.WorktreeInfo) string {
+	if entry.Prunable {
+		return ""
 	}
+	return WorktreeOwner(entry.Path)
+}
 
-	return worktrees, nil
+// parseWorktreeList parses the output of git worktree list --porcelain. The
+// first entry is always the main worktree, or the repository itself when bare.
+// Locked and prunable entries may carry a reason after the attribute name.
+func parseWorktreeList(raw string) []*models.WorktreeInfo {
+	var wts []*models.WorktreeInfo
+	var current *models.WorktreeInfo
+	for line := range strings.SplitSeq(raw, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if path, ok := strings.CutPrefix(line, "worktree "); ok {
+			current = &models.WorktreeInfo{Path: path, IsMain: len(wts) == 0}
+			wts = append(wts, current)
+			continue
+		}
+		if current == nil {
+			continue
+		}
+		attr, value, _ := strings.Cut(line, " ")
+		switch attr {
+		case "HEAD":
+			current.Head = value
+		case "branch":
+			current.Branch = strings.TrimPrefix(value, "refs/heads/")
+		case "detached":
+			current.Detached = true
+		case "bare":
+			current.Bare = true
+		case "locked":
+			current.Locked = true
+			current.LockReason = value
+		case "prunable":
+			current.Prunable = true
+			current.PrunableReason = value
+		}
+	}
+	return wts
 }
 
 // DetectHost detects the git host (github, gitlab, or unknown)
@@ -583,8 +788,7 @@ func (s *Service) DetectHost(ctx context.Context) string {
 
 	remoteURL := s.RunGit(ctx, []string{"git", "remote", "get-url", "origin"}, "", []int{0}, true, true)
 	if remoteURL != "" {
-		re := regexp.MustCompile(`(?:git@|https?://|ssh://|git://)(?:[^@]+@)?([^/:]+)`)
-		matches := re.FindStringSubmatch(remoteURL)
+		matches := remoteHostRe.FindStringSubmatch(remoteURL)
 		if len(matches) > 1 {
 			hostname := strings.ToLower(matches[1])
 			if strings.Contains(hostname, gitHostGitLab) {
@@ -621,6 +825,11 @@ func (s *Service) fetchGitLabPRs(ctx context.Context) (map[string]*models.PRInfo
 		return nil, err
 	}
 
+	return gitLabPRMap(prs), nil
+}
+
+// gitLabPRMap converts decoded GitLab merge requests into a map keyed by source branch.
+func gitLabPRMap(prs []map[string]any) map[string]*models.PRInfo {
 	prMap := make(map[string]*models.PRInfo)
 	for _, p := range prs {
 		state, _ := p["state"].(string)
@@ -634,6 +843,7 @@ func (s *Service) fetchGitLabPRs(ctx context.Context) (map[string]*models.PRInfo
 		description, _ := p["description"].(string)
 		webURL, _ := p["web_url"].(string)
 		sourceBranch, _ := p["source_branch"].(string)
+		isDraft, _ := p["draft"].(bool)
 
 		author := ""
 		authorName := ""
@@ -661,11 +871,34 @@ func (s *Service) fetchGitLabPRs(ctx context.Context) (map[string]*models.PRInfo
 				Author:      author,
 				AuthorName:  authorName,
 				AuthorIsBot: authorIsBot,
+				IsDraft:     isDraft,
+				Labels:      prLabels(p["labels"]),
 			}
 		}
 	}
 
-	return prMap, nil
+	return prMap
+}
+
+// prLabels extracts label names from GitHub ([{"name": ...}]) or GitLab
+// (["name", ...]) label lists.
+func prLabels(raw any) []string {
+	items, ok := raw.([]any)
+	if !ok || len(items) == 0 {
+		return nil
+	}
+	labels := make([]string, 0, len(items))
+	for _, item := range items {
+		switch v := item.(type) {
+		case string:
+			labels = append(labels, v)
+		case map[string]any:
+			if name, ok := v["name"].(string); ok {
+				labels = append(labels, name)
+			}
+		}
+	}
+	return labels
 }
 
 // FetchPRMap gathers PR/MR information via supported host APIs (GitHub or GitLab).
@@ -687,7 +920,7 @@ func (s *Service) FetchPRMap(ctx context.Context) (map[string]*models.PRInfo, er
 	prRaw := s.RunGit(ctx, []string{
 		"gh", "pr", "list",
 		"--state", "all",
-		"--json", "headRefName,state,number,title,body,url,author",
+		"--json", "headRefName,state,number,title,body,url,author,isDraft,labels",
 		"--limit", "100",
 	}, "", []int{0}, false, host == gitHostUnknown)
 
@@ -702,6 +935,11 @@ func (s *Service) FetchPRMap(ctx context.Context) (map[string]*models.PRInfo, er
 		return nil, err
 	}
 
+	return gitHubPRMap(prs), nil
+}
+
+// gitHubPRMap converts decoded gh pr list output into a map keyed by head branch.
+func gitHubPRMap(prs []map[string]any) map[string]*models.PRInfo {
 	prMap := make(map[string]*models.PRInfo)
 	for _, p := range prs {
 		headRefName, _ := p["headRefName"].(string)
@@ -710,6 +948,7 @@ func (s *Service) FetchPRMap(ctx context.Context) (map[string]*models.PRInfo, er
 		title, _ := p["title"].(string)
 		body, _ := p["body"].(string)
 		url, _ := p["url"].(string)
+		isDraft, _ := p["isDraft"].(bool)
 
 		author := ""
 		authorName := ""
@@ -737,13 +976,196 @@ func (s *Service) FetchPRMap(ctx context.Context) (map[string]*models.PRInfo, er
 				Author:      author,
 				AuthorName:  authorName,
 				AuthorIsBot: authorIsBot,
+				IsDraft:     isDraft,
+				Labels:      prLabels(p["labels"]),
 			}
 		}
 	}
 
+	return prMap
+}
+
+const (
+	// PRLookupTTL bounds how long a per-branch PR lookup is reused, including
+	// one that found no PR.
+	PRLookupTTL = 5 * time.Minute
+	// PRLookupConcurrency caps simultaneous per-branch gh/glab lookups.
+	PRLookupConcurrency = 4
+)
+
+// FetchPRMapForBranches returns PR/MR data for the given branches. On GitHub
+// it asks for every branch in batched GraphQL requests, including review
+// decision and check rollups. Otherwise, or when those requests fail, it uses
+// FetchPRMap and looks up any branches the recent listing missed one by one,
+// so PRs older than the listing limit still resolve on busy repositories.
+// Branches found to have no PR are not looked up again for PRLookupTTL.
+func (s *Service) FetchPRMapForBranches(ctx context.Context, branches []string) (map[string]*models.PRInfo, error) {
+	host := s.DetectHost(ctx)
+	if host == gitHostUnknown {
+		return make(map[string]*models.PRInfo), nil
+	}
+
+	mainBranch := s.GetMainBranch(ctx)
+	seen := make(map[string]bool)
+	candidates := make([]string, 0, len(branches))
+	for _, branch := range branches {
+		branch = strings.TrimSpace(branch)
+		if branch == "" || branch == "(detached)" || branch == mainBranch || seen[branch] {
+			continue
+		}
+		seen[branch] = true
+		candidates = append(candidates, branch)
+	}
+
+	if host == gitHostGithub && len(candidates) > 0 {
+		prMap, err := s.fetchGitHubPRsGraphQL(ctx, candidates)
+		if err == nil {
+			return prMap, nil
+		}
+		s.debugf("graphql PR fetch failed, falling back to gh pr list: %v", err)
+	}
+
+	prMap, err := s.FetchPRMap(ctx)
+	if err != nil {
+		return prMap, err
+	}
+	missing := make([]string, 0, len(candidates))
+	for _, branch := range candidates {
+		if _, ok := prMap[branch]; !ok && !s.knownWithoutPR(branch) {
+			missing = append(missing, branch)
+		}
+	}
+	if len(missing) == 0 {
+		return prMap, nil
+	}
+	s.debugf("looking up %d branch(es) missing from PR listing", len(missing))
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, PRLookupConcurrency)
+	for _, branch := range missing {
+		wg.Add(1)
+		go func(branch string) {
+			defer wg.Done()
+			defer crash.Recover()
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			defer func() { <-slots }()
+			pr, err := s.fetchPRForBranch(ctx, host, branch)
+			if err != nil {
+				s.debugf("PR lookup for %s failed: %v", branch, err)
+				return
+			}
+			if pr == nil {
+				s.recordNoPR(branch)
+				return
+			}
+			mu.Lock()
+			prMap[branch] = pr
+			mu.Unlock()
+		}(branch)
+	}
+	wg.Wait()
+
 	return prMap, nil
 }
 
+// knownWithoutPR reports whether a lookup within PRLookupTTL found no PR
+// for branch.
+func (s *Service) knownWithoutPR(branch string) bool {
+	s.prMissMu.Lock()
+	defer s.prMissMu.Unlock()
+	at, ok := s.prMisses[branch]
+	if ok && time.Since(at) >= PRLookupTTL {
+		delete(s.prMisses, branch)
+		return false
+	}
+	return ok
+}
+
+// recordNoPR remembers that branch has no PR for PRLookupTTL.
+func (s *Service) recordNoPR(branch string) {
+	s.prMissMu.Lock()
+	defer s.prMissMu.Unlock()
+	if s.prMisses == nil {
+		s.prMisses = make(map[string]time.Time)
+	}
+	s.prMisses[branch] = time.Now()
+}
+
+// fetchPRForBranch returns the most recent PR/MR whose head is branch, or nil
+// when the forge answered that there is none. A failed or unreadable lookup,
+// such as a rate limit or network error, is an error instead.
+func (s *Service) fetchPRForBranch(ctx context.Context, host, branch string) (*models.PRInfo, error) {
+	var raw string
+	if host == gitHostGitLab {
+		raw = s.RunGit(ctx, []string{
+			"glab", "api",
+			fmt.Sprintf("merge_requests?state=all&per_page=1&source_branch=%s", neturl.QueryEscape(branch)),
+		}, "", []int{0}, false, true)
+	} else {
+		raw = s.RunGit(ctx, []string{
+			"gh", "pr", "list",
+			"--state", "all",
+			"--head", branch,
+			"--json", "headRefName,state,number,title,body,url,author,isDraft,labels",
+			"--limit", "1",
+		}, "", []int{0}, false, true)
+	}
+	// Even an empty answer is a JSON list, so no output means the call failed.
+	if raw == "" {
+		return nil, fmt.Errorf("no answer from the forge")
+	}
+
+	var prs []map[string]any
+	if err := json.Unmarshal([]byte(raw), &prs); err != nil {
+		return nil, fmt.Errorf("failed to parse PR lookup: %w", err)
+	}
+	if host == gitHostGitLab {
+		return gitLabPRMap(prs)[branch], nil
+	}
+	return gitHubPRMap(prs)[branch], nil
+}
+
+// UpstreamHeadBranch returns the remote branch name a local branch tracks, read
+// from branch.<name>.merge. It returns an empty string when the branch has no
+// tracking configuration, tracks a branch of the same name or the main
+// branch, or tracks a local branch rather than a configured remote: worktrees
+// created from origin/main or another local branch track those too, yet are
+// not checkouts of a PR head.
+func (s *Service) UpstreamHeadBranch(ctx context.Context, branch, worktreePath string) string {
+	branch = strings.TrimSpace(branch)
+	if branch == "" || branch == "(detached)" {
+		return ""
+	}
+	remote := s.RunGit(ctx, []string{"git", "config", "--get", fmt.Sprintf("branch.%s.remote", branch)}, worktreePath, []int{0, 1}, true, true)
+	if remote == "" || remote == "." {
+		return ""
+	}
+	if s.RunGit(ctx, []string{"git", "config", "--get", fmt.Sprintf("remote.%s.url", remote)}, worktreePath, []int{0, 1}, true, true) == "" {
+		return ""
+	}
+	mergeRef := s.RunGit(ctx, []string{"git", "config", "--get", fmt.Sprintf("branch.%s.merge", branch)}, worktreePath, []int{0, 1}, true, true)
+	head := strings.TrimPrefix(mergeRef, "refs/heads/")
+	if head == "" || head == mergeRef || head == branch || head == s.GetMainBranch(ctx) {
+		return ""
+	}
+	return head
+}
+
+// BranchDescription returns the branch.<name>.description note, as set by
+// "git branch --edit-description", or an empty string.
+func (s *Service) BranchDescription(ctx context.Context, branch, worktreePath string) string {
+	branch = strings.TrimSpace(branch)
+	if branch == "" || branch == "(detached)" {
+		return ""
+	}
+	return s.RunGit(ctx, []string{"git", "config", "--get", fmt.Sprintf("branch.%s.description", branch)}, worktreePath, []int{0, 1}, true, true)
+}
+
 // FetchPRForWorktreeWithError fetches PR info and returns detailed error information.
 func (s *Service) FetchPRForWorktreeWithError(ctx context.Context, worktreePath string) (*models.PRInfo, error) {
 	host := s.DetectHost(ctx)
@@ -753,7 +1175,7 @@ func (s *Service) FetchPRForWorktreeWithError(ctx context.Context, worktreePath
 		// Run gh pr view with silent=false to capture actual errors
 		prRaw := s.RunGit(ctx, []string{
 			"gh", "pr", "view",
-			"--json", "number,state,title,body,url,headRefName,baseRefName,author",
+			"--json", "number,state,title,body,url,headRefName,baseRefName,author,isDraft,labels",
 		}, worktreePath, []int{0, 1}, false, false)
 
 		if prRaw == "" {
@@ -777,6 +1199,7 @@ func (s *Service) FetchPRForWorktreeWithError(ctx context.Context, worktreePath
 		url, _ := pr["url"].(string)
 		headRefName, _ := pr["headRefName"].(string)
 		baseRefName, _ := pr["baseRefName"].(string)
+		isDraft, _ := pr["isDraft"].(bool)
 
 		author := ""
 		authorName := ""
@@ -804,6 +1227,8 @@ func (s *Service) FetchPRForWorktreeWithError(ctx context.Context, worktreePath
 			Author:      author,
 			AuthorName:  authorName,
 			AuthorIsBot: authorIsBot,
+			IsDraft:     isDraft,
+			Labels:      prLabels(pr["labels"]),
 		}, nil
 
 	case gitHostGitLab:
@@ -839,6 +1264,7 @@ func (s *Service) FetchPRForWorktreeWithError(ctx context.Context, worktreePath
 		webURL, _ := pr["web_url"].(string)
 		sourceBranch, _ := pr["source_branch"].(string)
 		targetBranch, _ := pr["target_branch"].(string)
+		isDraft, _ := pr["draft"].(bool)
 
 		author := ""
 		authorName := ""
@@ -866,6 +1292,8 @@ func (s *Service) FetchPRForWorktreeWithError(ctx context.Context, worktreePath
 			Author:      author,
 			AuthorName:  authorName,
 			AuthorIsBot: authorIsBot,
+			IsDraft:     isDraft,
+			Labels:      prLabels(pr["labels"]),
 		}, nil
 	}
 
@@ -882,18 +1310,31 @@ func (s *Service) FetchPRForWorktree(ctx context.Context, worktreePath string) *
 
 // FetchAllOpenPRs fetches all open PRs/MRs and returns them as a slice.
 func (s *Service) FetchAllOpenPRs(ctx context.Context) ([]*models.PRInfo, error) {
+	return s.fetchOpenPRs(ctx, false)
+}
+
+// FetchMyOpenPRs fetches open PRs/MRs authored by the authenticated user.
+func (s *Service) FetchMyOpenPRs(ctx context.Context) ([]*models.PRInfo, error) {
+	return s.fetchOpenPRs(ctx, true)
+}
+
+func (s *Service) fetchOpenPRs(ctx context.Context, mine bool) ([]*models.PRInfo, error) {
 	host := s.DetectHost(ctx)
 	if host == gitHostGitLab {
-		return s.fetchGitLabOpenPRs(ctx)
+		return s.fetchGitLabOpenPRs(ctx, mine)
 	}
 
 	// Default to GitHub
-	prRaw := s.RunGit(ctx, []string{
+	args := []string{
 		"gh", "pr", "list",
 		"--state", "open",
 		"--json", "headRefName,state,number,title,body,url,author,isDraft,statusCheckRollup",
 		"--limit", "100",
-	}, "", []int{0}, false, host == gitHostUnknown)
+	}
+	if mine {
+		args = append(args, "--author", "@me")
+	}
+	prRaw := s.RunGit(ctx, args, "", []int{0}, false, host == gitHostUnknown)
 
 	if prRaw == "" {
 		return []*models.PRInfo{}, nil
@@ -954,8 +1395,12 @@ func (s *Service) FetchAllOpenPRs(ctx context.Context) ([]*models.PRInfo, error)
 	return result, nil
 }
 
-func (s *Service) fetchGitLabOpenPRs(ctx context.Context) ([]*models.PRInfo, error) {
-	prRaw := s.RunGit(ctx, []string{"glab", "api", "merge_requests?state=opened&per_page=100"}, "", []int{0}, false, false)
+func (s *Service) fetchGitLabOpenPRs(ctx context.Context, mine bool) ([]*models.PRInfo, error) {
+	endpoint := "merge_requests?state=opened&per_page=100"
+	if mine {
+		endpoint += "&scope=created_by_me"
+	}
+	prRaw := s.RunGit(ctx, []string{"glab", "api", endpoint}, "", []int{0}, false, false)
 	if prRaw == "" {
 		return []*models.PRInfo{}, nil
 	}
@@ -1315,6 +1760,16 @@ func (s *Service) gitlabStatusToConclusion(status string) string {
 	}
 }
 
+// BareRepoPath returns the path of the repository when it is bare, its
+// worktrees all linked ones, or an empty string otherwise.
+func (s *Service) BareRepoPath(ctx context.Context) string {
+	rawWts := s.RunGit(ctx, []string{"git", "worktree", "list", "--porcelain"}, "", []int{0}, true, true)
+	if wts := parseWorktreeList(rawWts); len(wts) > 0 && wts[0].Bare {
+		return wts[0].Path
+	}
+	return ""
+}
+
 // GetMainWorktreePath returns the path of the main worktree.
 func (s *Service) GetMainWorktreePath(ctx context.Context) string {
 	rawWts := s.RunGit(ctx, []string{"git", "worktree", "list", "--porcelain"}, "", []int{0}, true, false)
@@ -1460,13 +1915,14 @@ func (s *Service) CherryPickCommit(ctx context.Context, commitSHA, targetPath st
 	}
 
 	// Attempt cherry-pick
-	cmd, err := prepareAllowedCommand(ctx, []string{"git", "cherry-pick", commitSHA})
+	cmd, err := s.command(ctx, []string{"git", "cherry-pick", commitSHA}, targetPath)
 	if err != nil {
 		return false, err
 	}
-	cmd.Dir = targetPath
 
+	release := s.Acquire(ctx, PoolLocal)
 	output, err := cmd.CombinedOutput()
+	release()
 	if err != nil {
 		// Cherry-pick failed - check if it's due to conflicts
 		detail := strings.TrimSpace(string(output))
@@ -1504,14 +1960,12 @@ func (s *Service) ResolveRepoName(ctx context.Context) string {
 	// Optimization: If it's a standard GitHub/GitLab URL, parse directly and avoid external tool overhead
 	if remoteURL != "" {
 		if strings.Contains(remoteURL, "github.com") {
-			re := regexp.MustCompile(`github\.com[:/](.+)(?:\.git)?$`)
-			matches := re.FindStringSubmatch(remoteURL)
+			matches := githubRepoRe.FindStringSubmatch(remoteURL)
 			if len(matches) > 1 {
 				repoName = matches[1]
 			}
 		} else if strings.Contains(remoteURL, "gitlab.com") {
-			re := regexp.MustCompile(`gitlab\.com[:/](.+)(?:\.git)?$`)
-			matches := re.FindStringSubmatch(remoteURL)
+			matches := gitlabRepoRe.FindStringSubmatch(remoteURL)
 			if len(matches) > 1 {
 				repoName = matches[1]
 			}
@@ -1539,13 +1993,18 @@ func (s *Service) ResolveRepoName(ctx context.Context) string {
 
 	if repoName == "" && remoteURL != "" {
 		// Fallback: Parse remote URL if we have it (even if not github/gitlab, maybe self-hosted?)
-		re := regexp.MustCompile(`[:/]([^/]+/[^/]+)(?:\.git)?$`)
-		matches := re.FindStringSubmatch(remoteURL)
+		matches := remoteRepoRe.FindStringSubmatch(remoteURL)
 		if len(matches) > 1 {
 			repoName = matches[1]
 		}
 	}
 
+	if repoName == "" {
+		// A bare repository has no top level, and each of its worktrees
+		// has its own, so key it by the repository itself.
+		repoName = localRepoKey(s.BareRepoPath(ctx))
+	}
+
 	if repoName == "" {
 		// Try git rev-parse --show-toplevel
 		if out := s.RunGit(ctx, []string{"git", "rev-parse", "--show-toplevel"}, "", []int{0}, true, true); out != "" {
@@ -65,7 +65,7 @@ func TestFetchGitLabOpenPRs(t *testing.T) {
 	withStubbedPath(t, dir)
 
 	service := NewService(func(string, string) {}, func(string, string, string) {})
-	prs, err := service.fetchGitLabOpenPRs(context.Background())
+	prs, err := service.fetchGitLabOpenPRs(context.Background(), false)
 	require.NoError(t, err)
 	require.Len(t, prs, 1)
 	assert.Equal(t, "feature", prs[0].Branch)
@@ -106,3 +106,21 @@ func TestFetchGitLabCIFallbackArray(t *testing.T) {
 	require.Len(t, checks, 1)
 	assert.Equal(t, ciSkipped, checks[0].Conclusion)
 }
+
+func TestFetchMyOpenPRsGitLab(t *testing.T) {
+	stub := "#!/bin/sh\n" +
+		"if [ \"$1\" = \"api\" ] && [ \"$2\" = \"merge_requests?state=opened&per_page=100&scope=created_by_me\" ]; then\n" +
+		"  echo '[{\"iid\":4,\"state\":\"opened\",\"title\":\"Mine\",\"web_url\":\"https://example.com/4\",\"source_branch\":\"mine\"}]'\n" +
+		"  exit 0\n" +
+		"fi\n" +
+		"exit 1\n"
+	dir := writeStub(t, "glab", stub)
+	withStubbedPath(t, dir)
+
+	service := NewService(func(string, string) {}, func(string, string, string) {})
+	service.gitHost = gitHostGitLab
+	prs, err := service.FetchMyOpenPRs(context.Background())
+	require.NoError(t, err)
+	require.Len(t, prs, 1)
+	assert.Equal(t, "mine", prs[0].Branch)
+}
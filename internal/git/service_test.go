@@ -10,6 +10,7 @@ import (
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/chmouel/lazyworktree/internal/config"
 	"github.com/chmouel/lazyworktree/internal/models"
@@ -24,7 +25,9 @@ func TestNewService(t *testing.T) {
 	service := NewService(notify, notifyOnce)
 
 	assert.NotNil(t, service)
-	assert.NotNil(t, service.semaphore)
+	for _, p := range service.pools {
+		assert.NotNil(t, p)
+	}
 	assert.NotNil(t, service.notifiedSet)
 	assert.NotNil(t, service.notify)
 	assert.NotNil(t, service.notifyOnce)
@@ -37,11 +40,11 @@ func TestNewService(t *testing.T) {
 		expectedSlots = 32
 	}
 
-	// Semaphore should have the expected number of slots
+	// The local git pool should have the expected number of slots
 	count := 0
 	for i := 0; i < expectedSlots; i++ {
 		select {
-		case <-service.semaphore:
+		case <-service.pools[PoolLocal].tokens:
 			count++
 		default:
 			// Can't drain more from semaphore
@@ -239,6 +242,19 @@ func TestExecuteCommands(t *testing.T) {
 		// May fail if shell execution is restricted, but should not panic
 		_ = err
 	})
+
+	t.Run("stream output", func(t *testing.T) {
+		var out strings.Builder
+		err := service.ExecuteCommandsTo(ctx, []string{"echo out", "echo err >&2; exit 3"}, t.TempDir(), nil, &out)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "err")
+		// A login shell may print its own lines, so only the order is checked.
+		got := out.String()
+		first, second := strings.Index(got, "$ echo out\n"), strings.Index(got, "$ echo err")
+		require.True(t, first >= 0 && second > first, got)
+		assert.Contains(t, got[first:second], "out\n")
+		assert.Contains(t, got[second:], "\nerr\n")
+	})
 }
 
 func TestBuildThreePartDiff(t *testing.T) {
@@ -1608,3 +1624,390 @@ func TestApplyGitPagerEdgeCases(t *testing.T) {
 		assert.NotNil(t, result)
 	})
 }
+
+func TestFetchPRMapForBranchesGitHub(t *testing.T) {
+	stub := "#!/bin/sh\n" +
+		"case \"$*\" in\n" +
+		"  *\"--head old-feature\"*)\n" +
+		"    echo '[{\"headRefName\":\"old-feature\",\"state\":\"MERGED\",\"number\":7,\"title\":\"Old\",\"url\":\"https://github.com/repo/pull/7\"}]'\n" +
+		"    exit 0;;\n" +
+		"  *\"--head\"*)\n" +
+		"    echo '[]'\n" +
+		"    exit 0;;\n" +
+		"  \"pr list\"*)\n" +
+		"    echo '[{\"headRefName\":\"recent\",\"state\":\"OPEN\",\"number\":150,\"title\":\"Recent\",\"url\":\"https://github.com/repo/pull/150\"}]'\n" +
+		"    exit 0;;\n" +
+		"esac\n" +
+		"exit 1\n"
+	dir := writeStub(t, "gh", stub)
+	withStubbedPath(t, dir)
+
+	service := NewService(func(string, string) {}, func(string, string, string) {})
+	service.gitHost = gitHostGithub
+	service.mainBranch = "main"
+
+	prMap, err := service.FetchPRMapForBranches(context.Background(), []string{"main", "recent", "old-feature", "no-pr", "old-feature"})
+	require.NoError(t, err)
+	require.Len(t, prMap, 2)
+	assert.Equal(t, 150, prMap["recent"].Number)
+	require.NotNil(t, prMap["old-feature"])
+	assert.Equal(t, 7, prMap["old-feature"].Number)
+	assert.Equal(t, "MERGED", prMap["old-feature"].State)
+}
+
+func TestFetchPRMapForBranchesGitLab(t *testing.T) {
+	stub := "#!/bin/sh\n" +
+		"case \"$2\" in\n" +
+		"  *source_branch=feature%2Fold*)\n" +
+		"    echo '[{\"iid\":3,\"state\":\"merged\",\"title\":\"Old\",\"web_url\":\"https://gitlab.com/repo/-/merge_requests/3\",\"source_branch\":\"feature/old\"}]'\n" +
+		"    exit 0;;\n" +
+		"  merge_requests*)\n" +
+		"    echo '[]'\n" +
+		"    exit 0;;\n" +
+		"esac\n" +
+		"exit 1\n"
+	dir := writeStub(t, "glab", stub)
+	withStubbedPath(t, dir)
+
+	service := NewService(func(string, string) {}, func(string, string, string) {})
+	service.gitHost = gitHostGitLab
+	service.mainBranch = "main"
+
+	prMap, err := service.FetchPRMapForBranches(context.Background(), []string{"feature/old"})
+	require.NoError(t, err)
+	require.NotNil(t, prMap["feature/old"])
+	assert.Equal(t, 3, prMap["feature/old"].Number)
+}
+
+func TestFetchPRMapForBranchesCachesMisses(t *testing.T) {
+	stub := "#!/bin/sh\n" +
+		"case \"$2\" in\n" +
+		"  *source_branch=*)\n" +
+		"    echo \"$2\" >> \"$LW_STUB_LOG\"\n" +
+		"    echo '[]'\n" +
+		"    exit 0;;\n" +
+		"  merge_requests*)\n" +
+		"    echo '[]'\n" +
+		"    exit 0;;\n" +
+		"esac\n" +
+		"exit 1\n"
+	dir := writeStub(t, "glab", stub)
+	withStubbedPath(t, dir)
+	logPath := dir + "/calls.log"
+	t.Setenv("LW_STUB_LOG", logPath)
+
+	service := NewService(func(string, string) {}, func(string, string, string) {})
+	service.gitHost = gitHostGitLab
+	service.mainBranch = "main"
+
+	branches := []string{"a", "b", "c", "d", "e", "f"}
+	for range 2 {
+		prMap, err := service.FetchPRMapForBranches(context.Background(), branches)
+		require.NoError(t, err)
+		assert.Empty(t, prMap)
+	}
+	calls, err := os.ReadFile(logPath)
+	require.NoError(t, err)
+	assert.Len(t, strings.Split(strings.TrimSpace(string(calls)), "\n"), len(branches), "expected one lookup per branch across refreshes")
+
+	service.prMisses["a"] = time.Now().Add(-PRLookupTTL)
+	_, err = service.FetchPRMapForBranches(context.Background(), branches)
+	require.NoError(t, err)
+	calls, err = os.ReadFile(logPath)
+	require.NoError(t, err)
+	assert.Len(t, strings.Split(strings.TrimSpace(string(calls)), "\n"), len(branches)+1, "expected an expired miss to be looked up again")
+}
+
+func TestFetchPRMapForBranchesDoesNotCacheFailures(t *testing.T) {
+	stub := "#!/bin/sh\n" +
+		"case \"$2\" in\n" +
+		"  *source_branch=*)\n" +
+		"    echo \"$2\" >> \"$LW_STUB_LOG\"\n" +
+		"    echo 'rate limit exceeded' >&2\n" +
+		"    exit 1;;\n" +
+		"  merge_requests*)\n" +
+		"    echo '[]'\n" +
+		"    exit 0;;\n" +
+		"esac\n" +
+		"exit 1\n"
+	dir := writeStub(t, "glab", stub)
+	withStubbedPath(t, dir)
+	logPath := dir + "/calls.log"
+	t.Setenv("LW_STUB_LOG", logPath)
+
+	service := NewService(func(string, string) {}, func(string, string, string) {})
+	service.gitHost = gitHostGitLab
+	service.mainBranch = "main"
+
+	for range 2 {
+		_, err := service.FetchPRMapForBranches(context.Background(), []string{"feature"})
+		require.NoError(t, err)
+	}
+	calls, err := os.ReadFile(logPath)
+	require.NoError(t, err)
+	assert.Len(t, strings.Split(strings.TrimSpace(string(calls)), "\n"), 2, "expected a failed lookup to be retried")
+	assert.False(t, service.knownWithoutPR("feature"))
+}
+
+func TestUpstreamHeadBranch(t *testing.T) {
+	dir := t.TempDir()
+	setupGitRepo(t, dir)
+
+	setConfig := func(key, value string) {
+		t.Helper()
+		cmd := exec.Command("git", "config", key, value)
+		cmd.Dir = dir
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("failed to set %s: %v\noutput: %s", key, err, output)
+		}
+	}
+	setConfig("remote.fork.url", "https://github.com/contributor/repo.git")
+	setConfig("branch.local-fix.remote", "fork")
+	setConfig("branch.local-fix.merge", "refs/heads/contributor-fix")
+	setConfig("branch.same.remote", "fork")
+	setConfig("branch.same.merge", "refs/heads/same")
+	// Created from origin/main: tracks the main branch.
+	setConfig("branch.from-main.remote", "fork")
+	setConfig("branch.from-main.merge", "refs/heads/main")
+	// Created from the local feature/x: tracks a local branch.
+	setConfig("branch.from-local.remote", ".")
+	setConfig("branch.from-local.merge", "refs/heads/feature/x")
+	// Tracks a remote that is not configured.
+	setConfig("branch.gone.remote", "missing")
+	setConfig("branch.gone.merge", "refs/heads/their-branch")
+
+	service := NewService(func(string, string) {}, func(string, string, string) {})
+	service.mainBranch = "main"
+	ctx := context.Background()
+
+	assert.Equal(t, "contributor-fix", service.UpstreamHeadBranch(ctx, "local-fix", dir))
+	assert.Empty(t, service.UpstreamHeadBranch(ctx, "same", dir))
+	assert.Empty(t, service.UpstreamHeadBranch(ctx, "from-main", dir), "the main branch is never a PR head")
+	assert.Empty(t, service.UpstreamHeadBranch(ctx, "from-local", dir), "a local upstream is never a PR head")
+	assert.Empty(t, service.UpstreamHeadBranch(ctx, "gone", dir))
+	assert.Empty(t, service.UpstreamHeadBranch(ctx, "untracked", dir))
+	assert.Empty(t, service.UpstreamHeadBranch(ctx, "", dir))
+}
+
+func TestBranchDescription(t *testing.T) {
+	dir := t.TempDir()
+	setupGitRepo(t, dir)
+
+	cmd := exec.Command("git", "config", "branch.feature.description", "Spike for the new cache")
+	cmd.Dir = dir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("failed to set description: %v\noutput: %s", err, output)
+	}
+
+	service := NewService(func(string, string) {}, func(string, string, string) {})
+	ctx := context.Background()
+
+	assert.Equal(t, "Spike for the new cache", service.BranchDescription(ctx, "feature", dir))
+	assert.Empty(t, service.BranchDescription(ctx, "other", dir))
+	assert.Empty(t, service.BranchDescription(ctx, "(detached)", dir))
+}
+
+func TestPRLabels(t *testing.T) {
+	assert.Equal(t, []string{"bug", "ui"}, prLabels([]any{
+		map[string]any{"name": "bug"},
+		map[string]any{"name": "ui"},
+	}))
+	assert.Equal(t, []string{"backend"}, prLabels([]any{"backend"}))
+	assert.Nil(t, prLabels(nil))
+}
+
+func TestGetCommitMessages(t *testing.T) {
+	dir := t.TempDir()
+	setupGitRepo(t, dir)
+
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\noutput: %s", args, err, output)
+		}
+	}
+	run("tag", "base")
+	run("commit", "--allow-empty", "-m", "feat(ui): add panel", "-m", "BREAKING CHANGE: layout moved")
+	run("commit", "--allow-empty", "-m", "fix: typo")
+
+	service := NewService(func(string, string) {}, func(string, string, string) {})
+	commits := service.GetCommitMessages(context.Background(), "base", dir)
+	require.Len(t, commits, 2)
+	assert.NotEmpty(t, commits[0].SHA)
+	assert.Equal(t, "feat(ui): add panel\n\nBREAKING CHANGE: layout moved", commits[0].Message)
+	assert.Equal(t, "fix: typo", commits[1].Message)
+	assert.Empty(t, service.GetCommitMessages(context.Background(), "HEAD", dir))
+}
+
+func TestBranchChangedFiles(t *testing.T) {
+	dir := t.TempDir()
+	setupGitRepo(t, dir)
+	runGit(t, dir, "tag", "base")
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "api"), 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "api", "main.go"), []byte("package main\n"), 0o600))
+	runGit(t, dir, "add", ".")
+	runGit(t, dir, "commit", "-m", "add api")
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "web"), 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "web", "index.js"), []byte("\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "api", "main.go"), []byte("package api\n"), 0o600))
+
+	service := NewService(func(string, string) {}, func(string, string, string) {})
+	assert.Equal(t, []string{"api/main.go", "web/index.js"}, service.BranchChangedFiles(context.Background(), "base", dir))
+	assert.Empty(t, service.BranchChangedFiles(context.Background(), "base", filepath.Join(dir, "missing")))
+}
+
+func TestParseWorktreeList(t *testing.T) {
+	raw := strings.Join([]string{
+		"worktree /src/repo.git",
+		"bare",
+		"",
+		"worktree /wt/feature",
+		"HEAD 1234567890abcdef",
+		"branch refs/heads/feature/x",
+		"",
+		"worktree /wt/detached",
+		"HEAD abcdef1234567890",
+		"detached",
+		"",
+		"worktree /wt/usb",
+		"HEAD abcdef1234567890",
+		"branch refs/heads/usb",
+		"locked on a removable drive",
+		"",
+		"worktree /wt/gone",
+		"HEAD abcdef1234567890",
+		"branch refs/heads/gone",
+		"locked",
+		"prunable gitdir file points to non-existent location",
+		"",
+	}, "\n")
+
+	wts := parseWorktreeList(raw)
+	require.Len(t, wts, 5)
+
+	assert.True(t, wts[0].IsMain)
+	assert.True(t, wts[0].Bare)
+
+	assert.False(t, wts[1].IsMain)
+	assert.Equal(t, "feature/x", wts[1].Branch)
+	assert.Equal(t, "1234567890abcdef", wts[1].Head)
+	assert.False(t, wts[1].Detached)
+
+	assert.True(t, wts[2].Detached)
+	assert.Empty(t, wts[2].Branch)
+
+	assert.True(t, wts[3].Locked)
+	assert.Equal(t, "on a removable drive", wts[3].LockReason)
+
+	assert.True(t, wts[4].Locked)
+	assert.Empty(t, wts[4].LockReason)
+	assert.True(t, wts[4].Prunable)
+	assert.Equal(t, "gitdir file points to non-existent location", wts[4].PrunableReason)
+}
+
+func TestBareRepository(t *testing.T) {
+	src := t.TempDir()
+	runGit(t, src, "init", "-b", "trunk")
+	runGit(t, src, "-c", "user.name=t", "-c", "user.email=t@e", "commit", "--allow-empty", "-m", "init")
+	root := t.TempDir()
+	bare := filepath.Join(root, "repo.git")
+	runGit(t, root, "clone", "--bare", src, bare)
+	runGit(t, bare, "remote", "remove", "origin")
+	wtPath := filepath.Join(root, "trunk")
+	runGit(t, bare, "worktree", "add", wtPath, "trunk")
+
+	for _, dir := range []string{bare, wtPath} {
+		withCwd(t, dir)
+		service := NewService(func(string, string) {}, func(string, string, string) {})
+		ctx := context.Background()
+
+		assert.Equal(t, bare, service.BareRepoPath(ctx))
+		assert.Equal(t, "trunk", service.GetMainBranch(ctx), "the bare HEAD names the default branch")
+		assert.Equal(t, localRepoKey(bare), service.ResolveRepoName(ctx), "every worktree shares the key")
+
+		wts, err := service.GetWorktrees(ctx)
+		require.NoError(t, err)
+		require.Len(t, wts, 2)
+		for _, wt := range wts {
+			if wt.Bare {
+				assert.Equal(t, "(bare)", wt.Branch)
+				assert.False(t, wt.Dirty)
+			} else {
+				assert.Equal(t, "trunk", wt.Branch)
+			}
+		}
+	}
+
+	withCwd(t, src)
+	assert.Empty(t, NewService(func(string, string) {}, func(string, string, string) {}).BareRepoPath(context.Background()))
+}
+
+func TestRefreshWorktree(t *testing.T) {
+	repo := t.TempDir()
+	runGit(t, repo, "init", "-b", "main")
+	runGit(t, repo, "-c", "user.name=t", "-c", "user.email=t@e", "commit", "--allow-empty", "-m", "init")
+	wtPath := filepath.Join(t.TempDir(), "feature")
+	runGit(t, repo, "worktree", "add", "-b", "feature", wtPath)
+	require.NoError(t, os.WriteFile(filepath.Join(wtPath, "new.txt"), []byte("x"), 0o600))
+	withCwd(t, repo)
+
+	service := NewService(func(string, string) {}, func(string, string, string) {})
+	ctx := context.Background()
+
+	// Resolve symlinked temporary directories the way git reports them.
+	worktrees, err := service.GetWorktrees(ctx)
+	require.NoError(t, err)
+	var listed string
+	for _, wt := range worktrees {
+		if !wt.IsMain {
+			listed = wt.Path
+		}
+	}
+	require.NotEmpty(t, listed)
+
+	wt, err := service.RefreshWorktree(ctx, listed)
+	require.NoError(t, err)
+	assert.Equal(t, "feature", wt.Branch)
+	assert.False(t, wt.IsMain)
+	assert.True(t, wt.Dirty)
+	assert.Equal(t, 1, wt.Untracked)
+	assert.NotZero(t, wt.LastActiveTS)
+
+	_, err = service.RefreshWorktree(ctx, filepath.Join(repo, "missing"))
+	require.Error(t, err)
+}
+
+func TestPrepareAllowedCommandForgeTokens(t *testing.T) {
+	s := &Service{}
+	cmd, err := s.prepareAllowedCommand(context.Background(), []string{"gh", "pr", "list"})
+	require.NoError(t, err)
+	assert.Nil(t, cmd.Env, "without a token gh inherits the environment")
+
+	s.SetForgeTokens("ghp_secret", "glpat_secret")
+	for tool, want := range map[string]string{"gh": "GH_TOKEN=ghp_secret", "glab": "GITLAB_TOKEN=glpat_secret"} {
+		cmd, err := s.prepareAllowedCommand(context.Background(), []string{tool, "auth", "status"})
+		require.NoError(t, err)
+		assert.Contains(t, cmd.Env, want, tool)
+	}
+}
+
+func TestDisableForge(t *testing.T) {
+	repo := t.TempDir()
+	runGit(t, repo, "init")
+	runGit(t, repo, "remote", "add", "origin", "git@github.com:org/repo.git")
+	withCwd(t, repo)
+
+	var notified []string
+	s := NewService(func(msg, _ string) { notified = append(notified, msg) }, func(_, msg, _ string) { notified = append(notified, msg) })
+	s.DisableForge()
+	assert.Equal(t, gitHostUnknown, s.DetectHost(context.Background()), "safe mode ignores the GitHub remote")
+	for _, tool := range []string{"gh", "glab"} {
+		_, err := s.prepareAllowedCommand(context.Background(), []string{tool, "auth", "status"})
+		assert.ErrorIs(t, err, errForgeDisabled, tool)
+	}
+	assert.Empty(t, s.RunGit(context.Background(), []string{"gh", "repo", "view"}, "", []int{0}, true, false))
+	assert.Empty(t, notified, "a skipped gh call is not reported as an error")
+	assert.NotEmpty(t, s.RunGit(context.Background(), []string{"git", "rev-parse", "--git-dir"}, "", []int{0}, true, false), "git still runs")
+}
@@ -0,0 +1,128 @@
+package git
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// sparseTreeDepth is how deep the directory picker lists the tree; deeper
+// directories can still be given in presets.
+const sparseTreeDepth = 2
+
+// SparseCheckoutDirs returns the cone directories checked out in the
+// worktree at path, or nil when it is not sparse.
+func (s *Service) SparseCheckoutDirs(ctx context.Context, path string) []string {
+	if !isSparseCheckout(path) {
+		return nil
+	}
+	raw := s.RunGit(ctx, []string{"git", "sparse-checkout", "list"}, path, []int{0}, true, true)
+	var dirs []string
+	for line := range strings.SplitSeq(raw, "\n") {
+		if dir := strings.TrimSpace(line); dir != "" {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+// SetSparseCheckout restricts the worktree at path to dirs in cone mode,
+// updating its files to match.
+func (s *Service) SetSparseCheckout(ctx context.Context, path string, dirs []string) bool {
+	args := append([]string{"git", "sparse-checkout", "set", "--cone"}, dirs...)
+	return s.RunCommandChecked(ctx, args, path, "Failed to set sparse checkout")
+}
+
+// DisableSparseCheckout restores a full checkout of the worktree at path.
+func (s *Service) DisableSparseCheckout(ctx context.Context, path string) bool {
+	return s.RunCommandChecked(ctx, []string{"git", "sparse-checkout", "disable"}, path, "Failed to disable sparse checkout")
+}
+
+// CheckoutSparse populates a worktree added with --no-checkout with only
+// dirs, so a large repository is never written out in full.
+func (s *Service) CheckoutSparse(ctx context.Context, path string, dirs []string) bool {
+	if !s.SetSparseCheckout(ctx, path, dirs) {
+		return false
+	}
+	return s.RunCommandChecked(ctx, []string{"git", "read-tree", "-mu", "HEAD"}, path, "Failed to check out the sparse worktree")
+}
+
+// TreeDirectories lists the directories of ref down to two levels, for
+// choosing what a sparse worktree checks out.
+func (s *Service) TreeDirectories(ctx context.Context, ref string) []string {
+	raw := s.RunGit(ctx, []string{"git", "ls-tree", "-r", "-d", "--name-only", ref}, "", []int{0}, true, true)
+	var dirs []string
+	for line := range strings.SplitSeq(raw, "\n") {
+		dir := strings.TrimSpace(line)
+		if dir == "" || strings.Count(dir, "/") >= sparseTreeDepth {
+			continue
+		}
+		dirs = append(dirs, dir)
+	}
+	return dirs
+}
+
+// isSparseCheckout reports whether core.sparseCheckout is set for the
+// worktree at path. The configuration files are read directly so listing
+// worktrees costs no extra git command.
+func isSparseCheckout(path string) bool {
+	gitDir := worktreeGitDir(path)
+	if gitDir == "" {
+		return false
+	}
+	if value, ok := configBool(filepath.Join(gitDir, "config.worktree"), "core", "sparsecheckout"); ok {
+		return value
+	}
+	commonDir := gitDir
+	if worktrees := filepath.Dir(gitDir); filepath.Base(worktrees) == "worktrees" {
+		commonDir = filepath.Dir(worktrees)
+	}
+	value, _ := configBool(filepath.Join(commonDir, "config"), "core", "sparsecheckout")
+	return value
+}
+
+// configBool reads a boolean key from a git configuration file, reporting
+// whether it was set. Section and key names are matched case-insensitively;
+// includes and subsections are not followed.
+func configBool(file, section, key string) (value, found bool) {
+	// #nosec G304 -- file is a configuration file inside a git directory
+	f, err := os.Open(file)
+	if err != nil {
+		return false, false
+	}
+	defer func() { _ = f.Close() }()
+
+	current := ""
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' || line[0] == ';' {
+			continue
+		}
+		if line[0] == '[' {
+			current = strings.ToLower(strings.Trim(line, "[] \t"))
+			continue
+		}
+		if current != section {
+			continue
+		}
+		name, val, hasValue := strings.Cut(line, "=")
+		if !strings.EqualFold(strings.TrimSpace(name), key) {
+			continue
+		}
+		if !hasValue {
+			// A bare key means true.
+			value, found = true, true
+			continue
+		}
+		switch strings.ToLower(strings.TrimSpace(val)) {
+		case "true", "yes", "on", "1":
+			value, found = true, true
+		default:
+			value, found = false, true
+		}
+	}
+	return value, found
+}
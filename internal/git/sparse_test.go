@@ -0,0 +1,78 @@
+package git
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigBool(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "config")
+	require.NoError(t, os.WriteFile(file, []byte(`[core]
+	bare = false
+	SparseCheckout = yes
+[remote "origin"]
+	sparsecheckout = false
+[extensions]
+	worktreeConfig
+`), 0o600))
+
+	value, found := configBool(file, "core", "sparsecheckout")
+	assert.True(t, found)
+	assert.True(t, value)
+	value, found = configBool(file, "core", "bare")
+	assert.True(t, found)
+	assert.False(t, value)
+	value, found = configBool(file, "extensions", "worktreeconfig")
+	assert.True(t, found, "a bare key is set")
+	assert.True(t, value)
+	_, found = configBool(file, "core", "missing")
+	assert.False(t, found)
+	_, found = configBool(filepath.Join(t.TempDir(), "absent"), "core", "bare")
+	assert.False(t, found)
+}
+
+func TestSparseCheckout(t *testing.T) {
+	repo := t.TempDir()
+	runGit(t, repo, "init", "-b", "main")
+	runGit(t, repo, "config", "user.email", "test@example.com")
+	runGit(t, repo, "config", "user.name", "Test User")
+	runGit(t, repo, "config", "commit.gpgsign", "false")
+	for _, file := range []string{"README.md", "web/app/index.js", "services/api/main.go", "services/worker/main.go"} {
+		path := filepath.Join(repo, file)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o750))
+		require.NoError(t, os.WriteFile(path, []byte(file+"\n"), 0o600))
+	}
+	runGit(t, repo, "add", ".")
+	runGit(t, repo, "commit", "-m", "Initial commit")
+	withCwd(t, repo)
+
+	ctx := context.Background()
+	service := NewService(func(string, string) {}, func(string, string, string) {})
+	assert.Equal(t, []string{"services", "services/api", "services/worker", "web", "web/app"}, service.TreeDirectories(ctx, "main"))
+
+	wt := filepath.Join(t.TempDir(), "api")
+	runGit(t, repo, "worktree", "add", "--no-checkout", "-b", "api", wt, "main")
+	require.True(t, service.CheckoutSparse(ctx, wt, []string{"services/api"}))
+
+	assert.FileExists(t, filepath.Join(wt, "README.md"), "top-level files stay in cone mode")
+	assert.FileExists(t, filepath.Join(wt, "services", "api", "main.go"))
+	assert.NoDirExists(t, filepath.Join(wt, "web"))
+	assert.NoDirExists(t, filepath.Join(wt, "services", "worker"))
+	assert.Empty(t, runGit(t, wt, "status", "--porcelain"))
+	assert.True(t, isSparseCheckout(wt))
+	assert.False(t, isSparseCheckout(repo), "the main worktree keeps a full checkout")
+	assert.Equal(t, []string{"services/api"}, service.SparseCheckoutDirs(ctx, wt))
+
+	require.True(t, service.SetSparseCheckout(ctx, wt, []string{"services/api", "web"}))
+	assert.FileExists(t, filepath.Join(wt, "web", "app", "index.js"))
+
+	require.True(t, service.DisableSparseCheckout(ctx, wt))
+	assert.False(t, isSparseCheckout(wt))
+	assert.Nil(t, service.SparseCheckoutDirs(ctx, wt))
+	assert.FileExists(t, filepath.Join(wt, "services", "worker", "main.go"))
+}
@@ -0,0 +1,196 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// stackParentKey and stackBaseKey record, in a branch's git config
+// section, the branch it is stacked on and the commit of that branch it was
+// last based on, so the stack outlives new commits on the parent.
+const (
+	stackParentKey = "lazyworktree-parent"
+	stackBaseKey   = "lazyworktree-base"
+)
+
+// StackLink ties a branch to the branch it is stacked on.
+type StackLink struct {
+	Parent string // Branch it is stacked on
+	Base   string // Commit of Parent it was last based on
+}
+
+// StackLinks works out which of branches are stacked on another of them:
+// a branch whose commits, not yet on mainBranch, include another's tip is
+// stacked on the nearest such branch. A parent recorded earlier stands in
+// when the parent has moved on since, as long as it is still in branches.
+// Newly found links are recorded unless record is false.
+func (s *Service) StackLinks(ctx context.Context, branches []string, mainBranch string, record bool) map[string]StackLink {
+	if mainBranch == "" {
+		return nil
+	}
+	known := make(map[string]bool, len(branches))
+	for _, branch := range branches {
+		if branch != "" && branch != mainBranch {
+			known[branch] = true
+		}
+	}
+
+	// ancestors[b] lists the known branches whose tips b contains, beyond
+	// the main branch; tips[b] is b's commit.
+	ancestors := make(map[string][]string, len(known))
+	tips := make(map[string]string, len(known))
+	for branch := range known {
+		raw := s.RunGit(ctx, []string{
+			"git", "for-each-ref",
+			"--merged=refs/heads/" + branch, "--no-merged=refs/heads/" + mainBranch,
+			"--format=%(objectname) %(refname:short)", "refs/heads/",
+		}, "", []int{0}, true, true)
+		for line := range strings.SplitSeq(raw, "\n") {
+			sha, name, ok := strings.Cut(strings.TrimSpace(line), " ")
+			if !ok || !known[name] {
+				continue
+			}
+			if name == branch {
+				tips[branch] = sha
+				continue
+			}
+			ancestors[branch] = append(ancestors[branch], name)
+		}
+	}
+
+	links := make(map[string]StackLink)
+	recorded := s.recordedStackLinks(ctx)
+	for branch := range known {
+		parent := nearestParent(branch, ancestors, tips)
+		if parent == "" {
+			if link, ok := recorded[branch]; ok && known[link.Parent] {
+				links[branch] = link
+			}
+			continue
+		}
+		link := StackLink{Parent: parent, Base: tips[parent]}
+		links[branch] = link
+		if record && recorded[branch] != link {
+			s.RecordStackLink(ctx, branch, link)
+		}
+	}
+	return links
+}
+
+// nearestParent picks, among the branches whose tips branch contains, the
+// one containing the most others. Branches at branch's own commit are not
+// parents, as either could be stacked on the other.
+func nearestParent(branch string, ancestors map[string][]string, tips map[string]string) string {
+	if tips[branch] == "" {
+		return "" // Fully merged into the main branch
+	}
+	candidates := slices.Sorted(slices.Values(ancestors[branch]))
+	parent, depth := "", -1
+	for _, candidate := range candidates {
+		if tips[candidate] == tips[branch] {
+			continue
+		}
+		if d := len(ancestors[candidate]); d > depth {
+			parent, depth = candidate, d
+		}
+	}
+	return parent
+}
+
+// recordedStackLinks reads the links recorded in the git config.
+func (s *Service) recordedStackLinks(ctx context.Context) map[string]StackLink {
+	raw := s.RunGit(ctx, []string{"git", "config", "--get-regexp", `^branch\..*\.lazyworktree-(parent|base)$`}, "", []int{0, 1}, true, true)
+	links := make(map[string]StackLink)
+	for line := range strings.SplitSeq(raw, "\n") {
+		key, value, _ := strings.Cut(strings.TrimSpace(line), " ")
+		rest, ok := strings.CutPrefix(key, "branch.")
+		if !ok {
+			continue
+		}
+		dot := strings.LastIndex(rest, ".")
+		if dot <= 0 {
+			continue
+		}
+		branch, name := rest[:dot], rest[dot+1:]
+		link := links[branch]
+		switch name {
+		case stackParentKey:
+			link.Parent = value
+		case stackBaseKey:
+			link.Base = value
+		}
+		links[branch] = link
+	}
+	return links
+}
+
+// RecordStackLink records the branch's parent and base in the git config.
+func (s *Service) RecordStackLink(ctx context.Context, branch string, link StackLink) {
+	s.RunGit(ctx, []string{"git", "config", fmt.Sprintf("branch.%s.%s", branch, stackParentKey), link.Parent}, "", []int{0}, true, true)
+	if link.Base != "" {
+		s.RunGit(ctx, []string{"git", "config", fmt.Sprintf("branch.%s.%s", branch, stackBaseKey), link.Base}, "", []int{0}, true, true)
+	}
+}
+
+// Restack rebases the branch checked out in dir onto the current tip of its
+// parent, replaying only its own commits: those after the recorded base,
+// or, failing that, after the fork point git finds in the parent's reflog.
+// A rebase that stops on a conflict is aborted, leaving the branch as it
+// was. The new link is recorded on success.
+func (s *Service) Restack(ctx context.Context, dir, branch string, link StackLink) error {
+	base := link.Base
+	if base != "" && !s.isAncestor(ctx, dir, base) {
+		base = ""
+	}
+	if base == "" {
+		base = s.RunGit(ctx, []string{"git", "merge-base", "--fork-point", link.Parent, "HEAD"}, dir, []int{0, 1}, true, true)
+	}
+	if base == "" {
+		base = s.RunGit(ctx, []string{"git", "merge-base", link.Parent, "HEAD"}, dir, []int{0, 1}, true, true)
+	}
+	if base == "" {
+		return fmt.Errorf("%s shares no history with %s", branch, link.Parent)
+	}
+
+	args := []string{"git", "rebase", "--onto", link.Parent, base}
+	cmd, err := s.command(ctx, args, dir)
+	if err != nil {
+		return err
+	}
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	s.debugf("run: %s (cwd=%s)", strings.Join(args, " "), dir)
+	if err := cmd.Run(); err != nil {
+		s.RunGit(ctx, []string{"git", "rebase", "--abort"}, dir, []int{0, 128}, true, true)
+		if msg := strings.TrimSpace(output.String()); msg != "" {
+			return fmt.Errorf("failed to restack %s onto %s: %s", branch, link.Parent, firstLines(msg, 3))
+		}
+		return fmt.Errorf("failed to restack %s onto %s: %w", branch, link.Parent, err)
+	}
+
+	tip := s.RunGit(ctx, []string{"git", "rev-parse", "refs/heads/" + link.Parent}, dir, []int{0}, true, true)
+	s.RecordStackLink(ctx, branch, StackLink{Parent: link.Parent, Base: tip})
+	return nil
+}
+
+// isAncestor reports whether commit is an ancestor of HEAD in dir.
+func (s *Service) isAncestor(ctx context.Context, dir, commit string) bool {
+	cmd, err := s.command(ctx, []string{"git", "merge-base", "--is-ancestor", commit, "HEAD"}, dir)
+	if err != nil {
+		return false
+	}
+	return cmd.Run() == nil
+}
+
+// firstLines keeps the first n lines of s.
+func firstLines(s string, n int) string {
+	lines := strings.SplitN(s, "\n", n+1)
+	if len(lines) > n {
+		lines = lines[:n]
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,77 @@
+package git
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func commitFile(t *testing.T, dir, name string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(name+"\n"), 0o600))
+	runGit(t, dir, "add", name)
+	runGit(t, dir, "commit", "-m", "Add "+name)
+}
+
+func TestStackLinksAndRestack(t *testing.T) {
+	repo := t.TempDir()
+	runGit(t, repo, "init", "-b", "main")
+	runGit(t, repo, "config", "user.email", "test@example.com")
+	runGit(t, repo, "config", "user.name", "Test User")
+	runGit(t, repo, "config", "commit.gpgsign", "false")
+	commitFile(t, repo, "README.md")
+	withCwd(t, repo)
+
+	apiWt := filepath.Join(t.TempDir(), "api")
+	runGit(t, repo, "worktree", "add", "-b", "api", apiWt, "main")
+	commitFile(t, apiWt, "api.go")
+	uiWt := filepath.Join(t.TempDir(), "ui")
+	runGit(t, repo, "worktree", "add", "-b", "ui", uiWt, "api")
+	commitFile(t, uiWt, "ui.go")
+	docsWt := filepath.Join(t.TempDir(), "docs")
+	runGit(t, repo, "worktree", "add", "-b", "docs", docsWt, "ui")
+	commitFile(t, docsWt, "docs.md")
+	runGit(t, repo, "branch", "other", "main")
+
+	ctx := context.Background()
+	service := NewService(func(string, string) {}, func(string, string, string) {})
+	branches := []string{"main", "api", "ui", "docs", "other"}
+
+	links := service.StackLinks(ctx, branches, "main", true)
+	apiTip := runGit(t, repo, "rev-parse", "api")
+	uiTip := runGit(t, repo, "rev-parse", "ui")
+	assert.Equal(t, map[string]StackLink{
+		"ui":   {Parent: "api", Base: apiTip},
+		"docs": {Parent: "ui", Base: uiTip},
+	}, links)
+	assert.Empty(t, service.StackLinks(ctx, branches, "", true))
+
+	// A new commit on the parent hides the ancestry; the recorded link
+	// stands in until the branch is restacked.
+	commitFile(t, apiWt, "api2.go")
+	links = service.StackLinks(ctx, branches, "main", true)
+	assert.Equal(t, StackLink{Parent: "api", Base: apiTip}, links["ui"])
+
+	require.NoError(t, service.Restack(ctx, uiWt, "ui", links["ui"]))
+	assert.Equal(t, "4", runGit(t, uiWt, "rev-list", "--count", "HEAD"))
+	links = service.StackLinks(ctx, branches, "main", true)
+	assert.Equal(t, StackLink{Parent: "api", Base: runGit(t, repo, "rev-parse", "api")}, links["ui"])
+
+	require.NoError(t, service.Restack(ctx, docsWt, "docs", links["docs"]))
+	assert.Equal(t, "5", runGit(t, docsWt, "rev-list", "--count", "HEAD"))
+
+	// A conflict leaves the branch as it was.
+	require.NoError(t, os.WriteFile(filepath.Join(apiWt, "ui.go"), []byte("clash\n"), 0o600))
+	runGit(t, apiWt, "add", "ui.go")
+	runGit(t, apiWt, "commit", "-m", "Clash")
+	before := runGit(t, uiWt, "rev-parse", "HEAD")
+	err := service.Restack(ctx, uiWt, "ui", service.StackLinks(ctx, branches, "main", true)["ui"])
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to restack ui onto api")
+	assert.Equal(t, before, runGit(t, uiWt, "rev-parse", "HEAD"))
+	assert.Empty(t, runGit(t, uiWt, "status", "--porcelain"))
+}
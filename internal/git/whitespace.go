@@ -0,0 +1,57 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+)
+
+// FixStagedWhitespace rewrites the staged changes in dir without their
+// whitespace errors, in the index and the working tree alike: the staged
+// patch is taken back out and applied again with git apply
+// --whitespace=fix. It reports whether there was anything staged. Should a
+// file with staged changes also have unstaged ones, nothing is touched and
+// an error is returned.
+func (s *Service) FixStagedWhitespace(ctx context.Context, dir string) (bool, error) {
+	patch, err := s.gitWithInput(ctx, dir, "", "diff", "--cached", "--binary", "--no-color", "--no-ext-diff")
+	if err != nil {
+		return false, err
+	}
+	if strings.TrimSpace(patch) == "" {
+		return false, nil
+	}
+	if _, err := s.gitWithInput(ctx, dir, patch, "apply", "--index", "-R"); err != nil {
+		return true, fmt.Errorf("files with staged changes also have unstaged ones: %w", err)
+	}
+	if _, err := s.gitWithInput(ctx, dir, patch, "apply", "--index", "--whitespace=fix"); err != nil {
+		// Put the staged changes back as they were.
+		if _, restoreErr := s.gitWithInput(ctx, dir, patch, "apply", "--index"); restoreErr != nil {
+			return true, fmt.Errorf("%w; restoring the staged changes failed too: %w", err, restoreErr)
+		}
+		return true, err
+	}
+	return true, nil
+}
+
+// gitWithInput runs git in dir with input on its standard input, returning
+// its output or an error carrying its message.
+func (s *Service) gitWithInput(ctx context.Context, dir, input string, args ...string) (string, error) {
+	args = append([]string{"git"}, args...)
+	cmd, err := s.command(ctx, args, dir)
+	if err != nil {
+		return "", err
+	}
+	cmd.Stdin = strings.NewReader(input)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	s.debugf("run: %s (cwd=%s)", strings.Join(args, " "), dir)
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%s: %s", strings.Join(args, " "), msg)
+		}
+		return "", fmt.Errorf("%s: %w", strings.Join(args, " "), err)
+	}
+	return stdout.String(), nil
+}
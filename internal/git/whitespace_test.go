@@ -0,0 +1,56 @@
+package git
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFixStagedWhitespace(t *testing.T) {
+	repo := t.TempDir()
+	runGit(t, repo, "init", "-b", "main")
+	runGit(t, repo, "config", "user.email", "test@example.com")
+	runGit(t, repo, "config", "user.name", "Test User")
+	runGit(t, repo, "config", "commit.gpgsign", "false")
+	write := func(name, content string) {
+		require.NoError(t, os.WriteFile(filepath.Join(repo, name), []byte(content), 0o600))
+	}
+	write("a.txt", "one\n")
+	write("b.txt", "two\n")
+	runGit(t, repo, "add", ".")
+	runGit(t, repo, "commit", "-m", "Initial commit")
+
+	ctx := context.Background()
+	service := NewService(func(string, string) {}, func(string, string, string) {})
+	changed, err := service.FixStagedWhitespace(ctx, repo)
+	require.NoError(t, err)
+	assert.False(t, changed, "nothing staged")
+
+	write("a.txt", "one\nadded  \r\nclean\n")
+	runGit(t, repo, "add", "a.txt")
+	write("b.txt", "two \n")
+	changed, err = service.FixStagedWhitespace(ctx, repo)
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, "one\nadded\nclean", runGit(t, repo, "show", ":a.txt"))
+	content, err := os.ReadFile(filepath.Join(repo, "a.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "one\nadded\nclean\n", string(content))
+	assert.Empty(t, runGit(t, repo, "diff", "--", "a.txt"), "index and working tree agree")
+	assert.NotEmpty(t, runGit(t, repo, "diff", "--", "b.txt"), "unstaged changes are left alone")
+
+	write("a.txt", "one\nadded\nclean \n")
+	runGit(t, repo, "add", "a.txt")
+	write("a.txt", "one\nadded\nclean \nmore\n")
+	_, err = service.FixStagedWhitespace(ctx, repo)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "also have unstaged ones")
+	assert.Equal(t, "17", runGit(t, repo, "cat-file", "-s", ":a.txt"), "the staged trailing space is kept")
+	content, err = os.ReadFile(filepath.Join(repo, "a.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "one\nadded\nclean \nmore\n", string(content), "a refused fix touches nothing")
+}
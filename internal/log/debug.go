@@ -3,9 +3,14 @@ package log
 import (
 	"log"
 	"os"
+	"strings"
 	"sync"
 )
 
+// recentLines is how many log lines are kept in memory for crash reports,
+// whether or not a debug log file is configured.
+const recentLines = 200
+
 // DebugLogger handles debug logging to file and/or buffering.
 // It implements io.Writer to be compatible with standard log.Logger.
 type DebugLogger struct {
@@ -13,6 +18,8 @@ type DebugLogger struct {
 	file    *os.File
 	buffer  []byte
 	discard bool
+	recent  []string
+	next    int
 }
 
 var (
@@ -27,6 +34,7 @@ func (l *DebugLogger) Write(p []byte) (n int, err error) {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 
+	l.remember(p)
 	if l.discard {
 		return len(p), nil
 	}
@@ -47,6 +55,30 @@ func (l *DebugLogger) Write(p []byte) (n int, err error) {
 	return len(p), nil
 }
 
+// remember keeps p in the ring of recent lines. Callers hold l.mu.
+func (l *DebugLogger) remember(p []byte) {
+	line := strings.TrimRight(string(p), "\n")
+	if len(l.recent) < recentLines {
+		l.recent = append(l.recent, line)
+		return
+	}
+	l.recent[l.next] = line
+	l.next = (l.next + 1) % recentLines
+}
+
+// Tail returns up to n of the most recent log lines, oldest first.
+func Tail(n int) []string {
+	globalDebugLogger.mu.Lock()
+	defer globalDebugLogger.mu.Unlock()
+
+	l := globalDebugLogger
+	ordered := append(append([]string(nil), l.recent[l.next:]...), l.recent[:l.next]...)
+	if n < len(ordered) {
+		ordered = ordered[len(ordered)-n:]
+	}
+	return ordered
+}
+
 // SetFile sets the debug log file path. Creates the file if it doesn't exist.
 // If path is empty, discards all buffered logs and future logs.
 func SetFile(path string) error {
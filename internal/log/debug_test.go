@@ -2,6 +2,7 @@ package log
 
 import (
 	"bytes"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
@@ -17,9 +18,12 @@ func resetDebugLogger(t *testing.T) func() {
 	prevFile := globalDebugLogger.file
 	prevBuffer := append([]byte(nil), globalDebugLogger.buffer...)
 	prevDiscard := globalDebugLogger.discard
+	prevRecent, prevNext := globalDebugLogger.recent, globalDebugLogger.next
 	globalDebugLogger.file = nil
 	globalDebugLogger.buffer = nil
 	globalDebugLogger.discard = false
+	globalDebugLogger.recent = nil
+	globalDebugLogger.next = 0
 	globalDebugLogger.mu.Unlock()
 
 	return func() {
@@ -30,6 +34,7 @@ func resetDebugLogger(t *testing.T) func() {
 		globalDebugLogger.file = prevFile
 		globalDebugLogger.buffer = prevBuffer
 		globalDebugLogger.discard = prevDiscard
+		globalDebugLogger.recent, globalDebugLogger.next = prevRecent, prevNext
 		globalDebugLogger.mu.Unlock()
 	}
 }
@@ -423,3 +428,28 @@ func TestClose(t *testing.T) {
 		}
 	})
 }
+
+func TestTail(t *testing.T) {
+	restore := resetDebugLogger(t)
+	t.Cleanup(restore)
+	if err := SetFile(""); err != nil {
+		t.Fatalf("SetFile: %v", err)
+	}
+
+	for i := range recentLines + 5 {
+		_, _ = globalDebugLogger.Write([]byte(fmt.Sprintf("line %d\n", i)))
+	}
+
+	tail := Tail(3)
+	want := []string{
+		fmt.Sprintf("line %d", recentLines+2),
+		fmt.Sprintf("line %d", recentLines+3),
+		fmt.Sprintf("line %d", recentLines+4),
+	}
+	if strings.Join(tail, ",") != strings.Join(want, ",") {
+		t.Fatalf("Tail(3) = %v, want %v", tail, want)
+	}
+	if got := len(Tail(1000)); got != recentLines {
+		t.Fatalf("expected %d remembered lines even when discarding, got %d", recentLines, got)
+	}
+}
@@ -0,0 +1,309 @@
+// Package maintenance runs the housekeeping a repository with many
+// worktrees needs — git gc, git worktree prune, git fetch --all --prune and
+// cache cleanup — on demand or when each task's interval has elapsed, and
+// remembers when each last ran and how much space it reclaimed.
+package maintenance
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chmouel/lazyworktree/internal/models"
+	"github.com/chmouel/lazyworktree/internal/utils"
+)
+
+// Task names a maintenance task.
+type Task string
+
+const (
+	GC    Task = "gc"
+	Prune Task = "prune"
+	Fetch Task = "fetch"
+	Cache Task = "cache"
+)
+
+// Tasks lists every task in the order they are shown and run.
+var Tasks = []Task{Prune, Fetch, GC, Cache}
+
+// staleTempAge is how old a leftover temporary file must be before cache
+// cleanup removes it, so writes in progress are left alone.
+const staleTempAge = time.Hour
+
+// Description explains what t does.
+func (t Task) Description() string {
+	switch t {
+	case GC:
+		return "git gc: pack objects and drop unreachable ones"
+	case Prune:
+		return "git worktree prune: forget worktrees whose directory is gone"
+	case Fetch:
+		return "git fetch --all --prune: update remotes and drop deleted branches"
+	case Cache:
+		return "Remove caches of repositories that no longer exist"
+	default:
+		return string(t)
+	}
+}
+
+// ChangesRepository reports whether t rewrites the repository, which
+// read-only mode forbids.
+func (t Task) ChangesRepository() bool {
+	return t == GC || t == Prune
+}
+
+// Schedule is how often each task runs by itself; tasks absent from it only
+// run on demand.
+type Schedule map[Task]time.Duration
+
+// ParseInterval parses an interval such as "7d", "12h" or "30m". An empty
+// value means never.
+func ParseInterval(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n <= 0 {
+			return 0, fmt.Errorf("invalid interval %q", s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid interval %q: use e.g. 30m, 12h or 7d", s)
+	}
+	return d, nil
+}
+
+// FormatInterval renders d as ParseInterval reads it, e.g. "7d" or "12h".
+func FormatInterval(d time.Duration) string {
+	switch {
+	case d%(24*time.Hour) == 0:
+		return fmt.Sprintf("%dd", d/(24*time.Hour))
+	case d%time.Hour == 0:
+		return fmt.Sprintf("%dh", d/time.Hour)
+	case d%time.Minute == 0:
+		return fmt.Sprintf("%dm", d/time.Minute)
+	default:
+		return d.String()
+	}
+}
+
+// Run records one run of a task.
+type Run struct {
+	At        time.Time `json:"at"`
+	Duration  string    `json:"duration,omitempty"`
+	Reclaimed int64     `json:"reclaimed,omitempty"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// State holds the last run of each task.
+type State map[Task]Run
+
+// LoadState reads the state at path; a missing or damaged file yields an
+// empty state.
+func LoadState(path string) State {
+	state := State{}
+	// #nosec G304 -- path is derived from the worktree directory
+	if data, err := os.ReadFile(path); err == nil {
+		_ = json.Unmarshal(data, &state)
+	}
+	if state == nil {
+		state = State{}
+	}
+	return state
+}
+
+// Save writes the state to path.
+func (s State) Save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+// Due returns the scheduled tasks whose interval has elapsed since they
+// last ran, in the order of Tasks.
+func (s State) Due(schedule Schedule, now time.Time) []Task {
+	var due []Task
+	for _, task := range Tasks {
+		interval, ok := schedule[task]
+		if !ok || interval <= 0 {
+			continue
+		}
+		if last, ran := s[task]; !ran || now.Sub(last.At) >= interval {
+			due = append(due, task)
+		}
+	}
+	return due
+}
+
+// Runner runs tasks for the repository at RepoPath.
+type Runner struct {
+	RepoPath string
+	// CacheRoot holds every repository's cache directory.
+	CacheRoot string
+	// Git runs git in dir and returns its trimmed stdout; tests replace it.
+	Git func(ctx context.Context, dir string, args ...string) (string, error)
+	// FetchArgs are git's arguments for the fetch task, which adds --all
+	// and --prune; they carry the configured fetch tuning.
+	FetchArgs []string
+	now       func() time.Time
+}
+
+// NewRunner returns a runner for the repository at repoPath.
+func NewRunner(repoPath, cacheRoot string) *Runner {
+	return &Runner{RepoPath: repoPath, CacheRoot: cacheRoot, Git: runGit, FetchArgs: []string{"fetch", "--quiet"}, now: time.Now}
+}
+
+// Run performs task and reports how long it took and the space it freed.
+func (r *Runner) Run(ctx context.Context, task Task) Run {
+	start := r.now()
+	run := Run{At: start}
+	var err error
+	switch task {
+	case GC:
+		run.Reclaimed, err = r.measure(ctx, r.gitDir, "gc", "--quiet")
+	case Prune:
+		run.Reclaimed, err = r.measure(ctx, r.gitDir, "worktree", "prune")
+	case Fetch:
+		args := append(slices.Clone(r.FetchArgs), "--all", "--prune")
+		run.Reclaimed, err = r.measure(ctx, r.gitDir, args...)
+	case Cache:
+		run.Reclaimed, err = r.cleanCaches()
+	default:
+		err = fmt.Errorf("unknown maintenance task %q", task)
+	}
+	run.Duration = r.now().Sub(start).Round(time.Millisecond).String()
+	if err != nil {
+		run.Error = err.Error()
+	}
+	return run
+}
+
+// gitDir returns the repository's common git directory.
+func (r *Runner) gitDir(ctx context.Context) (string, error) {
+	dir, err := r.Git(ctx, r.RepoPath, "rev-parse", "--path-format=absolute", "--git-common-dir")
+	if err != nil {
+		return "", fmt.Errorf("failed to find the git directory: %w", err)
+	}
+	return dir, nil
+}
+
+// measure runs git with args and returns how much the directory returned by
+// dirFn shrank.
+func (r *Runner) measure(ctx context.Context, dirFn func(context.Context) (string, error), args ...string) (int64, error) {
+	dir, err := dirFn(ctx)
+	if err != nil {
+		return 0, err
+	}
+	before, _ := utils.DirSize(dir)
+	if _, err := r.Git(ctx, r.RepoPath, args...); err != nil {
+		return 0, err
+	}
+	after, _ := utils.DirSize(dir)
+	return max(before-after, 0), nil
+}
+
+// regenerableFiles are the cache files rebuilt on demand, the only ones
+// cleaned for a repository whose main worktree is gone. The repository key
+// is shared by clones, so snapshots, instances and the control socket next
+// to them may still belong to one that exists.
+var regenerableFiles = []string{models.CacheFilename, models.HealthFilename}
+
+// cleanCaches removes the regenerable cache files of repositories whose main
+// worktree no longer exists, and temporary files left behind by interrupted
+// writes. A cache directory left empty goes too.
+func (r *Runner) cleanCaches() (int64, error) {
+	before, _ := utils.DirSize(r.CacheRoot)
+	cutoff := r.now().Add(-staleTempAge)
+	var stale []string
+	err := filepath.WalkDir(r.CacheRoot, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return fs.SkipAll
+			}
+			return nil
+		}
+		if d.IsDir() {
+			if d.Name() == models.SnapshotsDirname || d.Name() == models.InstancesDirname {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		switch {
+		case d.Name() == models.CacheFilename:
+			if repo := cachedRepo(path); repo != "" {
+				if _, statErr := os.Stat(repo); os.IsNotExist(statErr) {
+					stale = append(stale, filepath.Dir(path))
+				}
+			}
+		case strings.HasSuffix(d.Name(), ".tmp"):
+			if info, infoErr := d.Info(); infoErr == nil && info.ModTime().Before(cutoff) {
+				_ = os.Remove(path)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	for _, dir := range stale {
+		for _, name := range regenerableFiles {
+			if err := os.Remove(filepath.Join(dir, name)); err != nil && !os.IsNotExist(err) {
+				return 0, fmt.Errorf("failed to remove %s: %w", filepath.Join(dir, name), err)
+			}
+		}
+		// Only succeeds when nothing else is left.
+		_ = os.Remove(dir)
+	}
+	after, _ := utils.DirSize(r.CacheRoot)
+	return max(before-after, 0), nil
+}
+
+// cachedRepo returns the main worktree path a worktree cache belongs to.
+func cachedRepo(path string) string {
+	// #nosec G304 -- path was found under the cache directory
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	var cache struct {
+		Repo string `json:"repo"`
+	}
+	if json.Unmarshal(data, &cache) != nil {
+		return ""
+	}
+	return cache.Repo
+}
+
+func runGit(ctx context.Context, dir string, args ...string) (string, error) {
+	// #nosec G204 -- the arguments are fixed in this package
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%w: %s", err, msg)
+		}
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
@@ -0,0 +1,219 @@
+package maintenance
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+func TestParseInterval(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    time.Duration
+		wantErr bool
+	}{
+		{in: "", want: 0},
+		{in: "7d", want: 7 * 24 * time.Hour},
+		{in: " 12h ", want: 12 * time.Hour},
+		{in: "30m", want: 30 * time.Minute},
+		{in: "0d", wantErr: true},
+		{in: "weekly", wantErr: true},
+		{in: "-1h", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseInterval(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("ParseInterval(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Fatalf("ParseInterval(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestFormatInterval(t *testing.T) {
+	for in, want := range map[time.Duration]string{
+		7 * 24 * time.Hour: "7d",
+		36 * time.Hour:     "36h",
+		30 * time.Minute:   "30m",
+		90 * time.Second:   "1m30s",
+	} {
+		if got := FormatInterval(in); got != want {
+			t.Fatalf("FormatInterval(%s) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestDue(t *testing.T) {
+	now := time.Date(2026, 5, 10, 12, 0, 0, 0, time.UTC)
+	schedule := Schedule{GC: 7 * 24 * time.Hour, Fetch: time.Hour, Cache: 24 * time.Hour}
+	state := State{
+		GC:    {At: now.Add(-8 * 24 * time.Hour)},
+		Fetch: {At: now.Add(-30 * time.Minute)},
+		Prune: {At: now.Add(-365 * 24 * time.Hour)},
+	}
+	got := state.Due(schedule, now)
+	want := []Task{GC, Cache}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Fatalf("Due() = %v, want %v", got, want)
+	}
+}
+
+func TestStateRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "repo", models.MaintenanceFilename)
+	if state := LoadState(path); len(state) != 0 {
+		t.Fatalf("expected an empty state, got %v", state)
+	}
+	at := time.Date(2026, 5, 10, 12, 0, 0, 0, time.UTC)
+	state := State{GC: {At: at, Duration: "1.2s", Reclaimed: 4096}}
+	if err := state.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded := LoadState(path)
+	if run := loaded[GC]; !run.At.Equal(at) || run.Reclaimed != 4096 {
+		t.Fatalf("unexpected run after reload: %+v", run)
+	}
+
+	if err := os.WriteFile(path, []byte("{not json"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if state := LoadState(path); state == nil || len(state) != 0 {
+		t.Fatalf("expected a damaged file to yield an empty state, got %v", state)
+	}
+}
+
+func gitCmd(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+}
+
+func TestRunGitTasks(t *testing.T) {
+	repo := t.TempDir()
+	gitCmd(t, repo, "init", "-b", "main")
+	gitCmd(t, repo, "config", "user.email", "test@example.com")
+	gitCmd(t, repo, "config", "user.name", "Test User")
+	gitCmd(t, repo, "config", "commit.gpgsign", "false")
+	gitCmd(t, repo, "commit", "--allow-empty", "-m", "Initial commit")
+	gone := filepath.Join(t.TempDir(), "gone")
+	gitCmd(t, repo, "worktree", "add", "-b", "gone", gone)
+	if err := os.RemoveAll(gone); err != nil {
+		t.Fatal(err)
+	}
+
+	runner := NewRunner(repo, t.TempDir())
+	for _, task := range []Task{Prune, GC} {
+		if run := runner.Run(context.Background(), task); run.Error != "" || run.At.IsZero() || run.Duration == "" {
+			t.Fatalf("%s: unexpected run %+v", task, run)
+		}
+	}
+	out, err := exec.Command("git", "-C", repo, "worktree", "list", "--porcelain").Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(string(out), "worktree "); n != 1 {
+		t.Fatalf("expected prune to leave only the main worktree, got:\n%s", out)
+	}
+
+	if run := runner.Run(context.Background(), Task("bogus")); run.Error == "" {
+		t.Fatal("expected an unknown task to fail")
+	}
+}
+
+func writeCache(t *testing.T, dir, repo string) {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(map[string]any{"version": 1, "repo": repo})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, models.CacheFilename), data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRunCacheCleanup(t *testing.T) {
+	root := t.TempDir()
+	live := filepath.Join(root, "example", "live")
+	stale := filepath.Join(root, "example", "stale")
+	writeCache(t, live, t.TempDir())
+	writeCache(t, stale, filepath.Join(t.TempDir(), "removed"))
+	if err := os.WriteFile(filepath.Join(stale, models.HealthFilename), make([]byte, 2048), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	oldTmp := filepath.Join(live, "cache.json.123.tmp")
+	newTmp := filepath.Join(live, "cache.json.456.tmp")
+	for _, path := range []string{oldTmp, newTmp} {
+		if err := os.WriteFile(path, []byte("partial"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	old := time.Now().Add(-2 * time.Hour)
+	if err := os.Chtimes(oldTmp, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	run := NewRunner(t.TempDir(), root).Run(context.Background(), Cache)
+	if run.Error != "" {
+		t.Fatalf("unexpected error: %s", run.Error)
+	}
+	if run.Reclaimed < 2048 {
+		t.Fatalf("expected at least 2048 bytes reclaimed, got %d", run.Reclaimed)
+	}
+	if _, err := os.Stat(stale); !os.IsNotExist(err) {
+		t.Fatalf("expected the stale cache to be removed, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(live, models.CacheFilename)); err != nil {
+		t.Fatalf("expected the live cache to be kept: %v", err)
+	}
+	if _, err := os.Stat(oldTmp); !os.IsNotExist(err) {
+		t.Fatalf("expected the old temporary file to be removed, got %v", err)
+	}
+	if _, err := os.Stat(newTmp); err != nil {
+		t.Fatalf("expected the recent temporary file to be kept: %v", err)
+	}
+
+	if run := NewRunner(t.TempDir(), filepath.Join(root, "missing")).Run(context.Background(), Cache); run.Error != "" {
+		t.Fatalf("expected a missing cache directory to be fine, got %s", run.Error)
+	}
+}
+
+func TestRunCacheCleanupKeepsSnapshotsAndSocket(t *testing.T) {
+	root := t.TempDir()
+	stale := filepath.Join(root, "example", "shared")
+	writeCache(t, stale, filepath.Join(t.TempDir(), "removed"))
+	snapshot := filepath.Join(stale, models.SnapshotsDirname, "abc", "snapshot.json")
+	if err := os.MkdirAll(filepath.Dir(snapshot), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	socket := filepath.Join(stale, models.ControlSocketFilename)
+	for _, path := range []string{snapshot, socket} {
+		if err := os.WriteFile(path, []byte("{}"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if run := NewRunner(t.TempDir(), root).Run(context.Background(), Cache); run.Error != "" {
+		t.Fatalf("unexpected error: %s", run.Error)
+	}
+	if _, err := os.Stat(filepath.Join(stale, models.CacheFilename)); !os.IsNotExist(err) {
+		t.Fatalf("expected the stale worktree cache to be removed, got %v", err)
+	}
+	for _, path := range []string{snapshot, socket} {
+		if _, err := os.Stat(path); err != nil {
+			t.Fatalf("expected %s to be kept: %v", filepath.Base(path), err)
+		}
+	}
+}
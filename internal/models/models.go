@@ -8,20 +8,36 @@ type CommitFile struct {
 	OldPath    string // For renames: the original path
 }
 
+// CommitMessage pairs a commit's short hash with its full message.
+type CommitMessage struct {
+	SHA     string
+	Message string
+}
+
 // PRInfo captures the relevant metadata for a pull request.
 type PRInfo struct {
-	Number      int
-	State       string
-	Title       string
-	Body        string // For branch_name_script input
-	URL         string
-	Branch      string // Branch name (headRefName for GitHub, source_branch for GitLab)
-	BaseBranch  string // Base branch name (baseRefName for GitHub, target_branch for GitLab)
-	Author      string // PR/MR author username
-	AuthorName  string // PR/MR author full name
-	AuthorIsBot bool   // Whether the author is a bot
-	IsDraft     bool   // Whether the PR is a draft
-	CIStatus    string // Computed CI status: "success", "failure", "pending", "none"
+	Number         int
+	State          string
+	Title          string
+	Body           string // For branch_name_script input
+	URL            string
+	Branch         string     // Branch name (headRefName for GitHub, source_branch for GitLab)
+	BaseBranch     string     // Base branch name (baseRefName for GitHub, target_branch for GitLab)
+	Author         string     // PR/MR author username
+	AuthorName     string     // PR/MR author full name
+	AuthorIsBot    bool       // Whether the author is a bot
+	IsDraft        bool       // Whether the PR is a draft
+	CIStatus       string     // Computed CI status: "success", "failure", "pending", "none"
+	ReviewDecision string     // Review state: "APPROVED", "CHANGES_REQUESTED", "REVIEW_REQUIRED" or empty
+	Checks         []*CICheck // CI checks, when fetched together with the PR
+	Labels         []string   // Label names attached to the PR/MR
+}
+
+// DeploymentInfo captures the latest deployment of a branch to an environment.
+type DeploymentInfo struct {
+	Environment string // Environment name, e.g. "preview"
+	State       string // Deployment state: "success", "pending", "in_progress", "failure", "error", "inactive"
+	URL         string // Environment URL, when the deployment exposes one
 }
 
 // IssueInfo captures the relevant metadata for an issue.
@@ -43,16 +59,38 @@ type CICheck struct {
 	Conclusion string // Conclusion: "success", "failure", "skipped", "cancelled", etc.
 }
 
+// CIAnnotation is a finding, such as a compiler error or a failed
+// assertion, that a CI check attached to lines of a file.
+type CIAnnotation struct {
+	Check     string // Name of the check run reporting it
+	Path      string // File relative to the repository root
+	StartLine int
+	EndLine   int
+	Level     string // "failure", "warning" or "notice"
+	Title     string
+	Message   string
+}
+
 // WorktreeInfo summarizes the information for a git worktree.
 type WorktreeInfo struct {
 	Path           string
 	Branch         string
 	IsMain         bool
+	Head           string // Commit checked out in the worktree
+	Detached       bool
+	Bare           bool // The repository itself when it has no main working tree
+	Locked         bool
+	LockReason     string
+	Prunable       bool // The worktree directory is missing; git worktree prune would drop it
+	PrunableReason string
+	Sparse         bool // Only some directories are checked out (git sparse-checkout)
 	Dirty          bool
 	Ahead          int
 	Behind         int
 	HasUpstream    bool
 	UpstreamBranch string // The upstream branch name (e.g., "origin/main" or "chmouel/feature-branch")
+	DivergenceRef  string // Remote-tracking ref Ahead and Behind count against
+	LastFetchTS    int64  // Unix timestamp of the last fetch seen by this worktree
 	LastActive     string
 	LastActiveTS   int64
 	LastSwitchedTS int64 // Unix timestamp of last UI access/switch
@@ -63,21 +101,64 @@ type WorktreeInfo struct {
 	Modified       int
 	Staged         int
 	Divergence     string
+	Owner          string // Who created the worktree: the recorded identity, or the directory's OS user
 }
 
 const (
 	// LastSelectedFilename stores the last worktree selection for a repo.
 	LastSelectedFilename = ".last-selected"
-	// CacheFilename stores cached worktree metadata for faster loads.
+	// CacheFilename stores cached worktree metadata for faster loads. It lives
+	// under the cache directory rather than the worktree directory.
 	CacheFilename = ".worktree-cache.json"
 	// CommandHistoryFilename stores the command history for the ! command.
 	CommandHistoryFilename = ".command-history.json"
 	// AccessHistoryFilename stores worktree access timestamps for sorting.
 	AccessHistoryFilename = ".worktree-access.json"
+	// NavigationHistoryFilename stores the back/forward worktree navigation history.
+	NavigationHistoryFilename = ".worktree-navigation.json"
 	// CommandPaletteHistoryFilename stores command palette usage history for MRU sorting.
 	CommandPaletteHistoryFilename = ".command-palette-history.json"
+	// WorktreeOrderFilename stores the pinned worktrees and the manual order.
+	WorktreeOrderFilename = ".worktree-order.json"
+	// AdoptedWorktreesFilename lists worktrees outside the managed directory that were adopted in place.
+	AdoptedWorktreesFilename = ".adopted-worktrees.json"
+	// InstancesDirname holds a file per running instance, named after its
+	// process ID, under the cache directory.
+	InstancesDirname = ".instances"
+	// ControlSocketFilename is the Unix socket a running instance listens on
+	// for control commands, under the cache directory.
+	ControlSocketFilename = "control.sock"
+	// SnapshotsDirname holds worktree snapshots under the cache directory.
+	SnapshotsDirname = "snapshots"
+	// MaintenanceFilename records when each maintenance task last ran.
+	MaintenanceFilename = ".maintenance.json"
+	// HealthFilename records the latest health check results of each
+	// worktree, under the cache directory.
+	HealthFilename = ".health.json"
+	// DeletedWorktreesFilename lists recently deleted worktrees, under the
+	// cache directory.
+	DeletedWorktreesFilename = ".deleted-worktrees.json"
+	// ServicesFilename lists the services running in each worktree, under
+	// the cache directory.
+	ServicesFilename = ".services.json"
+	// ArtifactsFilename lists the build artifacts synced into each
+	// worktree, under the cache directory.
+	ArtifactsFilename = ".artifacts.json"
 )
 
+// StateFilenames lists the durable per-repository files kept under the
+// worktree directory, in the order export-state bundles them.
+var StateFilenames = []string{
+	LastSelectedFilename,
+	CommandHistoryFilename,
+	AccessHistoryFilename,
+	NavigationHistoryFilename,
+	WorktreeOrderFilename,
+	CommandPaletteHistoryFilename,
+	AdoptedWorktreesFilename,
+	MaintenanceFilename,
+}
+
 // PR fetch status values for WorktreeInfo.PRFetchStatus field.
 const (
 	PRFetchStatusNotFetched = "not_fetched" // PR data has not been fetched yet
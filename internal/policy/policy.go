@@ -0,0 +1,154 @@
+// Package policy enforces the worktree quotas and naming rules an
+// administrator sets in the configuration.
+package policy
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/chmouel/lazyworktree/internal/utils"
+)
+
+// Policy restricts which worktrees may be created. Zero values disable each
+// rule.
+type Policy struct {
+	MaxWorktrees int
+	MaxDiskBytes int64
+	// BranchPattern must match the whole branch name.
+	BranchPattern *regexp.Regexp
+	// BannedBases are glob patterns, such as "release/*", matched against
+	// the base branch with and without its remote prefix.
+	BannedBases []string
+}
+
+// Usage is a repository's current consumption.
+type Usage struct {
+	// Worktrees counts the worktrees besides the main one.
+	Worktrees int
+	// DiskBytes is the size of the repository's worktree directory, or -1
+	// when it has not been measured.
+	DiskBytes int64
+}
+
+// New builds a policy from the configuration values, rejecting an invalid
+// size or pattern.
+func New(maxWorktrees int, maxDiskUsage, branchPattern string, bannedBases []string) (Policy, error) {
+	p := Policy{MaxWorktrees: max(maxWorktrees, 0), BannedBases: bannedBases}
+	if strings.TrimSpace(maxDiskUsage) != "" {
+		size, err := ParseSize(maxDiskUsage)
+		if err != nil {
+			return Policy{}, fmt.Errorf("max_disk_usage: %w", err)
+		}
+		p.MaxDiskBytes = size
+	}
+	if pattern := strings.TrimSpace(branchPattern); pattern != "" {
+		re, err := regexp.Compile("^(?:" + pattern + ")$")
+		if err != nil {
+			return Policy{}, fmt.Errorf("branch_name_pattern: %w", err)
+		}
+		p.BranchPattern = re
+	}
+	for _, base := range bannedBases {
+		if _, err := path.Match(base, ""); err != nil {
+			return Policy{}, fmt.Errorf("banned_base_branches: invalid pattern %q: %w", base, err)
+		}
+	}
+	return p, nil
+}
+
+// HasQuota reports whether a worktree count or disk quota is set.
+func (p Policy) HasQuota() bool {
+	return p.MaxWorktrees > 0 || p.MaxDiskBytes > 0
+}
+
+// NeedsDiskUsage reports whether checks depend on the measured disk usage.
+func (p Policy) NeedsDiskUsage() bool {
+	return p.MaxDiskBytes > 0
+}
+
+// CheckCreate returns why a worktree for branch, based on base, may not be
+// created. An empty branch or base skips the rule about it, as for pull
+// request branches which already exist.
+func (p Policy) CheckCreate(u Usage, branch, base string) error {
+	if base != "" {
+		if banned := p.bannedBase(base); banned != "" {
+			return fmt.Errorf("worktrees may not be based on %s (banned_base_branches: %s)", base, banned)
+		}
+	}
+	if branch != "" && p.BranchPattern != nil && !p.BranchPattern.MatchString(branch) {
+		pattern := strings.TrimSuffix(strings.TrimPrefix(p.BranchPattern.String(), "^(?:"), ")$")
+		return fmt.Errorf("branch %q does not match branch_name_pattern %s", branch, pattern)
+	}
+	if p.MaxWorktrees > 0 && u.Worktrees >= p.MaxWorktrees {
+		return fmt.Errorf("this repository already has %d of the %d worktrees allowed by max_worktrees; delete or prune one first", u.Worktrees, p.MaxWorktrees)
+	}
+	if p.MaxDiskBytes > 0 && u.DiskBytes >= p.MaxDiskBytes {
+		return fmt.Errorf("worktrees already use %s of the %s allowed by max_disk_usage; delete or prune one first", utils.FormatBytes(u.DiskBytes), utils.FormatBytes(p.MaxDiskBytes))
+	}
+	return nil
+}
+
+// bannedBase returns the pattern banning base, if any.
+func (p Policy) bannedBase(base string) string {
+	base = strings.TrimPrefix(base, "refs/heads/")
+	candidates := []string{base}
+	if _, branch, ok := strings.Cut(strings.TrimPrefix(base, "refs/remotes/"), "/"); ok {
+		candidates = append(candidates, branch)
+	}
+	for _, pattern := range p.BannedBases {
+		for _, candidate := range candidates {
+			if ok, _ := path.Match(pattern, candidate); ok {
+				return pattern
+			}
+		}
+	}
+	return ""
+}
+
+// Summary renders usage against the quotas, e.g. "3/10 worktrees · 1.2
+// GiB/20.0 GiB". It is empty when no quota is set.
+func (p Policy) Summary(u Usage) string {
+	var parts []string
+	if p.MaxWorktrees > 0 {
+		parts = append(parts, fmt.Sprintf("%d/%d worktrees", u.Worktrees, p.MaxWorktrees))
+	}
+	if p.MaxDiskBytes > 0 {
+		used := "…"
+		if u.DiskBytes >= 0 {
+			used = utils.FormatBytes(u.DiskBytes)
+		}
+		parts = append(parts, fmt.Sprintf("%s/%s", used, utils.FormatBytes(p.MaxDiskBytes)))
+	}
+	return strings.Join(parts, " · ")
+}
+
+// ParseSize parses a size such as "20GiB", "500M" or "1048576". Units are
+// binary, so "G", "GB" and "GiB" all mean 1024³ bytes.
+func ParseSize(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	i := strings.IndexFunc(s, func(r rune) bool { return !unicode.IsDigit(r) && r != '.' })
+	number, unit := s, ""
+	if i >= 0 {
+		number, unit = s[:i], strings.TrimSpace(s[i:])
+	}
+	value, err := strconv.ParseFloat(number, 64)
+	if err != nil || value < 0 {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+	unit = strings.TrimSuffix(strings.TrimSuffix(strings.ToUpper(unit), "B"), "I")
+	exp := 0
+	if unit != "" {
+		exp = strings.Index("KMGT", unit) + 1
+		if exp == 0 || len(unit) > 1 {
+			return 0, fmt.Errorf("invalid size unit in %q", s)
+		}
+	}
+	for range exp {
+		value *= 1024
+	}
+	return int64(value), nil
+}
@@ -0,0 +1,61 @@
+package policy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSize(t *testing.T) {
+	for in, want := range map[string]int64{
+		"1048576": 1 << 20,
+		"512K":    512 << 10,
+		"500MB":   500 << 20,
+		"20GiB":   20 << 30,
+		"1.5 g":   3 << 29,
+		"2T":      2 << 40,
+	} {
+		got, err := ParseSize(in)
+		require.NoError(t, err, in)
+		assert.Equal(t, want, got, in)
+	}
+	for _, bad := range []string{"", "lots", "10XB", "-1G", "10MM"} {
+		_, err := ParseSize(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestNewRejectsInvalidValues(t *testing.T) {
+	_, err := New(0, "big", "", nil)
+	assert.ErrorContains(t, err, "max_disk_usage")
+	_, err = New(0, "", "feature/(", nil)
+	assert.ErrorContains(t, err, "branch_name_pattern")
+	_, err = New(0, "", "", []string{"release/["})
+	assert.ErrorContains(t, err, "banned_base_branches")
+}
+
+func TestCheckCreate(t *testing.T) {
+	p, err := New(3, "1GiB", `(feature|fix)/[a-z0-9-]+`, []string{"production", "release/*"})
+	require.NoError(t, err)
+	room := Usage{Worktrees: 1, DiskBytes: 10 << 20}
+
+	require.NoError(t, p.CheckCreate(room, "feature/login", "main"))
+	assert.ErrorContains(t, p.CheckCreate(room, "login", "main"), `branch "login" does not match branch_name_pattern (feature|fix)/[a-z0-9-]+`)
+	assert.ErrorContains(t, p.CheckCreate(room, "fix/x", "origin/production"), "may not be based on origin/production")
+	assert.ErrorContains(t, p.CheckCreate(room, "fix/x", "release/1.2"), "banned_base_branches: release/*")
+	require.NoError(t, p.CheckCreate(room, "fix/x", ""), "an unknown base skips the base rule")
+	assert.ErrorContains(t, p.CheckCreate(Usage{Worktrees: 3, DiskBytes: -1}, "fix/x", "main"), "3 of the 3 worktrees allowed by max_worktrees")
+	assert.ErrorContains(t, p.CheckCreate(Usage{Worktrees: 1, DiskBytes: 2 << 30}, "fix/x", "main"), "2.0 GiB of the 1.0 GiB allowed by max_disk_usage")
+	require.NoError(t, p.CheckCreate(Usage{Worktrees: 1, DiskBytes: -1}, "fix/x", "main"), "unmeasured usage does not block creation")
+}
+
+func TestSummary(t *testing.T) {
+	p, err := New(10, "20G", "", nil)
+	require.NoError(t, err)
+	assert.True(t, p.HasQuota())
+	assert.Equal(t, "3/10 worktrees · …/20.0 GiB", p.Summary(Usage{Worktrees: 3, DiskBytes: -1}))
+	assert.Equal(t, "3/10 worktrees · 1.5 GiB/20.0 GiB", p.Summary(Usage{Worktrees: 3, DiskBytes: 3 << 29}))
+	assert.Empty(t, Policy{}.Summary(Usage{}))
+	assert.False(t, Policy{}.HasQuota())
+}
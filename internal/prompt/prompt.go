@@ -0,0 +1,155 @@
+// Package prompt renders the one-line repository summary printed by
+// "lazyworktree prompt" for shell prompts and status bars. It reads only
+// the worktree cache written by the TUI, so it stays fast enough to run on
+// every prompt.
+package prompt
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+// DefaultFormat renders e.g. "3wt 1* 1✗ 2pr", leaving out zero counts
+// other than the worktrees.
+const DefaultFormat = `{{.Worktrees}}wt{{if .Dirty}} {{.Dirty}}*{{end}}{{if .FailingCI}} {{.FailingCI}}✗{{end}}{{if .OpenPRs}} {{.OpenPRs}}pr{{end}}`
+
+// RepoIndexFilename maps worktree roots to repository keys under the cache
+// directory, sparing the prompt a call to git.
+const RepoIndexFilename = "prompt-repos.json"
+
+// Summary counts the state of a repository's worktrees.
+type Summary struct {
+	Repo string
+	// Worktrees counts the worktrees besides the main one.
+	Worktrees int
+	Dirty     int
+	FailingCI int
+	OpenPRs   int
+}
+
+// Summarise counts the worktrees of repo.
+func Summarise(repo string, worktrees []*models.WorktreeInfo) Summary {
+	s := Summary{Repo: repo}
+	for _, wt := range worktrees {
+		if !wt.IsMain {
+			s.Worktrees++
+		}
+		if wt.Dirty {
+			s.Dirty++
+		}
+		if wt.PR == nil {
+			continue
+		}
+		if wt.PR.State == "OPEN" {
+			s.OpenPRs++
+		}
+		if wt.PR.CIStatus == "failure" {
+			s.FailingCI++
+		}
+	}
+	return s
+}
+
+// Render formats s with a Go template such as DefaultFormat.
+func Render(format string, s Summary) (string, error) {
+	tmpl, err := template.New("prompt").Parse(format)
+	if err != nil {
+		return "", fmt.Errorf("invalid prompt format: %w", err)
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, s); err != nil {
+		return "", fmt.Errorf("invalid prompt format: %w", err)
+	}
+	return b.String(), nil
+}
+
+// WorktreeRoot returns the nearest directory from dir upwards holding a
+// .git entry, or "" outside a repository. Unlike asking git, it costs only
+// a few stat calls.
+func WorktreeRoot(dir string) string {
+	for {
+		if _, err := os.Lstat(filepath.Join(dir, ".git")); err == nil {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// ReadWorktrees reads the worktree cache the TUI keeps at path.
+func ReadWorktrees(path string) ([]*models.WorktreeInfo, error) {
+	// #nosec G304 -- the cache path is derived from the cache directory
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var cache struct {
+		Worktrees []*models.WorktreeInfo `json:"worktrees"`
+	}
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return nil, err
+	}
+	return cache.Worktrees, nil
+}
+
+// RepoIndex remembers the repository key of each worktree root seen.
+type RepoIndex struct {
+	path  string
+	repos map[string]string
+}
+
+// LoadRepoIndex reads the index at path; a missing or damaged file yields
+// an empty index.
+func LoadRepoIndex(path string) *RepoIndex {
+	idx := &RepoIndex{path: path, repos: map[string]string{}}
+	// #nosec G304 -- the index path is derived from the cache directory
+	if data, err := os.ReadFile(path); err == nil {
+		_ = json.Unmarshal(data, &idx.repos)
+	}
+	if idx.repos == nil {
+		idx.repos = map[string]string{}
+	}
+	return idx
+}
+
+// Lookup returns the repository key recorded for root.
+func (idx *RepoIndex) Lookup(root string) (string, bool) {
+	repo, ok := idx.repos[root]
+	return repo, ok
+}
+
+// Store records repo for root and saves the index, dropping roots which no
+// longer exist.
+func (idx *RepoIndex) Store(root, repo string) error {
+	for known := range idx.repos {
+		if _, err := os.Stat(known); err != nil {
+			delete(idx.repos, known)
+		}
+	}
+	idx.repos[root] = repo
+	data, err := json.Marshal(idx.repos)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(idx.path), 0o750); err != nil {
+		return err
+	}
+	tmp := fmt.Sprintf("%s.%d.tmp", idx.path, os.Getpid())
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, idx.path); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package prompt
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+func TestSummariseAndRender(t *testing.T) {
+	worktrees := []*models.WorktreeInfo{
+		{Path: "/repo", IsMain: true, Dirty: true},
+		{Path: "/wt/a", PR: &models.PRInfo{State: "OPEN", CIStatus: "failure"}},
+		{Path: "/wt/b", Dirty: true, PR: &models.PRInfo{State: "MERGED", CIStatus: "success"}},
+		{Path: "/wt/c"},
+	}
+	s := Summarise("owner/repo", worktrees)
+	want := Summary{Repo: "owner/repo", Worktrees: 3, Dirty: 2, FailingCI: 1, OpenPRs: 1}
+	if s != want {
+		t.Fatalf("expected %+v, got %+v", want, s)
+	}
+
+	tests := []struct {
+		name    string
+		format  string
+		summary Summary
+		want    string
+	}{
+		{name: "default", format: DefaultFormat, summary: s, want: "3wt 2* 1✗ 1pr"},
+		{name: "default omits zero counts", format: DefaultFormat, summary: Summary{Worktrees: 2}, want: "2wt"},
+		{name: "custom", format: "{{.Repo}}:{{.OpenPRs}}", summary: s, want: "owner/repo:1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Render(tt.format, tt.summary)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Fatalf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+	if _, err := Render("{{.Nope}}", s); err == nil {
+		t.Fatal("expected an unknown field to be rejected")
+	}
+}
+
+func TestWorktreeRoot(t *testing.T) {
+	root := t.TempDir()
+	nested := filepath.Join(root, "a", "b")
+	if err := os.MkdirAll(nested, 0o750); err != nil {
+		t.Fatal(err)
+	}
+	if got := WorktreeRoot(nested); got != "" {
+		t.Fatalf("expected no root outside a repository, got %q", got)
+	}
+	// Linked worktrees have a .git file rather than a directory.
+	if err := os.WriteFile(filepath.Join(root, ".git"), []byte("gitdir: /elsewhere\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if got := WorktreeRoot(nested); got != root {
+		t.Fatalf("expected %q, got %q", root, got)
+	}
+}
+
+func TestRepoIndex(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, RepoIndexFilename)
+	root := t.TempDir()
+
+	idx := LoadRepoIndex(path)
+	if _, ok := idx.Lookup(root); ok {
+		t.Fatal("expected an empty index")
+	}
+	if err := idx.Store("/gone", "old/repo"); err != nil {
+		t.Fatal(err)
+	}
+	if err := idx.Store(root, "owner/repo"); err != nil {
+		t.Fatal(err)
+	}
+
+	idx = LoadRepoIndex(path)
+	if repo, ok := idx.Lookup(root); !ok || repo != "owner/repo" {
+		t.Fatalf("expected owner/repo, got %q", repo)
+	}
+	if _, ok := idx.Lookup("/gone"); ok {
+		t.Fatal("expected roots which no longer exist to be dropped")
+	}
+}
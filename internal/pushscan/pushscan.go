@@ -0,0 +1,173 @@
+// Package pushscan looks through the commits a push would publish for large
+// files and obvious secrets, so they are caught before they reach a remote
+// rather than cleaned up with a force push afterwards.
+package pushscan
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// scanTimeout bounds the whole scan.
+const scanTimeout = 60 * time.Second
+
+// Finding is a large file or a likely secret in the commits to push.
+type Finding struct {
+	Rule   string // "Large file" or the kind of secret
+	Path   string // File it was found in
+	Commit string // Abbreviated commit adding it, when known
+	Detail string // Size of the file, or the secret with most of it hidden
+}
+
+// rule matches one kind of secret on an added line; the first group, when
+// present, is the secret itself.
+type rule struct {
+	name string
+	re   *regexp.Regexp
+}
+
+// rules are a small set of well-known credential formats, in the spirit of
+// gitleaks, favouring few false alarms over catching everything.
+var rules = []rule{
+	{"Private key", regexp.MustCompile(`-----BEGIN (?:[A-Z]+ )?PRIVATE KEY(?: BLOCK)?-----`)},
+	{"AWS access key", regexp.MustCompile(`\b((?:AKIA|ASIA)[0-9A-Z]{16})\b`)},
+	{"GitHub token", regexp.MustCompile(`\b(gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})\b`)},
+	{"GitLab token", regexp.MustCompile(`\b(glpat-[A-Za-z0-9_-]{20,})`)},
+	{"Slack token", regexp.MustCompile(`\b(xox[abprs]-[A-Za-z0-9-]{10,})`)},
+	{"Google API key", regexp.MustCompile(`\b(AIza[0-9A-Za-z_-]{35})`)},
+	{"Stripe secret key", regexp.MustCompile(`\b([rs]k_live_[0-9A-Za-z]{20,})`)},
+	{"Hard-coded secret", regexp.MustCompile(`(?i)\b(?:password|passwd|secret|api[_-]?key|access[_-]?token|auth[_-]?token)\b["']?\s*[:=]\s*["']([^"'\s]{8,})["']`)},
+}
+
+// Scan reports the files over maxFileBytes and the likely secrets on lines
+// added by the commits in dir that no remote has yet.
+func Scan(ctx context.Context, dir string, maxFileBytes int64) ([]Finding, error) {
+	ctx, cancel := context.WithTimeout(ctx, scanTimeout)
+	defer cancel()
+
+	large, err := largeFiles(ctx, dir, maxFileBytes)
+	if err != nil {
+		return nil, err
+	}
+	// #nosec G204 -- fixed git arguments
+	cmd := exec.CommandContext(ctx, "git", "log", "-p", "-U0", "--no-color", "--no-ext-diff", "--no-renames", "--format=commit %h", "HEAD", "--not", "--remotes")
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, commandError(err)
+	}
+	return append(large, ScanDiff(bytes.NewReader(out))...), nil
+}
+
+// largeFiles lists the files over maxFileBytes among the objects the
+// unpushed commits add.
+func largeFiles(ctx context.Context, dir string, maxFileBytes int64) ([]Finding, error) {
+	// #nosec G204 -- fixed git arguments
+	list := exec.CommandContext(ctx, "git", "rev-list", "--objects", "HEAD", "--not", "--remotes")
+	list.Dir = dir
+	objects, err := list.Output()
+	if err != nil {
+		return nil, commandError(err)
+	}
+	paths := map[string]string{}
+	var input strings.Builder
+	for line := range strings.SplitSeq(string(objects), "\n") {
+		oid, path, ok := strings.Cut(line, " ")
+		if ok && path != "" {
+			paths[oid] = path
+			input.WriteString(oid + "\n")
+		}
+	}
+	if input.Len() == 0 {
+		return nil, nil
+	}
+
+	// #nosec G204 -- fixed git arguments
+	check := exec.CommandContext(ctx, "git", "cat-file", "--batch-check=%(objectname) %(objecttype) %(objectsize)")
+	check.Dir = dir
+	check.Stdin = strings.NewReader(input.String())
+	out, err := check.Output()
+	if err != nil {
+		return nil, commandError(err)
+	}
+	var found []Finding
+	for line := range strings.SplitSeq(string(out), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 3 || fields[1] != "blob" {
+			continue
+		}
+		size, err := strconv.ParseInt(fields[2], 10, 64)
+		if err != nil || size <= maxFileBytes {
+			continue
+		}
+		found = append(found, Finding{Rule: "Large file", Path: paths[fields[0]], Detail: formatSize(size)})
+	}
+	return found, nil
+}
+
+// ScanDiff reports the likely secrets on the added lines of a patch, as
+// printed by git log -p --format="commit %h".
+func ScanDiff(r io.Reader) []Finding {
+	var found []Finding
+	commit, path := "", ""
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "commit "):
+			commit = strings.TrimPrefix(line, "commit ")
+		case strings.HasPrefix(line, "+++ "):
+			path = strings.TrimPrefix(strings.TrimPrefix(line, "+++ "), "b/")
+		case strings.HasPrefix(line, "+"):
+			for _, r := range rules {
+				match := r.re.FindStringSubmatch(line[1:])
+				if match == nil {
+					continue
+				}
+				secret := match[0]
+				if len(match) > 1 {
+					secret = match[1]
+				}
+				found = append(found, Finding{Rule: r.name, Path: path, Commit: commit, Detail: redact(secret)})
+				break
+			}
+		}
+	}
+	return found
+}
+
+// redact keeps only the start of a secret, enough to recognise it.
+func redact(secret string) string {
+	if strings.HasPrefix(secret, "-----") {
+		return secret
+	}
+	keep := min(4, len(secret)/4)
+	return secret[:keep] + strings.Repeat("*", 8)
+}
+
+// formatSize renders a size in bytes as MiB or KiB.
+func formatSize(size int64) string {
+	if size >= 1<<20 {
+		return fmt.Sprintf("%.1f MiB", float64(size)/(1<<20))
+	}
+	return fmt.Sprintf("%.1f KiB", float64(size)/(1<<10))
+}
+
+// commandError prefers git's own message to the exit status.
+func commandError(err error) error {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && len(bytes.TrimSpace(exitErr.Stderr)) > 0 {
+		return fmt.Errorf("%s", strings.TrimSpace(string(exitErr.Stderr)))
+	}
+	return err
+}
@@ -0,0 +1,150 @@
+// Package search finds worktrees by branch, directory name or pull request
+// across every repository with worktrees under the worktree root, for when
+// it is not clear which repository a ticket's branch lives in.
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/chmouel/lazyworktree/internal/git"
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+// Match is a worktree matching the query.
+type Match struct {
+	Repo   string         // Main worktree of the repository
+	Path   string         // Worktree directory
+	Branch string         // Empty for a detached HEAD
+	PR     *models.PRInfo // From the TUI's worktree cache; nil when unknown
+}
+
+// RepoName is the repository's directory name, for display.
+func (m Match) RepoName() string {
+	return filepath.Base(m.Repo)
+}
+
+// Repositories returns the main repositories owning worktrees under
+// worktreeRoot, together with those the TUI cached worktrees for under
+// cacheRoot, sorted by path.
+func Repositories(worktreeRoot, cacheRoot string) []string {
+	repos := git.KnownRepositories(worktreeRoot)
+	for repo := range readCaches(cacheRoot) {
+		if !slices.Contains(repos, repo) {
+			if info, err := os.Stat(repo); err == nil && info.IsDir() {
+				repos = append(repos, repo)
+			}
+		}
+	}
+	slices.Sort(repos)
+	return repos
+}
+
+// Find returns the worktrees of repos matching query, in repository then
+// path order. A query matches a branch, directory name or PR title
+// containing it, case-insensitively, and "#123", "!123" or "123" also
+// matches PR 123. PR details come from the worktree caches under
+// cacheRoot, so only PRs the TUI has seen are found.
+func Find(ctx context.Context, repos []string, cacheRoot, query string) []Match {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return nil
+	}
+	caches := readCaches(cacheRoot)
+	var matches []Match
+	for _, repo := range repos {
+		prs := make(map[string]*models.PRInfo)
+		for _, wt := range caches[repo] {
+			if wt.PR != nil {
+				prs[wt.Path] = wt.PR
+			}
+		}
+		for _, wt := range listWorktrees(ctx, repo) {
+			wt.Repo = repo
+			wt.PR = prs[wt.Path]
+			if matchesQuery(wt, query) {
+				matches = append(matches, wt)
+			}
+		}
+	}
+	return matches
+}
+
+// matchesQuery reports whether the worktree matches the lower-cased query.
+func matchesQuery(m Match, query string) bool {
+	if m.PR != nil {
+		if number, err := strconv.Atoi(strings.TrimLeft(query, "#!")); err == nil && number == m.PR.Number {
+			return true
+		}
+	}
+	fields := []string{m.Branch, filepath.Base(m.Path)}
+	if m.PR != nil {
+		fields = append(fields, m.PR.Title, m.PR.URL)
+	}
+	for _, field := range fields {
+		if field != "" && strings.Contains(strings.ToLower(field), query) {
+			return true
+		}
+	}
+	return false
+}
+
+// listWorktrees lists the repository's worktrees with git, which is quick
+// and always current, unlike the caches.
+func listWorktrees(ctx context.Context, repo string) []Match {
+	// #nosec G204 -- repo is a repository found under the worktree root
+	cmd := exec.CommandContext(ctx, "git", "-C", repo, "worktree", "list", "--porcelain")
+	out, err := cmd.Output()
+	if err != nil {
+		return nil
+	}
+	var worktrees []Match
+	for block := range strings.SplitSeq(strings.TrimSpace(string(out)), "\n\n") {
+		var wt Match
+		for line := range strings.SplitSeq(block, "\n") {
+			if path, ok := strings.CutPrefix(line, "worktree "); ok {
+				wt.Path = path
+			}
+			if ref, ok := strings.CutPrefix(line, "branch "); ok {
+				wt.Branch = strings.TrimPrefix(ref, "refs/heads/")
+			}
+		}
+		if wt.Path != "" {
+			worktrees = append(worktrees, wt)
+		}
+	}
+	return worktrees
+}
+
+// readCaches reads the worktree caches the TUI keeps under cacheRoot, one
+// per repository key such as "owner/repo", keyed by the repository's main
+// worktree.
+func readCaches(cacheRoot string) map[string][]*models.WorktreeInfo {
+	caches := make(map[string][]*models.WorktreeInfo)
+	_ = filepath.WalkDir(cacheRoot, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || d.Name() != models.CacheFilename {
+			return nil
+		}
+		// #nosec G304 -- the path is found under the cache directory
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		var cache struct {
+			Repo      string                 `json:"repo"`
+			Worktrees []*models.WorktreeInfo `json:"worktrees"`
+		}
+		if json.Unmarshal(data, &cache) == nil && cache.Repo != "" {
+			caches[cache.Repo] = cache.Worktrees
+		}
+		return nil
+	})
+	return caches
+}
@@ -0,0 +1,66 @@
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/chmouel/lazyworktree/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func runGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	cmd.Env = append(os.Environ(), "GIT_CONFIG_GLOBAL=/dev/null", "GIT_CONFIG_NOSYSTEM=1")
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+}
+
+func TestFind(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	root := t.TempDir()
+	repo := filepath.Join(t.TempDir(), "api")
+	require.NoError(t, os.MkdirAll(repo, 0o750))
+	runGit(t, repo, "init", "--quiet", "--initial-branch=main")
+	runGit(t, repo, "-c", "user.name=T", "-c", "user.email=t@example.com", "commit", "--quiet", "--allow-empty", "-m", "init")
+	wtPath := filepath.Join(root, "api", "jira-123-login")
+	runGit(t, repo, "worktree", "add", "--quiet", "-b", "jira-123-login", wtPath)
+
+	cacheRoot := t.TempDir()
+	cacheDir := filepath.Join(cacheRoot, "owner", "api")
+	require.NoError(t, os.MkdirAll(cacheDir, 0o750))
+	data, err := json.Marshal(map[string]any{
+		"version": 1,
+		"repo":    repo,
+		"worktrees": []*models.WorktreeInfo{
+			{Path: wtPath, Branch: "jira-123-login", PR: &models.PRInfo{Number: 42, Title: "Fix the login redirect", URL: "https://example.com/pull/42"}},
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(cacheDir, models.CacheFilename), data, 0o600))
+
+	repos := Repositories(root, cacheRoot)
+	require.Len(t, repos, 1)
+
+	for _, query := range []string{"JIRA-123", "#42", "42", "login redirect"} {
+		matches := Find(context.Background(), repos, cacheRoot, query)
+		require.Len(t, matches, 1, query)
+		assert.Equal(t, "jira-123-login", matches[0].Branch)
+		assert.Equal(t, "api", matches[0].RepoName())
+		require.NotNil(t, matches[0].PR)
+		assert.Equal(t, 42, matches[0].PR.Number)
+	}
+
+	matches := Find(context.Background(), repos, cacheRoot, "main")
+	require.Len(t, matches, 1)
+	assert.Nil(t, matches[0].PR)
+	assert.Empty(t, Find(context.Background(), repos, cacheRoot, "#7"))
+	assert.Empty(t, Find(context.Background(), repos, cacheRoot, " "))
+}
@@ -0,0 +1,22 @@
+//go:build !windows
+
+package secrets
+
+import "runtime"
+
+// platformBackends lists the installed credential stores, preferred first.
+func platformBackends() []Backend {
+	var backends []Backend
+	if runtime.GOOS == "darwin" {
+		if path := lookup("security"); path != "" {
+			backends = append(backends, keychain{path: path})
+		}
+	}
+	if path := lookup("secret-tool"); path != "" {
+		backends = append(backends, secretService{path: path})
+	}
+	if path := lookup("keyctl"); path != "" {
+		backends = append(backends, kernelKeyring{path: path})
+	}
+	return backends
+}
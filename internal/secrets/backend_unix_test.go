@@ -0,0 +1,35 @@
+//go:build !windows
+
+package secrets
+
+import (
+	"errors"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultPrefersSecretService(t *testing.T) {
+	orig := LookPath
+	t.Cleanup(func() { LookPath = orig })
+	installed := map[string]bool{"secret-tool": true, "keyctl": true}
+	LookPath = func(name string) (string, error) {
+		if installed[name] {
+			return "/usr/bin/" + name, nil
+		}
+		return "", errors.New("not found")
+	}
+
+	if runtime.GOOS != "darwin" {
+		assert.Equal(t, secretService{path: "/usr/bin/secret-tool"}, Default())
+	}
+
+	delete(installed, "secret-tool")
+	if runtime.GOOS != "darwin" {
+		assert.Equal(t, kernelKeyring{path: "/usr/bin/keyctl"}, Default())
+	}
+
+	delete(installed, "keyctl")
+	assert.Nil(t, Default())
+}
@@ -0,0 +1,82 @@
+//go:build windows
+
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// platformBackends lists the installed credential stores, preferred first.
+func platformBackends() []Backend {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return nil
+	}
+	return []Backend{dpapi{dir: filepath.Join(dir, service, "secrets")}}
+}
+
+// dpapi encrypts secrets with the Windows Data Protection API, tying them to
+// the current user, and keeps the encrypted blobs in dir.
+type dpapi struct{ dir string }
+
+func (d dpapi) Name() string { return "Windows DPAPI" }
+
+func (d dpapi) path(name string) string { return filepath.Join(d.dir, name+".bin") }
+
+func (d dpapi) Get(_ context.Context, name string) (string, error) {
+	data, err := os.ReadFile(d.path(name))
+	if err != nil {
+		return "", err
+	}
+	plain, err := unprotect(data)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt: %w", err)
+	}
+	return string(plain), nil
+}
+
+func (d dpapi) Set(_ context.Context, name, value string) error {
+	data, err := protect([]byte(value))
+	if err != nil {
+		return fmt.Errorf("failed to encrypt: %w", err)
+	}
+	if err := os.MkdirAll(d.dir, 0o700); err != nil {
+		return err
+	}
+	return os.WriteFile(d.path(name), data, 0o600)
+}
+
+func blob(data []byte) *windows.DataBlob {
+	if len(data) == 0 {
+		return &windows.DataBlob{}
+	}
+	return &windows.DataBlob{Size: uint32(len(data)), Data: &data[0]}
+}
+
+// takeBlob copies out and frees a blob allocated by DPAPI.
+func takeBlob(out *windows.DataBlob) []byte {
+	defer func() { _, _ = windows.LocalFree(windows.Handle(unsafe.Pointer(out.Data))) }()
+	return append([]byte(nil), unsafe.Slice(out.Data, out.Size)...)
+}
+
+func protect(data []byte) ([]byte, error) {
+	var out windows.DataBlob
+	if err := windows.CryptProtectData(blob(data), nil, nil, 0, nil, windows.CRYPTPROTECT_UI_FORBIDDEN, &out); err != nil {
+		return nil, err
+	}
+	return takeBlob(&out), nil
+}
+
+func unprotect(data []byte) ([]byte, error) {
+	var out windows.DataBlob
+	if err := windows.CryptUnprotectData(blob(data), nil, nil, 0, nil, windows.CRYPTPROTECT_UI_FORBIDDEN, &out); err != nil {
+		return nil, err
+	}
+	return takeBlob(&out), nil
+}
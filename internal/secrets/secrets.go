@@ -0,0 +1,171 @@
+// Package secrets keeps tokens out of the plaintext configuration by storing
+// them in the operating system's credential store. The configuration then
+// holds a "secret:<name>" reference which is resolved when it is loaded.
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Prefix marks a configuration value as a reference to a stored secret.
+const Prefix = "secret:"
+
+// service names the secrets in the credential store.
+const service = "lazyworktree"
+
+// commandTimeout bounds each call to a credential store helper.
+const commandTimeout = 10 * time.Second
+
+// ErrUnavailable is returned when no supported credential store is found.
+var ErrUnavailable = errors.New("no supported credential store found")
+
+var nameRe = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+
+// Backend stores and retrieves secrets by name.
+type Backend interface {
+	// Name describes the store, e.g. "macOS Keychain".
+	Name() string
+	Get(ctx context.Context, name string) (string, error)
+	Set(ctx context.Context, name, value string) error
+}
+
+// LookPath finds credential store helpers; tests replace it.
+var LookPath = exec.LookPath
+
+// runCommand runs a helper with stdin and returns its trimmed stdout; tests
+// replace it.
+var runCommand = func(ctx context.Context, stdin, name string, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, commandTimeout)
+	defer cancel()
+	// #nosec G204 -- the helpers and their arguments are fixed in this package
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stdin = strings.NewReader(stdin)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%s: %w: %s", name, err, msg)
+		}
+		return "", fmt.Errorf("%s: %w", name, err)
+	}
+	return strings.TrimRight(string(out), "\r\n"), nil
+}
+
+// ValidateName checks that name can be used as a secret name.
+func ValidateName(name string) error {
+	if !nameRe.MatchString(name) {
+		return fmt.Errorf("invalid secret name %q: use letters, digits, '.', '_' and '-'", name)
+	}
+	return nil
+}
+
+// ParseRef returns the secret name when value is a "secret:<name>" reference.
+func ParseRef(value string) (string, bool) {
+	name, ok := strings.CutPrefix(strings.TrimSpace(value), Prefix)
+	if !ok {
+		return "", false
+	}
+	return name, true
+}
+
+// Resolve returns value unchanged unless it is a secret reference, in which
+// case the secret is read from backend.
+func Resolve(ctx context.Context, backend Backend, value string) (string, error) {
+	name, ok := ParseRef(value)
+	if !ok {
+		return value, nil
+	}
+	if err := ValidateName(name); err != nil {
+		return "", err
+	}
+	if backend == nil {
+		return "", ErrUnavailable
+	}
+	secret, err := backend.Get(ctx, name)
+	if err != nil {
+		return "", fmt.Errorf("failed to read secret %q from %s: %w", name, backend.Name(), err)
+	}
+	return secret, nil
+}
+
+// Default returns the preferred credential store for this platform, or nil
+// when none is available.
+func Default() Backend {
+	if backends := platformBackends(); len(backends) > 0 {
+		return backends[0]
+	}
+	return nil
+}
+
+// keychain stores secrets in the macOS Keychain through security(1).
+type keychain struct{ path string }
+
+func (k keychain) Name() string { return "macOS Keychain" }
+
+func (k keychain) Get(ctx context.Context, name string) (string, error) {
+	return runCommand(ctx, "", k.path, "find-generic-password", "-s", service, "-a", name, "-w")
+}
+
+// Set feeds the command to security's interactive mode so the secret never
+// appears in the process list.
+func (k keychain) Set(ctx context.Context, name, value string) error {
+	if strings.ContainsAny(value, "\"\\\n") {
+		return errors.New("the macOS Keychain helper cannot store values containing quotes, backslashes or newlines")
+	}
+	line := fmt.Sprintf("add-generic-password -U -s %s -a %s -w \"%s\"\n", service, name, value)
+	_, err := runCommand(ctx, line, k.path, "-i")
+	return err
+}
+
+// secretService stores secrets through the freedesktop Secret Service, as
+// provided by GNOME Keyring or KWallet, using secret-tool(1).
+type secretService struct{ path string }
+
+func (s secretService) Name() string { return "Secret Service" }
+
+func (s secretService) Get(ctx context.Context, name string) (string, error) {
+	return runCommand(ctx, "", s.path, "lookup", "service", service, "account", name)
+}
+
+func (s secretService) Set(ctx context.Context, name, value string) error {
+	_, err := runCommand(ctx, value, s.path, "store", "--label="+service+" "+name, "service", service, "account", name)
+	return err
+}
+
+// kernelKeyring stores secrets in the Linux user keyring with keyctl(1).
+// The keyring does not survive a reboot.
+type kernelKeyring struct{ path string }
+
+func (k kernelKeyring) Name() string { return "Linux kernel keyring" }
+
+func (k kernelKeyring) description(name string) string { return service + ":" + name }
+
+func (k kernelKeyring) Get(ctx context.Context, name string) (string, error) {
+	id, err := runCommand(ctx, "", k.path, "search", "@u", "user", k.description(name))
+	if err != nil {
+		return "", err
+	}
+	return runCommand(ctx, "", k.path, "pipe", strings.TrimSpace(id))
+}
+
+func (k kernelKeyring) Set(ctx context.Context, name, value string) error {
+	_, err := runCommand(ctx, value, k.path, "padd", "user", k.description(name), "@u")
+	return err
+}
+
+// lookup returns the helper's path, or "" when it is not installed.
+func lookup(name string) string {
+	path, err := LookPath(name)
+	if err != nil {
+		return ""
+	}
+	return path
+}
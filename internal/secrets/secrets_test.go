@@ -0,0 +1,131 @@
+package secrets
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type call struct {
+	stdin string
+	args  []string
+}
+
+// fakeRunner replaces runCommand, answering each call with the next output.
+func fakeRunner(t *testing.T, outputs ...string) *[]call {
+	t.Helper()
+	var calls []call
+	orig := runCommand
+	runCommand = func(_ context.Context, stdin, name string, args ...string) (string, error) {
+		calls = append(calls, call{stdin: stdin, args: append([]string{name}, args...)})
+		if len(outputs) == 0 {
+			return "", errors.New("unexpected call")
+		}
+		out := outputs[0]
+		outputs = outputs[1:]
+		return out, nil
+	}
+	t.Cleanup(func() { runCommand = orig })
+	return &calls
+}
+
+type memoryBackend map[string]string
+
+func (m memoryBackend) Name() string { return "memory" }
+
+func (m memoryBackend) Get(_ context.Context, name string) (string, error) {
+	v, ok := m[name]
+	if !ok {
+		return "", errors.New("not found")
+	}
+	return v, nil
+}
+
+func (m memoryBackend) Set(_ context.Context, name, value string) error {
+	m[name] = value
+	return nil
+}
+
+func TestParseRef(t *testing.T) {
+	name, ok := ParseRef(" secret:github ")
+	assert.True(t, ok)
+	assert.Equal(t, "github", name)
+
+	_, ok = ParseRef("ghp_plaintext")
+	assert.False(t, ok)
+}
+
+func TestValidateName(t *testing.T) {
+	require.NoError(t, ValidateName("github.work_2"))
+	for _, bad := range []string{"", "-flag", "with space", "a/b", `q"uote`} {
+		assert.Error(t, ValidateName(bad), bad)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	ctx := context.Background()
+	backend := memoryBackend{"github": "ghp_secret"}
+
+	got, err := Resolve(ctx, backend, "ghp_plain")
+	require.NoError(t, err)
+	assert.Equal(t, "ghp_plain", got, "plain values are returned unchanged")
+
+	got, err = Resolve(ctx, backend, "secret:github")
+	require.NoError(t, err)
+	assert.Equal(t, "ghp_secret", got)
+
+	_, err = Resolve(ctx, backend, "secret:missing")
+	assert.ErrorContains(t, err, `failed to read secret "missing" from memory`)
+
+	_, err = Resolve(ctx, nil, "secret:github")
+	assert.ErrorIs(t, err, ErrUnavailable)
+}
+
+func TestKeychainKeepsSecretOutOfArguments(t *testing.T) {
+	calls := fakeRunner(t, "", "ghp_secret")
+	k := keychain{path: "security"}
+
+	require.NoError(t, k.Set(context.Background(), "github", "ghp_secret"))
+	got, err := k.Get(context.Background(), "github")
+	require.NoError(t, err)
+	assert.Equal(t, "ghp_secret", got)
+
+	require.Len(t, *calls, 2)
+	assert.Equal(t, []string{"security", "-i"}, (*calls)[0].args)
+	assert.Equal(t, "add-generic-password -U -s lazyworktree -a github -w \"ghp_secret\"\n", (*calls)[0].stdin)
+	assert.Equal(t, []string{"security", "find-generic-password", "-s", "lazyworktree", "-a", "github", "-w"}, (*calls)[1].args)
+
+	assert.Error(t, k.Set(context.Background(), "github", `bad"value`))
+}
+
+func TestSecretService(t *testing.T) {
+	calls := fakeRunner(t, "", "glpat_secret")
+	s := secretService{path: "secret-tool"}
+
+	require.NoError(t, s.Set(context.Background(), "gitlab", "glpat_secret"))
+	got, err := s.Get(context.Background(), "gitlab")
+	require.NoError(t, err)
+	assert.Equal(t, "glpat_secret", got)
+
+	assert.Equal(t, "glpat_secret", (*calls)[0].stdin)
+	assert.Equal(t, "store --label=lazyworktree gitlab service lazyworktree account gitlab", strings.Join((*calls)[0].args[1:], " "))
+	assert.Equal(t, "lookup service lazyworktree account gitlab", strings.Join((*calls)[1].args[1:], " "))
+}
+
+func TestKernelKeyring(t *testing.T) {
+	calls := fakeRunner(t, "", "12345\n", "ghp_secret")
+	k := kernelKeyring{path: "keyctl"}
+
+	require.NoError(t, k.Set(context.Background(), "github", "ghp_secret"))
+	got, err := k.Get(context.Background(), "github")
+	require.NoError(t, err)
+	assert.Equal(t, "ghp_secret", got)
+
+	assert.Equal(t, []string{"keyctl", "padd", "user", "lazyworktree:github", "@u"}, (*calls)[0].args)
+	assert.Equal(t, []string{"keyctl", "search", "@u", "user", "lazyworktree:github"}, (*calls)[1].args)
+	assert.Equal(t, []string{"keyctl", "pipe", "12345"}, (*calls)[2].args)
+}
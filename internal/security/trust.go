@@ -25,6 +25,11 @@ const (
 	TrustStatusNotFound
 )
 
+// TrustDBPath returns the location of the trust database.
+func TrustDBPath() string {
+	return getTrustDBPath()
+}
+
 func getTrustDBPath() string {
 	if xdgDataHome := os.Getenv("XDG_DATA_HOME"); xdgDataHome != "" {
 		return filepath.Join(xdgDataHome, "lazyworktree", "trusted.json")
@@ -0,0 +1,229 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chmouel/lazyworktree/internal/config"
+)
+
+// Listener is a process listening on a TCP port.
+type Listener struct {
+	Port    int
+	PID     int
+	Process string // Command name
+	Dir     string // Working directory, when it can be read
+}
+
+// ssUsers matches the processes column of ss -p, such as
+// users:(("node",pid=123,fd=20)).
+var ssUsers = regexp.MustCompile(`\("([^"]*)",pid=(\d+)`)
+
+// Listeners returns the processes listening on a port within ranges, sorted
+// by port. It asks lsof, or ss on Linux, and returns an error when neither
+// is available.
+func Listeners(ctx context.Context, ranges []config.PortRange) ([]Listener, error) {
+	if len(ranges) == 0 {
+		return nil, nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var listeners []Listener
+	switch {
+	case hasCommand("lsof"):
+		args := []string{"-nP", "-sTCP:LISTEN", "-Fpcn"}
+		for _, r := range ranges {
+			args = append(args, fmt.Sprintf("-iTCP:%d-%d", r.First, r.Last))
+		}
+		// #nosec G204 -- the arguments are port numbers and fixed flags
+		out, err := exec.CommandContext(ctx, "lsof", args...).Output()
+		if err != nil && len(out) == 0 {
+			// lsof exits with 1 when nothing listens.
+			return nil, nil
+		}
+		listeners = parseLsofListeners(string(out))
+		lsofDirs(ctx, listeners)
+	case runtime.GOOS == "linux" && hasCommand("ss"):
+		out, err := exec.CommandContext(ctx, "ss", "-Hltnp").Output()
+		if err != nil {
+			return nil, fmt.Errorf("ss: %w", err)
+		}
+		listeners = parseSSListeners(string(out))
+		for i := range listeners {
+			listeners[i].Dir, _ = os.Readlink(fmt.Sprintf("/proc/%d/cwd", listeners[i].PID))
+		}
+	default:
+		return nil, fmt.Errorf("finding the processes on a port needs lsof or ss")
+	}
+	return inRanges(listeners, ranges), nil
+}
+
+// hasCommand reports whether name is on the PATH.
+func hasCommand(name string) bool {
+	_, err := exec.LookPath(name)
+	return err == nil
+}
+
+// parseLsofListeners reads lsof -Fpcn output: a p line per process, then
+// its command and the addresses it listens on.
+func parseLsofListeners(out string) []Listener {
+	var listeners []Listener
+	seen := map[[2]int]bool{}
+	pid, process := 0, ""
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			continue
+		}
+		switch line[0] {
+		case 'p':
+			pid, _ = strconv.Atoi(line[1:])
+			process = ""
+		case 'c':
+			process = line[1:]
+		case 'n':
+			port := addressPort(line[1:])
+			if pid > 0 && port > 0 && !seen[[2]int{port, pid}] {
+				seen[[2]int{port, pid}] = true
+				listeners = append(listeners, Listener{Port: port, PID: pid, Process: process})
+			}
+		}
+	}
+	return listeners
+}
+
+// lsofDirs fills in the working directory of each listener.
+func lsofDirs(ctx context.Context, listeners []Listener) {
+	if len(listeners) == 0 {
+		return
+	}
+	pids := make([]string, 0, len(listeners))
+	for _, l := range listeners {
+		pids = append(pids, strconv.Itoa(l.PID))
+	}
+	// #nosec G204 -- the arguments are process IDs and fixed flags
+	out, _ := exec.CommandContext(ctx, "lsof", "-a", "-d", "cwd", "-p", strings.Join(pids, ","), "-Fpn").Output()
+	dirs := map[int]string{}
+	pid := 0
+	scanner := bufio.NewScanner(strings.NewReader(string(out)))
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "p"):
+			pid, _ = strconv.Atoi(line[1:])
+		case strings.HasPrefix(line, "n"):
+			dirs[pid] = line[1:]
+		}
+	}
+	for i := range listeners {
+		listeners[i].Dir = dirs[listeners[i].PID]
+	}
+}
+
+// parseSSListeners reads ss -Hltnp output, whose fourth column is the local
+// address and last lists the processes.
+func parseSSListeners(out string) []Listener {
+	var listeners []Listener
+	seen := map[[2]int]bool{}
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 4 {
+			continue
+		}
+		port := addressPort(fields[3])
+		if port == 0 {
+			continue
+		}
+		for _, match := range ssUsers.FindAllStringSubmatch(scanner.Text(), -1) {
+			pid, _ := strconv.Atoi(match[2])
+			if pid > 0 && !seen[[2]int{port, pid}] {
+				seen[[2]int{port, pid}] = true
+				listeners = append(listeners, Listener{Port: port, PID: pid, Process: match[1]})
+			}
+		}
+	}
+	return listeners
+}
+
+// addressPort returns the port of an address such as *:3000 or [::1]:5173.
+func addressPort(addr string) int {
+	colon := strings.LastIndexByte(addr, ':')
+	if colon < 0 {
+		return 0
+	}
+	port, err := strconv.Atoi(addr[colon+1:])
+	if err != nil || port <= 0 {
+		return 0
+	}
+	return port
+}
+
+// inRanges keeps the listeners on a port within ranges, sorted by port.
+func inRanges(listeners []Listener, ranges []config.PortRange) []Listener {
+	var kept []Listener
+	for _, l := range listeners {
+		for _, r := range ranges {
+			if l.Port >= r.First && l.Port <= r.Last {
+				kept = append(kept, l)
+				break
+			}
+		}
+	}
+	sort.SliceStable(kept, func(i, j int) bool {
+		if kept[i].Port != kept[j].Port {
+			return kept[i].Port < kept[j].Port
+		}
+		return kept[i].PID < kept[j].PID
+	})
+	return kept
+}
+
+// Owner returns the worktree among worktrees the listener runs in: the
+// one whose service holds its port, or else the deepest one containing its
+// working directory. It is empty for a process outside every worktree.
+func (l Listener) Owner(worktrees []string, running List, ports map[int][]int) string {
+	for _, p := range running {
+		if p.PID == l.PID {
+			return p.Worktree
+		}
+		for _, port := range ports[p.PID] {
+			if port == l.Port {
+				return p.Worktree
+			}
+		}
+	}
+	if l.Dir == "" {
+		return ""
+	}
+	owner := ""
+	dir := filepath.Clean(l.Dir)
+	for _, wt := range worktrees {
+		clean := filepath.Clean(wt)
+		if len(clean) > len(owner) && (dir == clean || strings.HasPrefix(dir, clean+string(filepath.Separator))) {
+			owner = wt
+		}
+	}
+	return owner
+}
+
+// Kill stops the process with the given ID, killing it if it does not exit
+// within a few seconds.
+func Kill(pid int) error {
+	if pid <= 0 {
+		return fmt.Errorf("invalid process ID %d", pid)
+	}
+	return terminateProcess(pid, stopTimeout)
+}
@@ -0,0 +1,52 @@
+//go:build !windows
+
+package services
+
+import (
+	"errors"
+	"os/exec"
+	"syscall"
+	"time"
+
+	"github.com/chmouel/lazyworktree/internal/utils"
+)
+
+// detach starts the service in a session of its own, so it survives the
+// terminal lazyworktree ran in and can be stopped with its children.
+func detach(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+}
+
+// terminate sends SIGTERM to the process group led by pid, then SIGKILL if
+// it is still running after timeout.
+func terminate(pid int, timeout time.Duration) error {
+	return signalUntilExit(-pid, pid, timeout)
+}
+
+// terminateProcess sends SIGTERM to the single process pid, then SIGKILL if
+// it is still running after timeout.
+func terminateProcess(pid int, timeout time.Duration) error {
+	return signalUntilExit(pid, pid, timeout)
+}
+
+// signalUntilExit sends SIGTERM to target, a process or a negated process
+// group, and SIGKILL once timeout passes with pid still running.
+func signalUntilExit(target, pid int, timeout time.Duration) error {
+	if err := syscall.Kill(target, syscall.SIGTERM); err != nil {
+		if errors.Is(err, syscall.ESRCH) {
+			return nil
+		}
+		return err
+	}
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if !utils.ProcessAlive(pid) {
+			return nil
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	if err := syscall.Kill(target, syscall.SIGKILL); err != nil && !errors.Is(err, syscall.ESRCH) {
+		return err
+	}
+	return nil
+}
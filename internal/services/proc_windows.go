@@ -0,0 +1,33 @@
+//go:build windows
+
+package services
+
+import (
+	"os/exec"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/chmouel/lazyworktree/internal/utils"
+)
+
+// detach starts the service in a process group of its own, so it can be
+// stopped with its children.
+func detach(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{CreationFlags: syscall.CREATE_NEW_PROCESS_GROUP}
+}
+
+// terminate ends the process tree rooted at pid; Windows has no gentler
+// signal for a console-less process.
+func terminate(pid int, _ time.Duration) error {
+	if !utils.ProcessAlive(pid) {
+		return nil
+	}
+	// #nosec G204 -- the argument is a process ID
+	return exec.Command("taskkill", "/T", "/F", "/PID", strconv.Itoa(pid)).Run()
+}
+
+// terminateProcess ends the process pid and those it started.
+func terminateProcess(pid int, timeout time.Duration) error {
+	return terminate(pid, timeout)
+}
@@ -0,0 +1,214 @@
+// Package services starts, tracks and stops the long-running development
+// services, such as a dev server, a repository declares for its worktrees.
+// Services outlive lazyworktree, so their process IDs are kept in the cache
+// directory.
+package services
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chmouel/lazyworktree/internal/models"
+	"github.com/chmouel/lazyworktree/internal/utils"
+)
+
+// stopTimeout is how long a service has to exit once asked before it is
+// killed.
+const stopTimeout = 5 * time.Second
+
+// Process is a running service.
+type Process struct {
+	Worktree  string    `json:"worktree"`
+	Name      string    `json:"name"`
+	Command   string    `json:"command"`
+	PID       int       `json:"pid"`
+	Log       string    `json:"log"`
+	StartedAt time.Time `json:"started_at"`
+}
+
+// List holds the services started from lazyworktree.
+type List []Process
+
+// mu serialises the read-modify-write of Start and Stop.
+var mu sync.Mutex
+
+// Path returns where the services of the repository named repoKey are
+// listed.
+func Path(repoKey string) string {
+	return filepath.Join(utils.CacheDir(), repoKey, models.ServicesFilename)
+}
+
+// LogPath returns the file a service of the worktree at worktree writes
+// its output to, next to the list at path.
+func LogPath(path, worktree, name string) string {
+	return filepath.Join(filepath.Dir(path), "services", fmt.Sprintf("%s-%s.log", filepath.Base(worktree), name))
+}
+
+// Load reads the list at path, leaving out the services that have exited.
+// A missing or damaged file yields an empty list.
+func Load(path string) List {
+	var list List
+	// #nosec G304 -- path is derived from the cache directory
+	if data, err := os.ReadFile(path); err == nil {
+		_ = json.Unmarshal(data, &list)
+	}
+	return slices.DeleteFunc(list, func(p Process) bool { return !utils.ProcessAlive(p.PID) })
+}
+
+// Save writes the list to path.
+func (l List) Save(path string) error {
+	if l == nil {
+		l = List{}
+	}
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+// Of returns the services running in the worktree at worktree.
+func (l List) Of(worktree string) List {
+	var of List
+	for _, p := range l {
+		if p.Worktree == worktree {
+			of = append(of, p)
+		}
+	}
+	return of
+}
+
+// Find returns the service called name running in the worktree.
+func (l List) Find(worktree, name string) (Process, bool) {
+	for _, p := range l {
+		if p.Worktree == worktree && p.Name == name {
+			return p, true
+		}
+	}
+	return Process{}, false
+}
+
+// Start runs command through bash in the worktree, detached so that it
+// outlives lazyworktree, appending its output to its log, and records it in
+// the list at path.
+func Start(path, worktree, name, command string, env map[string]string) (Process, error) {
+	mu.Lock()
+	defer mu.Unlock()
+	list := Load(path)
+	if p, ok := list.Find(worktree, name); ok {
+		return p, fmt.Errorf("%s is already running (pid %d)", name, p.PID)
+	}
+
+	logPath := LogPath(path, worktree, name)
+	if err := os.MkdirAll(filepath.Dir(logPath), 0o750); err != nil {
+		return Process{}, err
+	}
+	// #nosec G304 -- logPath is derived from the cache directory
+	logFile, err := os.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return Process{}, err
+	}
+	defer func() { _ = logFile.Close() }()
+
+	// #nosec G204 -- services are defined in the trusted repository config and run through bash intentionally
+	cmd := exec.Command("bash", "-lc", command)
+	cmd.Dir = worktree
+	cmd.Env = os.Environ()
+	for k, v := range env {
+		cmd.Env = append(cmd.Env, k+"="+v)
+	}
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	detach(cmd)
+	if err := cmd.Start(); err != nil {
+		return Process{}, err
+	}
+	// Reap the service should it exit while lazyworktree runs.
+	go func() { _ = cmd.Wait() }()
+
+	p := Process{Worktree: worktree, Name: name, Command: command, PID: cmd.Process.Pid, Log: logPath, StartedAt: time.Now()}
+	return p, append(list, p).Save(path)
+}
+
+// Stop asks the service called name in the worktree, and the processes it
+// started, to exit, killing them after a few seconds, and drops it from the
+// list at path.
+func Stop(path, worktree, name string) error {
+	mu.Lock()
+	defer mu.Unlock()
+	list := Load(path)
+	p, ok := list.Find(worktree, name)
+	if !ok {
+		return nil
+	}
+	if err := terminate(p.PID, stopTimeout); err != nil {
+		return fmt.Errorf("stopping %s: %w", name, err)
+	}
+	return slices.DeleteFunc(list, func(q Process) bool { return q.PID == p.PID }).Save(path)
+}
+
+// StopAll stops every service of the worktree, as it is deleted.
+func StopAll(path, worktree string) error {
+	var errs []string
+	for _, p := range Load(path).Of(worktree) {
+		if err := Stop(path, worktree, p.Name); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// Ports returns the TCP ports the service, or a process it started, listens
+// on. It relies on lsof and finds none without it.
+func Ports(ctx context.Context, pid int) []int {
+	if _, err := exec.LookPath("lsof"); err != nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+	// #nosec G204 -- the arguments are a process group ID and fixed flags
+	out, err := exec.CommandContext(ctx, "lsof", "-nP", "-a", "-g", strconv.Itoa(pid), "-iTCP", "-sTCP:LISTEN", "-Fn").Output()
+	if err != nil {
+		return nil
+	}
+	return parseLsofPorts(string(out))
+}
+
+// parseLsofPorts reads the ports of lsof -Fn output, whose name lines look
+// like n*:3000 or n[::1]:5173.
+func parseLsofPorts(out string) []int {
+	seen := map[int]bool{}
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "n") {
+			continue
+		}
+		if port := addressPort(line[1:]); port > 0 {
+			seen[port] = true
+		}
+	}
+	ports := make([]int, 0, len(seen))
+	for port := range seen {
+		ports = append(ports, port)
+	}
+	sort.Ints(ports)
+	return ports
+}
@@ -0,0 +1,105 @@
+package services
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chmouel/lazyworktree/internal/config"
+	"github.com/chmouel/lazyworktree/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStartAndStop(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a POSIX shell")
+	}
+	path := filepath.Join(t.TempDir(), "repo", ".services.json")
+	worktree := t.TempDir()
+
+	p, err := Start(path, worktree, "web", `echo "serving $LAZYWORKTREE_SERVICE from $PWD"; exec sleep 60`, map[string]string{"LAZYWORKTREE_SERVICE": "web"})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = StopAll(path, worktree) })
+	assert.Positive(t, p.PID)
+
+	_, err = Start(path, worktree, "web", "sleep 60", nil)
+	require.Error(t, err, "a running service is not started twice")
+
+	list := Load(path)
+	require.Len(t, list.Of(worktree), 1)
+	assert.Empty(t, list.Of("/elsewhere"))
+
+	require.Eventually(t, func() bool {
+		data, _ := os.ReadFile(p.Log)
+		return strings.Contains(string(data), "serving web from "+worktree)
+	}, 5*time.Second, 50*time.Millisecond)
+
+	require.NoError(t, StopAll(path, worktree))
+	assert.Empty(t, Load(path))
+	assert.False(t, utils.ProcessAlive(p.PID))
+}
+
+func TestLoadDropsExitedServices(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".services.json")
+	require.NoError(t, List{{Worktree: "/wt", Name: "gone", PID: 0}}.Save(path))
+	assert.Empty(t, Load(path))
+}
+
+func TestParseLsofPorts(t *testing.T) {
+	out := "p123\ng123\nf20\nn*:3000\nf21\nn[::1]:5173\nf22\nn127.0.0.1:3000\n"
+	assert.Equal(t, []int{3000, 5173}, parseLsofPorts(out))
+	assert.Empty(t, parseLsofPorts(""))
+}
+
+func TestParseListeners(t *testing.T) {
+	lsof := "p123\ncnode\nf20\nn*:3000\nf21\nn[::1]:3000\np456\ncvite\nn127.0.0.1:5173\n"
+	assert.Equal(t, []Listener{
+		{Port: 3000, PID: 123, Process: "node"},
+		{Port: 5173, PID: 456, Process: "vite"},
+	}, parseLsofListeners(lsof))
+
+	ss := `LISTEN 0 511 *:3000 *:* users:(("node",pid=123,fd=20))
+LISTEN 0 128 0.0.0.0:22 0.0.0.0:*
+LISTEN 0 511 [::1]:5173 [::]:* users:(("vite",pid=456,fd=3),("vite",pid=457,fd=3))
+`
+	assert.Equal(t, []Listener{
+		{Port: 3000, PID: 123, Process: "node"},
+		{Port: 5173, PID: 456, Process: "vite"},
+		{Port: 5173, PID: 457, Process: "vite"},
+	}, parseSSListeners(ss))
+
+	kept := inRanges(parseSSListeners(ss), []config.PortRange{{First: 5000, Last: 6000}, {First: 3000, Last: 3000}})
+	require.Len(t, kept, 3)
+	assert.Equal(t, 3000, kept[0].Port)
+	assert.Empty(t, inRanges(parseSSListeners(ss), []config.PortRange{{First: 8000, Last: 8080}}))
+}
+
+func TestListenerOwner(t *testing.T) {
+	worktrees := []string{"/repo", "/repo/.worktrees/feature", "/elsewhere/fix"}
+	running := List{{Worktree: "/elsewhere/fix", Name: "web", PID: 42}}
+	ports := map[int][]int{42: {4000}}
+
+	assert.Equal(t, "/repo/.worktrees/feature", Listener{Port: 3000, Dir: "/repo/.worktrees/feature/web"}.Owner(worktrees, running, ports))
+	assert.Equal(t, "/repo", Listener{Port: 3000, Dir: "/repo"}.Owner(worktrees, running, ports))
+	assert.Equal(t, "/elsewhere/fix", Listener{Port: 4000, PID: 99, Dir: "/tmp"}.Owner(worktrees, running, ports), "a service's port belongs to its worktree")
+	assert.Empty(t, Listener{Port: 3000, Dir: "/repository"}.Owner(worktrees, running, ports))
+	assert.Empty(t, Listener{Port: 3000}.Owner(worktrees, running, ports))
+}
+
+func TestKill(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sleep")
+	}
+	cmd := exec.Command("sleep", "60")
+	require.NoError(t, cmd.Start())
+	go func() { _ = cmd.Wait() }()
+
+	require.NoError(t, Kill(cmd.Process.Pid))
+	assert.Eventually(t, func() bool { return !utils.ProcessAlive(cmd.Process.Pid) }, 5*time.Second, 50*time.Millisecond)
+	assert.Error(t, Kill(0))
+}
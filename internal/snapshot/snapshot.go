@@ -0,0 +1,356 @@
+// Package snapshot records the full state of a worktree — its HEAD, staged
+// and unstaged changes and untracked files — so it can be brought back
+// after a risky rebase or reset. Unlike a stash, a snapshot leaves the
+// worktree untouched and keeps the index and working tree apart.
+package snapshot
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	metaFile     = "snapshot.json"
+	stagedFile   = "staged.patch"
+	unstagedFile = "unstaged.patch"
+	untrackedDir = "untracked"
+
+	// RefPrefix keeps each snapshot's HEAD reachable, so a rebase followed
+	// by git gc cannot drop it.
+	RefPrefix = "refs/lazyworktree/snapshots/"
+)
+
+// diffArgs keep patches plain whatever the user's diff.external, color.diff,
+// diff.noprefix or diff.mnemonicPrefix say, so git apply can read them.
+var diffArgs = []string{"diff", "--binary", "--no-color", "--no-ext-diff", "--src-prefix=a/", "--dst-prefix=b/"}
+
+// Snapshot describes a recorded worktree state.
+type Snapshot struct {
+	ID        string    `json:"id"`
+	Label     string    `json:"label"`
+	Created   time.Time `json:"created"`
+	Worktree  string    `json:"worktree"`
+	Branch    string    `json:"branch,omitempty"`
+	Head      string    `json:"head"`
+	Staged    bool      `json:"staged,omitempty"`
+	Unstaged  bool      `json:"unstaged,omitempty"`
+	Untracked []string  `json:"untracked,omitempty"`
+}
+
+// Summary describes the snapshot's content, e.g. "staged, unstaged, 2
+// untracked".
+func (s Snapshot) Summary() string {
+	var parts []string
+	if s.Staged {
+		parts = append(parts, "staged")
+	}
+	if s.Unstaged {
+		parts = append(parts, "unstaged")
+	}
+	if n := len(s.Untracked); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d untracked", n))
+	}
+	if len(parts) == 0 {
+		return "clean"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Store keeps snapshots as directories under Dir.
+type Store struct {
+	Dir string
+	// Git runs git in dir and returns its stdout; tests replace it.
+	Git func(ctx context.Context, dir string, args ...string) (string, error)
+	now func() time.Time
+}
+
+// NewStore returns a store keeping snapshots under dir.
+func NewStore(dir string) *Store {
+	return &Store{Dir: dir, Git: runGit, now: time.Now}
+}
+
+// Create records the state of the worktree at path under label.
+func (s *Store) Create(ctx context.Context, path, label string) (*Snapshot, error) {
+	head, err := s.Git(ctx, path, "rev-parse", "--verify", "HEAD")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read HEAD: %w", err)
+	}
+	created := s.now()
+	snap := &Snapshot{
+		ID:       strconv.FormatInt(created.UnixNano(), 36),
+		Label:    strings.TrimSpace(label),
+		Created:  created,
+		Worktree: path,
+		Head:     strings.TrimSpace(head),
+	}
+	if branch, err := s.Git(ctx, path, "symbolic-ref", "--quiet", "--short", "HEAD"); err == nil {
+		snap.Branch = strings.TrimSpace(branch)
+	}
+
+	dir := filepath.Join(s.Dir, snap.ID)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	ok := false
+	defer func() {
+		if !ok {
+			_ = os.RemoveAll(dir)
+		}
+	}()
+
+	staged, err := s.Git(ctx, path, append(diffArgs, "--cached")...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read staged changes: %w", err)
+	}
+	unstaged, err := s.Git(ctx, path, diffArgs...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read unstaged changes: %w", err)
+	}
+	snap.Staged, snap.Unstaged = staged != "", unstaged != ""
+	if err := os.WriteFile(filepath.Join(dir, stagedFile), []byte(staged), 0o600); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(dir, unstagedFile), []byte(unstaged), 0o600); err != nil {
+		return nil, err
+	}
+
+	untracked, err := s.Git(ctx, path, "ls-files", "--others", "--exclude-standard", "-z")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list untracked files: %w", err)
+	}
+	for rel := range strings.SplitSeq(untracked, "\x00") {
+		if rel == "" {
+			continue
+		}
+		if err := copyFile(filepath.Join(path, rel), filepath.Join(dir, untrackedDir, rel)); err != nil {
+			return nil, fmt.Errorf("failed to copy %s: %w", rel, err)
+		}
+		snap.Untracked = append(snap.Untracked, rel)
+	}
+
+	if _, err := s.Git(ctx, path, "update-ref", RefPrefix+snap.ID, snap.Head); err != nil {
+		return nil, fmt.Errorf("failed to pin HEAD: %w", err)
+	}
+	// The metadata is written last: a directory without it is incomplete.
+	data, err := json.MarshalIndent(snap, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(dir, metaFile), data, 0o600); err != nil {
+		return nil, err
+	}
+	ok = true
+	return snap, nil
+}
+
+// List returns the snapshots taken of the worktree at path, newest first,
+// or every snapshot when path is empty.
+func (s *Store) List(path string) ([]*Snapshot, error) {
+	entries, err := os.ReadDir(s.Dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var snaps []*Snapshot
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		snap, err := s.load(entry.Name())
+		if err != nil {
+			continue
+		}
+		if path == "" || snap.Worktree == path {
+			snaps = append(snaps, snap)
+		}
+	}
+	sort.Slice(snaps, func(i, j int) bool { return snaps[i].Created.After(snaps[j].Created) })
+	return snaps, nil
+}
+
+func (s *Store) load(id string) (*Snapshot, error) {
+	// #nosec G304 -- id names a directory under the store
+	data, err := os.ReadFile(filepath.Join(s.Dir, id, metaFile))
+	if err != nil {
+		return nil, err
+	}
+	var snap Snapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return nil, err
+	}
+	snap.ID = id
+	return &snap, nil
+}
+
+// Restore brings the worktree at path back to snap. The current state is
+// snapshotted first, labelled after snap, and returned so nothing is lost.
+func (s *Store) Restore(ctx context.Context, path string, snap *Snapshot) (*Snapshot, error) {
+	if snap.Branch != "" {
+		branch, _ := s.Git(ctx, path, "symbolic-ref", "--quiet", "--short", "HEAD")
+		if branch = strings.TrimSpace(branch); branch != snap.Branch {
+			return nil, fmt.Errorf("the snapshot was taken on %s but the worktree is on %s; switch back to %s first", snap.Branch, displayBranch(branch), snap.Branch)
+		}
+	}
+	dir := filepath.Join(s.Dir, snap.ID)
+	if err := s.checkPatches(ctx, path, dir, snap); err != nil {
+		return nil, err
+	}
+	backup, err := s.Create(ctx, path, fmt.Sprintf("before restoring %q", snap.Label))
+	if err != nil {
+		return nil, fmt.Errorf("failed to snapshot the current state: %w", err)
+	}
+
+	if _, err := s.Git(ctx, path, "reset", "--hard", snap.Head); err != nil {
+		return backup, fmt.Errorf("failed to reset to %s: %w", shortHash(snap.Head), err)
+	}
+	if _, err := s.Git(ctx, path, "clean", "-fd"); err != nil {
+		return backup, fmt.Errorf("failed to remove untracked files: %w", err)
+	}
+	if snap.Staged {
+		if _, err := s.Git(ctx, path, "apply", "--index", "--binary", filepath.Join(dir, stagedFile)); err != nil {
+			return backup, fmt.Errorf("failed to restore staged changes: %w", err)
+		}
+	}
+	if snap.Unstaged {
+		if _, err := s.Git(ctx, path, "apply", "--binary", filepath.Join(dir, unstagedFile)); err != nil {
+			return backup, fmt.Errorf("failed to restore unstaged changes: %w", err)
+		}
+	}
+	for _, rel := range snap.Untracked {
+		if err := copyFile(filepath.Join(dir, untrackedDir, rel), filepath.Join(path, rel)); err != nil {
+			return backup, fmt.Errorf("failed to restore %s: %w", rel, err)
+		}
+	}
+	return backup, nil
+}
+
+// checkPatches makes sure the staged and unstaged patches of snap apply on
+// top of its HEAD before Restore resets anything. They are applied to a
+// scratch index, leaving the worktree and its own index alone.
+func (s *Store) checkPatches(ctx context.Context, path, dir string, snap *Snapshot) error {
+	if !snap.Staged && !snap.Unstaged {
+		return nil
+	}
+	scratch, err := os.MkdirTemp("", "lazyworktree-snapshot-*")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.RemoveAll(scratch) }()
+	env := []string{"GIT_INDEX_FILE=" + filepath.Join(scratch, "index")}
+
+	if _, err := runGitEnv(ctx, path, env, "read-tree", snap.Head); err != nil {
+		return fmt.Errorf("failed to read %s: %w", shortHash(snap.Head), err)
+	}
+	if snap.Staged {
+		staged := filepath.Join(dir, stagedFile)
+		if _, err := runGitEnv(ctx, path, env, "apply", "--cached", "--check", "--binary", staged); err != nil {
+			return fmt.Errorf("the staged changes of the snapshot no longer apply: %w", err)
+		}
+		// The unstaged patch is relative to the staged state.
+		if _, err := runGitEnv(ctx, path, env, "apply", "--cached", "--binary", staged); err != nil {
+			return fmt.Errorf("the staged changes of the snapshot no longer apply: %w", err)
+		}
+	}
+	if snap.Unstaged {
+		if _, err := runGitEnv(ctx, path, env, "apply", "--cached", "--check", "--binary", filepath.Join(dir, unstagedFile)); err != nil {
+			return fmt.Errorf("the unstaged changes of the snapshot no longer apply: %w", err)
+		}
+	}
+	return nil
+}
+
+// Delete removes snap and the ref pinning its HEAD.
+func (s *Store) Delete(ctx context.Context, repoPath string, snap *Snapshot) error {
+	if _, err := s.Git(ctx, repoPath, "update-ref", "-d", RefPrefix+snap.ID); err != nil {
+		return fmt.Errorf("failed to drop %s: %w", RefPrefix+snap.ID, err)
+	}
+	return os.RemoveAll(filepath.Join(s.Dir, snap.ID))
+}
+
+func displayBranch(branch string) string {
+	if branch == "" {
+		return "a detached HEAD"
+	}
+	return branch
+}
+
+func shortHash(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
+
+// copyFile copies src to dst, keeping its mode, or recreates it when src
+// is a symbolic link.
+func copyFile(src, dst string) error {
+	info, err := os.Lstat(src)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0o700); err != nil {
+		return err
+	}
+	if info.Mode()&os.ModeSymlink != 0 {
+		target, err := os.Readlink(src)
+		if err != nil {
+			return err
+		}
+		_ = os.Remove(dst)
+		return os.Symlink(target, dst)
+	}
+	// #nosec G304 -- src is a file git listed in the worktree or the snapshot
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = in.Close() }()
+	// #nosec G304 -- dst mirrors src under the snapshot or the worktree
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// runGit runs git in dir and returns its stdout untrimmed, as patches are
+// read through it.
+func runGit(ctx context.Context, dir string, args ...string) (string, error) {
+	return runGitEnv(ctx, dir, nil, args...)
+}
+
+// runGitEnv is runGit with env added to the environment.
+func runGitEnv(ctx context.Context, dir string, env []string, args ...string) (string, error) {
+	// #nosec G204 -- the arguments are fixed in this package
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	if len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%w: %s", err, msg)
+		}
+		return "", err
+	}
+	return string(out), nil
+}
@@ -0,0 +1,208 @@
+package snapshot
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newRepo returns a repository with one commit of a.txt.
+func newRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "-q", "-b", "main"},
+		{"config", "user.email", "test@example.com"},
+		{"config", "user.name", "Test"},
+		{"config", "commit.gpgsign", "false"},
+	} {
+		git(t, dir, args...)
+	}
+	write(t, dir, "a.txt", "one\n")
+	git(t, dir, "add", "a.txt")
+	git(t, dir, "commit", "-q", "-m", "first")
+	return dir
+}
+
+func git(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	out, err := runGit(context.Background(), dir, args...)
+	if err != nil {
+		t.Fatalf("git %v: %v", args, err)
+	}
+	return strings.TrimSpace(out)
+}
+
+func write(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func read(t *testing.T, dir, name string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestCreateAndRestore(t *testing.T) {
+	ctx := context.Background()
+	repo := newRepo(t)
+	store := NewStore(t.TempDir())
+
+	write(t, repo, "a.txt", "staged\n")
+	git(t, repo, "add", "a.txt")
+	write(t, repo, "a.txt", "unstaged\n")
+	write(t, repo, "notes/todo.txt", "untracked\n")
+	head := git(t, repo, "rev-parse", "HEAD")
+
+	snap, err := store.Create(ctx, repo, "before rebase")
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if snap.Head != head || snap.Branch != "main" || !snap.Staged || !snap.Unstaged {
+		t.Fatalf("unexpected snapshot %+v", snap)
+	}
+	if got := snap.Summary(); got != "staged, unstaged, 1 untracked" {
+		t.Fatalf("unexpected summary %q", got)
+	}
+	if got := git(t, repo, "rev-parse", RefPrefix+snap.ID); got != head {
+		t.Fatalf("expected the snapshot ref to pin %s, got %s", head, got)
+	}
+
+	// Wreck the worktree: commit everything and drop the untracked file.
+	git(t, repo, "add", "-A")
+	git(t, repo, "commit", "-q", "-m", "oops")
+	write(t, repo, "b.txt", "stray\n")
+
+	store.now = func() time.Time { return snap.Created.Add(time.Second) }
+	backup, err := store.Restore(ctx, repo, snap)
+	if err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+	if got := git(t, repo, "rev-parse", "HEAD"); got != head {
+		t.Fatalf("expected HEAD %s, got %s", head, got)
+	}
+	if got := git(t, repo, "show", ":a.txt"); got != "staged" {
+		t.Fatalf("expected the staged content back in the index, got %q", got)
+	}
+	if got := read(t, repo, "a.txt"); got != "unstaged\n" {
+		t.Fatalf("expected the unstaged content back, got %q", got)
+	}
+	if got := read(t, repo, "notes/todo.txt"); got != "untracked\n" {
+		t.Fatalf("expected the untracked file back, got %q", got)
+	}
+	if _, err := os.Stat(filepath.Join(repo, "b.txt")); !os.IsNotExist(err) {
+		t.Fatalf("expected b.txt to be removed, got %v", err)
+	}
+	if backup == nil || len(backup.Untracked) != 1 || backup.Untracked[0] != "b.txt" {
+		t.Fatalf("expected the replaced state to be kept, got %+v", backup)
+	}
+
+	snaps, err := store.List(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(snaps) != 2 || snaps[0].ID != backup.ID {
+		t.Fatalf("expected the backup listed first, got %+v", snaps)
+	}
+	if err := store.Delete(ctx, repo, snap); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if snaps, _ := store.List(repo); len(snaps) != 1 {
+		t.Fatalf("expected one snapshot left, got %d", len(snaps))
+	}
+	if _, err := runGit(ctx, repo, "rev-parse", "--verify", "--quiet", RefPrefix+snap.ID); err == nil {
+		t.Fatal("expected the snapshot ref to be deleted")
+	}
+}
+
+func TestRestoreRefusesAnotherBranch(t *testing.T) {
+	ctx := context.Background()
+	repo := newRepo(t)
+	store := NewStore(t.TempDir())
+	snap, err := store.Create(ctx, repo, "clean")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snap.Summary() != "clean" {
+		t.Fatalf("expected a clean snapshot, got %q", snap.Summary())
+	}
+	git(t, repo, "switch", "-q", "-c", "other")
+	if _, err := store.Restore(ctx, repo, snap); err == nil || !strings.Contains(err.Error(), "taken on main") {
+		t.Fatalf("expected a branch mismatch error, got %v", err)
+	}
+}
+
+func TestSnapshotIgnoresDiffConfig(t *testing.T) {
+	ctx := context.Background()
+	repo := newRepo(t)
+	store := NewStore(t.TempDir())
+	for _, kv := range [][2]string{
+		{"diff.noprefix", "true"},
+		{"diff.mnemonicPrefix", "true"},
+		{"color.diff", "always"},
+		{"diff.external", "false"},
+	} {
+		git(t, repo, "config", kv[0], kv[1])
+	}
+
+	write(t, repo, "a.txt", "staged\n")
+	git(t, repo, "add", "a.txt")
+	write(t, repo, "a.txt", "unstaged\n")
+	snap, err := store.Create(ctx, repo, "plain patches")
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	git(t, repo, "checkout", "--", ".")
+	git(t, repo, "reset", "-q", "--hard")
+	store.now = func() time.Time { return snap.Created.Add(time.Second) }
+	if _, err := store.Restore(ctx, repo, snap); err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+	if got := git(t, repo, "show", ":a.txt"); got != "staged" {
+		t.Fatalf("expected the staged content back in the index, got %q", got)
+	}
+	if got := read(t, repo, "a.txt"); got != "unstaged\n" {
+		t.Fatalf("expected the unstaged content back, got %q", got)
+	}
+}
+
+func TestRestoreChecksPatchesBeforeReset(t *testing.T) {
+	ctx := context.Background()
+	repo := newRepo(t)
+	store := NewStore(t.TempDir())
+
+	write(t, repo, "a.txt", "snapshotted\n")
+	snap, err := store.Create(ctx, repo, "broken")
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	write(t, store.Dir, filepath.Join(snap.ID, unstagedFile), "not a patch\n")
+
+	write(t, repo, "a.txt", "current work\n")
+	if _, err := store.Restore(ctx, repo, snap); err == nil || !strings.Contains(err.Error(), "no longer apply") {
+		t.Fatalf("expected the broken patch to be refused, got %v", err)
+	}
+	if got := read(t, repo, "a.txt"); got != "current work\n" {
+		t.Fatalf("expected the worktree untouched, got %q", got)
+	}
+	if snaps, _ := store.List(repo); len(snaps) != 1 {
+		t.Fatalf("expected no backup snapshot for a refused restore, got %d", len(snaps))
+	}
+}
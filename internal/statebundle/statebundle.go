@@ -0,0 +1,267 @@
+// Package statebundle exports and imports lazyworktree's configuration and
+// per-repository state as a single tarball, for moving to another machine.
+package statebundle
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+const (
+	// FormatVersion is written to the manifest and checked on import.
+	FormatVersion = 1
+
+	manifestName   = "manifest.json"
+	configEntry    = "config/config.yaml"
+	trustEntry     = "data/trusted.json"
+	worktreePrefix = "worktrees/"
+
+	// maxEntryBytes caps each restored file; state files are small JSON.
+	maxEntryBytes = 64 << 20
+	// maxRepoDepth bounds how deep the worktree directory is searched for
+	// repository state, covering nested keys such as group/subgroup/repo.
+	maxRepoDepth = 4
+)
+
+// Paths locates the state on this machine.
+type Paths struct {
+	ConfigFile  string
+	TrustDB     string
+	WorktreeDir string
+}
+
+// Manifest describes a bundle.
+type Manifest struct {
+	Version int       `json:"version"`
+	Created time.Time `json:"created"`
+	Files   []string  `json:"files"`
+}
+
+// Result lists the archive entries handled by Import.
+type Result struct {
+	Written []string
+	Skipped []string
+}
+
+// file is a state file and its name inside the archive.
+type file struct {
+	entry string
+	path  string
+}
+
+// Export writes the configuration, the trust database and the state files of
+// every repository under the worktree directory to w as a gzipped tarball.
+// Missing files are left out. It returns the archive entries written.
+func Export(w io.Writer, p Paths, now time.Time) ([]string, error) {
+	files, err := collect(p)
+	if err != nil {
+		return nil, err
+	}
+	manifest := Manifest{Version: FormatVersion, Created: now.UTC()}
+	for _, f := range files {
+		manifest.Files = append(manifest.Files, f.entry)
+	}
+	manifestData, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	if err := writeEntry(tw, manifestName, manifestData, now); err != nil {
+		return nil, err
+	}
+	for _, f := range files {
+		// #nosec G304 -- paths come from the known state locations
+		data, err := os.ReadFile(f.path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", f.path, err)
+		}
+		if err := writeEntry(tw, f.entry, data, now); err != nil {
+			return nil, err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return manifest.Files, nil
+}
+
+func writeEntry(tw *tar.Writer, name string, data []byte, now time.Time) error {
+	header := &tar.Header{
+		Name:     name,
+		Mode:     0o600,
+		Size:     int64(len(data)),
+		ModTime:  now,
+		Typeflag: tar.TypeReg,
+	}
+	if err := tw.WriteHeader(header); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	if _, err := tw.Write(data); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
+
+// collect finds the state files that exist on this machine.
+func collect(p Paths) ([]file, error) {
+	var files []file
+	for _, f := range []file{{entry: configEntry, path: p.ConfigFile}, {entry: trustEntry, path: p.TrustDB}} {
+		if f.path == "" {
+			continue
+		}
+		if info, err := os.Stat(f.path); err == nil && info.Mode().IsRegular() {
+			files = append(files, f)
+		}
+	}
+	if p.WorktreeDir == "" {
+		return files, nil
+	}
+
+	root := filepath.Clean(p.WorktreeDir)
+	err := filepath.WalkDir(root, func(current string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if current == root && errors.Is(err, fs.ErrNotExist) {
+				return fs.SkipAll
+			}
+			return err
+		}
+		rel, err := filepath.Rel(root, current)
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if current == root {
+				return nil
+			}
+			// Worktree checkouts are git data, not lazyworktree state.
+			if d.Name() == models.InstancesDirname || strings.Count(rel, string(filepath.Separator)) >= maxRepoDepth {
+				return fs.SkipDir
+			}
+			if _, err := os.Lstat(filepath.Join(current, ".git")); err == nil {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if d.Type().IsRegular() && slices.Contains(models.StateFilenames, d.Name()) && filepath.Dir(rel) != "." {
+			files = append(files, file{entry: worktreePrefix + filepath.ToSlash(rel), path: current})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan %s: %w", root, err)
+	}
+	return files, nil
+}
+
+// Import restores a bundle written by Export. Files that already exist are
+// skipped unless overwrite is set. Entries outside the known locations are
+// rejected before anything is written.
+func Import(r io.Reader, p Paths, overwrite bool) (*Result, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("not a state bundle: %w", err)
+	}
+	defer func() { _ = gz.Close() }()
+
+	var manifest *Manifest
+	contents := map[string][]byte{}
+	var order []string
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read bundle: %w", err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			return nil, fmt.Errorf("unexpected entry %q in bundle", header.Name)
+		}
+		data, err := io.ReadAll(io.LimitReader(tr, maxEntryBytes+1))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", header.Name, err)
+		}
+		if len(data) > maxEntryBytes {
+			return nil, fmt.Errorf("%s is larger than %d bytes", header.Name, maxEntryBytes)
+		}
+		if header.Name == manifestName {
+			manifest = &Manifest{}
+			if err := json.Unmarshal(data, manifest); err != nil {
+				return nil, fmt.Errorf("invalid manifest: %w", err)
+			}
+			continue
+		}
+		if _, err := p.target(header.Name); err != nil {
+			return nil, err
+		}
+		contents[header.Name] = data
+		order = append(order, header.Name)
+	}
+	if manifest == nil {
+		return nil, errors.New("not a state bundle: the manifest is missing")
+	}
+	if manifest.Version > FormatVersion {
+		return nil, fmt.Errorf("bundle format %d is newer than this lazyworktree supports (%d)", manifest.Version, FormatVersion)
+	}
+
+	result := &Result{}
+	for _, entry := range order {
+		target, _ := p.target(entry)
+		if _, err := os.Stat(target); err == nil && !overwrite {
+			result.Skipped = append(result.Skipped, target)
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(target), 0o750); err != nil {
+			return result, fmt.Errorf("failed to create %s: %w", filepath.Dir(target), err)
+		}
+		if err := os.WriteFile(target, contents[entry], 0o600); err != nil {
+			return result, fmt.Errorf("failed to write %s: %w", target, err)
+		}
+		result.Written = append(result.Written, target)
+	}
+	return result, nil
+}
+
+// target maps an archive entry to where it is restored on this machine.
+func (p Paths) target(entry string) (string, error) {
+	switch entry {
+	case configEntry:
+		if p.ConfigFile == "" {
+			return "", errors.New("no configuration path to restore to")
+		}
+		return p.ConfigFile, nil
+	case trustEntry:
+		return p.TrustDB, nil
+	}
+
+	rel, ok := strings.CutPrefix(entry, worktreePrefix)
+	if !ok || rel == "" || path.IsAbs(rel) || path.Clean(rel) != rel || rel == ".." || strings.HasPrefix(rel, "../") {
+		return "", fmt.Errorf("refusing unexpected entry %q in bundle", entry)
+	}
+	if !slices.Contains(models.StateFilenames, path.Base(rel)) || path.Dir(rel) == "." {
+		return "", fmt.Errorf("refusing unexpected entry %q in bundle", entry)
+	}
+	if p.WorktreeDir == "" {
+		return "", errors.New("no worktree directory to restore to")
+	}
+	return filepath.Join(p.WorktreeDir, filepath.FromSlash(rel)), nil
+}
@@ -0,0 +1,129 @@
+package statebundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/chmouel/lazyworktree/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o750))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+}
+
+func testPaths(t *testing.T) Paths {
+	t.Helper()
+	root := t.TempDir()
+	return Paths{
+		ConfigFile:  filepath.Join(root, "config", "lazyworktree", "config.yaml"),
+		TrustDB:     filepath.Join(root, "data", "lazyworktree", "trusted.json"),
+		WorktreeDir: filepath.Join(root, "worktrees"),
+	}
+}
+
+func TestExportImportRoundTrip(t *testing.T) {
+	src := testPaths(t)
+	writeFile(t, src.ConfigFile, "theme: nord\n")
+	writeFile(t, src.TrustDB, `{"/repo/.wt":"abc"}`)
+	writeFile(t, filepath.Join(src.WorktreeDir, "owner", "repo", models.LastSelectedFilename), "/wt/feature")
+	writeFile(t, filepath.Join(src.WorktreeDir, "owner", "repo", models.AccessHistoryFilename), "{}")
+	writeFile(t, filepath.Join(src.WorktreeDir, "owner", "repo", models.InstancesDirname, "123"), "")
+	// Files inside a worktree checkout are never bundled.
+	writeFile(t, filepath.Join(src.WorktreeDir, "owner", "repo", "feature", ".git"), "gitdir: /repo/.git")
+	writeFile(t, filepath.Join(src.WorktreeDir, "owner", "repo", "feature", models.LastSelectedFilename), "ignored")
+	// Unknown files are left out too.
+	writeFile(t, filepath.Join(src.WorktreeDir, "owner", "repo", "notes.txt"), "ignored")
+
+	var buf bytes.Buffer
+	entries, err := Export(&buf, src, time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		configEntry,
+		trustEntry,
+		"worktrees/owner/repo/" + models.LastSelectedFilename,
+		"worktrees/owner/repo/" + models.AccessHistoryFilename,
+	}, entries)
+
+	dst := testPaths(t)
+	result, err := Import(bytes.NewReader(buf.Bytes()), dst, false)
+	require.NoError(t, err)
+	assert.Len(t, result.Written, 4)
+	assert.Empty(t, result.Skipped)
+
+	data, err := os.ReadFile(dst.ConfigFile)
+	require.NoError(t, err)
+	assert.Equal(t, "theme: nord\n", string(data))
+	data, err = os.ReadFile(filepath.Join(dst.WorktreeDir, "owner", "repo", models.LastSelectedFilename))
+	require.NoError(t, err)
+	assert.Equal(t, "/wt/feature", string(data))
+}
+
+func TestImportKeepsExistingFilesUnlessOverwriting(t *testing.T) {
+	src := testPaths(t)
+	writeFile(t, src.ConfigFile, "theme: nord\n")
+	var buf bytes.Buffer
+	_, err := Export(&buf, src, time.Now())
+	require.NoError(t, err)
+
+	dst := testPaths(t)
+	writeFile(t, dst.ConfigFile, "theme: dracula\n")
+	result, err := Import(bytes.NewReader(buf.Bytes()), dst, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{dst.ConfigFile}, result.Skipped)
+	data, _ := os.ReadFile(dst.ConfigFile)
+	assert.Equal(t, "theme: dracula\n", string(data))
+
+	result, err = Import(bytes.NewReader(buf.Bytes()), dst, true)
+	require.NoError(t, err)
+	assert.Equal(t, []string{dst.ConfigFile}, result.Written)
+	data, _ = os.ReadFile(dst.ConfigFile)
+	assert.Equal(t, "theme: nord\n", string(data))
+}
+
+func bundle(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0o600, Size: int64(len(content)), Typeflag: tar.TypeReg}))
+		_, err := tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+	return buf.Bytes()
+}
+
+func TestImportRejectsUnsafeBundles(t *testing.T) {
+	manifest := `{"version":1}`
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  string
+	}{
+		{name: "no manifest", files: map[string]string{configEntry: "x"}, want: "manifest is missing"},
+		{name: "newer format", files: map[string]string{manifestName: `{"version":99}`}, want: "newer"},
+		{name: "traversal", files: map[string]string{manifestName: manifest, "worktrees/../../.bashrc": "x"}, want: "refusing"},
+		{name: "absolute", files: map[string]string{manifestName: manifest, "/etc/passwd": "x"}, want: "refusing"},
+		{name: "unknown state file", files: map[string]string{manifestName: manifest, "worktrees/repo/run.sh": "x"}, want: "refusing"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dst := testPaths(t)
+			_, err := Import(bytes.NewReader(bundle(t, tt.files)), dst, true)
+			assert.ErrorContains(t, err, tt.want)
+			_, statErr := os.Stat(dst.ConfigFile)
+			assert.True(t, os.IsNotExist(statErr), "nothing is written from a rejected bundle")
+		})
+	}
+}
@@ -0,0 +1,237 @@
+// Package teamconfig fetches the .wt file a team publishes at an HTTPS URL or
+// on a git ref, such as refs/meta/lazyworktree, and verifies it before it is
+// merged under each member's own repository configuration.
+package teamconfig
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// DefaultFile is the file read from a ref when the source names none.
+	DefaultFile = ".wt"
+	// DefaultRemote is the remote refs are fetched from.
+	DefaultRemote = "origin"
+
+	requestTimeout = 30 * time.Second
+	// maxSize caps the team file; a .wt file is a few lines long.
+	maxSize = 1 << 20
+)
+
+// ErrHashMismatch is returned when the file does not match the expected
+// SHA-256.
+var ErrHashMismatch = errors.New("team configuration does not match team_config_sha256")
+
+// Source is where the team file is published: either URL or Ref is set.
+type Source struct {
+	URL  string
+	Ref  string
+	File string // Path of the file within Ref
+}
+
+// ParseSource parses a team_config value: an https:// URL, or a ref under
+// refs/ optionally followed by ":path" naming the file, which defaults to
+// .wt.
+func ParseSource(value string) (Source, error) {
+	value = strings.TrimSpace(value)
+	switch {
+	case value == "":
+		return Source{}, errors.New("team_config is not set")
+	case strings.HasPrefix(value, "https://"):
+		return Source{URL: value}, nil
+	case strings.HasPrefix(value, "http://"):
+		return Source{}, fmt.Errorf("team_config %q must use https", value)
+	case strings.HasPrefix(value, "refs/"):
+		ref, file, _ := strings.Cut(value, ":")
+		if file == "" {
+			file = DefaultFile
+		}
+		if strings.ContainsAny(ref, " ~^?*[\\") || strings.Contains(ref, "..") {
+			return Source{}, fmt.Errorf("team_config %q is not a valid ref", value)
+		}
+		return Source{Ref: ref, File: file}, nil
+	default:
+		return Source{}, fmt.Errorf("team_config %q must be an https:// URL or a ref such as refs/meta/lazyworktree", value)
+	}
+}
+
+// String returns the source as written in the configuration.
+func (s Source) String() string {
+	if s.URL != "" {
+		return s.URL
+	}
+	return s.Ref + ":" + s.File
+}
+
+// Options controls how the file is verified.
+type Options struct {
+	// SHA256 is the expected hex digest of the file; empty skips the check.
+	SHA256 string
+	// VerifySignature requires the ref's commit to carry a signature git
+	// accepts, as checked by git verify-commit. It applies to refs only.
+	VerifySignature bool
+}
+
+// Result is a fetched team file.
+type Result struct {
+	Data []byte
+	// Verified names the checks the file passed, e.g. "SHA-256"; empty when
+	// nothing vouches for it.
+	Verified []string
+}
+
+// Fetcher downloads team files.
+type Fetcher struct {
+	Client *http.Client
+	// Dir is the repository refs are fetched into.
+	Dir string
+	// Git runs git in Dir and returns its stdout; tests replace it.
+	Git func(ctx context.Context, dir string, args ...string) (string, error)
+}
+
+// NewFetcher returns a fetcher working in the repository at dir.
+func NewFetcher(dir string) *Fetcher {
+	return &Fetcher{
+		Client: &http.Client{Timeout: requestTimeout},
+		Dir:    dir,
+		Git:    runGit,
+	}
+}
+
+// Fetch retrieves the team file from src and verifies it according to opts.
+func (f *Fetcher) Fetch(ctx context.Context, src Source, opts Options) (*Result, error) {
+	if opts.VerifySignature && src.Ref == "" {
+		return nil, errors.New("team_config_verify_signature needs a ref source; pin URLs with team_config_sha256")
+	}
+	result := &Result{}
+	var err error
+	if src.URL != "" {
+		result.Data, err = f.download(ctx, src.URL)
+	} else {
+		result.Data, err = f.readRef(ctx, src, opts.VerifySignature)
+		if err == nil && opts.VerifySignature {
+			result.Verified = append(result.Verified, "signed commit")
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if opts.SHA256 != "" {
+		if err := VerifySHA256(result.Data, opts.SHA256); err != nil {
+			return nil, err
+		}
+		result.Verified = append(result.Verified, "SHA-256")
+	}
+	var raw map[string]any
+	if err := yaml.Unmarshal(result.Data, &raw); err != nil {
+		return nil, fmt.Errorf("team configuration from %s is not valid YAML: %w", src, err)
+	}
+	return result, nil
+}
+
+// download fetches url, refusing responses larger than maxSize.
+func (f *Fetcher) download(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := f.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", url, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download %s: unexpected HTTP status %s", url, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", url, err)
+	}
+	if len(data) > maxSize {
+		return nil, fmt.Errorf("team configuration at %s is larger than %d bytes", url, maxSize)
+	}
+	return data, nil
+}
+
+// readRef fetches the ref from the remote and reads the file from it.
+func (f *Fetcher) readRef(ctx context.Context, src Source, verify bool) ([]byte, error) {
+	if _, err := f.Git(ctx, f.Dir, "fetch", "--no-tags", DefaultRemote, "+"+src.Ref+":"+src.Ref); err != nil {
+		return nil, fmt.Errorf("failed to fetch %s from %s: %w", src.Ref, DefaultRemote, err)
+	}
+	if verify {
+		if _, err := f.Git(ctx, f.Dir, "verify-commit", src.Ref); err != nil {
+			return nil, fmt.Errorf("the commit at %s is not signed by a trusted key: %w", src.Ref, err)
+		}
+	}
+	out, err := f.Git(ctx, f.Dir, "show", src.Ref+":"+src.File)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s from %s: %w", src.File, src.Ref, err)
+	}
+	if len(out) > maxSize {
+		return nil, fmt.Errorf("team configuration in %s is larger than %d bytes", src.Ref, maxSize)
+	}
+	return []byte(out), nil
+}
+
+// VerifySHA256 checks data against a hex digest.
+func VerifySHA256(data []byte, want string) error {
+	sum := sha256.Sum256(data)
+	got := hex.EncodeToString(sum[:])
+	if !strings.EqualFold(got, strings.TrimSpace(want)) {
+		return fmt.Errorf("%w: got %s", ErrHashMismatch, got)
+	}
+	return nil
+}
+
+// Save writes data to path, replacing any previous copy only once complete.
+func Save(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	defer func() { _ = os.Remove(tmpPath) }()
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
+
+// runGit runs git in dir and returns its stdout, keeping trailing newlines
+// since file contents are read through it.
+func runGit(ctx context.Context, dir string, args ...string) (string, error) {
+	// #nosec G204 -- the arguments are built from a validated ref
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%w: %s", err, msg)
+		}
+		return "", err
+	}
+	return string(out), nil
+}
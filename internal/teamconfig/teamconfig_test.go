@@ -0,0 +1,131 @@
+package teamconfig
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const teamFile = "init_commands:\n  - npm ci\n"
+
+func digest(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestParseSource(t *testing.T) {
+	src, err := ParseSource(" https://example.com/team.wt ")
+	require.NoError(t, err)
+	assert.Equal(t, Source{URL: "https://example.com/team.wt"}, src)
+
+	src, err = ParseSource("refs/meta/lazyworktree")
+	require.NoError(t, err)
+	assert.Equal(t, Source{Ref: "refs/meta/lazyworktree", File: DefaultFile}, src)
+	assert.Equal(t, "refs/meta/lazyworktree:.wt", src.String())
+
+	src, err = ParseSource("refs/meta/lazyworktree:config/team.wt")
+	require.NoError(t, err)
+	assert.Equal(t, "config/team.wt", src.File)
+
+	for _, bad := range []string{"", "http://example.com/team.wt", "main", "refs/../x", "refs/meta/a b"} {
+		_, err := ParseSource(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestFetchURL(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/team.wt" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(teamFile))
+	}))
+	defer srv.Close()
+	f := &Fetcher{Client: srv.Client()}
+	ctx := context.Background()
+
+	result, err := f.Fetch(ctx, Source{URL: srv.URL + "/team.wt"}, Options{SHA256: strings.ToUpper(digest(teamFile))})
+	require.NoError(t, err)
+	assert.Equal(t, teamFile, string(result.Data))
+	assert.Equal(t, []string{"SHA-256"}, result.Verified)
+
+	result, err = f.Fetch(ctx, Source{URL: srv.URL + "/team.wt"}, Options{})
+	require.NoError(t, err)
+	assert.Empty(t, result.Verified, "an unpinned download is not verified")
+
+	_, err = f.Fetch(ctx, Source{URL: srv.URL + "/team.wt"}, Options{SHA256: digest("other")})
+	require.ErrorIs(t, err, ErrHashMismatch)
+
+	_, err = f.Fetch(ctx, Source{URL: srv.URL + "/missing"}, Options{})
+	assert.ErrorContains(t, err, "404")
+
+	_, err = f.Fetch(ctx, Source{URL: srv.URL + "/team.wt"}, Options{VerifySignature: true})
+	assert.ErrorContains(t, err, "needs a ref source")
+}
+
+func TestFetchRef(t *testing.T) {
+	var calls []string
+	signed := true
+	f := &Fetcher{Dir: "/repo", Git: func(_ context.Context, dir string, args ...string) (string, error) {
+		assert.Equal(t, "/repo", dir)
+		calls = append(calls, strings.Join(args, " "))
+		switch args[0] {
+		case "verify-commit":
+			if !signed {
+				return "", errors.New("no signature found")
+			}
+		case "show":
+			return teamFile, nil
+		}
+		return "", nil
+	}}
+	src := Source{Ref: "refs/meta/lazyworktree", File: DefaultFile}
+
+	result, err := f.Fetch(context.Background(), src, Options{VerifySignature: true})
+	require.NoError(t, err)
+	assert.Equal(t, teamFile, string(result.Data))
+	assert.Equal(t, []string{"signed commit"}, result.Verified)
+	assert.Equal(t, []string{
+		"fetch --no-tags origin +refs/meta/lazyworktree:refs/meta/lazyworktree",
+		"verify-commit refs/meta/lazyworktree",
+		"show refs/meta/lazyworktree:.wt",
+	}, calls)
+
+	signed = false
+	_, err = f.Fetch(context.Background(), src, Options{VerifySignature: true})
+	assert.ErrorContains(t, err, "not signed by a trusted key")
+}
+
+func TestFetchRejectsInvalidYAML(t *testing.T) {
+	f := &Fetcher{Git: func(_ context.Context, _ string, args ...string) (string, error) {
+		if args[0] == "show" {
+			return "init_commands: [[[", nil
+		}
+		return "", nil
+	}}
+	_, err := f.Fetch(context.Background(), Source{Ref: "refs/meta/lazyworktree", File: DefaultFile}, Options{})
+	assert.ErrorContains(t, err, "not valid YAML")
+}
+
+func TestSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "lazyworktree", "team.wt")
+	require.NoError(t, Save(path, []byte("one")))
+	require.NoError(t, Save(path, []byte("two")))
+	data, err := os.ReadFile(path) // #nosec G304 -- test path
+	require.NoError(t, err)
+	assert.Equal(t, "two", string(data))
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "no temporary files are left behind")
+}
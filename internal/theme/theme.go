@@ -68,6 +68,8 @@ const (
 	OneDarkName         = "one-dark"
 	RosePineName        = "rose-pine"
 	AyuMirageName       = "ayu-mirage"
+	OkabeItoName        = "okabe-ito"
+	OkabeItoLightName   = "okabe-ito-light"
 )
 
 // Dracula returns the Dracula theme (dark background, vibrant colors).
@@ -490,6 +492,49 @@ func CatppuccinMocha() *Theme {
 	}
 }
 
+// OkabeIto returns a dark theme built on the Okabe-Ito palette, whose
+// success, warning and error colours stay apart for red-green colour
+// blindness.
+func OkabeIto() *Theme {
+	return &Theme{
+		Background: lipgloss.Color("#1B1D23"),
+		Accent:     lipgloss.Color("#56B4E9"),
+		AccentFg:   lipgloss.Color("#1B1D23"), // Dark text on sky blue
+		AccentDim:  lipgloss.Color("#2A2F3A"),
+		Border:     lipgloss.Color("#4A5060"),
+		BorderDim:  lipgloss.Color("#30343E"),
+		MutedFg:    lipgloss.Color("#8A8F98"),
+		TextFg:     lipgloss.Color("#E6E6E6"),
+		SuccessFg:  lipgloss.Color("#56B4E9"), // Sky blue
+		WarnFg:     lipgloss.Color("#F0E442"), // Yellow
+		ErrorFg:    lipgloss.Color("#D55E00"), // Vermillion
+		Cyan:       lipgloss.Color("#009E73"),
+		Pink:       lipgloss.Color("#CC79A7"),
+		Yellow:     lipgloss.Color("#E69F00"),
+	}
+}
+
+// OkabeItoLight returns the light counterpart of OkabeIto, with darker
+// shades readable on white.
+func OkabeItoLight() *Theme {
+	return &Theme{
+		Background: lipgloss.Color("#FFFFFF"),
+		Accent:     lipgloss.Color("#0072B2"),
+		AccentFg:   lipgloss.Color("#FFFFFF"), // White text on blue
+		AccentDim:  lipgloss.Color("#DCE9F2"),
+		Border:     lipgloss.Color("#9AA3AD"),
+		BorderDim:  lipgloss.Color("#D5DAE0"),
+		MutedFg:    lipgloss.Color("#6B7280"),
+		TextFg:     lipgloss.Color("#1F2328"),
+		SuccessFg:  lipgloss.Color("#0072B2"), // Blue
+		WarnFg:     lipgloss.Color("#B07800"), // Dark orange
+		ErrorFg:    lipgloss.Color("#A8326E"), // Reddish purple
+		Cyan:       lipgloss.Color("#007A5A"),
+		Pink:       lipgloss.Color("#A8326E"),
+		Yellow:     lipgloss.Color("#B07800"),
+	}
+}
+
 // GetTheme returns a theme by name, or Dracula if not found.
 func GetTheme(name string) *Theme {
 	switch name {
@@ -533,6 +578,10 @@ func GetTheme(name string) *Theme {
 		return RosePine()
 	case AyuMirageName:
 		return AyuMirage()
+	case OkabeItoName:
+		return OkabeIto()
+	case OkabeItoLightName:
+		return OkabeItoLight()
 	default:
 		return Dracula()
 	}
@@ -572,6 +621,8 @@ func AvailableThemes() []string {
 		OneDarkName,
 		RosePineName,
 		AyuMirageName,
+		OkabeItoName,
+		OkabeItoLightName,
 	}
 }
 
@@ -1,6 +1,12 @@
 package theme
 
-import "testing"
+import (
+	"math"
+	"strconv"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
 
 func TestGetTheme(t *testing.T) {
 	for _, name := range AvailableThemes() {
@@ -57,3 +63,62 @@ func TestDefaults(t *testing.T) {
 		t.Errorf("DefaultLight() = %q, want %q", got, DraculaLightName)
 	}
 }
+
+// deuteranopiaLab simulates how a deuteranope sees hex, with the Machado et
+// al. (2009) matrix at full severity, and returns it in CIELAB.
+func deuteranopiaLab(t *testing.T, hex string) [3]float64 {
+	t.Helper()
+	var rgb [3]float64
+	for i := range rgb {
+		v, err := strconv.ParseUint(hex[1+2*i:3+2*i], 16, 8)
+		if err != nil {
+			t.Fatalf("bad colour %q: %v", hex, err)
+		}
+		c := float64(v) / 255
+		if c <= 0.04045 {
+			rgb[i] = c / 12.92
+		} else {
+			rgb[i] = math.Pow((c+0.055)/1.055, 2.4)
+		}
+	}
+	sim := [3][3]float64{
+		{0.367322, 0.860646, -0.227968},
+		{0.280085, 0.672501, 0.047413},
+		{-0.011820, 0.042940, 0.968881},
+	}
+	var lin [3]float64
+	for i := range lin {
+		lin[i] = math.Min(1, math.Max(0, sim[i][0]*rgb[0]+sim[i][1]*rgb[1]+sim[i][2]*rgb[2]))
+	}
+	x := (0.4124*lin[0] + 0.3576*lin[1] + 0.1805*lin[2]) / 0.95047
+	y := 0.2126*lin[0] + 0.7152*lin[1] + 0.0722*lin[2]
+	z := (0.0193*lin[0] + 0.1192*lin[1] + 0.9505*lin[2]) / 1.08883
+	f := func(v float64) float64 {
+		if v > 0.008856 {
+			return math.Cbrt(v)
+		}
+		return 7.787*v + 16.0/116
+	}
+	return [3]float64{116*f(y) - 16, 500 * (f(x) - f(y)), 200 * (f(y) - f(z))}
+}
+
+func TestOkabeItoThemesSurviveDeuteranopia(t *testing.T) {
+	// Dracula's green and red fall to about 20 apart; 30 is well visible.
+	const minDistance = 30.0
+	for _, name := range []string{OkabeItoName, OkabeItoLightName} {
+		thm := GetTheme(name)
+		statuses := map[string]lipgloss.Color{"success": thm.SuccessFg, "warning": thm.WarnFg, "error": thm.ErrorFg}
+		for a, colourA := range statuses {
+			for b, colourB := range statuses {
+				if a >= b {
+					continue
+				}
+				labA, labB := deuteranopiaLab(t, string(colourA)), deuteranopiaLab(t, string(colourB))
+				distance := math.Sqrt(math.Pow(labA[0]-labB[0], 2) + math.Pow(labA[1]-labB[1], 2) + math.Pow(labA[2]-labB[2], 2))
+				if distance < minDistance {
+					t.Errorf("%s: %s and %s are only %.1f apart under deuteranopia", name, a, b, distance)
+				}
+			}
+		}
+	}
+}
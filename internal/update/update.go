@@ -0,0 +1,340 @@
+// Package update installs lazyworktree releases published on GitHub.
+package update
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultAPIURL is the GitHub API endpoint for the latest release.
+	DefaultAPIURL = "https://api.github.com/repos/chmouel/lazyworktree/releases/latest"
+
+	projectName   = "lazyworktree"
+	checksumsName = "checksums.txt"
+
+	// maxDownloadBytes caps release downloads; archives are a few megabytes.
+	maxDownloadBytes = 100 << 20
+	requestTimeout   = 2 * time.Minute
+)
+
+// ErrNoAsset is returned when a release has no archive for this platform.
+var ErrNoAsset = errors.New("no release archive for this platform")
+
+// Release is the subset of a GitHub release the updater needs.
+type Release struct {
+	Tag    string  `json:"tag_name"`
+	Assets []Asset `json:"assets"`
+}
+
+// Asset is a file attached to a release.
+type Asset struct {
+	Name string `json:"name"`
+	URL  string `json:"browser_download_url"`
+}
+
+// find returns the asset called name.
+func (r *Release) find(name string) (Asset, bool) {
+	for _, asset := range r.Assets {
+		if asset.Name == name {
+			return asset, true
+		}
+	}
+	return Asset{}, false
+}
+
+// Updater fetches releases and replaces the running executable.
+type Updater struct {
+	Client *http.Client
+	APIURL string
+	GOOS   string
+	GOARCH string
+	// Token authenticates GitHub API requests; GITHUB_TOKEN is used when
+	// empty.
+	Token string
+}
+
+// New returns an updater for the platform lazyworktree was built for.
+func New() *Updater {
+	return &Updater{
+		Client: &http.Client{Timeout: requestTimeout},
+		APIURL: DefaultAPIURL,
+		GOOS:   runtime.GOOS,
+		GOARCH: runtime.GOARCH,
+	}
+}
+
+// Latest returns the most recent published release.
+func (u *Updater) Latest(ctx context.Context) (*Release, error) {
+	data, err := u.get(ctx, u.APIURL, "application/vnd.github+json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query the latest release: %w", err)
+	}
+	var release Release
+	if err := json.Unmarshal(data, &release); err != nil {
+		return nil, fmt.Errorf("failed to parse the latest release: %w", err)
+	}
+	if release.Tag == "" {
+		return nil, errors.New("the latest release has no tag")
+	}
+	return &release, nil
+}
+
+// Download fetches this platform's archive from release, checks it against
+// the release checksums and returns the lazyworktree binary inside it.
+func (u *Updater) Download(ctx context.Context, release *Release) ([]byte, error) {
+	name := ArchiveName(u.GOOS, u.GOARCH)
+	archive, ok := release.find(name)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s is not part of %s", ErrNoAsset, name, release.Tag)
+	}
+	checksums, ok := release.find(checksumsName)
+	if !ok {
+		return nil, fmt.Errorf("%s has no %s to verify the download against", release.Tag, checksumsName)
+	}
+
+	sums, err := u.get(ctx, checksums.URL, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", checksumsName, err)
+	}
+	data, err := u.get(ctx, archive.URL, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", name, err)
+	}
+	if err := VerifyChecksum(data, name, sums); err != nil {
+		return nil, err
+	}
+	return ExtractBinary(data, name, binaryName(u.GOOS))
+}
+
+// get downloads url, refusing responses larger than maxDownloadBytes.
+func (u *Updater) get(ctx context.Context, url, accept string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	token := u.Token
+	if token == "" {
+		token = os.Getenv("GITHUB_TOKEN")
+	}
+	if token != "" && strings.HasPrefix(url, "https://api.github.com/") {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := u.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected HTTP status %s", resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxDownloadBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxDownloadBytes {
+		return nil, fmt.Errorf("response larger than %d bytes", maxDownloadBytes)
+	}
+	return data, nil
+}
+
+// ArchiveName returns the release archive name for a platform, following the
+// goreleaser name template.
+func ArchiveName(goos, goarch string) string {
+	arch := goarch
+	switch goarch {
+	case "amd64":
+		arch = "x86_64"
+	case "386":
+		arch = "i386"
+	}
+	ext := ".tar.gz"
+	if goos == "windows" {
+		ext = ".zip"
+	}
+	return fmt.Sprintf("%s_%s_%s%s", projectName, strings.ToUpper(goos[:1])+goos[1:], arch, ext)
+}
+
+func binaryName(goos string) string {
+	if goos == "windows" {
+		return projectName + ".exe"
+	}
+	return projectName
+}
+
+// VerifyChecksum checks data against the SHA-256 listed for name in a
+// sha256sum-style checksums file.
+func VerifyChecksum(data []byte, name string, checksums []byte) error {
+	scanner := bufio.NewScanner(bytes.NewReader(checksums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 || strings.TrimPrefix(fields[1], "*") != name {
+			continue
+		}
+		sum := sha256.Sum256(data)
+		if !strings.EqualFold(hex.EncodeToString(sum[:]), fields[0]) {
+			return fmt.Errorf("checksum mismatch for %s", name)
+		}
+		return nil
+	}
+	return fmt.Errorf("no checksum listed for %s", name)
+}
+
+// ExtractBinary returns the file called binary from a .tar.gz or .zip
+// archive.
+func ExtractBinary(archive []byte, archiveName, binary string) ([]byte, error) {
+	if strings.HasSuffix(archiveName, ".zip") {
+		return extractZip(archive, binary)
+	}
+	return extractTarGz(archive, binary)
+}
+
+func extractTarGz(archive []byte, binary string) ([]byte, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer func() { _ = gz.Close() }()
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read archive: %w", err)
+		}
+		if header.Typeflag == tar.TypeReg && path.Base(header.Name) == binary {
+			return io.ReadAll(io.LimitReader(tr, maxDownloadBytes))
+		}
+	}
+	return nil, fmt.Errorf("%s not found in the archive", binary)
+}
+
+func extractZip(archive []byte, binary string) ([]byte, error) {
+	zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open archive: %w", err)
+	}
+	for _, file := range zr.File {
+		if file.FileInfo().IsDir() || path.Base(file.Name) != binary {
+			continue
+		}
+		rc, err := file.Open()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read archive: %w", err)
+		}
+		defer func() { _ = rc.Close() }()
+		return io.ReadAll(io.LimitReader(rc, maxDownloadBytes))
+	}
+	return nil, fmt.Errorf("%s not found in the archive", binary)
+}
+
+// Replace atomically swaps the executable at exe for binary. The new file is
+// written next to exe and renamed over it, so a failure leaves the current
+// executable untouched.
+func Replace(exe string, binary []byte) error {
+	info, err := os.Stat(exe)
+	if err != nil {
+		return fmt.Errorf("failed to stat %s: %w", exe, err)
+	}
+	dir := filepath.Dir(exe)
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(exe)+".new-*")
+	if err != nil {
+		return fmt.Errorf("failed to write to %s: %w", dir, err)
+	}
+	tmpPath := tmp.Name()
+	defer func() { _ = os.Remove(tmpPath) }()
+
+	if _, err := tmp.Write(binary); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write the new executable: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write the new executable: %w", err)
+	}
+	if err := os.Chmod(tmpPath, info.Mode().Perm()|0o111); err != nil {
+		return fmt.Errorf("failed to make the new executable runnable: %w", err)
+	}
+
+	// Windows cannot replace a running executable, but it can rename it.
+	movedAside := ""
+	if runtime.GOOS == "windows" {
+		movedAside = exe + ".old"
+		_ = os.Remove(movedAside)
+		if err := os.Rename(exe, movedAside); err != nil {
+			return fmt.Errorf("failed to move the current executable aside: %w", err)
+		}
+	}
+	if err := os.Rename(tmpPath, exe); err != nil {
+		if movedAside != "" {
+			if restoreErr := os.Rename(movedAside, exe); restoreErr != nil {
+				return fmt.Errorf("failed to replace %s: %w (the previous executable is at %s)", exe, err, movedAside)
+			}
+		}
+		return fmt.Errorf("failed to replace %s: %w", exe, err)
+	}
+	return nil
+}
+
+// IsNewer reports whether the release tag latest is newer than current.
+// Versions that are not plain semver, such as dev builds, never compare as
+// older so they are only replaced on request.
+func IsNewer(current, latest string) bool {
+	cur, ok := parseVersion(current)
+	if !ok {
+		return false
+	}
+	next, ok := parseVersion(latest)
+	if !ok {
+		return false
+	}
+	for i := range cur {
+		if next[i] != cur[i] {
+			return next[i] > cur[i]
+		}
+	}
+	return false
+}
+
+// parseVersion parses "v1.2.3" or "1.2.3", ignoring any pre-release suffix.
+func parseVersion(v string) ([3]int, bool) {
+	var parts [3]int
+	v = strings.TrimPrefix(strings.TrimSpace(v), "v")
+	if i := strings.IndexAny(v, "-+"); i >= 0 {
+		v = v[:i]
+	}
+	fields := strings.Split(v, ".")
+	if len(fields) != 3 {
+		return parts, false
+	}
+	for i, field := range fields {
+		n, err := strconv.Atoi(field)
+		if err != nil || n < 0 {
+			return parts, false
+		}
+		parts[i] = n
+	}
+	return parts, true
+}
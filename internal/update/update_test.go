@@ -0,0 +1,175 @@
+package update
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func tarGz(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0o755, Size: int64(len(content)), Typeflag: tar.TypeReg}))
+		_, err := tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+	return buf.Bytes()
+}
+
+func zipArchive(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+func checksumLine(data []byte, name string) string {
+	sum := sha256.Sum256(data)
+	return fmt.Sprintf("%s  %s\n", hex.EncodeToString(sum[:]), name)
+}
+
+func TestArchiveName(t *testing.T) {
+	assert.Equal(t, "lazyworktree_Linux_x86_64.tar.gz", ArchiveName("linux", "amd64"))
+	assert.Equal(t, "lazyworktree_Darwin_arm64.tar.gz", ArchiveName("darwin", "arm64"))
+	assert.Equal(t, "lazyworktree_Windows_x86_64.zip", ArchiveName("windows", "amd64"))
+}
+
+func TestIsNewer(t *testing.T) {
+	tests := []struct {
+		current, latest string
+		want            bool
+	}{
+		{current: "1.2.3", latest: "v1.2.4", want: true},
+		{current: "v1.2.3", latest: "v1.10.0", want: true},
+		{current: "1.2.3", latest: "v1.2.3"},
+		{current: "2.0.0", latest: "v1.9.9"},
+		{current: "1.2.3-next", latest: "v1.3.0", want: true},
+		{current: "dev", latest: "v1.0.0"},
+		{current: "1.2.3", latest: "nightly"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, IsNewer(tt.current, tt.latest), "%s -> %s", tt.current, tt.latest)
+	}
+}
+
+func TestVerifyChecksum(t *testing.T) {
+	data := []byte("archive")
+	sums := []byte("deadbeef  other.tar.gz\n" + checksumLine(data, "lazyworktree_Linux_x86_64.tar.gz"))
+
+	require.NoError(t, VerifyChecksum(data, "lazyworktree_Linux_x86_64.tar.gz", sums))
+	assert.ErrorContains(t, VerifyChecksum([]byte("tampered"), "lazyworktree_Linux_x86_64.tar.gz", sums), "checksum mismatch")
+	assert.ErrorContains(t, VerifyChecksum(data, "lazyworktree_Darwin_arm64.tar.gz", sums), "no checksum listed")
+}
+
+func TestExtractBinary(t *testing.T) {
+	files := map[string]string{"README.md": "docs", "lazyworktree": "linux binary"}
+	got, err := ExtractBinary(tarGz(t, files), "x.tar.gz", "lazyworktree")
+	require.NoError(t, err)
+	assert.Equal(t, "linux binary", string(got))
+
+	got, err = ExtractBinary(zipArchive(t, map[string]string{"lazyworktree.exe": "windows binary"}), "x.zip", "lazyworktree.exe")
+	require.NoError(t, err)
+	assert.Equal(t, "windows binary", string(got))
+
+	_, err = ExtractBinary(tarGz(t, map[string]string{"README.md": "docs"}), "x.tar.gz", "lazyworktree")
+	assert.ErrorContains(t, err, "not found")
+}
+
+func TestReplace(t *testing.T) {
+	exe := filepath.Join(t.TempDir(), "lazyworktree")
+	require.NoError(t, os.WriteFile(exe, []byte("old"), 0o700))
+
+	require.NoError(t, Replace(exe, []byte("new")))
+	data, err := os.ReadFile(exe)
+	require.NoError(t, err)
+	assert.Equal(t, "new", string(data))
+	info, err := os.Stat(exe)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o711), info.Mode().Perm())
+
+	entries, err := os.ReadDir(filepath.Dir(exe))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "no temporary file is left behind")
+}
+
+// releaseServer serves a latest release whose Linux archive holds binary.
+// A non-empty badSum replaces the archive checksum.
+func releaseServer(t *testing.T, tag, binary, badSum string) *httptest.Server {
+	t.Helper()
+	archiveName := ArchiveName("linux", "amd64")
+	archive := tarGz(t, map[string]string{"lazyworktree": binary})
+	sums := checksumLine(archive, archiveName)
+	if badSum != "" {
+		sums = badSum + "  " + archiveName + "\n"
+	}
+
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	mux.HandleFunc("/latest", func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(Release{Tag: tag, Assets: []Asset{
+			{Name: archiveName, URL: server.URL + "/archive"},
+			{Name: checksumsName, URL: server.URL + "/checksums"},
+		}})
+	})
+	mux.HandleFunc("/archive", func(w http.ResponseWriter, _ *http.Request) { _, _ = w.Write(archive) })
+	mux.HandleFunc("/checksums", func(w http.ResponseWriter, _ *http.Request) { _, _ = w.Write([]byte(sums)) })
+	return server
+}
+
+func testUpdater(server *httptest.Server, goos string) *Updater {
+	return &Updater{Client: server.Client(), APIURL: server.URL + "/latest", GOOS: goos, GOARCH: "amd64"}
+}
+
+func TestLatestAndDownload(t *testing.T) {
+	server := releaseServer(t, "v9.9.9", "fresh binary", "")
+	updater := testUpdater(server, "linux")
+	ctx := context.Background()
+
+	release, err := updater.Latest(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "v9.9.9", release.Tag)
+
+	binary, err := updater.Download(ctx, release)
+	require.NoError(t, err)
+	assert.Equal(t, "fresh binary", string(binary))
+
+	_, err = testUpdater(server, "darwin").Download(ctx, release)
+	assert.ErrorIs(t, err, ErrNoAsset)
+}
+
+func TestDownloadRejectsBadChecksum(t *testing.T) {
+	server := releaseServer(t, "v9.9.9", "fresh binary", "0000")
+	updater := testUpdater(server, "linux")
+	release, err := updater.Latest(context.Background())
+	require.NoError(t, err)
+
+	_, err = updater.Download(context.Background(), release)
+	assert.ErrorContains(t, err, "checksum mismatch")
+}
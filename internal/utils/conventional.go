@@ -0,0 +1,115 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+// conventionalHeaderRe matches "type(scope)!: description".
+var conventionalHeaderRe = regexp.MustCompile(`^([A-Za-z]+)(?:\(([^()\r\n]*)\))?(!)?: (\S.*)$`)
+
+// ConventionalCommit is a commit message parsed per the Conventional Commits spec.
+type ConventionalCommit struct {
+	Type        string
+	Scope       string
+	Description string
+	Breaking    bool
+}
+
+// ParseConventionalCommit parses the header (and BREAKING CHANGE footer) of a
+// commit message. It returns false when the header does not follow the spec.
+func ParseConventionalCommit(message string) (ConventionalCommit, bool) {
+	header, body, _ := strings.Cut(strings.TrimSpace(message), "\n")
+	match := conventionalHeaderRe.FindStringSubmatch(strings.TrimSpace(header))
+	if match == nil {
+		return ConventionalCommit{}, false
+	}
+	cc := ConventionalCommit{
+		Type:        strings.ToLower(match[1]),
+		Scope:       strings.TrimSpace(match[2]),
+		Description: strings.TrimSpace(match[4]),
+		Breaking:    match[3] == "!",
+	}
+	for line := range strings.SplitSeq(body, "\n") {
+		if strings.HasPrefix(line, "BREAKING CHANGE:") || strings.HasPrefix(line, "BREAKING-CHANGE:") {
+			cc.Breaking = true
+			break
+		}
+	}
+	return cc, true
+}
+
+// changelogSections orders the headings of a generated changelog.
+var changelogSections = []struct {
+	title string
+	types []string
+}{
+	{"Features", []string{"feat"}},
+	{"Bug Fixes", []string{"fix"}},
+	{"Performance", []string{"perf"}},
+	{"Reverts", []string{"revert"}},
+	{"Documentation", []string{"docs"}},
+	{"Other Changes", nil},
+}
+
+// GenerateChangelog renders commits as a Markdown changelog snippet grouped by
+// Conventional Commit type. Breaking changes are also listed in their own
+// section, and non-conventional commits fall under "Other Changes".
+func GenerateChangelog(commits []models.CommitMessage) string {
+	sections := make(map[string][]string)
+	var breaking []string
+	for _, commit := range commits {
+		cc, ok := ParseConventionalCommit(commit.Message)
+		if !ok {
+			header, _, _ := strings.Cut(strings.TrimSpace(commit.Message), "\n")
+			cc = ConventionalCommit{Description: strings.TrimSpace(header)}
+		}
+		if cc.Description == "" {
+			continue
+		}
+		entry := cc.Description
+		if cc.Scope != "" {
+			entry = fmt.Sprintf("**%s:** %s", cc.Scope, entry)
+		}
+		if commit.SHA != "" {
+			entry = fmt.Sprintf("%s (%s)", entry, commit.SHA)
+		}
+		if cc.Breaking {
+			breaking = append(breaking, entry)
+		}
+		sections[changelogSectionFor(cc.Type)] = append(sections[changelogSectionFor(cc.Type)], entry)
+	}
+
+	var b strings.Builder
+	writeSection := func(title string, entries []string) {
+		if len(entries) == 0 {
+			return
+		}
+		if b.Len() > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "### %s\n\n", title)
+		for _, entry := range entries {
+			fmt.Fprintf(&b, "- %s\n", entry)
+		}
+	}
+	writeSection("Breaking Changes", breaking)
+	for _, section := range changelogSections {
+		writeSection(section.title, sections[section.title])
+	}
+	return b.String()
+}
+
+func changelogSectionFor(commitType string) string {
+	for _, section := range changelogSections {
+		for _, t := range section.types {
+			if t == commitType {
+				return section.title
+			}
+		}
+	}
+	return "Other Changes"
+}
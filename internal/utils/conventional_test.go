@@ -0,0 +1,78 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/chmouel/lazyworktree/internal/models"
+)
+
+func TestParseConventionalCommit(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		message string
+		want    ConventionalCommit
+		ok      bool
+	}{
+		{name: "type only", message: "fix: handle nil", want: ConventionalCommit{Type: "fix", Description: "handle nil"}, ok: true},
+		{name: "scope", message: "feat(ui): add panel", want: ConventionalCommit{Type: "feat", Scope: "ui", Description: "add panel"}, ok: true},
+		{name: "bang", message: "refactor(api)!: drop v1", want: ConventionalCommit{Type: "refactor", Scope: "api", Description: "drop v1", Breaking: true}, ok: true},
+		{name: "footer", message: "feat: new flag\n\nBREAKING CHANGE: old flag removed", want: ConventionalCommit{Type: "feat", Description: "new flag", Breaking: true}, ok: true},
+		{name: "uppercase type", message: "Fix: typo", want: ConventionalCommit{Type: "fix", Description: "typo"}, ok: true},
+		{name: "plain", message: "Update README", ok: false},
+		{name: "missing space", message: "fix:typo", ok: false},
+		{name: "empty description", message: "fix: ", ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, ok := ParseConventionalCommit(tt.message)
+			if ok != tt.ok {
+				t.Fatalf("ParseConventionalCommit(%q) ok = %v, want %v", tt.message, ok, tt.ok)
+			}
+			if ok && got != tt.want {
+				t.Fatalf("ParseConventionalCommit(%q) = %+v, want %+v", tt.message, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateChangelog(t *testing.T) {
+	t.Parallel()
+
+	got := GenerateChangelog([]models.CommitMessage{
+		{SHA: "a1", Message: "fix: handle nil"},
+		{SHA: "b2", Message: "feat(ui)!: new layout"},
+		{SHA: "c3", Message: "Update README"},
+		{SHA: "d4", Message: "chore: bump deps"},
+	})
+	want := strings.Join([]string{
+		"### Breaking Changes",
+		"",
+		"- **ui:** new layout (b2)",
+		"",
+		"### Features",
+		"",
+		"- **ui:** new layout (b2)",
+		"",
+		"### Bug Fixes",
+		"",
+		"- handle nil (a1)",
+		"",
+		"### Other Changes",
+		"",
+		"- Update README (c3)",
+		"- bump deps (d4)",
+		"",
+	}, "\n")
+	if got != want {
+		t.Fatalf("GenerateChangelog() =\n%s\nwant\n%s", got, want)
+	}
+
+	if got := GenerateChangelog(nil); got != "" {
+		t.Fatalf("expected empty changelog, got %q", got)
+	}
+}
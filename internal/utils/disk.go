@@ -0,0 +1,43 @@
+package utils
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+)
+
+// DirSize returns the total size in bytes of the regular files below root.
+// Unreadable entries are skipped rather than failing the whole walk.
+func DirSize(root string) (int64, error) {
+	var total int64
+	err := filepath.WalkDir(root, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if d != nil && d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		if info, infoErr := d.Info(); infoErr == nil {
+			total += info.Size()
+		}
+		return nil
+	})
+	return total, err
+}
+
+// FormatBytes renders a byte count with a binary unit suffix, e.g. "1.5 MiB".
+func FormatBytes(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,47 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDirSize(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "sub"), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "a.txt"), make([]byte, 100), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "sub", "b.txt"), make([]byte, 50), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	size, err := DirSize(root)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if size != 150 {
+		t.Fatalf("DirSize() = %d, want 150", size)
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	t.Parallel()
+
+	tests := map[int64]string{
+		0:               "0 B",
+		512:             "512 B",
+		1536:            "1.5 KiB",
+		5 * 1024 * 1024: "5.0 MiB",
+		3 << 30:         "3.0 GiB",
+	}
+	for size, want := range tests {
+		if got := FormatBytes(size); got != want {
+			t.Fatalf("FormatBytes(%d) = %q, want %q", size, got, want)
+		}
+	}
+}
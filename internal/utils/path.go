@@ -17,3 +17,13 @@ func ExpandPath(path string) (string, error) {
 	}
 	return os.ExpandEnv(path), nil
 }
+
+// CacheDir returns lazyworktree's cache directory, under $XDG_CACHE_HOME or
+// ~/.cache. Caches can be deleted at any time without losing anything.
+func CacheDir() string {
+	if xdg := os.Getenv("XDG_CACHE_HOME"); xdg != "" {
+		return filepath.Join(xdg, "lazyworktree")
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".cache", "lazyworktree")
+}
@@ -69,3 +69,16 @@ func TestExpandPath(t *testing.T) {
 		})
 	}
 }
+
+func TestCacheDir(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", "/xdg/cache")
+	if got := CacheDir(); got != filepath.Join("/xdg/cache", "lazyworktree") {
+		t.Fatalf("CacheDir() = %q", got)
+	}
+
+	t.Setenv("XDG_CACHE_HOME", "")
+	home, _ := os.UserHomeDir()
+	if got := CacheDir(); got != filepath.Join(home, ".cache", "lazyworktree") {
+		t.Fatalf("CacheDir() = %q", got)
+	}
+}
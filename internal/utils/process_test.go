@@ -0,0 +1,19 @@
+package utils
+
+import (
+	"os"
+	"testing"
+)
+
+func TestProcessAlive(t *testing.T) {
+	t.Parallel()
+
+	if !ProcessAlive(os.Getpid()) {
+		t.Fatal("expected the test process to be alive")
+	}
+	for _, pid := range []int{0, -1} {
+		if ProcessAlive(pid) {
+			t.Fatalf("expected pid %d to be rejected", pid)
+		}
+	}
+}
@@ -0,0 +1,19 @@
+//go:build !windows
+
+package utils
+
+import (
+	"errors"
+	"syscall"
+)
+
+// ProcessAlive reports whether a process with the given ID is running.
+func ProcessAlive(pid int) bool {
+	// kill(0, 0) and negative IDs signal process groups, not one process.
+	if pid <= 0 {
+		return false
+	}
+	err := syscall.Kill(pid, 0)
+	// EPERM means the process exists but belongs to someone else.
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
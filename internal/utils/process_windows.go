@@ -0,0 +1,19 @@
+//go:build windows
+
+package utils
+
+import "os"
+
+// ProcessAlive reports whether a process with the given ID is running.
+// FindProcess opens a handle on Windows, so it fails for exited processes.
+func ProcessAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	proc, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	_ = proc.Release()
+	return true
+}
@@ -5,6 +5,11 @@ import (
 	"strings"
 )
 
+var (
+	nonAlnumRe = regexp.MustCompile(`[^a-z0-9]+`)
+	hyphensRe  = regexp.MustCompile(`-+`)
+)
+
 // SanitizeBranchName sanitizes a branch/title for use as a worktree directory name.
 // - Converts to lowercase
 // - Keeps only alphanumeric characters, replaces everything else with hyphens
@@ -15,11 +20,10 @@ func SanitizeBranchName(name string, maxLength int) string {
 	sanitized := strings.ToLower(strings.TrimSpace(name))
 
 	// Replace all non-alphanumeric characters with hyphens
-	re := regexp.MustCompile(`[^a-z0-9]+`)
-	sanitized = re.ReplaceAllString(sanitized, "-")
+	sanitized = nonAlnumRe.ReplaceAllString(sanitized, "-")
 
 	// Collapse consecutive hyphens
-	sanitized = regexp.MustCompile(`-+`).ReplaceAllString(sanitized, "-")
+	sanitized = hyphensRe.ReplaceAllString(sanitized, "-")
 
 	// Trim leading/trailing hyphens
 	sanitized = strings.Trim(sanitized, "-")
@@ -0,0 +1,177 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// windowsMaxPath is the path length Windows programs handle without
+	// core.longpaths.
+	windowsMaxPath = 260
+	// worktreePathHeadroom is left below windowsMaxPath for the files
+	// inside a worktree, which are checked out below its directory.
+	worktreePathHeadroom = 80
+	// defaultMaxNameBytes is the longest file name most filesystems store.
+	defaultMaxNameBytes = 255
+	// ecryptfsMaxNameBytes is the longest file name eCryptfs stores, as it
+	// encrypts names into longer ones.
+	ecryptfsMaxNameBytes = 143
+	// minShortenedName is the shortest name a directory is cut down to.
+	minShortenedName = 16
+)
+
+// windowsReservedNames are device names Windows refuses as file names,
+// whatever their extension.
+var windowsReservedNames = map[string]bool{
+	"CON": true, "PRN": true, "AUX": true, "NUL": true,
+	"COM1": true, "COM2": true, "COM3": true, "COM4": true, "COM5": true, "COM6": true, "COM7": true, "COM8": true, "COM9": true,
+	"LPT1": true, "LPT2": true, "LPT3": true, "LPT4": true, "LPT5": true, "LPT6": true, "LPT7": true, "LPT8": true, "LPT9": true,
+}
+
+// PathRules describes the limits of the filesystem a worktree is created on.
+type PathRules struct {
+	MaxPath         int  // Longest path allowed, 0 for no limit
+	MaxNameBytes    int  // Longest directory name in bytes, 0 for no limit
+	Windows         bool // Refuse names Windows reserves or cannot store
+	CaseInsensitive bool // Names differing only in case are the same directory
+}
+
+// PathRulesFor returns the rules of the filesystem holding dir on this
+// system. longPaths reports whether git's core.longpaths lifts the Windows
+// path limit.
+func PathRulesFor(dir string, longPaths bool) PathRules {
+	rules := PathRules{MaxNameBytes: maxNameBytes(dir)}
+	switch runtime.GOOS {
+	case "windows":
+		rules.Windows = true
+		rules.CaseInsensitive = true
+		if !longPaths {
+			rules.MaxPath = windowsMaxPath - worktreePathHeadroom
+		}
+	case "darwin":
+		rules.CaseInsensitive = true
+	}
+	return rules
+}
+
+// CheckWorktreePath reports why creating a worktree at path would fail
+// under rules, existing being the worktrees git already knows. When a
+// different name in the same directory avoids the problem, fixed is that
+// path; otherwise it is empty. A path without problems is returned as is.
+func CheckWorktreePath(path string, existing []string, rules PathRules) (fixed, problem string) {
+	dir, name := filepath.Dir(path), filepath.Base(path)
+	var problems []string
+
+	if rules.Windows {
+		if safe := windowsSafeName(name); safe != name {
+			problems = append(problems, fmt.Sprintf("%q is not a valid name on Windows", name))
+			name = safe
+		}
+	}
+	if rules.MaxNameBytes > 0 && len(name) > rules.MaxNameBytes {
+		problems = append(problems, fmt.Sprintf("the name is %d bytes, over the filesystem's %d", len(name), rules.MaxNameBytes))
+		name = shortenName(name, rules.MaxNameBytes)
+	}
+	if rules.MaxPath > 0 && len(filepath.Join(dir, name)) > rules.MaxPath {
+		room := rules.MaxPath - len(dir) - 1
+		if room < minShortenedName {
+			return "", fmt.Sprintf("%s is too long for Windows paths; enable core.longpaths or choose a shorter worktree_dir", dir)
+		}
+		problems = append(problems, fmt.Sprintf("the path is over the %d characters Windows allows, leaving room for the files inside", windowsMaxPath))
+		name = shortenName(name, room)
+	}
+	if other := clashingWorktree(filepath.Join(dir, name), existing, rules.CaseInsensitive); other != "" {
+		problems = append(problems, fmt.Sprintf("it clashes with the worktree at %s", other))
+		name = uniqueName(dir, name, existing, rules)
+	}
+
+	if len(problems) == 0 {
+		return path, ""
+	}
+	return filepath.Join(dir, name), strings.Join(problems, "; ")
+}
+
+// windowsSafeName replaces the characters Windows cannot store in a name,
+// drops trailing dots and spaces, and moves reserved device names aside.
+func windowsSafeName(name string) string {
+	safe := strings.Map(func(r rune) rune {
+		if r < 0x20 || strings.ContainsRune(`<>:"/\|?*`, r) {
+			return '-'
+		}
+		return r
+	}, name)
+	safe = strings.TrimRight(safe, ". ")
+	if safe == "" {
+		return "worktree"
+	}
+	base, _, _ := strings.Cut(safe, ".")
+	if windowsReservedNames[strings.ToUpper(base)] {
+		safe = "wt-" + safe
+	}
+	return safe
+}
+
+// shortenName cuts name down to limit bytes, keeping its start readable and
+// ending it with a hash of the whole name, so different long names stay
+// apart.
+func shortenName(name string, limit int) string {
+	sum := sha256.Sum256([]byte(name))
+	suffix := "-" + hex.EncodeToString(sum[:])[:6]
+	keep := limit - len(suffix)
+	if keep <= 0 {
+		return suffix[1:]
+	}
+	prefix := name
+	if len(prefix) > keep {
+		prefix = prefix[:keep]
+		for !utf8.ValidString(prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	prefix = strings.TrimRight(prefix, "-.")
+	if prefix == "" {
+		return suffix[1:]
+	}
+	return prefix + suffix
+}
+
+// clashingWorktree returns the known worktree at path, compared without
+// case where the filesystem ignores it.
+func clashingWorktree(path string, existing []string, caseInsensitive bool) string {
+	for _, other := range existing {
+		if other == path || (caseInsensitive && strings.EqualFold(other, path)) {
+			return other
+		}
+	}
+	return ""
+}
+
+// uniqueName numbers name until no known worktree uses it, shortening it
+// first when the number would break a limit.
+func uniqueName(dir, name string, existing []string, rules PathRules) string {
+	for i := 2; ; i++ {
+		suffix := "-" + strconv.Itoa(i)
+		limit := len(name) + len(suffix)
+		if rules.MaxNameBytes > 0 {
+			limit = min(limit, rules.MaxNameBytes)
+		}
+		if rules.MaxPath > 0 {
+			limit = min(limit, rules.MaxPath-len(dir)-1)
+		}
+		base := name
+		if len(base)+len(suffix) > limit {
+			base = shortenName(name, limit-len(suffix))
+		}
+		candidate := base + suffix
+		if clashingWorktree(filepath.Join(dir, candidate), existing, rules.CaseInsensitive) == "" {
+			return candidate
+		}
+	}
+}
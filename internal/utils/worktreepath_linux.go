@@ -0,0 +1,31 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// ecryptfsMagic identifies eCryptfs in statfs results.
+const ecryptfsMagic = 0xf15f
+
+// maxNameBytes returns the longest name the filesystem holding dir, or its
+// nearest existing parent, stores.
+func maxNameBytes(dir string) int {
+	for {
+		var st syscall.Statfs_t
+		if err := syscall.Statfs(dir, &st); err == nil {
+			if st.Type == ecryptfsMagic {
+				return ecryptfsMaxNameBytes
+			}
+			return defaultMaxNameBytes
+		} else if !os.IsNotExist(err) {
+			return defaultMaxNameBytes
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return defaultMaxNameBytes
+		}
+		dir = parent
+	}
+}
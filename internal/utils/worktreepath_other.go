@@ -0,0 +1,9 @@
+//go:build !linux
+
+package utils
+
+// maxNameBytes returns the longest name most filesystems store; eCryptfs
+// is only detected on Linux.
+func maxNameBytes(string) int {
+	return defaultMaxNameBytes
+}
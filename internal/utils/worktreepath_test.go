@@ -0,0 +1,68 @@
+package utils
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckWorktreePath(t *testing.T) {
+	dir := filepath.Join(string(filepath.Separator)+"wt", "repo")
+	long := strings.Repeat("a", 150)
+
+	tests := []struct {
+		name        string
+		worktree    string
+		existing    []string
+		rules       PathRules
+		wantName    string // Expected name of the fixed path; empty for none
+		wantProblem string
+	}{
+		{name: "fine", worktree: "feature", rules: PathRules{MaxNameBytes: 255}, wantName: "feature"},
+		{name: "ecryptfs name limit", worktree: long, rules: PathRules{MaxNameBytes: 143}, wantName: strings.Repeat("a", 136) + "-", wantProblem: "over the filesystem's 143"},
+		{name: "windows reserved name", worktree: "con", rules: PathRules{Windows: true}, wantName: "wt-con", wantProblem: "not a valid name on Windows"},
+		{name: "windows trailing dot", worktree: "release.", rules: PathRules{Windows: true}, wantName: "release", wantProblem: "not a valid name on Windows"},
+		{name: "windows path limit", worktree: long, rules: PathRules{MaxPath: 60}, wantName: strings.Repeat("a", 44) + "-", wantProblem: "over the 260 characters"},
+		{name: "windows directory too long", worktree: "feature", rules: PathRules{MaxPath: len(dir) + 5}, wantProblem: "enable core.longpaths"},
+		{name: "case collision", worktree: "feature", existing: []string{filepath.Join(dir, "Feature")}, rules: PathRules{CaseInsensitive: true}, wantName: "feature-2", wantProblem: "clashes with the worktree"},
+		{name: "case differs on a sensitive filesystem", worktree: "feature", existing: []string{filepath.Join(dir, "Feature")}, wantName: "feature"},
+		{name: "registered but missing", worktree: "feature", existing: []string{filepath.Join(dir, "feature"), filepath.Join(dir, "feature-2")}, wantName: "feature-3", wantProblem: "clashes"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fixed, problem := CheckWorktreePath(filepath.Join(dir, tt.worktree), tt.existing, tt.rules)
+			if tt.wantProblem == "" && problem != "" {
+				t.Fatalf("unexpected problem %q", problem)
+			}
+			if !strings.Contains(problem, tt.wantProblem) {
+				t.Fatalf("expected problem containing %q, got %q", tt.wantProblem, problem)
+			}
+			if tt.wantName == "" {
+				if fixed != "" {
+					t.Fatalf("expected no fix, got %q", fixed)
+				}
+				return
+			}
+			if filepath.Dir(fixed) != dir || !strings.HasPrefix(filepath.Base(fixed), tt.wantName) {
+				t.Fatalf("expected %s in %s, got %q", tt.wantName, dir, fixed)
+			}
+			if tt.rules.MaxNameBytes > 0 && len(filepath.Base(fixed)) > tt.rules.MaxNameBytes {
+				t.Fatalf("fixed name %q is still over the limit", filepath.Base(fixed))
+			}
+			if tt.rules.MaxPath > 0 && len(fixed) > tt.rules.MaxPath {
+				t.Fatalf("fixed path %q is still over the limit", fixed)
+			}
+		})
+	}
+}
+
+func TestShortenNameKeepsNamesApart(t *testing.T) {
+	a := shortenName(strings.Repeat("x", 40)+"-one", 20)
+	b := shortenName(strings.Repeat("x", 40)+"-two", 20)
+	if a == b || len(a) > 20 || len(b) > 20 {
+		t.Fatalf("expected distinct names within 20 bytes, got %q and %q", a, b)
+	}
+	if got := shortenName("ééééé", 8); len(got) > 8 || strings.ContainsRune(got, '�') {
+		t.Fatalf("expected a valid name within 8 bytes, got %q", got)
+	}
+}
@@ -0,0 +1,240 @@
+// Package workspace finds the sub-projects of a monorepo from its workspace
+// manifests: go.work, package.json workspaces and Cargo workspaces.
+package workspace
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Project kinds, named after the tool whose manifest declares them.
+const (
+	KindGo    = "go"
+	KindNPM   = "npm"
+	KindCargo = "cargo"
+)
+
+// Project is a sub-project declared by a workspace manifest.
+type Project struct {
+	// Dir is the slash-separated directory relative to the repository
+	// root, "." for the root itself.
+	Dir  string
+	Kind string
+}
+
+// Detect reads the workspace manifests at root and returns the projects
+// they declare, sorted by directory. A directory declared by several
+// manifests is listed once, under the first kind found.
+func Detect(root string) []Project {
+	seen := make(map[string]bool)
+	var projects []Project
+	add := func(kind string, dirs []string) {
+		for _, dir := range dirs {
+			if !seen[dir] {
+				seen[dir] = true
+				projects = append(projects, Project{Dir: dir, Kind: kind})
+			}
+		}
+	}
+	add(KindGo, goWorkDirs(root))
+	add(KindNPM, expandMembers(root, npmWorkspaces(root), "package.json"))
+	add(KindCargo, expandMembers(root, cargoMembers(root), "Cargo.toml"))
+	sort.Slice(projects, func(i, j int) bool { return projects[i].Dir < projects[j].Dir })
+	return projects
+}
+
+// Touched returns the projects owning files, which are slash-separated
+// paths relative to the repository root. Each file belongs to the project
+// with the deepest directory containing it; files outside every project
+// are ignored.
+func Touched(projects []Project, files []string) []Project {
+	hit := make(map[string]bool)
+	for _, file := range files {
+		best := -1
+		for i, p := range projects {
+			if !contains(p.Dir, file) {
+				continue
+			}
+			if best < 0 || len(p.Dir) > len(projects[best].Dir) {
+				best = i
+			}
+		}
+		if best >= 0 {
+			hit[projects[best].Dir] = true
+		}
+	}
+	var touched []Project
+	for _, p := range projects {
+		if hit[p.Dir] {
+			touched = append(touched, p)
+		}
+	}
+	return touched
+}
+
+func contains(dir, file string) bool {
+	return dir == "." || file == dir || strings.HasPrefix(file, dir+"/")
+}
+
+// cleanDir normalises a manifest path to a slash-separated directory
+// relative to the root, rejecting those outside it.
+func cleanDir(dir string) (string, bool) {
+	dir = path.Clean(strings.ReplaceAll(strings.TrimSpace(dir), `\`, "/"))
+	if dir == ".." || strings.HasPrefix(dir, "../") || path.IsAbs(dir) {
+		return "", false
+	}
+	return dir, true
+}
+
+// goWorkDirs returns the module directories of go.work's use directives.
+func goWorkDirs(root string) []string {
+	file, err := os.Open(filepath.Join(root, "go.work"))
+	if err != nil {
+		return nil
+	}
+	defer func() { _ = file.Close() }()
+
+	var dirs []string
+	inBlock := false
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "//")
+		line = strings.TrimSpace(line)
+		switch {
+		case inBlock && line == ")":
+			inBlock = false
+			continue
+		case inBlock:
+		case line == "use (" || line == "use(":
+			inBlock = true
+			continue
+		case strings.HasPrefix(line, "use "):
+			line = strings.TrimSpace(strings.TrimPrefix(line, "use "))
+		default:
+			continue
+		}
+		if line == "" {
+			continue
+		}
+		if dir, ok := cleanDir(strings.Trim(line, "\"`")); ok {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+// npmWorkspaces returns package.json's workspaces patterns, in either the
+// array or the {"packages": [...]} form.
+func npmWorkspaces(root string) []string {
+	data, err := os.ReadFile(filepath.Join(root, "package.json"))
+	if err != nil {
+		return nil
+	}
+	var manifest struct {
+		Workspaces json.RawMessage `json:"workspaces"`
+	}
+	if json.Unmarshal(data, &manifest) != nil || len(manifest.Workspaces) == 0 {
+		return nil
+	}
+	var patterns []string
+	if json.Unmarshal(manifest.Workspaces, &patterns) == nil {
+		return patterns
+	}
+	var nested struct {
+		Packages []string `json:"packages"`
+	}
+	if json.Unmarshal(manifest.Workspaces, &nested) == nil {
+		return nested.Packages
+	}
+	return nil
+}
+
+var (
+	tomlSection = regexp.MustCompile(`^\s*\[([^\]]+)\]\s*$`)
+	tomlString  = regexp.MustCompile(`"([^"]*)"|'([^']*)'`)
+)
+
+// cargoMembers returns the members array of Cargo.toml's [workspace]
+// table, which may span several lines.
+func cargoMembers(root string) []string {
+	data, err := os.ReadFile(filepath.Join(root, "Cargo.toml"))
+	if err != nil {
+		return nil
+	}
+	var members []string
+	inWorkspace, inMembers := false, false
+	for line := range strings.SplitSeq(string(data), "\n") {
+		line, _, _ = strings.Cut(line, "#")
+		if m := tomlSection.FindStringSubmatch(line); m != nil && !inMembers {
+			inWorkspace = strings.TrimSpace(m[1]) == "workspace"
+			continue
+		}
+		if !inWorkspace {
+			continue
+		}
+		if !inMembers {
+			key, value, ok := strings.Cut(line, "=")
+			if !ok || strings.TrimSpace(key) != "members" {
+				continue
+			}
+			line = value
+			inMembers = true
+		}
+		for _, m := range tomlString.FindAllStringSubmatch(line, -1) {
+			members = append(members, m[1]+m[2])
+		}
+		if strings.Contains(line, "]") {
+			inMembers = false
+		}
+	}
+	return members
+}
+
+// expandMembers resolves workspace patterns, which may hold globs, to the
+// directories under root that contain manifest. Patterns starting with "!"
+// exclude directories.
+func expandMembers(root string, patterns []string, manifest string) []string {
+	excluded := make(map[string]bool)
+	var dirs []string
+	for _, pattern := range patterns {
+		negate := strings.HasPrefix(pattern, "!")
+		pattern, ok := cleanDir(strings.TrimPrefix(pattern, "!"))
+		if !ok {
+			continue
+		}
+		// filepath.Glob has no "**"; treat it as a single level.
+		pattern = strings.ReplaceAll(pattern, "**", "*")
+		matches, err := filepath.Glob(filepath.Join(root, filepath.FromSlash(pattern)))
+		if err != nil {
+			continue
+		}
+		for _, match := range matches {
+			if _, err := os.Stat(filepath.Join(match, manifest)); err != nil {
+				continue
+			}
+			rel, err := filepath.Rel(root, match)
+			if err != nil {
+				continue
+			}
+			rel = filepath.ToSlash(rel)
+			if negate {
+				excluded[rel] = true
+			} else {
+				dirs = append(dirs, rel)
+			}
+		}
+	}
+	kept := dirs[:0]
+	for _, dir := range dirs {
+		if !excluded[dir] {
+			kept = append(kept, dir)
+		}
+	}
+	return kept
+}
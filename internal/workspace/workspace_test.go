@@ -0,0 +1,70 @@
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o750))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	}
+}
+
+func TestDetect(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"go.work":                 "go 1.25\n\nuse ./tools // helpers\nuse (\n\t.\n\t\"./services/api\"\n\t../outside\n)\n",
+		"package.json":            `{"name": "mono", "workspaces": {"packages": ["web/*", "!web/legacy"]}}`,
+		"web/app/package.json":    "{}",
+		"web/ui/package.json":     "{}",
+		"web/legacy/package.json": "{}",
+		"web/notes/README.md":     "not a package",
+		"Cargo.toml":              "[package]\nname = \"x\"\nmembers = [\"ignored\"]\n\n[workspace]\nresolver = \"2\"\nmembers = [\n  \"crates/*\", # all crates\n  'cli',\n]\nexclude = [\"crates/old\"]\n",
+		"crates/core/Cargo.toml":  "",
+		"cli/Cargo.toml":          "",
+	})
+
+	assert.Equal(t, []Project{
+		{Dir: ".", Kind: KindGo},
+		{Dir: "cli", Kind: KindCargo},
+		{Dir: "crates/core", Kind: KindCargo},
+		{Dir: "services/api", Kind: KindGo},
+		{Dir: "tools", Kind: KindGo},
+		{Dir: "web/app", Kind: KindNPM},
+		{Dir: "web/ui", Kind: KindNPM},
+	}, Detect(root))
+}
+
+func TestDetectArrayWorkspaces(t *testing.T) {
+	root := t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"package.json":            `{"workspaces": ["packages/*"]}`,
+		"packages/a/package.json": "{}",
+		"packages/b/package.json": "{}",
+		"packages/b/src/index.js": "",
+	})
+	assert.Equal(t, []Project{{Dir: "packages/a", Kind: KindNPM}, {Dir: "packages/b", Kind: KindNPM}}, Detect(root))
+	assert.Empty(t, Detect(t.TempDir()), "no manifests, no projects")
+}
+
+func TestTouched(t *testing.T) {
+	projects := []Project{
+		{Dir: ".", Kind: KindGo},
+		{Dir: "services/api", Kind: KindGo},
+		{Dir: "services/apigw", Kind: KindGo},
+		{Dir: "web", Kind: KindNPM},
+	}
+	assert.Equal(t, []Project{{Dir: "services/api", Kind: KindGo}}, Touched(projects, []string{"services/api/main.go"}))
+	assert.Equal(t, []Project{{Dir: ".", Kind: KindGo}, {Dir: "services/apigw", Kind: KindGo}},
+		Touched(projects, []string{"go.mod", "services/apigw/main.go", "services/apigw/go.sum"}))
+	assert.Empty(t, Touched(projects[1:], []string{"README.md"}), "files outside every project are ignored")
+	assert.Empty(t, Touched(projects, nil))
+}